@@ -2,18 +2,42 @@ package main
 
 import (
 	"context"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/zhs007/goutils"
+	"github.com/zhs007/slotsgamecore7/cheatpolicy"
 	"github.com/zhs007/slotsgamecore7/gamecollection"
+	"github.com/zhs007/slotsgamecore7/gateway"
+	"github.com/zhs007/slotsgamecore7/grpcutils"
 	"github.com/zhs007/slotsgamecore7/lowcode"
+	"github.com/zhs007/slotsgamecore7/metrics"
+	"github.com/zhs007/slotsgamecore7/roundstore"
+	"github.com/zhs007/slotsgamecore7/rtpmonitor"
 	sgc7ver "github.com/zhs007/slotsgamecore7/ver"
+	"github.com/zhs007/slotsgamecore7/wallet"
 )
 
 func main() {
 	goutils.InitLogger2("gamecollection", sgc7ver.Version,
 		"debug", true, "./logs")
 
-	serv, err := gamecollection.NewServ(":5000", sgc7ver.Version, false, lowcode.NewBasicRNG, lowcode.NewEmptyFeatureLevel)
+	// GRPCREFLECTION - 为 true 时注册 server reflection
+	// DRAINTIMEOUT - 退出时等进行中的请求的最长时间，比如 30s
+	opts := &grpcutils.ServOptions{
+		UseReflection: os.Getenv("GRPCREFLECTION") == "true",
+	}
+
+	drainTimeout, err := time.ParseDuration(os.Getenv("DRAINTIMEOUT"))
+	if err == nil {
+		opts.DrainTimeout = drainTimeout
+	}
+
+	serv, err := gamecollection.NewServWithOptions(":5000", sgc7ver.Version, opts, lowcode.NewBasicRNG, lowcode.NewEmptyFeatureLevel)
 	if err != nil {
 		goutils.Error("NewServ",
 			goutils.Err(err))
@@ -21,7 +45,149 @@ func main() {
 		return
 	}
 
-	lowcode.SetAllowForceOutcome(10000)
+	// ROUNDSTOREPATH - 保存没结束的局，为空时不保存
+	// WALLETFN - 本地开发用的钱包文件，为空时不扣钱，需要 ROUNDSTOREPATH
+	// WALLETBALANCE - 新玩家的初始余额
+	roundStorePath := os.Getenv("ROUNDSTOREPATH")
+	if roundStorePath != "" {
+		store, err := roundstore.NewFileRoundStore(roundStorePath)
+		if err != nil {
+			goutils.Error("NewFileRoundStore",
+				goutils.Err(err))
+
+			return
+		}
+
+		var w wallet.Wallet
+
+		walletfn := os.Getenv("WALLETFN")
+		if walletfn != "" {
+			balance, _ := strconv.ParseInt(os.Getenv("WALLETBALANCE"), 10, 64)
+
+			fw, err := wallet.NewFileWallet(walletfn, balance)
+			if err != nil {
+				goutils.Error("NewFileWallet",
+					goutils.Err(err))
+
+				return
+			}
+
+			w = fw
+		}
+
+		err = serv.SetRoundStore(store, w)
+		if err != nil {
+			goutils.Error("SetRoundStore",
+				goutils.Err(err))
+
+			return
+		}
+	}
+
+	// CHEATPOLICY - cheat 的配置文件，为空时不接受任何 cheat，生产环境不要配置
+	cheatPolicyFN := os.Getenv("CHEATPOLICY")
+	if cheatPolicyFN != "" {
+		policy, err := cheatpolicy.LoadPolicy(cheatPolicyFN)
+		if err != nil {
+			goutils.Error("LoadPolicy",
+				goutils.Err(err))
+
+			return
+		}
+
+		serv.SetCheatPolicy(policy)
+
+		if policy.IsAllowForceOutcomeEnabled() {
+			lowcode.SetAllowForceOutcome(10000)
+		}
+	}
+
+	// METRICSADDR - metrics 的地址，比如 127.0.0.1:9100，为空时不启动
+	// METRICSCURRENCIES - currency label 用的 currency，用逗号分隔，比如 EUR,USD，为空时用 metrics.DefaultCurrencies
+	metricsAddr := os.Getenv("METRICSADDR")
+	if metricsAddr != "" {
+		m := metrics.NewGameMetrics("gamecollection")
+
+		currencies := os.Getenv("METRICSCURRENCIES")
+		if currencies != "" {
+			m.SetCurrencies(strings.Split(currencies, ","))
+		}
+
+		serv.SetMetrics(m)
+
+		ms := metrics.NewServ(metricsAddr, m.Registry)
+		go func() {
+			err := ms.Start()
+			if err != nil {
+				goutils.Error("metrics.Serv.Start",
+					goutils.Err(err))
+			}
+		}()
+	}
+
+	// RTPMONITOR - RTP 监控的配置文件，为空时不监控
+	rtpMonitorFN := os.Getenv("RTPMONITOR")
+	if rtpMonitorFN != "" {
+		cfg, err := rtpmonitor.LoadConfig(rtpMonitorFN)
+		if err != nil {
+			goutils.Error("rtpmonitor.LoadConfig",
+				goutils.Err(err))
+
+			return
+		}
+
+		m, err := rtpmonitor.NewMonitor(cfg, &rtpmonitor.LogSink{})
+		if err != nil {
+			goutils.Error("rtpmonitor.NewMonitor",
+				goutils.Err(err))
+
+			return
+		}
+
+		serv.SetRTPMonitor(m)
+	}
+
+	// GATEWAYADDR - JSON/REST gateway 的地址，比如 127.0.0.1:8080，为空时不启动
+	var gs *gateway.Serv
+
+	gatewayAddr := os.Getenv("GATEWAYADDR")
+	if gatewayAddr != "" {
+		gs = gateway.NewServ(gatewayAddr, false)
+		gs.RegGameLogicCollection(gateway.GameLogicCollectionURL, serv)
+
+		go func() {
+			err := gs.Start()
+			if err != nil {
+				goutils.Error("gateway.Serv.Start",
+					goutils.Err(err))
+			}
+		}()
+	}
+
+	chanStopped := make(chan struct{})
+
+	go func() {
+		chanSignal := make(chan os.Signal, 1)
+		signal.Notify(chanSignal, syscall.SIGINT, syscall.SIGTERM)
+
+		<-chanSignal
+
+		if gs != nil {
+			gs.Stop()
+		}
+
+		serv.Stop()
+		close(chanStopped)
+	}()
+
+	err = serv.Start(context.Background())
+	if err != nil {
+		goutils.Error("Start",
+			goutils.Err(err))
+
+		return
+	}
 
-	serv.Start(context.Background())
+	// Start 在开始 drain 时就返回了，要等 Stop 结束
+	<-chanStopped
 }
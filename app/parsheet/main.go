@@ -0,0 +1,76 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+
+	"github.com/zhs007/goutils"
+	"github.com/zhs007/slotsgamecore7/lowcode"
+	sgc7ver "github.com/zhs007/slotsgamecore7/ver"
+)
+
+func main() {
+	goutils.InitLogger2("parsheet", sgc7ver.Version,
+		"info", true, "./logs")
+
+	strcore := os.Getenv("CORE")
+	if strcore == "" {
+		strcore = "8"
+	}
+
+	strspinnums := os.Getenv("SPINNUMS")
+	if strspinnums == "" {
+		strspinnums = "10000000"
+	}
+
+	strwincap := os.Getenv("WINCAP")
+	if strwincap == "" {
+		strwincap = "0"
+	}
+
+	gamecfg := os.Getenv("GAMECFG")
+	outputfn := os.Getenv("OUTPUT")
+	if outputfn == "" {
+		outputfn = "parsheet.xlsx"
+	}
+
+	icore, err := strconv.Atoi(strcore)
+	if err != nil {
+		goutils.Error("Getenv(CORE)",
+			goutils.Err(err))
+
+		return
+	}
+
+	ispinnums, err := strconv.ParseInt(strspinnums, 10, 64)
+	if err != nil {
+		goutils.Error("Getenv(SPINNUMS)",
+			goutils.Err(err))
+
+		return
+	}
+
+	wincap, err := strconv.ParseInt(strwincap, 10, 64)
+	if err != nil {
+		goutils.Error("Getenv(WINCAP)",
+			goutils.Err(err))
+
+		return
+	}
+
+	lowcode.SetReleaseMode()
+	lowcode.SetRTPMode()
+
+	err = lowcode.BuildParSheet(gamecfg, icore, ispinnums, outputfn, lowcode.NewBasicRNG, lowcode.NewEmptyFeatureLevel, wincap)
+	if err != nil {
+		goutils.Error("BuildParSheet",
+			slog.String("gamecfg", gamecfg),
+			goutils.Err(err))
+
+		return
+	}
+
+	goutils.Info("Done!",
+		slog.String("output", outputfn))
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/zhs007/goutils"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	"github.com/zhs007/slotsgamecore7/reelsconv"
+	sgc7ver "github.com/zhs007/slotsgamecore7/ver"
+)
+
+func loadPaytables(fn string) (*sgc7game.PayTables, error) {
+	if fn == "" {
+		return nil, nil
+	}
+
+	if strings.ToLower(filepath.Ext(fn)) == ".xlsx" {
+		return sgc7game.LoadPaytablesFromExcel(fn)
+	}
+
+	return sgc7game.LoadPayTables5JSON(fn)
+}
+
+func main() {
+	goutils.InitLogger2("reelsconv", sgc7ver.Version,
+		"info", true, "./logs")
+
+	inputfn := os.Getenv("INPUT")
+	outputfn := os.Getenv("OUTPUT")
+	paytablesfn := os.Getenv("PAYTABLES")
+
+	inputFormat, err := reelsconv.ParseFormat(os.Getenv("INPUTFORMAT"))
+	if err != nil {
+		goutils.Error("Getenv(INPUTFORMAT)",
+			goutils.Err(err))
+
+		return
+	}
+
+	outputFormat, err := reelsconv.ParseFormat(os.Getenv("OUTPUTFORMAT"))
+	if err != nil {
+		goutils.Error("Getenv(OUTPUTFORMAT)",
+			goutils.Err(err))
+
+		return
+	}
+
+	pt, err := loadPaytables(paytablesfn)
+	if err != nil {
+		goutils.Error("loadPaytables",
+			slog.String("paytables", paytablesfn),
+			goutils.Err(err))
+
+		return
+	}
+
+	rss, err := reelsconv.Convert(inputfn, inputFormat, outputfn, outputFormat, pt)
+	if err != nil {
+		goutils.Error("Convert",
+			slog.String("input", inputfn),
+			slog.String("output", outputfn),
+			goutils.Err(err))
+
+		return
+	}
+
+	goutils.Info("Done!",
+		slog.String("output", outputfn),
+		slog.Int("sets", len(rss.Sets)))
+}
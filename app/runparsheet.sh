@@ -0,0 +1 @@
+GAMECFG=../unittestdata/testgame.json OUTPUT=../output/parsheet.xlsx SPINNUMS=1000000 CORE=8 go run parsheet/*.go
\ No newline at end of file
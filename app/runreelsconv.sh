@@ -0,0 +1 @@
+INPUT=../unittestdata/reels.json INPUTFORMAT=reels5json OUTPUT=../output/reels.xml OUTPUTFORMAT=relaxxml PAYTABLES=../unittestdata/paytables.json go run reelsconv/*.go
\ No newline at end of file
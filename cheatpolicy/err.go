@@ -0,0 +1,14 @@
+package cheatpolicy
+
+import "errors"
+
+var (
+	// ErrCheatDisabled - the server does not accept cheats
+	ErrCheatDisabled = errors.New("cheat is disabled on this server")
+	// ErrGameNotAllowed - the game does not accept cheats
+	ErrGameNotAllowed = errors.New("cheat is not allowed for this game")
+	// ErrCallerNotAllowed - the caller is not in the allow-list
+	ErrCallerNotAllowed = errors.New("cheat is not allowed for this caller")
+	// ErrForceOutcomeNotAllowed - only the rng cheats are allowed
+	ErrForceOutcomeNotAllowed = errors.New("force outcome is not allowed")
+)
@@ -0,0 +1,169 @@
+package cheatpolicy
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"os"
+	"slices"
+
+	"github.com/valyala/fasthttp"
+	goutils "github.com/zhs007/goutils"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"gopkg.in/yaml.v2"
+)
+
+// AnyCaller - put it in AllowCallers to allow all callers
+const AnyCaller = "*"
+
+// CallerIDKey - the grpc metadata key / http header of caller id, only used when IsTrustCallerID is true
+const CallerIDKey = "x-caller-id"
+
+// Policy - cheat policy of a server
+//
+//	nil 或者零值都是不接受任何 cheat 的，生产环境不要配置它。
+//	caller 默认是对端的 IP，只有在前面有可信的网关会覆盖 x-caller-id 时，才能打开 IsTrustCallerID。
+type Policy struct {
+	IsEnabled           bool     `yaml:"isenabled" json:"isEnabled"`
+	IsAllowForceOutcome bool     `yaml:"isallowforceoutcome" json:"isAllowForceOutcome"` // 为 false 时只接受 rng 字符串
+	IsTrustCallerID     bool     `yaml:"istrustcallerid" json:"isTrustCallerID"`
+	GameCodes           []string `yaml:"gamecodes" json:"gameCodes"`       // 为空时是所有游戏
+	AllowCallers        []string `yaml:"allowcallers" json:"allowCallers"` // 为空时谁都不行，AnyCaller 是所有人
+}
+
+// IsAllowForceOutcomeEnabled - nil safe
+func (p *Policy) IsAllowForceOutcomeEnabled() bool {
+	return p != nil && p.IsEnabled && p.IsAllowForceOutcome
+}
+
+// Check - check the cheat, every accepted cheat is logged,
+//
+//	server is only for log, return nil if cheat is empty
+func (p *Policy) Check(server string, gameCode string, caller string, cheat string) error {
+	if cheat == "" {
+		return nil
+	}
+
+	err := p.check(gameCode, caller, cheat)
+	if err != nil {
+		goutils.Warn("cheatpolicy.Policy.Check:rejected",
+			slog.String("server", server),
+			slog.String("gameCode", gameCode),
+			slog.String("caller", caller),
+			slog.String("cheat", cheat),
+			goutils.Err(err))
+
+		return err
+	}
+
+	goutils.Info("cheatpolicy.Policy.Check:accepted",
+		slog.String("server", server),
+		slog.String("gameCode", gameCode),
+		slog.String("caller", caller),
+		slog.String("cheat", cheat))
+
+	return nil
+}
+
+func (p *Policy) check(gameCode string, caller string, cheat string) error {
+	if p == nil || !p.IsEnabled {
+		return ErrCheatDisabled
+	}
+
+	if len(p.GameCodes) > 0 && !slices.Contains(p.GameCodes, gameCode) {
+		return ErrGameNotAllowed
+	}
+
+	if caller == "" || !(slices.Contains(p.AllowCallers, AnyCaller) || slices.Contains(p.AllowCallers, caller)) {
+		return ErrCallerNotAllowed
+	}
+
+	if !p.IsAllowForceOutcome && !sgc7game.IsRngString(cheat) {
+		return ErrForceOutcomeNotAllowed
+	}
+
+	return nil
+}
+
+// CheckGRPC - check the cheat of a grpc request
+func (p *Policy) CheckGRPC(ctx context.Context, server string, gameCode string, cheat string) error {
+	if cheat == "" {
+		return nil
+	}
+
+	return p.Check(server, gameCode, p.GetGRPCCaller(ctx), cheat)
+}
+
+// CheckHTTP - check the cheat of a http request
+func (p *Policy) CheckHTTP(ctx *fasthttp.RequestCtx, server string, gameCode string, cheat string) error {
+	if cheat == "" {
+		return nil
+	}
+
+	return p.Check(server, gameCode, p.GetHTTPCaller(ctx), cheat)
+}
+
+// GetGRPCCaller - get the caller of a grpc request
+func (p *Policy) GetGRPCCaller(ctx context.Context) string {
+	if p != nil && p.IsTrustCallerID {
+		md, isok := metadata.FromIncomingContext(ctx)
+		if isok {
+			ids := md.Get(CallerIDKey)
+			if len(ids) > 0 && ids[0] != "" {
+				return ids[0]
+			}
+		}
+	}
+
+	pr, isok := peer.FromContext(ctx)
+	if !isok || pr.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(pr.Addr.String())
+	if err != nil {
+		return pr.Addr.String()
+	}
+
+	return host
+}
+
+// GetHTTPCaller - get the caller of a http request
+func (p *Policy) GetHTTPCaller(ctx *fasthttp.RequestCtx) string {
+	if p != nil && p.IsTrustCallerID {
+		id := string(ctx.Request.Header.Peek(CallerIDKey))
+		if id != "" {
+			return id
+		}
+	}
+
+	ip := ctx.RemoteIP()
+	if ip == nil || ip.IsUnspecified() {
+		return ""
+	}
+
+	return ip.String()
+}
+
+// IsCheatError - is it an error of this package
+func IsCheatError(err error) bool {
+	return err == ErrCheatDisabled || err == ErrGameNotAllowed || err == ErrCallerNotAllowed || err == ErrForceOutcomeNotAllowed
+}
+
+// LoadPolicy - load policy
+func LoadPolicy(fn string) (*Policy, error) {
+	data, err := os.ReadFile(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Policy{}
+	err = yaml.Unmarshal(data, p)
+	if err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
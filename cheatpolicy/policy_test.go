@@ -0,0 +1,100 @@
+package cheatpolicy
+
+import (
+	"context"
+	"net"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func Test_PolicyCheck(t *testing.T) {
+	var nilPolicy *Policy
+
+	assert.NoError(t, nilPolicy.Check("test", "game1", "127.0.0.1", ""))
+	assert.Equal(t, ErrCheatDisabled, nilPolicy.Check("test", "game1", "127.0.0.1", "1,2,3"))
+	assert.False(t, nilPolicy.IsAllowForceOutcomeEnabled())
+
+	p := &Policy{}
+	assert.Equal(t, ErrCheatDisabled, p.Check("test", "game1", "127.0.0.1", "1,2,3"))
+
+	p.IsEnabled = true
+	assert.Equal(t, ErrCallerNotAllowed, p.Check("test", "game1", "127.0.0.1", "1,2,3"))
+
+	p.AllowCallers = []string{"127.0.0.1"}
+	assert.NoError(t, p.Check("test", "game1", "127.0.0.1", "1,2,3"))
+	assert.Equal(t, ErrCallerNotAllowed, p.Check("test", "game1", "10.0.0.1", "1,2,3"))
+	assert.Equal(t, ErrCallerNotAllowed, p.Check("test", "game1", "", "1,2,3"))
+	assert.Equal(t, ErrForceOutcomeNotAllowed, p.Check("test", "game1", "127.0.0.1", "bg-wins>0"))
+
+	p.IsAllowForceOutcome = true
+	assert.NoError(t, p.Check("test", "game1", "127.0.0.1", "bg-wins>0"))
+	assert.True(t, p.IsAllowForceOutcomeEnabled())
+
+	p.GameCodes = []string{"game2"}
+	assert.Equal(t, ErrGameNotAllowed, p.Check("test", "game1", "127.0.0.1", "1,2,3"))
+	assert.NoError(t, p.Check("test", "game2", "127.0.0.1", "1,2,3"))
+
+	p.AllowCallers = []string{AnyCaller}
+	assert.NoError(t, p.Check("test", "game2", "10.0.0.1", "1,2,3"))
+
+	assert.True(t, IsCheatError(ErrGameNotAllowed))
+	assert.False(t, IsCheatError(nil))
+
+	t.Logf("Test_PolicyCheck OK")
+}
+
+func Test_PolicyCaller(t *testing.T) {
+	p := &Policy{
+		IsEnabled:    true,
+		AllowCallers: []string{"127.0.0.1", "qa"},
+	}
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 1234}})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(CallerIDKey, "qa2"))
+
+	// 默认不信任 x-caller-id
+	assert.Equal(t, "127.0.0.1", p.GetGRPCCaller(ctx))
+	assert.NoError(t, p.CheckGRPC(ctx, "test", "game1", "1,2,3"))
+
+	p.IsTrustCallerID = true
+	assert.Equal(t, "qa2", p.GetGRPCCaller(ctx))
+	assert.Equal(t, ErrCallerNotAllowed, p.CheckGRPC(ctx, "test", "game1", "1,2,3"))
+	assert.Equal(t, "", p.GetGRPCCaller(context.Background()))
+
+	httpctx := &fasthttp.RequestCtx{}
+	httpctx.Init(&fasthttp.Request{}, &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1234}, nil)
+	httpctx.Request.Header.Set(CallerIDKey, "qa")
+
+	assert.Equal(t, "qa", p.GetHTTPCaller(httpctx))
+	assert.NoError(t, p.CheckHTTP(httpctx, "test", "game1", "1,2,3"))
+
+	p.IsTrustCallerID = false
+	assert.Equal(t, "10.0.0.1", p.GetHTTPCaller(httpctx))
+	assert.Equal(t, ErrCallerNotAllowed, p.CheckHTTP(httpctx, "test", "game1", "1,2,3"))
+
+	t.Logf("Test_PolicyCaller OK")
+}
+
+func Test_LoadPolicy(t *testing.T) {
+	fn := path.Join(t.TempDir(), "cheatpolicy.yaml")
+	err := os.WriteFile(fn, []byte("isenabled: true\ngamecodes:\n  - game1\nallowcallers:\n  - 127.0.0.1\n"), 0644)
+	assert.NoError(t, err)
+
+	p, err := LoadPolicy(fn)
+	assert.NoError(t, err)
+	assert.True(t, p.IsEnabled)
+	assert.False(t, p.IsAllowForceOutcome)
+	assert.Equal(t, []string{"game1"}, p.GameCodes)
+	assert.Equal(t, []string{"127.0.0.1"}, p.AllowCallers)
+
+	_, err = LoadPolicy(path.Join(t.TempDir(), "nofile.yaml"))
+	assert.Error(t, err)
+
+	t.Logf("Test_LoadPolicy OK")
+}
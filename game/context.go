@@ -0,0 +1,50 @@
+package sgc7game
+
+import (
+	"context"
+
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+)
+
+// IGameWithContext - IGame with context, ctx is passed down to IPlugin.Random
+type IGameWithContext interface {
+	// PlayWithContext - play
+	PlayWithContext(ctx context.Context, plugin sgc7plugin.IPlugin, cmd string, param string, ps IPlayerState, stake *Stake, prs []*PlayResult, gameData any) (*PlayResult, error)
+}
+
+// IGameModWithContext - IGameMod with context, ctx is passed down to IPlugin.Random
+type IGameModWithContext interface {
+	// OnPlayWithContext - on play
+	OnPlayWithContext(ctx context.Context, game IGame, plugin sgc7plugin.IPlugin, cmd string, param string, ps IPlayerState, stake *Stake, prs []*PlayResult, gameData any) (*PlayResult, error)
+}
+
+// PlayWithContext - IGame.Play with context,
+//
+//	如果 game 没有实现 IGameWithContext，就用 Play，plugin 绑定 ctx，这样 Random 还是能拿到 ctx
+func PlayWithContext(ctx context.Context, game IGame, plugin sgc7plugin.IPlugin, cmd string, param string, ps IPlayerState, stake *Stake,
+	prs []*PlayResult, gameData any) (*PlayResult, error) {
+
+	err := ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	gwc, isok := game.(IGameWithContext)
+	if isok {
+		return gwc.PlayWithContext(ctx, plugin, cmd, param, ps, stake, prs, gameData)
+	}
+
+	return game.Play(sgc7plugin.WithContext(plugin, ctx), cmd, param, ps, stake, prs, gameData)
+}
+
+// OnPlayWithContext - IGameMod.OnPlay with context, like PlayWithContext
+func OnPlayWithContext(ctx context.Context, gameMod IGameMod, game IGame, plugin sgc7plugin.IPlugin, cmd string, param string, ps IPlayerState, stake *Stake,
+	prs []*PlayResult, gameData any) (*PlayResult, error) {
+
+	gmwc, isok := gameMod.(IGameModWithContext)
+	if isok {
+		return gmwc.OnPlayWithContext(ctx, game, plugin, cmd, param, ps, stake, prs, gameData)
+	}
+
+	return gameMod.OnPlay(game, sgc7plugin.WithContext(plugin, ctx), cmd, param, ps, stake, prs, gameData)
+}
@@ -0,0 +1,51 @@
+package sgc7game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+)
+
+type ctxKey struct{}
+
+// ctxGame - remember the context of the plugin
+type ctxGame struct {
+	*BasicGame
+	lastCtx context.Context
+}
+
+func (game *ctxGame) Play(plugin sgc7plugin.IPlugin, cmd string, param string, ps IPlayerState, stake *Stake, prs []*PlayResult, gameData any) (*PlayResult, error) {
+	game.lastCtx = sgc7plugin.GetContext(plugin)
+
+	return NewPlayResult("bg", 0, 0, "bg"), nil
+}
+
+func Test_PlayWithContext(t *testing.T) {
+	game := &ctxGame{
+		BasicGame: NewBasicGame(func() sgc7plugin.IPlugin {
+			return sgc7plugin.NewMockPlugin()
+		}),
+	}
+
+	plugin := game.NewPlugin()
+	ctx := context.WithValue(context.Background(), ctxKey{}, 1)
+
+	pr, err := PlayWithContext(ctx, game, plugin, "SPIN", "", nil, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, pr)
+	assert.Equal(t, ctx, game.lastCtx)
+
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	game.lastCtx = nil
+
+	pr, err = PlayWithContext(cctx, game, plugin, "SPIN", "", nil, nil, nil, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Nil(t, pr)
+	assert.Nil(t, game.lastCtx)
+
+	t.Logf("Test_PlayWithContext OK")
+}
@@ -1,8 +1,6 @@
 package sgc7game
 
 import (
-	"context"
-
 	goutils "github.com/zhs007/goutils"
 	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
 )
@@ -45,7 +43,7 @@ func (frr *FastReelsRandomSP) Random(plugin sgc7plugin.IPlugin) ([]int, error) {
 	arr := []int{}
 
 	for _, l := range frr.ArrIndex {
-		y, err := plugin.Random(context.Background(), len(l))
+		y, err := plugin.Random(sgc7plugin.GetContext(plugin), len(l))
 		if err != nil {
 			return nil, err
 		}
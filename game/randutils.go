@@ -1,15 +1,13 @@
 package sgc7game
 
 import (
-	"context"
-
 	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
 )
 
 // RandWithWeights - random with the weights
 func RandWithWeights(plugin sgc7plugin.IPlugin, max int, arr []int) (int, error) {
 	if len(arr) > 0 && max > 0 {
-		cr, err := plugin.Random(context.Background(), max)
+		cr, err := plugin.Random(sgc7plugin.GetContext(plugin), max)
 		if err != nil {
 			return -1, err
 		}
@@ -36,7 +34,7 @@ func RandList(plugin sgc7plugin.IPlugin, arr []int, num int) ([]int, error) {
 		narr := []int{}
 
 		for i := 0; i < num; i++ {
-			cr, err := plugin.Random(context.Background(), len(arr))
+			cr, err := plugin.Random(sgc7plugin.GetContext(plugin), len(arr))
 			if err != nil {
 				return nil, err
 			}
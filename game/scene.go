@@ -285,7 +285,7 @@ func (gs *GameScene) RandReelsWithReelData(reels *ReelsData, plugin sgc7plugin.I
 	}
 
 	for x, arr := range gs.Arr {
-		cn, err := plugin.Random(context.Background(), len(reels.Reels[x]))
+		cn, err := plugin.Random(sgc7plugin.GetContext(plugin), len(reels.Reels[x]))
 		if err != nil {
 			return err
 		}
@@ -314,7 +314,7 @@ func (gs *GameScene) RandReelsWithReelDataAndHeight(reels *ReelsData, height int
 	}
 
 	for x, arr := range gs.Arr {
-		cn, err := plugin.Random(context.Background(), len(reels.Reels[x]))
+		cn, err := plugin.Random(sgc7plugin.GetContext(plugin), len(reels.Reels[x]))
 		if err != nil {
 			return err
 		}
@@ -370,7 +370,7 @@ func (gs *GameScene) RandReelsWithReelDataMaskAndHeight(reels *ReelsData, height
 			continue
 		}
 
-		cn, err := plugin.Random(context.Background(), len(reels.Reels[x]))
+		cn, err := plugin.Random(sgc7plugin.GetContext(plugin), len(reels.Reels[x]))
 		if err != nil {
 			return err
 		}
@@ -415,7 +415,7 @@ func (gs *GameScene) RandMaskReelsWithReelData(reels *ReelsData, plugin sgc7plug
 
 	for x, arr := range gs.Arr {
 		if masks[x] != isReverse {
-			cn, err := plugin.Random(context.Background(), len(reels.Reels[x]))
+			cn, err := plugin.Random(sgc7plugin.GetContext(plugin), len(reels.Reels[x]))
 			if err != nil {
 				return err
 			}
@@ -445,7 +445,7 @@ func (gs *GameScene) RandExpandReelsWithReelData(reels *ReelsData, plugin sgc7pl
 	}
 
 	for x, arr := range gs.Arr {
-		cn, err := plugin.Random(context.Background(), len(reels.Reels[x]))
+		cn, err := plugin.Random(sgc7plugin.GetContext(plugin), len(reels.Reels[x]))
 		if err != nil {
 			return err
 		}
@@ -490,7 +490,7 @@ func (gs *GameScene) RandReelsEx(game IGame, plugin sgc7plugin.IPlugin, reelsNam
 	}
 
 	for i := 0; i < gs.Width-nums; i++ {
-		cr, err := plugin.Random(context.Background(), len(rarr))
+		cr, err := plugin.Random(sgc7plugin.GetContext(plugin), len(rarr))
 		if err != nil {
 			goutils.Error("GameScene.RandReelsEx:Random",
 				goutils.Err(err))
@@ -506,7 +506,7 @@ func (gs *GameScene) RandReelsEx(game IGame, plugin sgc7plugin.IPlugin, reelsNam
 		var err error
 
 		if goutils.IndexOfIntSlice(rarr, x, 0) < 0 {
-			cn, err = plugin.Random(context.Background(), len(reels.Reels[x]))
+			cn, err = plugin.Random(sgc7plugin.GetContext(plugin), len(reels.Reels[x]))
 		} else {
 			cn, err = rpd.RandReel(context.Background(), plugin, x)
 		}
@@ -562,7 +562,7 @@ func (gs *GameScene) RandReelsEx2(game IGame, plugin sgc7plugin.IPlugin, reelsNa
 	}
 
 	for i := 0; i < gs.Width-nums; i++ {
-		cr, err := plugin.Random(context.Background(), len(rarr))
+		cr, err := plugin.Random(sgc7plugin.GetContext(plugin), len(rarr))
 		if err != nil {
 			goutils.Error("GameScene.RandReelsEx:Random",
 				goutils.Err(err))
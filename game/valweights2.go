@@ -316,6 +316,33 @@ func NewValWeights2(vals []IVal, weights []int) (*ValWeights2, error) {
 	return vw, nil
 }
 
+// SaveExcel - save xlsx file, it can be loaded with LoadValWeights2FromExcel
+func (vw *ValWeights2) SaveExcel(fn string, headerVal string, headerWeight string) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheet := f.GetSheetName(0)
+
+	f.SetCellStr(sheet, goutils.Pos2Cell(0, 0), headerVal)
+	f.SetCellStr(sheet, goutils.Pos2Cell(1, 0), headerWeight)
+
+	for i, v := range vw.Vals {
+		f.SetCellStr(sheet, goutils.Pos2Cell(0, i+1), v.String())
+		f.SetCellValue(sheet, goutils.Pos2Cell(1, i+1), vw.Weights[i])
+	}
+
+	err := f.SaveAs(fn)
+	if err != nil {
+		goutils.Error("ValWeights2.SaveExcel:SaveAs",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return err
+	}
+
+	return nil
+}
+
 // LoadValWeights2FromExcel - load xlsx file
 func LoadValWeights2FromExcel(fn string, headerVal string, headerWeight string, funcNew FuncNewIVal) (*ValWeights2, error) {
 	f, err := excelize.OpenFile(fn)
@@ -32,6 +32,23 @@ func Test_LoadValWeights2FromExcel(t *testing.T) {
 	t.Logf("Test_LoadValWeights2FromExcel OK")
 }
 
+func Test_ValWeights2SaveExcel(t *testing.T) {
+	vw, err := LoadValWeights2FromExcel("../unittestdata/mysteryweights.xlsx", "val", "weight", NewIntVal[int])
+	assert.NoError(t, err)
+
+	err = vw.SaveExcel("../unittestdata/mysteryweights2.xlsx", "val", "weight")
+	assert.NoError(t, err)
+
+	vw1, err := LoadValWeights2FromExcel("../unittestdata/mysteryweights2.xlsx", "val", "weight", NewIntVal[int])
+	assert.NoError(t, err)
+
+	assert.Equal(t, vw.MaxWeight, vw1.MaxWeight)
+	assert.Equal(t, vw.Weights, vw1.Weights)
+	assert.Equal(t, vw.GetIntVals(), vw1.GetIntVals())
+
+	t.Logf("Test_ValWeights2SaveExcel OK")
+}
+
 // func Test_LoadValWeights2FromExcelWithSymbols(t *testing.T) {
 // 	pt, err := LoadPaytablesFromExcel("../data/game001/paytables.xlsx")
 // 	assert.NoError(t, err)
@@ -141,13 +141,20 @@ func (client *Client) InitializeGamePlayer(ctx context.Context, gameCode string)
 	return res, nil
 }
 
-// PlayGame - play game
+// PlayGame - play game, the unfinished round continues on the version in ps
 func (client *Client) PlayGame(ctx context.Context, gameCode string, ps *sgc7pb.PlayerState,
 	cheat string, stake *sgc7pb.Stake, clientParams string, cmd string) (*sgc7pb.ReplyPlayGame, error) {
 
+	return client.PlayGameWithVersion(ctx, gameCode, "", ps, cheat, stake, clientParams, cmd)
+}
+
+// PlayGameWithVersion - play game with a version of game config, empty version means the version in ps or the active version
+func (client *Client) PlayGameWithVersion(ctx context.Context, gameCode string, version string, ps *sgc7pb.PlayerState,
+	cheat string, stake *sgc7pb.Stake, clientParams string, cmd string) (*sgc7pb.ReplyPlayGame, error) {
+
 	err := client.onRequest(ctx)
 	if err != nil {
-		goutils.Error("Client.PlayGame:onRequest",
+		goutils.Error("Client.PlayGameWithVersion:onRequest",
 			goutils.Err(err))
 
 		return nil, err
@@ -155,6 +162,7 @@ func (client *Client) PlayGame(ctx context.Context, gameCode string, ps *sgc7pb.
 
 	stream, err := client.client.PlayGame(ctx, &sgc7pb.RequestPlayGame{
 		GameCode: gameCode,
+		Version:  version,
 		Play: &sgc7pb.RequestPlay{
 			PlayerState:  ps,
 			Cheat:        cheat,
@@ -182,7 +190,7 @@ func (client *Client) PlayGame(ctx context.Context, gameCode string, ps *sgc7pb.
 				return reply, nil
 			}
 
-			goutils.Error("Client.PlayGame:Recv",
+			goutils.Error("Client.PlayGameWithVersion:Recv",
 				slog.String("server address", client.servAddr),
 				goutils.Err(err))
 
@@ -200,3 +208,118 @@ func (client *Client) PlayGame(ctx context.Context, gameCode string, ps *sgc7pb.
 		// }
 	}
 }
+
+// ListGameVersions - list all versions of a game
+func (client *Client) ListGameVersions(ctx context.Context, gameCode string) (*sgc7pb.ReplyListGameVersions, error) {
+	err := client.onRequest(ctx)
+	if err != nil {
+		goutils.Error("Client.ListGameVersions:onRequest",
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	res, err := client.client.ListGameVersions(ctx, &sgc7pb.RequestListGameVersions{
+		GameCode: gameCode,
+	})
+	if err != nil {
+		goutils.Error("Client.ListGameVersions:ListGameVersions",
+			slog.String("server address", client.servAddr),
+			slog.String("gameCode", gameCode),
+			goutils.Err(err))
+
+		client.reset()
+
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ActivateGameVersion - activate a version of game
+func (client *Client) ActivateGameVersion(ctx context.Context, gameCode string, version string) (*sgc7pb.ReplyActivateGameVersion, error) {
+	err := client.onRequest(ctx)
+	if err != nil {
+		goutils.Error("Client.ActivateGameVersion:onRequest",
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	res, err := client.client.ActivateGameVersion(ctx, &sgc7pb.RequestActivateGameVersion{
+		GameCode: gameCode,
+		Version:  version,
+	})
+	if err != nil {
+		goutils.Error("Client.ActivateGameVersion:ActivateGameVersion",
+			slog.String("server address", client.servAddr),
+			slog.String("gameCode", gameCode),
+			slog.String("version", version),
+			goutils.Err(err))
+
+		client.reset()
+
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// RetireGameVersion - retire a version of game
+func (client *Client) RetireGameVersion(ctx context.Context, gameCode string, version string) (*sgc7pb.ReplyRetireGameVersion, error) {
+	err := client.onRequest(ctx)
+	if err != nil {
+		goutils.Error("Client.RetireGameVersion:onRequest",
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	res, err := client.client.RetireGameVersion(ctx, &sgc7pb.RequestRetireGameVersion{
+		GameCode: gameCode,
+		Version:  version,
+	})
+	if err != nil {
+		goutils.Error("Client.RetireGameVersion:RetireGameVersion",
+			slog.String("server address", client.servAddr),
+			slog.String("gameCode", gameCode),
+			slog.String("version", version),
+			goutils.Err(err))
+
+		client.reset()
+
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// ResumeGameRound - get all results so far of an unfinished round
+func (client *Client) ResumeGameRound(ctx context.Context, gameCode string, playerID string, roundID string) (*sgc7pb.ReplyPlayGame, error) {
+	err := client.onRequest(ctx)
+	if err != nil {
+		goutils.Error("Client.ResumeGameRound:onRequest",
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	res, err := client.client.ResumeGameRound(ctx, &sgc7pb.RequestResumeGameRound{
+		GameCode: gameCode,
+		RoundID:  roundID,
+		PlayerID: playerID,
+	})
+	if err != nil {
+		goutils.Error("Client.ResumeGameRound:ResumeGameRound",
+			slog.String("server address", client.servAddr),
+			slog.String("gameCode", gameCode),
+			slog.String("roundID", roundID),
+			goutils.Err(err))
+
+		client.reset()
+
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -7,4 +7,8 @@ var (
 	ErrInvalidGameCode = errors.New("invalid gameCode")
 	// ErrInvalidGameParams - invalid GameParams
 	ErrInvalidGameParams = errors.New("invalid GameParams")
+	// ErrInvalidGameVersion - invalid game version
+	ErrInvalidGameVersion = errors.New("invalid game version")
+	// ErrCannotRetireActiveVersion - cannot retire the active version
+	ErrCannotRetireActiveVersion = errors.New("cannot retire the active version")
 )
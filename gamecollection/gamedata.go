@@ -1,9 +1,12 @@
 package gamecollection
 
 import (
+	"context"
 	"log/slog"
+	"time"
 
 	"github.com/zhs007/goutils"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
 	"github.com/zhs007/slotsgamecore7/grpcserv"
 	"github.com/zhs007/slotsgamecore7/lowcode"
 	sgc7pbutils "github.com/zhs007/slotsgamecore7/pbutils"
@@ -12,15 +15,21 @@ import (
 )
 
 type GameData struct {
-	GameCode string
-	HashCode string
-	Data     []byte
-	Game     *lowcode.Game
-	Service  grpcserv.IService
+	GameCode   string
+	HashCode   string // HashCode 也是这个配置的 version
+	Data       []byte
+	Game       *lowcode.Game
+	Service    grpcserv.IService
+	CreateTime int64
 }
 
 // Play - play game
 func (gameD *GameData) Play(req *sgc7pb.RequestPlay) (*sgc7pb.ReplyPlay, error) {
+	return gameD.PlayWithContext(context.Background(), req)
+}
+
+// PlayWithContext - play game, ctx is passed down to the components and IPlugin.Random
+func (gameD *GameData) PlayWithContext(ctx context.Context, req *sgc7pb.RequestPlay) (*sgc7pb.ReplyPlay, error) {
 	ips := gameD.Game.NewPlayerState()
 	if req.PlayerState != nil {
 		err := gameD.Service.BuildPlayerStateFromPB(ips, req.PlayerState)
@@ -37,7 +46,18 @@ func (gameD *GameData) Play(req *sgc7pb.RequestPlay) (*sgc7pb.ReplyPlay, error)
 
 	stake := sgc7pbutils.BuildStake(req.Stake)
 
-	results, err := lowcode.Spin(gameD.Game, ips, plugin, stake, req.Command, req.ClientParams, req.Cheat, true)
+	// results 里的 scene 属于 gameData，要在 reply 构建完以后才能归还，否则并发时会被别的请求复用
+	gameData := gameD.Game.NewGameData(stake)
+	if gameData == nil {
+		goutils.Error("GameData.Play:NewGameData",
+			goutils.Err(sgc7game.ErrInvalidStake))
+
+		return nil, sgc7game.ErrInvalidStake
+	}
+
+	defer gameD.Game.DeleteGameData(gameData)
+
+	results, err := lowcode.SpinWithContext(ctx, gameD.Game, ips, plugin, stake, req.Command, req.ClientParams, req.Cheat, true, gameData)
 	if err != nil {
 		goutils.Error("GameData.Play:Spin",
 			goutils.Err(err))
@@ -69,6 +89,11 @@ func (gameD *GameData) Play(req *sgc7pb.RequestPlay) (*sgc7pb.ReplyPlay, error)
 		pr.NextCommandParams = lastr.NextCmdParams
 	}
 
+	// 没有结束的局要在这个版本上继续
+	if !pr.Finished && ps != nil {
+		ps.Version = gameD.HashCode
+	}
+
 	return pr, nil
 }
 
@@ -86,10 +111,11 @@ func NewGameData(gameCode string, data []byte, funcNewRNG lowcode.FuncNewRNG, fu
 	}
 
 	gameD := &GameData{
-		GameCode: gameCode,
-		Data:     data,
-		Game:     game,
-		Service:  NewService(),
+		GameCode:   gameCode,
+		Data:       data,
+		Game:       game,
+		Service:    NewService(),
+		CreateTime: time.Now().Unix(),
 	}
 
 	gameD.HashCode = Hash(data)
@@ -111,11 +137,12 @@ func NewGameDataWithHash(gameCode string, data []byte, hash string, funcNewRNG l
 	}
 
 	gameD := &GameData{
-		GameCode: gameCode,
-		Data:     data,
-		Game:     game,
-		HashCode: hash,
-		Service:  NewService(),
+		GameCode:   gameCode,
+		Data:       data,
+		Game:       game,
+		HashCode:   hash,
+		Service:    NewService(),
+		CreateTime: time.Now().Unix(),
 	}
 
 	return gameD, nil
@@ -1,85 +1,390 @@
 package gamecollection
 
 import (
+	"context"
 	"log/slog"
 	"sync"
 
 	"github.com/zhs007/goutils"
 	sgc7game "github.com/zhs007/slotsgamecore7/game"
 	"github.com/zhs007/slotsgamecore7/lowcode"
+	"github.com/zhs007/slotsgamecore7/metrics"
 	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
 )
 
+// DefaultMaxGameVersions - the default GameMgr.MaxGameVersions
+const DefaultMaxGameVersions = 8
+
+// gameVersions - all versions of a game
+//
+//	这个结构创建以后就不会再改，修改时会复制一份再整体替换，所以读的时候不需要锁
+type gameVersions struct {
+	active   *GameData
+	versions []*GameData // 按加入的顺序
+}
+
+// getVersion - get GameData with version, return nil if version is not found
+func (gvs *gameVersions) getVersion(version string) *GameData {
+	for _, gameD := range gvs.versions {
+		if gameD.HashCode == version {
+			return gameD
+		}
+	}
+
+	return nil
+}
+
+// clone - shallow copy, GameData is shared
+func (gvs *gameVersions) clone() *gameVersions {
+	return &gameVersions{
+		active:   gvs.active,
+		versions: append([]*GameData{}, gvs.versions...),
+	}
+}
+
+// evict - remove the oldest versions except the active one until there are maxNum versions, return the removed versions,
+//
+//	it is only used on a new gameVersions before it is stored
+func (gvs *gameVersions) evict(maxNum int) []*GameData {
+	if maxNum <= 0 || len(gvs.versions) <= maxNum {
+		return nil
+	}
+
+	var lst []*GameData
+
+	versions := make([]*GameData, 0, maxNum)
+	num := len(gvs.versions) - maxNum
+
+	for _, gameD := range gvs.versions {
+		if num > 0 && gameD != gvs.active {
+			lst = append(lst, gameD)
+			num--
+
+			continue
+		}
+
+		versions = append(versions, gameD)
+	}
+
+	gvs.versions = versions
+
+	return lst
+}
+
+// GameMgr - game manager
+//
+//	mapGames 是读多写少的，PlayGame 只在查找时读一次 *GameData，之后不持有任何锁，
+//	所以不同的 gameCode（以及同一个 gameCode）可以并行，同一个游戏的并发由 lowcode 里按 bet 的 sync.Pool 处理。
+//	InitGame 在锁外构建新的 GameData，然后整体替换，正在进行中的 PlayGame 会继续用旧版本完成。
+//	每个 gameCode 会保留多个版本（version 就是配置的 hash），没结束的局会继续用开始时的版本，直到这个版本被 retire。
+//	玩家的 PlayerState 不在这里，所以不知道旧版本还有没有人用，每个 gameCode 最多保留 MaxGameVersions 个版本，
+//	超过时淘汰最早加入的非 active 版本，和 retire 一样，这些版本上没结束的局不能再继续，MaxGameVersions <= 0 时不限制。
+//	原来导出的 MapGames 已经去掉了，用 GetGameData、RangeGames 和 GetGameNum 代替。
 type GameMgr struct {
-	sync.Mutex
-	MapGames        map[string]*GameData
+	MaxGameVersions int
+	mapGames        sync.Map // gameCode -> *gameVersions
+	mapInitLocks    sync.Map // gameCode -> *sync.Mutex，串行化同一个 gameCode 的所有修改
 	newRNG          lowcode.FuncNewRNG
 	newFeatureLevel lowcode.FuncNewFeatureLevel
 }
 
+// getInitLock - get the lock of gameCode
+func (mgr *GameMgr) getInitLock(gameCode string) *sync.Mutex {
+	lock, _ := mgr.mapInitLocks.LoadOrStore(gameCode, &sync.Mutex{})
+
+	return lock.(*sync.Mutex)
+}
+
+// getGameVersions - return nil if gameCode is not found
+func (mgr *GameMgr) getGameVersions(gameCode string) *gameVersions {
+	gvs, isok := mgr.mapGames.Load(gameCode)
+	if !isok {
+		return nil
+	}
+
+	return gvs.(*gameVersions)
+}
+
+// GetGameData - get the active GameData, return nil if gameCode is not found
+func (mgr *GameMgr) GetGameData(gameCode string) *GameData {
+	gvs := mgr.getGameVersions(gameCode)
+	if gvs == nil {
+		return nil
+	}
+
+	return gvs.active
+}
+
+// GetGameDataWithVersion - get GameData with version, return the active GameData if version is empty
+func (mgr *GameMgr) GetGameDataWithVersion(gameCode string, version string) (*GameData, error) {
+	gvs := mgr.getGameVersions(gameCode)
+	if gvs == nil {
+		goutils.Error("GameMgr.GetGameDataWithVersion",
+			slog.String("gameCode", gameCode),
+			goutils.Err(ErrInvalidGameCode))
+
+		return nil, ErrInvalidGameCode
+	}
+
+	if version == "" {
+		return gvs.active, nil
+	}
+
+	gameD := gvs.getVersion(version)
+	if gameD == nil {
+		goutils.Error("GameMgr.GetGameDataWithVersion",
+			slog.String("gameCode", gameCode),
+			slog.String("version", version),
+			goutils.Err(ErrInvalidGameVersion))
+
+		return nil, ErrInvalidGameVersion
+	}
+
+	return gameD, nil
+}
+
+// GetGameNum - get the number of games
+func (mgr *GameMgr) GetGameNum() int {
+	num := 0
+
+	mgr.mapGames.Range(func(key, value any) bool {
+		num++
+
+		return true
+	})
+
+	return num
+}
+
+// RangeGames - calls f for the active GameData of every game, stops if f returns false, replaces the old MapGames
+func (mgr *GameMgr) RangeGames(f func(gameCode string, gameD *GameData) bool) {
+	mgr.mapGames.Range(func(key, value any) bool {
+		gvs := value.(*gameVersions)
+		if gvs.active == nil {
+			return true
+		}
+
+		return f(key.(string), gvs.active)
+	})
+}
+
+// InitGame - add a version of game and activate it
 func (mgr *GameMgr) InitGame(gameCode string, data []byte) error {
-	mgr.Lock()
-	defer mgr.Unlock()
+	_, err := mgr.AddGameVersion(gameCode, data, true)
 
-	gameD, isok := mgr.MapGames[gameCode]
-	if isok {
-		hash := Hash(data)
+	return err
+}
 
-		if hash == gameD.HashCode {
-			goutils.Info("GameMgr.InitGame:same hash",
+// AddGameVersion - add a version of game, return the version,
+//
+//	if the version already exists, it is only activated (if isActivate is true), so it can be used to roll back
+func (mgr *GameMgr) AddGameVersion(gameCode string, data []byte, isActivate bool) (string, error) {
+	lock := mgr.getInitLock(gameCode)
+	lock.Lock()
+	defer lock.Unlock()
+
+	hash := Hash(data)
+
+	gvs := mgr.getGameVersions(gameCode)
+	if gvs != nil {
+		gameD := gvs.getVersion(hash)
+		if gameD != nil {
+			goutils.Info("GameMgr.AddGameVersion:same hash",
 				slog.String("gameCode", gameCode),
 				slog.String("hash", hash))
 
-			return nil
+			if isActivate && gvs.active != gameD {
+				ngvs := gvs.clone()
+				ngvs.active = gameD
+
+				mgr.mapGames.Store(gameCode, ngvs)
+			}
+
+			return hash, nil
 		}
+	}
 
-		// goutils.Info("GameMgr.InitGame",
-		// 	slog.String("data", string(data)))
+	// 这里不持有 mapGames 的任何锁，编译大配置时不会阻塞其它请求
+	gameD, err := NewGameDataWithHash(gameCode, data, hash, mgr.newRNG, mgr.newFeatureLevel)
+	if err != nil {
+		goutils.Error("GameMgr.AddGameVersion:NewGameDataWithHash",
+			goutils.Err(err))
 
-		gameD1, err := NewGameDataWithHash(gameCode, data, hash, mgr.newRNG, mgr.newFeatureLevel)
-		if err != nil {
-			goutils.Error("GameMgr.InitGame:NewGameDataWithHash",
-				goutils.Err(err))
+		return "", err
+	}
 
-			return err
-		}
+	var ngvs *gameVersions
+	if gvs != nil {
+		ngvs = gvs.clone()
+	} else {
+		// 第一个版本总是 active 的
+		ngvs = &gameVersions{active: gameD}
+	}
+
+	ngvs.versions = append(ngvs.versions, gameD)
+	if isActivate {
+		ngvs.active = gameD
+	}
+
+	evicted := ngvs.evict(mgr.MaxGameVersions)
+
+	mgr.mapGames.Store(gameCode, ngvs)
+
+	for _, v := range evicted {
+		goutils.Info("GameMgr.AddGameVersion:evict",
+			slog.String("gameCode", gameCode),
+			slog.String("version", v.HashCode))
+	}
+
+	goutils.Info("GameMgr.AddGameVersion:OK!",
+		slog.String("gameCode", gameCode),
+		slog.String("version", hash),
+		slog.Bool("isActive", ngvs.active == gameD))
 
-		mgr.MapGames[gameCode] = gameD1
+	return hash, nil
+}
 
-		goutils.Info("GameMgr.InitGame:OK!",
-			slog.String("gameCode", gameCode))
+// ListGameVersions - list all versions of a game
+func (mgr *GameMgr) ListGameVersions(gameCode string) ([]*sgc7pb.GameVersion, error) {
+	gvs := mgr.getGameVersions(gameCode)
+	if gvs == nil {
+		goutils.Error("GameMgr.ListGameVersions",
+			slog.String("gameCode", gameCode),
+			goutils.Err(ErrInvalidGameCode))
 
+		return nil, ErrInvalidGameCode
+	}
+
+	lst := make([]*sgc7pb.GameVersion, 0, len(gvs.versions))
+	for _, gameD := range gvs.versions {
+		lst = append(lst, &sgc7pb.GameVersion{
+			Version:    gameD.HashCode,
+			IsActive:   gameD == gvs.active,
+			CreateTime: gameD.CreateTime,
+		})
+	}
+
+	return lst, nil
+}
+
+// ActivateGameVersion - activate a version of game, the new rounds will use this version
+func (mgr *GameMgr) ActivateGameVersion(gameCode string, version string) error {
+	lock := mgr.getInitLock(gameCode)
+	lock.Lock()
+	defer lock.Unlock()
+
+	gvs := mgr.getGameVersions(gameCode)
+	if gvs == nil {
+		goutils.Error("GameMgr.ActivateGameVersion",
+			slog.String("gameCode", gameCode),
+			goutils.Err(ErrInvalidGameCode))
+
+		return ErrInvalidGameCode
+	}
+
+	gameD := gvs.getVersion(version)
+	if gameD == nil {
+		goutils.Error("GameMgr.ActivateGameVersion",
+			slog.String("gameCode", gameCode),
+			slog.String("version", version),
+			goutils.Err(ErrInvalidGameVersion))
+
+		return ErrInvalidGameVersion
+	}
+
+	if gvs.active == gameD {
 		return nil
 	}
 
-	// goutils.Info("GameMgr.InitGame",
-	// 	slog.String("data", string(data)))
+	ngvs := gvs.clone()
+	ngvs.active = gameD
 
-	gameD1, err := NewGameData(gameCode, data, mgr.newRNG, mgr.newFeatureLevel)
-	if err != nil {
-		goutils.Error("GameMgr.InitGame:NewGameData",
-			goutils.Err(err))
+	mgr.mapGames.Store(gameCode, ngvs)
+
+	goutils.Info("GameMgr.ActivateGameVersion:OK!",
+		slog.String("gameCode", gameCode),
+		slog.String("version", version))
+
+	return nil
+}
 
-		return err
+// RetireGameVersion - retire a version of game, the active version can not be retired,
+//
+//	the unfinished rounds on this version can not continue
+func (mgr *GameMgr) RetireGameVersion(gameCode string, version string) error {
+	lock := mgr.getInitLock(gameCode)
+	lock.Lock()
+	defer lock.Unlock()
+
+	gvs := mgr.getGameVersions(gameCode)
+	if gvs == nil {
+		goutils.Error("GameMgr.RetireGameVersion",
+			slog.String("gameCode", gameCode),
+			goutils.Err(ErrInvalidGameCode))
+
+		return ErrInvalidGameCode
+	}
+
+	gameD := gvs.getVersion(version)
+	if gameD == nil {
+		goutils.Error("GameMgr.RetireGameVersion",
+			slog.String("gameCode", gameCode),
+			slog.String("version", version),
+			goutils.Err(ErrInvalidGameVersion))
+
+		return ErrInvalidGameVersion
+	}
+
+	if gvs.active == gameD {
+		goutils.Error("GameMgr.RetireGameVersion",
+			slog.String("gameCode", gameCode),
+			slog.String("version", version),
+			goutils.Err(ErrCannotRetireActiveVersion))
+
+		return ErrCannotRetireActiveVersion
 	}
 
-	mgr.MapGames[gameCode] = gameD1
+	ngvs := &gameVersions{
+		active: gvs.active,
+	}
 
-	goutils.Info("GameMgr.InitGame:OK!",
-		slog.String("gameCode", gameCode))
+	for _, cur := range gvs.versions {
+		if cur != gameD {
+			ngvs.versions = append(ngvs.versions, cur)
+		}
+	}
+
+	mgr.mapGames.Store(gameCode, ngvs)
+
+	goutils.Info("GameMgr.RetireGameVersion:OK!",
+		slog.String("gameCode", gameCode),
+		slog.String("version", version))
 
 	return nil
 }
 
-func (mgr *GameMgr) GetGameConfig(gameCode string) (*sgc7game.Config, error) {
-	mgr.Lock()
-	defer mgr.Unlock()
+// collectMetrics - set the versions and the pool stats of all games in m
+func (mgr *GameMgr) collectMetrics(m *metrics.GameMetrics) {
+	mgr.mapGames.Range(func(key, value any) bool {
+		gameCode := key.(string)
+		gvs := value.(*gameVersions)
 
-	gameD, isok := mgr.MapGames[gameCode]
-	if !isok {
+		m.SetGameVersions(gameCode, len(gvs.versions), gvs.active.HashCode)
+
+		for _, gameD := range gvs.versions {
+			m.SetGamePool(gameCode, gameD.HashCode, gameD.Game)
+		}
+
+		return true
+	})
+}
+
+func (mgr *GameMgr) GetGameConfig(gameCode string) (*sgc7game.Config, error) {
+	gameD := mgr.GetGameData(gameCode)
+	if gameD == nil {
 		goutils.Error("GameMgr.GetGameConfig",
 			slog.String("gameCode", gameCode),
-			slog.Int("game number", len(mgr.MapGames)),
+			slog.Int("game number", mgr.GetGameNum()),
 			goutils.Err(ErrInvalidGameCode))
 
 		return nil, ErrInvalidGameCode
@@ -89,14 +394,11 @@ func (mgr *GameMgr) GetGameConfig(gameCode string) (*sgc7game.Config, error) {
 }
 
 func (mgr *GameMgr) InitializeGamePlayer(gameCode string) (*sgc7pb.PlayerState, error) {
-	mgr.Lock()
-	defer mgr.Unlock()
-
-	gameD, isok := mgr.MapGames[gameCode]
-	if !isok || gameD == nil || gameD.Game == nil || gameD.Service == nil {
+	gameD := mgr.GetGameData(gameCode)
+	if gameD == nil || gameD.Game == nil || gameD.Service == nil {
 		goutils.Error("GameMgr.InitializeGamePlayer",
 			slog.String("gameCode", gameCode),
-			slog.Int("game number", len(mgr.MapGames)),
+			slog.Int("game number", mgr.GetGameNum()),
 			goutils.Err(ErrInvalidGameCode))
 
 		return nil, ErrInvalidGameCode
@@ -107,24 +409,34 @@ func (mgr *GameMgr) InitializeGamePlayer(gameCode string) (*sgc7pb.PlayerState,
 	return gameD.Service.BuildPBPlayerState(ps)
 }
 
-// PlayGame - play game
-func (mgr *GameMgr) PlayGame(gameCode string, req *sgc7pb.RequestPlay) (*sgc7pb.ReplyPlay, error) {
-	mgr.Lock()
-	defer mgr.Unlock()
+// PlayGame - play game,
+//
+//	version 为空时，如果 playerState 里有 version（没结束的局），就用它，否则用 active 的版本
+func (mgr *GameMgr) PlayGame(gameCode string, version string, req *sgc7pb.RequestPlay) (*sgc7pb.ReplyPlay, error) {
+	return mgr.PlayGameWithContext(context.Background(), gameCode, version, req)
+}
 
-	gameD, isok := mgr.MapGames[gameCode]
-	if !isok {
-		goutils.Error("GameMgr.PlayGame",
+// PlayGameWithContext - like PlayGame, ctx is passed down to the components and IPlugin.Random
+func (mgr *GameMgr) PlayGameWithContext(ctx context.Context, gameCode string, version string, req *sgc7pb.RequestPlay) (*sgc7pb.ReplyPlay, error) {
+	if version == "" && req.PlayerState != nil {
+		version = req.PlayerState.Version
+	}
+
+	gameD, err := mgr.GetGameDataWithVersion(gameCode, version)
+	if err != nil {
+		goutils.Error("GameMgr.PlayGame:GetGameDataWithVersion",
 			slog.String("gameCode", gameCode),
-			goutils.Err(ErrInvalidGameCode))
+			slog.String("version", version),
+			goutils.Err(err))
 
-		return nil, ErrInvalidGameCode
+		return nil, err
 	}
 
-	reply, err := gameD.Play(req)
+	reply, err := gameD.PlayWithContext(ctx, req)
 	if err != nil {
 		goutils.Error("GameMgr.PlayGame",
 			slog.String("gameCode", gameCode),
+			slog.String("version", gameD.HashCode),
 			goutils.Err(err))
 
 		return nil, err
@@ -135,7 +447,7 @@ func (mgr *GameMgr) PlayGame(gameCode string, req *sgc7pb.RequestPlay) (*sgc7pb.
 
 func NewGameMgr(funcNewRNG lowcode.FuncNewRNG, funcNewFeatureLevel lowcode.FuncNewFeatureLevel) *GameMgr {
 	return &GameMgr{
-		MapGames:        make(map[string]*GameData),
+		MaxGameVersions: DefaultMaxGameVersions,
 		newRNG:          funcNewRNG,
 		newFeatureLevel: funcNewFeatureLevel,
 	}
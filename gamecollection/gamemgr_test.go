@@ -0,0 +1,433 @@
+package gamecollection
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zhs007/slotsgamecore7/lowcode"
+	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
+)
+
+func newTestRequestPlay() *sgc7pb.RequestPlay {
+	return &sgc7pb.RequestPlay{
+		Stake: &sgc7pb.Stake{
+			CoinBet:  1,
+			CashBet:  20,
+			Currency: "EUR",
+		},
+		Command: "SPIN",
+	}
+}
+
+// fillRespinNum - add the missing scatter numbers to respinNumWithScatterNum, up to maxNum, with the last respin number
+func fillRespinNum(obj any, maxNum int) {
+	switch v := obj.(type) {
+	case map[string]any:
+		for k, cv := range v {
+			lst, isok := cv.([]any)
+			if k == "respinNumWithScatterNum" && isok && len(lst) > 0 {
+				last, isok1 := lst[len(lst)-1].([]any)
+				if isok1 && len(last) == 2 {
+					for n := int(last[0].(float64)) + 1; n <= maxNum; n++ {
+						lst = append(lst, []any{float64(n), last[1]})
+					}
+
+					v[k] = lst
+				}
+
+				continue
+			}
+
+			fillRespinNum(cv, maxNum)
+		}
+	case []any:
+		for _, cv := range v {
+			fillRespinNum(cv, maxNum)
+		}
+	}
+}
+
+// loadTestGameData - testgame.json 的 bg-scatter 没有配置 6 个以上 scatter 的 respin 次数，偶尔会返回 ErrInvalidSymbolNum，
+//
+//	这里补上，并发测试和 benchmark 都用它，所以不用忽略任何错误
+func loadTestGameData(tb testing.TB) []byte {
+	data, err := os.ReadFile("../unittestdata/testgame.json")
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	var obj any
+	err = json.Unmarshal(data, &obj)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	// 5x3
+	fillRespinNum(obj, 15)
+
+	data, err = json.Marshal(obj)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return data
+}
+
+func Test_GameMgrConcurrentPlay(t *testing.T) {
+	data := loadTestGameData(t)
+
+	mgr := NewGameMgr(lowcode.NewBasicRNG, lowcode.NewEmptyFeatureLevel)
+
+	err := mgr.InitGame("game1", data)
+	assert.NoError(t, err)
+
+	err = mgr.InitGame("game2", data)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, mgr.GetGameNum())
+
+	gameD1 := mgr.GetGameData("game1")
+	assert.NotNil(t, gameD1)
+
+	mapGames := make(map[string]*GameData)
+	mgr.RangeGames(func(gameCode string, gameD *GameData) bool {
+		mapGames[gameCode] = gameD
+
+		return true
+	})
+	assert.Equal(t, 2, len(mapGames))
+	assert.Equal(t, gameD1, mapGames["game1"])
+	assert.Equal(t, mgr.GetGameData("game2"), mapGames["game2"])
+
+	num := 0
+	mgr.RangeGames(func(gameCode string, gameD *GameData) bool {
+		num++
+
+		return false
+	})
+	assert.Equal(t, 1, num)
+
+	// same hash, nothing changed
+	err = mgr.InitGame("game1", data)
+	assert.NoError(t, err)
+	assert.Equal(t, gameD1, mgr.GetGameData("game1"))
+
+	_, err = mgr.PlayGame("game3", "", newTestRequestPlay())
+	assert.Equal(t, ErrInvalidGameCode, err)
+
+	wg := sync.WaitGroup{}
+	errs := make(chan error, 100)
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func(gameCode string) {
+			defer wg.Done()
+
+			for j := 0; j < 50; j++ {
+				_, err := mgr.PlayGame(gameCode, "", newTestRequestPlay())
+				if err != nil {
+					errs <- err
+
+					return
+				}
+			}
+		}([]string{"game1", "game2"}[i%2])
+	}
+
+	// InitGame 在游戏进行中替换 game1，不影响正在进行的请求
+	data1 := append([]byte{}, data...)
+	data1 = append(data1, '\n')
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		err := mgr.InitGame("game1", data1)
+		if err != nil {
+			errs <- err
+		}
+	}()
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	gameD2 := mgr.GetGameData("game1")
+	assert.NotNil(t, gameD2)
+	assert.NotEqual(t, gameD1, gameD2)
+	assert.Equal(t, Hash(data1), gameD2.HashCode)
+
+	// the old version is still usable
+	_, err = gameD1.Play(newTestRequestPlay())
+	assert.NoError(t, err)
+
+	t.Logf("Test_GameMgrConcurrentPlay OK")
+}
+
+// Benchmark_GameMgrPlayGame - play a game in parallel, run it with different -cpu to compare the throughput
+//
+//	go test -run none -bench GameMgrPlayGame -cpu 1,2,4,8 ./gamecollection
+func Benchmark_GameMgrPlayGame(b *testing.B) {
+	data := loadTestGameData(b)
+
+	mgr := NewGameMgr(lowcode.NewBasicRNG, lowcode.NewEmptyFeatureLevel)
+
+	err := mgr.InitGame("game1", data)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		req := newTestRequestPlay()
+
+		for pb.Next() {
+			_, err := mgr.PlayGame("game1", "", req)
+			if err != nil {
+				b.Error(err)
+
+				return
+			}
+		}
+	})
+}
+
+func Test_GameMgrVersions(t *testing.T) {
+	data, err := os.ReadFile("../unittestdata/testgame.json")
+	assert.NoError(t, err)
+
+	data1 := append([]byte{}, data...)
+	data1 = append(data1, '\n')
+
+	mgr := NewGameMgr(lowcode.NewBasicRNG, lowcode.NewEmptyFeatureLevel)
+
+	_, err = mgr.ListGameVersions("game1")
+	assert.Equal(t, ErrInvalidGameCode, err)
+
+	// the first version is always active
+	v0, err := mgr.AddGameVersion("game1", data, false)
+	assert.NoError(t, err)
+	assert.Equal(t, Hash(data), v0)
+
+	v1, err := mgr.AddGameVersion("game1", data1, false)
+	assert.NoError(t, err)
+	assert.Equal(t, Hash(data1), v1)
+
+	lst, err := mgr.ListGameVersions("game1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(lst))
+	assert.Equal(t, v0, lst[0].Version)
+	assert.True(t, lst[0].IsActive)
+	assert.Equal(t, v1, lst[1].Version)
+	assert.False(t, lst[1].IsActive)
+
+	assert.Equal(t, v0, mgr.GetGameData("game1").HashCode)
+
+	// the unfinished round is pinned to the version in playerState
+	reply, err := mgr.PlayGame("game1", "", newTestRequestPlay())
+	assert.NoError(t, err)
+	if reply.Finished {
+		assert.Equal(t, "", reply.PlayerState.Version)
+	} else {
+		assert.Equal(t, v0, reply.PlayerState.Version)
+	}
+
+	req := newTestRequestPlay()
+	req.PlayerState = reply.PlayerState
+	req.PlayerState.Version = v1
+
+	reply, err = mgr.PlayGame("game1", "", req)
+	assert.NoError(t, err)
+	if !reply.Finished {
+		assert.Equal(t, v1, reply.PlayerState.Version)
+	}
+
+	_, err = mgr.PlayGame("game1", "abc", newTestRequestPlay())
+	assert.Equal(t, ErrInvalidGameVersion, err)
+
+	// activate & retire
+	err = mgr.ActivateGameVersion("game1", "abc")
+	assert.Equal(t, ErrInvalidGameVersion, err)
+
+	err = mgr.ActivateGameVersion("game1", v1)
+	assert.NoError(t, err)
+	assert.Equal(t, v1, mgr.GetGameData("game1").HashCode)
+
+	err = mgr.RetireGameVersion("game1", v1)
+	assert.Equal(t, ErrCannotRetireActiveVersion, err)
+
+	err = mgr.RetireGameVersion("game1", v0)
+	assert.NoError(t, err)
+
+	lst, err = mgr.ListGameVersions("game1")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(lst))
+	assert.Equal(t, v1, lst[0].Version)
+
+	// the rounds on the retired version can not continue
+	req = newTestRequestPlay()
+	req.PlayerState = reply.PlayerState
+	req.PlayerState.Version = v0
+
+	_, err = mgr.PlayGame("game1", "", req)
+	assert.Equal(t, ErrInvalidGameVersion, err)
+
+	_, err = mgr.PlayGame("game1", v1, req)
+	assert.NoError(t, err)
+
+	// roll back
+	err = mgr.InitGame("game1", data)
+	assert.NoError(t, err)
+	assert.Equal(t, v0, mgr.GetGameData("game1").HashCode)
+
+	err = mgr.ActivateGameVersion("game1", v1)
+	assert.NoError(t, err)
+
+	err = mgr.InitGame("game1", data)
+	assert.NoError(t, err)
+	assert.Equal(t, v0, mgr.GetGameData("game1").HashCode)
+
+	t.Logf("Test_GameMgrVersions OK")
+}
+
+func Test_GameMgrMaxVersions(t *testing.T) {
+	data, err := os.ReadFile("../unittestdata/playerpickgame.json")
+	assert.NoError(t, err)
+
+	mgr := NewGameMgr(lowcode.NewBasicRNG, lowcode.NewEmptyFeatureLevel)
+	assert.Equal(t, DefaultMaxGameVersions, mgr.MaxGameVersions)
+
+	mgr.MaxGameVersions = 2
+
+	versions := make([]string, 4)
+	for i := range versions {
+		cur := append([]byte{}, data...)
+		for j := 0; j < i; j++ {
+			cur = append(cur, '\n')
+		}
+
+		// versions[0] is the first version, it is always active
+		versions[i], err = mgr.AddGameVersion("game1", cur, false)
+		assert.NoError(t, err)
+	}
+
+	// the active version is kept, the oldest others are evicted
+	lst, err := mgr.ListGameVersions("game1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(lst))
+	assert.Equal(t, versions[0], lst[0].Version)
+	assert.True(t, lst[0].IsActive)
+	assert.Equal(t, versions[3], lst[1].Version)
+
+	_, err = mgr.GetGameDataWithVersion("game1", versions[1])
+	assert.Equal(t, ErrInvalidGameVersion, err)
+
+	_, err = mgr.GetGameDataWithVersion("game1", versions[2])
+	assert.Equal(t, ErrInvalidGameVersion, err)
+
+	// the evicted version can be added again
+	_, err = mgr.AddGameVersion("game1", append(append([]byte{}, data...), '\n'), true)
+	assert.NoError(t, err)
+
+	lst, err = mgr.ListGameVersions("game1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(lst))
+	assert.Equal(t, versions[3], lst[0].Version)
+	assert.Equal(t, versions[1], lst[1].Version)
+	assert.True(t, lst[1].IsActive)
+
+	// no limit
+	mgr.MaxGameVersions = 0
+
+	_, err = mgr.AddGameVersion("game1", data, false)
+	assert.NoError(t, err)
+
+	lst, err = mgr.ListGameVersions("game1")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(lst))
+
+	t.Logf("Test_GameMgrMaxVersions OK")
+}
+
+func Test_GameMgrPlayerPick(t *testing.T) {
+	data, err := os.ReadFile("../unittestdata/playerpickgame.json")
+	assert.NoError(t, err)
+
+	mgr := NewGameMgr(lowcode.NewBasicRNG, lowcode.NewEmptyFeatureLevel)
+
+	err = mgr.InitGame("playerpick", data)
+	assert.NoError(t, err)
+
+	stake := &sgc7pb.Stake{
+		CoinBet:  1,
+		CashBet:  1,
+		Currency: "EUR",
+	}
+
+	// 开始和选择是两个独立请求,棋盘只能从上一次返回的 PlayerState 里恢复
+	reply, err := mgr.PlayGame("playerpick", "", &sgc7pb.RequestPlay{
+		Stake:   stake,
+		Command: "SPIN",
+	})
+	assert.NoError(t, err)
+	assert.False(t, reply.Finished)
+	assert.Equal(t, []string{"bg-pick", "bg-pick", "bg-pick", "bg-pick"}, reply.NextCommands)
+	assert.Equal(t, []string{"0", "1", "2", "3"}, reply.NextCommandParams)
+	assert.NotNil(t, reply.PlayerState)
+
+	// 没有 PlayerState 时不能直接选
+	_, err = mgr.PlayGame("playerpick", "", &sgc7pb.RequestPlay{
+		Stake:        stake,
+		Command:      "bg-pick",
+		ClientParams: "0",
+	})
+	assert.Error(t, err)
+
+	reply, err = mgr.PlayGame("playerpick", "", &sgc7pb.RequestPlay{
+		Stake:        stake,
+		Command:      "bg-pick",
+		ClientParams: "0",
+		PlayerState:  reply.PlayerState,
+	})
+	assert.NoError(t, err)
+	assert.False(t, reply.Finished)
+	assert.Equal(t, []string{"1", "2", "3"}, reply.NextCommandParams)
+
+	// 已经选过的位置不能再选
+	_, err = mgr.PlayGame("playerpick", "", &sgc7pb.RequestPlay{
+		Stake:        stake,
+		Command:      "bg-pick",
+		ClientParams: "0",
+		PlayerState:  reply.PlayerState,
+	})
+	assert.Error(t, err)
+
+	reply, err = mgr.PlayGame("playerpick", "", &sgc7pb.RequestPlay{
+		Stake:        stake,
+		Command:      "bg-pick",
+		ClientParams: "3",
+		PlayerState:  reply.PlayerState,
+	})
+	assert.NoError(t, err)
+	assert.True(t, reply.Finished)
+	assert.Empty(t, reply.NextCommands)
+
+	// 选完以后棋盘被清掉,旧的命令不能再用
+	_, err = mgr.PlayGame("playerpick", "", &sgc7pb.RequestPlay{
+		Stake:        stake,
+		Command:      "bg-pick",
+		ClientParams: "1",
+		PlayerState:  reply.PlayerState,
+	})
+	assert.Error(t, err)
+
+	t.Logf("Test_GameMgrPlayerPick OK")
+}
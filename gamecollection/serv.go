@@ -3,57 +3,76 @@ package gamecollection
 import (
 	"context"
 	"log/slog"
-	"net"
+	"time"
 
 	goutils "github.com/zhs007/goutils"
+	"github.com/zhs007/slotsgamecore7/cheatpolicy"
+	"github.com/zhs007/slotsgamecore7/grpcutils"
+	"github.com/zhs007/slotsgamecore7/jackpot"
 	"github.com/zhs007/slotsgamecore7/lowcode"
+	"github.com/zhs007/slotsgamecore7/metrics"
 	sgc7pbutils "github.com/zhs007/slotsgamecore7/pbutils"
+	"github.com/zhs007/slotsgamecore7/roundstore"
+	"github.com/zhs007/slotsgamecore7/rtpmonitor"
 	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
 	sgc7ver "github.com/zhs007/slotsgamecore7/ver"
-	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
-	"google.golang.org/grpc"
+	"github.com/zhs007/slotsgamecore7/wallet"
 )
 
 // Serv - Service
 type Serv struct {
 	sgc7pb.UnimplementedGameLogicCollectionServer
-	lis      net.Listener
-	grpcServ *grpc.Server
-	mgrGame  *GameMgr
+	grpcServ    *grpcutils.Server
+	mgrGame     *GameMgr
+	roundMgr    *roundstore.RoundMgr
+	cheatPolicy *cheatpolicy.Policy
+	metrics     *metrics.GameMetrics
+	rtpMonitor  *rtpmonitor.Monitor
+	stopCleanup context.CancelFunc
+}
+
+func init() {
+	metrics.AddErrorReason(ErrInvalidGameCode, "invalid_game")
+	metrics.AddErrorReason(ErrInvalidGameVersion, "invalid_game")
+	metrics.AddErrorReason(ErrInvalidGameParams, "invalid_params")
 }
 
 // NewServ -
 func NewServ(bindaddr string, version string, useOpenTelemetry bool, funcNewRNG lowcode.FuncNewRNG, funcNewFeatureLevel lowcode.FuncNewFeatureLevel) (*Serv, error) {
+	opts := &grpcutils.ServOptions{}
+
+	if useOpenTelemetry {
+		opts.UseOpenTelemetry = true
+		opts.MaxRecvMsgSize = 1024 * 1024 * 10
+		opts.MaxSendMsgSize = 1024 * 1024 * 10
+	}
+
+	return NewServWithOptions(bindaddr, version, opts, funcNewRNG, funcNewFeatureLevel)
+}
+
+// NewServWithOptions - new a Serv with ServOptions,
+//
+//	health 里每个 gameCode 是一个 service，InitGame 成功以后才是 SERVING
+func NewServWithOptions(bindaddr string, version string, opts *grpcutils.ServOptions, funcNewRNG lowcode.FuncNewRNG, funcNewFeatureLevel lowcode.FuncNewFeatureLevel) (*Serv, error) {
 	// lowcode.SetJsonMode()
 
-	lis, err := net.Listen("tcp", bindaddr)
+	grpcServ, err := grpcutils.NewServer(bindaddr, opts)
 	if err != nil {
-		goutils.Error("NewServ.Listen",
+		goutils.Error("NewServWithOptions:NewServer",
 			goutils.Err(err))
 
 		return nil, err
 	}
 
-	var grpcServ *grpc.Server
-
-	if useOpenTelemetry {
-		grpcServ = grpc.NewServer(
-			grpc.MaxRecvMsgSize(1024*1024*10),
-			grpc.MaxSendMsgSize(1024*1024*10),
-			// otelgrpc v0.63.0 uses stats handlers rather than interceptors
-			grpc.StatsHandler(otelgrpc.NewServerHandler()),
-		)
-	} else {
-		grpcServ = grpc.NewServer()
-	}
-
 	serv := &Serv{
-		lis:      lis,
 		grpcServ: grpcServ,
 		mgrGame:  NewGameMgr(funcNewRNG, funcNewFeatureLevel),
 	}
 
-	sgc7pb.RegisterGameLogicCollectionServer(grpcServ, serv)
+	sgc7pb.RegisterGameLogicCollectionServer(grpcServ.GRPCServ, serv)
+
+	grpcServ.SetServing("", true)
+	grpcServ.SetServing(sgc7pb.GameLogicCollection_ServiceDesc.ServiceName, true)
 
 	goutils.Info("NewServ OK.",
 		slog.String("addr", bindaddr),
@@ -63,14 +82,147 @@ func NewServ(bindaddr string, version string, useOpenTelemetry bool, funcNewRNG
 	return serv, nil
 }
 
+// SetRoundStore - save the rounds in store, so the unfinished rounds can be resumed,
+//
+//	w can be nil, if it is not nil, the bets and wins are in wallet, and the pending transactions are recovered here
+func (serv *Serv) SetRoundStore(store roundstore.RoundStore, w wallet.Wallet) error {
+	roundMgr := roundstore.NewRoundMgr(store, w)
+
+	num, err := roundMgr.RecoverRounds()
+	if err != nil {
+		goutils.Error("Serv.SetRoundStore:RecoverRounds",
+			goutils.Err(err))
+
+		return err
+	}
+
+	if num > 0 {
+		goutils.Info("Serv.SetRoundStore:RecoverRounds",
+			slog.Int("rounds", num))
+	}
+
+	if serv.stopCleanup != nil {
+		serv.stopCleanup()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	roundMgr.StartCleanup(ctx, roundstore.DefaultCleanupInterval)
+
+	serv.roundMgr = roundMgr
+	serv.stopCleanup = cancel
+
+	return nil
+}
+
+// SetJackpotPool - the jackpots are paid from jp in the rounds, SetRoundStore must be called first
+func (serv *Serv) SetJackpotPool(jp jackpot.JackpotPool) error {
+	if serv.roundMgr == nil {
+		goutils.Error("Serv.SetJackpotPool",
+			goutils.Err(roundstore.ErrNoRoundStore))
+
+		return roundstore.ErrNoRoundStore
+	}
+
+	serv.roundMgr.Jackpot = jp
+
+	return nil
+}
+
+// SetCheatPolicy - the cheats are rejected unless the policy allows them,
+//
+//	if the policy allows force outcome, lowcode.SetAllowForceOutcome is still needed
+func (serv *Serv) SetCheatPolicy(policy *cheatpolicy.Policy) {
+	serv.cheatPolicy = policy
+}
+
+// play - play with RoundStore if it is set
+func (serv *Serv) play(ctx context.Context, req *sgc7pb.RequestPlayGame) (*sgc7pb.ReplyPlay, error) {
+	if req.Play == nil {
+		goutils.Error("Serv.play",
+			slog.String("gameCode", req.GameCode),
+			goutils.Err(ErrInvalidGameParams))
+
+		return nil, ErrInvalidGameParams
+	}
+
+	err := serv.cheatPolicy.CheckGRPC(ctx, "gamecollection", req.GameCode, req.Play.Cheat)
+	if err != nil {
+		goutils.Error("Serv.play:CheckGRPC",
+			slog.String("gameCode", req.GameCode),
+			goutils.Err(err))
+
+		serv.metrics.OnError(serv.getMetricsGame(req.GameCode), err)
+
+		return nil, err
+	}
+
+	if serv.grpcServ.IsDraining() && metrics.IsBetCommand(req.Play.Command) {
+		goutils.Error("Serv.play",
+			slog.String("gameCode", req.GameCode),
+			goutils.Err(grpcutils.ErrServerDraining))
+
+		return nil, grpcutils.ErrServerDraining
+	}
+
+	if serv.roundMgr == nil {
+		return serv.playGame(ctx, req.GameCode, req.Version, req.Play)
+	}
+
+	return serv.roundMgr.Play(req.GameCode, req.Play, func(play *sgc7pb.RequestPlay) (*sgc7pb.ReplyPlay, error) {
+		return serv.playGame(ctx, req.GameCode, req.Version, play)
+	})
+}
+
+// getMetricsGame - the game label of metrics, the gameCode is from the client, so it's metrics.UnknownGame if the game is not loaded
+func (serv *Serv) getMetricsGame(gameCode string) string {
+	if serv.mgrGame.GetGameData(gameCode) == nil {
+		return metrics.UnknownGame
+	}
+
+	return gameCode
+}
+
+// playGame - GameMgr.PlayGame, and record it in metrics and RTP monitor
+func (serv *Serv) playGame(ctx context.Context, gameCode string, version string, play *sgc7pb.RequestPlay) (*sgc7pb.ReplyPlay, error) {
+	st := time.Now()
+
+	res, err := serv.mgrGame.PlayGameWithContext(ctx, gameCode, version, play)
+
+	serv.metrics.OnPlayPB(serv.getMetricsGame(gameCode), play, res, err, time.Since(st))
+
+	if err == nil {
+		serv.rtpMonitor.OnPlayPB(gameCode, play, res)
+	}
+
+	return res, err
+}
+
+// SetRTPMonitor - monitor the live RTP of the games
+func (serv *Serv) SetRTPMonitor(m *rtpmonitor.Monitor) {
+	serv.rtpMonitor = m
+}
+
+// SetMetrics - record the plays in m, use metrics.NewServ to serve m.Registry
+func (serv *Serv) SetMetrics(m *metrics.GameMetrics) {
+	serv.metrics = m
+
+	m.OnCollect(func() {
+		serv.mgrGame.collectMetrics(m)
+	})
+}
+
 // Start - start a service
 func (serv *Serv) Start(ctx context.Context) error {
-	return serv.grpcServ.Serve(serv.lis)
+	return serv.grpcServ.Start()
 }
 
-// Stop - stop service
+// Stop - stop service, the new rounds are rejected, and the in-flight requests can finish in DrainTimeout
 func (serv *Serv) Stop() {
-	serv.lis.Close()
+	if serv.stopCleanup != nil {
+		serv.stopCleanup()
+	}
+
+	serv.grpcServ.Stop()
 }
 
 // initGame - initial game
@@ -78,9 +230,14 @@ func (serv *Serv) InitGame(ctx context.Context, req *sgc7pb.RequestInitGame) (*s
 	goutils.Debug("Serv.InitGame",
 		slog.Any("req", req))
 
-	err := serv.mgrGame.InitGame(req.GameCode, []byte(req.Config))
+	// 第一次初始化时，成功之前都是 NOT_SERVING
+	if serv.mgrGame.GetGameData(req.GameCode) == nil {
+		serv.grpcServ.SetServing(req.GameCode, false)
+	}
+
+	version, err := serv.mgrGame.AddGameVersion(req.GameCode, []byte(req.Config), !req.NotActivate)
 	if err != nil {
-		goutils.Error("Serv.InitGame:InitGame",
+		goutils.Error("Serv.InitGame:AddGameVersion",
 			goutils.Err(err))
 
 		return &sgc7pb.ReplyInitGame{
@@ -89,8 +246,11 @@ func (serv *Serv) InitGame(ctx context.Context, req *sgc7pb.RequestInitGame) (*s
 		}, nil
 	}
 
+	serv.grpcServ.SetServing(req.GameCode, true)
+
 	return &sgc7pb.ReplyInitGame{
-		IsOK: true,
+		IsOK:    true,
+		Version: version,
 	}, nil
 }
 
@@ -143,7 +303,7 @@ func (serv *Serv) PlayGame(req *sgc7pb.RequestPlayGame, stream sgc7pb.GameLogicC
 	goutils.Debug("Serv.PlayGame",
 		slog.Any("req", req))
 
-	res, err := serv.mgrGame.PlayGame(req.GameCode, req.Play)
+	res, err := serv.play(stream.Context(), req)
 	if err != nil {
 		goutils.Error("Serv.PlayGame:PlayGame",
 			goutils.Err(err))
@@ -167,7 +327,7 @@ func (serv *Serv) PlayGame2(ctx context.Context, req *sgc7pb.RequestPlayGame) (*
 	goutils.Debug("Serv.PlayGame2",
 		slog.Any("req", req))
 
-	res, err := serv.mgrGame.PlayGame(req.GameCode, req.Play)
+	res, err := serv.play(ctx, req)
 	if err != nil {
 		goutils.Error("Serv.PlayGame:PlayGame2",
 			goutils.Err(err))
@@ -183,3 +343,99 @@ func (serv *Serv) PlayGame2(ctx context.Context, req *sgc7pb.RequestPlayGame) (*
 		Play: res,
 	}, nil
 }
+
+// ListGameVersions - list all versions of a game
+func (serv *Serv) ListGameVersions(ctx context.Context, req *sgc7pb.RequestListGameVersions) (*sgc7pb.ReplyListGameVersions, error) {
+	goutils.Debug("Serv.ListGameVersions",
+		slog.Any("req", req))
+
+	lst, err := serv.mgrGame.ListGameVersions(req.GameCode)
+	if err != nil {
+		goutils.Error("Serv.ListGameVersions:ListGameVersions",
+			goutils.Err(err))
+
+		return &sgc7pb.ReplyListGameVersions{
+			IsOK: false,
+			Err:  err.Error(),
+		}, nil
+	}
+
+	return &sgc7pb.ReplyListGameVersions{
+		IsOK:     true,
+		Versions: lst,
+	}, nil
+}
+
+// ActivateGameVersion - activate a version of game
+func (serv *Serv) ActivateGameVersion(ctx context.Context, req *sgc7pb.RequestActivateGameVersion) (*sgc7pb.ReplyActivateGameVersion, error) {
+	goutils.Debug("Serv.ActivateGameVersion",
+		slog.Any("req", req))
+
+	err := serv.mgrGame.ActivateGameVersion(req.GameCode, req.Version)
+	if err != nil {
+		goutils.Error("Serv.ActivateGameVersion:ActivateGameVersion",
+			goutils.Err(err))
+
+		return &sgc7pb.ReplyActivateGameVersion{
+			IsOK: false,
+			Err:  err.Error(),
+		}, nil
+	}
+
+	return &sgc7pb.ReplyActivateGameVersion{
+		IsOK: true,
+	}, nil
+}
+
+// RetireGameVersion - retire a version of game
+func (serv *Serv) RetireGameVersion(ctx context.Context, req *sgc7pb.RequestRetireGameVersion) (*sgc7pb.ReplyRetireGameVersion, error) {
+	goutils.Debug("Serv.RetireGameVersion",
+		slog.Any("req", req))
+
+	err := serv.mgrGame.RetireGameVersion(req.GameCode, req.Version)
+	if err != nil {
+		goutils.Error("Serv.RetireGameVersion:RetireGameVersion",
+			goutils.Err(err))
+
+		return &sgc7pb.ReplyRetireGameVersion{
+			IsOK: false,
+			Err:  err.Error(),
+		}, nil
+	}
+
+	return &sgc7pb.ReplyRetireGameVersion{
+		IsOK: true,
+	}, nil
+}
+
+// ResumeGameRound - get all results so far of an unfinished round
+func (serv *Serv) ResumeGameRound(ctx context.Context, req *sgc7pb.RequestResumeGameRound) (*sgc7pb.ReplyPlayGame, error) {
+	goutils.Debug("Serv.ResumeGameRound",
+		slog.Any("req", req))
+
+	if serv.roundMgr == nil {
+		goutils.Error("Serv.ResumeGameRound",
+			goutils.Err(roundstore.ErrNoRoundStore))
+
+		return &sgc7pb.ReplyPlayGame{
+			IsOK: false,
+			Err:  roundstore.ErrNoRoundStore.Error(),
+		}, nil
+	}
+
+	res, err := serv.roundMgr.ResumeRound(req.GameCode, req.PlayerID, req.RoundID)
+	if err != nil {
+		goutils.Error("Serv.ResumeGameRound:ResumeRound",
+			goutils.Err(err))
+
+		return &sgc7pb.ReplyPlayGame{
+			IsOK: false,
+			Err:  err.Error(),
+		}, nil
+	}
+
+	return &sgc7pb.ReplyPlayGame{
+		IsOK: true,
+		Play: res,
+	}, nil
+}
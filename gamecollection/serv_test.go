@@ -0,0 +1,263 @@
+package gamecollection
+
+import (
+	"context"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zhs007/slotsgamecore7/cheatpolicy"
+	"github.com/zhs007/slotsgamecore7/grpcutils"
+	"github.com/zhs007/slotsgamecore7/lowcode"
+	"github.com/zhs007/slotsgamecore7/metrics"
+	"github.com/zhs007/slotsgamecore7/roundstore"
+	"github.com/zhs007/slotsgamecore7/rtpmonitor"
+	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/proto"
+)
+
+func Test_ServRoundStore(t *testing.T) {
+	data, err := os.ReadFile("../unittestdata/testgame.json")
+	assert.NoError(t, err)
+
+	serv, err := NewServ("127.0.0.1:0", "test", false, lowcode.NewBasicRNG, lowcode.NewEmptyFeatureLevel)
+	assert.NoError(t, err)
+	defer serv.Stop()
+
+	res, err := serv.ResumeGameRound(context.Background(), &sgc7pb.RequestResumeGameRound{GameCode: "game1", RoundID: "abc"})
+	assert.NoError(t, err)
+	assert.False(t, res.IsOK)
+
+	err = serv.SetRoundStore(roundstore.NewMemRoundStore(), nil)
+	assert.NoError(t, err)
+
+	reply0, err := serv.InitGame(context.Background(), &sgc7pb.RequestInitGame{GameCode: "game1", Config: string(data)})
+	assert.NoError(t, err)
+	assert.True(t, reply0.IsOK)
+	assert.Equal(t, Hash(data), reply0.Version)
+
+	req := &sgc7pb.RequestPlayGame{
+		GameCode: "game1",
+		Play:     newTestRequestPlay(),
+	}
+	req.Play.RequestID = "req1"
+	req.Play.PlayerID = "p1"
+
+	reply1, err := serv.PlayGame2(context.Background(), req)
+	assert.NoError(t, err)
+	assert.True(t, reply1.IsOK)
+	assert.True(t, roundstore.IsValidRoundID(reply1.Play.RoundID))
+	assert.True(t, strings.HasSuffix(reply1.Play.RoundID, "-req1"))
+
+	// the duplicate request returns the same reply
+	reply2, err := serv.PlayGame2(context.Background(), req)
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(reply1, reply2))
+
+	res, err = serv.ResumeGameRound(context.Background(), &sgc7pb.RequestResumeGameRound{GameCode: "game1", RoundID: reply1.Play.RoundID, PlayerID: "p2"})
+	assert.NoError(t, err)
+	assert.False(t, res.IsOK)
+
+	res, err = serv.ResumeGameRound(context.Background(), &sgc7pb.RequestResumeGameRound{GameCode: "game1", RoundID: reply1.Play.RoundID, PlayerID: "p1"})
+	assert.NoError(t, err)
+	assert.True(t, res.IsOK)
+	assert.Equal(t, reply1.Play.Finished, res.Play.Finished)
+	assert.Equal(t, len(reply1.Play.Results), len(res.Play.Results))
+
+	t.Logf("Test_ServRoundStore OK")
+}
+
+func Test_ServCheatPolicy(t *testing.T) {
+	data, err := os.ReadFile("../unittestdata/testgame.json")
+	assert.NoError(t, err)
+
+	serv, err := NewServ("127.0.0.1:0", "test", false, lowcode.NewBasicRNG, lowcode.NewEmptyFeatureLevel)
+	assert.NoError(t, err)
+	defer serv.Stop()
+
+	reply0, err := serv.InitGame(context.Background(), &sgc7pb.RequestInitGame{GameCode: "game1", Config: string(data)})
+	assert.NoError(t, err)
+	assert.True(t, reply0.IsOK)
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 1234}})
+
+	req := &sgc7pb.RequestPlayGame{
+		GameCode: "game1",
+		Play:     newTestRequestPlay(),
+	}
+	req.Play.Cheat = "1,2,3"
+
+	// 默认不接受 cheat
+	reply1, err := serv.PlayGame2(ctx, req)
+	assert.NoError(t, err)
+	assert.False(t, reply1.IsOK)
+	assert.Equal(t, cheatpolicy.ErrCheatDisabled.Error(), reply1.Err)
+
+	serv.SetCheatPolicy(&cheatpolicy.Policy{
+		IsEnabled:    true,
+		GameCodes:    []string{"game2"},
+		AllowCallers: []string{"127.0.0.1"},
+	})
+
+	reply1, err = serv.PlayGame2(ctx, req)
+	assert.NoError(t, err)
+	assert.False(t, reply1.IsOK)
+	assert.Equal(t, cheatpolicy.ErrGameNotAllowed.Error(), reply1.Err)
+
+	serv.SetCheatPolicy(&cheatpolicy.Policy{
+		IsEnabled:    true,
+		GameCodes:    []string{"game1"},
+		AllowCallers: []string{"127.0.0.1"},
+	})
+
+	reply1, err = serv.PlayGame2(ctx, req)
+	assert.NoError(t, err)
+	assert.True(t, reply1.IsOK)
+
+	reply1, err = serv.PlayGame2(context.Background(), req)
+	assert.NoError(t, err)
+	assert.False(t, reply1.IsOK)
+	assert.Equal(t, cheatpolicy.ErrCallerNotAllowed.Error(), reply1.Err)
+
+	// 没有 cheat 的请求不受影响
+	req.Play.Cheat = ""
+	reply1, err = serv.PlayGame2(context.Background(), req)
+	assert.NoError(t, err)
+	assert.True(t, reply1.IsOK)
+
+	t.Logf("Test_ServCheatPolicy OK")
+}
+
+func Test_ServMetrics(t *testing.T) {
+	data, err := os.ReadFile("../unittestdata/testgame.json")
+	assert.NoError(t, err)
+
+	serv, err := NewServ("127.0.0.1:0", "test", false, lowcode.NewBasicRNG, lowcode.NewEmptyFeatureLevel)
+	assert.NoError(t, err)
+	defer serv.Stop()
+
+	m := metrics.NewGameMetrics("gamecollection")
+	serv.SetMetrics(m)
+
+	reply0, err := serv.InitGame(context.Background(), &sgc7pb.RequestInitGame{GameCode: "game1", Config: string(data)})
+	assert.NoError(t, err)
+	assert.True(t, reply0.IsOK)
+
+	reply1, err := serv.PlayGame2(context.Background(), &sgc7pb.RequestPlayGame{GameCode: "game1", Play: newTestRequestPlay()})
+	assert.NoError(t, err)
+	assert.True(t, reply1.IsOK)
+
+	reply1, err = serv.PlayGame2(context.Background(), &sgc7pb.RequestPlayGame{GameCode: "game2", Play: newTestRequestPlay()})
+	assert.NoError(t, err)
+	assert.False(t, reply1.IsOK)
+
+	assert.Equal(t, 1.0, m.Plays.Get("gamecollection", "game1", "SPIN"))
+	assert.Equal(t, 20.0, m.Bet.Get("gamecollection", "game1", "EUR"))
+	// gameCode 是客户端传的，没有这个游戏时用 unknown
+	assert.Equal(t, 1.0, m.Errors.Get("gamecollection", metrics.UnknownGame, "invalid_game"))
+	assert.Equal(t, 0.0, m.Errors.Get("gamecollection", "game2", "invalid_game"))
+
+	str := m.Registry.String()
+	assert.Contains(t, str, `sgc7_game_versions{server="gamecollection",game="game1"} 1`)
+	assert.Contains(t, str, `sgc7_game_active_version{server="gamecollection",game="game1",version="`+reply0.Version+`"} 1`)
+	assert.Contains(t, str, `sgc7_gameprop_pool_in_use{server="gamecollection",game="game1",version="`+reply0.Version+`",bet="20"} 0`)
+	assert.Contains(t, str, `sgc7_gameprop_pool_created{server="gamecollection",game="game1",version="`+reply0.Version+`",bet="20"}`)
+
+	t.Logf("Test_ServMetrics OK")
+}
+
+func Test_ServRTPMonitor(t *testing.T) {
+	data, err := os.ReadFile("../unittestdata/testgame.json")
+	assert.NoError(t, err)
+
+	serv, err := NewServ("127.0.0.1:0", "test", false, lowcode.NewBasicRNG, lowcode.NewEmptyFeatureLevel)
+	assert.NoError(t, err)
+	defer serv.Stop()
+
+	sink := rtpmonitor.NewMemSink()
+
+	// 理论 RTP 故意设得很高，第一局就会报警
+	m, err := rtpmonitor.NewMonitor(&rtpmonitor.Config{
+		WindowRounds: 10,
+		MinRounds:    1,
+		Games: map[string]*rtpmonitor.GameConfig{
+			"game1": {RTP: 1000, SD: 0.01},
+		},
+	}, sink)
+	assert.NoError(t, err)
+
+	serv.SetRTPMonitor(m)
+
+	reply0, err := serv.InitGame(context.Background(), &sgc7pb.RequestInitGame{GameCode: "game1", Config: string(data)})
+	assert.NoError(t, err)
+	assert.True(t, reply0.IsOK)
+
+	reply1, err := serv.PlayGame2(context.Background(), &sgc7pb.RequestPlayGame{GameCode: "game1", Play: newTestRequestPlay()})
+	assert.NoError(t, err)
+	assert.True(t, reply1.IsOK)
+
+	alerts := sink.GetAlerts()
+	assert.Equal(t, 1, len(alerts))
+	assert.Equal(t, rtpmonitor.LevelCritical, alerts[0].Level)
+	assert.Equal(t, "game1", alerts[0].GameCode)
+	assert.Equal(t, 20, alerts[0].BetMethod)
+
+	t.Logf("Test_ServRTPMonitor OK")
+}
+
+func Test_ServHealthAndDrain(t *testing.T) {
+	data, err := os.ReadFile("../unittestdata/testgame.json")
+	assert.NoError(t, err)
+
+	serv, err := NewServWithOptions("127.0.0.1:0", "test", &grpcutils.ServOptions{DrainTimeout: time.Second}, lowcode.NewBasicRNG, lowcode.NewEmptyFeatureLevel)
+	assert.NoError(t, err)
+	defer serv.Stop()
+
+	hs := serv.grpcServ.Health
+
+	res, err := hs.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, res.Status)
+
+	// InitGame 失败时是 NOT_SERVING
+	reply0, err := serv.InitGame(context.Background(), &sgc7pb.RequestInitGame{GameCode: "game1", Config: "{}"})
+	assert.NoError(t, err)
+	assert.False(t, reply0.IsOK)
+
+	res, err = hs.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "game1"})
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, res.Status)
+
+	reply0, err = serv.InitGame(context.Background(), &sgc7pb.RequestInitGame{GameCode: "game1", Config: string(data)})
+	assert.NoError(t, err)
+	assert.True(t, reply0.IsOK)
+
+	res, err = hs.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "game1"})
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, res.Status)
+
+	serv.Stop()
+
+	res, err = hs.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "game1"})
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, res.Status)
+
+	// drain 以后不接受新的局，但没结束的局可以继续
+	reply1, err := serv.PlayGame2(context.Background(), &sgc7pb.RequestPlayGame{GameCode: "game1", Play: newTestRequestPlay()})
+	assert.NoError(t, err)
+	assert.False(t, reply1.IsOK)
+	assert.Equal(t, grpcutils.ErrServerDraining.Error(), reply1.Err)
+
+	play := newTestRequestPlay()
+	play.Command = "RESPIN"
+	reply1, err = serv.PlayGame2(context.Background(), &sgc7pb.RequestPlayGame{GameCode: "game1", Play: play})
+	assert.NoError(t, err)
+	assert.NotEqual(t, grpcutils.ErrServerDraining.Error(), reply1.Err)
+
+	t.Logf("Test_ServHealthAndDrain OK")
+}
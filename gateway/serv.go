@@ -0,0 +1,197 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/valyala/fasthttp"
+	goutils "github.com/zhs007/goutils"
+	"github.com/zhs007/slotsgamecore7/cheatpolicy"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	"github.com/zhs007/slotsgamecore7/grpcutils"
+	sgc7http "github.com/zhs007/slotsgamecore7/http"
+	"github.com/zhs007/slotsgamecore7/roundstore"
+	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
+	"github.com/zhs007/slotsgamecore7/wallet"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// GameLogicURL - default prefix of GameLogic
+const GameLogicURL = "/v1/game"
+
+// GameLogicCollectionURL - default prefix of GameLogicCollection
+const GameLogicCollectionURL = "/v1/gamecollection"
+
+// Serv - JSON/REST gateway, the requests are forwarded to the same service as the gRPC server
+//
+//	x-caller-id 默认是不转发的，caller 是对端的 IP，只有前面有可信的代理会覆盖 x-caller-id 时，才能打开 IsTrustCallerID
+type Serv struct {
+	*sgc7http.Serv
+	IsTrustCallerID bool
+}
+
+// NewServ - new a serv
+func NewServ(bindAddr string, isDebugMode bool) *Serv {
+	return &Serv{
+		Serv: sgc7http.NewServ(bindAddr, isDebugMode),
+	}
+}
+
+// RegGameLogic - register GameLogic, prefix is like GameLogicURL
+func (s *Serv) RegGameLogic(prefix string, gl sgc7pb.GameLogicServer) {
+	regHandle(s, goutils.AppendString(prefix, "/config"), func() *sgc7pb.RequestConfig {
+		return &sgc7pb.RequestConfig{}
+	}, gl.GetConfig)
+
+	regHandle(s, goutils.AppendString(prefix, "/initialize"), func() *sgc7pb.RequestInitialize {
+		return &sgc7pb.RequestInitialize{}
+	}, gl.Initialize)
+
+	regHandle(s, goutils.AppendString(prefix, "/play"), func() *sgc7pb.RequestPlay {
+		return &sgc7pb.RequestPlay{}
+	}, gl.Play2)
+
+	regHandle(s, goutils.AppendString(prefix, "/resume"), func() *sgc7pb.RequestResumeRound {
+		return &sgc7pb.RequestResumeRound{}
+	}, gl.ResumeRound)
+}
+
+// RegGameLogicCollection - register GameLogicCollection, prefix is like GameLogicCollectionURL
+func (s *Serv) RegGameLogicCollection(prefix string, glc sgc7pb.GameLogicCollectionServer) {
+	regHandle(s, goutils.AppendString(prefix, "/initgame"), func() *sgc7pb.RequestInitGame {
+		return &sgc7pb.RequestInitGame{}
+	}, glc.InitGame)
+
+	regHandle(s, goutils.AppendString(prefix, "/config"), func() *sgc7pb.RequestGameConfig {
+		return &sgc7pb.RequestGameConfig{}
+	}, glc.GetGameConfig)
+
+	regHandle(s, goutils.AppendString(prefix, "/initialize"), func() *sgc7pb.RequestInitializeGamePlayer {
+		return &sgc7pb.RequestInitializeGamePlayer{}
+	}, glc.InitializeGamePlayer)
+
+	regHandle(s, goutils.AppendString(prefix, "/play"), func() *sgc7pb.RequestPlayGame {
+		return &sgc7pb.RequestPlayGame{}
+	}, glc.PlayGame2)
+
+	regHandle(s, goutils.AppendString(prefix, "/versions"), func() *sgc7pb.RequestListGameVersions {
+		return &sgc7pb.RequestListGameVersions{}
+	}, glc.ListGameVersions)
+
+	regHandle(s, goutils.AppendString(prefix, "/activate"), func() *sgc7pb.RequestActivateGameVersion {
+		return &sgc7pb.RequestActivateGameVersion{}
+	}, glc.ActivateGameVersion)
+
+	regHandle(s, goutils.AppendString(prefix, "/retire"), func() *sgc7pb.RequestRetireGameVersion {
+		return &sgc7pb.RequestRetireGameVersion{}
+	}, glc.RetireGameVersion)
+
+	regHandle(s, goutils.AppendString(prefix, "/resume"), func() *sgc7pb.RequestResumeGameRound {
+		return &sgc7pb.RequestResumeGameRound{}
+	}, glc.ResumeGameRound)
+}
+
+// regHandle - POST，body 是 protojson，空 body 等于空的请求
+func regHandle[Req proto.Message, Res proto.Message](s *Serv, url string, newReq func() Req,
+	onCall func(ctx context.Context, req Req) (Res, error)) {
+
+	s.RegHandle(url, func(ctx *fasthttp.RequestCtx, serv *sgc7http.Serv) {
+		setCORS(ctx)
+
+		if ctx.Request.Header.IsOptions() {
+			ctx.SetStatusCode(fasthttp.StatusNoContent)
+
+			return
+		}
+
+		if !ctx.Request.Header.IsPost() {
+			serv.SetHTTPStatus(ctx, fasthttp.StatusMethodNotAllowed)
+
+			return
+		}
+
+		req := newReq()
+
+		body := ctx.PostBody()
+		if len(body) > 0 {
+			err := protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(body, req)
+			if err != nil {
+				goutils.Warn("gateway.Serv:Unmarshal",
+					slog.String("url", url),
+					goutils.Err(err))
+
+				serv.SetHTTPStatus(ctx, fasthttp.StatusBadRequest)
+
+				return
+			}
+		}
+
+		res, err := onCall(BuildContext(ctx, s.IsTrustCallerID), req)
+		if err != nil {
+			goutils.Warn("gateway.Serv:onCall",
+				slog.String("url", url),
+				goutils.Err(err))
+
+			serv.SetHTTPStatus(ctx, GetHTTPStatus(err))
+
+			return
+		}
+
+		serv.SetPBResponse(ctx, res)
+	})
+}
+
+// BuildContext - 把 http 请求转成 gRPC 的 context，cheatpolicy 这些可以用同样的逻辑取 caller，
+//
+//	isTrustCallerID 为 false 时不转发客户端的 x-caller-id
+func BuildContext(ctx *fasthttp.RequestCtx, isTrustCallerID bool) context.Context {
+	c := peer.NewContext(ctx, &peer.Peer{
+		Addr: ctx.RemoteAddr(),
+	})
+
+	if !isTrustCallerID {
+		return c
+	}
+
+	callerID := ctx.Request.Header.Peek(cheatpolicy.CallerIDKey)
+	if len(callerID) > 0 {
+		c = metadata.NewIncomingContext(c, metadata.Pairs(cheatpolicy.CallerIDKey, string(callerID)))
+	}
+
+	return c
+}
+
+// GetHTTPStatus - error -> http status
+func GetHTTPStatus(err error) int {
+	if cheatpolicy.IsCheatError(err) {
+		return fasthttp.StatusForbidden
+	}
+
+	if errors.Is(err, grpcutils.ErrServerDraining) {
+		return fasthttp.StatusServiceUnavailable
+	}
+
+	if errors.Is(err, roundstore.ErrNoRoundStore) {
+		return fasthttp.StatusNotFound
+	}
+
+	if errors.Is(err, sgc7game.ErrInvalidStake) || errors.Is(err, roundstore.ErrInvalidRoundID) ||
+		errors.Is(err, roundstore.ErrRoundFinished) || errors.Is(err, roundstore.ErrInvalidPlayerID) ||
+		errors.Is(err, roundstore.ErrInvalidCommand) ||
+		errors.Is(err, wallet.ErrInsufficientBalance) {
+		return fasthttp.StatusBadRequest
+	}
+
+	return fasthttp.StatusInternalServerError
+}
+
+func setCORS(ctx *fasthttp.RequestCtx) {
+	ctx.Response.Header.Set("Access-Control-Allow-Origin", "*")
+	ctx.Response.Header.Set("Access-Control-Allow-Credentials", "true")
+	ctx.Response.Header.Set("Access-Control-Allow-Methods", "POST, OPTIONS")
+	ctx.Response.Header.Set("Access-Control-Allow-Headers", "Origin, X-Requested-With, Content-Type, Accept, Connection, User-Agent, Cookie")
+}
@@ -0,0 +1,243 @@
+package gateway
+
+import (
+	"net"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/zhs007/slotsgamecore7/cheatpolicy"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	"github.com/zhs007/slotsgamecore7/gamecollection"
+	"github.com/zhs007/slotsgamecore7/grpcserv"
+	"github.com/zhs007/slotsgamecore7/grpcutils"
+	"github.com/zhs007/slotsgamecore7/lowcode"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+	"github.com/zhs007/slotsgamecore7/roundstore"
+	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// testService - grpcserv.IService for lowcode game
+type testService struct {
+	*grpcserv.BasicService2
+}
+
+// BuildPBGameModParam - any -> *anypb.Any
+func (sv *testService) BuildPBGameModParam(gp any) (*anypb.Any, error) {
+	mygp, isok := gp.(*lowcode.GameParams)
+	if !isok {
+		return nil, sgc7game.ErrInvalidParam
+	}
+
+	return anypb.New(&mygp.GameParam)
+}
+
+// BuildPBGameModParamFromAny - *anypb.Any -> any
+func (sv *testService) BuildPBGameModParamFromAny(msg *anypb.Any) (any, error) {
+	mygp := &sgc7pb.GameParam{}
+
+	err := msg.UnmarshalTo(mygp)
+	if err != nil {
+		return nil, err
+	}
+
+	return mygp, nil
+}
+
+func newTestRequestPlay(cheat string) *sgc7pb.RequestPlay {
+	return &sgc7pb.RequestPlay{
+		Stake: &sgc7pb.Stake{
+			CoinBet:  1,
+			CashBet:  20,
+			Currency: "EUR",
+		},
+		Command: "SPIN",
+		Cheat:   cheat,
+	}
+}
+
+func call(t *testing.T, serv *Serv, method string, url string, ip string, req proto.Message, res proto.Message) int {
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Init(&fasthttp.Request{}, &net.TCPAddr{IP: net.ParseIP(ip), Port: 1234}, nil)
+	ctx.Request.Header.SetMethod(method)
+	ctx.Request.SetRequestURI(url)
+
+	if req != nil {
+		body, err := protojson.Marshal(req)
+		assert.NoError(t, err)
+
+		ctx.Request.SetBody(body)
+	}
+
+	serv.HandleFastHTTP(ctx)
+
+	if res != nil && ctx.Response.StatusCode() == fasthttp.StatusOK {
+		err := protojson.Unmarshal(ctx.Response.Body(), res)
+		assert.NoError(t, err)
+	}
+
+	return ctx.Response.StatusCode()
+}
+
+func Test_ServGameLogic(t *testing.T) {
+	game, err := lowcode.NewGame2("../unittestdata/testgame.json", func() sgc7plugin.IPlugin {
+		return sgc7plugin.NewFastPlugin()
+	}, lowcode.NewBasicRNG, lowcode.NewEmptyFeatureLevel)
+	assert.NoError(t, err)
+
+	gs, err := grpcserv.NewServ(&testService{grpcserv.NewBasicService2()}, game, "127.0.0.1:0", "test", false)
+	assert.NoError(t, err)
+	defer gs.Stop()
+
+	serv := NewServ("127.0.0.1:0", false)
+	serv.RegGameLogic(GameLogicURL, gs)
+
+	cfg := &sgc7pb.GameConfig{}
+	code := call(t, serv, fasthttp.MethodPost, "/v1/game/config", "127.0.0.1", nil, cfg)
+	assert.Equal(t, fasthttp.StatusOK, code)
+	assert.NotEmpty(t, cfg.PayTables)
+
+	code = call(t, serv, fasthttp.MethodGet, "/v1/game/config", "127.0.0.1", nil, nil)
+	assert.Equal(t, fasthttp.StatusMethodNotAllowed, code)
+
+	ps := &sgc7pb.PlayerState{}
+	code = call(t, serv, fasthttp.MethodPost, "/v1/game/initialize", "127.0.0.1", &sgc7pb.RequestInitialize{}, ps)
+	assert.Equal(t, fasthttp.StatusOK, code)
+
+	reply := &sgc7pb.ReplyPlay{}
+	code = call(t, serv, fasthttp.MethodPost, "/v1/game/play", "127.0.0.1", newTestRequestPlay(""), reply)
+	assert.Equal(t, fasthttp.StatusOK, code)
+	assert.NotEmpty(t, reply.Results)
+	assert.NotEmpty(t, reply.RandomNumbers)
+
+	// cheat 和 gRPC 一样走 cheatpolicy
+	code = call(t, serv, fasthttp.MethodPost, "/v1/game/play", "127.0.0.1", newTestRequestPlay("1,2,3"), nil)
+	assert.Equal(t, fasthttp.StatusForbidden, code)
+
+	gs.SetCheatPolicy(&cheatpolicy.Policy{
+		IsEnabled:    true,
+		AllowCallers: []string{"127.0.0.1"},
+	})
+
+	code = call(t, serv, fasthttp.MethodPost, "/v1/game/play", "127.0.0.1", newTestRequestPlay("1,2,3"), nil)
+	assert.Equal(t, fasthttp.StatusOK, code)
+
+	code = call(t, serv, fasthttp.MethodPost, "/v1/game/play", "10.0.0.1", newTestRequestPlay("1,2,3"), nil)
+	assert.Equal(t, fasthttp.StatusForbidden, code)
+
+	// 没有 RoundStore
+	code = call(t, serv, fasthttp.MethodPost, "/v1/game/resume", "127.0.0.1", &sgc7pb.RequestResumeRound{RoundID: "123"}, nil)
+	assert.Equal(t, fasthttp.StatusNotFound, code)
+
+	code = call(t, serv, fasthttp.MethodPost, "/v1/game/unknown", "127.0.0.1", nil, nil)
+	assert.Equal(t, fasthttp.StatusNotFound, code)
+
+	t.Logf("Test_ServGameLogic OK")
+}
+
+func Test_ServGameLogicCollection(t *testing.T) {
+	data, err := os.ReadFile("../unittestdata/testgame.json")
+	assert.NoError(t, err)
+
+	gs, err := gamecollection.NewServ("127.0.0.1:0", "test", false, lowcode.NewBasicRNG, lowcode.NewEmptyFeatureLevel)
+	assert.NoError(t, err)
+	defer gs.Stop()
+
+	serv := NewServ("127.0.0.1:0", false)
+	serv.RegGameLogicCollection(GameLogicCollectionURL, gs)
+
+	replyInit := &sgc7pb.ReplyInitGame{}
+	code := call(t, serv, fasthttp.MethodPost, "/v1/gamecollection/initgame", "127.0.0.1",
+		&sgc7pb.RequestInitGame{GameCode: "game1", Config: string(data)}, replyInit)
+	assert.Equal(t, fasthttp.StatusOK, code)
+	assert.True(t, replyInit.IsOK)
+
+	replyCfg := &sgc7pb.ReplyGameConfig{}
+	code = call(t, serv, fasthttp.MethodPost, "/v1/gamecollection/config", "127.0.0.1",
+		&sgc7pb.RequestGameConfig{GameCode: "game1"}, replyCfg)
+	assert.Equal(t, fasthttp.StatusOK, code)
+	assert.True(t, replyCfg.IsOK)
+
+	replyPS := &sgc7pb.ReplyInitializeGamePlayer{}
+	code = call(t, serv, fasthttp.MethodPost, "/v1/gamecollection/initialize", "127.0.0.1",
+		&sgc7pb.RequestInitializeGamePlayer{GameCode: "game1"}, replyPS)
+	assert.Equal(t, fasthttp.StatusOK, code)
+	assert.True(t, replyPS.IsOK)
+
+	replyPlay := &sgc7pb.ReplyPlayGame{}
+	code = call(t, serv, fasthttp.MethodPost, "/v1/gamecollection/play", "127.0.0.1",
+		&sgc7pb.RequestPlayGame{GameCode: "game1", Play: newTestRequestPlay("")}, replyPlay)
+	assert.Equal(t, fasthttp.StatusOK, code)
+	assert.True(t, replyPlay.IsOK)
+	assert.NotEmpty(t, replyPlay.Play.Results)
+
+	// GameLogicCollection 的错误放在 reply 里
+	replyPlay = &sgc7pb.ReplyPlayGame{}
+	code = call(t, serv, fasthttp.MethodPost, "/v1/gamecollection/play", "127.0.0.1",
+		&sgc7pb.RequestPlayGame{GameCode: "game1", Play: newTestRequestPlay("1,2,3")}, replyPlay)
+	assert.Equal(t, fasthttp.StatusOK, code)
+	assert.False(t, replyPlay.IsOK)
+	assert.Equal(t, cheatpolicy.ErrCheatDisabled.Error(), replyPlay.Err)
+
+	replyVers := &sgc7pb.ReplyListGameVersions{}
+	code = call(t, serv, fasthttp.MethodPost, "/v1/gamecollection/versions", "127.0.0.1",
+		&sgc7pb.RequestListGameVersions{GameCode: "game1"}, replyVers)
+	assert.Equal(t, fasthttp.StatusOK, code)
+	assert.True(t, replyVers.IsOK)
+	assert.Equal(t, 1, len(replyVers.Versions))
+
+	replyResume := &sgc7pb.ReplyPlayGame{}
+	code = call(t, serv, fasthttp.MethodPost, "/v1/gamecollection/resume", "127.0.0.1",
+		&sgc7pb.RequestResumeGameRound{GameCode: "game1", RoundID: "123"}, replyResume)
+	assert.Equal(t, fasthttp.StatusOK, code)
+	assert.False(t, replyResume.IsOK)
+	assert.Equal(t, roundstore.ErrNoRoundStore.Error(), replyResume.Err)
+
+	// 空 body 等于空的请求
+	replyPlay = &sgc7pb.ReplyPlayGame{}
+	code = call(t, serv, fasthttp.MethodPost, "/v1/gamecollection/play", "127.0.0.1", nil, replyPlay)
+	assert.Equal(t, fasthttp.StatusOK, code)
+	assert.False(t, replyPlay.IsOK)
+	assert.Equal(t, gamecollection.ErrInvalidGameParams.Error(), replyPlay.Err)
+
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Init(&fasthttp.Request{}, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 1234}, nil)
+	ctx.Request.Header.SetMethod(fasthttp.MethodPost)
+	ctx.Request.SetRequestURI("/v1/gamecollection/play")
+	ctx.Request.SetBody([]byte("{"))
+	serv.HandleFastHTTP(ctx)
+	assert.Equal(t, fasthttp.StatusBadRequest, ctx.Response.StatusCode())
+
+	t.Logf("Test_ServGameLogicCollection OK")
+}
+
+func Test_GetHTTPStatus(t *testing.T) {
+	assert.Equal(t, fasthttp.StatusForbidden, GetHTTPStatus(cheatpolicy.ErrCallerNotAllowed))
+	assert.Equal(t, fasthttp.StatusServiceUnavailable, GetHTTPStatus(grpcutils.ErrServerDraining))
+	assert.Equal(t, fasthttp.StatusBadRequest, GetHTTPStatus(sgc7game.ErrInvalidStake))
+	assert.Equal(t, fasthttp.StatusNotFound, GetHTTPStatus(roundstore.ErrNoRoundStore))
+	assert.Equal(t, fasthttp.StatusInternalServerError, GetHTTPStatus(sgc7game.ErrInvalidParam))
+
+	t.Logf("Test_GetHTTPStatus OK")
+}
+
+func Test_BuildContext(t *testing.T) {
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Init(&fasthttp.Request{}, &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1234}, nil)
+	ctx.Request.Header.Set(cheatpolicy.CallerIDKey, "qa")
+
+	policy := &cheatpolicy.Policy{IsTrustCallerID: true}
+
+	// 客户端的 x-caller-id 默认不转发
+	assert.Equal(t, "10.0.0.1", policy.GetGRPCCaller(BuildContext(ctx, false)))
+	assert.Equal(t, "qa", policy.GetGRPCCaller(BuildContext(ctx, true)))
+
+	setCORS(ctx)
+	assert.NotContains(t, string(ctx.Response.Header.Peek("Access-Control-Allow-Headers")), "X-Caller-Id")
+
+	t.Logf("Test_BuildContext OK")
+}
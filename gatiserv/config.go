@@ -1,8 +1,12 @@
 package gatiserv
 
+import "github.com/zhs007/slotsgamecore7/cheatpolicy"
+
 // Config - configuration
 type Config struct {
 	GameID      string
 	BindAddr    string
 	IsDebugMode bool
+	// CheatPolicy - 为空时不接受任何 cheat
+	CheatPolicy *cheatpolicy.Policy
 }
@@ -2,11 +2,13 @@ package gatiserv
 
 import (
 	"log/slog"
+	"time"
 
 	"github.com/valyala/fasthttp"
 	goutils "github.com/zhs007/goutils"
 	sgc7game "github.com/zhs007/slotsgamecore7/game"
 	sgc7http "github.com/zhs007/slotsgamecore7/http"
+	"github.com/zhs007/slotsgamecore7/metrics"
 )
 
 // BasicURL - basic url
@@ -17,6 +19,7 @@ type Serv struct {
 	*sgc7http.Serv
 	Service IService
 	Cfg     *Config
+	metrics *metrics.GameMetrics
 }
 
 // NewServ - new a serv
@@ -25,6 +28,7 @@ func NewServ(service IService, cfg *Config) *Serv {
 		sgc7http.NewServ(cfg.BindAddr, cfg.IsDebugMode),
 		service,
 		cfg,
+		nil,
 	}
 
 	s.RegHandle(goutils.AppendString(BasicURL, cfg.GameID, "/config"),
@@ -118,7 +122,19 @@ func NewServ(service IService, cfg *Config) *Serv {
 				return
 			}
 
-			ret, err := s.Service.Play(params)
+			err = s.Cfg.CheatPolicy.CheckHTTP(ctx, "gatiserv", s.Cfg.GameID, params.Cheat)
+			if err != nil {
+				goutils.Warn("gatiserv.Serv.play:CheckHTTP",
+					goutils.Err(err))
+
+				s.metrics.OnError(s.Cfg.GameID, err)
+
+				s.SetHTTPStatus(ctx, fasthttp.StatusForbidden)
+
+				return
+			}
+
+			ret, err := s.play(params)
 			if err != nil {
 				goutils.Warn("gatiserv.Serv.play:Play",
 					goutils.Err(err))
@@ -241,3 +257,43 @@ func (serv *Serv) RegMission(id string) {
 			serv.SetResponse(ctx, ret)
 		})
 }
+
+// SetMetrics - record the plays in m, use metrics.NewServ to serve m.Registry
+func (s *Serv) SetMetrics(m *metrics.GameMetrics) {
+	s.metrics = m
+
+	bs, isok := s.Service.(*BasicService)
+	if isok {
+		m.OnCollect(func() {
+			m.SetGamePool(s.Cfg.GameID, "", bs.Game)
+		})
+	}
+}
+
+// play - Service.Play, and record it in metrics
+func (s *Serv) play(params *PlayParams) (*PlayResult, error) {
+	st := time.Now()
+
+	ret, err := s.Service.Play(params)
+
+	if s.metrics != nil {
+		info := &metrics.PlayInfo{
+			GameCode: s.Cfg.GameID,
+			Command:  params.Cmd,
+			Currency: params.Stake.Currency,
+			CashBet:  params.Stake.CashBet,
+		}
+
+		if ret != nil {
+			info.RngNum = len(ret.RandomNumbers)
+
+			for _, r := range ret.Results {
+				info.CashWin += r.CashWin
+			}
+		}
+
+		s.metrics.OnPlay(info, err, time.Since(st))
+	}
+
+	return ret, err
+}
@@ -1,13 +1,16 @@
 package gatiserv
 
 import (
+	"net"
 	"testing"
 	"time"
 
 	"github.com/bytedance/sonic"
 	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
 
 	goutils "github.com/zhs007/goutils"
+	"github.com/zhs007/slotsgamecore7/cheatpolicy"
 	sgc7game "github.com/zhs007/slotsgamecore7/game"
 	sgc7http "github.com/zhs007/slotsgamecore7/http"
 )
@@ -321,3 +324,52 @@ func Test_Serv(t *testing.T) {
 
 	t.Logf("Test_Serv OK")
 }
+
+func playWithCheat(t *testing.T, serv *Serv, ip string, cheat string) int {
+	body, err := sonic.Marshal(&PlayParams{Cheat: cheat})
+	assert.NoError(t, err)
+
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Init(&fasthttp.Request{}, &net.TCPAddr{IP: net.ParseIP(ip), Port: 1234}, nil)
+	ctx.Request.Header.SetMethod(fasthttp.MethodPost)
+	ctx.Request.SetRequestURI("/v2/games/1019/play")
+	ctx.Request.SetBody(body)
+
+	serv.HandleFastHTTP(ctx)
+
+	return ctx.Response.StatusCode()
+}
+
+func Test_ServCheatPolicy(t *testing.T) {
+	cfg := &Config{
+		GameID:   "1019",
+		BindAddr: "127.0.0.1:0",
+	}
+
+	service := &testService{
+		&sgc7game.Config{
+			Width:  5,
+			Height: 3,
+		},
+		0,
+		nil,
+	}
+
+	serv := NewServ(service, cfg)
+
+	// 默认不接受 cheat
+	assert.Equal(t, fasthttp.StatusForbidden, playWithCheat(t, serv, "127.0.0.1", "1,2,3"))
+	assert.Equal(t, fasthttp.StatusOK, playWithCheat(t, serv, "127.0.0.1", ""))
+
+	cfg.CheatPolicy = &cheatpolicy.Policy{
+		IsEnabled:    true,
+		GameCodes:    []string{"1019"},
+		AllowCallers: []string{"127.0.0.1"},
+	}
+
+	assert.Equal(t, fasthttp.StatusOK, playWithCheat(t, serv, "127.0.0.1", "1,2,3"))
+	assert.Equal(t, fasthttp.StatusForbidden, playWithCheat(t, serv, "10.0.0.1", "1,2,3"))
+	assert.Equal(t, fasthttp.StatusForbidden, playWithCheat(t, serv, "127.0.0.1", "bg-wins>0"))
+
+	t.Logf("Test_ServCheatPolicy OK")
+}
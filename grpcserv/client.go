@@ -158,3 +158,37 @@ func (client *Client) Play(ctx context.Context, ps *sgc7pb.PlayerState,
 		}
 	}
 }
+
+// ResumeRound - get all results so far of an unfinished round
+func (client *Client) ResumeRound(ctx context.Context, playerID string, roundID string) (*sgc7pb.ReplyPlay, error) {
+	if client.conn == nil || client.client == nil {
+		conn, err := grpc.Dial(client.servAddr, grpc.WithInsecure())
+		if err != nil {
+			goutils.Error("Client.ResumeRound:grpc.Dial",
+				slog.String("server address", client.servAddr),
+				goutils.Err(err))
+
+			return nil, err
+		}
+
+		client.conn = conn
+		client.client = sgc7pb.NewGameLogicClient(conn)
+	}
+
+	res, err := client.client.ResumeRound(ctx, &sgc7pb.RequestResumeRound{
+		RoundID:  roundID,
+		PlayerID: playerID,
+	})
+	if err != nil {
+		goutils.Error("Client.ResumeRound:ResumeRound",
+			slog.String("server address", client.servAddr),
+			slog.String("roundID", roundID),
+			goutils.Err(err))
+
+		client.reset()
+
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,141 @@
+package grpcserv
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/bytedance/sonic"
+	goutils "github.com/zhs007/goutils"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	sgc7pbutils "github.com/zhs007/slotsgamecore7/pbutils"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
+)
+
+// ProcCheat - process cheat
+func ProcCheat(plugin sgc7plugin.IPlugin, cheat string) error {
+	if cheat != "" {
+		str := goutils.AppendString("[", cheat, "]")
+
+		rngs := []int{}
+		err := sonic.Unmarshal([]byte(str), &rngs)
+		if err != nil {
+			return err
+		}
+
+		plugin.SetCache(rngs)
+	}
+
+	return nil
+}
+
+// PlayGame - play game, it is shared by grpcserv, simserv and gateway
+func PlayGame(service IService, game sgc7game.IGame, req *sgc7pb.RequestPlay) (*sgc7pb.ReplyPlay, error) {
+	return PlayGameWithContext(context.Background(), service, game, req)
+}
+
+// PlayGameWithContext - like PlayGame, ctx is passed down to IPlugin.Random, and the play stops if ctx is canceled
+func PlayGameWithContext(ctx context.Context, service IService, game sgc7game.IGame, req *sgc7pb.RequestPlay) (*sgc7pb.ReplyPlay, error) {
+	ips := game.NewPlayerState()
+	if req.PlayerState != nil {
+		err := service.BuildPlayerStateFromPB(ips, req.PlayerState)
+		if err != nil {
+			goutils.Error("PlayGame:BuildPlayerStateFromPB",
+				goutils.Err(err))
+
+			return nil, err
+		}
+	}
+
+	basePlugin := game.NewPlugin()
+	defer game.FreePlugin(basePlugin)
+
+	plugin := sgc7plugin.WithContext(basePlugin, ctx)
+
+	ProcCheat(plugin, req.Cheat)
+
+	stake := sgc7pbutils.BuildStake(req.Stake)
+	err := game.CheckStake(stake)
+	if err != nil {
+		goutils.Error("PlayGame:CheckStake",
+			slog.Any("stake", stake),
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	results := []*sgc7game.PlayResult{}
+	gameData := game.NewGameData(stake)
+	if gameData == nil {
+		goutils.Error("PlayGame:NewGameData",
+			goutils.Err(sgc7game.ErrInvalidStake))
+
+		return nil, sgc7game.ErrInvalidStake
+	}
+
+	defer game.DeleteGameData(gameData)
+
+	cmd := req.Command
+
+	game.OnBet(plugin, cmd, req.ClientParams, ips, stake, results, gameData)
+
+	for {
+		if cmd == "" {
+			cmd = "SPIN"
+		}
+
+		pr, err := sgc7game.PlayWithContext(ctx, game, plugin, cmd, req.ClientParams, ips, stake, results, gameData)
+		if err != nil {
+			goutils.Error("PlayGame:Play",
+				slog.Int("results", len(results)),
+				goutils.Err(err))
+
+			return nil, err
+		}
+
+		if pr == nil {
+			break
+		}
+
+		results = append(results, pr)
+		if pr.IsFinish {
+			break
+		}
+
+		if pr.IsWait {
+			break
+		}
+
+		if len(pr.NextCmds) > 0 {
+			cmd = pr.NextCmds[0]
+		} else {
+			cmd = ""
+		}
+	}
+
+	pr := &sgc7pb.ReplyPlay{
+		RandomNumbers: sgc7pbutils.BuildPBRngs(plugin.GetUsedRngs()),
+	}
+
+	ps, err := service.BuildPBPlayerState(ips)
+	if err != nil {
+		goutils.Error("PlayGame:BuildPlayerState",
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	pr.PlayerState = ps
+
+	if len(results) > 0 {
+		AddPlayResult(service, pr, results)
+
+		lastr := results[len(results)-1]
+
+		pr.Finished = lastr.IsFinish
+		pr.NextCommands = lastr.NextCmds
+		pr.NextCommandParams = lastr.NextCmdParams
+	}
+
+	return pr, nil
+}
@@ -3,58 +3,64 @@ package grpcserv
 import (
 	"context"
 	"log/slog"
-	"net"
+	"time"
 
-	"github.com/bytedance/sonic"
 	goutils "github.com/zhs007/goutils"
+	"github.com/zhs007/slotsgamecore7/cheatpolicy"
 	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	"github.com/zhs007/slotsgamecore7/grpcutils"
+	"github.com/zhs007/slotsgamecore7/jackpot"
+	"github.com/zhs007/slotsgamecore7/metrics"
 	sgc7pbutils "github.com/zhs007/slotsgamecore7/pbutils"
 	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+	"github.com/zhs007/slotsgamecore7/roundstore"
+	"github.com/zhs007/slotsgamecore7/rtpmonitor"
 	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
 	sgc7ver "github.com/zhs007/slotsgamecore7/ver"
-	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"github.com/zhs007/slotsgamecore7/wallet"
 	"go.uber.org/zap/zapcore"
-	"google.golang.org/grpc"
 )
 
 // Serv - Game Logic Service
 type Serv struct {
 	sgc7pb.UnimplementedGameLogicServer
-	lis      net.Listener
-	grpcServ *grpc.Server
-	service  IService
-	game     sgc7game.IGame
+	grpcServ    *grpcutils.Server
+	service     IService
+	game        sgc7game.IGame
+	roundMgr    *roundstore.RoundMgr
+	cheatPolicy *cheatpolicy.Policy
+	metrics     *metrics.GameMetrics
+	rtpMonitor  *rtpmonitor.Monitor
+	stopCleanup context.CancelFunc
 }
 
 // NewServ -
 func NewServ(service IService, game sgc7game.IGame, bindaddr string, version string, useOpenTelemetry bool) (*Serv, error) {
-	lis, err := net.Listen("tcp", bindaddr)
+	return NewServWithOptions(service, game, bindaddr, version, &grpcutils.ServOptions{
+		UseOpenTelemetry: useOpenTelemetry,
+	})
+}
+
+// NewServWithOptions - new a Serv with ServOptions
+func NewServWithOptions(service IService, game sgc7game.IGame, bindaddr string, version string, opts *grpcutils.ServOptions) (*Serv, error) {
+	grpcServ, err := grpcutils.NewServer(bindaddr, opts)
 	if err != nil {
-		goutils.Error("NewServ.Listen",
+		goutils.Error("NewServWithOptions:NewServer",
 			goutils.Err(err))
 
 		return nil, err
 	}
 
-	var grpcServ *grpc.Server
-
-	if useOpenTelemetry {
-		grpcServ = grpc.NewServer(
-			// otelgrpc v0.63.0 uses stats handlers rather than interceptors
-			grpc.StatsHandler(otelgrpc.NewServerHandler()),
-		)
-	} else {
-		grpcServ = grpc.NewServer()
-	}
-
 	serv := &Serv{
-		lis:      lis,
 		grpcServ: grpcServ,
 		service:  service,
 		game:     game,
 	}
 
-	sgc7pb.RegisterGameLogicServer(grpcServ, serv)
+	sgc7pb.RegisterGameLogicServer(grpcServ.GRPCServ, serv)
+
+	grpcServ.SetServing("", true)
+	grpcServ.SetServing(sgc7pb.GameLogic_ServiceDesc.ServiceName, true)
 
 	goutils.Info("NewServ OK.",
 		slog.String("addr", bindaddr),
@@ -64,14 +70,85 @@ func NewServ(service IService, game sgc7game.IGame, bindaddr string, version str
 	return serv, nil
 }
 
+// SetRoundStore - save the rounds in store, so the unfinished rounds can be resumed,
+//
+//	w can be nil, if it is not nil, the bets and wins are in wallet, and the pending transactions are recovered here
+func (serv *Serv) SetRoundStore(store roundstore.RoundStore, w wallet.Wallet) error {
+	roundMgr := roundstore.NewRoundMgr(store, w)
+
+	num, err := roundMgr.RecoverRounds()
+	if err != nil {
+		goutils.Error("Serv.SetRoundStore:RecoverRounds",
+			goutils.Err(err))
+
+		return err
+	}
+
+	if num > 0 {
+		goutils.Info("Serv.SetRoundStore:RecoverRounds",
+			slog.Int("rounds", num))
+	}
+
+	if serv.stopCleanup != nil {
+		serv.stopCleanup()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	roundMgr.StartCleanup(ctx, roundstore.DefaultCleanupInterval)
+
+	serv.roundMgr = roundMgr
+	serv.stopCleanup = cancel
+
+	return nil
+}
+
+// SetJackpotPool - the jackpots are paid from jp in the rounds, SetRoundStore must be called first
+func (serv *Serv) SetJackpotPool(jp jackpot.JackpotPool) error {
+	if serv.roundMgr == nil {
+		goutils.Error("Serv.SetJackpotPool",
+			goutils.Err(roundstore.ErrNoRoundStore))
+
+		return roundstore.ErrNoRoundStore
+	}
+
+	serv.roundMgr.Jackpot = jp
+
+	return nil
+}
+
+// SetCheatPolicy - the cheats are rejected unless the policy allows them,
+//
+//	there is only one game in this server, so GameCodes in policy should be empty
+func (serv *Serv) SetCheatPolicy(policy *cheatpolicy.Policy) {
+	serv.cheatPolicy = policy
+}
+
+// SetMetrics - record the plays in m, use metrics.NewServ to serve m.Registry
+func (serv *Serv) SetMetrics(m *metrics.GameMetrics) {
+	serv.metrics = m
+
+	m.OnCollect(func() {
+		m.SetGamePool("", "", serv.game)
+	})
+}
+
+// SetRTPMonitor - monitor the live RTP, there is only one game in this server, so the gameCode of theory is empty
+func (serv *Serv) SetRTPMonitor(m *rtpmonitor.Monitor) {
+	serv.rtpMonitor = m
+}
+
 // Start - start a service
 func (serv *Serv) Start(ctx context.Context) error {
-	return serv.grpcServ.Serve(serv.lis)
+	return serv.grpcServ.Start()
 }
 
-// Stop - stop service
+// Stop - stop service, the new rounds are rejected, and the in-flight requests can finish in DrainTimeout
 func (serv *Serv) Stop() {
-	serv.lis.Close()
+	if serv.stopCleanup != nil {
+		serv.stopCleanup()
+	}
+
+	serv.grpcServ.Stop()
 }
 
 // GetConfig - get config
@@ -111,9 +188,19 @@ func (serv *Serv) Play(req *sgc7pb.RequestPlay, stream sgc7pb.GameLogic_PlayServ
 	goutils.Debug("Serv.Play",
 		slog.Any("req", req))
 
-	res, err := serv.onPlay(req)
+	err := serv.cheatPolicy.CheckGRPC(stream.Context(), "grpcserv", "", req.Cheat)
 	if err != nil {
-		goutils.Error("Serv.Play:onPlay",
+		goutils.Error("Serv.Play:CheckGRPC",
+			goutils.Err(err))
+
+		serv.metrics.OnError("", err)
+
+		return err
+	}
+
+	res, err := serv.play(stream.Context(), req)
+	if err != nil {
+		goutils.Error("Serv.Play:play",
 			goutils.Err(err))
 
 		return err
@@ -129,9 +216,19 @@ func (serv *Serv) Play2(ctx context.Context, req *sgc7pb.RequestPlay) (*sgc7pb.R
 	goutils.Debug("Serv.Play",
 		slog.Any("req", req))
 
-	res, err := serv.onPlay(req)
+	err := serv.cheatPolicy.CheckGRPC(ctx, "grpcserv", "", req.Cheat)
 	if err != nil {
-		goutils.Error("Serv.Play:onPlay",
+		goutils.Error("Serv.Play2:CheckGRPC",
+			goutils.Err(err))
+
+		serv.metrics.OnError("", err)
+
+		return nil, err
+	}
+
+	res, err := serv.play(ctx, req)
+	if err != nil {
+		goutils.Error("Serv.Play:play",
 			goutils.Err(err))
 
 		return nil, err
@@ -144,125 +241,72 @@ func (serv *Serv) Play2(ctx context.Context, req *sgc7pb.RequestPlay) (*sgc7pb.R
 	return res, nil
 }
 
-// ProcCheat - process cheat
-func (serv *Serv) ProcCheat(plugin sgc7plugin.IPlugin, cheat string) error {
-	if cheat != "" {
-		str := goutils.AppendString("[", cheat, "]")
-
-		rngs := []int{}
-		err := sonic.Unmarshal([]byte(str), &rngs)
-		if err != nil {
-			return err
-		}
-
-		plugin.SetCache(rngs)
-	}
+// ResumeRound - get all results so far of an unfinished round
+func (serv *Serv) ResumeRound(ctx context.Context, req *sgc7pb.RequestResumeRound) (*sgc7pb.ReplyPlay, error) {
+	goutils.Debug("Serv.ResumeRound",
+		slog.Any("req", req))
 
-	return nil
-}
+	if serv.roundMgr == nil {
+		goutils.Error("Serv.ResumeRound",
+			goutils.Err(roundstore.ErrNoRoundStore))
 
-// Play - play game
-func (serv *Serv) onPlay(req *sgc7pb.RequestPlay) (*sgc7pb.ReplyPlay, error) {
-	ips := serv.game.NewPlayerState()
-	if req.PlayerState != nil {
-		err := serv.service.BuildPlayerStateFromPB(ips, req.PlayerState)
-		if err != nil {
-			goutils.Error("Serv.onPlay:BuildPlayerStateFromPB",
-				goutils.Err(err))
-
-			return nil, err
-		}
+		return nil, roundstore.ErrNoRoundStore
 	}
 
-	plugin := serv.game.NewPlugin()
-	defer serv.game.FreePlugin(plugin)
-
-	serv.ProcCheat(plugin, req.Cheat)
-
-	stake := sgc7pbutils.BuildStake(req.Stake)
-	err := serv.game.CheckStake(stake)
+	res, err := serv.roundMgr.ResumeRound("", req.PlayerID, req.RoundID)
 	if err != nil {
-		goutils.Error("Serv.onPlay:CheckStake",
-			slog.Any("stake", stake),
+		goutils.Error("Serv.ResumeRound:ResumeRound",
 			goutils.Err(err))
 
 		return nil, err
 	}
 
-	results := []*sgc7game.PlayResult{}
-	gameData := serv.game.NewGameData(stake)
-	if gameData == nil {
-		goutils.Error("Serv.onPlay:NewGameData",
-			goutils.Err(sgc7game.ErrInvalidStake))
-
-		return nil, sgc7game.ErrInvalidStake
-	}
-
-	defer serv.game.DeleteGameData(gameData)
+	serv.LogReplyPlay("Serv.ResumeRound", res, zapcore.DebugLevel)
 
-	cmd := req.Command
-
-	serv.game.OnBet(plugin, cmd, req.ClientParams, ips, stake, results, gameData)
-
-	for {
-		if cmd == "" {
-			cmd = "SPIN"
-		}
-
-		pr, err := serv.game.Play(plugin, cmd, req.ClientParams, ips, stake, results, gameData)
-		if err != nil {
-			goutils.Error("Serv.onPlay:Play",
-				slog.Int("results", len(results)),
-				goutils.Err(err))
-
-			return nil, err
-		}
-
-		if pr == nil {
-			break
-		}
-
-		results = append(results, pr)
-		if pr.IsFinish {
-			break
-		}
+	return res, nil
+}
 
-		if pr.IsWait {
-			break
-		}
+// play - play with RoundStore if it is set
+func (serv *Serv) play(ctx context.Context, req *sgc7pb.RequestPlay) (*sgc7pb.ReplyPlay, error) {
+	if serv.grpcServ.IsDraining() && metrics.IsBetCommand(req.Command) {
+		goutils.Error("Serv.play",
+			goutils.Err(grpcutils.ErrServerDraining))
 
-		if len(pr.NextCmds) > 0 {
-			cmd = pr.NextCmds[0]
-		} else {
-			cmd = ""
-		}
+		return nil, grpcutils.ErrServerDraining
 	}
 
-	pr := &sgc7pb.ReplyPlay{
-		RandomNumbers: sgc7pbutils.BuildPBRngs(plugin.GetUsedRngs()),
+	if serv.roundMgr == nil {
+		return serv.onPlayWithMetrics(ctx, req)
 	}
 
-	ps, err := serv.service.BuildPBPlayerState(ips)
-	if err != nil {
-		goutils.Error("Serv.onPlay:BuildPlayerState",
-			goutils.Err(err))
-
-		return nil, err
-	}
+	return serv.roundMgr.Play("", req, func(req *sgc7pb.RequestPlay) (*sgc7pb.ReplyPlay, error) {
+		return serv.onPlayWithMetrics(ctx, req)
+	})
+}
 
-	pr.PlayerState = ps
+// onPlayWithMetrics - onPlay, and record it in metrics and RTP monitor
+func (serv *Serv) onPlayWithMetrics(ctx context.Context, req *sgc7pb.RequestPlay) (*sgc7pb.ReplyPlay, error) {
+	st := time.Now()
 
-	if len(results) > 0 {
-		AddPlayResult(serv.service, pr, results)
+	res, err := serv.onPlay(ctx, req)
 
-		lastr := results[len(results)-1]
+	serv.metrics.OnPlayPB("", req, res, err, time.Since(st))
 
-		pr.Finished = lastr.IsFinish
-		pr.NextCommands = lastr.NextCmds
-		pr.NextCommandParams = lastr.NextCmdParams
+	if err == nil {
+		serv.rtpMonitor.OnPlayPB("", req, res)
 	}
 
-	return pr, nil
+	return res, err
+}
+
+// ProcCheat - process cheat
+func (serv *Serv) ProcCheat(plugin sgc7plugin.IPlugin, cheat string) error {
+	return ProcCheat(plugin, cheat)
+}
+
+// Play - play game
+func (serv *Serv) onPlay(ctx context.Context, req *sgc7pb.RequestPlay) (*sgc7pb.ReplyPlay, error) {
+	return PlayGameWithContext(ctx, serv.service, serv.game, req)
 }
 
 // Play - play game
@@ -0,0 +1,87 @@
+package grpcserv
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zhs007/slotsgamecore7/cheatpolicy"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	"github.com/zhs007/slotsgamecore7/lowcode"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// testService - grpcserv.IService for lowcode game
+type testService struct {
+	*BasicService2
+}
+
+// BuildPBGameModParam - any -> *anypb.Any
+func (sv *testService) BuildPBGameModParam(gp any) (*anypb.Any, error) {
+	mygp, isok := gp.(*lowcode.GameParams)
+	if !isok {
+		return nil, sgc7game.ErrInvalidParam
+	}
+
+	return anypb.New(&mygp.GameParam)
+}
+
+// BuildPBGameModParamFromAny - *anypb.Any -> any
+func (sv *testService) BuildPBGameModParamFromAny(msg *anypb.Any) (any, error) {
+	mygp := &sgc7pb.GameParam{}
+
+	err := msg.UnmarshalTo(mygp)
+	if err != nil {
+		return nil, err
+	}
+
+	return mygp, nil
+}
+
+func Test_ServCheatPolicy(t *testing.T) {
+	game, err := lowcode.NewGame2("../unittestdata/testgame.json", func() sgc7plugin.IPlugin {
+		return sgc7plugin.NewFastPlugin()
+	}, lowcode.NewBasicRNG, lowcode.NewEmptyFeatureLevel)
+	assert.NoError(t, err)
+
+	serv, err := NewServ(&testService{NewBasicService2()}, game, "127.0.0.1:0", "test", false)
+	assert.NoError(t, err)
+	defer serv.Stop()
+
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 1234}})
+
+	req := &sgc7pb.RequestPlay{
+		Stake: &sgc7pb.Stake{
+			CoinBet:  1,
+			CashBet:  20,
+			Currency: "EUR",
+		},
+		Command: "SPIN",
+		Cheat:   "1,2,3",
+	}
+
+	// 默认不接受 cheat
+	_, err = serv.Play2(ctx, req)
+	assert.Equal(t, cheatpolicy.ErrCheatDisabled, err)
+
+	serv.SetCheatPolicy(&cheatpolicy.Policy{
+		IsEnabled:    true,
+		AllowCallers: []string{"127.0.0.1"},
+	})
+
+	_, err = serv.Play2(ctx, req)
+	assert.NoError(t, err)
+
+	_, err = serv.Play2(peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1234}}), req)
+	assert.Equal(t, cheatpolicy.ErrCallerNotAllowed, err)
+
+	req.Cheat = "bg-wins>0"
+	_, err = serv.Play2(ctx, req)
+	assert.Equal(t, cheatpolicy.ErrForceOutcomeNotAllowed, err)
+
+	t.Logf("Test_ServCheatPolicy OK")
+}
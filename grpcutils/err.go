@@ -0,0 +1,8 @@
+package grpcutils
+
+import "errors"
+
+var (
+	// ErrServerDraining - the server is shutting down, new rounds are rejected
+	ErrServerDraining = errors.New("server is draining")
+)
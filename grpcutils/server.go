@@ -0,0 +1,108 @@
+package grpcutils
+
+import (
+	"log/slog"
+	"net"
+	"sync/atomic"
+	"time"
+
+	goutils "github.com/zhs007/goutils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// Server - a grpc server with health service, optional reflection and graceful stop
+//
+//	health 里 "" 是整个服务的状态，其它的 service name 由具体的服务决定（比如 gamecollection 用 gameCode 表示每个游戏是否可用）。
+//	Stop 时先把所有状态设成 NOT_SERVING，IsDraining 返回 true（服务自己拒绝新的局），然后 GracefulStop，超时以后强制 Stop。
+type Server struct {
+	Opts       *ServOptions
+	GRPCServ   *grpc.Server
+	Health     *health.Server
+	lis        net.Listener
+	isDraining atomic.Bool
+}
+
+// NewServer - new a Server, the services should be registered in GRPCServ before Start
+func NewServer(bindaddr string, opts *ServOptions) (*Server, error) {
+	lis, err := net.Listen("tcp", bindaddr)
+	if err != nil {
+		goutils.Error("NewServer.Listen",
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	grpcServ := grpc.NewServer(opts.BuildServerOptions()...)
+
+	hs := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServ, hs)
+
+	if opts != nil && opts.UseReflection {
+		reflection.Register(grpcServ)
+	}
+
+	return &Server{
+		Opts:     opts,
+		GRPCServ: grpcServ,
+		Health:   hs,
+		lis:      lis,
+	}, nil
+}
+
+// GetAddr - get the listen address, it is useful when the port is 0
+func (s *Server) GetAddr() string {
+	return s.lis.Addr().String()
+}
+
+// SetServing - set the health status of a service, "" is the whole server
+func (s *Server) SetServing(service string, isServing bool) {
+	if isServing {
+		s.Health.SetServingStatus(service, healthpb.HealthCheckResponse_SERVING)
+	} else {
+		s.Health.SetServingStatus(service, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+}
+
+// IsDraining - is the server shutting down
+func (s *Server) IsDraining() bool {
+	return s.isDraining.Load()
+}
+
+// Start - serve, it returns after Stop
+func (s *Server) Start() error {
+	return s.GRPCServ.Serve(s.lis)
+}
+
+// Stop - graceful stop, the in-flight requests can finish in DrainTimeout
+func (s *Server) Stop() {
+	if s.isDraining.Swap(true) {
+		return
+	}
+
+	// 所有的 service 都变成 NOT_SERVING，负载均衡会把新请求发到别的地方
+	s.Health.Shutdown()
+
+	done := make(chan struct{})
+
+	go func() {
+		s.GRPCServ.GracefulStop()
+		close(done)
+	}()
+
+	timeout := s.Opts.GetDrainTimeout()
+
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		goutils.Warn("Server.Stop:GracefulStop timeout",
+			slog.Duration("timeout", timeout))
+
+		s.GRPCServ.Stop()
+	}
+
+	// 没有 Start 过时 grpc 不会关 listener
+	s.lis.Close()
+}
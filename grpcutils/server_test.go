@@ -0,0 +1,108 @@
+package grpcutils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+)
+
+func Test_ServOptions(t *testing.T) {
+	var nilOpts *ServOptions
+
+	assert.Equal(t, 0, len(nilOpts.BuildServerOptions()))
+	assert.Equal(t, DefaultDrainTimeout, nilOpts.GetDrainTimeout())
+
+	opts := &ServOptions{
+		UseOpenTelemetry: true,
+		MaxRecvMsgSize:   1024,
+		MaxSendMsgSize:   1024,
+		KeepaliveTime:    time.Minute,
+		KeepaliveMinTime: time.Second,
+		DrainTimeout:     time.Second,
+	}
+
+	assert.Equal(t, 5, len(opts.BuildServerOptions()))
+	assert.Equal(t, time.Second, opts.GetDrainTimeout())
+
+	t.Logf("Test_ServOptions OK")
+}
+
+func Test_Server(t *testing.T) {
+	serv, err := NewServer("127.0.0.1:0", &ServOptions{
+		UseReflection: true,
+		DrainTimeout:  time.Millisecond * 200,
+	})
+	assert.NoError(t, err)
+
+	serv.SetServing("", true)
+	serv.SetServing("game1", false)
+
+	go serv.Start()
+
+	conn, err := grpc.NewClient(serv.GetAddr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	defer conn.Close()
+
+	client := healthpb.NewHealthClient(conn)
+
+	res, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, res.Status)
+
+	res, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "game1"})
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, res.Status)
+
+	// reflection
+	rclient := reflectionpb.NewServerReflectionClient(conn)
+	stream, err := rclient.ServerReflectionInfo(context.Background())
+	assert.NoError(t, err)
+
+	err = stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	})
+	assert.NoError(t, err)
+
+	rres, err := stream.Recv()
+	assert.NoError(t, err)
+
+	services := []string{}
+	for _, v := range rres.GetListServicesResponse().Service {
+		services = append(services, v.Name)
+	}
+
+	assert.Contains(t, services, healthpb.Health_ServiceDesc.ServiceName)
+	stream.CloseSend()
+
+	// Watch 是一个不会结束的 stream，Stop 要等到 DrainTimeout 以后强制结束
+	watch, err := client.Watch(context.Background(), &healthpb.HealthCheckRequest{})
+	assert.NoError(t, err)
+
+	wres, err := watch.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, wres.Status)
+
+	assert.False(t, serv.IsDraining())
+
+	st := time.Now()
+	serv.Stop()
+
+	assert.True(t, serv.IsDraining())
+	assert.True(t, time.Since(st) >= time.Millisecond*200)
+	assert.True(t, time.Since(st) < time.Second*5)
+
+	wres, err = watch.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, wres.Status)
+
+	// 第二次 Stop 直接返回
+	serv.Stop()
+
+	t.Logf("Test_Server OK")
+}
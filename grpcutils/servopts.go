@@ -0,0 +1,72 @@
+package grpcutils
+
+import (
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+// DefaultDrainTimeout - the default deadline of graceful stop
+const DefaultDrainTimeout = 30 * time.Second
+
+// ServOptions - options shared by all grpc servers, the zero value is the grpc default
+type ServOptions struct {
+	UseOpenTelemetry    bool          `yaml:"useopentelemetry"`
+	UseReflection       bool          `yaml:"usereflection"`    // 注册 server reflection，方便 grpcurl 之类的工具
+	MaxRecvMsgSize      int           `yaml:"maxrecvmsgsize"`   // 为 0 时用 grpc 的默认值
+	MaxSendMsgSize      int           `yaml:"maxsendmsgsize"`   // 为 0 时用 grpc 的默认值
+	KeepaliveTime       time.Duration `yaml:"keepalivetime"`    // 连接空闲多久以后 ping 客户端，为 0 时用 grpc 的默认值
+	KeepaliveTimeout    time.Duration `yaml:"keepalivetimeout"` // ping 以后等多久没回应就断开
+	KeepaliveMinTime    time.Duration `yaml:"keepalivemintime"` // 客户端 ping 的最小间隔，为 0 时用 grpc 的默认值
+	PermitWithoutStream bool          `yaml:"permitwithoutstream"`
+	DrainTimeout        time.Duration `yaml:"draintimeout"` // Stop 时等进行中的请求的最长时间，为 0 时用 DefaultDrainTimeout
+}
+
+// GetDrainTimeout - get DrainTimeout, nil safe
+func (opts *ServOptions) GetDrainTimeout() time.Duration {
+	if opts == nil || opts.DrainTimeout <= 0 {
+		return DefaultDrainTimeout
+	}
+
+	return opts.DrainTimeout
+}
+
+// BuildServerOptions - build grpc.ServerOption, nil safe
+func (opts *ServOptions) BuildServerOptions() []grpc.ServerOption {
+	lst := []grpc.ServerOption{}
+
+	if opts == nil {
+		return lst
+	}
+
+	if opts.MaxRecvMsgSize > 0 {
+		lst = append(lst, grpc.MaxRecvMsgSize(opts.MaxRecvMsgSize))
+	}
+
+	if opts.MaxSendMsgSize > 0 {
+		lst = append(lst, grpc.MaxSendMsgSize(opts.MaxSendMsgSize))
+	}
+
+	if opts.KeepaliveTime > 0 || opts.KeepaliveTimeout > 0 {
+		lst = append(lst, grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    opts.KeepaliveTime,
+			Timeout: opts.KeepaliveTimeout,
+		}))
+	}
+
+	if opts.KeepaliveMinTime > 0 || opts.PermitWithoutStream {
+		lst = append(lst, grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             opts.KeepaliveMinTime,
+			PermitWithoutStream: opts.PermitWithoutStream,
+		}))
+	}
+
+	if opts.UseOpenTelemetry {
+		// otelgrpc v0.63.0 uses stats handlers rather than interceptors
+		lst = append(lst, grpc.StatsHandler(otelgrpc.NewServerHandler()))
+	}
+
+	return lst
+}
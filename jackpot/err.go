@@ -0,0 +1,16 @@
+package jackpot
+
+import "errors"
+
+var (
+	// ErrInvalidConfig - invalid config
+	ErrInvalidConfig = errors.New("invalid config")
+	// ErrInvalidContribution - invalid contribution
+	ErrInvalidContribution = errors.New("invalid contribution")
+	// ErrInvalidHit - invalid hit
+	ErrInvalidHit = errors.New("invalid hit")
+	// ErrInvalidTier - invalid tier
+	ErrInvalidTier = errors.New("invalid tier")
+	// ErrTransactionConflict - the same txID or hitID with the different data
+	ErrTransactionConflict = errors.New("transaction conflict")
+)
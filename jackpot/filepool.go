@@ -0,0 +1,139 @@
+package jackpot
+
+import (
+	"log/slog"
+	"os"
+	"path"
+
+	"github.com/bytedance/sonic"
+	"github.com/zhs007/goutils"
+)
+
+// FileJackpotPool - MemJackpotPool saved in a json file after every change, for development
+type FileJackpotPool struct {
+	*MemJackpotPool
+	fn string
+}
+
+// save - write a temporary file and rename it
+func (fp *FileJackpotPool) save(data *memPoolData) error {
+	buf, err := sonic.Marshal(data)
+	if err != nil {
+		goutils.Error("FileJackpotPool.save:Marshal",
+			goutils.Err(err))
+
+		return err
+	}
+
+	f, err := os.CreateTemp(path.Dir(fp.fn), path.Base(fp.fn)+".*.tmp")
+	if err != nil {
+		goutils.Error("FileJackpotPool.save:CreateTemp",
+			slog.String("fn", fp.fn),
+			goutils.Err(err))
+
+		return err
+	}
+
+	tmpfn := f.Name()
+
+	_, err = f.Write(buf)
+	if err == nil {
+		err = f.Sync()
+	}
+
+	f.Close()
+
+	if err == nil {
+		err = os.Rename(tmpfn, fp.fn)
+	}
+
+	if err != nil {
+		goutils.Error("FileJackpotPool.save:Write",
+			slog.String("fn", fp.fn),
+			goutils.Err(err))
+
+		os.Remove(tmpfn)
+
+		return err
+	}
+
+	return nil
+}
+
+// isSameTiers - the pool in the file must have the same tiers with the config
+func isSameTiers(cfg *Config, pool *Pool) bool {
+	if len(pool.Tiers) != len(cfg.Tiers) {
+		return false
+	}
+
+	for i, v := range cfg.Tiers {
+		if pool.Tiers[i] == nil || pool.Tiers[i].Name != v.Name {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NewFileJackpotPool - new a FileJackpotPool, load fn if it exists
+func NewFileJackpotPool(fn string, cfg *Config) (*FileJackpotPool, error) {
+	mp, err := NewMemJackpotPool(cfg)
+	if err != nil {
+		goutils.Error("NewFileJackpotPool:NewMemJackpotPool",
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	fp := &FileJackpotPool{
+		MemJackpotPool: mp,
+		fn:             fn,
+	}
+
+	buf, err := os.ReadFile(fn)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			goutils.Error("NewFileJackpotPool:ReadFile",
+				slog.String("fn", fn),
+				goutils.Err(err))
+
+			return nil, err
+		}
+	} else {
+		err = sonic.Unmarshal(buf, fp.data)
+		if err != nil {
+			goutils.Error("NewFileJackpotPool:Unmarshal",
+				slog.String("fn", fn),
+				goutils.Err(err))
+
+			return nil, err
+		}
+
+		if fp.data.Pools == nil {
+			fp.data.Pools = make(map[string]*Pool)
+		}
+
+		if fp.data.Contributions == nil {
+			fp.data.Contributions = make(map[string]*contributionRecord)
+		}
+
+		if fp.data.Hits == nil {
+			fp.data.Hits = make(map[string]*hitRecord)
+		}
+
+		for poolID, pool := range fp.data.Pools {
+			if !isSameTiers(cfg, pool) {
+				goutils.Error("NewFileJackpotPool:isSameTiers",
+					slog.String("fn", fn),
+					slog.String("poolID", poolID),
+					goutils.Err(ErrInvalidConfig))
+
+				return nil, ErrInvalidConfig
+			}
+		}
+	}
+
+	fp.onChanged = fp.save
+
+	return fp, nil
+}
@@ -0,0 +1,258 @@
+package jackpot
+
+// ContributionPrecision - the contribution rates are in 1/ContributionPrecision of the stake
+const ContributionPrecision = 10000
+
+// TierConfig - a tier of the jackpot, like mini, minor, major or grand
+type TierConfig struct {
+	Name            string          `yaml:"name" json:"name"`
+	Seed            int64           `yaml:"seed" json:"seed"`                       // the value after reset
+	Contribution    int64           `yaml:"contribution" json:"contribution"`       // the rate of the stake, in 1/ContributionPrecision
+	MapContribution map[int64]int64 `yaml:"mapContribution" json:"mapContribution"` // stake -> rate, overwrite Contribution
+	MustHitBy       int64           `yaml:"mustHitBy" json:"mustHitBy"`             // it must hit when the value reaches it, 0 is no ceiling
+}
+
+// getContribution - the rate for the stake
+func (cfg *TierConfig) getContribution(stake int64) int64 {
+	rate, isok := cfg.MapContribution[stake]
+	if isok {
+		return rate
+	}
+
+	return cfg.Contribution
+}
+
+func isValidRate(rate int64) bool {
+	return rate >= 0 && rate <= ContributionPrecision
+}
+
+func (cfg *TierConfig) isValid() bool {
+	if cfg.Name == "" || cfg.Seed < 0 || !isValidRate(cfg.Contribution) {
+		return false
+	}
+
+	for _, v := range cfg.MapContribution {
+		if !isValidRate(v) {
+			return false
+		}
+	}
+
+	return cfg.MustHitBy == 0 || cfg.MustHitBy > cfg.Seed
+}
+
+// Config - the config of the jackpot pools, all the pools have the same tiers
+type Config struct {
+	Tiers []*TierConfig `yaml:"tiers" json:"tiers"`
+}
+
+// GetTierIndex - get the index of the tier, return -1 if it is not found
+func (cfg *Config) GetTierIndex(name string) int {
+	for i, v := range cfg.Tiers {
+		if v.Name == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// isValid - the names are unique, and the total rate for any stake is not greater than 100%
+func (cfg *Config) isValid() bool {
+	if len(cfg.Tiers) == 0 {
+		return false
+	}
+
+	stakes := []int64{0}
+	for i, v := range cfg.Tiers {
+		if v == nil || !v.isValid() || cfg.GetTierIndex(v.Name) != i {
+			return false
+		}
+
+		for stake := range v.MapContribution {
+			stakes = append(stakes, stake)
+		}
+	}
+
+	for _, stake := range stakes {
+		total := int64(0)
+		for _, v := range cfg.Tiers {
+			total += v.getContribution(stake)
+		}
+
+		if total > ContributionPrecision {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Contribution - take the contribution from a stake, it's idempotent with TxID
+type Contribution struct {
+	TxID     string `json:"txID"`
+	PoolID   string `json:"poolID"`
+	PlayerID string `json:"playerID"`
+	Stake    int64  `json:"stake"`
+}
+
+func (c *Contribution) isValid() bool {
+	return c.TxID != "" && c.Stake >= 0
+}
+
+func (c *Contribution) isSame(c1 *Contribution) bool {
+	return c.PoolID == c1.PoolID && c.PlayerID == c1.PlayerID && c.Stake == c1.Stake
+}
+
+// Hit - hit a tier, it's idempotent with HitID
+type Hit struct {
+	HitID    string `json:"hitID"`
+	PoolID   string `json:"poolID"`
+	PlayerID string `json:"playerID"`
+	Tier     string `json:"tier"`
+}
+
+func (h *Hit) isValid() bool {
+	return h.HitID != ""
+}
+
+func (h *Hit) isSame(h1 *Hit) bool {
+	return h.PoolID == h1.PoolID && h.PlayerID == h1.PlayerID && h.Tier == h1.Tier
+}
+
+// Tier - the state of a tier,
+//
+//	TotalSeed + TotalContribution == TotalWin + Value + Overflow
+type Tier struct {
+	Name              string `json:"name"`
+	Value             int64  `json:"value"`
+	Remainder         int64  `json:"remainder"` // 还不到 1 的贡献，单位是 1/ContributionPrecision
+	Overflow          int64  `json:"overflow"`  // 到了 MustHitBy 以后的贡献，重置时加到 Value 里
+	IsMustHit         bool   `json:"isMustHit"` // 到了 MustHitBy，还没有被 hit
+	HitTimes          int64  `json:"hitTimes"`
+	TotalContribution int64  `json:"totalContribution"`
+	TotalSeed         int64  `json:"totalSeed"`
+	TotalWin          int64  `json:"totalWin"`
+}
+
+// addValue - the value can not be greater than MustHitBy, return true if it reaches MustHitBy now
+func (tier *Tier) addValue(cfg *TierConfig, val int64) bool {
+	tier.Value += val
+
+	if cfg.MustHitBy <= 0 || tier.Value < cfg.MustHitBy {
+		return false
+	}
+
+	tier.Overflow += tier.Value - cfg.MustHitBy
+	tier.Value = cfg.MustHitBy
+
+	if tier.IsMustHit {
+		return false
+	}
+
+	tier.IsMustHit = true
+
+	return true
+}
+
+// contribute - return true if it reaches MustHitBy now
+func (tier *Tier) contribute(cfg *TierConfig, stake int64) bool {
+	tier.Remainder += stake * cfg.getContribution(stake)
+
+	val := tier.Remainder / ContributionPrecision
+	tier.Remainder %= ContributionPrecision
+	tier.TotalContribution += val
+
+	return tier.addValue(cfg, val)
+}
+
+// reset - reset to seed, the overflow is added,
+//
+//	but the value is kept below MustHitBy, so the next contribution can reach MustHitBy
+func (tier *Tier) reset(cfg *TierConfig) {
+	tier.Value = cfg.Seed + tier.Overflow
+	tier.Overflow = 0
+	tier.IsMustHit = false
+	tier.TotalSeed += cfg.Seed
+
+	if cfg.MustHitBy > 0 && tier.Value >= cfg.MustHitBy {
+		tier.Overflow = tier.Value - cfg.MustHitBy + 1
+		tier.Value = cfg.MustHitBy - 1
+	}
+}
+
+// Pool - a jackpot pool, shared by all the players with the same poolID
+type Pool struct {
+	PoolID     string  `json:"poolID"`
+	TotalStake int64   `json:"totalStake"`
+	Tiers      []*Tier `json:"tiers"`
+}
+
+// Clone - clone
+func (pool *Pool) Clone() *Pool {
+	target := &Pool{
+		PoolID:     pool.PoolID,
+		TotalStake: pool.TotalStake,
+		Tiers:      make([]*Tier, len(pool.Tiers)),
+	}
+
+	for i, v := range pool.Tiers {
+		tier := *v
+		target.Tiers[i] = &tier
+	}
+
+	return target
+}
+
+// GetTier - get the tier, return nil if it is not found
+func (pool *Pool) GetTier(name string) *Tier {
+	for _, v := range pool.Tiers {
+		if v.Name == name {
+			return v
+		}
+	}
+
+	return nil
+}
+
+// GetContributionRTP - the rate of the stakes which go into the pool
+func (pool *Pool) GetContributionRTP() float64 {
+	if pool.TotalStake <= 0 {
+		return 0
+	}
+
+	total := int64(0)
+	for _, v := range pool.Tiers {
+		total += v.TotalContribution
+	}
+
+	return float64(total) / float64(pool.TotalStake)
+}
+
+func newPool(cfg *Config, poolID string) *Pool {
+	pool := &Pool{
+		PoolID: poolID,
+		Tiers:  make([]*Tier, len(cfg.Tiers)),
+	}
+
+	for i, v := range cfg.Tiers {
+		pool.Tiers[i] = &Tier{Name: v.Name}
+		pool.Tiers[i].reset(v)
+	}
+
+	return pool
+}
+
+// JackpotPool - the progressive jackpot pools, the contributions and hits are idempotent,
+//
+//	the implementations must be safe for concurrent use, and a tier can only be won once until it is reset
+type JackpotPool interface {
+	// GetConfig - get the config
+	GetConfig() *Config
+	// GetPool - get a copy of the pool, the new pool is seeded
+	GetPool(poolID string) (*Pool, error)
+	// Contribute - take the contribution from the stake,
+	//	return the tiers reached MustHitBy with this contribution, the caller must hit them
+	Contribute(c *Contribution) ([]string, error)
+	// Hit - hit a tier, return the win, and the tier is reset to seed
+	Hit(h *Hit) (int64, error)
+}
@@ -0,0 +1,343 @@
+package jackpot
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
+)
+
+func newTestConfig() *Config {
+	return &Config{
+		Tiers: []*TierConfig{
+			{Name: "mini", Seed: 10, Contribution: 100, MustHitBy: 20},
+			{Name: "grand", Seed: 1000, Contribution: 50, MapContribution: map[int64]int64{1000: 200}},
+		},
+	}
+}
+
+// checkPool - TotalSeed + TotalContribution == TotalWin + Value + Overflow
+func checkPool(t *testing.T, pool *Pool) {
+	for _, v := range pool.Tiers {
+		assert.Equal(t, v.TotalSeed+v.TotalContribution, v.TotalWin+v.Value+v.Overflow, v.Name)
+	}
+}
+
+func testJackpotPool(t *testing.T, jp JackpotPool) {
+	pool, err := jp.GetPool("p")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), pool.GetTier("mini").Value)
+	assert.Equal(t, int64(1000), pool.GetTier("grand").Value)
+
+	_, err = jp.Contribute(&Contribution{TxID: "", PoolID: "p", Stake: 100})
+	assert.Equal(t, ErrInvalidContribution, err)
+
+	_, err = jp.Contribute(&Contribution{TxID: "tx0", PoolID: "p", Stake: -100})
+	assert.Equal(t, ErrInvalidContribution, err)
+
+	mustHit, err := jp.Contribute(&Contribution{TxID: "tx1", PoolID: "p", PlayerID: "p1", Stake: 150})
+	assert.NoError(t, err)
+	assert.Empty(t, mustHit)
+
+	// idempotent
+	mustHit, err = jp.Contribute(&Contribution{TxID: "tx1", PoolID: "p", PlayerID: "p1", Stake: 150})
+	assert.NoError(t, err)
+	assert.Empty(t, mustHit)
+
+	_, err = jp.Contribute(&Contribution{TxID: "tx1", PoolID: "p", PlayerID: "p1", Stake: 10})
+	assert.Equal(t, ErrTransactionConflict, err)
+
+	// mini reaches MustHitBy, grand uses the rate of the stake 1000
+	mustHit, err = jp.Contribute(&Contribution{TxID: "tx2", PoolID: "p", PlayerID: "p2", Stake: 1000})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"mini"}, mustHit)
+
+	mustHit, err = jp.Contribute(&Contribution{TxID: "tx2", PoolID: "p", PlayerID: "p2", Stake: 1000})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"mini"}, mustHit)
+
+	// mini is waiting for the hit, the contribution goes into overflow
+	mustHit, err = jp.Contribute(&Contribution{TxID: "tx3", PoolID: "p", PlayerID: "p1", Stake: 100})
+	assert.NoError(t, err)
+	assert.Empty(t, mustHit)
+
+	pool, err = jp.GetPool("p")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(20), pool.GetTier("mini").Value)
+	assert.Equal(t, int64(2), pool.GetTier("mini").Overflow)
+	assert.True(t, pool.GetTier("mini").IsMustHit)
+	assert.Equal(t, int64(1021), pool.GetTier("grand").Value)
+	checkPool(t, pool)
+
+	_, err = jp.Hit(&Hit{HitID: "", PoolID: "p", Tier: "mini"})
+	assert.Equal(t, ErrInvalidHit, err)
+
+	_, err = jp.Hit(&Hit{HitID: "h0", PoolID: "p", Tier: "none"})
+	assert.Equal(t, ErrInvalidTier, err)
+
+	win, err := jp.Hit(&Hit{HitID: "h1", PoolID: "p", PlayerID: "p2", Tier: "mini"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(20), win)
+
+	win, err = jp.Hit(&Hit{HitID: "h1", PoolID: "p", PlayerID: "p2", Tier: "mini"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(20), win)
+
+	_, err = jp.Hit(&Hit{HitID: "h1", PoolID: "p", PlayerID: "p2", Tier: "grand"})
+	assert.Equal(t, ErrTransactionConflict, err)
+
+	win, err = jp.Hit(&Hit{HitID: "h2", PoolID: "p", PlayerID: "p1", Tier: "grand"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1021), win)
+
+	pool, err = jp.GetPool("p")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1250), pool.TotalStake)
+	assert.InDelta(t, float64(12+21)/1250, pool.GetContributionRTP(), 0.000001)
+
+	// the overflow is added after reset
+	mini := pool.GetTier("mini")
+	assert.Equal(t, int64(12), mini.Value)
+	assert.Equal(t, int64(0), mini.Overflow)
+	assert.False(t, mini.IsMustHit)
+	assert.Equal(t, int64(1), mini.HitTimes)
+	assert.Equal(t, int64(20), mini.TotalWin)
+
+	grand := pool.GetTier("grand")
+	assert.Equal(t, int64(1000), grand.Value)
+	assert.Equal(t, int64(2000), grand.TotalSeed)
+	checkPool(t, pool)
+
+	// the other pool
+	pool, err = jp.GetPool("q")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), pool.GetTier("mini").Value)
+	assert.Equal(t, int64(0), pool.TotalStake)
+}
+
+func Test_Config(t *testing.T) {
+	_, err := NewMemJackpotPool(nil)
+	assert.Equal(t, ErrInvalidConfig, err)
+
+	_, err = NewMemJackpotPool(&Config{})
+	assert.Equal(t, ErrInvalidConfig, err)
+
+	_, err = NewMemJackpotPool(&Config{Tiers: []*TierConfig{{Name: "mini", Seed: 10}, {Name: "mini", Seed: 10}}})
+	assert.Equal(t, ErrInvalidConfig, err)
+
+	_, err = NewMemJackpotPool(&Config{Tiers: []*TierConfig{{Name: "mini", Seed: 10, MustHitBy: 10}}})
+	assert.Equal(t, ErrInvalidConfig, err)
+
+	_, err = NewMemJackpotPool(&Config{Tiers: []*TierConfig{
+		{Name: "mini", Contribution: 5000},
+		{Name: "grand", Contribution: 1000, MapContribution: map[int64]int64{100: 6000}},
+	}})
+	assert.Equal(t, ErrInvalidConfig, err)
+
+	cfg := newTestConfig()
+	assert.Equal(t, 1, cfg.GetTierIndex("grand"))
+	assert.Equal(t, -1, cfg.GetTierIndex("none"))
+
+	_, err = NewMemJackpotPool(cfg)
+	assert.NoError(t, err)
+
+	t.Logf("Test_Config OK")
+}
+
+func Test_MemJackpotPool(t *testing.T) {
+	jp, err := NewMemJackpotPool(newTestConfig())
+	assert.NoError(t, err)
+
+	testJackpotPool(t, jp)
+
+	t.Logf("Test_MemJackpotPool OK")
+}
+
+func Test_MemJackpotPoolConcurrent(t *testing.T) {
+	jp, err := NewMemJackpotPool(newTestConfig())
+	assert.NoError(t, err)
+
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+
+	totalWin := int64(0)
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func(player int) {
+			defer wg.Done()
+
+			for j := 0; j < 200; j++ {
+				roundID := fmt.Sprintf("p%v-r%v", player, j)
+
+				mustHit, err := jp.Contribute(&Contribution{TxID: roundID, PoolID: "p", Stake: 100})
+				assert.NoError(t, err)
+
+				tiers := mustHit
+				if j%50 == 0 {
+					tiers = append(tiers, "grand")
+				}
+
+				for _, tier := range tiers {
+					win, err := jp.Hit(&Hit{HitID: roundID + "-" + tier, PoolID: "p", Tier: tier})
+					assert.NoError(t, err)
+
+					lock.Lock()
+					totalWin += win
+					lock.Unlock()
+				}
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	pool, err := jp.GetPool("p")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(8*200*100), pool.TotalStake)
+	checkPool(t, pool)
+
+	poolWin := int64(0)
+	for _, v := range pool.Tiers {
+		poolWin += v.TotalWin
+	}
+
+	assert.Equal(t, totalWin, poolWin)
+	assert.Equal(t, int64(8*4), pool.GetTier("grand").HitTimes)
+	assert.False(t, pool.GetTier("mini").IsMustHit)
+
+	t.Logf("Test_MemJackpotPoolConcurrent OK")
+}
+
+func Test_FileJackpotPool(t *testing.T) {
+	fn := "../unittestdata/jackpot.json"
+	os.Remove(fn)
+	defer os.Remove(fn)
+
+	jp, err := NewFileJackpotPool(fn, newTestConfig())
+	assert.NoError(t, err)
+
+	testJackpotPool(t, jp)
+
+	// reload
+	jp1, err := NewFileJackpotPool(fn, newTestConfig())
+	assert.NoError(t, err)
+
+	pool, err := jp1.GetPool("p")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), pool.GetTier("mini").Value)
+	assert.Equal(t, int64(1250), pool.TotalStake)
+
+	win, err := jp1.Hit(&Hit{HitID: "h1", PoolID: "p", PlayerID: "p2", Tier: "mini"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(20), win)
+
+	mustHit, err := jp1.Contribute(&Contribution{TxID: "tx2", PoolID: "p", PlayerID: "p2", Stake: 1000})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"mini"}, mustHit)
+
+	// the tiers are changed
+	_, err = NewFileJackpotPool(fn, &Config{Tiers: []*TierConfig{{Name: "major", Seed: 100}}})
+	assert.Equal(t, ErrInvalidConfig, err)
+
+	t.Logf("Test_FileJackpotPool OK")
+}
+
+func Test_ProcPlayResults(t *testing.T) {
+	jp, err := NewMemJackpotPool(newTestConfig())
+	assert.NoError(t, err)
+
+	newResults := func() []*sgc7game.PlayResult {
+		pr0 := sgc7game.NewPlayResult("bg", 0, 0, "bg")
+		pr1 := sgc7game.NewPlayResult("fg", 1, 0, "fg")
+		pr1.JackpotType = 2
+		pr1.JackpotCashWin = 999
+
+		return []*sgc7game.PlayResult{pr0, pr1}
+	}
+
+	// grand is hit by the game, and mini reaches MustHitBy
+	results := newResults()
+	win, err := ProcPlayResults(jp, "p", "p1", "r1", 1000, results)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1020+20), win)
+	assert.Equal(t, 0, results[0].JackpotType)
+	assert.Equal(t, int64(0), results[0].JackpotCashWin)
+	assert.Equal(t, 2, results[1].JackpotType)
+	assert.Equal(t, int64(1040), results[1].JackpotCashWin)
+
+	// retry
+	win, err = ProcPlayResults(jp, "p", "p1", "r1", 1000, newResults())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1040), win)
+
+	pool, err := jp.GetPool("p")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), pool.TotalStake)
+	assert.Equal(t, int64(1000), pool.GetTier("grand").Value)
+	assert.Equal(t, int64(10), pool.GetTier("mini").Value)
+	checkPool(t, pool)
+
+	pr := sgc7game.NewPlayResult("bg", 0, 0, "bg")
+	pr.JackpotType = 3
+	_, err = ProcPlayResults(jp, "p", "p1", "r2", 100, []*sgc7game.PlayResult{pr})
+	assert.Equal(t, ErrInvalidTier, err)
+
+	win, err = ProcPlayResults(jp, "p", "p1", "r3", 100, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), win)
+
+	t.Logf("Test_ProcPlayResults OK")
+}
+
+func Test_ProcPBResults(t *testing.T) {
+	jp, err := NewMemJackpotPool(newTestConfig())
+	assert.NoError(t, err)
+
+	// grand is hit by the game, and mini reaches MustHitBy
+	results := []*sgc7pb.GameResult{
+		{ClientData: &sgc7pb.PlayResult{}},
+		{ClientData: &sgc7pb.PlayResult{JackpotType: 2, JackpotCashWin: 999}},
+	}
+
+	win, err := ProcPBResults(jp, "p", "p1", "r1", 1000, true, 0, results)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1020+20), win)
+	assert.Equal(t, int32(0), results[0].ClientData.JackpotType)
+	assert.Equal(t, int32(2), results[1].ClientData.JackpotType)
+	assert.Equal(t, int64(1040), results[1].ClientData.JackpotCashWin)
+
+	// the next step of the round, no contribution
+	results = []*sgc7pb.GameResult{{}, {ClientData: &sgc7pb.PlayResult{JackpotType: 1}}}
+
+	win, err = ProcPBResults(jp, "p", "p1", "r1", 1000, false, 2, results)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), win)
+	assert.Equal(t, int64(10), results[1].ClientData.JackpotCashWin)
+
+	pool, err := jp.GetPool("p")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), pool.TotalStake)
+	checkPool(t, pool)
+
+	_, err = ProcPBResults(jp, "p", "p1", "r2", 100, true, 0, []*sgc7pb.GameResult{{ClientData: &sgc7pb.PlayResult{JackpotType: 3}}})
+	assert.Equal(t, ErrInvalidTier, err)
+
+	// mini reaches MustHitBy, the results have no ClientData
+	results = []*sgc7pb.GameResult{{}, {}}
+
+	win, err = ProcPBResults(jp, "q", "p1", "r3", 1000, true, 0, results)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(20), win)
+	assert.Nil(t, results[0].ClientData)
+	assert.NotNil(t, results[1].ClientData)
+	assert.Equal(t, int32(1), results[1].ClientData.JackpotType)
+	assert.Equal(t, int64(20), results[1].ClientData.JackpotCashWin)
+
+	t.Logf("Test_ProcPBResults OK")
+}
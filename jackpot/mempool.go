@@ -0,0 +1,242 @@
+package jackpot
+
+import (
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/zhs007/goutils"
+)
+
+// contributionRecord - a contribution in MemJackpotPool
+type contributionRecord struct {
+	Contribution *Contribution `json:"contribution"`
+	MustHit      []string      `json:"mustHit"`
+}
+
+// hitRecord - a hit in MemJackpotPool
+type hitRecord struct {
+	Hit *Hit  `json:"hit"`
+	Win int64 `json:"win"`
+}
+
+// memPoolData - the data of MemJackpotPool, FileJackpotPool saves it
+type memPoolData struct {
+	Pools         map[string]*Pool               `json:"pools"`
+	Contributions map[string]*contributionRecord `json:"contributions"`
+	Hits          map[string]*hitRecord          `json:"hits"`
+}
+
+// MemJackpotPool - JackpotPool in memory, for development
+type MemJackpotPool struct {
+	lock      sync.Mutex
+	cfg       *Config
+	data      *memPoolData
+	onChanged func(data *memPoolData) error // 在锁里调用
+}
+
+// getPool - the new pool is seeded, isNew is true if it is a new pool
+func (mp *MemJackpotPool) getPool(poolID string) (pool *Pool, isNew bool) {
+	pool, isok := mp.data.Pools[poolID]
+	if isok {
+		return pool, false
+	}
+
+	pool = newPool(mp.cfg, poolID)
+	mp.data.Pools[poolID] = pool
+
+	return pool, true
+}
+
+// commit - 改完以后调用，失败时恢复 pool，并删掉这次的记录
+func (mp *MemJackpotPool) commit(poolID string, oldPool *Pool, onFail func()) error {
+	if mp.onChanged == nil {
+		return nil
+	}
+
+	err := mp.onChanged(mp.data)
+	if err != nil {
+		if oldPool != nil {
+			mp.data.Pools[poolID] = oldPool
+		} else {
+			delete(mp.data.Pools, poolID)
+		}
+
+		if onFail != nil {
+			onFail()
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// GetConfig - get the config
+func (mp *MemJackpotPool) GetConfig() *Config {
+	return mp.cfg
+}
+
+// GetPool - get a copy of the pool, the new pool is seeded
+func (mp *MemJackpotPool) GetPool(poolID string) (*Pool, error) {
+	mp.lock.Lock()
+	defer mp.lock.Unlock()
+
+	pool, isNew := mp.getPool(poolID)
+	if isNew {
+		err := mp.commit(poolID, nil, nil)
+		if err != nil {
+			goutils.Error("MemJackpotPool.GetPool:commit",
+				slog.String("poolID", poolID),
+				goutils.Err(err))
+
+			return nil, err
+		}
+	}
+
+	return pool.Clone(), nil
+}
+
+// Contribute - take the contribution from the stake, return the tiers reached MustHitBy with this contribution
+func (mp *MemJackpotPool) Contribute(c *Contribution) ([]string, error) {
+	if !c.isValid() {
+		goutils.Error("MemJackpotPool.Contribute",
+			slog.Any("contribution", c),
+			goutils.Err(ErrInvalidContribution))
+
+		return nil, ErrInvalidContribution
+	}
+
+	mp.lock.Lock()
+	defer mp.lock.Unlock()
+
+	record, isok := mp.data.Contributions[c.TxID]
+	if isok {
+		if !record.Contribution.isSame(c) {
+			goutils.Error("MemJackpotPool.Contribute",
+				slog.Any("contribution", c),
+				goutils.Err(ErrTransactionConflict))
+
+			return nil, ErrTransactionConflict
+		}
+
+		return slices.Clone(record.MustHit), nil
+	}
+
+	oldPool, isok := mp.data.Pools[c.PoolID]
+	if isok {
+		oldPool = oldPool.Clone()
+	}
+
+	pool, _ := mp.getPool(c.PoolID)
+
+	var mustHit []string
+
+	pool.TotalStake += c.Stake
+
+	for i, v := range pool.Tiers {
+		if v.contribute(mp.cfg.Tiers[i], c.Stake) {
+			mustHit = append(mustHit, v.Name)
+		}
+	}
+
+	mp.data.Contributions[c.TxID] = &contributionRecord{Contribution: c, MustHit: mustHit}
+
+	err := mp.commit(c.PoolID, oldPool, func() {
+		delete(mp.data.Contributions, c.TxID)
+	})
+	if err != nil {
+		goutils.Error("MemJackpotPool.Contribute:commit",
+			slog.Any("contribution", c),
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	return slices.Clone(mustHit), nil
+}
+
+// Hit - hit a tier, return the win, and the tier is reset to seed
+func (mp *MemJackpotPool) Hit(h *Hit) (int64, error) {
+	if !h.isValid() {
+		goutils.Error("MemJackpotPool.Hit",
+			slog.Any("hit", h),
+			goutils.Err(ErrInvalidHit))
+
+		return 0, ErrInvalidHit
+	}
+
+	ti := mp.cfg.GetTierIndex(h.Tier)
+	if ti < 0 {
+		goutils.Error("MemJackpotPool.Hit",
+			slog.Any("hit", h),
+			goutils.Err(ErrInvalidTier))
+
+		return 0, ErrInvalidTier
+	}
+
+	mp.lock.Lock()
+	defer mp.lock.Unlock()
+
+	record, isok := mp.data.Hits[h.HitID]
+	if isok {
+		if !record.Hit.isSame(h) {
+			goutils.Error("MemJackpotPool.Hit",
+				slog.Any("hit", h),
+				goutils.Err(ErrTransactionConflict))
+
+			return 0, ErrTransactionConflict
+		}
+
+		return record.Win, nil
+	}
+
+	oldPool, isok := mp.data.Pools[h.PoolID]
+	if isok {
+		oldPool = oldPool.Clone()
+	}
+
+	pool, _ := mp.getPool(h.PoolID)
+	tier := pool.Tiers[ti]
+
+	win := tier.Value
+
+	tier.HitTimes++
+	tier.TotalWin += win
+	tier.reset(mp.cfg.Tiers[ti])
+
+	mp.data.Hits[h.HitID] = &hitRecord{Hit: h, Win: win}
+
+	err := mp.commit(h.PoolID, oldPool, func() {
+		delete(mp.data.Hits, h.HitID)
+	})
+	if err != nil {
+		goutils.Error("MemJackpotPool.Hit:commit",
+			slog.Any("hit", h),
+			goutils.Err(err))
+
+		return 0, err
+	}
+
+	return win, nil
+}
+
+// NewMemJackpotPool - new a MemJackpotPool
+func NewMemJackpotPool(cfg *Config) (*MemJackpotPool, error) {
+	if cfg == nil || !cfg.isValid() {
+		goutils.Error("NewMemJackpotPool",
+			slog.Any("cfg", cfg),
+			goutils.Err(ErrInvalidConfig))
+
+		return nil, ErrInvalidConfig
+	}
+
+	return &MemJackpotPool{
+		cfg: cfg,
+		data: &memPoolData{
+			Pools:         make(map[string]*Pool),
+			Contributions: make(map[string]*contributionRecord),
+			Hits:          make(map[string]*hitRecord),
+		},
+	}, nil
+}
@@ -0,0 +1,185 @@
+package jackpot
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/zhs007/goutils"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
+)
+
+// resultJackpot - JackpotType and the win from the pool of a result
+type resultJackpot struct {
+	jackpotType int
+	win         int64
+	isHit       bool
+}
+
+// procResults - contribute when isContribute is true, and hit the tiers in lst,
+//
+//	offset is the index of lst[0] in the round, it's used in HitID
+func procResults(jp JackpotPool, poolID string, playerID string, roundID string, stake int64, isContribute bool, offset int, lst []*resultJackpot) (int64, error) {
+	cfg := jp.GetConfig()
+
+	var mustHit []string
+
+	if isContribute {
+		ret, err := jp.Contribute(&Contribution{
+			TxID:     roundID + "-jc",
+			PoolID:   poolID,
+			PlayerID: playerID,
+			Stake:    stake,
+		})
+		if err != nil {
+			goutils.Error("procResults:Contribute",
+				slog.String("roundID", roundID),
+				goutils.Err(err))
+
+			return 0, err
+		}
+
+		mustHit = ret
+	}
+
+	totalWin := int64(0)
+
+	hit := func(rj *resultJackpot, ti int, hitID string) error {
+		win, err := jp.Hit(&Hit{
+			HitID:    hitID,
+			PoolID:   poolID,
+			PlayerID: playerID,
+			Tier:     cfg.Tiers[ti].Name,
+		})
+		if err != nil {
+			goutils.Error("procResults:Hit",
+				slog.String("hitID", hitID),
+				goutils.Err(err))
+
+			return err
+		}
+
+		if rj.jackpotType <= 0 {
+			rj.jackpotType = ti + 1
+		}
+
+		rj.win += win
+		rj.isHit = true
+		totalWin += win
+
+		return nil
+	}
+
+	for i, rj := range lst {
+		if rj.jackpotType <= 0 {
+			continue
+		}
+
+		if rj.jackpotType > len(cfg.Tiers) {
+			goutils.Error("procResults",
+				slog.Int("jackpotType", rj.jackpotType),
+				goutils.Err(ErrInvalidTier))
+
+			return 0, ErrInvalidTier
+		}
+
+		err := hit(rj, rj.jackpotType-1, fmt.Sprintf("%v-jh%v", roundID, offset+i))
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if len(mustHit) > 0 {
+		if len(lst) == 0 {
+			lst = append(lst, &resultJackpot{})
+		}
+
+		lastrj := lst[len(lst)-1]
+		for _, v := range mustHit {
+			err := hit(lastrj, cfg.GetTierIndex(v), fmt.Sprintf("%v-jm-%v", roundID, v))
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	return totalWin, nil
+}
+
+// ProcPlayResults - take the contribution from the stake, and pay the tiers hit in the results,
+//
+//	JackpotType in PlayResult is the index of the tier + 1, the win of the pool is set to JackpotCashWin,
+//	the tiers reached MustHitBy are paid in the last result.
+//	The TxID and the HitIDs are built with roundID, so it's safe to retry the same round with the results from the game,
+//	the results are modified here, so do not retry with them.
+//	It returns the total jackpot win, the caller should credit it.
+func ProcPlayResults(jp JackpotPool, poolID string, playerID string, roundID string, stake int64, results []*sgc7game.PlayResult) (int64, error) {
+	if len(results) == 0 {
+		return 0, nil
+	}
+
+	lst := make([]*resultJackpot, len(results))
+	for i, pr := range results {
+		lst[i] = &resultJackpot{jackpotType: pr.JackpotType}
+	}
+
+	win, err := procResults(jp, poolID, playerID, roundID, stake, true, 0, lst)
+	if err != nil {
+		goutils.Error("ProcPlayResults:procResults",
+			slog.String("roundID", roundID),
+			goutils.Err(err))
+
+		return 0, err
+	}
+
+	for i, pr := range results {
+		if lst[i].isHit {
+			// 这里的 JackpotCashWin 是按配置算的，换成奖池里的
+			pr.JackpotType = lst[i].jackpotType
+			pr.JackpotCashWin = lst[i].win
+		}
+	}
+
+	return win, nil
+}
+
+// ProcPBResults - like ProcPlayResults, for the results of a step in a round,
+//
+//	the contribution is taken only in the first step (isContribute is true),
+//	offset is the number of results before this step, so the HitIDs are unique in the round.
+//	The results without ClientData can't hit the tiers, the tiers reached MustHitBy are paid in the last result,
+//	ClientData is added to it if it has none, so the results should not be empty.
+func ProcPBResults(jp JackpotPool, poolID string, playerID string, roundID string, stake int64, isContribute bool, offset int,
+	results []*sgc7pb.GameResult) (int64, error) {
+
+	lst := make([]*resultJackpot, len(results))
+	for i, r := range results {
+		lst[i] = &resultJackpot{}
+
+		if r.ClientData != nil {
+			lst[i].jackpotType = int(r.ClientData.JackpotType)
+		}
+	}
+
+	win, err := procResults(jp, poolID, playerID, roundID, stake, isContribute, offset, lst)
+	if err != nil {
+		goutils.Error("ProcPBResults:procResults",
+			slog.String("roundID", roundID),
+			goutils.Err(err))
+
+		return 0, err
+	}
+
+	for i, r := range results {
+		if lst[i].isHit {
+			if r.ClientData == nil {
+				r.ClientData = &sgc7pb.PlayResult{}
+			}
+
+			r.ClientData.JackpotType = int32(lst[i].jackpotType)
+			r.ClientData.JackpotCashWin = lst[i].win
+		}
+	}
+
+	return win, nil
+}
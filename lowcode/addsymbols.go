@@ -1,7 +1,6 @@
 package lowcode
 
 import (
-	"context"
 	"log/slog"
 	"os"
 	"strings"
@@ -252,7 +251,7 @@ func (addSymbols *AddSymbols) onIncUntilTriggeredNormal(gameProp *GameProperty,
 	isTrigger := false
 
 	for i := 0; i < len(pos)/2; i++ {
-		cr, err := plugin.Random(context.Background(), len(pos)/2)
+		cr, err := plugin.Random(sgc7plugin.GetContext(plugin), len(pos)/2)
 		if err != nil {
 			goutils.Error("AddSymbols.onIncUntilTriggeredNormal:Random",
 				goutils.Err(err))
@@ -313,7 +312,7 @@ func (addSymbols *AddSymbols) onNormal(gameProp *GameProperty, curpr *sgc7game.P
 	ngs := gs.CloneEx(gameProp.PoolScene)
 
 	for i := 0; i < num; i++ {
-		cr, err := plugin.Random(context.Background(), len(pos)/2)
+		cr, err := plugin.Random(sgc7plugin.GetContext(plugin), len(pos)/2)
 		if err != nil {
 			goutils.Error("AddSymbols.onNormal:Random",
 				goutils.Err(err))
@@ -376,7 +375,7 @@ func (addSymbols *AddSymbols) onOthers(gameProp *GameProperty, curpr *sgc7game.P
 
 	if len(xarr) <= num {
 		for x := range xarr {
-			cy, err := plugin.Random(context.Background(), height)
+			cy, err := plugin.Random(sgc7plugin.GetContext(plugin), height)
 			if err != nil {
 				goutils.Error("AddSymbols.onOthers:Random",
 					goutils.Err(err))
@@ -389,7 +388,7 @@ func (addSymbols *AddSymbols) onOthers(gameProp *GameProperty, curpr *sgc7game.P
 		}
 	} else {
 		for i := 0; i < num; i++ {
-			cxi, err := plugin.Random(context.Background(), len(xarr))
+			cxi, err := plugin.Random(sgc7plugin.GetContext(plugin), len(xarr))
 			if err != nil {
 				goutils.Error("AddSymbols.onOthers:Random",
 					goutils.Err(err))
@@ -397,7 +396,7 @@ func (addSymbols *AddSymbols) onOthers(gameProp *GameProperty, curpr *sgc7game.P
 				return "", err
 			}
 
-			cy, err := plugin.Random(context.Background(), height)
+			cy, err := plugin.Random(sgc7plugin.GetContext(plugin), height)
 			if err != nil {
 				goutils.Error("AddSymbols.onOthers:Random",
 					goutils.Err(err))
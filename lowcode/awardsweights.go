@@ -1,8 +1,6 @@
 package lowcode
 
 import (
-	"context"
-
 	sgc7game "github.com/zhs007/slotsgamecore7/game"
 	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
 )
@@ -34,7 +32,7 @@ func (aw *AwardsWeights) RandVal(plugin sgc7plugin.IPlugin) (*AwardsNode, error)
 		return aw.Nodes[0], nil
 	}
 
-	cr, err := plugin.Random(context.Background(), aw.MaxWeight)
+	cr, err := plugin.Random(sgc7plugin.GetContext(plugin), aw.MaxWeight)
 	if err != nil {
 		return nil, err
 	}
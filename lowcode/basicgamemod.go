@@ -1,15 +1,22 @@
 package lowcode
 
 import (
+	"context"
 	"log/slog"
 
 	"github.com/zhs007/goutils"
 	sgc7game "github.com/zhs007/slotsgamecore7/game"
 	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
 	"github.com/zhs007/slotsgamecore7/stats2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/protobuf/types/known/anypb"
 )
 
+// tracerName - 每个 component 一个 span，每次 play 时再取 tracer，这样后面 SetTracerProvider 也能生效
+const tracerName = "github.com/zhs007/slotsgamecore7/lowcode"
+
 // BasicGameMod - basic gamemod
 type BasicGameMod struct {
 	*sgc7game.BasicGameMod
@@ -43,6 +50,20 @@ func (bgm *BasicGameMod) newPlayResult(prs []*sgc7game.PlayResult, stake *sgc7ga
 func (bgm *BasicGameMod) OnPlay(game sgc7game.IGame, plugin sgc7plugin.IPlugin, cmd string, param string,
 	ps sgc7game.IPlayerState, stake *sgc7game.Stake, prs []*sgc7game.PlayResult, gameData any) (*sgc7game.PlayResult, error) {
 
+	return bgm.onPlay(context.Background(), false, game, plugin, cmd, param, ps, stake, prs, gameData)
+}
+
+// OnPlayWithContext - on play, ctx is checked before every component, and every component has a trace span
+func (bgm *BasicGameMod) OnPlayWithContext(ctx context.Context, game sgc7game.IGame, plugin sgc7plugin.IPlugin, cmd string, param string,
+	ps sgc7game.IPlayerState, stake *sgc7game.Stake, prs []*sgc7game.PlayResult, gameData any) (*sgc7game.PlayResult, error) {
+
+	return bgm.onPlay(ctx, true, game, plugin, cmd, param, ps, stake, prs, gameData)
+}
+
+// onPlay - isWithContext 为 false 时不检查 ctx，也没有 trace span，和原来的 OnPlay 一样（模拟时用这个）
+func (bgm *BasicGameMod) onPlay(ctx context.Context, isWithContext bool, game sgc7game.IGame, plugin sgc7plugin.IPlugin, cmd string, param string,
+	ps sgc7game.IPlayerState, stake *sgc7game.Stake, prs []*sgc7game.PlayResult, gameData any) (*sgc7game.PlayResult, error) {
+
 	gameProp, isok := gameData.(*GameProperty)
 	if !isok {
 		goutils.Error("BasicGameMod.OnPlay",
@@ -130,7 +151,23 @@ func (bgm *BasicGameMod) OnPlay(game sgc7game.IGame, plugin sgc7plugin.IPlugin,
 		}
 	}
 
+	var tracer trace.Tracer
+	if isWithContext {
+		tracer = otel.Tracer(tracerName)
+	}
+
 	for {
+		if isWithContext {
+			err := ctx.Err()
+			if err != nil {
+				goutils.Error("BasicGameMod.OnPlay:ctx.Err",
+					slog.String("component", curComponent.GetName()),
+					goutils.Err(err))
+
+				return nil, err
+			}
+		}
+
 		isComponentDoNothing := false
 		isSetMode, set, currng, newComponent := gameProp.rng.GetCurRNG(curBetMode, gameProp, curComponent, gameProp.callStack.GetCurComponentData(gameProp, curComponent), gameProp.featureLevel)
 		if newComponent != "" {
@@ -150,6 +187,14 @@ func (bgm *BasicGameMod) OnPlay(game sgc7game.IGame, plugin sgc7plugin.IPlugin,
 
 		nextComponentName := ""
 		var err error
+		var span trace.Span
+
+		if isWithContext {
+			var curctx context.Context
+			curctx, span = tracer.Start(ctx, curComponent.GetName())
+
+			currng = sgc7plugin.WithContext(currng, curctx)
+		}
 
 		if isSetMode {
 			nextComponentName, err = curComponent.OnPlayGameWithSet(gameProp, pr, gp, currng, cmd, param, ps, stake, prs, cd, set)
@@ -157,6 +202,15 @@ func (bgm *BasicGameMod) OnPlay(game sgc7game.IGame, plugin sgc7plugin.IPlugin,
 			nextComponentName, err = curComponent.OnPlayGame(gameProp, pr, gp, currng, cmd, param, ps, stake, prs, cd)
 		}
 
+		if span != nil {
+			if err != nil && err != ErrComponentDoNothing {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			}
+
+			span.End()
+		}
+
 		if err != nil {
 			if err != ErrComponentDoNothing {
 				goutils.Error("BasicGameMod.OnPlay:OnPlayGame",
@@ -286,13 +340,16 @@ func (bgm *BasicGameMod) OnPlay(game sgc7game.IGame, plugin sgc7plugin.IPlugin,
 
 	if gAllowStats2 && pr.IsFinish {
 		totalwins := int64(pr.CoinWin)
+		jackpotwins := int64(pr.JackpotCoinWin)
 
 		for _, cpr := range prs {
 			totalwins += int64(cpr.CoinWin)
+			jackpotwins += int64(cpr.JackpotCoinWin)
 		}
 
 		rngs := sgc7plugin.GetRngs(plugin)
 		gameProp.stats2Cache.ProcStatsOnEnding(totalwins, rngs)
+		gameProp.stats2Cache.ProcStatsJackpotOnEnding(jackpotwins)
 
 		components.Stats2.PushCache(gameProp.stats2Cache)
 
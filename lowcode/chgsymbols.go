@@ -1,7 +1,6 @@
 package lowcode
 
 import (
-	"context"
 	"log/slog"
 	"os"
 	"strings"
@@ -325,7 +324,7 @@ func (chgSymbols *ChgSymbols) procReels(gameProp *GameProperty, cd *BasicCompone
 				if len(arry) == 1 {
 					ngs.Arr[x][arry[0]] = cursc
 				} else {
-					arryi, err := plugin.Random(context.Background(), len(arry))
+					arryi, err := plugin.Random(sgc7plugin.GetContext(plugin), len(arry))
 					if err != nil {
 						goutils.Error("ChgSymbols.procReels:Random",
 							goutils.Err(err))
@@ -579,7 +578,7 @@ func (chgSymbols *ChgSymbols) procRandomWithNoTrigger(gameProp *GameProperty, cd
 		}
 
 		if len(posx) > 1 {
-			pi1, err := plugin.Random(context.Background(), len(posx))
+			pi1, err := plugin.Random(sgc7plugin.GetContext(plugin), len(posx))
 			if err != nil {
 				goutils.Error("ChgSymbols.procRandomWithNoTrigger:roll pos",
 					goutils.Err(err))
@@ -1,7 +1,6 @@
 package lowcode
 
 import (
-	"context"
 	"log/slog"
 	"os"
 	"slices"
@@ -972,7 +971,7 @@ func (chgSymbols2 *ChgSymbols2) procSymbolsWithPos(gameProp *GameProperty, plugi
 	ngs := gs.CloneEx(gameProp.PoolScene)
 
 	for _, v := range symbolCodes {
-		cr, err := plugin.Random(context.Background(), len(pos)/2)
+		cr, err := plugin.Random(sgc7plugin.GetContext(plugin), len(pos)/2)
 		if err != nil {
 			goutils.Error("ChgSymbols2.procSymbolsWithPos:Random",
 				goutils.Err(err))
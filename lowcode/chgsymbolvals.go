@@ -1,7 +1,6 @@
 package lowcode
 
 import (
-	"context"
 	"log/slog"
 	"os"
 	"strings"
@@ -225,7 +224,7 @@ func (chgSymbolVals *ChgSymbolVals) rebuildPos(pos []int, plugin sgc7plugin.IPlu
 	npos := []int{}
 
 	for i := 0; i < chgSymbolVals.Config.MaxNumber; i++ {
-		cr, err := plugin.Random(context.Background(), len(pos)/2)
+		cr, err := plugin.Random(sgc7plugin.GetContext(plugin), len(pos)/2)
 		if err != nil {
 			goutils.Error("ChgSymbolVals.rebuildPos:Random",
 				goutils.Err(err))
@@ -1,7 +1,6 @@
 package lowcode
 
 import (
-	"context"
 	"log/slog"
 	"os"
 	"slices"
@@ -1078,7 +1077,7 @@ func (cpt *CollectorPayTrigger) procSwitcher(gameProp *GameProperty, plugin sgc7
 	}
 
 	if posd.Len() > 0 {
-		ci, err := plugin.Random(context.Background(), posd.Len())
+		ci, err := plugin.Random(sgc7plugin.GetContext(plugin), posd.Len())
 		if err != nil {
 			goutils.Error("CollectorPayTrigger.procSwitcher:Random",
 				slog.Int("posdLen", posd.Len()),
@@ -1117,7 +1116,7 @@ func (cpt *CollectorPayTrigger) procSwitcher(gameProp *GameProperty, plugin sgc7
 	}
 
 	if posd.Len() > 0 {
-		ci, err := plugin.Random(context.Background(), posd.Len())
+		ci, err := plugin.Random(sgc7plugin.GetContext(plugin), posd.Len())
 		if err != nil {
 			goutils.Error("CollectorPayTrigger.procSwitcher:Random",
 				slog.Int("posdLen", posd.Len()),
@@ -71,6 +71,10 @@ const (
 	CVX                     string = "x"                     // x
 	CVY                     string = "y"                     // y
 	CVOutputString          string = "outputstring"          // outputstring
+	CVWays                  string = "ways"                  // ways 的数量，空的格子不算
+	CVNewNumber             string = "newnumber"             // 这一步新增的数量
+	CVTotalMulti            string = "totalmulti"            // 所有倍数的和
+	CVLevel                 string = "level"                 // 当前等级
 )
 
 const (
@@ -146,6 +146,14 @@ func NewComponentMgr() *ComponentMgr {
 	mgr.Reg(GenTropiCoolSPSymbolsTypeName, NewGenTropiCoolSPSymbols)
 	mgr.Reg(TropiCoolSPBonusTypeName, NewTropiCoolSPBonus)
 	mgr.Reg(CPCoreTypeName, NewCPCore)
+	mgr.Reg(PlayerPickTypeName, NewPlayerPick)
+	mgr.Reg(GambleTypeName, NewGamble)
+	mgr.Reg(DynamicHeightReelsTypeName, NewDynamicHeightReels)
+	mgr.Reg(StickySymbolsTypeName, NewStickySymbols)
+	mgr.Reg(TrailBoardTypeName, NewTrailBoard)
+	mgr.Reg(WheelBonusTypeName, NewWheelBonus)
+	mgr.Reg(SymbolUpgradeTypeName, NewSymbolUpgrade)
+	mgr.Reg(PositionMultiplierGridTypeName, NewPositionMultiplierGrid)
 
 	return mgr
 }
@@ -0,0 +1,107 @@
+package lowcode
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func Test_SpinWithContext(t *testing.T) {
+	game, err := NewGame2("../unittestdata/testgame.json", func() sgc7plugin.IPlugin {
+		return sgc7plugin.NewFastPlugin()
+	}, NewBasicRNG, NewEmptyFeatureLevel)
+	assert.NoError(t, err)
+
+	sr := tracetest.NewSpanRecorder()
+	oldtp := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	defer otel.SetTracerProvider(oldtp)
+
+	stake := &sgc7game.Stake{CoinBet: 1, CashBet: 20, Currency: "EUR"}
+	plugin := game.NewPlugin()
+	defer game.FreePlugin(plugin)
+
+	gameData := game.NewGameData(stake)
+	defer game.DeleteGameData(gameData)
+
+	ctx, span := otel.Tracer("test").Start(context.Background(), "spin")
+
+	rets, err := SpinWithContext(ctx, game, game.Initialize(), plugin, stake, "SPIN", "", "", false, gameData)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, rets)
+
+	span.End()
+
+	spans := sr.Ended()
+	assert.True(t, len(spans) > 1)
+
+	for _, v := range spans[:len(spans)-1] {
+		assert.Equal(t, span.SpanContext().TraceID(), v.SpanContext().TraceID())
+		assert.NotNil(t, game.Pool.mapComponents[20].MapComponents[v.Name()])
+	}
+
+	cctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = SpinWithContext(cctx, game, game.Initialize(), plugin, stake, "SPIN", "", "", false, gameData)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	t.Logf("Test_SpinWithContext OK")
+}
+
+// ctxPlugin - record the spans of the ctx passed to Random
+type ctxPlugin struct {
+	sgc7plugin.IPlugin
+	spans []trace.SpanContext
+}
+
+func (plugin *ctxPlugin) Random(ctx context.Context, r int) (int, error) {
+	plugin.spans = append(plugin.spans, trace.SpanContextFromContext(ctx))
+
+	return plugin.IPlugin.Random(ctx, r)
+}
+
+func Test_SpinWithContextRandom(t *testing.T) {
+	game, err := NewGame2("../unittestdata/testgame.json", func() sgc7plugin.IPlugin {
+		return sgc7plugin.NewFastPlugin()
+	}, NewBasicRNG, NewEmptyFeatureLevel)
+	assert.NoError(t, err)
+
+	sr := tracetest.NewSpanRecorder()
+	oldtp := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	defer otel.SetTracerProvider(oldtp)
+
+	stake := &sgc7game.Stake{CoinBet: 1, CashBet: 20, Currency: "EUR"}
+	plugin := &ctxPlugin{IPlugin: sgc7plugin.NewFastPlugin()}
+
+	gameData := game.NewGameData(stake)
+	defer game.DeleteGameData(gameData)
+
+	// context.Background() is honoured too, every Random is in the span of a component
+	_, err = SpinWithContext(context.Background(), game, game.Initialize(), plugin, stake, "SPIN", "", "", false, gameData)
+	assert.NoError(t, err)
+
+	spans := sr.Ended()
+	assert.NotEmpty(t, spans)
+	assert.NotEmpty(t, plugin.spans)
+
+	mapSpans := make(map[trace.SpanID]bool)
+	for _, v := range spans {
+		mapSpans[v.SpanContext().SpanID()] = true
+	}
+
+	for _, v := range plugin.spans {
+		assert.True(t, v.IsValid())
+		assert.True(t, mapSpans[v.SpanID()])
+	}
+
+	t.Logf("Test_SpinWithContextRandom OK")
+}
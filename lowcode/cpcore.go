@@ -1,7 +1,6 @@
 package lowcode
 
 import (
-	"context"
 	"fmt"
 	"log/slog"
 	"math/rand"
@@ -160,7 +159,7 @@ func (gcd *CPCoreData) onRefillSymbols2(gameProp *GameProperty, curpr *sgc7game.
 				return ErrInvalidComponentData
 			}
 
-			ci, err := plugin.Random(context.Background(), posd.Len())
+			ci, err := plugin.Random(sgc7plugin.GetContext(plugin), posd.Len())
 			if err != nil {
 				goutils.Error("CPCoreData.onRefillSymbols2:Random",
 					goutils.Err(err))
@@ -206,7 +205,7 @@ func (gcd *CPCoreData) onRefillSymbols2(gameProp *GameProperty, curpr *sgc7game.
 			return ErrInvalidComponentData
 		}
 
-		ci, err := plugin.Random(context.Background(), posd.Len())
+		ci, err := plugin.Random(sgc7plugin.GetContext(plugin), posd.Len())
 		if err != nil {
 			goutils.Error("CPCoreData.onRefillSymbols2:Random",
 				goutils.Err(err))
@@ -996,7 +995,7 @@ func (cpc *CPCore) OnPlayGame(gameProp *GameProperty, curpr *sgc7game.PlayResult
 			return "", ErrInvalidComponentData
 		}
 
-		ci, err := plugin.Random(context.Background(), posd.Len())
+		ci, err := plugin.Random(sgc7plugin.GetContext(plugin), posd.Len())
 		if err != nil {
 			goutils.Error("CPCore.OnPlayGame:Random",
 				goutils.Err(err))
@@ -1024,7 +1023,7 @@ func (cpc *CPCore) OnPlayGame(gameProp *GameProperty, curpr *sgc7game.PlayResult
 			break
 		}
 
-		ci, err := plugin.Random(context.Background(), posd.Len())
+		ci, err := plugin.Random(sgc7plugin.GetContext(plugin), posd.Len())
 		if err != nil {
 			goutils.Error("CPCore.OnPlayGame:Random2",
 				goutils.Err(err))
@@ -0,0 +1,508 @@
+package lowcode
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+
+	"github.com/bytedance/sonic"
+	"github.com/bytedance/sonic/ast"
+	"github.com/zhs007/goutils"
+	"github.com/zhs007/slotsgamecore7/asciigame"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+	"github.com/zhs007/slotsgamecore7/sgc7pb"
+	"github.com/zhs007/slotsgamecore7/stats2"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v2"
+)
+
+const DynamicHeightReelsTypeName = "dynamicHeightReels"
+
+// DynamicHeightReelsData -
+type DynamicHeightReelsData struct {
+	BasicComponentData
+	Heights      []int // 每个轴的高度，不包括顶轴
+	TopReel      []int // 顶轴上的符号，从左到右
+	TopReelStart int
+	TopReelIndex int
+	Ways         int
+}
+
+// OnNewGame -
+func (dynamicHeightReelsData *DynamicHeightReelsData) OnNewGame(gameProp *GameProperty, component IComponent) {
+	dynamicHeightReelsData.BasicComponentData.OnNewGame(gameProp, component)
+}
+
+// onNewStep -
+func (dynamicHeightReelsData *DynamicHeightReelsData) onNewStep() {
+	dynamicHeightReelsData.UsedScenes = nil
+	dynamicHeightReelsData.Heights = nil
+	dynamicHeightReelsData.TopReel = nil
+	dynamicHeightReelsData.TopReelStart = 0
+	dynamicHeightReelsData.TopReelIndex = -1
+	dynamicHeightReelsData.Ways = 0
+}
+
+// Clone
+func (dynamicHeightReelsData *DynamicHeightReelsData) Clone() IComponentData {
+	target := &DynamicHeightReelsData{
+		BasicComponentData: dynamicHeightReelsData.CloneBasicComponentData(),
+		Heights:            slices.Clone(dynamicHeightReelsData.Heights),
+		TopReel:            slices.Clone(dynamicHeightReelsData.TopReel),
+		TopReelStart:       dynamicHeightReelsData.TopReelStart,
+		TopReelIndex:       dynamicHeightReelsData.TopReelIndex,
+		Ways:               dynamicHeightReelsData.Ways,
+	}
+
+	return target
+}
+
+// BuildPBComponentData
+func (dynamicHeightReelsData *DynamicHeightReelsData) BuildPBComponentData() proto.Message {
+	pbcd := &sgc7pb.DynamicHeightReelsData{
+		BasicComponentData: dynamicHeightReelsData.BuildPBBasicComponentData(),
+		TopReelStart:       int32(dynamicHeightReelsData.TopReelStart),
+		TopReelIndex:       int32(dynamicHeightReelsData.TopReelIndex),
+		Ways:               int32(dynamicHeightReelsData.Ways),
+	}
+
+	for _, v := range dynamicHeightReelsData.Heights {
+		pbcd.Heights = append(pbcd.Heights, int32(v))
+	}
+
+	for _, v := range dynamicHeightReelsData.TopReel {
+		pbcd.TopReel = append(pbcd.TopReel, int32(v))
+	}
+
+	return pbcd
+}
+
+// GetValEx -
+func (dynamicHeightReelsData *DynamicHeightReelsData) GetValEx(key string, getType GetComponentValType) (int, bool) {
+	if key == CVWays {
+		return dynamicHeightReelsData.Ways, true
+	}
+
+	return 0, false
+}
+
+// DynamicHeightReelsConfig - configuration for DynamicHeightReels
+//
+//	每个轴的高度按权重随机，符号放在轴的下面，上面空出来的格子是 -1，
+//	有顶轴时，第 0 行是顶轴，横着覆盖 [topReelStart, topReelStart + topReelWidth) 这些轴，ways 也会算上顶轴的格子
+type DynamicHeightReelsConfig struct {
+	BasicComponentConfig `yaml:",inline" json:",inline"`
+	ReelSet              string                  `yaml:"reelSet" json:"reelSet"`
+	HeightWeights        []string                `yaml:"heightWeights" json:"heightWeights"` // 每个轴的高度权重，只有 1 个时所有轴都用它
+	HeightWeightsVW      []*sgc7game.ValWeights2 `yaml:"-" json:"-"`
+	TopReelSet           string                  `yaml:"topReelSet" json:"topReelSet"`     // 顶轴用这个 reelSet 的第 1 个轴，空表示没有顶轴
+	TopReelStart         int                     `yaml:"topReelStart" json:"topReelStart"` // 顶轴覆盖的第一个轴
+	TopReelWidth         int                     `yaml:"topReelWidth" json:"topReelWidth"` // 顶轴覆盖几个轴，0 表示覆盖除了两边以外的轴
+	Controllers          []*Award                `yaml:"controllers" json:"controllers"`
+}
+
+// SetLinkComponent
+func (cfg *DynamicHeightReelsConfig) SetLinkComponent(link string, componentName string) {
+	if link == "next" {
+		cfg.DefaultNextComponent = componentName
+	}
+}
+
+type DynamicHeightReels struct {
+	*BasicComponent `json:"-"`
+	Config          *DynamicHeightReelsConfig `json:"config"`
+}
+
+// Init -
+func (dynamicHeightReels *DynamicHeightReels) Init(fn string, pool *GamePropertyPool) error {
+	data, err := os.ReadFile(fn)
+	if err != nil {
+		goutils.Error("DynamicHeightReels.Init:ReadFile",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return err
+	}
+
+	cfg := &DynamicHeightReelsConfig{}
+
+	err = yaml.Unmarshal(data, cfg)
+	if err != nil {
+		goutils.Error("DynamicHeightReels.Init:Unmarshal",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return err
+	}
+
+	return dynamicHeightReels.InitEx(cfg, pool)
+}
+
+// InitEx -
+func (dynamicHeightReels *DynamicHeightReels) InitEx(cfg any, pool *GamePropertyPool) error {
+	dynamicHeightReels.Config = cfg.(*DynamicHeightReelsConfig)
+	dynamicHeightReels.Config.ComponentType = DynamicHeightReelsTypeName
+
+	if pool.Config == nil {
+		goutils.Error("DynamicHeightReels.InitEx:Config",
+			goutils.Err(ErrInvalidComponentConfig))
+
+		return ErrInvalidComponentConfig
+	}
+
+	width := pool.Config.Width
+	height := pool.Config.Height
+
+	if len(dynamicHeightReels.Config.HeightWeights) != 1 && len(dynamicHeightReels.Config.HeightWeights) != width {
+		goutils.Error("DynamicHeightReels.InitEx:HeightWeights",
+			slog.Int("heightWeights", len(dynamicHeightReels.Config.HeightWeights)),
+			slog.Int("width", width),
+			goutils.Err(ErrInvalidComponentConfig))
+
+		return ErrInvalidComponentConfig
+	}
+
+	topRows := 0
+	if dynamicHeightReels.Config.TopReelSet != "" {
+		topRows = 1
+
+		if dynamicHeightReels.Config.TopReelWidth <= 0 {
+			dynamicHeightReels.Config.TopReelStart = 1
+			dynamicHeightReels.Config.TopReelWidth = width - 2
+		}
+
+		if dynamicHeightReels.Config.TopReelStart < 0 || dynamicHeightReels.Config.TopReelWidth <= 0 ||
+			dynamicHeightReels.Config.TopReelStart+dynamicHeightReels.Config.TopReelWidth > width {
+
+			goutils.Error("DynamicHeightReels.InitEx:TopReel",
+				slog.Int("topReelStart", dynamicHeightReels.Config.TopReelStart),
+				slog.Int("topReelWidth", dynamicHeightReels.Config.TopReelWidth),
+				goutils.Err(ErrInvalidComponentConfig))
+
+			return ErrInvalidComponentConfig
+		}
+	}
+
+	dynamicHeightReels.Config.HeightWeightsVW = nil
+
+	for _, v := range dynamicHeightReels.Config.HeightWeights {
+		vw2, err := pool.LoadIntWeights(v, dynamicHeightReels.Config.UseFileMapping)
+		if err != nil || vw2 == nil {
+			goutils.Error("DynamicHeightReels.InitEx:LoadIntWeights",
+				slog.String("Weight", v),
+				goutils.Err(ErrInvalidComponentConfig))
+
+			return ErrInvalidComponentConfig
+		}
+
+		for _, h := range vw2.Vals {
+			if h.Int() <= 0 || h.Int()+topRows > height {
+				goutils.Error("DynamicHeightReels.InitEx:Height",
+					slog.String("Weight", v),
+					slog.Int("height", h.Int()),
+					goutils.Err(ErrInvalidComponentConfig))
+
+				return ErrInvalidComponentConfig
+			}
+		}
+
+		dynamicHeightReels.Config.HeightWeightsVW = append(dynamicHeightReels.Config.HeightWeightsVW, vw2)
+	}
+
+	for _, ctrl := range dynamicHeightReels.Config.Controllers {
+		ctrl.Init()
+	}
+
+	dynamicHeightReels.onInit(&dynamicHeightReels.Config.BasicComponentConfig)
+
+	return nil
+}
+
+func (dynamicHeightReels *DynamicHeightReels) getReelSet(basicCD *BasicComponentData) string {
+	str := basicCD.GetConfigVal(CCVReelSet)
+	if str != "" {
+		return str
+	}
+
+	return dynamicHeightReels.Config.ReelSet
+}
+
+func (dynamicHeightReels *DynamicHeightReels) getHeightWeight(x int) *sgc7game.ValWeights2 {
+	if len(dynamicHeightReels.Config.HeightWeightsVW) == 1 {
+		return dynamicHeightReels.Config.HeightWeightsVW[0]
+	}
+
+	return dynamicHeightReels.Config.HeightWeightsVW[x]
+}
+
+// isUnderTopReel - 这个轴上面有没有顶轴
+func (dynamicHeightReels *DynamicHeightReels) isUnderTopReel(x int) bool {
+	return dynamicHeightReels.Config.TopReelSet != "" &&
+		x >= dynamicHeightReels.Config.TopReelStart && x < dynamicHeightReels.Config.TopReelStart+dynamicHeightReels.Config.TopReelWidth
+}
+
+// OnProcControllers -
+func (dynamicHeightReels *DynamicHeightReels) ProcControllers(gameProp *GameProperty, plugin sgc7plugin.IPlugin, curpr *sgc7game.PlayResult, gp *GameParams, val int, strVal string) {
+	if len(dynamicHeightReels.Config.Controllers) > 0 {
+		gameProp.procAwards(plugin, dynamicHeightReels.Config.Controllers, curpr, gp)
+	}
+}
+
+// randScene - 先随机每个轴的高度，再随机每个轴的位置，最后随机顶轴的位置
+func (dynamicHeightReels *DynamicHeightReels) randScene(gameProp *GameProperty, plugin sgc7plugin.IPlugin, sc *sgc7game.GameScene,
+	rd *sgc7game.ReelsData, cd *DynamicHeightReelsData) error {
+
+	for x := 0; x < sc.Width; x++ {
+		val, err := dynamicHeightReels.getHeightWeight(x).RandVal(plugin)
+		if err != nil {
+			goutils.Error("DynamicHeightReels.randScene:RandVal",
+				slog.Int("x", x),
+				goutils.Err(err))
+
+			return err
+		}
+
+		cd.Heights = append(cd.Heights, val.Int())
+	}
+
+	sc.Indexes = make([]int, 0, sc.Width)
+
+	for x, arr := range sc.Arr {
+		reel := rd.Reels[x]
+
+		cn, err := plugin.Random(sgc7plugin.GetContext(plugin), len(reel))
+		if err != nil {
+			goutils.Error("DynamicHeightReels.randScene:Random",
+				slog.Int("x", x),
+				goutils.Err(err))
+
+			return err
+		}
+
+		sc.Indexes = append(sc.Indexes, cn)
+
+		starty := len(arr) - cd.Heights[x]
+		for y := range arr {
+			if y < starty {
+				arr[y] = -1
+
+				continue
+			}
+
+			arr[y] = reel[cn]
+
+			cn++
+			if cn >= len(reel) {
+				cn -= len(reel)
+			}
+		}
+	}
+
+	ways := 1
+	for x, h := range cd.Heights {
+		if dynamicHeightReels.isUnderTopReel(x) {
+			h++
+		}
+
+		ways *= h
+	}
+
+	cd.Ways = ways
+
+	if dynamicHeightReels.Config.TopReelSet == "" {
+		return nil
+	}
+
+	trd, isok := gameProp.Pool.Config.MapReels[dynamicHeightReels.Config.TopReelSet]
+	if !isok || len(trd.Reels) == 0 || len(trd.Reels[0]) == 0 {
+		goutils.Error("DynamicHeightReels.randScene:TopReelSet",
+			slog.String("topReelSet", dynamicHeightReels.Config.TopReelSet),
+			goutils.Err(ErrInvalidReels))
+
+		return ErrInvalidReels
+	}
+
+	topReel := trd.Reels[0]
+
+	cn, err := plugin.Random(sgc7plugin.GetContext(plugin), len(topReel))
+	if err != nil {
+		goutils.Error("DynamicHeightReels.randScene:Random",
+			goutils.Err(err))
+
+		return err
+	}
+
+	cd.TopReelStart = dynamicHeightReels.Config.TopReelStart
+	cd.TopReelIndex = cn
+
+	for i := 0; i < dynamicHeightReels.Config.TopReelWidth; i++ {
+		s := topReel[(cn+i)%len(topReel)]
+
+		sc.Arr[dynamicHeightReels.Config.TopReelStart+i][0] = s
+		cd.TopReel = append(cd.TopReel, s)
+	}
+
+	return nil
+}
+
+// playgame
+func (dynamicHeightReels *DynamicHeightReels) OnPlayGame(gameProp *GameProperty, curpr *sgc7game.PlayResult, gp *GameParams, plugin sgc7plugin.IPlugin,
+	cmd string, param string, ps sgc7game.IPlayerState, stake *sgc7game.Stake, prs []*sgc7game.PlayResult, icd IComponentData) (string, error) {
+
+	cd := icd.(*DynamicHeightReelsData)
+	cd.onNewStep()
+
+	reelname := dynamicHeightReels.getReelSet(&cd.BasicComponentData)
+	rd, isok := gameProp.Pool.Config.MapReels[reelname]
+	if !isok {
+		goutils.Error("DynamicHeightReels.OnPlayGame:MapReels",
+			slog.String("reelSet", reelname),
+			goutils.Err(ErrInvalidReels))
+
+		return "", ErrInvalidReels
+	}
+
+	gameProp.TagStr(TagCurReels, reelname)
+
+	gameProp.CurReels = rd
+
+	sc := gameProp.PoolScene.New(gameProp.GetVal(GamePropWidth), gameProp.GetVal(GamePropHeight))
+	sc.ReelName = reelname
+
+	err := dynamicHeightReels.randScene(gameProp, plugin, sc, rd, cd)
+	if err != nil {
+		goutils.Error("DynamicHeightReels.OnPlayGame:randScene",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	dynamicHeightReels.AddScene(gameProp, curpr, sc, &cd.BasicComponentData)
+
+	dynamicHeightReels.ProcControllers(gameProp, plugin, curpr, gp, cd.Ways, "")
+
+	nc := dynamicHeightReels.onStepEnd(gameProp, curpr, gp, "")
+
+	return nc, nil
+}
+
+// OnAsciiGame - outpur to asciigame
+func (dynamicHeightReels *DynamicHeightReels) OnAsciiGame(gameProp *GameProperty, pr *sgc7game.PlayResult, lst []*sgc7game.PlayResult, mapSymbolColor *asciigame.SymbolColorMap, icd IComponentData) error {
+	cd := icd.(*DynamicHeightReelsData)
+
+	if len(cd.UsedScenes) > 0 {
+		asciigame.OutputScene("initial symbols", pr.Scenes[cd.UsedScenes[0]], mapSymbolColor)
+	}
+
+	fmt.Printf("heights %v, ways %v\n", cd.Heights, cd.Ways)
+
+	return nil
+}
+
+// NewComponentData -
+func (dynamicHeightReels *DynamicHeightReels) NewComponentData() IComponentData {
+	return &DynamicHeightReelsData{
+		TopReelIndex: -1,
+	}
+}
+
+// OnStats2
+func (dynamicHeightReels *DynamicHeightReels) OnStats2(icd IComponentData, s2 *stats2.Cache, gameProp *GameProperty, gp *GameParams, pr *sgc7game.PlayResult, isOnStepEnd bool) {
+	dynamicHeightReels.BasicComponent.OnStats2(icd, s2, gameProp, gp, pr, isOnStepEnd)
+
+	cd := icd.(*DynamicHeightReelsData)
+
+	s2.ProcStatsIntVal(dynamicHeightReels.GetName(), cd.Ways)
+}
+
+// NewStats2 -
+func (dynamicHeightReels *DynamicHeightReels) NewStats2(parent string) *stats2.Feature {
+	return stats2.NewFeature(parent, []stats2.Option{stats2.OptIntVal})
+}
+
+func NewDynamicHeightReels(name string) IComponent {
+	return &DynamicHeightReels{
+		BasicComponent: NewBasicComponent(name, 0),
+	}
+}
+
+// "reelSet": "bgreels",
+// "heightWeights": ["height2to7"],
+// "topReelSet": "bgtopreel",
+// "topReelStart": 1,
+// "topReelWidth": 4
+type jsonDynamicHeightReels struct {
+	ReelSet       string   `json:"reelSet"`
+	HeightWeights []string `json:"heightWeights"`
+	TopReelSet    string   `json:"topReelSet"`
+	TopReelStart  int      `json:"topReelStart"`
+	TopReelWidth  int      `json:"topReelWidth"`
+}
+
+func (jcfg *jsonDynamicHeightReels) build() *DynamicHeightReelsConfig {
+	cfg := &DynamicHeightReelsConfig{
+		ReelSet:       jcfg.ReelSet,
+		HeightWeights: slices.Clone(jcfg.HeightWeights),
+		TopReelSet:    jcfg.TopReelSet,
+		TopReelStart:  jcfg.TopReelStart,
+		TopReelWidth:  jcfg.TopReelWidth,
+	}
+
+	return cfg
+}
+
+func parseDynamicHeightReels(gamecfg *BetConfig, cell *ast.Node) (string, error) {
+	cfg, label, ctrls, err := getConfigInCell(cell)
+	if err != nil {
+		goutils.Error("parseDynamicHeightReels:getConfigInCell",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	buf, err := cfg.MarshalJSON()
+	if err != nil {
+		goutils.Error("parseDynamicHeightReels:MarshalJSON",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	data := &jsonDynamicHeightReels{}
+
+	err = sonic.Unmarshal(buf, data)
+	if err != nil {
+		goutils.Error("parseDynamicHeightReels:Unmarshal",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	cfgd := data.build()
+
+	if ctrls != nil {
+		awards, err := parseControllers(ctrls)
+		if err != nil {
+			goutils.Error("parseDynamicHeightReels:parseControllers",
+				goutils.Err(err))
+
+			return "", err
+		}
+
+		cfgd.Controllers = awards
+	}
+
+	gamecfg.mapConfig[label] = cfgd
+	gamecfg.mapBasicConfig[label] = &cfgd.BasicComponentConfig
+
+	ccfg := &ComponentConfig{
+		Name: label,
+		Type: DynamicHeightReelsTypeName,
+	}
+
+	gamecfg.Components = append(gamecfg.Components, ccfg)
+
+	return label, nil
+}
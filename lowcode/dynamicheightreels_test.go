@@ -0,0 +1,112 @@
+package lowcode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+	"github.com/zhs007/slotsgamecore7/sgc7pb"
+)
+
+func Test_DynamicHeightReelsOnPlayGame(t *testing.T) {
+	vw, err := sgc7game.NewValWeights2([]sgc7game.IVal{
+		sgc7game.NewIntValEx(1), sgc7game.NewIntValEx(2), sgc7game.NewIntValEx(3),
+	}, []int{1, 1, 1})
+	assert.NoError(t, err)
+
+	pool := &GamePropertyPool{
+		mapIntValWeights: map[string]*sgc7game.ValWeights2{"heights": vw},
+		Config: &Config{
+			Width:  3,
+			Height: 4,
+			MapReels: map[string]*sgc7game.ReelsData{
+				"bg":  {Reels: [][]int{{1, 2, 3, 4}, {5, 6, 7, 8}, {9, 10, 11, 12}}},
+				"top": {Reels: [][]int{{20, 21, 22}}},
+			},
+		},
+	}
+
+	gameProp := &GameProperty{Pool: pool}
+	gameProp.PoolScene = sgc7game.NewGameScenePoolEx()
+	gameProp.rng = &stubRNG{}
+	gameProp.featureLevel = &stubFeatureLevel{}
+	gameProp.SceneStack = NewSceneStack(false)
+	gameProp.OtherSceneStack = NewSceneStack(true)
+	gameProp.MapVals = map[int]int{GamePropWidth: 3, GamePropHeight: 4}
+	gameProp.mapStr = map[string]string{}
+
+	stake := &sgc7game.Stake{CoinBet: 1, CashBet: 1}
+	gp := NewGameParam(stake, nil)
+
+	dhr := NewDynamicHeightReels("dhr").(*DynamicHeightReels)
+	err = dhr.InitEx(&DynamicHeightReelsConfig{
+		BasicComponentConfig: BasicComponentConfig{DefaultNextComponent: "next"},
+		ReelSet:              "bg",
+		HeightWeights:        []string{"heights"},
+		TopReelSet:           "top",
+	}, pool)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, dhr.Config.TopReelStart)
+	assert.Equal(t, 1, dhr.Config.TopReelWidth)
+
+	cd := dhr.NewComponentData().(*DynamicHeightReelsData)
+	cd.OnNewGame(gameProp, dhr)
+
+	// 高度是 3, 1, 2，位置是 0, 1, 3，顶轴是 2
+	plugin := sgc7plugin.NewMockPlugin()
+	plugin.Cache = []int{2, 0, 1, 0, 1, 3, 2}
+
+	pr := sgc7game.NewPlayResult("bg", 0, 0, "bg")
+	nc, err := dhr.OnPlayGame(gameProp, pr, gp, plugin, DefaultCmd, "", nil, stake, nil, cd)
+	assert.NoError(t, err)
+	assert.Equal(t, "next", nc)
+	assert.Len(t, pr.Scenes, 1)
+
+	assert.Equal(t, []int{3, 1, 2}, cd.Heights)
+	assert.Equal(t, []int{22}, cd.TopReel)
+	assert.Equal(t, 2, cd.TopReelIndex)
+	assert.Equal(t, 3*2*2, cd.Ways)
+
+	sc := pr.Scenes[0]
+	assert.Equal(t, []int{-1, 1, 2, 3}, sc.Arr[0])
+	assert.Equal(t, []int{22, -1, -1, 6}, sc.Arr[1])
+	assert.Equal(t, []int{-1, -1, 12, 9}, sc.Arr[2])
+	assert.Equal(t, []int{0, 1, 3}, sc.Indexes)
+
+	ways, isok := cd.GetValEx(CVWays, GCVTypeNormal)
+	assert.True(t, isok)
+	assert.Equal(t, 12, ways)
+
+	pbcd := cd.BuildPBComponentData().(*sgc7pb.DynamicHeightReelsData)
+	assert.Equal(t, []int32{3, 1, 2}, pbcd.Heights)
+	assert.Equal(t, int32(12), pbcd.Ways)
+	assert.Equal(t, int32(1), pbcd.TopReelStart)
+
+	// waysTrigger 里的 ways 不算空的格子
+	waysTrigger := NewWaysTrigger("wt").(*WaysTrigger)
+	waysTrigger.Config = &WaysTriggerConfig{}
+	assert.Equal(t, 12, waysTrigger.calcWays(gameProp, sc, &WaysTriggerData{}))
+
+	cd1 := cd.Clone().(*DynamicHeightReelsData)
+	cd1.Heights[0] = 1
+	assert.Equal(t, 3, cd.Heights[0])
+
+	// 顶轴超出了范围
+	err = NewDynamicHeightReels("dhr").InitEx(&DynamicHeightReelsConfig{
+		ReelSet:       "bg",
+		HeightWeights: []string{"heights"},
+		TopReelSet:    "top",
+		TopReelStart:  2,
+		TopReelWidth:  2,
+	}, pool)
+	assert.ErrorIs(t, err, ErrInvalidComponentConfig)
+
+	err = NewDynamicHeightReels("dhr").InitEx(&DynamicHeightReelsConfig{
+		ReelSet:       "bg",
+		HeightWeights: []string{"heights", "heights"},
+	}, pool)
+	assert.ErrorIs(t, err, ErrInvalidComponentConfig)
+
+	t.Logf("Test_DynamicHeightReelsOnPlayGame OK")
+}
@@ -1,7 +1,6 @@
 package lowcode
 
 import (
-	"context"
 	"log/slog"
 	"os"
 
@@ -178,7 +177,7 @@ func (featureBar *FeatureBar) randFirstJump(_ *GameProperty, _ *FeatureBarData,
 		return true, nil
 	}
 
-	cr, err := plugin.Random(context.Background(), 100)
+	cr, err := plugin.Random(sgc7plugin.GetContext(plugin), 100)
 	if err != nil {
 		goutils.Error("FeatureBar.randFirstJump:Random",
 			goutils.Err(err))
@@ -1,7 +1,6 @@
 package lowcode
 
 import (
-	"context"
 	"log/slog"
 	"os"
 	"slices"
@@ -259,7 +258,7 @@ func (featureBar2 *FeatureBar2) randFirstJump(_ *GameProperty, _ *FeatureBar2Dat
 		return true, nil
 	}
 
-	cr, err := plugin.Random(context.Background(), 100)
+	cr, err := plugin.Random(sgc7plugin.GetContext(plugin), 100)
 	if err != nil {
 		goutils.Error("FeatureBar2.randFirstJump:Random",
 			goutils.Err(err))
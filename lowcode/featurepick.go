@@ -1,7 +1,6 @@
 package lowcode
 
 import (
-	"context"
 	"log/slog"
 	"os"
 	"slices"
@@ -219,7 +218,7 @@ func (featurePick *FeaturePick) OnPlayGame(gameProp *GameProperty, curpr *sgc7ga
 		cd.CurSelected = make([]string, curPickNum)
 
 		for i := range curPickNum {
-			ci, err := plugin.Random(context.Background(), curPickNum-i)
+			ci, err := plugin.Random(sgc7plugin.GetContext(plugin), curPickNum-i)
 			if err != nil {
 				goutils.Error("FeaturePick.OnPlayGame:Random",
 					goutils.Err(err))
@@ -0,0 +1,977 @@
+package lowcode
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/bytedance/sonic/ast"
+	"github.com/zhs007/goutils"
+	"github.com/zhs007/slotsgamecore7/asciigame"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+	"github.com/zhs007/slotsgamecore7/sgc7pb"
+	"github.com/zhs007/slotsgamecore7/stats2"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v2"
+)
+
+const GambleTypeName = "gamble"
+
+type GambleType int
+
+const (
+	GambleTypeColour GambleType = 0 // 猜颜色，x2
+	GambleTypeSuit   GambleType = 1 // 猜花色，x4
+	GambleTypeLadder GambleType = 2 // 爬梯子，按 Ladder 里的倍数往上走
+)
+
+const (
+	// DefaultGambleCmd - 默认的 gamble 命令
+	DefaultGambleCmd = "GAMBLE"
+	// DefaultCollectCmd - 默认的收钱命令
+	DefaultCollectCmd = "COLLECT"
+	// GambleLadderUp - ladder 模式下唯一的选项
+	GambleLadderUp = "up"
+	// GambleWin - ladder 模式下的 history
+	GambleWin = "win"
+	// GambleLose - ladder 模式下的 history
+	GambleLose = "lose"
+	// GambleCollect - 收钱
+	GambleCollect = "collect"
+
+	// gambleRTPPrecision - rtp 的精度，万分之一
+	gambleRTPPrecision = 10000
+)
+
+var gambleColourOptions = []string{"red", "black"}
+var gambleSuitOptions = []string{"hearts", "diamonds", "clubs", "spades"}
+
+func parseGambleType(str string) GambleType {
+	switch strings.ToLower(str) {
+	case "suit":
+		return GambleTypeSuit
+	case "ladder":
+		return GambleTypeLadder
+	}
+
+	return GambleTypeColour
+}
+
+// GambleCmdParam - NextCmdParams 里带上当前的状态给前端看，
+//
+//	状态以服务器保存的 GamblePS 为准，param 里的状态和 GamblePS 不一样时就是非法的
+type GambleCmdParam struct {
+	Option   string   `json:"option,omitempty"` // collect 时是空的
+	BaseWin  int      `json:"baseWin"`
+	CurWin   int      `json:"curWin"`
+	Rung     int      `json:"rung"`
+	Attempts int      `json:"attempts"`
+	History  []string `json:"history,omitempty"`
+}
+
+// GamblePS - 等玩家选的时候，gamble 的状态放在私有的 PlayerState 里，
+//
+//	这样每次请求都可以用新的 gameData，也不需要相信前端传回来的 param
+type GamblePS struct {
+	IsWaiting bool     `json:"isWaiting"`
+	BaseWin   int      `json:"baseWin"`
+	CurWin    int      `json:"curWin"`
+	Rung      int      `json:"rung"`
+	Attempts  int      `json:"attempts"`
+	History   []string `json:"history,omitempty"`
+}
+
+// SetPublicJson
+func (ps *GamblePS) SetPublicJson(str string) error {
+	return nil
+}
+
+// SetPrivateJson
+func (ps *GamblePS) SetPrivateJson(str string) error {
+	err := sonic.UnmarshalString(str, ps)
+	if err != nil {
+		goutils.Error("GamblePS.SetPrivateJson:UnmarshalString",
+			goutils.Err(err))
+
+		return err
+	}
+
+	return nil
+}
+
+// GetPublicJson
+func (ps *GamblePS) GetPublicJson() string {
+	return ""
+}
+
+// GetPrivateJson
+func (ps *GamblePS) GetPrivateJson() string {
+	str, err := sonic.MarshalString(ps)
+	if err != nil {
+		goutils.Error("GamblePS.GetPrivateJson:MarshalString",
+			goutils.Err(err))
+
+		return ""
+	}
+
+	return str
+}
+
+// Clone
+func (ps *GamblePS) Clone() IComponentPS {
+	return &GamblePS{
+		IsWaiting: ps.IsWaiting,
+		BaseWin:   ps.BaseWin,
+		CurWin:    ps.CurWin,
+		Rung:      ps.Rung,
+		Attempts:  ps.Attempts,
+		History:   slices.Clone(ps.History),
+	}
+}
+
+// GambleData - CurWin 是现在可以领的奖，输了就是 0
+type GambleData struct {
+	BasicComponentData
+	BaseWin   int      // 进入 gamble 时这一局的奖
+	CurWin    int      // 当前可以领的奖
+	Rung      int      // ladder 模式下当前在第几级，0 表示还没往上走
+	Attempts  int      // gamble 了几次
+	History   []string // 每次翻出来的牌，ladder 模式是 win / lose
+	CurCard   string   // 这一步翻出来的牌
+	IsWaiting bool
+}
+
+// OnNewGame -
+func (gambleData *GambleData) OnNewGame(gameProp *GameProperty, component IComponent) {
+	gambleData.BasicComponentData.OnNewGame(gameProp, component)
+
+	gambleData.BaseWin = 0
+	gambleData.CurWin = 0
+	gambleData.Rung = 0
+	gambleData.Attempts = 0
+	gambleData.History = nil
+	gambleData.CurCard = ""
+	gambleData.IsWaiting = false
+}
+
+// onNewStep -
+func (gambleData *GambleData) onNewStep() {
+	gambleData.UsedResults = nil
+	gambleData.CurCard = ""
+}
+
+// Clone
+func (gambleData *GambleData) Clone() IComponentData {
+	target := &GambleData{
+		BasicComponentData: gambleData.CloneBasicComponentData(),
+		BaseWin:            gambleData.BaseWin,
+		CurWin:             gambleData.CurWin,
+		Rung:               gambleData.Rung,
+		Attempts:           gambleData.Attempts,
+		History:            slices.Clone(gambleData.History),
+		CurCard:            gambleData.CurCard,
+		IsWaiting:          gambleData.IsWaiting,
+	}
+
+	return target
+}
+
+// BuildPBComponentData - 用 FeaturePickData，
+//
+//	selected 是 history，curSelected 是这一步翻出来的牌，
+//	pickNum 是已经 gamble 的次数，curPickedNum 是 ladder 的级数，output 是当前可以领的奖
+func (gambleData *GambleData) BuildPBComponentData() proto.Message {
+	pbcd := &sgc7pb.FeaturePickData{
+		BasicComponentData: gambleData.BuildPBBasicComponentData(),
+		Selected:           slices.Clone(gambleData.History),
+		PickNum:            int32(gambleData.Attempts),
+		CurPickedNum:       int32(gambleData.Rung),
+	}
+
+	pbcd.BasicComponentData.Output = int32(gambleData.CurWin)
+
+	if gambleData.CurCard != "" {
+		pbcd.CurSelected = []string{gambleData.CurCard}
+	}
+
+	return pbcd
+}
+
+// GetValEx -
+func (gambleData *GambleData) GetValEx(key string, getType GetComponentValType) (int, bool) {
+	switch key {
+	case CVWins:
+		return gambleData.CurWin, true
+	case CVNumber:
+		return gambleData.Attempts, true
+	}
+
+	return 0, false
+}
+
+// GambleConfig - configuration for Gamble
+type GambleConfig struct {
+	BasicComponentConfig `yaml:",inline" json:",inline"`
+	StrType              string     `yaml:"type" json:"type"` // colour / suit / ladder
+	Type                 GambleType `yaml:"-" json:"-"`
+	GambleCmd            string     `yaml:"gambleCmd" json:"gambleCmd"`     // 默认是 GAMBLE
+	CollectCmd           string     `yaml:"collectCmd" json:"collectCmd"`   // 默认是 COLLECT
+	MaxWin               int        `yaml:"maxWin" json:"maxWin"`           // 赢了以后超过这个值就不能 gamble，0 表示不限制
+	MaxAttempts          int        `yaml:"maxAttempts" json:"maxAttempts"` // 最多 gamble 几次，0 表示不限制
+	Ladder               []int      `yaml:"ladder" json:"ladder"`           // ladder 模式下每一级相对 BaseWin 的倍数，要递增
+	RTP                  float64    `yaml:"rtp" json:"rtp"`                 // 每次 gamble 的 rtp，0 表示 1，不能大于 1
+	RTPBP                int        `yaml:"-" json:"-"`                     // RTP 换算成万分之一
+}
+
+// SetLinkComponent
+func (cfg *GambleConfig) SetLinkComponent(link string, componentName string) {
+	if link == "next" {
+		cfg.DefaultNextComponent = componentName
+	}
+}
+
+// Gamble - 一局赢了以后可以 gamble，要放在流程的最后，
+//
+//	需要在 MapCmdComponent 里把 GambleCmd、CollectCmd 对应到这个组件，没有配置的话 InitEx 会自动加上，
+//	NextCmds 第一个是 CollectCmd，这样自动选择时不会 gamble，RTP 模式下也不会进入 gamble，用 CalcRTP 单独评估
+type Gamble struct {
+	*BasicComponent `json:"-"`
+	Config          *GambleConfig `json:"config"`
+}
+
+// Init -
+func (gamble *Gamble) Init(fn string, pool *GamePropertyPool) error {
+	data, err := os.ReadFile(fn)
+	if err != nil {
+		goutils.Error("Gamble.Init:ReadFile",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return err
+	}
+
+	cfg := &GambleConfig{}
+
+	err = yaml.Unmarshal(data, cfg)
+	if err != nil {
+		goutils.Error("Gamble.Init:Unmarshal",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return err
+	}
+
+	return gamble.InitEx(cfg, pool)
+}
+
+// InitEx -
+func (gamble *Gamble) InitEx(cfg any, pool *GamePropertyPool) error {
+	gamble.Config = cfg.(*GambleConfig)
+	gamble.Config.ComponentType = GambleTypeName
+
+	gamble.Config.Type = parseGambleType(gamble.Config.StrType)
+
+	if gamble.Config.GambleCmd == "" {
+		gamble.Config.GambleCmd = DefaultGambleCmd
+	}
+
+	if gamble.Config.CollectCmd == "" {
+		gamble.Config.CollectCmd = DefaultCollectCmd
+	}
+
+	if gamble.Config.GambleCmd == gamble.Config.CollectCmd || gamble.Config.GambleCmd == DefaultCmd || gamble.Config.CollectCmd == DefaultCmd {
+		goutils.Error("Gamble.InitEx:Cmd",
+			slog.String("gambleCmd", gamble.Config.GambleCmd),
+			slog.String("collectCmd", gamble.Config.CollectCmd),
+			goutils.Err(ErrInvalidComponentConfig))
+
+		return ErrInvalidComponentConfig
+	}
+
+	if gamble.Config.RTP == 0 {
+		gamble.Config.RTP = 1
+	}
+
+	if gamble.Config.RTP < 0 || gamble.Config.RTP > 1 {
+		goutils.Error("Gamble.InitEx:RTP",
+			slog.Float64("rtp", gamble.Config.RTP),
+			goutils.Err(ErrInvalidComponentConfig))
+
+		return ErrInvalidComponentConfig
+	}
+
+	gamble.Config.RTPBP = int(gamble.Config.RTP*gambleRTPPrecision + 0.5)
+
+	if gamble.Config.Type == GambleTypeLadder {
+		if len(gamble.Config.Ladder) == 0 {
+			goutils.Error("Gamble.InitEx:Ladder",
+				goutils.Err(ErrInvalidComponentConfig))
+
+			return ErrInvalidComponentConfig
+		}
+
+		last := 1
+		for _, v := range gamble.Config.Ladder {
+			if v <= last {
+				goutils.Error("Gamble.InitEx:Ladder",
+					slog.Any("ladder", gamble.Config.Ladder),
+					goutils.Err(ErrInvalidComponentConfig))
+
+				return ErrInvalidComponentConfig
+			}
+
+			last = v
+		}
+	}
+
+	if pool != nil && pool.Config != nil {
+		if pool.Config.MapCmdComponent == nil {
+			pool.Config.MapCmdComponent = make(map[string]string)
+		}
+
+		for _, cmd := range []string{gamble.Config.GambleCmd, gamble.Config.CollectCmd} {
+			cn, isok := pool.Config.MapCmdComponent[cmd]
+			if !isok {
+				pool.Config.MapCmdComponent[cmd] = gamble.Name
+			} else if cn != gamble.Name {
+				goutils.Error("Gamble.InitEx:MapCmdComponent",
+					slog.String("cmd", cmd),
+					slog.String("component", cn),
+					goutils.Err(ErrInvalidComponentConfig))
+
+				return ErrInvalidComponentConfig
+			}
+		}
+	}
+
+	gamble.onInit(&gamble.Config.BasicComponentConfig)
+
+	return nil
+}
+
+// getOptions - 玩家可以选的
+func (gamble *Gamble) getOptions() []string {
+	switch gamble.Config.Type {
+	case GambleTypeSuit:
+		return gambleSuitOptions
+	case GambleTypeLadder:
+		return []string{GambleLadderUp}
+	}
+
+	return gambleColourOptions
+}
+
+// getMul - 当前的倍数和赢了以后的倍数，ladder 是相对 BaseWin，其它的是相对 CurWin
+func (gamble *Gamble) getMul(cd *GambleData) (int, int) {
+	switch gamble.Config.Type {
+	case GambleTypeSuit:
+		return 1, 4
+	case GambleTypeLadder:
+		cur := 1
+		if cd.Rung > 0 {
+			cur = gamble.Config.Ladder[cd.Rung-1]
+		}
+
+		if cd.Rung >= len(gamble.Config.Ladder) {
+			return cur, cur
+		}
+
+		return cur, gamble.Config.Ladder[cd.Rung]
+	}
+
+	return 1, 2
+}
+
+// getNextWin - 赢了以后的奖
+func (gamble *Gamble) getNextWin(cd *GambleData) int {
+	cur, next := gamble.getMul(cd)
+
+	if gamble.Config.Type == GambleTypeLadder {
+		return cd.BaseWin * next
+	}
+
+	return cd.CurWin * next / cur
+}
+
+// canGamble - 次数、梯子、最大奖都没超
+func (gamble *Gamble) canGamble(cd *GambleData) bool {
+	if cd.CurWin <= 0 {
+		return false
+	}
+
+	if gamble.Config.MaxAttempts > 0 && cd.Attempts >= gamble.Config.MaxAttempts {
+		return false
+	}
+
+	if gamble.Config.Type == GambleTypeLadder && cd.Rung >= len(gamble.Config.Ladder) {
+		return false
+	}
+
+	if gamble.Config.MaxWin > 0 && gamble.getNextWin(cd) > gamble.Config.MaxWin {
+		return false
+	}
+
+	return true
+}
+
+// CalcRTP - 每次 gamble 的 rtp，按 OnPlayGame 里同样的整数概率算，每一级都一样，返回最大的那个
+func (gamble *Gamble) CalcRTP() float64 {
+	cd := &GambleData{}
+	rtp := 0.0
+
+	num := 1
+	if gamble.Config.Type == GambleTypeLadder {
+		num = len(gamble.Config.Ladder)
+	}
+
+	for i := 0; i < num; i++ {
+		cd.Rung = i
+
+		cur, next := gamble.getMul(cd)
+		curRTP := float64(cur*gamble.Config.RTPBP) / float64(next*gambleRTPPrecision) * float64(next) / float64(cur)
+
+		if curRTP > rtp {
+			rtp = curRTP
+		}
+	}
+
+	return rtp
+}
+
+// getRoundWin - 这一局到现在的奖，这一步的还没有算到 curpr.CoinWin 里
+func (gamble *Gamble) getRoundWin(curpr *sgc7game.PlayResult, prs []*sgc7game.PlayResult) int {
+	wins := 0
+
+	for _, v := range prs {
+		wins += v.CoinWin
+	}
+
+	for _, v := range curpr.Results {
+		if !v.IsNoPayNow {
+			wins += v.CoinWin
+		}
+	}
+
+	return wins
+}
+
+// holdWins - 进入 gamble 时，这一局前面的奖都先不付，等 collect 或者 gamble 结束时再一起付
+func (gamble *Gamble) holdWins(curpr *sgc7game.PlayResult, prs []*sgc7game.PlayResult) {
+	for _, v := range curpr.Results {
+		v.IsNoPayNow = true
+	}
+
+	for _, pr := range prs {
+		for _, v := range pr.Results {
+			v.IsNoPayNow = true
+		}
+
+		pr.CashWin = 0
+		pr.CoinWin = 0
+	}
+}
+
+// addWin - 付 CurWin
+func (gamble *Gamble) addWin(curpr *sgc7game.PlayResult, stake *sgc7game.Stake, cd *GambleData) {
+	ret := &sgc7game.Result{
+		Symbol:    -1,
+		Type:      sgc7game.RTBonus,
+		LineIndex: -1,
+		CoinWin:   cd.CurWin,
+		CashWin:   cd.CurWin * int(stake.CoinBet),
+	}
+
+	gamble.AddResult(curpr, ret, &cd.BasicComponentData)
+}
+
+// buildCmdParam -
+func (gamble *Gamble) buildCmdParam(cd *GambleData, option string) (string, error) {
+	param := &GambleCmdParam{
+		Option:   option,
+		BaseWin:  cd.BaseWin,
+		CurWin:   cd.CurWin,
+		Rung:     cd.Rung,
+		Attempts: cd.Attempts,
+		History:  cd.History,
+	}
+
+	buf, err := sonic.Marshal(param)
+	if err != nil {
+		goutils.Error("Gamble.buildCmdParam:Marshal",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	return string(buf), nil
+}
+
+// parseCmdParam - 返回玩家选的 option，
+//
+//	用同一个 gameData 时（比如 wsserv），状态以 GambleData 为准，否则从 GamblePS 里恢复，
+//	param 里的状态必须和服务器的一样，改过的 param 都是非法的
+func (gamble *Gamble) parseCmdParam(cd *GambleData, cps *GamblePS, cmdParam string) (string, error) {
+	param := &GambleCmdParam{}
+
+	err := sonic.Unmarshal([]byte(cmdParam), param)
+	if err != nil {
+		goutils.Error("Gamble.parseCmdParam:Unmarshal",
+			slog.String("param", cmdParam),
+			goutils.Err(ErrInvalidCmdParam))
+
+		return "", ErrInvalidCmdParam
+	}
+
+	if !cd.IsWaiting {
+		if cps == nil || !cps.IsWaiting {
+			goutils.Error("Gamble.parseCmdParam:GamblePS",
+				goutils.Err(ErrInvalidCommand))
+
+			return "", ErrInvalidCommand
+		}
+
+		cd.BaseWin = cps.BaseWin
+		cd.CurWin = cps.CurWin
+		cd.Rung = cps.Rung
+		cd.Attempts = cps.Attempts
+		cd.History = slices.Clone(cps.History)
+		cd.IsWaiting = true
+	}
+
+	if param.BaseWin != cd.BaseWin || param.CurWin != cd.CurWin || param.Rung != cd.Rung ||
+		param.Attempts != cd.Attempts || !slices.Equal(param.History, cd.History) {
+
+		goutils.Error("Gamble.parseCmdParam",
+			slog.String("param", cmdParam),
+			slog.Int("baseWin", cd.BaseWin),
+			slog.Int("curWin", cd.CurWin),
+			goutils.Err(ErrInvalidCmdParam))
+
+		return "", ErrInvalidCmdParam
+	}
+
+	return param.Option, nil
+}
+
+// getPS - 私有的 GamblePS，没有 PlayerState 时返回 nil
+func (gamble *Gamble) getPS(ips sgc7game.IPlayerState, stake *sgc7game.Stake) *GamblePS {
+	ps, isok := ips.(*PlayerState)
+	if !isok || ps == nil || stake.CoinBet <= 0 {
+		return nil
+	}
+
+	cps, isok := ps.GetBetPriCPS(int(stake.CashBet/stake.CoinBet), int(stake.CoinBet), gamble.GetName()).(*GamblePS)
+	if !isok {
+		return nil
+	}
+
+	return cps
+}
+
+// savePS - 在等玩家选时保存状态，结束了就清掉，清掉以后同样的 param 就不能再用了
+func (gamble *Gamble) savePS(cps *GamblePS, cd *GambleData) {
+	if cps == nil {
+		return
+	}
+
+	if !cd.IsWaiting {
+		*cps = GamblePS{}
+
+		return
+	}
+
+	cps.IsWaiting = true
+	cps.BaseWin = cd.BaseWin
+	cps.CurWin = cd.CurWin
+	cps.Rung = cd.Rung
+	cps.Attempts = cd.Attempts
+	cps.History = slices.Clone(cd.History)
+}
+
+// wait - 等玩家选，第一个是 CollectCmd
+func (gamble *Gamble) wait(curpr *sgc7game.PlayResult, cd *GambleData) error {
+	options := gamble.getOptions()
+
+	lstcmd := make([]string, 0, len(options)+1)
+	lstparam := make([]string, 0, len(options)+1)
+
+	param, err := gamble.buildCmdParam(cd, "")
+	if err != nil {
+		return err
+	}
+
+	lstcmd = append(lstcmd, gamble.Config.CollectCmd)
+	lstparam = append(lstparam, param)
+
+	for _, v := range options {
+		param, err := gamble.buildCmdParam(cd, v)
+		if err != nil {
+			return err
+		}
+
+		lstcmd = append(lstcmd, gamble.Config.GambleCmd)
+		lstparam = append(lstparam, param)
+	}
+
+	curpr.NextCmds = lstcmd
+	curpr.NextCmdParams = lstparam
+	curpr.IsFinish = false
+	curpr.IsWait = true
+
+	cd.IsWaiting = true
+
+	return nil
+}
+
+// procGamble - 赢的概率是 rtp * cur / next，所以每次的 rtp 正好是配置的 rtp
+func (gamble *Gamble) procGamble(plugin sgc7plugin.IPlugin, cd *GambleData, param string) error {
+	options := gamble.getOptions()
+	if !slices.Contains(options, param) {
+		goutils.Error("Gamble.procGamble:param",
+			slog.String("param", param),
+			goutils.Err(ErrInvalidCmdParam))
+
+		return ErrInvalidCmdParam
+	}
+
+	cur, next := gamble.getMul(cd)
+
+	cr, err := plugin.Random(sgc7plugin.GetContext(plugin), next*gambleRTPPrecision)
+	if err != nil {
+		goutils.Error("Gamble.procGamble:Random",
+			goutils.Err(err))
+
+		return err
+	}
+
+	isWin := cr < cur*gamble.Config.RTPBP
+
+	card := GambleWin
+	if !isWin {
+		card = GambleLose
+	}
+
+	if gamble.Config.Type != GambleTypeLadder {
+		card = param
+
+		if !isWin {
+			others := slices.DeleteFunc(slices.Clone(options), func(v string) bool { return v == param })
+
+			ci, err := plugin.Random(sgc7plugin.GetContext(plugin), len(others))
+			if err != nil {
+				goutils.Error("Gamble.procGamble:Random",
+					goutils.Err(err))
+
+				return err
+			}
+
+			card = others[ci]
+		}
+	}
+
+	cd.Attempts++
+	cd.CurCard = card
+	cd.History = append(cd.History, card)
+
+	if isWin {
+		cd.CurWin = gamble.getNextWin(cd)
+		cd.Rung++
+	} else {
+		cd.CurWin = 0
+	}
+
+	return nil
+}
+
+// playgame
+func (gamble *Gamble) OnPlayGame(gameProp *GameProperty, curpr *sgc7game.PlayResult, gp *GameParams, plugin sgc7plugin.IPlugin,
+	cmd string, param string, ps sgc7game.IPlayerState, stake *sgc7game.Stake, prs []*sgc7game.PlayResult, icd IComponentData) (string, error) {
+
+	cd, isok := icd.(*GambleData)
+	if !isok {
+		goutils.Error("Gamble.OnPlayGame:invalid icd",
+			goutils.Err(ErrInvalidComponentData))
+
+		return "", ErrInvalidComponentData
+	}
+
+	cd.onNewStep()
+
+	cps := gamble.getPS(ps, stake)
+
+	switch cmd {
+	case gamble.Config.CollectCmd:
+		_, err := gamble.parseCmdParam(cd, cps, param)
+		if err != nil || !cd.IsWaiting || cd.CurWin <= 0 {
+			goutils.Error("Gamble.OnPlayGame:CollectCmd",
+				goutils.Err(ErrInvalidCommand))
+
+			return "", ErrInvalidCommand
+		}
+
+		cd.IsWaiting = false
+		cd.CurCard = GambleCollect
+
+		gamble.savePS(cps, cd)
+		gamble.addWin(curpr, stake, cd)
+
+		nc := gamble.onStepEnd(gameProp, curpr, gp, "")
+
+		return nc, nil
+	case gamble.Config.GambleCmd:
+		option, err := gamble.parseCmdParam(cd, cps, param)
+		if err != nil || !cd.IsWaiting {
+			goutils.Error("Gamble.OnPlayGame:GambleCmd",
+				goutils.Err(ErrInvalidCommand))
+
+			return "", ErrInvalidCommand
+		}
+
+		if !gamble.canGamble(cd) {
+			goutils.Error("Gamble.OnPlayGame:canGamble",
+				slog.Int("attempts", cd.Attempts),
+				slog.Int("curWin", cd.CurWin),
+				goutils.Err(ErrInvalidCommand))
+
+			return "", ErrInvalidCommand
+		}
+
+		err = gamble.procGamble(plugin, cd, option)
+		if err != nil {
+			goutils.Error("Gamble.OnPlayGame:procGamble",
+				goutils.Err(err))
+
+			return "", err
+		}
+
+		if gamble.canGamble(cd) {
+			err = gamble.wait(curpr, cd)
+			if err != nil {
+				goutils.Error("Gamble.OnPlayGame:wait",
+					goutils.Err(err))
+
+				return "", err
+			}
+
+			gamble.savePS(cps, cd)
+
+			return "", nil
+		}
+
+		cd.IsWaiting = false
+		gamble.savePS(cps, cd)
+
+		// 输了就什么都不付，前面的奖在进入 gamble 时就没付
+		if cd.CurWin > 0 {
+			gamble.addWin(curpr, stake, cd)
+		}
+
+		nc := gamble.onStepEnd(gameProp, curpr, gp, "")
+
+		return nc, nil
+	}
+
+	// 还有 respin 没结束，或者 RTP 模式，都不 gamble
+	if gIsRTPMode || len(gameProp.RespinComponents) > 0 {
+		nc := gamble.onStepEnd(gameProp, curpr, gp, "")
+
+		return nc, ErrComponentDoNothing
+	}
+
+	wins := gamble.getRoundWin(curpr, prs)
+
+	cd.BaseWin = wins
+	cd.CurWin = wins
+	cd.Rung = 0
+	cd.Attempts = 0
+	cd.History = nil
+	cd.IsWaiting = false
+
+	if !gamble.canGamble(cd) {
+		nc := gamble.onStepEnd(gameProp, curpr, gp, "")
+
+		return nc, ErrComponentDoNothing
+	}
+
+	err := gamble.wait(curpr, cd)
+	if err != nil {
+		goutils.Error("Gamble.OnPlayGame:wait",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	gamble.holdWins(curpr, prs)
+	gamble.savePS(cps, cd)
+
+	// 等玩家选，这一步到这里结束
+	return "", nil
+}
+
+// OnAsciiGame - outpur to asciigame
+func (gamble *Gamble) OnAsciiGame(gameProp *GameProperty, pr *sgc7game.PlayResult, lst []*sgc7game.PlayResult, mapSymbolColor *asciigame.SymbolColorMap, icd IComponentData) error {
+	cd, isok := icd.(*GambleData)
+	if !isok {
+		goutils.Error("Gamble.OnAsciiGame:invalid icd",
+			goutils.Err(ErrInvalidComponentData))
+
+		return ErrInvalidComponentData
+	}
+
+	fmt.Printf("gamble: name=%s, card=%s, curWin=%v, attempts=%v, history=%v\n",
+		gamble.GetName(),
+		cd.CurCard,
+		cd.CurWin,
+		cd.Attempts,
+		cd.History)
+
+	return nil
+}
+
+// InitPlayerState - 每次下注前把 GamblePS 读出来
+func (gamble *Gamble) InitPlayerState(pool *GamePropertyPool, gameProp *GameProperty, plugin sgc7plugin.IPlugin,
+	ps *PlayerState, betMethod int, bet int) error {
+
+	if bet <= 0 {
+		return nil
+	}
+
+	bps := ps.GetBetMethodPri(betMethod).GetBetPS(bet)
+
+	cname := gamble.GetName()
+
+	_, isok := bps.MapComponentData[cname]
+	if !isok {
+		cps := &GamblePS{}
+
+		str, isok := bps.MapString[cname]
+		if isok {
+			err := cps.SetPrivateJson(str)
+			if err != nil {
+				goutils.Error("Gamble.InitPlayerState:SetPrivateJson",
+					goutils.Err(err))
+
+				return err
+			}
+		}
+
+		bps.MapComponentData[cname] = cps
+	}
+
+	return nil
+}
+
+// NewPlayerState - new IComponentPS
+func (gamble *Gamble) NewPlayerState() IComponentPS {
+	return &GamblePS{}
+}
+
+// NewComponentData -
+func (gamble *Gamble) NewComponentData() IComponentData {
+	return &GambleData{}
+}
+
+// OnStats2
+func (gamble *Gamble) OnStats2(icd IComponentData, s2 *stats2.Cache, gameProp *GameProperty, gp *GameParams, pr *sgc7game.PlayResult, isOnStepEnd bool) {
+	gamble.BasicComponent.OnStats2(icd, s2, gameProp, gp, pr, isOnStepEnd)
+
+	cd, isok := icd.(*GambleData)
+	if !isok {
+		goutils.Error("Gamble.OnStats2:invalid icd",
+			goutils.Err(ErrInvalidComponentData))
+
+		return
+	}
+
+	if cd.CurCard != "" {
+		s2.ProcStatsStrVal(gamble.GetName(), cd.CurCard)
+	}
+}
+
+// NewStats2 -
+func (gamble *Gamble) NewStats2(parent string) *stats2.Feature {
+	return stats2.NewFeature(parent, []stats2.Option{stats2.OptStrVal})
+}
+
+func NewGamble(name string) IComponent {
+	return &Gamble{
+		BasicComponent: NewBasicComponent(name, 0),
+	}
+}
+
+// "type": "colour",
+// "gambleCmd": "GAMBLE",
+// "collectCmd": "COLLECT",
+// "maxWin": 5000,
+// "maxAttempts": 5,
+// "ladder": [2, 3, 5, 8],
+// "rtp": 0.98
+type jsonGamble struct {
+	Type        string  `json:"type"`
+	GambleCmd   string  `json:"gambleCmd"`
+	CollectCmd  string  `json:"collectCmd"`
+	MaxWin      int     `json:"maxWin"`
+	MaxAttempts int     `json:"maxAttempts"`
+	Ladder      []int   `json:"ladder"`
+	RTP         float64 `json:"rtp"`
+}
+
+func (jcfg *jsonGamble) build() *GambleConfig {
+	cfg := &GambleConfig{
+		StrType:     strings.ToLower(jcfg.Type),
+		GambleCmd:   jcfg.GambleCmd,
+		CollectCmd:  jcfg.CollectCmd,
+		MaxWin:      jcfg.MaxWin,
+		MaxAttempts: jcfg.MaxAttempts,
+		Ladder:      slices.Clone(jcfg.Ladder),
+		RTP:         jcfg.RTP,
+	}
+
+	return cfg
+}
+
+func parseGamble(gamecfg *BetConfig, cell *ast.Node) (string, error) {
+	cfg, label, _, err := getConfigInCell(cell)
+	if err != nil {
+		goutils.Error("parseGamble:getConfigInCell",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	buf, err := cfg.MarshalJSON()
+	if err != nil {
+		goutils.Error("parseGamble:MarshalJSON",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	data := &jsonGamble{}
+
+	err = sonic.Unmarshal(buf, data)
+	if err != nil {
+		goutils.Error("parseGamble:Unmarshal",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	cfgd := data.build()
+
+	gamecfg.mapConfig[label] = cfgd
+	gamecfg.mapBasicConfig[label] = &cfgd.BasicComponentConfig
+
+	ccfg := &ComponentConfig{
+		Name: label,
+		Type: GambleTypeName,
+	}
+
+	gamecfg.Components = append(gamecfg.Components, ccfg)
+
+	return label, nil
+}
@@ -0,0 +1,357 @@
+package lowcode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+	"github.com/zhs007/slotsgamecore7/sgc7pb"
+)
+
+func newTestGamble(t *testing.T, cfg *GambleConfig) *Gamble {
+	gamble := NewGamble("gamble").(*Gamble)
+
+	err := gamble.InitEx(cfg, &GamePropertyPool{Config: &Config{}})
+	assert.NoError(t, err)
+
+	return gamble
+}
+
+// newTestWinPlayResult - 前面的组件已经赢了 wins
+func newTestWinPlayResult(wins int) *sgc7game.PlayResult {
+	pr := sgc7game.NewPlayResult("bg", 0, 0, "bg")
+	pr.Results = append(pr.Results, &sgc7game.Result{
+		Type:    sgc7game.RTLine,
+		CoinWin: wins,
+		CashWin: wins,
+	})
+
+	return pr
+}
+
+func sumGambleWins(prs ...*sgc7game.PlayResult) int {
+	wins := 0
+
+	for _, pr := range prs {
+		for _, v := range pr.Results {
+			if !v.IsNoPayNow {
+				wins += v.CoinWin
+			}
+		}
+	}
+
+	return wins
+}
+
+func Test_GambleInitEx(t *testing.T) {
+	pool := &GamePropertyPool{Config: &Config{}}
+
+	gamble := NewGamble("gamble").(*Gamble)
+	err := gamble.InitEx(&GambleConfig{}, pool)
+	assert.NoError(t, err)
+	assert.Equal(t, GambleTypeColour, gamble.Config.Type)
+	assert.Equal(t, 10000, gamble.Config.RTPBP)
+	assert.Equal(t, "gamble", pool.Config.MapCmdComponent[DefaultGambleCmd])
+	assert.Equal(t, "gamble", pool.Config.MapCmdComponent[DefaultCollectCmd])
+	assert.Equal(t, 1.0, gamble.CalcRTP())
+
+	// 命令已经给了别的组件
+	gamble1 := NewGamble("gamble1").(*Gamble)
+	err = gamble1.InitEx(&GambleConfig{}, pool)
+	assert.ErrorIs(t, err, ErrInvalidComponentConfig)
+
+	err = gamble1.InitEx(&GambleConfig{GambleCmd: "G", CollectCmd: "G"}, pool)
+	assert.ErrorIs(t, err, ErrInvalidComponentConfig)
+
+	err = gamble1.InitEx(&GambleConfig{GambleCmd: "G1", CollectCmd: "C1", RTP: 1.01}, pool)
+	assert.ErrorIs(t, err, ErrInvalidComponentConfig)
+
+	err = gamble1.InitEx(&GambleConfig{StrType: "ladder", GambleCmd: "G1", CollectCmd: "C1"}, pool)
+	assert.ErrorIs(t, err, ErrInvalidComponentConfig)
+
+	err = gamble1.InitEx(&GambleConfig{StrType: "ladder", GambleCmd: "G1", CollectCmd: "C1", Ladder: []int{2, 2}}, pool)
+	assert.ErrorIs(t, err, ErrInvalidComponentConfig)
+
+	err = gamble1.InitEx(&GambleConfig{StrType: "ladder", GambleCmd: "G1", CollectCmd: "C1", Ladder: []int{2, 3, 5}, RTP: 0.95}, pool)
+	assert.NoError(t, err)
+	assert.Equal(t, GambleTypeLadder, gamble1.Config.Type)
+	assert.InDelta(t, 0.95, gamble1.CalcRTP(), 1e-9)
+
+	t.Logf("Test_GambleInitEx OK")
+}
+
+func Test_GambleColour(t *testing.T) {
+	gamble := newTestGamble(t, &GambleConfig{MaxAttempts: 3})
+
+	stake := &sgc7game.Stake{CoinBet: 2, CashBet: 20}
+	gameProp := &GameProperty{Pool: &GamePropertyPool{}}
+	gp := NewGameParam(stake, nil)
+	cd := gamble.NewComponentData().(*GambleData)
+	plugin := sgc7plugin.NewMockPlugin()
+
+	// 没有奖就不 gamble
+	pr := sgc7game.NewPlayResult("bg", 0, 0, "bg")
+	_, err := gamble.OnPlayGame(gameProp, pr, gp, plugin, DefaultCmd, "", nil, stake, nil, cd)
+	assert.ErrorIs(t, err, ErrComponentDoNothing)
+	assert.False(t, pr.IsWait)
+
+	// 没在等的时候不能 gamble
+	_, err = gamble.OnPlayGame(gameProp, pr, gp, plugin, DefaultGambleCmd, "red", nil, stake, nil, cd)
+	assert.ErrorIs(t, err, ErrInvalidCommand)
+
+	pr0 := newTestWinPlayResult(10)
+	nc, err := gamble.OnPlayGame(gameProp, pr0, gp, plugin, DefaultCmd, "", nil, stake, nil, cd)
+	assert.NoError(t, err)
+	assert.Equal(t, "", nc)
+	assert.True(t, pr0.IsWait)
+	assert.Equal(t, []string{DefaultCollectCmd, DefaultGambleCmd, DefaultGambleCmd}, pr0.NextCmds)
+	assert.Len(t, pr0.NextCmdParams, 3)
+	assert.Equal(t, 10, cd.CurWin)
+
+	// 前面的奖先不付
+	assert.True(t, pr0.Results[0].IsNoPayNow)
+	assert.Equal(t, 0, sumGambleWins(pr0))
+
+	green, err := gamble.buildCmdParam(cd, "green")
+	assert.NoError(t, err)
+
+	_, err = gamble.OnPlayGame(gameProp, sgc7game.NewPlayResult("bg", 1, 0, "bg"), gp, plugin, DefaultGambleCmd, green, nil, stake, []*sgc7game.PlayResult{pr0}, cd)
+	assert.ErrorIs(t, err, ErrInvalidCmdParam)
+
+	// 赢了 x2，还在等，不付
+	plugin.Cache = []int{0}
+	pr1 := sgc7game.NewPlayResult("bg", 1, 0, "bg")
+	nc, err = gamble.OnPlayGame(gameProp, pr1, gp, plugin, DefaultGambleCmd, pr0.NextCmdParams[1], nil, stake, []*sgc7game.PlayResult{pr0}, cd)
+	assert.NoError(t, err)
+	assert.Equal(t, "", nc)
+	assert.True(t, pr1.IsWait)
+	assert.Equal(t, 20, cd.CurWin)
+	assert.Empty(t, pr1.Results)
+
+	pbcd := cd.BuildPBComponentData().(*sgc7pb.FeaturePickData)
+	assert.Equal(t, []string{"red"}, pbcd.Selected)
+	assert.Equal(t, []string{"red"}, pbcd.CurSelected)
+	assert.Equal(t, int32(20), pbcd.BasicComponentData.Output)
+	assert.Equal(t, int32(1), pbcd.PickNum)
+
+	// 输了，翻出来的是另一种颜色，总奖是 0
+	plugin.Cache = []int{15000, 0}
+	pr2 := sgc7game.NewPlayResult("bg", 2, 0, "bg")
+	nc, err = gamble.OnPlayGame(gameProp, pr2, gp, plugin, DefaultGambleCmd, pr1.NextCmdParams[2], nil, stake, []*sgc7game.PlayResult{pr0, pr1}, cd)
+	assert.NoError(t, err)
+	assert.Equal(t, "", nc)
+	assert.False(t, pr2.IsWait)
+	assert.Equal(t, 0, cd.CurWin)
+	assert.Equal(t, "red", cd.CurCard)
+	assert.Equal(t, []string{"red", "red"}, cd.History)
+	assert.Empty(t, pr2.Results)
+	assert.Equal(t, 0, sumGambleWins(pr0, pr1, pr2))
+
+	_, err = gamble.OnPlayGame(gameProp, sgc7game.NewPlayResult("bg", 3, 0, "bg"), gp, plugin, DefaultCollectCmd, "", nil, stake, nil, cd)
+	assert.ErrorIs(t, err, ErrInvalidCommand)
+
+	cd1 := cd.Clone().(*GambleData)
+	assert.Equal(t, cd.History, cd1.History)
+
+	v, isok := cd.GetValEx(CVNumber, GCVTypeNormal)
+	assert.True(t, isok)
+	assert.Equal(t, 2, v)
+
+	t.Logf("Test_GambleColour OK")
+}
+
+func Test_GambleCollectAndMaxWin(t *testing.T) {
+	gamble := newTestGamble(t, &GambleConfig{StrType: "suit", MaxWin: 100})
+
+	stake := &sgc7game.Stake{CoinBet: 1, CashBet: 10}
+	gameProp := &GameProperty{Pool: &GamePropertyPool{}}
+	gp := NewGameParam(stake, nil)
+	cd := gamble.NewComponentData().(*GambleData)
+	plugin := sgc7plugin.NewMockPlugin()
+
+	pr0 := newTestWinPlayResult(10)
+	_, err := gamble.OnPlayGame(gameProp, pr0, gp, plugin, DefaultCmd, "", nil, stake, nil, cd)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, len(pr0.NextCmds))
+
+	// x4 以后是 40，再 x4 超过 maxWin，直接结束，这时才付
+	plugin.Cache = []int{0}
+	pr1 := sgc7game.NewPlayResult("bg", 1, 0, "bg")
+	_, err = gamble.OnPlayGame(gameProp, pr1, gp, plugin, DefaultGambleCmd, pr0.NextCmdParams[1], nil, stake, nil, cd)
+	assert.NoError(t, err)
+	assert.False(t, pr1.IsWait)
+	assert.Equal(t, 40, cd.CurWin)
+	assert.Equal(t, 40, sumGambleWins(pr0, pr1))
+
+	// 收钱
+	pr0 = newTestWinPlayResult(10)
+	_, err = gamble.OnPlayGame(gameProp, pr0, gp, plugin, DefaultCmd, "", nil, stake, nil, cd)
+	assert.NoError(t, err)
+
+	pr1 = sgc7game.NewPlayResult("bg", 1, 0, "bg")
+	_, err = gamble.OnPlayGame(gameProp, pr1, gp, plugin, DefaultCollectCmd, pr0.NextCmdParams[0], nil, stake, nil, cd)
+	assert.NoError(t, err)
+	assert.False(t, pr1.IsWait)
+	assert.Len(t, pr1.Results, 1)
+	assert.Equal(t, 10, pr1.Results[0].CashWin)
+	assert.Equal(t, GambleCollect, cd.CurCard)
+	assert.Equal(t, 10, cd.CurWin)
+	assert.Equal(t, 10, sumGambleWins(pr0, pr1))
+
+	t.Logf("Test_GambleCollectAndMaxWin OK")
+}
+
+func Test_GambleLadder(t *testing.T) {
+	gamble := newTestGamble(t, &GambleConfig{StrType: "ladder", Ladder: []int{2, 3}, RTP: 0.5})
+
+	stake := &sgc7game.Stake{CoinBet: 1, CashBet: 10}
+	gameProp := &GameProperty{Pool: &GamePropertyPool{}}
+	gp := NewGameParam(stake, nil)
+	cd := gamble.NewComponentData().(*GambleData)
+	plugin := sgc7plugin.NewMockPlugin()
+
+	pr0 := newTestWinPlayResult(10)
+	_, err := gamble.OnPlayGame(gameProp, pr0, gp, plugin, DefaultCmd, "", nil, stake, nil, cd)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{DefaultCollectCmd, DefaultGambleCmd}, pr0.NextCmds)
+
+	// 1 -> 2，赢的概率是 0.5 / 2，range 是 20000，小于 5000 才赢
+	plugin.Cache = []int{4999}
+	pr1 := sgc7game.NewPlayResult("bg", 1, 0, "bg")
+	_, err = gamble.OnPlayGame(gameProp, pr1, gp, plugin, DefaultGambleCmd, pr0.NextCmdParams[1], nil, stake, nil, cd)
+	assert.NoError(t, err)
+	assert.True(t, pr1.IsWait)
+	assert.Equal(t, 20, cd.CurWin)
+	assert.Equal(t, 1, cd.Rung)
+
+	// 2 -> 3，range 是 30000，小于 10000 才赢，到顶了就结束
+	plugin.Cache = []int{9999}
+	pr2 := sgc7game.NewPlayResult("bg", 2, 0, "bg")
+	_, err = gamble.OnPlayGame(gameProp, pr2, gp, plugin, DefaultGambleCmd, pr1.NextCmdParams[1], nil, stake, nil, cd)
+	assert.NoError(t, err)
+	assert.False(t, pr2.IsWait)
+	assert.Equal(t, 30, cd.CurWin)
+	assert.Equal(t, []string{GambleWin, GambleWin}, cd.History)
+	assert.Equal(t, 30, sumGambleWins(pr0, pr1, pr2))
+
+	// 输了
+	pr0 = newTestWinPlayResult(10)
+	_, err = gamble.OnPlayGame(gameProp, pr0, gp, plugin, DefaultCmd, "", nil, stake, nil, cd)
+	assert.NoError(t, err)
+
+	plugin.Cache = []int{5000}
+	pr1 = sgc7game.NewPlayResult("bg", 1, 0, "bg")
+	_, err = gamble.OnPlayGame(gameProp, pr1, gp, plugin, DefaultGambleCmd, pr0.NextCmdParams[1], nil, stake, nil, cd)
+	assert.NoError(t, err)
+	assert.False(t, pr1.IsWait)
+	assert.Equal(t, []string{GambleLose}, cd.History)
+	assert.Equal(t, 0, sumGambleWins(pr0, pr1))
+
+	t.Logf("Test_GambleLadder OK")
+}
+
+// newGambleRequestPS - 模拟一次新的请求，PlayerState 经过 json 传回来
+func newGambleRequestPS(t *testing.T, gamble *Gamble, ps *PlayerState, stake *sgc7game.Stake) *PlayerState {
+	nps := NewPlayerState()
+
+	if ps != nil {
+		ps.OnOutput()
+
+		err := nps.SetPrivateJson(ps.GetPrivateJson())
+		assert.NoError(t, err)
+	}
+
+	err := gamble.InitPlayerState(nil, nil, nil, nps, int(stake.CashBet/stake.CoinBet), int(stake.CoinBet))
+	assert.NoError(t, err)
+
+	return nps
+}
+
+// 每次请求都是新的 gameData，状态从私有的 PlayerState 里恢复
+func Test_GambleWithNewGameData(t *testing.T) {
+	gamble := newTestGamble(t, &GambleConfig{MaxAttempts: 3})
+
+	stake := &sgc7game.Stake{CoinBet: 2, CashBet: 20}
+	gameProp := &GameProperty{Pool: &GamePropertyPool{}}
+	gp := NewGameParam(stake, nil)
+	plugin := sgc7plugin.NewMockPlugin()
+
+	ps0 := newGambleRequestPS(t, gamble, nil, stake)
+	pr0 := newTestWinPlayResult(10)
+	_, err := gamble.OnPlayGame(gameProp, pr0, gp, plugin, DefaultCmd, "", ps0, stake, nil, gamble.NewComponentData())
+	assert.NoError(t, err)
+
+	// 赢了
+	plugin.Cache = []int{0}
+	ps1 := newGambleRequestPS(t, gamble, ps0, stake)
+	cd := gamble.NewComponentData().(*GambleData)
+	pr1 := sgc7game.NewPlayResult("bg", 0, 0, "bg")
+	_, err = gamble.OnPlayGame(gameProp, pr1, gp, plugin, DefaultGambleCmd, pr0.NextCmdParams[1], ps1, stake, nil, cd)
+	assert.NoError(t, err)
+	assert.True(t, pr1.IsWait)
+	assert.Equal(t, 10, cd.BaseWin)
+	assert.Equal(t, 20, cd.CurWin)
+	assert.Equal(t, 1, cd.Attempts)
+	assert.Empty(t, pr1.Results)
+
+	// 改过的 param 不行，baseWin 和 curWin 对得上也不行，要和服务器保存的一样
+	ps2 := newGambleRequestPS(t, gamble, ps1, stake)
+	for _, param := range []string{
+		`{"baseWin":10,"curWin":1000,"attempts":1,"history":["red"]}`,
+		`{"baseWin":500,"curWin":1000,"attempts":1,"history":["red"]}`,
+		pr0.NextCmdParams[0],
+		"",
+	} {
+		cd = gamble.NewComponentData().(*GambleData)
+		_, err = gamble.OnPlayGame(gameProp, sgc7game.NewPlayResult("bg", 0, 0, "bg"), gp, plugin, DefaultCollectCmd,
+			param, ps2, stake, nil, cd)
+		assert.ErrorIs(t, err, ErrInvalidCommand)
+	}
+
+	// 没有 PlayerState 不行
+	cd = gamble.NewComponentData().(*GambleData)
+	_, err = gamble.OnPlayGame(gameProp, sgc7game.NewPlayResult("bg", 0, 0, "bg"), gp, plugin, DefaultCollectCmd,
+		pr1.NextCmdParams[0], nil, stake, nil, cd)
+	assert.ErrorIs(t, err, ErrInvalidCommand)
+
+	// 收钱
+	ps3 := newGambleRequestPS(t, gamble, ps2, stake)
+	cd = gamble.NewComponentData().(*GambleData)
+	pr2 := sgc7game.NewPlayResult("bg", 0, 0, "bg")
+	_, err = gamble.OnPlayGame(gameProp, pr2, gp, plugin, DefaultCollectCmd, pr1.NextCmdParams[0], ps3, stake, nil, cd)
+	assert.NoError(t, err)
+	assert.False(t, pr2.IsWait)
+	assert.Len(t, pr2.Results, 1)
+	assert.Equal(t, 20, pr2.Results[0].CoinWin)
+	assert.Equal(t, 40, pr2.Results[0].CashWin)
+
+	// 收过以后同样的 param 不能再用
+	ps4 := newGambleRequestPS(t, gamble, ps3, stake)
+	cd = gamble.NewComponentData().(*GambleData)
+	_, err = gamble.OnPlayGame(gameProp, sgc7game.NewPlayResult("bg", 0, 0, "bg"), gp, plugin, DefaultCollectCmd,
+		pr1.NextCmdParams[0], ps4, stake, nil, cd)
+	assert.ErrorIs(t, err, ErrInvalidCommand)
+
+	// 输了，什么都不付
+	pr3 := newTestWinPlayResult(10)
+	_, err = gamble.OnPlayGame(gameProp, pr3, gp, plugin, DefaultCmd, "", ps4, stake, nil, gamble.NewComponentData())
+	assert.NoError(t, err)
+
+	plugin.Cache = []int{15000, 0}
+	ps5 := newGambleRequestPS(t, gamble, ps4, stake)
+	cd = gamble.NewComponentData().(*GambleData)
+	pr4 := sgc7game.NewPlayResult("bg", 0, 0, "bg")
+	_, err = gamble.OnPlayGame(gameProp, pr4, gp, plugin, DefaultGambleCmd, pr3.NextCmdParams[1], ps5, stake, nil, cd)
+	assert.NoError(t, err)
+	assert.False(t, pr4.IsWait)
+	assert.Equal(t, 0, cd.CurWin)
+	assert.Equal(t, []string{"black"}, cd.History)
+	assert.Empty(t, pr4.Results)
+
+	cps := gamble.getPS(ps5, stake)
+	assert.NotNil(t, cps)
+	assert.False(t, cps.IsWaiting)
+
+	t.Logf("Test_GambleWithNewGameData OK")
+}
@@ -1,6 +1,9 @@
 package lowcode
 
 import (
+	"context"
+	"log/slog"
+
 	"github.com/bytedance/sonic"
 	"github.com/xuri/excelize/v2"
 	"github.com/zhs007/goutils"
@@ -89,6 +92,29 @@ func (game *Game) CheckStake(stake *sgc7game.Stake) error {
 	return nil
 }
 
+// PlayWithContext - like BasicGame.Play, ctx is passed to the gamemod
+func (game *Game) PlayWithContext(ctx context.Context, plugin sgc7plugin.IPlugin, cmd string, param string, ps sgc7game.IPlayerState,
+	stake *sgc7game.Stake, prs []*sgc7game.PlayResult, gameData any) (*sgc7game.PlayResult, error) {
+
+	curgamemod, isok := game.MapGameMods[ps.GetCurGameMod()]
+	if !isok {
+		goutils.Error("Game.PlayWithContext:MapGameMods[CurGameMod]",
+			slog.String("CurGameMod", ps.GetCurGameMod()),
+			goutils.Err(sgc7game.ErrInvalidGameMod))
+
+		return nil, sgc7game.ErrInvalidGameMod
+	}
+
+	pr, err := sgc7game.OnPlayWithContext(ctx, curgamemod, game.BasicGame, plugin, cmd, param, ps, stake, prs, gameData)
+	if err != nil {
+		return nil, err
+	}
+
+	ps.SetCurGameMod(pr.NextGameMod)
+
+	return pr, nil
+}
+
 // NewPlayerState - new playerstate
 // NewPlayerState 用于 new 一个空的 playerstate，不需要 initial ，后面会 reset 数据
 // Initialize 用于直接 生成一个 playerstate，并初始化它
@@ -141,12 +167,14 @@ func (game *Game) NewGameData(stake *sgc7game.Stake) sgc7game.IGameData {
 	}
 
 	gameProp := pool.Get().(*GameProperty)
+	game.Pool.onGetGameProp(gameProp.CurBetMul)
 
 	return gameProp
 }
 
 // DeleteGameData - delete GameData
 func (game *Game) DeleteGameData(gamed sgc7game.IGameData) {
+	game.Pool.onPutGameProp(gamed.GetBetMul())
 	game.Pool.MapGamePropPool[gamed.GetBetMul()].Put(gamed)
 }
 
@@ -165,10 +193,11 @@ func (game *Game) BuildGameConfigData() error {
 	return nil
 }
 
-func (game *Game) SaveParSheet(f *excelize.File) error {
+// saveStaticParSheet - paytable, reels, linedata and weights
+func (game *Game) saveStaticParSheet(f *excelize.File) error {
 	err := SavePaytable(f, "paytable", game.Cfg.PayTables)
 	if err != nil {
-		goutils.Error("Game.SaveParSheet:SavePaytable",
+		goutils.Error("Game.saveStaticParSheet:SavePaytable",
 			goutils.Err(err))
 
 		return err
@@ -177,7 +206,7 @@ func (game *Game) SaveParSheet(f *excelize.File) error {
 	for rn, r := range game.Pool.Config.MapReels {
 		err = SaveReels(f, rn, game.Cfg.PayTables, r)
 		if err != nil {
-			goutils.Error("Game.SaveParSheet:SaveReels",
+			goutils.Error("Game.saveStaticParSheet:SaveReels",
 				goutils.Err(err))
 
 			return err
@@ -187,7 +216,7 @@ func (game *Game) SaveParSheet(f *excelize.File) error {
 	for ln, l := range game.Pool.Config.MapLinedate {
 		err = SaveLineData(f, ln, l)
 		if err != nil {
-			goutils.Error("Game.SaveParSheet:SaveLineData",
+			goutils.Error("Game.saveStaticParSheet:SaveLineData",
 				goutils.Err(err))
 
 			return err
@@ -197,7 +226,7 @@ func (game *Game) SaveParSheet(f *excelize.File) error {
 	for k, v := range game.Pool.Config.mapStrWeights {
 		err = SaveStrWeights(f, k, v)
 		if err != nil {
-			goutils.Error("Game.SaveParSheet:SaveStrWeights",
+			goutils.Error("Game.saveStaticParSheet:SaveStrWeights",
 				goutils.Err(err))
 
 			return err
@@ -207,7 +236,7 @@ func (game *Game) SaveParSheet(f *excelize.File) error {
 	for k, v := range game.Pool.Config.mapReelSetWeights {
 		err = SaveStrWeights(f, k, v)
 		if err != nil {
-			goutils.Error("Game.SaveParSheet:SaveStrWeights",
+			goutils.Error("Game.saveStaticParSheet:SaveStrWeights",
 				goutils.Err(err))
 
 			return err
@@ -217,7 +246,7 @@ func (game *Game) SaveParSheet(f *excelize.File) error {
 	for k, v := range game.Pool.Config.mapValWeights {
 		err = SaveIntWeights(f, k, v)
 		if err != nil {
-			goutils.Error("Game.SaveParSheet:SaveIntWeights",
+			goutils.Error("Game.saveStaticParSheet:SaveIntWeights",
 				goutils.Err(err))
 
 			return err
@@ -1,7 +1,6 @@
 package lowcode
 
 import (
-	"context"
 	"log/slog"
 	"os"
 
@@ -263,7 +262,7 @@ func (genGigaSymbol *GenGigaSymbol) OnPlayGame(gameProp *GameProperty, curpr *sg
 	}
 
 	if genGigaSymbol.Config.Number == 1 {
-		i, err := plugin.Random(context.Background(), len(lstpos)/2)
+		i, err := plugin.Random(sgc7plugin.GetContext(plugin), len(lstpos)/2)
 		if err != nil {
 			goutils.Error("GenGigaSymbol.OnPlayGame:Random",
 				goutils.Err(err))
@@ -1,7 +1,6 @@
 package lowcode
 
 import (
-	"context"
 	"fmt"
 	"log/slog"
 	"os"
@@ -324,7 +323,7 @@ func (gm *GenMask) OnPlayGame(gameProp *GameProperty, curpr *sgc7game.PlayResult
 				} else if gm.Config.WeightValue <= 0 {
 					nmask[i] = false
 				} else {
-					cr, err := plugin.Random(context.Background(), 10000)
+					cr, err := plugin.Random(sgc7plugin.GetContext(plugin), 10000)
 					if err != nil {
 						goutils.Error("GenMask.OnPlayGame:Random",
 							goutils.Err(err))
@@ -354,7 +353,7 @@ func (gm *GenMask) OnPlayGame(gameProp *GameProperty, curpr *sgc7game.PlayResult
 					} else if gm.Config.WeightValue <= 0 {
 						nmask[i] = false
 					} else {
-						cr, err := plugin.Random(context.Background(), 10000)
+						cr, err := plugin.Random(sgc7plugin.GetContext(plugin), 10000)
 						if err != nil {
 							goutils.Error("GenMask.OnPlayGame:Random",
 								goutils.Err(err))
@@ -1,7 +1,6 @@
 package lowcode
 
 import (
-	"context"
 	"log/slog"
 	"os"
 	"slices"
@@ -359,7 +358,7 @@ func (genPositionCollection *GenPositionCollection) OnPlayGame(gameProp *GamePro
 		}
 
 		for i := 0; i < n; i++ {
-			ri, err := plugin.Random(context.Background(), pos.Len())
+			ri, err := plugin.Random(sgc7plugin.GetContext(plugin), pos.Len())
 			if err != nil {
 				goutils.Error("GenPositionCollection.OnPlayGame:Random",
 					goutils.Err(err))
@@ -400,7 +399,7 @@ func (genPositionCollection *GenPositionCollection) OnPlayGame(gameProp *GamePro
 		}
 
 		for i := 0; i < n; i++ {
-			ri, err := plugin.Random(context.Background(), pos.Len())
+			ri, err := plugin.Random(sgc7plugin.GetContext(plugin), pos.Len())
 			if err != nil {
 				goutils.Error("GenPositionCollection.OnPlayGame:Random",
 					goutils.Err(err))
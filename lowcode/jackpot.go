@@ -87,11 +87,13 @@ func (jackpotData *JackpotData) GetValEx(key string, getType GetComponentValType
 // JackpotConfig - configuration for Jackpot
 type JackpotConfig struct {
 	BasicComponentConfig `yaml:",inline" json:",inline"`
-	BetTypeString        string   `yaml:"betType" json:"betType"`         // bet or totalBet or noPay
-	BetType              BetType  `yaml:"-" json:"-"`                     // bet or totalBet or noPay
-	Wins                 int      `yaml:"wins" json:"wins"`               // wins
-	WinMulti             int      `yaml:"winMulti" json:"winMulti"`       // winMulti，最后的中奖倍数，默认为1
-	Controllers          []*Award `yaml:"controllers" json:"controllers"` // 新的奖励系统
+	BetTypeString        string   `yaml:"betType" json:"betType"`           // bet or totalBet or noPay
+	BetType              BetType  `yaml:"-" json:"-"`                       // bet or totalBet or noPay
+	Wins                 int      `yaml:"wins" json:"wins"`                 // wins
+	WinMulti             int      `yaml:"winMulti" json:"winMulti"`         // winMulti，最后的中奖倍数，默认为1
+	JackpotType          int      `yaml:"jackpotType" json:"jackpotType"`   // 奖池里的第几档，从 1 开始，0 表示固定奖励；不为 0 时由奖池支付，wins 只是用来算 rtp 的平均值
+	Contribution         int      `yaml:"contribution" json:"contribution"` // 这一档从 bet 里抽的比例，单位是 1/10000，只用来统计
+	Controllers          []*Award `yaml:"controllers" json:"controllers"`   // 新的奖励系统
 }
 
 // SetLinkComponent
@@ -142,6 +144,15 @@ func (jackpot *Jackpot) InitEx(cfg any, pool *GamePropertyPool) error {
 		jackpot.Config.WinMulti = 0
 	}
 
+	if jackpot.Config.JackpotType < 0 || jackpot.Config.Contribution < 0 {
+		goutils.Error("Jackpot.InitEx",
+			slog.Int("jackpotType", jackpot.Config.JackpotType),
+			slog.Int("contribution", jackpot.Config.Contribution),
+			goutils.Err(ErrInvalidComponentConfig))
+
+		return ErrInvalidComponentConfig
+	}
+
 	for _, ctrl := range jackpot.Config.Controllers {
 		ctrl.Init()
 	}
@@ -174,6 +185,19 @@ func (jackpot *Jackpot) OnPlayGame(gameProp *GameProperty, curpr *sgc7game.PlayR
 
 	bet := gameProp.GetBet3(stake, jackpot.Config.BetType)
 
+	if jackpot.Config.JackpotType > 0 {
+		// 奖池由服务器支付，这里只标记是哪一档，JackpotCashWin 会被换成奖池里的值
+		curpr.JackpotType = jackpot.Config.JackpotType
+		curpr.JackpotCoinWin += cd.Wins
+		curpr.JackpotCashWin += int64(cd.Wins * bet)
+
+		jackpot.ProcControllers(gameProp, plugin, curpr, gp, -1, "")
+
+		nc := jackpot.onStepEnd(gameProp, curpr, gp, "")
+
+		return nc, nil
+	}
+
 	ret := &sgc7game.Result{
 		Symbol:    -1,
 		Type:      sgc7game.RTBonus,
@@ -263,9 +287,11 @@ func NewJackpot(name string) IComponent {
 // "betType": "bet",
 // "wins": 1000
 type jsonJackpot struct {
-	WinMulti int    `json:"winMulti"`
-	BetType  string `json:"betType"`
-	Wins     int    `json:"wins"`
+	WinMulti     int    `json:"winMulti"`
+	BetType      string `json:"betType"`
+	Wins         int    `json:"wins"`
+	JackpotType  int    `json:"jackpotType"`
+	Contribution int    `json:"contribution"`
 }
 
 func (jwt *jsonJackpot) build() *JackpotConfig {
@@ -273,6 +299,8 @@ func (jwt *jsonJackpot) build() *JackpotConfig {
 		WinMulti:      jwt.WinMulti,
 		BetTypeString: jwt.BetType,
 		Wins:          jwt.Wins,
+		JackpotType:   jwt.JackpotType,
+		Contribution:  jwt.Contribution,
 	}
 
 	// cfg.UseSceneV3 = true
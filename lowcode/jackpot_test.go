@@ -0,0 +1,64 @@
+package lowcode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+)
+
+func Test_JackpotOnPlayGame(t *testing.T) {
+	stake := &sgc7game.Stake{CoinBet: 2, CashBet: 20}
+	gameProp := &GameProperty{Pool: &GamePropertyPool{}}
+	gp := NewGameParam(stake, nil)
+	plugin := sgc7plugin.NewMockPlugin()
+
+	jackpot := NewJackpot("jackpot").(*Jackpot)
+	err := jackpot.InitEx(&JackpotConfig{
+		BasicComponentConfig: BasicComponentConfig{DefaultNextComponent: "next"},
+		BetTypeString:        "bet",
+		Wins:                 100,
+	}, nil)
+	assert.NoError(t, err)
+
+	cd := jackpot.NewComponentData().(*JackpotData)
+	cd.OnNewGame(gameProp, jackpot)
+
+	// 固定奖励
+	pr := sgc7game.NewPlayResult("bg", 0, 0, "bg")
+	nc, err := jackpot.OnPlayGame(gameProp, pr, gp, plugin, DefaultCmd, "", nil, stake, nil, cd)
+	assert.NoError(t, err)
+	assert.Equal(t, "next", nc)
+	assert.Len(t, pr.Results, 1)
+	assert.Equal(t, 200, pr.Results[0].CashWin)
+	assert.Equal(t, 0, pr.JackpotType)
+
+	// 奖池
+	jackpot = NewJackpot("jackpot").(*Jackpot)
+	err = jackpot.InitEx(&JackpotConfig{
+		BasicComponentConfig: BasicComponentConfig{DefaultNextComponent: "next"},
+		BetTypeString:        "bet",
+		Wins:                 100,
+		JackpotType:          2,
+		Contribution:         150,
+	}, nil)
+	assert.NoError(t, err)
+
+	cd = jackpot.NewComponentData().(*JackpotData)
+	cd.OnNewGame(gameProp, jackpot)
+
+	pr = sgc7game.NewPlayResult("bg", 0, 0, "bg")
+	nc, err = jackpot.OnPlayGame(gameProp, pr, gp, plugin, DefaultCmd, "", nil, stake, nil, cd)
+	assert.NoError(t, err)
+	assert.Equal(t, "next", nc)
+	assert.Empty(t, pr.Results)
+	assert.Equal(t, 2, pr.JackpotType)
+	assert.Equal(t, 100, pr.JackpotCoinWin)
+	assert.Equal(t, int64(200), pr.JackpotCashWin)
+
+	err = NewJackpot("jackpot").InitEx(&JackpotConfig{JackpotType: -1}, nil)
+	assert.ErrorIs(t, err, ErrInvalidComponentConfig)
+
+	t.Logf("Test_JackpotOnPlayGame OK")
+}
@@ -140,4 +140,12 @@ func init() {
 	gJsonMgr.RegLoadComponent(strings.ToLower(GenTropiCoolSPSymbolsTypeName), parseGenTropiCoolSPSymbols)
 	gJsonMgr.RegLoadComponent(strings.ToLower(TropiCoolSPBonusTypeName), parseTropiCoolSPBonus)
 	gJsonMgr.RegLoadComponent(strings.ToLower(CPCoreTypeName), parseCPCore)
+	gJsonMgr.RegLoadComponent(strings.ToLower(PlayerPickTypeName), parsePlayerPick)
+	gJsonMgr.RegLoadComponent(strings.ToLower(GambleTypeName), parseGamble)
+	gJsonMgr.RegLoadComponent(strings.ToLower(DynamicHeightReelsTypeName), parseDynamicHeightReels)
+	gJsonMgr.RegLoadComponent(strings.ToLower(StickySymbolsTypeName), parseStickySymbols)
+	gJsonMgr.RegLoadComponent(strings.ToLower(TrailBoardTypeName), parseTrailBoard)
+	gJsonMgr.RegLoadComponent(strings.ToLower(WheelBonusTypeName), parseWheelBonus)
+	gJsonMgr.RegLoadComponent(strings.ToLower(SymbolUpgradeTypeName), parseSymbolUpgrade)
+	gJsonMgr.RegLoadComponent(strings.ToLower(PositionMultiplierGridTypeName), parsePositionMultiplierGrid)
 }
@@ -0,0 +1,586 @@
+package lowcode
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+	"github.com/zhs007/goutils"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	"github.com/zhs007/slotsgamecore7/mathtoolset"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+	sgc7rtp "github.com/zhs007/slotsgamecore7/rtp"
+	"github.com/zhs007/slotsgamecore7/stats2"
+)
+
+// ParSheetVIConfidence - z value for the volatility index (90%)
+const ParSheetVIConfidence = 1.645
+
+// ParSheetCIConfidence - z value for the rtp confidence interval (95%)
+const ParSheetCIConfidence = 1.96
+
+// parSheetAnalytic - analytic result for a paying trigger on a reel set
+type parSheetAnalytic struct {
+	reels     string
+	component string
+	stt       SymbolTriggerType
+	ssws      *mathtoolset.SymbolsWinsStats
+}
+
+func (analytic *parSheetAnalytic) rtp() float64 {
+	if analytic.ssws.TotalBet <= 0 {
+		return 0
+	}
+
+	return float64(analytic.ssws.TotalWins) / float64(analytic.ssws.TotalBet)
+}
+
+// getPayTriggerConfig - 只分析读paytable赔付的线、ways和scatter
+func getPayTriggerConfig(ic IComponent) (SymbolTriggerType, []int, []int, int, BetType, bool) {
+	switch c := ic.(type) {
+	case *LinesTrigger:
+		return c.Config.TriggerType, c.Config.SymbolCodes, c.Config.WildSymbolCodes, c.Config.WinMulti, c.Config.BetType, c.Config.TriggerType == STTypeLines
+	case *WaysTrigger:
+		return c.Config.TriggerType, c.Config.SymbolCodes, c.Config.WildSymbolCodes, c.Config.WinMulti, c.Config.BetType, c.Config.TriggerType == STTypeWays
+	case *ScatterTrigger:
+		return c.Config.TriggerType, c.Config.SymbolCodes, c.Config.WildSymbolCodes, c.Config.WinMulti, c.Config.BetType, c.Config.TriggerType == STTypeScatters
+	}
+
+	return STTypeUnknow, nil, nil, 0, BTypeNoPay, false
+}
+
+func toSymbolTypes(pt *sgc7game.PayTables, codes []int) []mathtoolset.SymbolType {
+	if len(codes) == 0 {
+		for _, v := range pt.MapSymbols {
+			if _, isok := pt.MapPay[v]; isok {
+				codes = append(codes, v)
+			}
+		}
+
+		sort.Ints(codes)
+	}
+
+	lst := make([]mathtoolset.SymbolType, 0, len(codes))
+	for _, v := range codes {
+		lst = append(lst, mathtoolset.SymbolType(v))
+	}
+
+	return lst
+}
+
+// analyzeParSheet - 用 mathtoolset 计算每套轮带上，每个赔付组件的理论命中次数和rtp
+func (game *Game) analyzeParSheet(bet int) ([]*parSheetAnalytic, error) {
+	components, isok := game.Pool.mapComponents[bet]
+	if !isok {
+		goutils.Error("Game.analyzeParSheet",
+			slog.Int("bet", bet),
+			goutils.Err(ErrInvalidBet))
+
+		return nil, ErrInvalidBet
+	}
+
+	pt := game.Cfg.PayTables
+	lst := []*parSheetAnalytic{}
+
+	reelsNames := []string{}
+	for rn := range game.Pool.Config.MapReels {
+		reelsNames = append(reelsNames, rn)
+	}
+
+	sort.Strings(reelsNames)
+
+	for _, ic := range components.Components {
+		stt, symbolCodes, wildCodes, winMulti, bt, canAnalyze := getPayTriggerConfig(ic)
+		if !canAnalyze || bt == BTypeNoPay {
+			continue
+		}
+
+		if winMulti <= 0 {
+			winMulti = 1
+		}
+
+		symbols := toSymbolTypes(pt, symbolCodes)
+
+		var wilds []mathtoolset.SymbolType
+		if len(wildCodes) > 0 {
+			wilds = toSymbolTypes(pt, wildCodes)
+		}
+
+		for _, rn := range reelsNames {
+			rd := game.Pool.Config.MapReels[rn]
+
+			var ssws *mathtoolset.SymbolsWinsStats
+			var err error
+
+			switch stt {
+			case STTypeLines:
+				ld := game.Pool.Config.GetDefaultLineData()
+				if ld == nil {
+					continue
+				}
+
+				ssws, err = mathtoolset.AnalyzeReelsWithLine(pt, rd, symbols, wilds, nil, bet, len(ld.Lines))
+			case STTypeWays:
+				ssws, err = mathtoolset.AnalyzeReelsWaysEx3(pt, rd, symbols, wilds, mathtoolset.NewSymbolMapping(), nil, nil,
+					game.Pool.Config.Height, 1, bet)
+			case STTypeScatters:
+				ssws, err = mathtoolset.AnalyzeReelsScatter(pt, rd, symbols, nil, game.Pool.Config.Height)
+				if err == nil {
+					ssws.TotalBet *= int64(bet)
+				}
+			}
+
+			if err != nil {
+				goutils.Error("Game.analyzeParSheet:Analyze",
+					slog.String("reels", rn),
+					slog.String("component", ic.GetName()),
+					goutils.Err(err))
+
+				return nil, err
+			}
+
+			if winMulti > 1 {
+				ssws.TotalWins *= int64(winMulti)
+
+				for _, sws := range ssws.MapSymbols {
+					for i := range sws.Wins {
+						sws.Wins[i] *= int64(winMulti)
+					}
+				}
+			}
+
+			lst = append(lst, &parSheetAnalytic{
+				reels:     rn,
+				component: ic.GetName(),
+				stt:       stt,
+				ssws:      ssws,
+			})
+		}
+	}
+
+	return lst, nil
+}
+
+func saveParSheetAnalytic(f *excelize.File, sheet string, pt *sgc7game.PayTables, lst []*parSheetAnalytic) error {
+	_, err := f.NewSheet(sheet)
+	if err != nil {
+		goutils.Error("saveParSheetAnalytic.NewSheet",
+			slog.String("sheet", sheet),
+			goutils.Err(err))
+
+		return err
+	}
+
+	y := 0
+	for _, analytic := range lst {
+		f.SetCellStr(sheet, goutils.Pos2Cell(0, y), "reels")
+		f.SetCellStr(sheet, goutils.Pos2Cell(1, y), analytic.reels)
+		f.SetCellStr(sheet, goutils.Pos2Cell(2, y), "component")
+		f.SetCellStr(sheet, goutils.Pos2Cell(3, y), analytic.component)
+		f.SetCellStr(sheet, goutils.Pos2Cell(4, y), "combinations")
+		f.SetCellValue(sheet, goutils.Pos2Cell(5, y), analytic.ssws.TotalBet)
+		f.SetCellStr(sheet, goutils.Pos2Cell(6, y), "rtp")
+		f.SetCellValue(sheet, goutils.Pos2Cell(7, y), analytic.rtp())
+		y++
+
+		num := analytic.ssws.Num
+
+		f.SetCellStr(sheet, goutils.Pos2Cell(0, y), "symbol")
+		for i := 0; i < num; i++ {
+			f.SetCellStr(sheet, goutils.Pos2Cell(1+i, y), fmt.Sprintf("hits X%v", i+1))
+			f.SetCellStr(sheet, goutils.Pos2Cell(1+num+i, y), fmt.Sprintf("rtp X%v", i+1))
+		}
+		f.SetCellStr(sheet, goutils.Pos2Cell(1+num*2, y), "rtp")
+		y++
+
+		for _, s := range analytic.ssws.Symbols {
+			sws := analytic.ssws.MapSymbols[s]
+
+			f.SetCellStr(sheet, goutils.Pos2Cell(0, y), pt.GetStringFromInt(int(s)))
+
+			symbolWins := int64(0)
+			for i := 0; i < num; i++ {
+				f.SetCellValue(sheet, goutils.Pos2Cell(1+i, y), sws.WinsNum[i])
+
+				if analytic.ssws.TotalBet > 0 {
+					f.SetCellValue(sheet, goutils.Pos2Cell(1+num+i, y), float64(sws.Wins[i])/float64(analytic.ssws.TotalBet))
+				} else {
+					f.SetCellValue(sheet, goutils.Pos2Cell(1+num+i, y), 0)
+				}
+
+				symbolWins += sws.Wins[i]
+			}
+
+			if analytic.ssws.TotalBet > 0 {
+				f.SetCellValue(sheet, goutils.Pos2Cell(1+num*2, y), float64(symbolWins)/float64(analytic.ssws.TotalBet))
+			} else {
+				f.SetCellValue(sheet, goutils.Pos2Cell(1+num*2, y), 0)
+			}
+
+			y++
+		}
+
+		y++
+	}
+
+	return nil
+}
+
+func getSortedBets(mapStats map[int]*stats2.Stats) []int {
+	bets := []int{}
+	for bet := range mapStats {
+		bets = append(bets, bet)
+	}
+
+	sort.Ints(bets)
+
+	return bets
+}
+
+func (game *Game) saveParSheetSummary(f *excelize.File, sheet string, mapStats map[int]*stats2.Stats) error {
+	_, err := f.NewSheet(sheet)
+	if err != nil {
+		goutils.Error("Game.saveParSheetSummary.NewSheet",
+			slog.String("sheet", sheet),
+			goutils.Err(err))
+
+		return err
+	}
+
+	f.SetCellStr(sheet, goutils.Pos2Cell(0, 0), "name")
+	f.SetCellStr(sheet, goutils.Pos2Cell(1, 0), game.Pool.Config.Name)
+	f.SetCellStr(sheet, goutils.Pos2Cell(0, 1), "width")
+	f.SetCellValue(sheet, goutils.Pos2Cell(1, 1), game.Pool.Config.Width)
+	f.SetCellStr(sheet, goutils.Pos2Cell(0, 2), "height")
+	f.SetCellValue(sheet, goutils.Pos2Cell(1, 2), game.Pool.Config.Height)
+
+	header := []string{"bet method", "total bet in wins", "spin times", "total bet", "total wins", "rtp",
+		"hit rate", "SD", "volatility index (90%)", "confidence interval (95%)", "max wins", "max wins (x bet)", "times of the max wins"}
+
+	for i, v := range header {
+		f.SetCellStr(sheet, goutils.Pos2Cell(i, 4), v)
+	}
+
+	y := 5
+	for i, bet := range game.Pool.Config.Bets {
+		f.SetCellValue(sheet, goutils.Pos2Cell(0, y), bet)
+		if i < len(game.Pool.Config.TotalBetInWins) {
+			f.SetCellValue(sheet, goutils.Pos2Cell(1, y), game.Pool.Config.TotalBetInWins[i])
+		}
+
+		s2, isok := mapStats[bet]
+		if isok && s2 != nil && s2.BetEndingTimes > 0 && s2.TotalBet > 0 {
+			f.SetCellValue(sheet, goutils.Pos2Cell(2, y), s2.BetEndingTimes)
+			f.SetCellValue(sheet, goutils.Pos2Cell(3, y), s2.TotalBet)
+			f.SetCellValue(sheet, goutils.Pos2Cell(4, y), s2.TotalWins)
+			f.SetCellValue(sheet, goutils.Pos2Cell(5, y), float64(s2.TotalWins)/float64(s2.TotalBet))
+			f.SetCellValue(sheet, goutils.Pos2Cell(6, y), 1-float64(s2.Wins.MapWinTimes[0])/float64(s2.BetEndingTimes))
+
+			curbet := s2.TotalBet / s2.BetTimes
+			sd := s2.Wins.CalcSD(int(curbet))
+			f.SetCellValue(sheet, goutils.Pos2Cell(7, y), sd)
+			f.SetCellValue(sheet, goutils.Pos2Cell(8, y), sd*ParSheetVIConfidence)
+			f.SetCellValue(sheet, goutils.Pos2Cell(9, y), sd*ParSheetCIConfidence/math.Sqrt(float64(s2.BetEndingTimes)))
+			f.SetCellValue(sheet, goutils.Pos2Cell(10, y), s2.MaxWins)
+			f.SetCellValue(sheet, goutils.Pos2Cell(11, y), float64(s2.MaxWins)/float64(curbet))
+			f.SetCellValue(sheet, goutils.Pos2Cell(12, y), s2.MaxWinTimes)
+		}
+
+		y++
+	}
+
+	return nil
+}
+
+// saveParSheetFeatures - 每个组件的触发概率、期望价值和rtp贡献
+func (game *Game) saveParSheetFeatures(f *excelize.File, sheet string, mapStats map[int]*stats2.Stats) error {
+	_, err := f.NewSheet(sheet)
+	if err != nil {
+		goutils.Error("Game.saveParSheetFeatures.NewSheet",
+			slog.String("sheet", sheet),
+			goutils.Err(err))
+
+		return err
+	}
+
+	header := []string{"bet method", "component", "component type", "bet type", "parent", "parent run times", "trigger times",
+		"trigger chance", "1 in", "total wins", "avg wins for per trigger (x bet)", "rtp", "percent of total rtp"}
+
+	for i, v := range header {
+		f.SetCellStr(sheet, goutils.Pos2Cell(i, 0), v)
+	}
+
+	y := 1
+	for _, bet := range getSortedBets(mapStats) {
+		s2 := mapStats[bet]
+		if s2 == nil || s2.BetTimes <= 0 {
+			continue
+		}
+
+		curbet := float64(s2.TotalBet) / float64(s2.BetTimes)
+
+		for _, cn := range s2.Components {
+			f2, isok := s2.MapStats[cn]
+			if !isok {
+				continue
+			}
+
+			triggerTimes := int64(0)
+			totalWins := int64(0)
+			hasWins := false
+
+			if f2.RootTrigger != nil {
+				triggerTimes = f2.RootTrigger.TriggerTimes
+				totalWins = f2.RootTrigger.TotalWins
+				hasWins = true
+			} else if f2.Trigger != nil {
+				triggerTimes = f2.Trigger.TriggerTimes
+			}
+
+			if f2.Wins != nil && !hasWins {
+				totalWins = f2.Wins.TotalWin
+				hasWins = true
+			}
+
+			parentTimes := s2.GetRunTimes(f2.Parent)
+
+			f.SetCellValue(sheet, goutils.Pos2Cell(0, y), bet)
+			f.SetCellStr(sheet, goutils.Pos2Cell(1, y), cn)
+
+			betCfg, isok := game.Pool.Config.MapBetConfigs[bet]
+			if isok {
+				basicCfg, isok := betCfg.mapBasicConfig[cn]
+				if isok {
+					f.SetCellStr(sheet, goutils.Pos2Cell(2, y), basicCfg.ComponentType)
+				}
+			}
+
+			components, isok := game.Pool.mapComponents[bet]
+			if isok {
+				ic, isok := components.MapComponents[cn]
+				if isok {
+					_, _, _, _, bt, isPay := getPayTriggerConfig(ic)
+					if isPay {
+						f.SetCellStr(sheet, goutils.Pos2Cell(3, y), betType2String(bt))
+					}
+				}
+			}
+
+			f.SetCellStr(sheet, goutils.Pos2Cell(4, y), f2.Parent)
+			f.SetCellValue(sheet, goutils.Pos2Cell(5, y), parentTimes)
+			f.SetCellValue(sheet, goutils.Pos2Cell(6, y), triggerTimes)
+
+			if parentTimes > 0 && triggerTimes > 0 {
+				f.SetCellValue(sheet, goutils.Pos2Cell(7, y), float64(triggerTimes)/float64(parentTimes))
+				f.SetCellValue(sheet, goutils.Pos2Cell(8, y), float64(parentTimes)/float64(triggerTimes))
+			}
+
+			if hasWins {
+				f.SetCellValue(sheet, goutils.Pos2Cell(9, y), totalWins)
+
+				if triggerTimes > 0 {
+					f.SetCellValue(sheet, goutils.Pos2Cell(10, y), float64(totalWins)/float64(triggerTimes)/curbet)
+				}
+
+				if s2.TotalBet > 0 {
+					f.SetCellValue(sheet, goutils.Pos2Cell(11, y), float64(totalWins)/float64(s2.TotalBet))
+				}
+
+				if s2.TotalWins > 0 {
+					f.SetCellValue(sheet, goutils.Pos2Cell(12, y), float64(totalWins)/float64(s2.TotalWins))
+				}
+			}
+
+			y++
+		}
+	}
+
+	return nil
+}
+
+func betType2String(bt BetType) string {
+	switch bt {
+	case BTypeBet:
+		return "bet"
+	case BTypeTotalBet:
+		return "totalBet"
+	}
+
+	return "noPay"
+}
+
+// SaveParSheet - 保留原来的接口，只输出静态数据和理论值，等同于 SaveParSheetEx(f, nil)
+func (game *Game) SaveParSheet(f *excelize.File) error {
+	return game.SaveParSheetEx(f, nil)
+}
+
+// SaveParSheetEx - 静态数据、mathtoolset 的理论值和 stats2 的模拟结果，mapStats 是 bet -> stats2，可以为空
+func (game *Game) SaveParSheetEx(f *excelize.File, mapStats map[int]*stats2.Stats) error {
+	mapAnalytic := make(map[int][]*parSheetAnalytic)
+	for _, bet := range game.Pool.Config.Bets {
+		lst, err := game.analyzeParSheet(bet)
+		if err != nil {
+			goutils.Error("Game.SaveParSheetEx:analyzeParSheet",
+				slog.Int("bet", bet),
+				goutils.Err(err))
+
+			return err
+		}
+
+		mapAnalytic[bet] = lst
+	}
+
+	err := game.saveParSheetSummary(f, "summary", mapStats)
+	if err != nil {
+		goutils.Error("Game.SaveParSheetEx:saveParSheetSummary",
+			goutils.Err(err))
+
+		return err
+	}
+
+	err = game.saveStaticParSheet(f)
+	if err != nil {
+		goutils.Error("Game.SaveParSheetEx:saveStaticParSheet",
+			goutils.Err(err))
+
+		return err
+	}
+
+	for i, bet := range game.Pool.Config.Bets {
+		sheet := "analytic"
+		if i > 0 {
+			sheet = fmt.Sprintf("analytic - %v", bet)
+		}
+
+		err = saveParSheetAnalytic(f, sheet, game.Cfg.PayTables, mapAnalytic[bet])
+		if err != nil {
+			goutils.Error("Game.SaveParSheetEx:saveParSheetAnalytic",
+				slog.Int("bet", bet),
+				goutils.Err(err))
+
+			return err
+		}
+	}
+
+	if len(mapStats) > 0 {
+		err = game.saveParSheetFeatures(f, "features", mapStats)
+		if err != nil {
+			goutils.Error("Game.SaveParSheetEx:saveParSheetFeatures",
+				goutils.Err(err))
+
+			return err
+		}
+
+		for _, bet := range getSortedBets(mapStats) {
+			s2 := mapStats[bet]
+			if s2 == nil || s2.BetTimes <= 0 {
+				continue
+			}
+
+			sheet := fmt.Sprintf("win distribution - %v", bet)
+			f.NewSheet(sheet)
+
+			s2.Wins.SaveSheet(f, sheet, s2)
+		}
+	}
+
+	return nil
+}
+
+// getParSheetStats2 - 等模拟结束后取这个 bet 的 stats2，没开 stats2 时返回 nil
+func (game *Game) getParSheetStats2(bet int) (*stats2.Stats, error) {
+	components, isok := game.Pool.mapComponents[bet]
+	if !isok {
+		goutils.Error("Game.getParSheetStats2",
+			slog.Int("bet", bet),
+			goutils.Err(ErrInvalidBet))
+
+		return nil, ErrInvalidBet
+	}
+
+	if components.Stats2 == nil {
+		return nil, nil
+	}
+
+	components.Stats2.WaitEnding()
+
+	return components.Stats2, nil
+}
+
+// BuildParSheet - 从游戏配置生成完整的 par sheet，spinnums 为 0 时只输出静态数据和理论值
+func BuildParSheet(gamecfg string, icore int, ispinnums int64, fn string, funcNewRNG FuncNewRNG, funcNewFeatureLevel FuncNewFeatureLevel, wincap int64) error {
+	sgc7plugin.IsNoRNGCache = true
+
+	if ispinnums > 0 {
+		SetAllowStatsV2()
+	}
+
+	if wincap > 0 {
+		stats2.SetWinCap(int(wincap))
+	}
+
+	game, err := NewGame2(gamecfg, func() sgc7plugin.IPlugin {
+		return sgc7plugin.NewFastPlugin()
+	}, funcNewRNG, funcNewFeatureLevel)
+	if err != nil {
+		goutils.Error("BuildParSheet:NewGame2",
+			slog.String("gamecfg", gamecfg),
+			goutils.Err(err))
+
+		return err
+	}
+
+	mapStats := make(map[int]*stats2.Stats)
+
+	if ispinnums > 0 {
+		for _, bet := range game.Pool.Config.Bets {
+			rtp := sgc7rtp.NewRTP()
+
+			stake := &sgc7game.Stake{
+				CoinBet:  1,
+				CashBet:  int64(bet),
+				Currency: "EUR",
+			}
+
+			d := sgc7rtp.StartRTP3(game, rtp, icore, ispinnums, stake, 100000, func(totalnums int64, curnums int64, curtime time.Duration, curwin int64, curbet int64) {
+				goutils.Info(fmt.Sprintf("Iterations: %v\t\t | Total Won: %v\t\t | Total Bet: %v\t\t | Current RTP: %v%%\n", curnums, curwin, curbet, float64(curwin)*100/float64(curbet)),
+					slog.String("cost time", curtime.String()))
+			}, true, wincap)
+
+			goutils.Info("BuildParSheet:StartRTP3",
+				slog.Int("bet", bet),
+				slog.Float64("rtp", float64(rtp.TotalWins)/float64(rtp.TotalBet)),
+				slog.Duration("cost time", d))
+
+			s2, err := game.getParSheetStats2(bet)
+			if err != nil {
+				goutils.Error("BuildParSheet:getParSheetStats2",
+					slog.Int("bet", bet),
+					goutils.Err(err))
+
+				return err
+			}
+
+			if s2 != nil {
+				mapStats[bet] = s2
+			}
+		}
+	}
+
+	f := excelize.NewFile()
+
+	err = game.SaveParSheetEx(f, mapStats)
+	if err != nil {
+		goutils.Error("BuildParSheet:SaveParSheetEx",
+			goutils.Err(err))
+
+		return err
+	}
+
+	f.DeleteSheet(f.GetSheetName(0))
+
+	return f.SaveAs(fn)
+}
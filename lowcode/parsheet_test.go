@@ -0,0 +1,51 @@
+package lowcode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+)
+
+func Test_SaveParSheet(t *testing.T) {
+	game, err := NewGame2("../unittestdata/testgame.json", func() sgc7plugin.IPlugin {
+		return sgc7plugin.NewFastPlugin()
+	}, NewBasicRNG, NewEmptyFeatureLevel)
+	assert.NoError(t, err)
+	assert.NotNil(t, game)
+
+	f := excelize.NewFile()
+
+	err = game.SaveParSheetEx(f, nil)
+	assert.NoError(t, err)
+
+	sheets := f.GetSheetList()
+	assert.Contains(t, sheets, "summary")
+	assert.Contains(t, sheets, "paytable")
+	assert.Contains(t, sheets, "analytic")
+	assert.NotContains(t, sheets, "features")
+
+	name, err := f.GetCellValue("summary", "B1")
+	assert.NoError(t, err)
+	assert.Equal(t, game.Pool.Config.Name, name)
+
+	lst, err := game.analyzeParSheet(game.Pool.Config.Bets[0])
+	assert.NoError(t, err)
+
+	for _, v := range lst {
+		assert.True(t, v.rtp() >= 0)
+	}
+
+	f1 := excelize.NewFile()
+
+	err = game.SaveParSheet(f1)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, sheets, f1.GetSheetList())
+
+	s2, err := game.getParSheetStats2(-1)
+	assert.ErrorIs(t, err, ErrInvalidBet)
+	assert.Nil(t, s2)
+
+	t.Logf("Test_SaveParSheet OK")
+}
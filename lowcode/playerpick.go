@@ -0,0 +1,744 @@
+package lowcode
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/bytedance/sonic"
+	"github.com/bytedance/sonic/ast"
+	"github.com/zhs007/goutils"
+	"github.com/zhs007/slotsgamecore7/asciigame"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+	"github.com/zhs007/slotsgamecore7/sgc7pb"
+	"github.com/zhs007/slotsgamecore7/stats2"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v2"
+)
+
+const PlayerPickTypeName = "playerPick"
+
+const (
+	// PlayerPickAwardAny - 每次选中都会触发
+	PlayerPickAwardAny = "<any>"
+	// PlayerPickAwardEnd - 选择结束时触发
+	PlayerPickAwardEnd = "<end>"
+)
+
+// PlayerPickPS - 等玩家选的时候，board 放在私有的 PlayerState 里，每次请求都可以用新的 gameData
+type PlayerPickPS struct {
+	IsWaiting bool     `json:"isWaiting"`
+	Board     []string `json:"board,omitempty"`
+	Picked    []int    `json:"picked,omitempty"`
+	PickNum   int      `json:"pickNum"`
+}
+
+// SetPublicJson
+func (ps *PlayerPickPS) SetPublicJson(str string) error {
+	return nil
+}
+
+// SetPrivateJson
+func (ps *PlayerPickPS) SetPrivateJson(str string) error {
+	err := sonic.UnmarshalString(str, ps)
+	if err != nil {
+		goutils.Error("PlayerPickPS.SetPrivateJson:UnmarshalString",
+			goutils.Err(err))
+
+		return err
+	}
+
+	return nil
+}
+
+// GetPublicJson
+func (ps *PlayerPickPS) GetPublicJson() string {
+	return ""
+}
+
+// GetPrivateJson
+func (ps *PlayerPickPS) GetPrivateJson() string {
+	str, err := sonic.MarshalString(ps)
+	if err != nil {
+		goutils.Error("PlayerPickPS.GetPrivateJson:MarshalString",
+			goutils.Err(err))
+
+		return ""
+	}
+
+	return str
+}
+
+// Clone
+func (ps *PlayerPickPS) Clone() IComponentPS {
+	return &PlayerPickPS{
+		IsWaiting: ps.IsWaiting,
+		Board:     slices.Clone(ps.Board),
+		Picked:    slices.Clone(ps.Picked),
+		PickNum:   ps.PickNum,
+	}
+}
+
+// PlayerPickData - 服务器先生成整个 board，但只把选中的部分发给前端，结束以后才会全部发给前端
+type PlayerPickData struct {
+	BasicComponentData
+	Board     []string // 隐藏的结果，没结束时不会发给前端
+	Picked    []int    // 选过的位置，按选择的顺序
+	CurPicked []int    // 这一步选的位置
+	PickNum   int      // 最多选几次，0 表示选到 pooper 或者选完
+	IsEnded   bool
+}
+
+// OnNewGame -
+func (playerPickData *PlayerPickData) OnNewGame(gameProp *GameProperty, component IComponent) {
+	playerPickData.BasicComponentData.OnNewGame(gameProp, component)
+
+	playerPickData.Board = nil
+	playerPickData.Picked = nil
+	playerPickData.CurPicked = nil
+	playerPickData.PickNum = 0
+	playerPickData.IsEnded = false
+}
+
+// onNewStep -
+func (playerPickData *PlayerPickData) onNewStep() {
+	playerPickData.CurPicked = nil
+}
+
+// isWaiting - board 已经生成，还没选完
+func (playerPickData *PlayerPickData) isWaiting() bool {
+	return playerPickData.Board != nil && !playerPickData.IsEnded
+}
+
+// Clone
+func (playerPickData *PlayerPickData) Clone() IComponentData {
+	target := &PlayerPickData{
+		BasicComponentData: playerPickData.CloneBasicComponentData(),
+		Board:              slices.Clone(playerPickData.Board),
+		Picked:             slices.Clone(playerPickData.Picked),
+		CurPicked:          slices.Clone(playerPickData.CurPicked),
+		PickNum:            playerPickData.PickNum,
+		IsEnded:            playerPickData.IsEnded,
+	}
+
+	return target
+}
+
+// BuildPBComponentData - 用 FeaturePickData，
+//
+//	pos 是选过的位置，selected 是对应的值，curSelected 是这一步选中的值，
+//	unSelected 只有在结束以后才有，是整个 board
+func (playerPickData *PlayerPickData) BuildPBComponentData() proto.Message {
+	pbcd := &sgc7pb.FeaturePickData{
+		BasicComponentData: playerPickData.BuildPBBasicComponentData(),
+		PickNum:            int32(playerPickData.PickNum),
+		CurPickedNum:       int32(len(playerPickData.Picked)),
+	}
+
+	pbcd.BasicComponentData.Pos = make([]int32, len(playerPickData.Picked))
+	pbcd.Selected = make([]string, len(playerPickData.Picked))
+
+	for i, v := range playerPickData.Picked {
+		pbcd.BasicComponentData.Pos[i] = int32(v)
+		pbcd.Selected[i] = playerPickData.Board[v]
+	}
+
+	for _, v := range playerPickData.CurPicked {
+		pbcd.CurSelected = append(pbcd.CurSelected, playerPickData.Board[v])
+	}
+
+	if playerPickData.IsEnded {
+		pbcd.UnSelected = slices.Clone(playerPickData.Board)
+	}
+
+	return pbcd
+}
+
+// GetValEx -
+func (playerPickData *PlayerPickData) GetValEx(key string, getType GetComponentValType) (int, bool) {
+	if key == CVNumber {
+		return len(playerPickData.Picked), true
+	}
+
+	return 0, false
+}
+
+// SetConfigIntVal -
+func (playerPickData *PlayerPickData) SetConfigIntVal(key string, val int) {
+	playerPickData.BasicComponentData.SetConfigIntVal(key, val)
+
+	// 特殊处理
+	if key == CCVPickNum && playerPickData.isWaiting() {
+		playerPickData.PickNum = val
+	}
+}
+
+// ChgConfigIntVal -
+func (playerPickData *PlayerPickData) ChgConfigIntVal(key string, off int) int {
+	val := playerPickData.BasicComponentData.ChgConfigIntVal(key, off)
+
+	if key == CCVPickNum && playerPickData.isWaiting() {
+		playerPickData.PickNum = val
+	}
+
+	return val
+}
+
+// PlayerPickConfig - configuration for PlayerPick
+type PlayerPickConfig struct {
+	BasicComponentConfig `yaml:",inline" json:",inline"`
+	StrWeight            string                `yaml:"weight" json:"weight"` // weight
+	Weight               *sgc7game.ValWeights2 `yaml:"-" json:"-"`
+	BoardSize            int                   `yaml:"boardSize" json:"boardSize"`
+	IsUniqueVal          bool                  `yaml:"isUniqueVal" json:"isUniqueVal"` // 不放回抽取，board 里的值不会重复
+	PickNum              int                   `yaml:"pickNum" json:"pickNum"`         // 最多选几次，0 表示选到 pooper 或者选完
+	PooperVals           []string              `yaml:"pooperVals" json:"pooperVals"`   // 选到这些值就结束
+	IsAutoPick           bool                  `yaml:"isAutoPick" json:"isAutoPick"`   // 不等玩家，随机选，RTP 模式下也会自动选
+	MapControllers       map[string][]*Award   `yaml:"mapControllers" json:"mapControllers"`
+}
+
+// SetLinkComponent
+func (cfg *PlayerPickConfig) SetLinkComponent(link string, componentName string) {
+	if link == "next" {
+		cfg.DefaultNextComponent = componentName
+	}
+}
+
+// PlayerPick - 玩家选择，服务器先生成隐藏的 board，玩家每次用 NextCmdParams 里的位置来选，
+//
+//	没选完时 board 保存在私有的 PlayerState 里，所以每次选都可以是一个新的请求
+type PlayerPick struct {
+	*BasicComponent `json:"-"`
+	Config          *PlayerPickConfig `json:"config"`
+}
+
+// Init -
+func (playerPick *PlayerPick) Init(fn string, pool *GamePropertyPool) error {
+	data, err := os.ReadFile(fn)
+	if err != nil {
+		goutils.Error("PlayerPick.Init:ReadFile",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return err
+	}
+
+	cfg := &PlayerPickConfig{}
+
+	err = yaml.Unmarshal(data, cfg)
+	if err != nil {
+		goutils.Error("PlayerPick.Init:Unmarshal",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return err
+	}
+
+	return playerPick.InitEx(cfg, pool)
+}
+
+// InitEx -
+func (playerPick *PlayerPick) InitEx(cfg any, pool *GamePropertyPool) error {
+	playerPick.Config = cfg.(*PlayerPickConfig)
+	playerPick.Config.ComponentType = PlayerPickTypeName
+
+	if playerPick.Config.BoardSize <= 0 {
+		goutils.Error("PlayerPick.InitEx:BoardSize",
+			slog.Int("boardSize", playerPick.Config.BoardSize),
+			goutils.Err(ErrInvalidComponentConfig))
+
+		return ErrInvalidComponentConfig
+	}
+
+	if playerPick.Config.StrWeight != "" {
+		vw2, err := pool.LoadStrWeights(playerPick.Config.StrWeight, playerPick.Config.UseFileMapping)
+		if err != nil {
+			goutils.Error("PlayerPick.InitEx:LoadStrWeights",
+				slog.String("weight", playerPick.Config.StrWeight),
+				goutils.Err(err))
+
+			return err
+		}
+
+		playerPick.Config.Weight = vw2
+	}
+
+	if playerPick.Config.Weight == nil {
+		goutils.Error("PlayerPick.InitEx:Weight",
+			goutils.Err(ErrInvalidComponentConfig))
+
+		return ErrInvalidComponentConfig
+	}
+
+	if playerPick.Config.IsUniqueVal && playerPick.Config.BoardSize > len(playerPick.Config.Weight.Vals) {
+		goutils.Error("PlayerPick.InitEx:IsUniqueVal",
+			slog.Int("boardSize", playerPick.Config.BoardSize),
+			slog.Int("vals", len(playerPick.Config.Weight.Vals)),
+			goutils.Err(ErrInvalidComponentConfig))
+
+		return ErrInvalidComponentConfig
+	}
+
+	for _, awards := range playerPick.Config.MapControllers {
+		for _, award := range awards {
+			award.Init()
+		}
+	}
+
+	playerPick.onInit(&playerPick.Config.BasicComponentConfig)
+
+	return nil
+}
+
+// ProcControllers -
+func (playerPick *PlayerPick) ProcControllers(gameProp *GameProperty, plugin sgc7plugin.IPlugin, curpr *sgc7game.PlayResult, gp *GameParams, val int, strVal string) {
+	controllers, isok := playerPick.Config.MapControllers[strVal]
+	if isok {
+		if len(controllers) > 0 {
+			gameProp.procAwards(plugin, controllers, curpr, gp)
+		}
+	}
+}
+
+func (playerPick *PlayerPick) getWeight(gameProp *GameProperty, basicCD *BasicComponentData) *sgc7game.ValWeights2 {
+	str := basicCD.GetConfigVal(CCVWeight)
+	if str != "" {
+		vw2, err := gameProp.Pool.LoadStrWeights(str, playerPick.Config.UseFileMapping)
+		if err != nil || vw2 == nil {
+			goutils.Error("PlayerPick.getWeight:LoadStrWeights",
+				slog.String("weight", str),
+				goutils.Err(err))
+
+			return playerPick.Config.Weight
+		}
+
+		return vw2
+	}
+
+	return playerPick.Config.Weight
+}
+
+func (playerPick *PlayerPick) getPickNum(_ *GameProperty, basicCD *BasicComponentData) int {
+	ival, isok := basicCD.GetConfigIntVal(CCVPickNum)
+	if isok {
+		return ival
+	}
+
+	return playerPick.Config.PickNum
+}
+
+// isAutoPick - RTP 模式下不需要前端，直接自动选
+func (playerPick *PlayerPick) isAutoPick() bool {
+	return playerPick.Config.IsAutoPick || gIsRTPMode
+}
+
+// newBoard - 生成隐藏的 board
+func (playerPick *PlayerPick) newBoard(gameProp *GameProperty, plugin sgc7plugin.IPlugin, cd *PlayerPickData) error {
+	vw := playerPick.getWeight(gameProp, &cd.BasicComponentData)
+	if playerPick.Config.IsUniqueVal {
+		vw = vw.Clone()
+	}
+
+	board := make([]string, playerPick.Config.BoardSize)
+
+	for i := range board {
+		cv, err := vw.RandVal(plugin)
+		if err != nil {
+			goutils.Error("PlayerPick.newBoard:RandVal",
+				goutils.Err(err))
+
+			return err
+		}
+
+		board[i] = cv.String()
+
+		if playerPick.Config.IsUniqueVal && i < len(board)-1 {
+			err = vw.RemoveVal(cv)
+			if err != nil {
+				goutils.Error("PlayerPick.newBoard:RemoveVal",
+					slog.String("val", cv.String()),
+					goutils.Err(err))
+
+				return err
+			}
+		}
+	}
+
+	cd.Board = board
+	cd.Picked = nil
+	cd.CurPicked = nil
+	cd.PickNum = playerPick.getPickNum(gameProp, &cd.BasicComponentData)
+	cd.IsEnded = false
+
+	return nil
+}
+
+// pick - 选中 index，处理奖励，判断是否结束
+func (playerPick *PlayerPick) pick(gameProp *GameProperty, plugin sgc7plugin.IPlugin, curpr *sgc7game.PlayResult, gp *GameParams, cd *PlayerPickData, index int) error {
+	if index < 0 || index >= len(cd.Board) || slices.Contains(cd.Picked, index) {
+		goutils.Error("PlayerPick.pick",
+			slog.Int("index", index),
+			goutils.Err(ErrInvalidCmdParam))
+
+		return ErrInvalidCmdParam
+	}
+
+	val := cd.Board[index]
+
+	cd.Picked = append(cd.Picked, index)
+	cd.CurPicked = append(cd.CurPicked, index)
+	cd.Output = index
+
+	playerPick.ProcControllers(gameProp, plugin, curpr, gp, -1, PlayerPickAwardAny)
+	playerPick.ProcControllers(gameProp, plugin, curpr, gp, -1, val)
+
+	if slices.Contains(playerPick.Config.PooperVals, val) ||
+		(cd.PickNum > 0 && len(cd.Picked) >= cd.PickNum) ||
+		len(cd.Picked) >= len(cd.Board) {
+
+		cd.IsEnded = true
+
+		playerPick.ProcControllers(gameProp, plugin, curpr, gp, -1, PlayerPickAwardEnd)
+	}
+
+	return nil
+}
+
+// getPS - 私有的 PlayerPickPS，没有 PlayerState 时返回 nil
+func (playerPick *PlayerPick) getPS(ips sgc7game.IPlayerState, stake *sgc7game.Stake) *PlayerPickPS {
+	ps, isok := ips.(*PlayerState)
+	if !isok || ps == nil || stake.CoinBet <= 0 {
+		return nil
+	}
+
+	cps, isok := ps.GetBetPriCPS(int(stake.CashBet/stake.CoinBet), int(stake.CoinBet), playerPick.GetName()).(*PlayerPickPS)
+	if !isok {
+		return nil
+	}
+
+	return cps
+}
+
+// loadPS - 用新的 gameData 时，从 PlayerPickPS 里恢复 board
+func (playerPick *PlayerPick) loadPS(cps *PlayerPickPS, cd *PlayerPickData) bool {
+	if cps == nil || !cps.IsWaiting || len(cps.Board) != playerPick.Config.BoardSize {
+		return false
+	}
+
+	cd.Board = slices.Clone(cps.Board)
+	cd.Picked = slices.Clone(cps.Picked)
+	cd.PickNum = cps.PickNum
+	cd.IsEnded = false
+
+	return true
+}
+
+// savePS - 在等玩家选时保存 board，选完了就清掉
+func (playerPick *PlayerPick) savePS(cps *PlayerPickPS, cd *PlayerPickData) {
+	if cps == nil {
+		return
+	}
+
+	if !cd.isWaiting() {
+		*cps = PlayerPickPS{}
+
+		return
+	}
+
+	cps.IsWaiting = true
+	cps.Board = slices.Clone(cd.Board)
+	cps.Picked = slices.Clone(cd.Picked)
+	cps.PickNum = cd.PickNum
+}
+
+// getUnpicked - 还没选过的位置
+func (playerPick *PlayerPick) getUnpicked(cd *PlayerPickData) []int {
+	lst := []int{}
+
+	for i := range cd.Board {
+		if !slices.Contains(cd.Picked, i) {
+			lst = append(lst, i)
+		}
+	}
+
+	return lst
+}
+
+// playgame
+func (playerPick *PlayerPick) OnPlayGame(gameProp *GameProperty, curpr *sgc7game.PlayResult, gp *GameParams, plugin sgc7plugin.IPlugin,
+	cmd string, param string, ps sgc7game.IPlayerState, stake *sgc7game.Stake, prs []*sgc7game.PlayResult, icd IComponentData) (string, error) {
+
+	cd, isok := icd.(*PlayerPickData)
+	if !isok {
+		goutils.Error("PlayerPick.OnPlayGame:invalid icd",
+			goutils.Err(ErrInvalidComponentData))
+
+		return "", ErrInvalidComponentData
+	}
+
+	cd.onNewStep()
+
+	cps := playerPick.getPS(ps, stake)
+
+	if cmd == playerPick.Name {
+		if !cd.isWaiting() && !playerPick.loadPS(cps, cd) {
+			goutils.Error("PlayerPick.OnPlayGame:isWaiting",
+				slog.String("cmd", cmd),
+				goutils.Err(ErrInvalidCommand))
+
+			return "", ErrInvalidCommand
+		}
+
+		index, err := strconv.Atoi(param)
+		if err != nil {
+			goutils.Error("PlayerPick.OnPlayGame:Atoi",
+				slog.String("param", param),
+				goutils.Err(ErrInvalidCmdParam))
+
+			return "", ErrInvalidCmdParam
+		}
+
+		err = playerPick.pick(gameProp, plugin, curpr, gp, cd, index)
+		if err != nil {
+			goutils.Error("PlayerPick.OnPlayGame:pick",
+				goutils.Err(err))
+
+			return "", err
+		}
+	} else {
+		err := playerPick.newBoard(gameProp, plugin, cd)
+		if err != nil {
+			goutils.Error("PlayerPick.OnPlayGame:newBoard",
+				goutils.Err(err))
+
+			return "", err
+		}
+
+		if playerPick.isAutoPick() {
+			for !cd.IsEnded {
+				lst := playerPick.getUnpicked(cd)
+
+				ci, err := plugin.Random(sgc7plugin.GetContext(plugin), len(lst))
+				if err != nil {
+					goutils.Error("PlayerPick.OnPlayGame:Random",
+						goutils.Err(err))
+
+					return "", err
+				}
+
+				err = playerPick.pick(gameProp, plugin, curpr, gp, cd, lst[ci])
+				if err != nil {
+					goutils.Error("PlayerPick.OnPlayGame:pick",
+						goutils.Err(err))
+
+					return "", err
+				}
+			}
+		}
+	}
+
+	playerPick.savePS(cps, cd)
+
+	if !cd.IsEnded {
+		lst := playerPick.getUnpicked(cd)
+
+		lstcmd := make([]string, len(lst))
+		lstparam := make([]string, len(lst))
+
+		for i, v := range lst {
+			lstcmd[i] = playerPick.Name
+			lstparam[i] = strconv.Itoa(v)
+		}
+
+		curpr.NextCmds = lstcmd
+		curpr.NextCmdParams = lstparam
+		curpr.IsFinish = false
+		curpr.IsWait = true
+
+		// 等玩家选，这一步到这里结束
+		return "", nil
+	}
+
+	nc := playerPick.onStepEnd(gameProp, curpr, gp, "")
+
+	return nc, nil
+}
+
+// OnAsciiGame - outpur to asciigame
+func (playerPick *PlayerPick) OnAsciiGame(gameProp *GameProperty, pr *sgc7game.PlayResult, lst []*sgc7game.PlayResult, mapSymbolColor *asciigame.SymbolColorMap, icd IComponentData) error {
+	cd, isok := icd.(*PlayerPickData)
+	if !isok {
+		goutils.Error("PlayerPick.OnAsciiGame:invalid icd",
+			goutils.Err(ErrInvalidComponentData))
+
+		return ErrInvalidComponentData
+	}
+
+	fmt.Printf("playerPick: name=%s, picked=%v, curPicked=%v, isEnded=%v\n",
+		playerPick.GetName(),
+		cd.Picked,
+		cd.CurPicked,
+		cd.IsEnded)
+
+	return nil
+}
+
+// InitPlayerState - 每次下注前把 PlayerPickPS 读出来
+func (playerPick *PlayerPick) InitPlayerState(pool *GamePropertyPool, gameProp *GameProperty, plugin sgc7plugin.IPlugin,
+	ps *PlayerState, betMethod int, bet int) error {
+
+	if bet <= 0 {
+		return nil
+	}
+
+	bps := ps.GetBetMethodPri(betMethod).GetBetPS(bet)
+
+	cname := playerPick.GetName()
+
+	_, isok := bps.MapComponentData[cname]
+	if !isok {
+		cps := &PlayerPickPS{}
+
+		str, isok := bps.MapString[cname]
+		if isok {
+			err := cps.SetPrivateJson(str)
+			if err != nil {
+				goutils.Error("PlayerPick.InitPlayerState:SetPrivateJson",
+					goutils.Err(err))
+
+				return err
+			}
+		}
+
+		bps.MapComponentData[cname] = cps
+	}
+
+	return nil
+}
+
+// NewPlayerState - new IComponentPS
+func (playerPick *PlayerPick) NewPlayerState() IComponentPS {
+	return &PlayerPickPS{}
+}
+
+// NewComponentData -
+func (playerPick *PlayerPick) NewComponentData() IComponentData {
+	return &PlayerPickData{}
+}
+
+// OnStats2
+func (playerPick *PlayerPick) OnStats2(icd IComponentData, s2 *stats2.Cache, gameProp *GameProperty, gp *GameParams, pr *sgc7game.PlayResult, isOnStepEnd bool) {
+	playerPick.BasicComponent.OnStats2(icd, s2, gameProp, gp, pr, isOnStepEnd)
+
+	cd, isok := icd.(*PlayerPickData)
+	if !isok {
+		goutils.Error("PlayerPick.OnStats2:invalid icd",
+			goutils.Err(ErrInvalidComponentData))
+
+		return
+	}
+
+	for _, v := range cd.CurPicked {
+		s2.ProcStatsStrVal(playerPick.GetName(), cd.Board[v])
+	}
+
+	if cd.IsEnded {
+		s2.ProcStatsIntVal(playerPick.GetName(), len(cd.Picked))
+	}
+}
+
+// NewStats2 -
+func (playerPick *PlayerPick) NewStats2(parent string) *stats2.Feature {
+	return stats2.NewFeature(parent, []stats2.Option{stats2.OptStrVal, stats2.OptIntVal})
+}
+
+func NewPlayerPick(name string) IComponent {
+	return &PlayerPick{
+		BasicComponent: NewBasicComponent(name, 1),
+	}
+}
+
+// "weight": "bonuspickweight",
+// "boardSize": 12,
+// "isUniqueVal": false,
+// "pickNum": 0,
+// "pooperVals": ["pooper"],
+// "isAutoPick": false
+type jsonPlayerPick struct {
+	Weight      string   `json:"weight"`
+	BoardSize   int      `json:"boardSize"`
+	IsUniqueVal bool     `json:"isUniqueVal"`
+	PickNum     int      `json:"pickNum"`
+	PooperVals  []string `json:"pooperVals"`
+	IsAutoPick  bool     `json:"isAutoPick"`
+}
+
+func (jcfg *jsonPlayerPick) build() *PlayerPickConfig {
+	cfg := &PlayerPickConfig{
+		StrWeight:   jcfg.Weight,
+		BoardSize:   jcfg.BoardSize,
+		IsUniqueVal: jcfg.IsUniqueVal,
+		PickNum:     jcfg.PickNum,
+		PooperVals:  slices.Clone(jcfg.PooperVals),
+		IsAutoPick:  jcfg.IsAutoPick,
+	}
+
+	return cfg
+}
+
+func parsePlayerPick(gamecfg *BetConfig, cell *ast.Node) (string, error) {
+	cfg, label, ctrls, err := getConfigInCell(cell)
+	if err != nil {
+		goutils.Error("parsePlayerPick:getConfigInCell",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	buf, err := cfg.MarshalJSON()
+	if err != nil {
+		goutils.Error("parsePlayerPick:MarshalJSON",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	data := &jsonPlayerPick{}
+
+	err = sonic.Unmarshal(buf, data)
+	if err != nil {
+		goutils.Error("parsePlayerPick:Unmarshal",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	cfgd := data.build()
+
+	if ctrls != nil {
+		mapAwards, err := parseAllAndStrMapControllers2(ctrls)
+		if err != nil {
+			goutils.Error("parsePlayerPick:parseAllAndStrMapControllers2",
+				goutils.Err(err))
+
+			return "", err
+		}
+
+		cfgd.MapControllers = mapAwards
+	}
+
+	gamecfg.mapConfig[label] = cfgd
+	gamecfg.mapBasicConfig[label] = &cfgd.BasicComponentConfig
+
+	ccfg := &ComponentConfig{
+		Name: label,
+		Type: PlayerPickTypeName,
+	}
+
+	gamecfg.Components = append(gamecfg.Components, ccfg)
+
+	return label, nil
+}
@@ -0,0 +1,177 @@
+package lowcode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+	"github.com/zhs007/slotsgamecore7/sgc7pb"
+)
+
+func newTestPlayerPick(t *testing.T, cfg *PlayerPickConfig) *PlayerPick {
+	vw, err := sgc7game.NewValWeights2([]sgc7game.IVal{
+		sgc7game.NewStrValEx("10"),
+		sgc7game.NewStrValEx("20"),
+		sgc7game.NewStrValEx("pooper"),
+	}, []int{1, 1, 1})
+	assert.NoError(t, err)
+
+	pool := &GamePropertyPool{mapStrValWeights: map[string]*sgc7game.ValWeights2{"w": vw}}
+
+	cfg.StrWeight = "w"
+	cfg.DefaultNextComponent = "next"
+
+	playerPick := NewPlayerPick("pick").(*PlayerPick)
+	err = playerPick.InitEx(cfg, pool)
+	assert.NoError(t, err)
+
+	return playerPick
+}
+
+func Test_PlayerPickInitEx(t *testing.T) {
+	vw, err := sgc7game.NewValWeights2([]sgc7game.IVal{sgc7game.NewStrValEx("10")}, []int{1})
+	assert.NoError(t, err)
+
+	pool := &GamePropertyPool{mapStrValWeights: map[string]*sgc7game.ValWeights2{"w": vw}}
+
+	playerPick := NewPlayerPick("pick").(*PlayerPick)
+
+	err = playerPick.InitEx(&PlayerPickConfig{StrWeight: "w"}, pool)
+	assert.ErrorIs(t, err, ErrInvalidComponentConfig)
+
+	err = playerPick.InitEx(&PlayerPickConfig{StrWeight: "none", BoardSize: 3}, pool)
+	assert.ErrorIs(t, err, ErrInvalidComponentConfig)
+
+	err = playerPick.InitEx(&PlayerPickConfig{StrWeight: "w", BoardSize: 3, IsUniqueVal: true}, pool)
+	assert.ErrorIs(t, err, ErrInvalidComponentConfig)
+
+	err = playerPick.InitEx(&PlayerPickConfig{StrWeight: "w", BoardSize: 3}, pool)
+	assert.NoError(t, err)
+	assert.Equal(t, vw, playerPick.Config.Weight)
+
+	t.Logf("Test_PlayerPickInitEx OK")
+}
+
+func Test_PlayerPickOnPlayGame(t *testing.T) {
+	playerPick := newTestPlayerPick(t, &PlayerPickConfig{
+		BoardSize:  4,
+		PooperVals: []string{"pooper"},
+		MapControllers: map[string][]*Award{
+			PlayerPickAwardAny: {{AwardType: ""}},
+			PlayerPickAwardEnd: {{AwardType: ""}},
+		},
+	})
+
+	stake := &sgc7game.Stake{CoinBet: 1, CashBet: 1}
+	gameProp := &GameProperty{Pool: &GamePropertyPool{}}
+	gp := NewGameParam(stake, nil)
+	cd := playerPick.NewComponentData().(*PlayerPickData)
+
+	plugin := sgc7plugin.NewMockPlugin()
+	plugin.Cache = []int{0, 1, 2, 0}
+
+	// 第一步只生成 board，等玩家选
+	pr := sgc7game.NewPlayResult("bg", 0, 0, "bg")
+	nc, err := playerPick.OnPlayGame(gameProp, pr, gp, plugin, DefaultCmd, "", nil, stake, nil, cd)
+	assert.NoError(t, err)
+	assert.Equal(t, "", nc)
+	assert.Equal(t, []string{"10", "20", "pooper", "10"}, cd.Board)
+	assert.True(t, pr.IsWait)
+	assert.False(t, pr.IsFinish)
+	assert.Equal(t, []string{"pick", "pick", "pick", "pick"}, pr.NextCmds)
+	assert.Equal(t, []string{"0", "1", "2", "3"}, pr.NextCmdParams)
+
+	// 没结束时不能把 board 发给前端
+	pbcd := cd.BuildPBComponentData().(*sgc7pb.FeaturePickData)
+	assert.Empty(t, pbcd.Selected)
+	assert.Empty(t, pbcd.UnSelected)
+
+	// 选第 1 个
+	pr = sgc7game.NewPlayResult("bg", 1, 0, "bg")
+	nc, err = playerPick.OnPlayGame(gameProp, pr, gp, plugin, "pick", "1", nil, stake, nil, cd)
+	assert.NoError(t, err)
+	assert.Equal(t, "", nc)
+	assert.True(t, pr.IsWait)
+	assert.Equal(t, []string{"0", "2", "3"}, pr.NextCmdParams)
+
+	pbcd = cd.BuildPBComponentData().(*sgc7pb.FeaturePickData)
+	assert.Equal(t, []int32{1}, pbcd.BasicComponentData.Pos)
+	assert.Equal(t, []string{"20"}, pbcd.Selected)
+	assert.Equal(t, []string{"20"}, pbcd.CurSelected)
+	assert.Empty(t, pbcd.UnSelected)
+
+	v, isok := cd.GetValEx(CVNumber, GCVTypeNormal)
+	assert.True(t, isok)
+	assert.Equal(t, 1, v)
+
+	// 已经选过的、不存在的都不行
+	_, err = playerPick.OnPlayGame(gameProp, sgc7game.NewPlayResult("bg", 2, 0, "bg"), gp, plugin, "pick", "1", nil, stake, nil, cd)
+	assert.ErrorIs(t, err, ErrInvalidCmdParam)
+
+	_, err = playerPick.OnPlayGame(gameProp, sgc7game.NewPlayResult("bg", 2, 0, "bg"), gp, plugin, "pick", "9", nil, stake, nil, cd)
+	assert.ErrorIs(t, err, ErrInvalidCmdParam)
+
+	_, err = playerPick.OnPlayGame(gameProp, sgc7game.NewPlayResult("bg", 2, 0, "bg"), gp, plugin, "pick", "abc", nil, stake, nil, cd)
+	assert.ErrorIs(t, err, ErrInvalidCmdParam)
+
+	// 选到 pooper 结束，整个 board 都发给前端
+	pr = sgc7game.NewPlayResult("bg", 2, 0, "bg")
+	nc, err = playerPick.OnPlayGame(gameProp, pr, gp, plugin, "pick", "2", nil, stake, nil, cd)
+	assert.NoError(t, err)
+	assert.Equal(t, "next", nc)
+	assert.False(t, pr.IsWait)
+	assert.Empty(t, pr.NextCmds)
+	assert.True(t, cd.IsEnded)
+
+	pbcd = cd.BuildPBComponentData().(*sgc7pb.FeaturePickData)
+	assert.Equal(t, []int32{1, 2}, pbcd.BasicComponentData.Pos)
+	assert.Equal(t, []string{"20", "pooper"}, pbcd.Selected)
+	assert.Equal(t, []string{"pooper"}, pbcd.CurSelected)
+	assert.Equal(t, []string{"10", "20", "pooper", "10"}, pbcd.UnSelected)
+	assert.Equal(t, int32(2), pbcd.CurPickedNum)
+
+	// 结束以后不能再选
+	_, err = playerPick.OnPlayGame(gameProp, sgc7game.NewPlayResult("bg", 3, 0, "bg"), gp, plugin, "pick", "0", nil, stake, nil, cd)
+	assert.ErrorIs(t, err, ErrInvalidCommand)
+
+	cd1 := cd.Clone().(*PlayerPickData)
+	assert.Equal(t, cd.Board, cd1.Board)
+	assert.Equal(t, cd.Picked, cd1.Picked)
+	assert.True(t, cd1.IsEnded)
+
+	cd.OnNewGame(gameProp, playerPick)
+	assert.Nil(t, cd.Board)
+	assert.False(t, cd.IsEnded)
+
+	t.Logf("Test_PlayerPickOnPlayGame OK")
+}
+
+func Test_PlayerPickAutoPick(t *testing.T) {
+	playerPick := newTestPlayerPick(t, &PlayerPickConfig{
+		BoardSize:   3,
+		IsUniqueVal: true,
+		PickNum:     2,
+		IsAutoPick:  true,
+	})
+
+	stake := &sgc7game.Stake{CoinBet: 1, CashBet: 1}
+	gameProp := &GameProperty{Pool: &GamePropertyPool{}}
+	gp := NewGameParam(stake, nil)
+	cd := playerPick.NewComponentData().(*PlayerPickData)
+
+	plugin := sgc7plugin.NewMockPlugin()
+	// board: pooper, 10, 20 ; pick: 2, 0
+	plugin.Cache = []int{2, 0, 2, 0}
+
+	pr := sgc7game.NewPlayResult("bg", 0, 0, "bg")
+	nc, err := playerPick.OnPlayGame(gameProp, pr, gp, plugin, DefaultCmd, "", nil, stake, nil, cd)
+	assert.NoError(t, err)
+	assert.Equal(t, "next", nc)
+	assert.False(t, pr.IsWait)
+	assert.Equal(t, []string{"pooper", "10", "20"}, cd.Board)
+	assert.Equal(t, []int{2, 0}, cd.Picked)
+	assert.True(t, cd.IsEnded)
+
+	t.Logf("Test_PlayerPickAutoPick OK")
+}
@@ -200,6 +200,34 @@ func (ps *PlayerState) GetBetMethodPub(betMethod int) *BetMethodPS {
 	return betmps
 }
 
+// GetBetMethodPri - 私有的状态不会发给前端，用来放服务器要保存的隐藏数据
+func (ps *PlayerState) GetBetMethodPri(betMethod int) *BetMethodPS {
+	if ps.MapBetMothodPri == nil {
+		ps.MapBetMothodPri = make(map[int]*BetMethodPS)
+	}
+
+	betmps, isok := ps.MapBetMothodPri[betMethod]
+	if !isok {
+		ps.MapBetMothodPri[betMethod] = &BetMethodPS{
+			MapBet: make(map[int]*BetPS),
+		}
+
+		return ps.MapBetMothodPri[betMethod]
+	}
+
+	return betmps
+}
+
+// GetBetPriCPS - get the private IComponentPS, return nil if it is not found
+func (ps *PlayerState) GetBetPriCPS(betMethod int, bet int, componentName string) IComponentPS {
+	betmps, isok := ps.MapBetMothodPri[betMethod]
+	if !isok {
+		return nil
+	}
+
+	return betmps.GetBetCPS(bet, componentName)
+}
+
 func (ps *PlayerState) Rebuild() {
 	for _, v := range ps.MapBetMothodPri {
 		v.Rebuild()
@@ -0,0 +1,523 @@
+package lowcode
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+
+	"github.com/bytedance/sonic"
+	"github.com/bytedance/sonic/ast"
+	"github.com/zhs007/goutils"
+	"github.com/zhs007/slotsgamecore7/asciigame"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+	"github.com/zhs007/slotsgamecore7/sgc7pb"
+	"github.com/zhs007/slotsgamecore7/stats2"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v2"
+)
+
+const PositionMultiplierGridTypeName = "positionMultiplierGrid"
+
+type PositionMultiplierType int
+
+const (
+	PMTSum      PositionMultiplierType = 0 // 中奖位置上的倍数相加
+	PMTMultiply PositionMultiplierType = 1 // 中奖位置上的倍数相乘
+)
+
+func parsePositionMultiplierType(str string) PositionMultiplierType {
+	if str == "multiply" {
+		return PMTMultiply
+	}
+
+	return PMTSum
+}
+
+type PositionMultiplierUpdateType int
+
+const (
+	PMUTDouble PositionMultiplierUpdateType = 0 // 再次中奖时倍数翻倍
+	PMUTAdd    PositionMultiplierUpdateType = 1 // 再次中奖时倍数加上 addMulti
+)
+
+func parsePositionMultiplierUpdateType(str string) PositionMultiplierUpdateType {
+	if str == "add" {
+		return PMUTAdd
+	}
+
+	return PMUTDouble
+}
+
+type PositionMultiplierGridData struct {
+	BasicComponentData
+	Multis       [][]int // 0 表示没有标记，1 表示只有标记
+	WinMultis    []int   // 这一步每个被修改的中奖用到的倍数
+	Wins         int
+	isRoundEnded bool // 上一步没有中奖，下一步就是新的一轮
+}
+
+// OnNewGame -
+func (positionMultiplierGridData *PositionMultiplierGridData) OnNewGame(gameProp *GameProperty, component IComponent) {
+	positionMultiplierGridData.BasicComponentData.OnNewGame(gameProp, component)
+
+	positionMultiplierGridData.Multis = nil
+	positionMultiplierGridData.isRoundEnded = false
+}
+
+// onNewStep -
+func (positionMultiplierGridData *PositionMultiplierGridData) onNewStep() {
+	positionMultiplierGridData.UsedResults = nil
+	positionMultiplierGridData.WinMultis = nil
+	positionMultiplierGridData.Wins = 0
+}
+
+// Clone
+func (positionMultiplierGridData *PositionMultiplierGridData) Clone() IComponentData {
+	target := &PositionMultiplierGridData{
+		BasicComponentData: positionMultiplierGridData.CloneBasicComponentData(),
+		WinMultis:          slices.Clone(positionMultiplierGridData.WinMultis),
+		Wins:               positionMultiplierGridData.Wins,
+		isRoundEnded:       positionMultiplierGridData.isRoundEnded,
+	}
+
+	for _, arr := range positionMultiplierGridData.Multis {
+		target.Multis = append(target.Multis, slices.Clone(arr))
+	}
+
+	return target
+}
+
+// BuildPBComponentData
+func (positionMultiplierGridData *PositionMultiplierGridData) BuildPBComponentData() proto.Message {
+	pbcd := &sgc7pb.PositionMultiplierGridData{
+		BasicComponentData: positionMultiplierGridData.BuildPBBasicComponentData(),
+		Width:              int32(len(positionMultiplierGridData.Multis)),
+		Wins:               int32(positionMultiplierGridData.Wins),
+	}
+
+	for x, arr := range positionMultiplierGridData.Multis {
+		if x == 0 {
+			pbcd.Height = int32(len(arr))
+		}
+
+		for _, v := range arr {
+			pbcd.Multis = append(pbcd.Multis, int32(v))
+		}
+	}
+
+	for _, v := range positionMultiplierGridData.WinMultis {
+		pbcd.WinMultis = append(pbcd.WinMultis, int32(v))
+	}
+
+	return pbcd
+}
+
+// GetValEx -
+func (positionMultiplierGridData *PositionMultiplierGridData) GetValEx(key string, getType GetComponentValType) (int, bool) {
+	switch key {
+	case CVWins:
+		return positionMultiplierGridData.Wins, true
+	case CVNumber:
+		return positionMultiplierGridData.getMarkedNum(), true
+	case CVTotalMulti:
+		return positionMultiplierGridData.getTotalMulti(), true
+	}
+
+	return positionMultiplierGridData.BasicComponentData.GetValEx(key, getType)
+}
+
+// getMarkedNum - 被标记的位置数量
+func (positionMultiplierGridData *PositionMultiplierGridData) getMarkedNum() int {
+	num := 0
+
+	for _, arr := range positionMultiplierGridData.Multis {
+		for _, v := range arr {
+			if v > 0 {
+				num++
+			}
+		}
+	}
+
+	return num
+}
+
+// getTotalMulti - 所有大于 1 的倍数的和
+func (positionMultiplierGridData *PositionMultiplierGridData) getTotalMulti() int {
+	total := 0
+
+	for _, arr := range positionMultiplierGridData.Multis {
+		for _, v := range arr {
+			if v > 1 {
+				total += v
+			}
+		}
+	}
+
+	return total
+}
+
+// getMaxMulti -
+func (positionMultiplierGridData *PositionMultiplierGridData) getMaxMulti() int {
+	maxMulti := 0
+
+	for _, arr := range positionMultiplierGridData.Multis {
+		for _, v := range arr {
+			maxMulti = max(maxMulti, v)
+		}
+	}
+
+	return maxMulti
+}
+
+// PositionMultiplierGridConfig - configuration for PositionMultiplierGrid
+//
+//	sourceComponents 里中奖的位置，第一次中奖时标记为 initMulti，之后再中奖就按 updateType 增加倍数，
+//	每个中奖会先用已有的倍数（只算大于 1 的）修改奖励，然后再更新倍数，
+//	倍数在同一轮的消除里一直保留，一轮结束后清掉，配置了 isKeepInFreeSpins 时整个 game 都保留
+type PositionMultiplierGridConfig struct {
+	BasicComponentConfig `yaml:",inline" json:",inline"`
+	SourceComponents     []string                     `yaml:"sourceComponents" json:"sourceComponents"` // clusterTrigger 或者 scatterTrigger
+	StrType              string                       `yaml:"type" json:"type"`                         // sum or multiply
+	Type                 PositionMultiplierType       `yaml:"-" json:"-"`
+	StrUpdateType        string                       `yaml:"updateType" json:"updateType"` // double or add
+	UpdateType           PositionMultiplierUpdateType `yaml:"-" json:"-"`
+	InitMulti            int                          `yaml:"initMulti" json:"initMulti"`                 // 第一次中奖时的倍数，1 就是只标记
+	AddMulti             int                          `yaml:"addMulti" json:"addMulti"`                   // updateType 是 add 时用
+	MaxPosMulti          int                          `yaml:"maxPosMulti" json:"maxPosMulti"`             // 每个位置的倍数上限，0 表示不限制
+	MaxWinMulti          int                          `yaml:"maxWinMulti" json:"maxWinMulti"`             // 每个中奖的倍数上限，0 表示不限制
+	IsKeepInFreeSpins    bool                         `yaml:"isKeepInFreeSpins" json:"isKeepInFreeSpins"` // 一轮结束后也不清掉
+}
+
+// SetLinkComponent
+func (cfg *PositionMultiplierGridConfig) SetLinkComponent(link string, componentName string) {
+	if link == "next" {
+		cfg.DefaultNextComponent = componentName
+	}
+}
+
+type PositionMultiplierGrid struct {
+	*BasicComponent `json:"-"`
+	Config          *PositionMultiplierGridConfig `json:"config"`
+}
+
+// Init -
+func (positionMultiplierGrid *PositionMultiplierGrid) Init(fn string, pool *GamePropertyPool) error {
+	data, err := os.ReadFile(fn)
+	if err != nil {
+		goutils.Error("PositionMultiplierGrid.Init:ReadFile",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return err
+	}
+
+	cfg := &PositionMultiplierGridConfig{}
+
+	err = yaml.Unmarshal(data, cfg)
+	if err != nil {
+		goutils.Error("PositionMultiplierGrid.Init:Unmarshal",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return err
+	}
+
+	return positionMultiplierGrid.InitEx(cfg, pool)
+}
+
+// InitEx -
+func (positionMultiplierGrid *PositionMultiplierGrid) InitEx(cfg any, pool *GamePropertyPool) error {
+	positionMultiplierGrid.Config = cfg.(*PositionMultiplierGridConfig)
+	positionMultiplierGrid.Config.ComponentType = PositionMultiplierGridTypeName
+
+	if len(positionMultiplierGrid.Config.SourceComponents) == 0 {
+		goutils.Error("PositionMultiplierGrid.InitEx:SourceComponents",
+			goutils.Err(ErrInvalidComponentConfig))
+
+		return ErrInvalidComponentConfig
+	}
+
+	positionMultiplierGrid.Config.Type = parsePositionMultiplierType(positionMultiplierGrid.Config.StrType)
+	positionMultiplierGrid.Config.UpdateType = parsePositionMultiplierUpdateType(positionMultiplierGrid.Config.StrUpdateType)
+
+	if positionMultiplierGrid.Config.InitMulti <= 0 {
+		positionMultiplierGrid.Config.InitMulti = 1
+	}
+
+	if positionMultiplierGrid.Config.UpdateType == PMUTAdd && positionMultiplierGrid.Config.AddMulti <= 0 {
+		goutils.Error("PositionMultiplierGrid.InitEx:AddMulti",
+			slog.Int("addMulti", positionMultiplierGrid.Config.AddMulti),
+			goutils.Err(ErrInvalidComponentConfig))
+
+		return ErrInvalidComponentConfig
+	}
+
+	if positionMultiplierGrid.Config.MaxPosMulti < 0 || positionMultiplierGrid.Config.MaxWinMulti < 0 {
+		goutils.Error("PositionMultiplierGrid.InitEx:Max",
+			slog.Int("maxPosMulti", positionMultiplierGrid.Config.MaxPosMulti),
+			slog.Int("maxWinMulti", positionMultiplierGrid.Config.MaxWinMulti),
+			goutils.Err(ErrInvalidComponentConfig))
+
+		return ErrInvalidComponentConfig
+	}
+
+	positionMultiplierGrid.onInit(&positionMultiplierGrid.Config.BasicComponentConfig)
+
+	return nil
+}
+
+// isClear -
+func (positionMultiplierGrid *PositionMultiplierGrid) isClear(cd *BasicComponentData) bool {
+	v, isok := cd.GetConfigIntVal(CCVClear)
+	if isok {
+		return v != 0
+	}
+
+	return false
+}
+
+// calcWinMulti - 只有大于 1 的倍数才生效
+func (positionMultiplierGrid *PositionMultiplierGrid) calcWinMulti(cd *PositionMultiplierGridData, ret *sgc7game.Result) int {
+	multi := 0
+	if positionMultiplierGrid.Config.Type == PMTMultiply {
+		multi = 1
+	}
+
+	for i := 0; i < len(ret.Pos)/2; i++ {
+		v := cd.Multis[ret.Pos[i*2]][ret.Pos[i*2+1]]
+		if v <= 1 {
+			continue
+		}
+
+		if positionMultiplierGrid.Config.Type == PMTMultiply {
+			multi *= v
+		} else {
+			multi += v
+		}
+
+		if positionMultiplierGrid.Config.MaxWinMulti > 0 && multi >= positionMultiplierGrid.Config.MaxWinMulti {
+			return positionMultiplierGrid.Config.MaxWinMulti
+		}
+	}
+
+	return max(multi, 1)
+}
+
+// updateMulti -
+func (positionMultiplierGrid *PositionMultiplierGrid) updateMulti(v int) int {
+	if v <= 0 {
+		v = positionMultiplierGrid.Config.InitMulti
+	} else if positionMultiplierGrid.Config.UpdateType == PMUTAdd {
+		v += positionMultiplierGrid.Config.AddMulti
+	} else {
+		v *= 2
+	}
+
+	if positionMultiplierGrid.Config.MaxPosMulti > 0 && v > positionMultiplierGrid.Config.MaxPosMulti {
+		return positionMultiplierGrid.Config.MaxPosMulti
+	}
+
+	return v
+}
+
+// playgame
+func (positionMultiplierGrid *PositionMultiplierGrid) OnPlayGame(gameProp *GameProperty, curpr *sgc7game.PlayResult, gp *GameParams, plugin sgc7plugin.IPlugin,
+	cmd string, param string, ps sgc7game.IPlayerState, stake *sgc7game.Stake, prs []*sgc7game.PlayResult, icd IComponentData) (string, error) {
+
+	cd := icd.(*PositionMultiplierGridData)
+	cd.onNewStep()
+
+	if positionMultiplierGrid.isClear(&cd.BasicComponentData) {
+		cd.Multis = nil
+
+		cd.SetConfigIntVal(CCVClear, 0)
+	}
+
+	if cd.isRoundEnded && !positionMultiplierGrid.Config.IsKeepInFreeSpins {
+		cd.Multis = nil
+	}
+
+	gs := positionMultiplierGrid.GetTargetScene3(gameProp, curpr, prs, 0)
+	if gs == nil {
+		goutils.Error("PositionMultiplierGrid.OnPlayGame:GetTargetScene3",
+			goutils.Err(ErrInvalidScene))
+
+		return "", ErrInvalidScene
+	}
+
+	if len(cd.Multis) != gs.Width || len(cd.Multis[0]) != gs.Height {
+		cd.Multis = make([][]int, gs.Width)
+		for x := range cd.Multis {
+			cd.Multis[x] = make([]int, gs.Height)
+		}
+	}
+
+	var lst []*sgc7game.Result
+
+	for _, cn := range positionMultiplierGrid.Config.SourceComponents {
+		// 如果前面没有执行过，就可能没有清理数据，所以这里需要跳过
+		if goutils.IndexOfStringSlice(gp.HistoryComponents, cn, 0) < 0 {
+			continue
+		}
+
+		ccd := gameProp.GetComponentDataWithName(cn)
+		for _, ri := range ccd.GetResults() {
+			lst = append(lst, curpr.Results[ri])
+		}
+	}
+
+	cd.isRoundEnded = len(lst) == 0
+
+	if len(lst) == 0 {
+		nc := positionMultiplierGrid.onStepEnd(gameProp, curpr, gp, "")
+
+		return nc, ErrComponentDoNothing
+	}
+
+	// 先用已有的倍数
+	for _, ret := range lst {
+		multi := positionMultiplierGrid.calcWinMulti(cd, ret)
+		if multi > 1 {
+			ret.CashWin *= multi
+			ret.CoinWin *= multi
+			ret.OtherMul *= multi
+
+			cd.WinMultis = append(cd.WinMultis, multi)
+			cd.Wins += ret.CoinWin
+		}
+	}
+
+	// 再更新倍数，同一个位置一步只更新一次
+	updated := make([][]bool, gs.Width)
+	for x := range updated {
+		updated[x] = make([]bool, gs.Height)
+	}
+
+	for _, ret := range lst {
+		for i := 0; i < len(ret.Pos)/2; i++ {
+			x, y := ret.Pos[i*2], ret.Pos[i*2+1]
+			if updated[x][y] {
+				continue
+			}
+
+			updated[x][y] = true
+			cd.Multis[x][y] = positionMultiplierGrid.updateMulti(cd.Multis[x][y])
+		}
+	}
+
+	nc := positionMultiplierGrid.onStepEnd(gameProp, curpr, gp, "")
+
+	return nc, nil
+}
+
+// OnAsciiGame - outpur to asciigame
+func (positionMultiplierGrid *PositionMultiplierGrid) OnAsciiGame(gameProp *GameProperty, pr *sgc7game.PlayResult, lst []*sgc7game.PlayResult, mapSymbolColor *asciigame.SymbolColorMap, icd IComponentData) error {
+	cd := icd.(*PositionMultiplierGridData)
+
+	fmt.Printf("positionMultiplierGrid %v: multis %v, winMultis %v, wins %v\n", positionMultiplierGrid.GetName(), cd.Multis, cd.WinMultis, cd.Wins)
+
+	return nil
+}
+
+// NewComponentData -
+func (positionMultiplierGrid *PositionMultiplierGrid) NewComponentData() IComponentData {
+	return &PositionMultiplierGridData{}
+}
+
+// OnStats2
+func (positionMultiplierGrid *PositionMultiplierGrid) OnStats2(icd IComponentData, s2 *stats2.Cache, gameProp *GameProperty, gp *GameParams, pr *sgc7game.PlayResult, isOnStepEnd bool) {
+	positionMultiplierGrid.BasicComponent.OnStats2(icd, s2, gameProp, gp, pr, isOnStepEnd)
+
+	cd := icd.(*PositionMultiplierGridData)
+
+	s2.ProcStatsWins(positionMultiplierGrid.GetName(), int64(cd.Wins))
+	s2.ProcStatsIntVal(positionMultiplierGrid.GetName(), cd.getMaxMulti())
+}
+
+// NewStats2 -
+func (positionMultiplierGrid *PositionMultiplierGrid) NewStats2(parent string) *stats2.Feature {
+	return stats2.NewFeature(parent, []stats2.Option{stats2.OptWins, stats2.OptIntVal})
+}
+
+func NewPositionMultiplierGrid(name string) IComponent {
+	return &PositionMultiplierGrid{
+		BasicComponent: NewBasicComponent(name, 1),
+	}
+}
+
+// "sourceComponents": ["bg-cluster"],
+// "type": "sum",
+// "updateType": "double",
+// "initMulti": 1,
+// "maxPosMulti": 128,
+// "maxWinMulti": 0,
+// "isKeepInFreeSpins": true
+type jsonPositionMultiplierGrid struct {
+	SourceComponents  []string `json:"sourceComponents"`
+	Type              string   `json:"type"`
+	UpdateType        string   `json:"updateType"`
+	InitMulti         int      `json:"initMulti"`
+	AddMulti          int      `json:"addMulti"`
+	MaxPosMulti       int      `json:"maxPosMulti"`
+	MaxWinMulti       int      `json:"maxWinMulti"`
+	IsKeepInFreeSpins bool     `json:"isKeepInFreeSpins"`
+}
+
+func (jcfg *jsonPositionMultiplierGrid) build() *PositionMultiplierGridConfig {
+	cfg := &PositionMultiplierGridConfig{
+		SourceComponents:  slices.Clone(jcfg.SourceComponents),
+		StrType:           jcfg.Type,
+		StrUpdateType:     jcfg.UpdateType,
+		InitMulti:         jcfg.InitMulti,
+		AddMulti:          jcfg.AddMulti,
+		MaxPosMulti:       jcfg.MaxPosMulti,
+		MaxWinMulti:       jcfg.MaxWinMulti,
+		IsKeepInFreeSpins: jcfg.IsKeepInFreeSpins,
+	}
+
+	return cfg
+}
+
+func parsePositionMultiplierGrid(gamecfg *BetConfig, cell *ast.Node) (string, error) {
+	cfg, label, _, err := getConfigInCell(cell)
+	if err != nil {
+		goutils.Error("parsePositionMultiplierGrid:getConfigInCell",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	buf, err := cfg.MarshalJSON()
+	if err != nil {
+		goutils.Error("parsePositionMultiplierGrid:MarshalJSON",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	data := &jsonPositionMultiplierGrid{}
+
+	err = sonic.Unmarshal(buf, data)
+	if err != nil {
+		goutils.Error("parsePositionMultiplierGrid:Unmarshal",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	cfgd := data.build()
+
+	gamecfg.mapConfig[label] = cfgd
+	gamecfg.mapBasicConfig[label] = &cfgd.BasicComponentConfig
+
+	ccfg := &ComponentConfig{
+		Name: label,
+		Type: PositionMultiplierGridTypeName,
+	}
+
+	gamecfg.Components = append(gamecfg.Components, ccfg)
+
+	return label, nil
+}
@@ -0,0 +1,150 @@
+package lowcode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+	"github.com/zhs007/slotsgamecore7/sgc7pb"
+)
+
+func Test_PositionMultiplierGridOnPlayGame(t *testing.T) {
+	gameProp := &GameProperty{Pool: &GamePropertyPool{}}
+	gameProp.Components = NewComponentList()
+	gameProp.callStack = NewCallStack()
+	gameProp.callStack.OnNewGame()
+
+	gameProp.Components.MapComponents["cluster"] = NewClusterTrigger("cluster")
+
+	ctd := &ClusterTriggerData{}
+	gameProp.callStack.nodes[0].MapComponentData["cluster"] = ctd
+
+	stake := &sgc7game.Stake{CoinBet: 1, CashBet: 1}
+	gp := NewGameParam(stake, nil)
+	gp.HistoryComponents = []string{"cluster"}
+	plugin := sgc7plugin.NewMockPlugin()
+
+	grid := NewPositionMultiplierGrid("grid").(*PositionMultiplierGrid)
+	err := grid.InitEx(&PositionMultiplierGridConfig{
+		BasicComponentConfig: BasicComponentConfig{DefaultNextComponent: "next"},
+		SourceComponents:     []string{"cluster"},
+		MaxPosMulti:          8,
+	}, gameProp.Pool)
+	assert.NoError(t, err)
+
+	cd := grid.NewComponentData().(*PositionMultiplierGridData)
+	cd.OnNewGame(gameProp, grid)
+
+	gs, err := sgc7game.NewGameSceneWithArr2([][]int{{1, 1, 1}, {1, 1, 1}, {1, 1, 1}})
+	assert.NoError(t, err)
+
+	play := func(rets ...*sgc7game.Result) (*sgc7game.PlayResult, error) {
+		gameProp.SceneStack = NewSceneStack(false)
+		gameProp.SceneStack.Push("reels", gs)
+
+		pr := sgc7game.NewPlayResult("bg", 0, 0, "bg")
+		ctd.UsedResults = nil
+		for _, ret := range rets {
+			ctd.UsedResults = append(ctd.UsedResults, len(pr.Results))
+			pr.Results = append(pr.Results, ret)
+		}
+
+		nc, err := grid.OnPlayGame(gameProp, pr, gp, plugin, DefaultCmd, "", nil, stake, nil, cd)
+		assert.Equal(t, "next", nc)
+
+		return pr, err
+	}
+
+	newResult := func(pos ...int) *sgc7game.Result {
+		return &sgc7game.Result{CoinWin: 10, CashWin: 10, OtherMul: 1, Pos: pos}
+	}
+
+	// 第一次中奖只标记
+	_, err = play(newResult(0, 0, 0, 1, 1, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, cd.Wins)
+	assert.Equal(t, []int{1, 1, 0}, cd.Multis[0])
+
+	// 再次中奖，先用倍数再翻倍
+	pr, err := play(newResult(0, 0, 0, 1, 2, 2))
+	assert.NoError(t, err)
+	assert.Equal(t, 10, pr.Results[0].CoinWin)
+	assert.Equal(t, []int{2, 2, 0}, cd.Multis[0])
+
+	// 同一步两个中奖重叠的位置只更新一次
+	pr, err = play(newResult(0, 0, 0, 1), newResult(0, 0, 1, 1))
+	assert.NoError(t, err)
+	assert.Equal(t, 40, pr.Results[0].CoinWin)
+	assert.Equal(t, 20, pr.Results[1].CoinWin)
+	assert.Equal(t, 4, pr.Results[0].OtherMul)
+	assert.Equal(t, []int{4, 2}, cd.WinMultis)
+	assert.Equal(t, 60, cd.Wins)
+	assert.Equal(t, []int{4, 4, 0}, cd.Multis[0])
+	assert.Equal(t, 2, cd.Multis[1][1])
+
+	pbcd := cd.BuildPBComponentData().(*sgc7pb.PositionMultiplierGridData)
+	assert.Equal(t, int32(3), pbcd.Width)
+	assert.Equal(t, int32(3), pbcd.Height)
+	assert.Equal(t, []int32{4, 4, 0, 0, 2, 0, 0, 0, 1}, pbcd.Multis)
+	assert.Equal(t, []int32{4, 2}, pbcd.WinMultis)
+
+	totalMulti, isok := cd.GetValEx(CVTotalMulti, GCVTypeNormal)
+	assert.True(t, isok)
+	assert.Equal(t, 10, totalMulti)
+
+	number, isok := cd.GetValEx(CVNumber, GCVTypeNormal)
+	assert.True(t, isok)
+	assert.Equal(t, 4, number)
+
+	cd1 := cd.Clone().(*PositionMultiplierGridData)
+	cd1.Multis[0][0] = 100
+	assert.Equal(t, 4, cd.Multis[0][0])
+
+	// 每个位置最多 8 倍
+	play(newResult(0, 0))
+	play(newResult(0, 0))
+	assert.Equal(t, 8, cd.Multis[0][0])
+
+	// 没有中奖，这一轮结束
+	_, err = play()
+	assert.ErrorIs(t, err, ErrComponentDoNothing)
+	assert.Equal(t, 8, cd.Multis[0][0])
+
+	_, err = play(newResult(0, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, cd.Wins)
+	assert.Equal(t, []int{1, 0, 0}, cd.Multis[0])
+
+	t.Logf("Test_PositionMultiplierGridOnPlayGame OK")
+}
+
+func Test_PositionMultiplierGridMultiply(t *testing.T) {
+	grid := NewPositionMultiplierGrid("grid").(*PositionMultiplierGrid)
+	err := grid.InitEx(&PositionMultiplierGridConfig{
+		SourceComponents:  []string{"scatter"},
+		StrType:           "multiply",
+		StrUpdateType:     "add",
+		InitMulti:         2,
+		AddMulti:          1,
+		MaxWinMulti:       10,
+		IsKeepInFreeSpins: true,
+	}, nil)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 2, grid.updateMulti(0))
+	assert.Equal(t, 3, grid.updateMulti(2))
+
+	cd := &PositionMultiplierGridData{Multis: [][]int{{2, 3}, {1, 4}}}
+	assert.Equal(t, 6, grid.calcWinMulti(cd, &sgc7game.Result{Pos: []int{0, 0, 0, 1, 1, 0}}))
+	assert.Equal(t, 10, grid.calcWinMulti(cd, &sgc7game.Result{Pos: []int{0, 0, 0, 1, 1, 1}}))
+	assert.Equal(t, 1, grid.calcWinMulti(cd, &sgc7game.Result{Pos: []int{1, 0}}))
+
+	err = NewPositionMultiplierGrid("grid").InitEx(&PositionMultiplierGridConfig{}, nil)
+	assert.ErrorIs(t, err, ErrInvalidComponentConfig)
+
+	err = NewPositionMultiplierGrid("grid").InitEx(&PositionMultiplierGridConfig{SourceComponents: []string{"scatter"}, StrUpdateType: "add"}, nil)
+	assert.ErrorIs(t, err, ErrInvalidComponentConfig)
+
+	t.Logf("Test_PositionMultiplierGridMultiply OK")
+}
@@ -2,7 +2,9 @@ package lowcode
 
 import (
 	"log/slog"
+	"slices"
 	"sync"
+	"sync/atomic"
 
 	"github.com/fatih/color"
 	"github.com/zhs007/goutils"
@@ -12,6 +14,18 @@ import (
 	sgc7stats "github.com/zhs007/slotsgamecore7/stats"
 )
 
+// GamePropPoolStats - stats of the pool of a bet
+type GamePropPoolStats struct {
+	Bet     int
+	Created int64 // 新建的 GameProperty 数量，sync.Pool 在 GC 时会丢掉一些，所以会比实际持有的多
+	InUse   int64 // 正在使用的 GameProperty 数量
+}
+
+type gamePropPoolCounter struct {
+	created atomic.Int64
+	inUse   atomic.Int64
+}
+
 type GamePropertyPool struct {
 	MapGamePropPool  map[int]*sync.Pool
 	Config           *Config
@@ -24,9 +38,48 @@ type GamePropertyPool struct {
 	mapIntValWeights map[string]*sgc7game.ValWeights2
 	newRNG           FuncNewRNG
 	newFeatureLevel  FuncNewFeatureLevel
+	mapCounters      map[int]*gamePropPoolCounter // 初始化以后不会再改
+}
+
+// GetStats - get the stats of all bets, sorted by bet
+func (pool *GamePropertyPool) GetStats() []*GamePropPoolStats {
+	lst := make([]*GamePropPoolStats, 0, len(pool.mapCounters))
+
+	for bet, counter := range pool.mapCounters {
+		lst = append(lst, &GamePropPoolStats{
+			Bet:     bet,
+			Created: counter.created.Load(),
+			InUse:   counter.inUse.Load(),
+		})
+	}
+
+	slices.SortFunc(lst, func(a, b *GamePropPoolStats) int {
+		return a.Bet - b.Bet
+	})
+
+	return lst
+}
+
+func (pool *GamePropertyPool) onGetGameProp(betMul int) {
+	counter, isok := pool.mapCounters[betMul]
+	if isok {
+		counter.inUse.Add(1)
+	}
+}
+
+func (pool *GamePropertyPool) onPutGameProp(betMul int) {
+	counter, isok := pool.mapCounters[betMul]
+	if isok {
+		counter.inUse.Add(-1)
+	}
 }
 
 func (pool *GamePropertyPool) newGameProp(betMul int) *GameProperty {
+	counter, isok := pool.mapCounters[betMul]
+	if isok {
+		counter.created.Add(1)
+	}
+
 	gameProp := &GameProperty{
 		CurBetMul:        betMul,
 		Pool:             pool,
@@ -287,6 +340,7 @@ func newGamePropertyPool2(cfg *Config, funcNewRNG FuncNewRNG, funcNewFeatureLeve
 		mapIntValWeights: make(map[string]*sgc7game.ValWeights2),
 		newRNG:           funcNewRNG,
 		newFeatureLevel:  funcNewFeatureLevel,
+		mapCounters:      make(map[int]*gamePropPoolCounter),
 	}
 
 	if cfg.SymbolsViewer == "" {
@@ -334,6 +388,7 @@ func newGamePropertyPool2(cfg *Config, funcNewRNG FuncNewRNG, funcNewFeatureLeve
 	}
 
 	for _, bet := range cfg.Bets {
+		pool.mapCounters[bet] = &gamePropPoolCounter{}
 		pool.MapGamePropPool[bet] = &sync.Pool{
 			New: func() any {
 				return pool.newGameProp(bet)
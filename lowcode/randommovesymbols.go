@@ -1,7 +1,6 @@
 package lowcode
 
 import (
-	"context"
 	"log/slog"
 	"os"
 
@@ -294,7 +293,7 @@ func (randomMoveSymbols *RandomMoveSymbols) procNormal(gameProp *GameProperty, c
 		x := posSrc[i*2]
 		y := posSrc[i*2+1]
 
-		cr, err := plugin.Random(context.Background(), len(posTarget)/2)
+		cr, err := plugin.Random(sgc7plugin.GetContext(plugin), len(posTarget)/2)
 		if err != nil {
 			goutils.Error("RandomMoveSymbols.procNormal:Random",
 				goutils.Err(err))
@@ -412,7 +411,7 @@ func (randomMoveSymbols *RandomMoveSymbols) procReels(gameProp *GameProperty, cd
 					return gs, err
 				}
 			} else {
-				cr, err := plugin.Random(context.Background(), len(yArr))
+				cr, err := plugin.Random(sgc7plugin.GetContext(plugin), len(yArr))
 				if err != nil {
 					goutils.Error("RandomMoveSymbols.procReels:Random",
 						goutils.Err(err))
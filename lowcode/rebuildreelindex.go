@@ -1,7 +1,6 @@
 package lowcode
 
 import (
-	"context"
 	"log/slog"
 	"os"
 
@@ -88,7 +87,7 @@ func (rebuildReelIndex *RebuildReelIndex) InitEx(cfg any, pool *GamePropertyPool
 }
 
 func (rebuildReelIndex *RebuildReelIndex) procCircle(gameProp *GameProperty, gs *sgc7game.GameScene, plugin sgc7plugin.IPlugin) (*sgc7game.GameScene, error) {
-	cr, err := plugin.Random(context.Background(), gs.Width)
+	cr, err := plugin.Random(sgc7plugin.GetContext(plugin), gs.Width)
 	if err != nil {
 		goutils.Error("RebuildReelIndex.procCircle:Random",
 			goutils.Err(err))
@@ -1,7 +1,6 @@
 package lowcode
 
 import (
-	"context"
 	"log/slog"
 	"os"
 
@@ -101,7 +100,7 @@ func (rebuildSymbols *RebuildSymbols) InitEx(cfg any, pool *GamePropertyPool) er
 }
 
 func (rebuildSymbols *RebuildSymbols) procCircle(gameProp *GameProperty, gs *sgc7game.GameScene, plugin sgc7plugin.IPlugin) (*sgc7game.GameScene, error) {
-	cr, err := plugin.Random(context.Background(), len(rebuildSymbols.Config.SymbolCodes))
+	cr, err := plugin.Random(sgc7plugin.GetContext(plugin), len(rebuildSymbols.Config.SymbolCodes))
 	if err != nil {
 		goutils.Error("RebuildSymbols.procCircle:Random",
 			goutils.Err(err))
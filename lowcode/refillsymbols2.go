@@ -1,7 +1,6 @@
 package lowcode
 
 import (
-	"context"
 	"log/slog"
 	"os"
 
@@ -216,7 +215,7 @@ func (refillSymbols2 *RefillSymbols2) refillHeightAndMaskX(gameProp *GamePropert
 				continue
 			} else {
 				if ngs.Indexes[x] < 0 {
-					ci, err := plugin.Random(context.Background(), len(cr.Reels[x]))
+					ci, err := plugin.Random(sgc7plugin.GetContext(plugin), len(cr.Reels[x]))
 					if err != nil {
 						goutils.Error("RefillSymbols2.refillHeightAndMaskX:Random",
 							slog.Int("len", len(cr.Reels[x])),
@@ -262,7 +261,7 @@ func (refillSymbols2 *RefillSymbols2) refillHeightAndMaskX(gameProp *GamePropert
 			continue
 		} else {
 			if ngs.Indexes[x] < 0 {
-				ci, err := plugin.Random(context.Background(), len(cr.Reels[x]))
+				ci, err := plugin.Random(sgc7plugin.GetContext(plugin), len(cr.Reels[x]))
 				if err != nil {
 					goutils.Error("RefillSymbols2.refillHeightAndMaskX:Random",
 						slog.Int("len", len(cr.Reels[x])),
@@ -340,7 +339,7 @@ func (refillSymbols2 *RefillSymbols2) refillMaskX(gameProp *GameProperty, plugin
 				continue
 			} else {
 				if ngs.Indexes[x] < 0 {
-					ci, err := plugin.Random(context.Background(), len(cr.Reels[x]))
+					ci, err := plugin.Random(sgc7plugin.GetContext(plugin), len(cr.Reels[x]))
 					if err != nil {
 						goutils.Error("RefillSymbols2.refillMaskX:Random",
 							slog.Int("len", len(cr.Reels[x])),
@@ -382,7 +381,7 @@ func (refillSymbols2 *RefillSymbols2) refillMaskX(gameProp *GameProperty, plugin
 			continue
 		} else {
 			if ngs.Indexes[x] < 0 {
-				ci, err := plugin.Random(context.Background(), len(cr.Reels[x]))
+				ci, err := plugin.Random(sgc7plugin.GetContext(plugin), len(cr.Reels[x]))
 				if err != nil {
 					goutils.Error("RefillSymbols2.refillMaskX:Random",
 						slog.Int("len", len(cr.Reels[x])),
@@ -8,6 +8,22 @@ func NewStats2(components *ComponentList) *stats2.Stats {
 	lst := components.statsNodeData.GetComponents()
 	s2 := stats2.NewStats(lst)
 
+	// 同一档奖池可能有多个 jackpot 组件，抽成只算一次
+	mapContribution := make(map[int]int)
+	for _, ic := range components.MapComponents {
+		jackpot, isok := ic.(*Jackpot)
+		if isok && jackpot.Config.JackpotType > 0 {
+			mapContribution[jackpot.Config.JackpotType] = max(mapContribution[jackpot.Config.JackpotType], jackpot.Config.Contribution)
+		}
+	}
+
+	contribution := 0
+	for _, v := range mapContribution {
+		contribution += v
+	}
+
+	s2.SetJackpotContribution(contribution)
+
 	for _, key := range lst {
 		ic, isok := components.MapComponents[key]
 		if isok {
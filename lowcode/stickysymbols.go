@@ -0,0 +1,615 @@
+package lowcode
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/bytedance/sonic/ast"
+	"github.com/zhs007/goutils"
+	"github.com/zhs007/slotsgamecore7/asciigame"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+	"github.com/zhs007/slotsgamecore7/sgc7pb"
+	"github.com/zhs007/slotsgamecore7/stats2"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v2"
+)
+
+const StickySymbolsTypeName = "stickySymbols"
+
+type StickyMoveType int
+
+const (
+	SMTNone  StickyMoveType = 0 // 不动
+	SMTLeft  StickyMoveType = 1 // 每次往左一个轴
+	SMTRight StickyMoveType = 2 // 每次往右一个轴
+	SMTUp    StickyMoveType = 3 // 每次往上一格
+	SMTDown  StickyMoveType = 4 // 每次往下一格
+)
+
+func parseStickyMoveType(str string) StickyMoveType {
+	str = strings.ToLower(str)
+	switch str {
+	case "left":
+		return SMTLeft
+	case "right":
+		return SMTRight
+	case "up":
+		return SMTUp
+	case "down":
+		return SMTDown
+	}
+
+	return SMTNone
+}
+
+// StickySymbol - 一个 sticky 的位置和状态
+type StickySymbol struct {
+	X        int
+	Y        int
+	Symbol   int
+	Multi    int
+	Life     int // 之后还能保留几次，-1 表示一直保留
+	MoveType StickyMoveType
+}
+
+// move - 返回 false 表示移出去了
+func (sticky *StickySymbol) move(width int, height int) bool {
+	switch sticky.MoveType {
+	case SMTLeft:
+		sticky.X--
+	case SMTRight:
+		sticky.X++
+	case SMTUp:
+		sticky.Y--
+	case SMTDown:
+		sticky.Y++
+	}
+
+	return sticky.X >= 0 && sticky.X < width && sticky.Y >= 0 && sticky.Y < height
+}
+
+type StickySymbolsData struct {
+	BasicComponentData
+	Stickies   []*StickySymbol
+	NewNum     int // 这一步新增的数量
+	RemovedNum int // 这一步移除的数量，包括到期的和移出去的
+}
+
+// OnNewGame -
+func (stickySymbolsData *StickySymbolsData) OnNewGame(gameProp *GameProperty, component IComponent) {
+	stickySymbolsData.BasicComponentData.OnNewGame(gameProp, component)
+
+	stickySymbolsData.Stickies = nil
+	stickySymbolsData.Pos = nil
+	stickySymbolsData.NewNum = 0
+	stickySymbolsData.RemovedNum = 0
+}
+
+// onNewStep -
+func (stickySymbolsData *StickySymbolsData) onNewStep() {
+	stickySymbolsData.UsedScenes = nil
+	stickySymbolsData.UsedOtherScenes = nil
+	stickySymbolsData.NewNum = 0
+	stickySymbolsData.RemovedNum = 0
+}
+
+// Clone
+func (stickySymbolsData *StickySymbolsData) Clone() IComponentData {
+	target := &StickySymbolsData{
+		BasicComponentData: stickySymbolsData.CloneBasicComponentData(),
+		NewNum:             stickySymbolsData.NewNum,
+		RemovedNum:         stickySymbolsData.RemovedNum,
+	}
+
+	for _, v := range stickySymbolsData.Stickies {
+		sticky := *v
+		target.Stickies = append(target.Stickies, &sticky)
+	}
+
+	return target
+}
+
+// BuildPBComponentData
+func (stickySymbolsData *StickySymbolsData) BuildPBComponentData() proto.Message {
+	pbcd := &sgc7pb.StickySymbolsData{
+		BasicComponentData: stickySymbolsData.BuildPBBasicComponentData(),
+		NewNum:             int32(stickySymbolsData.NewNum),
+		RemovedNum:         int32(stickySymbolsData.RemovedNum),
+	}
+
+	for _, v := range stickySymbolsData.Stickies {
+		pbcd.Stickies = append(pbcd.Stickies, &sgc7pb.StickySymbol{
+			X:        int32(v.X),
+			Y:        int32(v.Y),
+			Symbol:   int32(v.Symbol),
+			Multi:    int32(v.Multi),
+			Life:     int32(v.Life),
+			MoveType: int32(v.MoveType),
+		})
+	}
+
+	return pbcd
+}
+
+// GetValEx -
+func (stickySymbolsData *StickySymbolsData) GetValEx(key string, getType GetComponentValType) (int, bool) {
+	switch key {
+	case CVNumber:
+		return len(stickySymbolsData.Stickies), true
+	case CVNewNumber:
+		return stickySymbolsData.NewNum, true
+	case CVTotalMulti:
+		return stickySymbolsData.getTotalMulti(), true
+	}
+
+	return stickySymbolsData.BasicComponentData.GetValEx(key, getType)
+}
+
+func (stickySymbolsData *StickySymbolsData) getTotalMulti() int {
+	total := 0
+	for _, v := range stickySymbolsData.Stickies {
+		total += v.Multi
+	}
+
+	return total
+}
+
+func (stickySymbolsData *StickySymbolsData) getSticky(x int, y int) *StickySymbol {
+	for _, v := range stickySymbolsData.Stickies {
+		if v.X == x && v.Y == y {
+			return v
+		}
+	}
+
+	return nil
+}
+
+// update - 先处理到期的，再移动，移到同一个位置的会合并，倍数相加，保留时间取长的
+func (stickySymbolsData *StickySymbolsData) update(width int, height int, addMulti int) {
+	lst := make([]*StickySymbol, 0, len(stickySymbolsData.Stickies))
+
+	for _, v := range stickySymbolsData.Stickies {
+		if v.Life == 0 {
+			stickySymbolsData.RemovedNum++
+
+			continue
+		}
+
+		if v.Life > 0 {
+			v.Life--
+		}
+
+		if !v.move(width, height) {
+			stickySymbolsData.RemovedNum++
+
+			continue
+		}
+
+		v.Multi += addMulti
+
+		isMerged := false
+		for _, cv := range lst {
+			if cv.X == v.X && cv.Y == v.Y {
+				cv.Multi += v.Multi
+				if cv.Life >= 0 && (v.Life < 0 || v.Life > cv.Life) {
+					cv.Life = v.Life
+				}
+
+				stickySymbolsData.RemovedNum++
+				isMerged = true
+
+				break
+			}
+		}
+
+		if !isMerged {
+			lst = append(lst, v)
+		}
+	}
+
+	stickySymbolsData.Stickies = lst
+}
+
+func (stickySymbolsData *StickySymbolsData) rebuildPos() {
+	stickySymbolsData.Pos = nil
+
+	for _, v := range stickySymbolsData.Stickies {
+		stickySymbolsData.Pos = append(stickySymbolsData.Pos, v.X, v.Y)
+	}
+}
+
+// StickySymbolsConfig - configuration for StickySymbols
+//
+//	落下的符号变成 sticky，每一步开始时先处理到期和移动，再收集新的，最后把 sticky 放到盘面上
+type StickySymbolsConfig struct {
+	BasicComponentConfig `yaml:",inline" json:",inline"`
+	Symbols              []string              `yaml:"symbols" json:"symbols"` // 这些符号落下以后变成 sticky
+	SymbolCodes          []int                 `yaml:"-" json:"-"`
+	Lifetime             int                   `yaml:"lifetime" json:"lifetime"` // 之后还能保留几次，0 表示一直保留，直到移出去或被清掉
+	StrMoveType          string                `yaml:"moveType" json:"moveType"` // none、left、right、up、down
+	MoveType             StickyMoveType        `yaml:"-" json:"-"`
+	MoveTypeWeight       string                `yaml:"moveTypeWeight" json:"moveTypeWeight"` // 新的 sticky 按权重随机方向，会覆盖 moveType
+	MoveTypeWeightVW     *sgc7game.ValWeights2 `yaml:"-" json:"-"`
+	Multi                int                   `yaml:"multi" json:"multi"`             // 新的 sticky 的倍数，0 表示 1
+	MultiWeight          string                `yaml:"multiWeight" json:"multiWeight"` // 新的 sticky 按权重随机倍数，会覆盖 multi
+	MultiWeightVW        *sgc7game.ValWeights2 `yaml:"-" json:"-"`
+	AddMulti             int                   `yaml:"addMulti" json:"addMulti"`       // 每保留一次，倍数加多少
+	IsOutputMulti        bool                  `yaml:"outputMulti" json:"outputMulti"` // 把倍数输出到 otherScene
+	Controllers          []*Award              `yaml:"controllers" json:"controllers"` // 有新的 sticky 时执行
+}
+
+// SetLinkComponent
+func (cfg *StickySymbolsConfig) SetLinkComponent(link string, componentName string) {
+	if link == "next" {
+		cfg.DefaultNextComponent = componentName
+	}
+}
+
+type StickySymbols struct {
+	*BasicComponent `json:"-"`
+	Config          *StickySymbolsConfig `json:"config"`
+}
+
+// Init -
+func (stickySymbols *StickySymbols) Init(fn string, pool *GamePropertyPool) error {
+	data, err := os.ReadFile(fn)
+	if err != nil {
+		goutils.Error("StickySymbols.Init:ReadFile",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return err
+	}
+
+	cfg := &StickySymbolsConfig{}
+
+	err = yaml.Unmarshal(data, cfg)
+	if err != nil {
+		goutils.Error("StickySymbols.Init:Unmarshal",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return err
+	}
+
+	return stickySymbols.InitEx(cfg, pool)
+}
+
+// InitEx -
+func (stickySymbols *StickySymbols) InitEx(cfg any, pool *GamePropertyPool) error {
+	stickySymbols.Config = cfg.(*StickySymbolsConfig)
+	stickySymbols.Config.ComponentType = StickySymbolsTypeName
+
+	if stickySymbols.Config.Lifetime < 0 || stickySymbols.Config.Multi < 0 || stickySymbols.Config.AddMulti < 0 {
+		goutils.Error("StickySymbols.InitEx:Config",
+			slog.Int("lifetime", stickySymbols.Config.Lifetime),
+			slog.Int("multi", stickySymbols.Config.Multi),
+			slog.Int("addMulti", stickySymbols.Config.AddMulti),
+			goutils.Err(ErrInvalidComponentConfig))
+
+		return ErrInvalidComponentConfig
+	}
+
+	stickySymbols.Config.SymbolCodes = nil
+	for _, v := range stickySymbols.Config.Symbols {
+		sc, isok := pool.DefaultPaytables.MapSymbols[v]
+		if !isok {
+			goutils.Error("StickySymbols.InitEx:Symbols",
+				slog.String("symbol", v),
+				goutils.Err(ErrInvalidSymbol))
+
+			return ErrInvalidSymbol
+		}
+
+		stickySymbols.Config.SymbolCodes = append(stickySymbols.Config.SymbolCodes, sc)
+	}
+
+	stickySymbols.Config.MoveType = parseStickyMoveType(stickySymbols.Config.StrMoveType)
+
+	if stickySymbols.Config.MoveTypeWeight != "" {
+		vw2, err := pool.LoadStrWeights(stickySymbols.Config.MoveTypeWeight, stickySymbols.Config.UseFileMapping)
+		if err != nil {
+			goutils.Error("StickySymbols.InitEx:LoadStrWeights",
+				slog.String("Weight", stickySymbols.Config.MoveTypeWeight),
+				goutils.Err(err))
+
+			return err
+		}
+
+		stickySymbols.Config.MoveTypeWeightVW = vw2
+	}
+
+	if stickySymbols.Config.MultiWeight != "" {
+		vw2, err := pool.LoadIntWeights(stickySymbols.Config.MultiWeight, stickySymbols.Config.UseFileMapping)
+		if err != nil {
+			goutils.Error("StickySymbols.InitEx:LoadIntWeights",
+				slog.String("Weight", stickySymbols.Config.MultiWeight),
+				goutils.Err(err))
+
+			return err
+		}
+
+		stickySymbols.Config.MultiWeightVW = vw2
+	}
+
+	for _, ctrl := range stickySymbols.Config.Controllers {
+		ctrl.Init()
+	}
+
+	stickySymbols.onInit(&stickySymbols.Config.BasicComponentConfig)
+
+	return nil
+}
+
+// OnProcControllers -
+func (stickySymbols *StickySymbols) ProcControllers(gameProp *GameProperty, plugin sgc7plugin.IPlugin, curpr *sgc7game.PlayResult, gp *GameParams, val int, strVal string) {
+	if len(stickySymbols.Config.Controllers) > 0 {
+		gameProp.procAwards(plugin, stickySymbols.Config.Controllers, curpr, gp)
+	}
+}
+
+func (stickySymbols *StickySymbols) isClear(basicCD *BasicComponentData) bool {
+	clear, isok := basicCD.GetConfigIntVal(CCVClear)
+	if isok {
+		return clear != 0
+	}
+
+	return false
+}
+
+// newSticky - 先随机倍数，再随机方向
+func (stickySymbols *StickySymbols) newSticky(plugin sgc7plugin.IPlugin, x int, y int, symbol int) (*StickySymbol, error) {
+	sticky := &StickySymbol{
+		X:        x,
+		Y:        y,
+		Symbol:   symbol,
+		Multi:    stickySymbols.Config.Multi,
+		Life:     stickySymbols.Config.Lifetime,
+		MoveType: stickySymbols.Config.MoveType,
+	}
+
+	if sticky.Multi <= 0 {
+		sticky.Multi = 1
+	}
+
+	if sticky.Life <= 0 {
+		sticky.Life = -1
+	}
+
+	if stickySymbols.Config.MultiWeightVW != nil {
+		cv, err := stickySymbols.Config.MultiWeightVW.RandVal(plugin)
+		if err != nil {
+			goutils.Error("StickySymbols.newSticky:MultiWeightVW.RandVal",
+				goutils.Err(err))
+
+			return nil, err
+		}
+
+		sticky.Multi = cv.Int()
+	}
+
+	if stickySymbols.Config.MoveTypeWeightVW != nil {
+		cv, err := stickySymbols.Config.MoveTypeWeightVW.RandVal(plugin)
+		if err != nil {
+			goutils.Error("StickySymbols.newSticky:MoveTypeWeightVW.RandVal",
+				goutils.Err(err))
+
+			return nil, err
+		}
+
+		sticky.MoveType = parseStickyMoveType(cv.String())
+	}
+
+	return sticky, nil
+}
+
+// playgame
+func (stickySymbols *StickySymbols) OnPlayGame(gameProp *GameProperty, curpr *sgc7game.PlayResult, gp *GameParams, plugin sgc7plugin.IPlugin,
+	cmd string, param string, ps sgc7game.IPlayerState, stake *sgc7game.Stake, prs []*sgc7game.PlayResult, icd IComponentData) (string, error) {
+
+	cd := icd.(*StickySymbolsData)
+	cd.onNewStep()
+
+	if stickySymbols.isClear(&cd.BasicComponentData) {
+		cd.RemovedNum += len(cd.Stickies)
+		cd.Stickies = nil
+
+		cd.SetConfigIntVal(CCVClear, 0)
+	}
+
+	gs := stickySymbols.GetTargetScene3(gameProp, curpr, prs, 0)
+
+	cd.update(gs.Width, gs.Height, stickySymbols.Config.AddMulti)
+
+	for x, arr := range gs.Arr {
+		for y, s := range arr {
+			if !slices.Contains(stickySymbols.Config.SymbolCodes, s) || cd.getSticky(x, y) != nil {
+				continue
+			}
+
+			sticky, err := stickySymbols.newSticky(plugin, x, y, s)
+			if err != nil {
+				goutils.Error("StickySymbols.OnPlayGame:newSticky",
+					goutils.Err(err))
+
+				return "", err
+			}
+
+			cd.Stickies = append(cd.Stickies, sticky)
+			cd.NewNum++
+		}
+	}
+
+	cd.rebuildPos()
+
+	if len(cd.Stickies) > 0 {
+		ngs := gs.CloneEx(gameProp.PoolScene)
+
+		for _, v := range cd.Stickies {
+			ngs.Arr[v.X][v.Y] = v.Symbol
+		}
+
+		stickySymbols.AddScene(gameProp, curpr, ngs, &cd.BasicComponentData)
+
+		if stickySymbols.Config.IsOutputMulti {
+			ogs := gameProp.PoolScene.New(gs.Width, gs.Height)
+			for _, arr := range ogs.Arr {
+				for y := range arr {
+					arr[y] = 1
+				}
+			}
+
+			for _, v := range cd.Stickies {
+				ogs.Arr[v.X][v.Y] = v.Multi
+			}
+
+			stickySymbols.AddOtherScene(gameProp, curpr, ogs, &cd.BasicComponentData)
+		}
+	} else if stickySymbols.Config.IsOutputMulti {
+		stickySymbols.ClearOtherScene(gameProp)
+	}
+
+	if cd.NewNum > 0 {
+		stickySymbols.ProcControllers(gameProp, plugin, curpr, gp, cd.NewNum, "")
+	}
+
+	nc := stickySymbols.onStepEnd(gameProp, curpr, gp, "")
+
+	return nc, nil
+}
+
+// OnAsciiGame - outpur to asciigame
+func (stickySymbols *StickySymbols) OnAsciiGame(gameProp *GameProperty, pr *sgc7game.PlayResult, lst []*sgc7game.PlayResult, mapSymbolColor *asciigame.SymbolColorMap, icd IComponentData) error {
+	cd := icd.(*StickySymbolsData)
+
+	if len(cd.UsedScenes) > 0 {
+		asciigame.OutputScene("after the sticky symbols", pr.Scenes[cd.UsedScenes[0]], mapSymbolColor)
+	}
+
+	if len(cd.UsedOtherScenes) > 0 {
+		asciigame.OutputOtherScene("The multi of the sticky symbols", pr.OtherScenes[cd.UsedOtherScenes[0]])
+	}
+
+	fmt.Printf("sticky symbols %v, new %v, removed %v\n", len(cd.Stickies), cd.NewNum, cd.RemovedNum)
+
+	return nil
+}
+
+// NewComponentData -
+func (stickySymbols *StickySymbols) NewComponentData() IComponentData {
+	return &StickySymbolsData{}
+}
+
+// OnStats2
+func (stickySymbols *StickySymbols) OnStats2(icd IComponentData, s2 *stats2.Cache, gameProp *GameProperty, gp *GameParams, pr *sgc7game.PlayResult, isOnStepEnd bool) {
+	stickySymbols.BasicComponent.OnStats2(icd, s2, gameProp, gp, pr, isOnStepEnd)
+
+	cd := icd.(*StickySymbolsData)
+
+	s2.ProcStatsIntVal(stickySymbols.GetName(), len(cd.Stickies))
+	s2.ProcStatsIntVal2(stickySymbols.GetName(), cd.getTotalMulti())
+}
+
+// NewStats2 -
+func (stickySymbols *StickySymbols) NewStats2(parent string) *stats2.Feature {
+	return stats2.NewFeature(parent, []stats2.Option{stats2.OptIntVal, stats2.OptIntVal2})
+}
+
+func NewStickySymbols(name string) IComponent {
+	return &StickySymbols{
+		BasicComponent: NewBasicComponent(name, 1),
+	}
+}
+
+// "symbols": ["WL"],
+// "lifetime": 0,
+// "moveType": "left",
+// "moveTypeWeight": "",
+// "multi": 2,
+// "multiWeight": "",
+// "addMulti": 0,
+// "outputMulti": true
+type jsonStickySymbols struct {
+	Symbols        []string `json:"symbols"`
+	Lifetime       int      `json:"lifetime"`
+	MoveType       string   `json:"moveType"`
+	MoveTypeWeight string   `json:"moveTypeWeight"`
+	Multi          int      `json:"multi"`
+	MultiWeight    string   `json:"multiWeight"`
+	AddMulti       int      `json:"addMulti"`
+	IsOutputMulti  bool     `json:"outputMulti"`
+}
+
+func (jcfg *jsonStickySymbols) build() *StickySymbolsConfig {
+	cfg := &StickySymbolsConfig{
+		Symbols:        slices.Clone(jcfg.Symbols),
+		Lifetime:       jcfg.Lifetime,
+		StrMoveType:    jcfg.MoveType,
+		MoveTypeWeight: jcfg.MoveTypeWeight,
+		Multi:          jcfg.Multi,
+		MultiWeight:    jcfg.MultiWeight,
+		AddMulti:       jcfg.AddMulti,
+		IsOutputMulti:  jcfg.IsOutputMulti,
+	}
+
+	return cfg
+}
+
+func parseStickySymbols(gamecfg *BetConfig, cell *ast.Node) (string, error) {
+	cfg, label, ctrls, err := getConfigInCell(cell)
+	if err != nil {
+		goutils.Error("parseStickySymbols:getConfigInCell",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	buf, err := cfg.MarshalJSON()
+	if err != nil {
+		goutils.Error("parseStickySymbols:MarshalJSON",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	data := &jsonStickySymbols{}
+
+	err = sonic.Unmarshal(buf, data)
+	if err != nil {
+		goutils.Error("parseStickySymbols:Unmarshal",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	cfgd := data.build()
+
+	if ctrls != nil {
+		awards, err := parseControllers(ctrls)
+		if err != nil {
+			goutils.Error("parseStickySymbols:parseControllers",
+				goutils.Err(err))
+
+			return "", err
+		}
+
+		cfgd.Controllers = awards
+	}
+
+	gamecfg.mapConfig[label] = cfgd
+	gamecfg.mapBasicConfig[label] = &cfgd.BasicComponentConfig
+
+	ccfg := &ComponentConfig{
+		Name: label,
+		Type: StickySymbolsTypeName,
+	}
+
+	gamecfg.Components = append(gamecfg.Components, ccfg)
+
+	return label, nil
+}
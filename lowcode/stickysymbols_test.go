@@ -0,0 +1,135 @@
+package lowcode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+	"github.com/zhs007/slotsgamecore7/sgc7pb"
+)
+
+func Test_StickySymbolsOnPlayGame(t *testing.T) {
+	pool := &GamePropertyPool{
+		DefaultPaytables: &sgc7game.PayTables{MapSymbols: map[string]int{"WL": 9, "A": 1}},
+	}
+
+	gameProp := &GameProperty{Pool: pool}
+	gameProp.PoolScene = sgc7game.NewGameScenePoolEx()
+	gameProp.rng = &stubRNG{}
+	gameProp.featureLevel = &stubFeatureLevel{}
+
+	stake := &sgc7game.Stake{CoinBet: 1, CashBet: 1}
+	gp := NewGameParam(stake, nil)
+	plugin := sgc7plugin.NewMockPlugin()
+
+	sticky := NewStickySymbols("sticky").(*StickySymbols)
+	err := sticky.InitEx(&StickySymbolsConfig{
+		BasicComponentConfig: BasicComponentConfig{DefaultNextComponent: "next"},
+		Symbols:              []string{"WL"},
+		StrMoveType:          "left",
+		Multi:                2,
+		AddMulti:             1,
+		IsOutputMulti:        true,
+	}, pool)
+	assert.NoError(t, err)
+
+	cd := sticky.NewComponentData().(*StickySymbolsData)
+	cd.OnNewGame(gameProp, sticky)
+
+	play := func(arr [][]int) *sgc7game.PlayResult {
+		gs, err := sgc7game.NewGameSceneWithArr2(arr)
+		assert.NoError(t, err)
+
+		gameProp.SceneStack = NewSceneStack(false)
+		gameProp.OtherSceneStack = NewSceneStack(true)
+		gameProp.SceneStack.Push("reels", gs)
+
+		pr := sgc7game.NewPlayResult("bg", 0, 0, "bg")
+		nc, err := sticky.OnPlayGame(gameProp, pr, gp, plugin, DefaultCmd, "", nil, stake, nil, cd)
+		assert.NoError(t, err)
+		assert.Equal(t, "next", nc)
+
+		return pr
+	}
+
+	// 落下一个 wild
+	pr := play([][]int{{1, 1, 1}, {1, 1, 1}, {1, 9, 1}})
+	assert.Equal(t, 1, cd.NewNum)
+	assert.Equal(t, []int{2, 1}, cd.Pos)
+	assert.Len(t, pr.OtherScenes, 1)
+	assert.Equal(t, []int{1, 2, 1}, pr.OtherScenes[0].Arr[2])
+
+	// 往左走一个轴，倍数加 1
+	pr = play([][]int{{1, 1, 1}, {1, 1, 1}, {1, 1, 1}})
+	assert.Equal(t, 0, cd.NewNum)
+	assert.Equal(t, []int{1, 9, 1}, pr.Scenes[0].Arr[1])
+	assert.Equal(t, []int{1, 1, 1}, pr.Scenes[0].Arr[2])
+	assert.Equal(t, 3, pr.OtherScenes[0].Arr[1][1])
+
+	// 原来位置上又落下一个 wild
+	play([][]int{{1, 1, 1}, {1, 9, 1}, {1, 1, 1}})
+	assert.Equal(t, 1, cd.NewNum)
+	assert.Equal(t, []int{0, 1, 1, 1}, cd.Pos)
+
+	number, isok := cd.GetValEx(CVNumber, GCVTypeNormal)
+	assert.True(t, isok)
+	assert.Equal(t, 2, number)
+
+	totalMulti, isok := cd.GetValEx(CVTotalMulti, GCVTypeNormal)
+	assert.True(t, isok)
+	assert.Equal(t, 4+2, totalMulti)
+
+	pbcd := cd.BuildPBComponentData().(*sgc7pb.StickySymbolsData)
+	assert.Len(t, pbcd.Stickies, 2)
+	assert.Equal(t, int32(4), pbcd.Stickies[0].Multi)
+	assert.Equal(t, int32(SMTLeft), pbcd.Stickies[0].MoveType)
+
+	cd1 := cd.Clone().(*StickySymbolsData)
+	cd1.Stickies[0].Multi = 100
+	assert.Equal(t, 4, cd.Stickies[0].Multi)
+
+	// 第一个移出去了
+	play([][]int{{1, 1, 1}, {1, 1, 1}, {1, 1, 1}})
+	assert.Equal(t, 1, cd.RemovedNum)
+	assert.Equal(t, []int{0, 1}, cd.Pos)
+	assert.Equal(t, 3, cd.Stickies[0].Multi)
+
+	// 清掉
+	cd.SetConfigIntVal(CCVClear, 1)
+	pr = play([][]int{{1, 1, 1}, {1, 1, 1}, {1, 1, 1}})
+	assert.Equal(t, 1, cd.RemovedNum)
+	assert.Empty(t, cd.Stickies)
+	assert.Empty(t, pr.Scenes)
+
+	// 不动的，保留 1 次
+	sticky = NewStickySymbols("sticky").(*StickySymbols)
+	err = sticky.InitEx(&StickySymbolsConfig{
+		BasicComponentConfig: BasicComponentConfig{DefaultNextComponent: "next"},
+		Symbols:              []string{"WL"},
+		Lifetime:             1,
+	}, pool)
+	assert.NoError(t, err)
+
+	cd = sticky.NewComponentData().(*StickySymbolsData)
+	cd.OnNewGame(gameProp, sticky)
+
+	play([][]int{{9, 1, 1}, {1, 1, 1}, {1, 1, 1}})
+	assert.Equal(t, 1, cd.Stickies[0].Multi)
+
+	pr = play([][]int{{1, 1, 1}, {1, 1, 1}, {1, 1, 1}})
+	assert.Equal(t, []int{9, 1, 1}, pr.Scenes[0].Arr[0])
+	assert.Empty(t, pr.OtherScenes)
+
+	play([][]int{{1, 1, 1}, {1, 1, 1}, {1, 1, 1}})
+	assert.Equal(t, 1, cd.RemovedNum)
+	assert.Empty(t, cd.Stickies)
+
+	err = NewStickySymbols("sticky").InitEx(&StickySymbolsConfig{Symbols: []string{"H1"}}, pool)
+	assert.ErrorIs(t, err, ErrInvalidSymbol)
+
+	err = NewStickySymbols("sticky").InitEx(&StickySymbolsConfig{Lifetime: -1}, pool)
+	assert.ErrorIs(t, err, ErrInvalidComponentConfig)
+
+	t.Logf("Test_StickySymbolsOnPlayGame OK")
+}
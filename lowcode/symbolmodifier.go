@@ -1,7 +1,6 @@
 package lowcode
 
 import (
-	"context"
 	"log/slog"
 	"os"
 
@@ -140,7 +139,7 @@ func (symbolModifier *SymbolModifier) chgSymbols(gameProp *GameProperty, plugin
 	copy(lst, symbolModifier.Config.TargetSymbolCodes)
 
 	for {
-		cr, err := plugin.Random(context.Background(), len(lst))
+		cr, err := plugin.Random(sgc7plugin.GetContext(plugin), len(lst))
 		if err != nil {
 			goutils.Error("SymbolModifier.chgSymbols:random symbols",
 				goutils.Err(err))
@@ -172,7 +171,7 @@ func (symbolModifier *SymbolModifier) procSymbolsRandPos(gameProp *GameProperty,
 		return false
 	}
 
-	ci, err := plugin.Random(context.Background(), len(lst)/2)
+	ci, err := plugin.Random(sgc7plugin.GetContext(plugin), len(lst)/2)
 	if err != nil {
 		goutils.Error("SymbolModifier.procSymbolsRandPos:random pos",
 			goutils.Err(err))
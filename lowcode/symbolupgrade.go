@@ -0,0 +1,576 @@
+package lowcode
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+
+	"github.com/bytedance/sonic"
+	"github.com/bytedance/sonic/ast"
+	"github.com/zhs007/goutils"
+	"github.com/zhs007/slotsgamecore7/asciigame"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+	"github.com/zhs007/slotsgamecore7/sgc7pb"
+	"github.com/zhs007/slotsgamecore7/stats2"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v2"
+)
+
+const SymbolUpgradeTypeName = "symbolUpgrade"
+
+type SymbolUpgradeType int
+
+const (
+	SUTShift SymbolUpgradeType = 0 // 每升一级，梯子上的每个符号都往上走一格，最多到最高的符号
+	SUTMerge SymbolUpgradeType = 1 // 升到 n 级，梯子上低于第 n 个的符号都变成第 n 个
+)
+
+func parseSymbolUpgradeType(str string) SymbolUpgradeType {
+	if str == "merge" {
+		return SUTMerge
+	}
+
+	return SUTShift
+}
+
+type SymbolUpgradeData struct {
+	BasicComponentData
+	Level       int
+	PrevLevel   int
+	UpgradedNum int
+}
+
+// OnNewGame -
+func (symbolUpgradeData *SymbolUpgradeData) OnNewGame(gameProp *GameProperty, component IComponent) {
+	symbolUpgradeData.BasicComponentData.OnNewGame(gameProp, component)
+
+	symbolUpgradeData.Level = 0
+	symbolUpgradeData.PrevLevel = 0
+	symbolUpgradeData.UpgradedNum = 0
+}
+
+// onNewStep -
+func (symbolUpgradeData *SymbolUpgradeData) onNewStep() {
+	symbolUpgradeData.UsedScenes = nil
+	symbolUpgradeData.PrevLevel = symbolUpgradeData.Level
+	symbolUpgradeData.UpgradedNum = 0
+}
+
+// Clone
+func (symbolUpgradeData *SymbolUpgradeData) Clone() IComponentData {
+	target := &SymbolUpgradeData{
+		BasicComponentData: symbolUpgradeData.CloneBasicComponentData(),
+		Level:              symbolUpgradeData.Level,
+		PrevLevel:          symbolUpgradeData.PrevLevel,
+		UpgradedNum:        symbolUpgradeData.UpgradedNum,
+	}
+
+	return target
+}
+
+// BuildPBComponentData
+func (symbolUpgradeData *SymbolUpgradeData) BuildPBComponentData() proto.Message {
+	pbcd := &sgc7pb.SymbolUpgradeData{
+		BasicComponentData: symbolUpgradeData.BuildPBBasicComponentData(),
+		Level:              int32(symbolUpgradeData.Level),
+		UpgradedNum:        int32(symbolUpgradeData.UpgradedNum),
+	}
+
+	return pbcd
+}
+
+// GetValEx -
+func (symbolUpgradeData *SymbolUpgradeData) GetValEx(key string, getType GetComponentValType) (int, bool) {
+	switch key {
+	case CVLevel, CVValue:
+		return symbolUpgradeData.Level, true
+	case CVNumber:
+		return symbolUpgradeData.UpgradedNum, true
+	}
+
+	return symbolUpgradeData.BasicComponentData.GetValEx(key, getType)
+}
+
+// symbolUpgradeReel - 升级后的轮带来自哪个轮带，是第几级
+type symbolUpgradeReel struct {
+	reelSet string
+	level   int
+}
+
+// SymbolUpgradeConfig - configuration for SymbolUpgrade
+//
+//	symbols 是从低到高排好序的符号，等级来自 collector 或者 levelVal，
+//	如果配置了 levelThresholds，等级就是达到的门槛数量，
+//	reelSets 会在初始化时为每个等级生成升级后的轮带，之后 basicReels 里的组件会切换到当前等级的轮带
+type SymbolUpgradeConfig struct {
+	BasicComponentConfig `yaml:",inline" json:",inline"`
+	Symbols              []string                      `yaml:"symbols" json:"symbols"`
+	SymbolCodes          []int                         `yaml:"-" json:"-"`
+	StrUpgradeType       string                        `yaml:"upgradeType" json:"upgradeType"` // shift or merge
+	UpgradeType          SymbolUpgradeType             `yaml:"-" json:"-"`
+	Collector            string                        `yaml:"collector" json:"collector"`             // 用 collector 的 value 作为等级
+	LevelVal             string                        `yaml:"levelVal" json:"levelVal"`               // 没有 collector 时，用这个组件值作为等级
+	LevelThresholds      []int                         `yaml:"levelThresholds" json:"levelThresholds"` // 升到每一级需要的值
+	ReelSets             []string                      `yaml:"reelSets" json:"reelSets"`
+	BasicReels           []string                      `yaml:"basicReels" json:"basicReels"`
+	MaxLevel             int                           `yaml:"-" json:"-"`
+	MapUpgradedReels     map[string][]string           `yaml:"-" json:"-"` // 下标是等级，0 就是原始轮带
+	mapReelLevel         map[string]*symbolUpgradeReel `yaml:"-" json:"-"`
+	Controllers          []*Award                      `yaml:"controllers" json:"controllers"` // 升级时执行
+}
+
+// SetLinkComponent
+func (cfg *SymbolUpgradeConfig) SetLinkComponent(link string, componentName string) {
+	if link == "next" {
+		cfg.DefaultNextComponent = componentName
+	}
+}
+
+type SymbolUpgrade struct {
+	*BasicComponent `json:"-"`
+	Config          *SymbolUpgradeConfig `json:"config"`
+}
+
+// Init -
+func (symbolUpgrade *SymbolUpgrade) Init(fn string, pool *GamePropertyPool) error {
+	data, err := os.ReadFile(fn)
+	if err != nil {
+		goutils.Error("SymbolUpgrade.Init:ReadFile",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return err
+	}
+
+	cfg := &SymbolUpgradeConfig{}
+
+	err = yaml.Unmarshal(data, cfg)
+	if err != nil {
+		goutils.Error("SymbolUpgrade.Init:Unmarshal",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return err
+	}
+
+	return symbolUpgrade.InitEx(cfg, pool)
+}
+
+// InitEx -
+func (symbolUpgrade *SymbolUpgrade) InitEx(cfg any, pool *GamePropertyPool) error {
+	symbolUpgrade.Config = cfg.(*SymbolUpgradeConfig)
+	symbolUpgrade.Config.ComponentType = SymbolUpgradeTypeName
+
+	if len(symbolUpgrade.Config.Symbols) < 2 {
+		goutils.Error("SymbolUpgrade.InitEx:Symbols",
+			slog.Int("symbols", len(symbolUpgrade.Config.Symbols)),
+			goutils.Err(ErrInvalidComponentConfig))
+
+		return ErrInvalidComponentConfig
+	}
+
+	symbolUpgrade.Config.SymbolCodes = nil
+	for _, s := range symbolUpgrade.Config.Symbols {
+		sc, isok := pool.DefaultPaytables.MapSymbols[s]
+		if !isok || slices.Contains(symbolUpgrade.Config.SymbolCodes, sc) {
+			goutils.Error("SymbolUpgrade.InitEx:Symbol",
+				slog.String("symbol", s),
+				goutils.Err(ErrInvalidSymbol))
+
+			return ErrInvalidSymbol
+		}
+
+		symbolUpgrade.Config.SymbolCodes = append(symbolUpgrade.Config.SymbolCodes, sc)
+	}
+
+	if symbolUpgrade.Config.Collector == "" && symbolUpgrade.Config.LevelVal == "" {
+		goutils.Error("SymbolUpgrade.InitEx:Level",
+			goutils.Err(ErrInvalidComponentConfig))
+
+		return ErrInvalidComponentConfig
+	}
+
+	for i, v := range symbolUpgrade.Config.LevelThresholds {
+		if v <= 0 || (i > 0 && v <= symbolUpgrade.Config.LevelThresholds[i-1]) {
+			goutils.Error("SymbolUpgrade.InitEx:LevelThresholds",
+				slog.Any("levelThresholds", symbolUpgrade.Config.LevelThresholds),
+				goutils.Err(ErrInvalidComponentConfig))
+
+			return ErrInvalidComponentConfig
+		}
+	}
+
+	symbolUpgrade.Config.UpgradeType = parseSymbolUpgradeType(symbolUpgrade.Config.StrUpgradeType)
+	symbolUpgrade.Config.MaxLevel = len(symbolUpgrade.Config.SymbolCodes) - 1
+
+	if len(symbolUpgrade.Config.LevelThresholds) > 0 && len(symbolUpgrade.Config.LevelThresholds) < symbolUpgrade.Config.MaxLevel {
+		symbolUpgrade.Config.MaxLevel = len(symbolUpgrade.Config.LevelThresholds)
+	}
+
+	symbolUpgrade.Config.MapUpgradedReels = make(map[string][]string)
+	symbolUpgrade.Config.mapReelLevel = make(map[string]*symbolUpgradeReel)
+
+	for _, reelSet := range symbolUpgrade.Config.ReelSets {
+		rd, isok := pool.Config.MapReels[reelSet]
+		if !isok {
+			goutils.Error("SymbolUpgrade.InitEx:ReelSets",
+				slog.String("reelSet", reelSet),
+				goutils.Err(ErrInvalidReels))
+
+			return ErrInvalidReels
+		}
+
+		names := []string{reelSet}
+		symbolUpgrade.Config.mapReelLevel[reelSet] = &symbolUpgradeReel{reelSet: reelSet}
+
+		for level := 1; level <= symbolUpgrade.Config.MaxLevel; level++ {
+			name := fmt.Sprintf("%v-%v-lv%v", reelSet, symbolUpgrade.GetName(), level)
+
+			pool.Config.MapReels[name] = symbolUpgrade.upgradeReels(rd, level)
+
+			names = append(names, name)
+			symbolUpgrade.Config.mapReelLevel[name] = &symbolUpgradeReel{reelSet: reelSet, level: level}
+		}
+
+		symbolUpgrade.Config.MapUpgradedReels[reelSet] = names
+	}
+
+	for _, award := range symbolUpgrade.Config.Controllers {
+		award.Init()
+	}
+
+	symbolUpgrade.onInit(&symbolUpgrade.Config.BasicComponentConfig)
+
+	return nil
+}
+
+// upgradeSymbol - 从 from 级升到 to 级，不在梯子上的符号不变
+func (symbolUpgrade *SymbolUpgrade) upgradeSymbol(s int, from int, to int) int {
+	if to <= from {
+		return s
+	}
+
+	i := slices.Index(symbolUpgrade.Config.SymbolCodes, s)
+	if i < 0 {
+		return s
+	}
+
+	if symbolUpgrade.Config.UpgradeType == SUTMerge {
+		if i < to {
+			return symbolUpgrade.Config.SymbolCodes[to]
+		}
+
+		return s
+	}
+
+	return symbolUpgrade.Config.SymbolCodes[min(i+to-from, len(symbolUpgrade.Config.SymbolCodes)-1)]
+}
+
+// upgradeReels -
+func (symbolUpgrade *SymbolUpgrade) upgradeReels(rd *sgc7game.ReelsData, level int) *sgc7game.ReelsData {
+	nrd := &sgc7game.ReelsData{
+		Reels: make([][]int, len(rd.Reels)),
+	}
+
+	for x, reel := range rd.Reels {
+		nrd.Reels[x] = make([]int, len(reel))
+
+		for y, s := range reel {
+			nrd.Reels[x][y] = symbolUpgrade.upgradeSymbol(s, 0, level)
+		}
+	}
+
+	return nrd
+}
+
+// getLevel -
+func (symbolUpgrade *SymbolUpgrade) getLevel(gameProp *GameProperty) (int, error) {
+	var val int
+	var err error
+
+	if symbolUpgrade.Config.Collector != "" {
+		val, err = gameProp.GetComponentVal2(symbolUpgrade.Config.Collector, CVValue)
+	} else {
+		val, err = gameProp.GetComponentVal(symbolUpgrade.Config.LevelVal)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	if len(symbolUpgrade.Config.LevelThresholds) > 0 {
+		level := 0
+		for _, v := range symbolUpgrade.Config.LevelThresholds {
+			if val < v {
+				break
+			}
+
+			level++
+		}
+
+		val = level
+	}
+
+	return max(min(val, symbolUpgrade.Config.MaxLevel), 0), nil
+}
+
+// getSceneLevel - 用升级后的轮带转出来的符号已经升过级了
+func (symbolUpgrade *SymbolUpgrade) getSceneLevel(gs *sgc7game.GameScene) int {
+	reel, isok := symbolUpgrade.Config.mapReelLevel[gs.ReelName]
+	if isok {
+		return reel.level
+	}
+
+	return 0
+}
+
+// chgBasicReels - 让后面的 basicReels 用当前等级的轮带
+func (symbolUpgrade *SymbolUpgrade) chgBasicReels(gameProp *GameProperty, level int) error {
+	for _, name := range symbolUpgrade.Config.BasicReels {
+		ic, isok := gameProp.Components.MapComponents[name]
+		if !isok {
+			goutils.Error("SymbolUpgrade.chgBasicReels:MapComponents",
+				slog.String("component", name),
+				goutils.Err(ErrInvalidComponent))
+
+			return ErrInvalidComponent
+		}
+
+		basicReels, isok := ic.(*BasicReels)
+		if !isok {
+			goutils.Error("SymbolUpgrade.chgBasicReels:BasicReels",
+				slog.String("component", name),
+				goutils.Err(ErrInvalidComponentConfig))
+
+			return ErrInvalidComponentConfig
+		}
+
+		cd := gameProp.GetCurComponentDataWithName(name)
+		if cd == nil {
+			goutils.Error("SymbolUpgrade.chgBasicReels:GetCurComponentDataWithName",
+				slog.String("component", name),
+				goutils.Err(ErrInvalidComponent))
+
+			return ErrInvalidComponent
+		}
+
+		curReelSet := cd.GetConfigVal(CCVReelSet)
+		if curReelSet == "" {
+			curReelSet = basicReels.Config.ReelSet
+		}
+
+		reel, isok := symbolUpgrade.Config.mapReelLevel[curReelSet]
+		if !isok {
+			// 不在 reelSets 里的轮带不升级
+			continue
+		}
+
+		err := gameProp.SetComponentConfigVal(fmt.Sprintf("%v.%v", name, CCVReelSet), symbolUpgrade.Config.MapUpgradedReels[reel.reelSet][level])
+		if err != nil {
+			goutils.Error("SymbolUpgrade.chgBasicReels:SetComponentConfigVal",
+				slog.String("component", name),
+				goutils.Err(err))
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+// OnProcControllers -
+func (symbolUpgrade *SymbolUpgrade) ProcControllers(gameProp *GameProperty, plugin sgc7plugin.IPlugin, curpr *sgc7game.PlayResult, gp *GameParams, val int, strVal string) {
+	if len(symbolUpgrade.Config.Controllers) > 0 {
+		gameProp.procAwards(plugin, symbolUpgrade.Config.Controllers, curpr, gp)
+	}
+}
+
+// playgame
+func (symbolUpgrade *SymbolUpgrade) OnPlayGame(gameProp *GameProperty, curpr *sgc7game.PlayResult, gp *GameParams, plugin sgc7plugin.IPlugin,
+	cmd string, param string, ps sgc7game.IPlayerState, stake *sgc7game.Stake, prs []*sgc7game.PlayResult, icd IComponentData) (string, error) {
+
+	cd := icd.(*SymbolUpgradeData)
+	cd.onNewStep()
+
+	level, err := symbolUpgrade.getLevel(gameProp)
+	if err != nil {
+		goutils.Error("SymbolUpgrade.OnPlayGame:getLevel",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	cd.Level = level
+
+	gs := symbolUpgrade.GetTargetScene3(gameProp, curpr, prs, 0)
+	if gs != nil {
+		from := symbolUpgrade.getSceneLevel(gs)
+
+		if level > from {
+			var ngs *sgc7game.GameScene
+
+			for x, arr := range gs.Arr {
+				for y, s := range arr {
+					ns := symbolUpgrade.upgradeSymbol(s, from, level)
+					if ns != s {
+						if ngs == nil {
+							ngs = gs.CloneEx(gameProp.PoolScene)
+						}
+
+						ngs.Arr[x][y] = ns
+						cd.UpgradedNum++
+					}
+				}
+			}
+
+			if ngs != nil {
+				reel, isok := symbolUpgrade.Config.mapReelLevel[gs.ReelName]
+				if isok {
+					ngs.ReelName = symbolUpgrade.Config.MapUpgradedReels[reel.reelSet][level]
+				}
+
+				symbolUpgrade.AddScene(gameProp, curpr, ngs, &cd.BasicComponentData)
+			}
+		}
+	}
+
+	err = symbolUpgrade.chgBasicReels(gameProp, level)
+	if err != nil {
+		goutils.Error("SymbolUpgrade.OnPlayGame:chgBasicReels",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	if cd.Level > cd.PrevLevel {
+		symbolUpgrade.ProcControllers(gameProp, plugin, curpr, gp, cd.Level, "")
+	}
+
+	nc := symbolUpgrade.onStepEnd(gameProp, curpr, gp, "")
+
+	return nc, nil
+}
+
+// OnAsciiGame - outpur to asciigame
+func (symbolUpgrade *SymbolUpgrade) OnAsciiGame(gameProp *GameProperty, pr *sgc7game.PlayResult, lst []*sgc7game.PlayResult, mapSymbolColor *asciigame.SymbolColorMap, icd IComponentData) error {
+	cd := icd.(*SymbolUpgradeData)
+
+	fmt.Printf("symbolUpgrade %v: level %v\n", symbolUpgrade.GetName(), cd.Level)
+
+	if len(cd.UsedScenes) > 0 {
+		asciigame.OutputScene("after symbolUpgrade", pr.Scenes[cd.UsedScenes[0]], mapSymbolColor)
+	}
+
+	return nil
+}
+
+// NewComponentData -
+func (symbolUpgrade *SymbolUpgrade) NewComponentData() IComponentData {
+	return &SymbolUpgradeData{}
+}
+
+// OnStats2
+func (symbolUpgrade *SymbolUpgrade) OnStats2(icd IComponentData, s2 *stats2.Cache, gameProp *GameProperty, gp *GameParams, pr *sgc7game.PlayResult, isOnStepEnd bool) {
+	symbolUpgrade.BasicComponent.OnStats2(icd, s2, gameProp, gp, pr, isOnStepEnd)
+
+	cd := icd.(*SymbolUpgradeData)
+
+	s2.ProcStatsIntVal(symbolUpgrade.GetName(), cd.Level)
+}
+
+// NewStats2 -
+func (symbolUpgrade *SymbolUpgrade) NewStats2(parent string) *stats2.Feature {
+	return stats2.NewFeature(parent, []stats2.Option{stats2.OptIntVal})
+}
+
+func NewSymbolUpgrade(name string) IComponent {
+	return &SymbolUpgrade{
+		BasicComponent: NewBasicComponent(name, 1),
+	}
+}
+
+// "symbols": ["L1", "L2", "L3", "H1"],
+// "upgradeType": "shift",
+// "collector": "bg-collector",
+// "levelThresholds": [4, 8, 12],
+// "reelSets": ["fg-reel"],
+// "basicReels": ["fg-spin"]
+type jsonSymbolUpgrade struct {
+	Symbols         []string `json:"symbols"`
+	UpgradeType     string   `json:"upgradeType"`
+	Collector       string   `json:"collector"`
+	LevelVal        string   `json:"levelVal"`
+	LevelThresholds []int    `json:"levelThresholds"`
+	ReelSets        []string `json:"reelSets"`
+	BasicReels      []string `json:"basicReels"`
+}
+
+func (jcfg *jsonSymbolUpgrade) build() *SymbolUpgradeConfig {
+	cfg := &SymbolUpgradeConfig{
+		Symbols:         slices.Clone(jcfg.Symbols),
+		StrUpgradeType:  jcfg.UpgradeType,
+		Collector:       jcfg.Collector,
+		LevelVal:        jcfg.LevelVal,
+		LevelThresholds: slices.Clone(jcfg.LevelThresholds),
+		ReelSets:        slices.Clone(jcfg.ReelSets),
+		BasicReels:      slices.Clone(jcfg.BasicReels),
+	}
+
+	return cfg
+}
+
+func parseSymbolUpgrade(gamecfg *BetConfig, cell *ast.Node) (string, error) {
+	cfg, label, ctrls, err := getConfigInCell(cell)
+	if err != nil {
+		goutils.Error("parseSymbolUpgrade:getConfigInCell",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	buf, err := cfg.MarshalJSON()
+	if err != nil {
+		goutils.Error("parseSymbolUpgrade:MarshalJSON",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	data := &jsonSymbolUpgrade{}
+
+	err = sonic.Unmarshal(buf, data)
+	if err != nil {
+		goutils.Error("parseSymbolUpgrade:Unmarshal",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	cfgd := data.build()
+
+	if ctrls != nil {
+		awards, err := parseControllers(ctrls)
+		if err != nil {
+			goutils.Error("parseSymbolUpgrade:parseControllers",
+				goutils.Err(err))
+
+			return "", err
+		}
+
+		cfgd.Controllers = awards
+	}
+
+	gamecfg.mapConfig[label] = cfgd
+	gamecfg.mapBasicConfig[label] = &cfgd.BasicComponentConfig
+
+	ccfg := &ComponentConfig{
+		Name: label,
+		Type: SymbolUpgradeTypeName,
+	}
+
+	gamecfg.Components = append(gamecfg.Components, ccfg)
+
+	return label, nil
+}
@@ -0,0 +1,164 @@
+package lowcode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+	"github.com/zhs007/slotsgamecore7/sgc7pb"
+)
+
+func Test_SymbolUpgradeOnPlayGame(t *testing.T) {
+	pool := &GamePropertyPool{
+		DefaultPaytables: &sgc7game.PayTables{MapSymbols: map[string]int{"WL": 0, "L1": 1, "L2": 2, "L3": 3, "H1": 4}},
+		Config: &Config{
+			Width:  3,
+			Height: 3,
+			MapReels: map[string]*sgc7game.ReelsData{
+				"fg": {Reels: [][]int{{1, 2, 3, 4}, {1, 0, 2, 3}, {4, 3, 2, 1}}},
+			},
+		},
+	}
+
+	gameProp := &GameProperty{Pool: pool}
+	gameProp.PoolScene = sgc7game.NewGameScenePoolEx()
+	gameProp.rng = &stubRNG{}
+	gameProp.featureLevel = &stubFeatureLevel{}
+	gameProp.MapVals = map[int]int{GamePropWidth: 3, GamePropHeight: 3}
+	gameProp.mapStr = map[string]string{}
+	gameProp.Components = NewComponentList()
+	gameProp.callStack = NewCallStack()
+	gameProp.callStack.OnNewGame()
+
+	stake := &sgc7game.Stake{CoinBet: 1, CashBet: 1}
+	gp := NewGameParam(stake, nil)
+	plugin := sgc7plugin.NewMockPlugin()
+
+	spin := NewBasicReels("spin").(*BasicReels)
+	err := spin.InitEx(&BasicReelsConfig{ReelSet: "fg"}, pool)
+	assert.NoError(t, err)
+
+	gameProp.Components.MapComponents["spin"] = spin
+	gameProp.Components.MapComponents["col"] = NewCollector("col")
+
+	col := &CollectorData{}
+	gameProp.callStack.nodes[0].MapComponentData["col"] = col
+
+	symbolUpgrade := NewSymbolUpgrade("up").(*SymbolUpgrade)
+	err = symbolUpgrade.InitEx(&SymbolUpgradeConfig{
+		BasicComponentConfig: BasicComponentConfig{DefaultNextComponent: "next"},
+		Symbols:              []string{"L1", "L2", "L3", "H1"},
+		Collector:            "col",
+		LevelThresholds:      []int{2, 4, 6},
+		ReelSets:             []string{"fg"},
+		BasicReels:           []string{"spin"},
+	}, pool)
+	assert.NoError(t, err)
+
+	// 每一级的轮带在初始化时就生成好了
+	assert.Equal(t, []string{"fg", "fg-up-lv1", "fg-up-lv2", "fg-up-lv3"}, symbolUpgrade.Config.MapUpgradedReels["fg"])
+	assert.Equal(t, [][]int{{2, 3, 4, 4}, {2, 0, 3, 4}, {4, 4, 3, 2}}, pool.Config.MapReels["fg-up-lv1"].Reels)
+	assert.Equal(t, [][]int{{4, 4, 4, 4}, {4, 0, 4, 4}, {4, 4, 4, 4}}, pool.Config.MapReels["fg-up-lv3"].Reels)
+
+	cd := symbolUpgrade.NewComponentData().(*SymbolUpgradeData)
+	cd.OnNewGame(gameProp, symbolUpgrade)
+
+	play := func(gs *sgc7game.GameScene) *sgc7game.PlayResult {
+		gameProp.SceneStack = NewSceneStack(false)
+		gameProp.OtherSceneStack = NewSceneStack(true)
+		gameProp.SceneStack.Push("reels", gs)
+
+		pr := sgc7game.NewPlayResult("bg", 0, 0, "bg")
+		nc, err := symbolUpgrade.OnPlayGame(gameProp, pr, gp, plugin, DefaultCmd, "", nil, stake, nil, cd)
+		assert.NoError(t, err)
+		assert.Equal(t, "next", nc)
+
+		return pr
+	}
+
+	gs, err := sgc7game.NewGameSceneWithArr2([][]int{{1, 2, 3}, {1, 0, 2}, {4, 3, 2}})
+	assert.NoError(t, err)
+	gs.ReelName = "fg"
+
+	// 还没升级
+	col.Val = 1
+	pr := play(gs)
+	assert.Equal(t, 0, cd.Level)
+	assert.Empty(t, pr.Scenes)
+
+	col.Val = 3
+	pr = play(gs)
+	assert.Equal(t, 1, cd.Level)
+	assert.Equal(t, 7, cd.UpgradedNum)
+	assert.Len(t, pr.Scenes, 1)
+	assert.Equal(t, [][]int{{2, 3, 4}, {2, 0, 3}, {4, 4, 3}}, pr.Scenes[0].Arr)
+	assert.Equal(t, "fg-up-lv1", pr.Scenes[0].ReelName)
+
+	spincd := gameProp.GetCurComponentDataWithName("spin")
+	assert.Equal(t, "fg-up-lv1", spincd.GetConfigVal(CCVReelSet))
+
+	// 后面的 basicReels 用升级后的轮带
+	gameProp.SceneStack = NewSceneStack(false)
+	pr = sgc7game.NewPlayResult("bg", 0, 0, "bg")
+	_, err = spin.OnPlayGame(gameProp, pr, gp, plugin, DefaultCmd, "", nil, stake, nil, spincd)
+	assert.NoError(t, err)
+	assert.Equal(t, "fg-up-lv1", pr.Scenes[0].ReelName)
+	assert.Equal(t, []int{2, 3, 4}, pr.Scenes[0].Arr[0])
+
+	// 用升级后的轮带转出来的，只需要再升一级
+	col.Val = 4
+	pr = play(pr.Scenes[0])
+	assert.Equal(t, 2, cd.Level)
+	assert.Equal(t, 1, cd.PrevLevel)
+	assert.Equal(t, [][]int{{3, 4, 4}, {3, 0, 4}, {4, 4, 4}}, pr.Scenes[0].Arr)
+	assert.Equal(t, "fg-up-lv2", spincd.GetConfigVal(CCVReelSet))
+
+	level, isok := cd.GetValEx(CVLevel, GCVTypeNormal)
+	assert.True(t, isok)
+	assert.Equal(t, 2, level)
+
+	pbcd := cd.BuildPBComponentData().(*sgc7pb.SymbolUpgradeData)
+	assert.Equal(t, int32(2), pbcd.Level)
+	assert.Equal(t, int32(cd.UpgradedNum), pbcd.UpgradedNum)
+
+	cd1 := cd.Clone().(*SymbolUpgradeData)
+	assert.Equal(t, cd.Level, cd1.Level)
+
+	// 超过最高等级
+	col.Val = 100
+	play(gs)
+	assert.Equal(t, 3, cd.Level)
+
+	err = NewSymbolUpgrade("up").InitEx(&SymbolUpgradeConfig{Symbols: []string{"L1", "H2"}, Collector: "col"}, pool)
+	assert.ErrorIs(t, err, ErrInvalidSymbol)
+
+	err = NewSymbolUpgrade("up").InitEx(&SymbolUpgradeConfig{Symbols: []string{"L1", "L2"}}, pool)
+	assert.ErrorIs(t, err, ErrInvalidComponentConfig)
+
+	t.Logf("Test_SymbolUpgradeOnPlayGame OK")
+}
+
+func Test_SymbolUpgradeMerge(t *testing.T) {
+	pool := &GamePropertyPool{
+		DefaultPaytables: &sgc7game.PayTables{MapSymbols: map[string]int{"L1": 1, "L2": 2, "L3": 3, "H1": 4}},
+		Config:           &Config{MapReels: map[string]*sgc7game.ReelsData{}},
+	}
+
+	symbolUpgrade := NewSymbolUpgrade("up").(*SymbolUpgrade)
+	err := symbolUpgrade.InitEx(&SymbolUpgradeConfig{
+		Symbols:        []string{"L1", "L2", "L3", "H1"},
+		StrUpgradeType: "merge",
+		LevelVal:       "col.value",
+	}, pool)
+	assert.NoError(t, err)
+
+	assert.Equal(t, 3, symbolUpgrade.upgradeSymbol(1, 0, 2))
+	assert.Equal(t, 3, symbolUpgrade.upgradeSymbol(2, 1, 2))
+	assert.Equal(t, 3, symbolUpgrade.upgradeSymbol(3, 0, 2))
+	assert.Equal(t, 4, symbolUpgrade.upgradeSymbol(4, 0, 2))
+	assert.Equal(t, 2, symbolUpgrade.upgradeSymbol(2, 2, 2))
+	assert.Equal(t, 9, symbolUpgrade.upgradeSymbol(9, 0, 3))
+
+	t.Logf("Test_SymbolUpgradeMerge OK")
+}
@@ -0,0 +1,758 @@
+package lowcode
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/bytedance/sonic/ast"
+	"github.com/zhs007/goutils"
+	"github.com/zhs007/slotsgamecore7/asciigame"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+	"github.com/zhs007/slotsgamecore7/sgc7pb"
+	"github.com/zhs007/slotsgamecore7/stats2"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v2"
+)
+
+const TrailBoardTypeName = "trailBoard"
+
+// TrailBoardEndBranch - 走完或落在 end 格子上时的分支
+const TrailBoardEndBranch = "end"
+
+type TrailCellType int
+
+const (
+	TCTEmpty   TrailCellType = 0 // 什么都没有
+	TCTPrize   TrailCellType = 1 // 奖励，先累积起来，value 是 bet 的倍数
+	TCTMulti   TrailCellType = 2 // 累积奖励的倍数乘上 value
+	TCTAdvance TrailCellType = 3 // 再往前走 value 格
+	TCTBack    TrailCellType = 4 // 往回走 value 格
+	TCTCollect TrailCellType = 5 // 把累积的奖励乘上倍数以后支付
+	TCTEnd     TrailCellType = 6 // 支付累积的奖励，然后结束
+)
+
+func parseTrailCellType(str string) TrailCellType {
+	str = strings.ToLower(str)
+	switch str {
+	case "prize":
+		return TCTPrize
+	case "multi":
+		return TCTMulti
+	case "advance":
+		return TCTAdvance
+	case "back":
+		return TCTBack
+	case "collect":
+		return TCTCollect
+	case "end":
+		return TCTEnd
+	}
+
+	return TCTEmpty
+}
+
+// TrailCell - 一个格子
+type TrailCell struct {
+	StrType string        `yaml:"type" json:"type"` // empty、prize、multi、advance、back、collect、end
+	Type    TrailCellType `yaml:"-" json:"-"`
+	Value   int           `yaml:"value" json:"value"`
+}
+
+type TrailBoardPS struct {
+	Position    int `json:"position"`
+	Multi       int `json:"multi"`
+	PendingWins int `json:"pendingWins"`
+}
+
+// SetPublicJson
+func (ps *TrailBoardPS) SetPublicJson(str string) error {
+	err := sonic.UnmarshalString(str, ps)
+	if err != nil {
+		goutils.Error("TrailBoardPS.SetPublicJson:UnmarshalString",
+			goutils.Err(err))
+
+		return err
+	}
+
+	return nil
+}
+
+// SetPrivateJson
+func (ps *TrailBoardPS) SetPrivateJson(str string) error {
+	return nil
+}
+
+// GetPublicJson
+func (ps *TrailBoardPS) GetPublicJson() string {
+	str, err := sonic.MarshalString(ps)
+	if err != nil {
+		goutils.Error("TrailBoardPS.GetPublicJson:MarshalString",
+			goutils.Err(err))
+
+		return ""
+	}
+
+	return str
+}
+
+// GetPrivateJson
+func (ps *TrailBoardPS) GetPrivateJson() string {
+	return ""
+}
+
+// Clone
+func (ps *TrailBoardPS) Clone() IComponentPS {
+	return &TrailBoardPS{
+		Position:    ps.Position,
+		Multi:       ps.Multi,
+		PendingWins: ps.PendingWins,
+	}
+}
+
+func newTrailBoardPS() *TrailBoardPS {
+	return &TrailBoardPS{
+		Position: -1,
+		Multi:    1,
+	}
+}
+
+type TrailBoardData struct {
+	BasicComponentData
+	Position    int   // 当前位置，-1 是起点，在第一个格子前面
+	Steps       int   // 这一步走了几格
+	Path        []int // 这一步落下的格子，包括 advance 和 back 带来的
+	Multi       int   // 累积奖励的倍数
+	PendingWins int   // 还没支付的累积奖励，bet 的倍数
+	Wins        int   // 这一步支付的奖励，bet 的倍数
+	IsEnded     bool  // 这一步结束了，状态已经回到起点
+}
+
+// OnNewGame -
+func (trailBoardData *TrailBoardData) OnNewGame(gameProp *GameProperty, component IComponent) {
+	trailBoardData.BasicComponentData.OnNewGame(gameProp, component)
+
+	trailBoardData.reset()
+	trailBoardData.Steps = 0
+	trailBoardData.Path = nil
+	trailBoardData.Wins = 0
+	trailBoardData.IsEnded = false
+}
+
+// onNewStep -
+func (trailBoardData *TrailBoardData) onNewStep() {
+	trailBoardData.UsedResults = nil
+	trailBoardData.Steps = 0
+	trailBoardData.Path = nil
+	trailBoardData.Wins = 0
+	trailBoardData.IsEnded = false
+}
+
+// reset - 回到起点
+func (trailBoardData *TrailBoardData) reset() {
+	trailBoardData.Position = -1
+	trailBoardData.Multi = 1
+	trailBoardData.PendingWins = 0
+}
+
+func (trailBoardData *TrailBoardData) loadPS(ps *TrailBoardPS) {
+	trailBoardData.Position = ps.Position
+	trailBoardData.Multi = ps.Multi
+	trailBoardData.PendingWins = ps.PendingWins
+}
+
+func (trailBoardData *TrailBoardData) savePS(ps *TrailBoardPS) {
+	ps.Position = trailBoardData.Position
+	ps.Multi = trailBoardData.Multi
+	ps.PendingWins = trailBoardData.PendingWins
+}
+
+// Clone
+func (trailBoardData *TrailBoardData) Clone() IComponentData {
+	target := &TrailBoardData{
+		BasicComponentData: trailBoardData.CloneBasicComponentData(),
+		Position:           trailBoardData.Position,
+		Steps:              trailBoardData.Steps,
+		Path:               slices.Clone(trailBoardData.Path),
+		Multi:              trailBoardData.Multi,
+		PendingWins:        trailBoardData.PendingWins,
+		Wins:               trailBoardData.Wins,
+		IsEnded:            trailBoardData.IsEnded,
+	}
+
+	return target
+}
+
+// BuildPBComponentData
+func (trailBoardData *TrailBoardData) BuildPBComponentData() proto.Message {
+	pbcd := &sgc7pb.TrailBoardData{
+		BasicComponentData: trailBoardData.BuildPBBasicComponentData(),
+		Position:           int32(trailBoardData.Position),
+		Steps:              int32(trailBoardData.Steps),
+		Multi:              int32(trailBoardData.Multi),
+		PendingWins:        int32(trailBoardData.PendingWins),
+		Wins:               int32(trailBoardData.Wins),
+		IsEnded:            trailBoardData.IsEnded,
+	}
+
+	for _, v := range trailBoardData.Path {
+		pbcd.Path = append(pbcd.Path, int32(v))
+	}
+
+	return pbcd
+}
+
+// GetValEx -
+func (trailBoardData *TrailBoardData) GetValEx(key string, getType GetComponentValType) (int, bool) {
+	switch key {
+	case CVValue:
+		return trailBoardData.Position, true
+	case CVNumber:
+		return trailBoardData.Steps, true
+	case CVWins:
+		return trailBoardData.Wins, true
+	case CVWinMulti:
+		return trailBoardData.Multi, true
+	}
+
+	return trailBoardData.BasicComponentData.GetValEx(key, getType)
+}
+
+// TrailBoardConfig - configuration for TrailBoard
+//
+//	每一步按 number、moveVal、moveWeight 的顺序确定走几格，落下的格子会执行 mapBranchs 里对应下标的 awards，
+//	最后落下的格子有 jumpToComponent 时跳转过去，结束时可以用 end 分支
+type TrailBoardConfig struct {
+	BasicComponentConfig `yaml:",inline" json:",inline"`
+	Cells                []*TrailCell           `yaml:"cells" json:"cells"`
+	IsLoop               bool                   `yaml:"isLoop" json:"isLoop"`         // 走到最后一格以后从头开始，否则走到最后一格就结束
+	MoveVal              string                 `yaml:"moveVal" json:"moveVal"`       // 用这个组件值来确定走几格，比如 rollNumber 的 number
+	MoveWeight           string                 `yaml:"moveWeight" json:"moveWeight"` // 按权重随机走几格
+	MoveWeightVW         *sgc7game.ValWeights2  `yaml:"-" json:"-"`
+	BetTypeString        string                 `yaml:"betType" json:"betType"` // bet or totalBet or noPay
+	BetType              BetType                `yaml:"-" json:"-"`
+	IsPlayerState        bool                   `yaml:"isPlayerState" json:"isPlayerState"` // 进度保存在 playerState 里，可以跨越多次 base 的 spin
+	IsIgnoreBet          bool                   `yaml:"isIgnoreBet" json:"isIgnoreBet"`
+	MapBranchs           map[string]*BranchNode `yaml:"mapBranchs" json:"mapBranchs"` // key 是格子的下标，或者 end
+}
+
+// SetLinkComponent
+func (cfg *TrailBoardConfig) SetLinkComponent(link string, componentName string) {
+	if link == "next" {
+		cfg.DefaultNextComponent = componentName
+	} else {
+		if cfg.MapBranchs == nil {
+			cfg.MapBranchs = make(map[string]*BranchNode)
+		}
+
+		if cfg.MapBranchs[link] == nil {
+			cfg.MapBranchs[link] = &BranchNode{
+				JumpToComponent: componentName,
+			}
+		} else {
+			cfg.MapBranchs[link].JumpToComponent = componentName
+		}
+	}
+}
+
+type TrailBoard struct {
+	*BasicComponent `json:"-"`
+	Config          *TrailBoardConfig `json:"config"`
+}
+
+// Init -
+func (trailBoard *TrailBoard) Init(fn string, pool *GamePropertyPool) error {
+	data, err := os.ReadFile(fn)
+	if err != nil {
+		goutils.Error("TrailBoard.Init:ReadFile",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return err
+	}
+
+	cfg := &TrailBoardConfig{}
+
+	err = yaml.Unmarshal(data, cfg)
+	if err != nil {
+		goutils.Error("TrailBoard.Init:Unmarshal",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return err
+	}
+
+	return trailBoard.InitEx(cfg, pool)
+}
+
+// InitEx -
+func (trailBoard *TrailBoard) InitEx(cfg any, pool *GamePropertyPool) error {
+	trailBoard.Config = cfg.(*TrailBoardConfig)
+	trailBoard.Config.ComponentType = TrailBoardTypeName
+
+	if len(trailBoard.Config.Cells) == 0 {
+		goutils.Error("TrailBoard.InitEx:Cells",
+			goutils.Err(ErrInvalidComponentConfig))
+
+		return ErrInvalidComponentConfig
+	}
+
+	for i, v := range trailBoard.Config.Cells {
+		v.Type = parseTrailCellType(v.StrType)
+
+		if v.Value < 0 || ((v.Type == TCTAdvance || v.Type == TCTBack || v.Type == TCTMulti) && v.Value == 0) {
+			goutils.Error("TrailBoard.InitEx:Cell",
+				slog.Int("index", i),
+				slog.String("type", v.StrType),
+				slog.Int("value", v.Value),
+				goutils.Err(ErrInvalidComponentConfig))
+
+			return ErrInvalidComponentConfig
+		}
+	}
+
+	if trailBoard.Config.MoveWeight != "" {
+		vw2, err := pool.LoadIntWeights(trailBoard.Config.MoveWeight, trailBoard.Config.UseFileMapping)
+		if err != nil {
+			goutils.Error("TrailBoard.InitEx:LoadIntWeights",
+				slog.String("Weight", trailBoard.Config.MoveWeight),
+				goutils.Err(err))
+
+			return err
+		}
+
+		trailBoard.Config.MoveWeightVW = vw2
+	}
+
+	trailBoard.Config.BetType = ParseBetType(trailBoard.Config.BetTypeString)
+
+	for _, node := range trailBoard.Config.MapBranchs {
+		for _, award := range node.Awards {
+			award.Init()
+		}
+	}
+
+	trailBoard.onInit(&trailBoard.Config.BasicComponentConfig)
+
+	return nil
+}
+
+// OnProcControllers -
+func (trailBoard *TrailBoard) ProcControllers(gameProp *GameProperty, plugin sgc7plugin.IPlugin, curpr *sgc7game.PlayResult, gp *GameParams, val int, strVal string) {
+	branch, isok := trailBoard.Config.MapBranchs[strVal]
+	if isok {
+		if len(branch.Awards) > 0 {
+			gameProp.procAwards(plugin, branch.Awards, curpr, gp)
+		}
+	}
+}
+
+// getSteps - 走几格
+func (trailBoard *TrailBoard) getSteps(gameProp *GameProperty, plugin sgc7plugin.IPlugin, cd *TrailBoardData) (int, error) {
+	steps, isok := cd.GetConfigIntVal(CCVNumber)
+	if isok {
+		cd.ClearConfigIntVal(CCVNumber)
+
+		return steps, nil
+	}
+
+	if trailBoard.Config.MoveVal != "" {
+		return gameProp.GetComponentVal(trailBoard.Config.MoveVal)
+	}
+
+	if trailBoard.Config.MoveWeightVW != nil {
+		cv, err := trailBoard.Config.MoveWeightVW.RandVal(plugin)
+		if err != nil {
+			goutils.Error("TrailBoard.getSteps:RandVal",
+				goutils.Err(err))
+
+			return 0, err
+		}
+
+		return cv.Int(), nil
+	}
+
+	goutils.Error("TrailBoard.getSteps",
+		goutils.Err(ErrInvalidComponentConfig))
+
+	return 0, ErrInvalidComponentConfig
+}
+
+// move - 返回 true 表示走到了最后一格
+func (trailBoard *TrailBoard) move(cd *TrailBoardData, off int) bool {
+	pos := cd.Position + off
+	if pos < 0 {
+		pos = 0
+	}
+
+	if trailBoard.Config.IsLoop {
+		cd.Position = pos % len(trailBoard.Config.Cells)
+
+		return false
+	}
+
+	if pos >= len(trailBoard.Config.Cells)-1 {
+		cd.Position = len(trailBoard.Config.Cells) - 1
+
+		return true
+	}
+
+	cd.Position = pos
+
+	return false
+}
+
+// collect - 支付累积的奖励
+func (trailBoard *TrailBoard) collect(gameProp *GameProperty, curpr *sgc7game.PlayResult, stake *sgc7game.Stake, cd *TrailBoardData) {
+	if cd.PendingWins <= 0 {
+		return
+	}
+
+	wins := cd.PendingWins * cd.Multi
+	cd.PendingWins = 0
+	cd.Wins += wins
+
+	bet := gameProp.GetBet3(stake, trailBoard.Config.BetType)
+
+	ret := &sgc7game.Result{
+		Symbol:    -1,
+		Type:      sgc7game.RTBonus,
+		LineIndex: -1,
+		CoinWin:   wins,
+		CashWin:   wins * bet,
+	}
+
+	trailBoard.AddResult(curpr, ret, &cd.BasicComponentData)
+}
+
+// procSteps - 走 steps 格，落下的格子如果是 advance 或 back 会继续走，但最多连续走格子数量那么多次
+func (trailBoard *TrailBoard) procSteps(gameProp *GameProperty, curpr *sgc7game.PlayResult, stake *sgc7game.Stake, cd *TrailBoardData, steps int) {
+	off := steps
+
+	for i := 0; i < len(trailBoard.Config.Cells); i++ {
+		isLast := trailBoard.move(cd, off)
+		cd.Path = append(cd.Path, cd.Position)
+
+		off = 0
+		cell := trailBoard.Config.Cells[cd.Position]
+
+		switch cell.Type {
+		case TCTPrize:
+			cd.PendingWins += cell.Value
+		case TCTMulti:
+			cd.Multi *= cell.Value
+		case TCTAdvance:
+			off = cell.Value
+		case TCTBack:
+			off = -cell.Value
+		case TCTCollect:
+			trailBoard.collect(gameProp, curpr, stake, cd)
+		case TCTEnd:
+			isLast = true
+		}
+
+		if isLast {
+			cd.IsEnded = true
+
+			break
+		}
+
+		if off == 0 {
+			break
+		}
+	}
+
+	if cd.IsEnded {
+		trailBoard.collect(gameProp, curpr, stake, cd)
+	}
+}
+
+func (trailBoard *TrailBoard) getPlayerState(ips sgc7game.IPlayerState, stake *sgc7game.Stake) (*TrailBoardPS, error) {
+	ps, isok := ips.(*PlayerState)
+	if !isok {
+		goutils.Error("TrailBoard.getPlayerState:PlayerState",
+			goutils.Err(ErrInvalidPlayerState))
+
+		return nil, ErrInvalidPlayerState
+	}
+
+	betMethod := stake.CashBet / stake.CoinBet
+	bmd := ps.GetBetMethodPub(int(betMethod))
+
+	bet := stake.CoinBet
+	if trailBoard.Config.IsIgnoreBet {
+		bet = -1
+	}
+
+	cps, isok := bmd.GetBetCPS(int(bet), trailBoard.GetName()).(*TrailBoardPS)
+	if !isok {
+		goutils.Error("TrailBoard.getPlayerState:GetBetCPS",
+			goutils.Err(ErrInvalidPlayerState))
+
+		return nil, ErrInvalidPlayerState
+	}
+
+	return cps, nil
+}
+
+// playgame
+func (trailBoard *TrailBoard) OnPlayGame(gameProp *GameProperty, curpr *sgc7game.PlayResult, gp *GameParams, plugin sgc7plugin.IPlugin,
+	cmd string, param string, ips sgc7game.IPlayerState, stake *sgc7game.Stake, prs []*sgc7game.PlayResult, icd IComponentData) (string, error) {
+
+	cd := icd.(*TrailBoardData)
+	cd.onNewStep()
+
+	var cps *TrailBoardPS
+	if trailBoard.Config.IsPlayerState {
+		ps, err := trailBoard.getPlayerState(ips, stake)
+		if err != nil {
+			goutils.Error("TrailBoard.OnPlayGame:getPlayerState",
+				goutils.Err(err))
+
+			return "", err
+		}
+
+		cps = ps
+		cd.loadPS(cps)
+	}
+
+	steps, err := trailBoard.getSteps(gameProp, plugin, cd)
+	if err != nil {
+		goutils.Error("TrailBoard.OnPlayGame:getSteps",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	cd.Steps = steps
+
+	trailBoard.procSteps(gameProp, curpr, stake, cd, steps)
+
+	for _, v := range cd.Path {
+		trailBoard.ProcControllers(gameProp, plugin, curpr, gp, v, strconv.Itoa(v))
+	}
+
+	nextComponent := ""
+
+	if len(cd.Path) > 0 {
+		branch, isok := trailBoard.Config.MapBranchs[strconv.Itoa(cd.Position)]
+		if isok {
+			nextComponent = branch.JumpToComponent
+		}
+	}
+
+	if cd.IsEnded {
+		trailBoard.ProcControllers(gameProp, plugin, curpr, gp, -1, TrailBoardEndBranch)
+
+		if nextComponent == "" {
+			branch, isok := trailBoard.Config.MapBranchs[TrailBoardEndBranch]
+			if isok {
+				nextComponent = branch.JumpToComponent
+			}
+		}
+
+		cd.reset()
+	}
+
+	if cps != nil {
+		cd.savePS(cps)
+	}
+
+	nc := trailBoard.onStepEnd(gameProp, curpr, gp, nextComponent)
+
+	return nc, nil
+}
+
+// OnAsciiGame - outpur to asciigame
+func (trailBoard *TrailBoard) OnAsciiGame(gameProp *GameProperty, pr *sgc7game.PlayResult, lst []*sgc7game.PlayResult, mapSymbolColor *asciigame.SymbolColorMap, icd IComponentData) error {
+	cd := icd.(*TrailBoardData)
+
+	fmt.Printf("trailBoard %v: steps %v, path %v, wins %v, ended %v\n", trailBoard.GetName(), cd.Steps, cd.Path, cd.Wins, cd.IsEnded)
+
+	return nil
+}
+
+// NewComponentData -
+func (trailBoard *TrailBoard) NewComponentData() IComponentData {
+	return &TrailBoardData{
+		Position: -1,
+		Multi:    1,
+	}
+}
+
+// GetAllLinkComponents -
+func (trailBoard *TrailBoard) GetAllLinkComponents() []string {
+	lst := []string{trailBoard.Config.DefaultNextComponent}
+
+	for _, v := range trailBoard.Config.MapBranchs {
+		lst = append(lst, v.JumpToComponent)
+	}
+
+	return lst
+}
+
+// GetNextLinkComponents -
+func (trailBoard *TrailBoard) GetNextLinkComponents() []string {
+	return trailBoard.GetAllLinkComponents()
+}
+
+// OnStats2 - 每个格子落下的次数
+func (trailBoard *TrailBoard) OnStats2(icd IComponentData, s2 *stats2.Cache, gameProp *GameProperty, gp *GameParams, pr *sgc7game.PlayResult, isOnStepEnd bool) {
+	trailBoard.BasicComponent.OnStats2(icd, s2, gameProp, gp, pr, isOnStepEnd)
+
+	cd := icd.(*TrailBoardData)
+
+	for _, v := range cd.Path {
+		s2.ProcStatsIntVal(trailBoard.GetName(), v)
+	}
+}
+
+// NewStats2 -
+func (trailBoard *TrailBoard) NewStats2(parent string) *stats2.Feature {
+	return stats2.NewFeature(parent, []stats2.Option{stats2.OptIntVal})
+}
+
+// InitPlayerState -
+func (trailBoard *TrailBoard) InitPlayerState(pool *GamePropertyPool, gameProp *GameProperty, plugin sgc7plugin.IPlugin,
+	ps *PlayerState, betMethod int, bet int) error {
+
+	if trailBoard.Config.IsPlayerState {
+		bmd := ps.GetBetMethodPub(betMethod)
+		if bet <= 0 {
+			return nil
+		}
+
+		if trailBoard.Config.IsIgnoreBet {
+			bet = -1
+		}
+
+		bps := bmd.GetBetPS(bet)
+
+		cname := trailBoard.GetName()
+
+		_, isok := bps.MapComponentData[cname]
+		if !isok {
+			cps := newTrailBoardPS()
+
+			str, isok := bps.MapString[cname]
+			if isok {
+				cps.SetPublicJson(str)
+			}
+
+			bps.MapComponentData[cname] = cps
+		}
+	}
+
+	return nil
+}
+
+func NewTrailBoard(name string) IComponent {
+	return &TrailBoard{
+		BasicComponent: NewBasicComponent(name, 0),
+	}
+}
+
+// "cells": [{"type": "prize", "value": 5}, {"type": "advance", "value": 2}, {"type": "end"}],
+// "isLoop": false,
+// "moveVal": "bg-roll.number",
+// "moveWeight": "",
+// "betType": "bet",
+// "isPlayerState": true,
+// "isIgnoreBet": false
+type jsonTrailBoard struct {
+	Cells         []*TrailCell `json:"cells"`
+	IsLoop        bool         `json:"isLoop"`
+	MoveVal       string       `json:"moveVal"`
+	MoveWeight    string       `json:"moveWeight"`
+	BetType       string       `json:"betType"`
+	IsPlayerState bool         `json:"isPlayerState"`
+	IsIgnoreBet   bool         `json:"isIgnoreBet"`
+}
+
+func (jcfg *jsonTrailBoard) build() *TrailBoardConfig {
+	cfg := &TrailBoardConfig{
+		IsLoop:        jcfg.IsLoop,
+		MoveVal:       jcfg.MoveVal,
+		MoveWeight:    jcfg.MoveWeight,
+		BetTypeString: jcfg.BetType,
+		IsPlayerState: jcfg.IsPlayerState,
+		IsIgnoreBet:   jcfg.IsIgnoreBet,
+		MapBranchs:    make(map[string]*BranchNode),
+	}
+
+	for _, v := range jcfg.Cells {
+		cfg.Cells = append(cfg.Cells, &TrailCell{
+			StrType: v.StrType,
+			Value:   v.Value,
+		})
+	}
+
+	return cfg
+}
+
+func parseTrailBoard(gamecfg *BetConfig, cell *ast.Node) (string, error) {
+	cfg, label, ctrls, err := getConfigInCell(cell)
+	if err != nil {
+		goutils.Error("parseTrailBoard:getConfigInCell",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	buf, err := cfg.MarshalJSON()
+	if err != nil {
+		goutils.Error("parseTrailBoard:MarshalJSON",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	data := &jsonTrailBoard{}
+
+	err = sonic.Unmarshal(buf, data)
+	if err != nil {
+		goutils.Error("parseTrailBoard:Unmarshal",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	cfgd := data.build()
+
+	if ctrls != nil {
+		mapAwards, err := parseMapControllers(ctrls)
+		if err != nil {
+			goutils.Error("parseTrailBoard:parseMapControllers",
+				goutils.Err(err))
+
+			return "", err
+		}
+
+		for k, arr := range mapAwards {
+			if cfgd.MapBranchs[k] == nil {
+				cfgd.MapBranchs[k] = &BranchNode{
+					Awards: arr,
+				}
+			} else {
+				cfgd.MapBranchs[k].Awards = arr
+			}
+		}
+	}
+
+	gamecfg.mapConfig[label] = cfgd
+	gamecfg.mapBasicConfig[label] = &cfgd.BasicComponentConfig
+
+	ccfg := &ComponentConfig{
+		Name: label,
+		Type: TrailBoardTypeName,
+	}
+
+	gamecfg.Components = append(gamecfg.Components, ccfg)
+
+	return label, nil
+}
@@ -0,0 +1,173 @@
+package lowcode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+	"github.com/zhs007/slotsgamecore7/sgc7pb"
+)
+
+func newTestTrailCells() []*TrailCell {
+	return []*TrailCell{
+		{StrType: "prize", Value: 5},
+		{StrType: "multi", Value: 2},
+		{StrType: "advance", Value: 2},
+		{StrType: "empty"},
+		{StrType: "prize", Value: 10},
+		{StrType: "back", Value: 3},
+		{StrType: "collect"},
+		{StrType: "end"},
+	}
+}
+
+func Test_TrailBoardOnPlayGame(t *testing.T) {
+	gameProp := &GameProperty{Pool: &GamePropertyPool{}}
+	stake := &sgc7game.Stake{CoinBet: 2, CashBet: 20}
+	gp := NewGameParam(stake, nil)
+	plugin := sgc7plugin.NewMockPlugin()
+
+	trailBoard := NewTrailBoard("trail").(*TrailBoard)
+	err := trailBoard.InitEx(&TrailBoardConfig{
+		BasicComponentConfig: BasicComponentConfig{DefaultNextComponent: "next"},
+		Cells:                newTestTrailCells(),
+		BetTypeString:        "bet",
+		MapBranchs: map[string]*BranchNode{
+			"4":                 {JumpToComponent: "bonus"},
+			TrailBoardEndBranch: {JumpToComponent: "fg"},
+		},
+	}, gameProp.Pool)
+	assert.NoError(t, err)
+
+	cd := trailBoard.NewComponentData().(*TrailBoardData)
+	cd.OnNewGame(gameProp, trailBoard)
+
+	play := func(steps int) (*sgc7game.PlayResult, string) {
+		cd.SetConfigIntVal(CCVNumber, steps)
+
+		pr := sgc7game.NewPlayResult("bg", 0, 0, "bg")
+		nc, err := trailBoard.OnPlayGame(gameProp, pr, gp, plugin, DefaultCmd, "", nil, stake, nil, cd)
+		assert.NoError(t, err)
+
+		return pr, nc
+	}
+
+	_, nc := play(1)
+	assert.Equal(t, "next", nc)
+	assert.Equal(t, []int{0}, cd.Path)
+	assert.Equal(t, 5, cd.PendingWins)
+
+	// advance 到 4
+	_, nc = play(2)
+	assert.Equal(t, "bonus", nc)
+	assert.Equal(t, []int{2, 4}, cd.Path)
+	assert.Equal(t, 15, cd.PendingWins)
+
+	// back 到 2，再 advance 到 4
+	play(1)
+	assert.Equal(t, []int{5, 2, 4}, cd.Path)
+	assert.Equal(t, 25, cd.PendingWins)
+
+	pr, nc := play(2)
+	assert.Equal(t, "next", nc)
+	assert.Equal(t, 25, cd.Wins)
+	assert.Equal(t, 0, cd.PendingWins)
+	assert.Len(t, pr.Results, 1)
+	assert.Equal(t, 50, pr.Results[0].CashWin)
+
+	wins, isok := cd.GetValEx(CVWins, GCVTypeNormal)
+	assert.True(t, isok)
+	assert.Equal(t, 25, wins)
+
+	pbcd := cd.BuildPBComponentData().(*sgc7pb.TrailBoardData)
+	assert.Equal(t, int32(6), pbcd.Position)
+	assert.Equal(t, []int32{6}, pbcd.Path)
+
+	// 超过最后一格就结束，回到起点
+	_, nc = play(3)
+	assert.Equal(t, "fg", nc)
+	assert.True(t, cd.IsEnded)
+	assert.Equal(t, []int{7}, cd.Path)
+	assert.Equal(t, -1, cd.Position)
+
+	cd1 := cd.Clone().(*TrailBoardData)
+	cd1.Path[0] = 0
+	assert.Equal(t, 7, cd.Path[0])
+
+	err = NewTrailBoard("trail").InitEx(&TrailBoardConfig{Cells: []*TrailCell{{StrType: "advance"}}}, gameProp.Pool)
+	assert.ErrorIs(t, err, ErrInvalidComponentConfig)
+
+	err = NewTrailBoard("trail").InitEx(&TrailBoardConfig{}, gameProp.Pool)
+	assert.ErrorIs(t, err, ErrInvalidComponentConfig)
+
+	t.Logf("Test_TrailBoardOnPlayGame OK")
+}
+
+func Test_TrailBoardPlayerState(t *testing.T) {
+	vw, err := sgc7game.NewValWeights2([]sgc7game.IVal{
+		sgc7game.NewIntValEx(1), sgc7game.NewIntValEx(2), sgc7game.NewIntValEx(3),
+	}, []int{1, 1, 1})
+	assert.NoError(t, err)
+
+	pool := &GamePropertyPool{mapIntValWeights: map[string]*sgc7game.ValWeights2{"dice": vw}}
+	gameProp := &GameProperty{Pool: pool}
+	stake := &sgc7game.Stake{CoinBet: 1, CashBet: 1}
+	gp := NewGameParam(stake, nil)
+
+	plugin := sgc7plugin.NewMockPlugin()
+	plugin.Cache = []int{1, 2, 1}
+
+	trailBoard := NewTrailBoard("trail").(*TrailBoard)
+	err = trailBoard.InitEx(&TrailBoardConfig{
+		BasicComponentConfig: BasicComponentConfig{DefaultNextComponent: "next"},
+		Cells:                newTestTrailCells(),
+		MoveWeight:           "dice",
+		BetTypeString:        "bet",
+		IsPlayerState:        true,
+	}, pool)
+	assert.NoError(t, err)
+
+	ps := NewPlayerState()
+	err = trailBoard.InitPlayerState(pool, gameProp, plugin, ps, 1, 1)
+	assert.NoError(t, err)
+
+	// 每次都是新的 game，进度保存在 playerState 里
+	play := func() *TrailBoardData {
+		cd := trailBoard.NewComponentData().(*TrailBoardData)
+		cd.OnNewGame(gameProp, trailBoard)
+
+		pr := sgc7game.NewPlayResult("bg", 0, 0, "bg")
+		_, err := trailBoard.OnPlayGame(gameProp, pr, gp, plugin, DefaultCmd, "", ps, stake, nil, cd)
+		assert.NoError(t, err)
+
+		return cd
+	}
+
+	cd := play()
+	assert.Equal(t, 1, cd.Position)
+	assert.Equal(t, 2, cd.Multi)
+
+	cd = play()
+	assert.Equal(t, 4, cd.Position)
+	assert.Equal(t, 10, cd.PendingWins)
+
+	cd = play()
+	assert.Equal(t, 6, cd.Position)
+	assert.Equal(t, 20, cd.Wins)
+
+	cps := ps.GetBetMethodPub(1).GetBetCPS(1, "trail").(*TrailBoardPS)
+	assert.Equal(t, 6, cps.Position)
+	assert.Equal(t, 2, cps.Multi)
+	assert.Equal(t, 0, cps.PendingWins)
+
+	cps1 := &TrailBoardPS{}
+	err = cps1.SetPublicJson(cps.GetPublicJson())
+	assert.NoError(t, err)
+	assert.Equal(t, cps, cps1)
+
+	_, err = trailBoard.OnPlayGame(gameProp, sgc7game.NewPlayResult("bg", 0, 0, "bg"), gp, plugin, DefaultCmd, "", nil, stake, nil, trailBoard.NewComponentData())
+	assert.ErrorIs(t, err, ErrInvalidPlayerState)
+
+	t.Logf("Test_TrailBoardPlayerState OK")
+}
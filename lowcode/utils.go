@@ -121,7 +121,6 @@ func GenDefaultScene(game *Game, bet int) (*sgc7game.GameScene, error) {
 func procSpin(game *Game, ips sgc7game.IPlayerState, plugin sgc7plugin.IPlugin, stake *sgc7game.Stake, cmd string,
 	params string, isNotAutoSelect bool) ([]*sgc7game.PlayResult, error) {
 
-	results := []*sgc7game.PlayResult{}
 	gameData := game.NewGameData(stake)
 	if gameData == nil {
 		goutils.Error("procSpin:NewGameData",
@@ -132,6 +131,15 @@ func procSpin(game *Game, ips sgc7game.IPlayerState, plugin sgc7plugin.IPlugin,
 
 	defer game.DeleteGameData(gameData)
 
+	return procSpinWithGameData(context.Background(), game, ips, plugin, stake, cmd, params, isNotAutoSelect, gameData)
+}
+
+// procSpinWithGameData - results 里的 scene 等数据属于 gameData，gameData 归还到 pool 之前要用完 results
+func procSpinWithGameData(ctx context.Context, game *Game, ips sgc7game.IPlayerState, plugin sgc7plugin.IPlugin, stake *sgc7game.Stake, cmd string,
+	params string, isNotAutoSelect bool, gameData sgc7game.IGameData) ([]*sgc7game.PlayResult, error) {
+
+	results := []*sgc7game.PlayResult{}
+
 	game.OnBet(plugin, cmd, params, ips, stake, results, gameData)
 
 	for {
@@ -139,7 +147,7 @@ func procSpin(game *Game, ips sgc7game.IPlayerState, plugin sgc7plugin.IPlugin,
 			cmd = "SPIN"
 		}
 
-		pr, err := game.Play(plugin, cmd, params, ips, stake, results, gameData)
+		pr, err := game.PlayWithContext(ctx, plugin, cmd, params, ips, stake, results, gameData)
 		if err != nil {
 			goutils.Error("Spin:Play",
 				slog.Int("results", len(results)),
@@ -191,6 +199,39 @@ func procSpin(game *Game, ips sgc7game.IPlayerState, plugin sgc7plugin.IPlugin,
 }
 
 func Spin(game *Game, ips sgc7game.IPlayerState, plugin sgc7plugin.IPlugin, stake *sgc7game.Stake, cmd string, params string, cheat string, isNotAutoSelect bool) ([]*sgc7game.PlayResult, error) {
+	return spin(context.Background(), game, ips, plugin, stake, cmd, params, cheat, isNotAutoSelect, nil)
+}
+
+// SpinWithGameData - like Spin, but gameData is owned by the caller (from game.NewGameData),
+//
+//	the scenes in results are still valid until the caller calls game.DeleteGameData(gameData),
+//	so the results can be serialized safely when the same game is played concurrently
+func SpinWithGameData(game *Game, ips sgc7game.IPlayerState, plugin sgc7plugin.IPlugin, stake *sgc7game.Stake, cmd string, params string, cheat string, isNotAutoSelect bool,
+	gameData sgc7game.IGameData) ([]*sgc7game.PlayResult, error) {
+
+	return SpinWithContext(context.Background(), game, ips, plugin, stake, cmd, params, cheat, isNotAutoSelect, gameData)
+}
+
+// SpinWithContext - like SpinWithGameData, ctx is passed down to the components and IPlugin.Random,
+//
+//	ctx 取消以后，在下一个 component 之前返回 ctx.Err()
+func SpinWithContext(ctx context.Context, game *Game, ips sgc7game.IPlayerState, plugin sgc7plugin.IPlugin, stake *sgc7game.Stake, cmd string, params string, cheat string,
+	isNotAutoSelect bool, gameData sgc7game.IGameData) ([]*sgc7game.PlayResult, error) {
+
+	if gameData == nil {
+		goutils.Error("SpinWithContext",
+			goutils.Err(sgc7game.ErrInvalidStake))
+
+		return nil, sgc7game.ErrInvalidStake
+	}
+
+	return spin(ctx, game, ips, sgc7plugin.WithContext(plugin, ctx), stake, cmd, params, cheat, isNotAutoSelect, gameData)
+}
+
+// spin - gameData 为 nil 时，每次 procSpin 自己从 pool 里取
+func spin(ctx context.Context, game *Game, ips sgc7game.IPlayerState, plugin sgc7plugin.IPlugin, stake *sgc7game.Stake, cmd string, params string, cheat string, isNotAutoSelect bool,
+	gameData sgc7game.IGameData) ([]*sgc7game.PlayResult, error) {
+
 	fo, err := ProcCheat(plugin, cheat)
 	if err != nil {
 		goutils.Error("Spin:ProcCheat",
@@ -209,8 +250,16 @@ func Spin(game *Game, ips sgc7game.IPlayerState, plugin sgc7plugin.IPlugin, stak
 		return nil, err
 	}
 
+	procSpinFunc := func() ([]*sgc7game.PlayResult, error) {
+		if gameData == nil {
+			return procSpin(game, ips, plugin, stake, cmd, params, isNotAutoSelect)
+		}
+
+		return procSpinWithGameData(ctx, game, ips, plugin, stake, cmd, params, isNotAutoSelect, gameData)
+	}
+
 	if fo == nil {
-		return procSpin(game, ips, plugin, stake, cmd, params, isNotAutoSelect)
+		return procSpinFunc()
 	}
 
 	for tryi := 0; tryi < gMaxForceOutcomeTimes; tryi++ {
@@ -218,7 +267,7 @@ func Spin(game *Game, ips sgc7game.IPlayerState, plugin sgc7plugin.IPlugin, stak
 		plugin.ClearCache()
 		plugin.ClearUsedRngs()
 
-		lst, err := procSpin(game, ips, plugin, stake, cmd, params, isNotAutoSelect)
+		lst, err := procSpinFunc()
 		if err != nil {
 			goutils.Error("Spin:procSpin",
 				goutils.Err(err))
@@ -298,7 +347,7 @@ func Shuffle(arr []int, plugin sgc7plugin.IPlugin) ([]int, error) {
 			return dst, nil
 		}
 
-		cr, err := plugin.Random(context.Background(), len(arr))
+		cr, err := plugin.Random(sgc7plugin.GetContext(plugin), len(arr))
 		if err != nil {
 			goutils.Error("Shuffle:Random",
 				goutils.Err(err))
@@ -28,6 +28,7 @@ type WaysTriggerData struct {
 	RespinNum     int
 	Wins          int
 	WinMulti      int
+	Ways          int
 	SymbolCodes   []int
 }
 
@@ -48,6 +49,7 @@ func (waysTriggerData *WaysTriggerData) onNewStep() {
 	waysTriggerData.RespinNum = 0
 	waysTriggerData.Wins = 0
 	waysTriggerData.WinMulti = 1
+	waysTriggerData.Ways = 0
 }
 
 // Clone
@@ -59,6 +61,7 @@ func (waysTriggerData *WaysTriggerData) Clone() IComponentData {
 		RespinNum:          waysTriggerData.RespinNum,
 		Wins:               waysTriggerData.Wins,
 		WinMulti:           waysTriggerData.WinMulti,
+		Ways:               waysTriggerData.Ways,
 		SymbolCodes:        slices.Clone(waysTriggerData.SymbolCodes),
 	}
 
@@ -75,6 +78,7 @@ func (waysTriggerData *WaysTriggerData) BuildPBComponentData() proto.Message {
 		RespinNum:          int32(waysTriggerData.RespinNum),
 		Wins:               int32(waysTriggerData.Wins),
 		WinMulti:           int32(waysTriggerData.WinMulti),
+		Ways:               int32(waysTriggerData.Ways),
 	}
 
 	return pbcd
@@ -93,6 +97,8 @@ func (waysTriggerData *WaysTriggerData) GetValEx(key string, getType GetComponen
 		return waysTriggerData.Wins, true
 	case CVResultNum, CVWinResultNum:
 		return len(waysTriggerData.UsedResults), true
+	case CVWays:
+		return waysTriggerData.Ways, true
 	}
 
 	return 0, false
@@ -368,6 +374,39 @@ func (waysTrigger *WaysTrigger) getGigaData(gameProp *GameProperty) (*GenGigaSym
 	return gigacd, nil
 }
 
+// calcWays - 空的格子（-1）不算，所以动态高度和顶轴也可以直接用
+func (waysTrigger *WaysTrigger) calcWays(gameProp *GameProperty, gs *sgc7game.GameScene, cd *WaysTriggerData) int {
+	var validMask []bool
+
+	rowMask := waysTrigger.getRowMask(&cd.BasicComponentData)
+	if rowMask != "" {
+		maskCompData := gameProp.GetComponentDataWithName(rowMask)
+		if maskCompData != nil {
+			validMask = maskCompData.GetMask()
+		}
+	}
+
+	ways := 1
+	for _, arr := range gs.Arr {
+		num := 0
+		for y, s := range arr {
+			if s < 0 {
+				continue
+			}
+
+			if validMask != nil && (y >= len(validMask) || !validMask[y]) {
+				continue
+			}
+
+			num++
+		}
+
+		ways *= num
+	}
+
+	return ways
+}
+
 // CanTrigger -
 func (waysTrigger *WaysTrigger) canTrigger(gameProp *GameProperty, gs *sgc7game.GameScene, os *sgc7game.GameScene, _ *sgc7game.PlayResult, stake *sgc7game.Stake, cd *WaysTriggerData) (bool, []*sgc7game.Result) {
 	isTrigger := false
@@ -921,6 +960,8 @@ func (waysTrigger *WaysTrigger) OnPlayGame(gameProp *GameProperty, curpr *sgc7ga
 		os = tos
 	}
 
+	std.Ways = waysTrigger.calcWays(gameProp, gs, std)
+
 	isTrigger, lst := waysTrigger.canTrigger(gameProp, gs, os, curpr, stake, std)
 
 	if isTrigger {
@@ -0,0 +1,546 @@
+package lowcode
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/bytedance/sonic/ast"
+	"github.com/zhs007/goutils"
+	"github.com/zhs007/slotsgamecore7/asciigame"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+	"github.com/zhs007/slotsgamecore7/sgc7pb"
+	"github.com/zhs007/slotsgamecore7/stats2"
+	"google.golang.org/protobuf/proto"
+	"gopkg.in/yaml.v2"
+)
+
+const WheelBonusTypeName = "wheelBonus"
+
+type WheelSegmentType int
+
+const (
+	WSTCredit   WheelSegmentType = 0 // 奖励，value 是 bet 的倍数
+	WSTMulti    WheelSegmentType = 1 // 倍数，给 controllers 用
+	WSTJackpot  WheelSegmentType = 2 // 奖池，value 是 jackpotType，也就是奖池档位 + 1
+	WSTFreeSpin WheelSegmentType = 3 // 免费游戏，value 是次数
+	WSTUpgrade  WheelSegmentType = 4 // 升级到下一个轮盘
+	WSTRespin   WheelSegmentType = 5 // respin，value 是次数
+)
+
+var wheelSegmentTypeNames = []string{"credit", "multi", "jackpot", "freespin", "upgrade", "respin"}
+
+func parseWheelSegmentType(str string) (WheelSegmentType, error) {
+	i := slices.Index(wheelSegmentTypeNames, strings.ToLower(str))
+	if i < 0 {
+		return WSTCredit, ErrInvalidComponentConfig
+	}
+
+	return WheelSegmentType(i), nil
+}
+
+func (t WheelSegmentType) String() string {
+	return wheelSegmentTypeNames[t]
+}
+
+// WheelSegment - 轮盘上的一格
+type WheelSegment struct {
+	StrType string           `yaml:"type" json:"type"` // credit、multi、jackpot、freespin、upgrade、respin
+	Type    WheelSegmentType `yaml:"-" json:"-"`
+	Value   int              `yaml:"value" json:"value"`
+	Weight  int              `yaml:"weight" json:"weight"` // 没有配置 wheel 的 weight 时用这个
+}
+
+// Wheel - 一个轮盘
+type Wheel struct {
+	Segments []*WheelSegment       `yaml:"segments" json:"segments"`
+	Weight   string                `yaml:"weight" json:"weight"` // 值是 segments 的下标
+	WeightVW *sgc7game.ValWeights2 `yaml:"-" json:"-"`
+}
+
+type WheelBonusData struct {
+	BasicComponentData
+	Wheels      []int // 每次转的是第几个轮盘
+	Segments    []int // 每次落在第几格
+	SegmentType string
+	Value       int
+	Wins        int // 奖励，bet 的倍数
+	Multi       int
+}
+
+// OnNewGame -
+func (wheelBonusData *WheelBonusData) OnNewGame(gameProp *GameProperty, component IComponent) {
+	wheelBonusData.BasicComponentData.OnNewGame(gameProp, component)
+}
+
+// onNewStep -
+func (wheelBonusData *WheelBonusData) onNewStep() {
+	wheelBonusData.UsedResults = nil
+	wheelBonusData.Wheels = nil
+	wheelBonusData.Segments = nil
+	wheelBonusData.SegmentType = ""
+	wheelBonusData.Value = 0
+	wheelBonusData.Wins = 0
+	wheelBonusData.Multi = 1
+}
+
+// Clone
+func (wheelBonusData *WheelBonusData) Clone() IComponentData {
+	target := &WheelBonusData{
+		BasicComponentData: wheelBonusData.CloneBasicComponentData(),
+		Wheels:             slices.Clone(wheelBonusData.Wheels),
+		Segments:           slices.Clone(wheelBonusData.Segments),
+		SegmentType:        wheelBonusData.SegmentType,
+		Value:              wheelBonusData.Value,
+		Wins:               wheelBonusData.Wins,
+		Multi:              wheelBonusData.Multi,
+	}
+
+	return target
+}
+
+// BuildPBComponentData
+func (wheelBonusData *WheelBonusData) BuildPBComponentData() proto.Message {
+	pbcd := &sgc7pb.WheelBonusData{
+		BasicComponentData: wheelBonusData.BuildPBBasicComponentData(),
+		SegmentType:        wheelBonusData.SegmentType,
+		Value:              int32(wheelBonusData.Value),
+		Wins:               int32(wheelBonusData.Wins),
+		Multi:              int32(wheelBonusData.Multi),
+	}
+
+	for _, v := range wheelBonusData.Wheels {
+		pbcd.Wheels = append(pbcd.Wheels, int32(v))
+	}
+
+	for _, v := range wheelBonusData.Segments {
+		pbcd.Segments = append(pbcd.Segments, int32(v))
+	}
+
+	return pbcd
+}
+
+// GetValEx -
+func (wheelBonusData *WheelBonusData) GetValEx(key string, getType GetComponentValType) (int, bool) {
+	switch key {
+	case CVValue:
+		return wheelBonusData.Value, true
+	case CVWins:
+		return wheelBonusData.Wins, true
+	case CVWinMulti:
+		return wheelBonusData.Multi, true
+	case CVRespinNum:
+		if wheelBonusData.SegmentType == WSTFreeSpin.String() || wheelBonusData.SegmentType == WSTRespin.String() {
+			return wheelBonusData.Value, true
+		}
+
+		return 0, true
+	}
+
+	return wheelBonusData.BasicComponentData.GetValEx(key, getType)
+}
+
+// WheelBonusConfig - configuration for WheelBonus
+//
+//	从第一个轮盘开始转，落在 upgrade 上就转下一个轮盘，直到落在别的格子上，
+//	最后落下的格子类型对应 mapBranchs 里的分支，会执行 awards 并跳转
+type WheelBonusConfig struct {
+	BasicComponentConfig `yaml:",inline" json:",inline"`
+	Wheels               []*Wheel               `yaml:"wheels" json:"wheels"`
+	BetTypeString        string                 `yaml:"betType" json:"betType"` // bet or totalBet or noPay
+	BetType              BetType                `yaml:"-" json:"-"`
+	MapBranchs           map[string]*BranchNode `yaml:"mapBranchs" json:"mapBranchs"` // key 是格子的类型
+}
+
+// SetLinkComponent
+func (cfg *WheelBonusConfig) SetLinkComponent(link string, componentName string) {
+	if link == "next" {
+		cfg.DefaultNextComponent = componentName
+	} else {
+		if cfg.MapBranchs == nil {
+			cfg.MapBranchs = make(map[string]*BranchNode)
+		}
+
+		if cfg.MapBranchs[link] == nil {
+			cfg.MapBranchs[link] = &BranchNode{
+				JumpToComponent: componentName,
+			}
+		} else {
+			cfg.MapBranchs[link].JumpToComponent = componentName
+		}
+	}
+}
+
+type WheelBonus struct {
+	*BasicComponent `json:"-"`
+	Config          *WheelBonusConfig `json:"config"`
+}
+
+// Init -
+func (wheelBonus *WheelBonus) Init(fn string, pool *GamePropertyPool) error {
+	data, err := os.ReadFile(fn)
+	if err != nil {
+		goutils.Error("WheelBonus.Init:ReadFile",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return err
+	}
+
+	cfg := &WheelBonusConfig{}
+
+	err = yaml.Unmarshal(data, cfg)
+	if err != nil {
+		goutils.Error("WheelBonus.Init:Unmarshal",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return err
+	}
+
+	return wheelBonus.InitEx(cfg, pool)
+}
+
+// initWheel -
+func (wheelBonus *WheelBonus) initWheel(wheel *Wheel, isLast bool, pool *GamePropertyPool) error {
+	if len(wheel.Segments) == 0 {
+		return ErrInvalidComponentConfig
+	}
+
+	vals := make([]sgc7game.IVal, 0, len(wheel.Segments))
+	weights := make([]int, 0, len(wheel.Segments))
+
+	for i, seg := range wheel.Segments {
+		st, err := parseWheelSegmentType(seg.StrType)
+		if err != nil {
+			return err
+		}
+
+		seg.Type = st
+
+		// 最后一个轮盘不能升级，奖池的档位从 1 开始
+		if (st == WSTUpgrade && isLast) || (st == WSTJackpot && seg.Value <= 0) || seg.Value < 0 || seg.Weight < 0 {
+			return ErrInvalidComponentConfig
+		}
+
+		vals = append(vals, sgc7game.NewIntValEx(i))
+		weights = append(weights, seg.Weight)
+	}
+
+	if wheel.Weight != "" {
+		vw2, err := pool.LoadIntWeights(wheel.Weight, wheelBonus.Config.UseFileMapping)
+		if err != nil {
+			return err
+		}
+
+		for _, v := range vw2.Vals {
+			if v.Int() < 0 || v.Int() >= len(wheel.Segments) {
+				return ErrInvalidComponentConfig
+			}
+		}
+
+		wheel.WeightVW = vw2
+
+		return nil
+	}
+
+	vw2, err := sgc7game.NewValWeights2(vals, weights)
+	if err != nil {
+		return err
+	}
+
+	if vw2.MaxWeight <= 0 {
+		return ErrInvalidComponentConfig
+	}
+
+	wheel.WeightVW = vw2
+
+	return nil
+}
+
+// InitEx -
+func (wheelBonus *WheelBonus) InitEx(cfg any, pool *GamePropertyPool) error {
+	wheelBonus.Config = cfg.(*WheelBonusConfig)
+	wheelBonus.Config.ComponentType = WheelBonusTypeName
+
+	if len(wheelBonus.Config.Wheels) == 0 {
+		goutils.Error("WheelBonus.InitEx:Wheels",
+			goutils.Err(ErrInvalidComponentConfig))
+
+		return ErrInvalidComponentConfig
+	}
+
+	for i, wheel := range wheelBonus.Config.Wheels {
+		err := wheelBonus.initWheel(wheel, i == len(wheelBonus.Config.Wheels)-1, pool)
+		if err != nil {
+			goutils.Error("WheelBonus.InitEx:initWheel",
+				slog.Int("wheel", i),
+				goutils.Err(err))
+
+			return err
+		}
+	}
+
+	wheelBonus.Config.BetType = ParseBetType(wheelBonus.Config.BetTypeString)
+
+	for _, node := range wheelBonus.Config.MapBranchs {
+		for _, award := range node.Awards {
+			award.Init()
+		}
+	}
+
+	wheelBonus.onInit(&wheelBonus.Config.BasicComponentConfig)
+
+	return nil
+}
+
+// OnProcControllers -
+func (wheelBonus *WheelBonus) ProcControllers(gameProp *GameProperty, plugin sgc7plugin.IPlugin, curpr *sgc7game.PlayResult, gp *GameParams, val int, strVal string) {
+	branch, isok := wheelBonus.Config.MapBranchs[strVal]
+	if isok {
+		if len(branch.Awards) > 0 {
+			gameProp.procAwards(plugin, branch.Awards, curpr, gp)
+		}
+	}
+}
+
+// spin - 一直转到不是 upgrade 为止
+func (wheelBonus *WheelBonus) spin(plugin sgc7plugin.IPlugin, cd *WheelBonusData) (*WheelSegment, error) {
+	for wi, wheel := range wheelBonus.Config.Wheels {
+		cv, err := wheel.WeightVW.RandVal(plugin)
+		if err != nil {
+			goutils.Error("WheelBonus.spin:RandVal",
+				slog.Int("wheel", wi),
+				goutils.Err(err))
+
+			return nil, err
+		}
+
+		cd.Wheels = append(cd.Wheels, wi)
+		cd.Segments = append(cd.Segments, cv.Int())
+
+		seg := wheel.Segments[cv.Int()]
+		if seg.Type != WSTUpgrade {
+			return seg, nil
+		}
+	}
+
+	// InitEx 里已经检查过最后一个轮盘不能升级
+	goutils.Error("WheelBonus.spin",
+		goutils.Err(ErrInvalidComponentConfig))
+
+	return nil, ErrInvalidComponentConfig
+}
+
+// playgame
+func (wheelBonus *WheelBonus) OnPlayGame(gameProp *GameProperty, curpr *sgc7game.PlayResult, gp *GameParams, plugin sgc7plugin.IPlugin,
+	cmd string, param string, ps sgc7game.IPlayerState, stake *sgc7game.Stake, prs []*sgc7game.PlayResult, icd IComponentData) (string, error) {
+
+	cd := icd.(*WheelBonusData)
+	cd.onNewStep()
+
+	seg, err := wheelBonus.spin(plugin, cd)
+	if err != nil {
+		goutils.Error("WheelBonus.OnPlayGame:spin",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	cd.SegmentType = seg.Type.String()
+	cd.Value = seg.Value
+
+	switch seg.Type {
+	case WSTCredit:
+		cd.Wins = seg.Value
+
+		bet := gameProp.GetBet3(stake, wheelBonus.Config.BetType)
+
+		ret := &sgc7game.Result{
+			Symbol:    -1,
+			Type:      sgc7game.RTBonus,
+			LineIndex: -1,
+			CoinWin:   cd.Wins,
+			CashWin:   cd.Wins * bet,
+		}
+
+		wheelBonus.AddResult(curpr, ret, &cd.BasicComponentData)
+	case WSTMulti:
+		cd.Multi = seg.Value
+	case WSTJackpot:
+		// 奖池由服务器支付，这里只标记是哪一档
+		curpr.JackpotType = seg.Value
+	}
+
+	wheelBonus.ProcControllers(gameProp, plugin, curpr, gp, cd.Value, cd.SegmentType)
+
+	nextComponent := ""
+
+	branch, isok := wheelBonus.Config.MapBranchs[cd.SegmentType]
+	if isok {
+		nextComponent = branch.JumpToComponent
+	}
+
+	nc := wheelBonus.onStepEnd(gameProp, curpr, gp, nextComponent)
+
+	return nc, nil
+}
+
+// OnAsciiGame - outpur to asciigame
+func (wheelBonus *WheelBonus) OnAsciiGame(gameProp *GameProperty, pr *sgc7game.PlayResult, lst []*sgc7game.PlayResult, mapSymbolColor *asciigame.SymbolColorMap, icd IComponentData) error {
+	cd := icd.(*WheelBonusData)
+
+	fmt.Printf("wheelBonus %v: wheels %v, segments %v, %v %v\n", wheelBonus.GetName(), cd.Wheels, cd.Segments, cd.SegmentType, cd.Value)
+
+	return nil
+}
+
+// NewComponentData -
+func (wheelBonus *WheelBonus) NewComponentData() IComponentData {
+	return &WheelBonusData{
+		Multi: 1,
+	}
+}
+
+// GetAllLinkComponents -
+func (wheelBonus *WheelBonus) GetAllLinkComponents() []string {
+	lst := []string{wheelBonus.Config.DefaultNextComponent}
+
+	for _, v := range wheelBonus.Config.MapBranchs {
+		lst = append(lst, v.JumpToComponent)
+	}
+
+	return lst
+}
+
+// GetNextLinkComponents -
+func (wheelBonus *WheelBonus) GetNextLinkComponents() []string {
+	return wheelBonus.GetAllLinkComponents()
+}
+
+// OnStats2 - 每个轮盘每一格落下的次数，key 是 轮盘下标:格子下标
+func (wheelBonus *WheelBonus) OnStats2(icd IComponentData, s2 *stats2.Cache, gameProp *GameProperty, gp *GameParams, pr *sgc7game.PlayResult, isOnStepEnd bool) {
+	wheelBonus.BasicComponent.OnStats2(icd, s2, gameProp, gp, pr, isOnStepEnd)
+
+	cd := icd.(*WheelBonusData)
+
+	for i, wi := range cd.Wheels {
+		s2.ProcStatsStrVal(wheelBonus.GetName(), fmt.Sprintf("%v:%v", wi, cd.Segments[i]))
+	}
+
+	s2.ProcStatsWins(wheelBonus.GetName(), int64(cd.Wins))
+}
+
+// NewStats2 -
+func (wheelBonus *WheelBonus) NewStats2(parent string) *stats2.Feature {
+	return stats2.NewFeature(parent, []stats2.Option{stats2.OptStrVal, stats2.OptWins})
+}
+
+func NewWheelBonus(name string) IComponent {
+	return &WheelBonus{
+		BasicComponent: NewBasicComponent(name, 0),
+	}
+}
+
+// "wheels": [
+//
+//	{"segments": [{"type": "credit", "value": 10, "weight": 5}, {"type": "upgrade", "weight": 1}]},
+//	{"weight": "innerwheel", "segments": [{"type": "jackpot", "value": 1}, {"type": "freespin", "value": 8}]}
+//
+// ],
+// "betType": "bet"
+type jsonWheelBonus struct {
+	Wheels  []*Wheel `json:"wheels"`
+	BetType string   `json:"betType"`
+}
+
+func (jcfg *jsonWheelBonus) build() *WheelBonusConfig {
+	cfg := &WheelBonusConfig{
+		BetTypeString: jcfg.BetType,
+		MapBranchs:    make(map[string]*BranchNode),
+	}
+
+	for _, wheel := range jcfg.Wheels {
+		cw := &Wheel{
+			Weight: wheel.Weight,
+		}
+
+		for _, seg := range wheel.Segments {
+			cw.Segments = append(cw.Segments, &WheelSegment{
+				StrType: seg.StrType,
+				Value:   seg.Value,
+				Weight:  seg.Weight,
+			})
+		}
+
+		cfg.Wheels = append(cfg.Wheels, cw)
+	}
+
+	return cfg
+}
+
+func parseWheelBonus(gamecfg *BetConfig, cell *ast.Node) (string, error) {
+	cfg, label, ctrls, err := getConfigInCell(cell)
+	if err != nil {
+		goutils.Error("parseWheelBonus:getConfigInCell",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	buf, err := cfg.MarshalJSON()
+	if err != nil {
+		goutils.Error("parseWheelBonus:MarshalJSON",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	data := &jsonWheelBonus{}
+
+	err = sonic.Unmarshal(buf, data)
+	if err != nil {
+		goutils.Error("parseWheelBonus:Unmarshal",
+			goutils.Err(err))
+
+		return "", err
+	}
+
+	cfgd := data.build()
+
+	if ctrls != nil {
+		mapAwards, err := parseMapControllers(ctrls)
+		if err != nil {
+			goutils.Error("parseWheelBonus:parseMapControllers",
+				goutils.Err(err))
+
+			return "", err
+		}
+
+		for k, arr := range mapAwards {
+			if cfgd.MapBranchs[k] == nil {
+				cfgd.MapBranchs[k] = &BranchNode{
+					Awards: arr,
+				}
+			} else {
+				cfgd.MapBranchs[k].Awards = arr
+			}
+		}
+	}
+
+	gamecfg.mapConfig[label] = cfgd
+	gamecfg.mapBasicConfig[label] = &cfgd.BasicComponentConfig
+
+	ccfg := &ComponentConfig{
+		Name: label,
+		Type: WheelBonusTypeName,
+	}
+
+	gamecfg.Components = append(gamecfg.Components, ccfg)
+
+	return label, nil
+}
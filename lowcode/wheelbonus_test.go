@@ -0,0 +1,129 @@
+package lowcode
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+	"github.com/zhs007/slotsgamecore7/sgc7pb"
+	"github.com/zhs007/slotsgamecore7/stats2"
+)
+
+func Test_WheelBonusOnPlayGame(t *testing.T) {
+	vw, err := sgc7game.NewValWeights2([]sgc7game.IVal{
+		sgc7game.NewIntValEx(0), sgc7game.NewIntValEx(1), sgc7game.NewIntValEx(2),
+	}, []int{1, 1, 1})
+	assert.NoError(t, err)
+
+	pool := &GamePropertyPool{mapIntValWeights: map[string]*sgc7game.ValWeights2{"inner": vw}}
+	gameProp := &GameProperty{Pool: pool}
+	stake := &sgc7game.Stake{CoinBet: 2, CashBet: 20}
+	gp := NewGameParam(stake, nil)
+	plugin := sgc7plugin.NewMockPlugin()
+
+	wheelBonus := NewWheelBonus("wheel").(*WheelBonus)
+	err = wheelBonus.InitEx(&WheelBonusConfig{
+		BasicComponentConfig: BasicComponentConfig{DefaultNextComponent: "next"},
+		Wheels: []*Wheel{
+			{Segments: []*WheelSegment{
+				{StrType: "credit", Value: 10, Weight: 1},
+				{StrType: "multi", Value: 3, Weight: 1},
+				{StrType: "upgrade", Weight: 1},
+			}},
+			{Segments: []*WheelSegment{
+				{StrType: "jackpot", Value: 2, Weight: 1},
+				{StrType: "freespin", Value: 8, Weight: 1},
+				{StrType: "upgrade", Weight: 1},
+			}},
+			{Weight: "inner", Segments: []*WheelSegment{
+				{StrType: "respin", Value: 3},
+				{StrType: "credit", Value: 100},
+				{StrType: "jackpot", Value: 1},
+			}},
+		},
+		BetTypeString: "bet",
+		MapBranchs: map[string]*BranchNode{
+			"freespin": {JumpToComponent: "fg"},
+		},
+	}, pool)
+	assert.NoError(t, err)
+
+	cd := wheelBonus.NewComponentData().(*WheelBonusData)
+	cd.OnNewGame(gameProp, wheelBonus)
+
+	play := func(cache []int) (*sgc7game.PlayResult, string) {
+		plugin.Cache = cache
+
+		pr := sgc7game.NewPlayResult("bg", 0, 0, "bg")
+		nc, err := wheelBonus.OnPlayGame(gameProp, pr, gp, plugin, DefaultCmd, "", nil, stake, nil, cd)
+		assert.NoError(t, err)
+
+		return pr, nc
+	}
+
+	pr, nc := play([]int{0})
+	assert.Equal(t, "next", nc)
+	assert.Equal(t, []int{0}, cd.Wheels)
+	assert.Equal(t, []int{0}, cd.Segments)
+	assert.Equal(t, 10, cd.Wins)
+	assert.Len(t, pr.Results, 1)
+	assert.Equal(t, 10, pr.Results[0].CoinWin)
+	assert.Equal(t, 20, pr.Results[0].CashWin)
+
+	// 升级到第二个轮盘，落在免费游戏上
+	pr, nc = play([]int{2, 1})
+	assert.Equal(t, "fg", nc)
+	assert.Equal(t, []int{0, 1}, cd.Wheels)
+	assert.Equal(t, []int{2, 1}, cd.Segments)
+	assert.Equal(t, "freespin", cd.SegmentType)
+	assert.Equal(t, 0, cd.Wins)
+	assert.Empty(t, pr.Results)
+
+	respinNum, isok := cd.GetValEx(CVRespinNum, GCVTypeNormal)
+	assert.True(t, isok)
+	assert.Equal(t, 8, respinNum)
+
+	// 一直升级到最后一个轮盘
+	pr, nc = play([]int{2, 2, 2})
+	assert.Equal(t, "next", nc)
+	assert.Equal(t, []int{0, 1, 2}, cd.Wheels)
+	assert.Equal(t, []int{2, 2, 2}, cd.Segments)
+	assert.Equal(t, 1, pr.JackpotType)
+
+	pbcd := cd.BuildPBComponentData().(*sgc7pb.WheelBonusData)
+	assert.Equal(t, []int32{0, 1, 2}, pbcd.Wheels)
+	assert.Equal(t, []int32{2, 2, 2}, pbcd.Segments)
+	assert.Equal(t, "jackpot", pbcd.SegmentType)
+
+	cd1 := cd.Clone().(*WheelBonusData)
+	cd1.Segments[0] = 0
+	assert.Equal(t, 2, cd.Segments[0])
+
+	play([]int{1})
+	multi, isok := cd.GetValEx(CVWinMulti, GCVTypeNormal)
+	assert.True(t, isok)
+	assert.Equal(t, 3, multi)
+
+	// 统计每个轮盘每一格
+	s2 := stats2.NewCache(1)
+	s2.AddFeature("wheel", wheelBonus.NewStats2(""), false)
+	wheelBonus.OnStats2(cd, s2, gameProp, gp, nil, false)
+	assert.Equal(t, int64(1), s2.MapStats["wheel"].StrVal.MapUsedTimes["0:1"])
+
+	// 最后一个轮盘不能升级
+	err = NewWheelBonus("wheel").InitEx(&WheelBonusConfig{
+		Wheels: []*Wheel{{Segments: []*WheelSegment{{StrType: "upgrade", Weight: 1}}}},
+	}, pool)
+	assert.ErrorIs(t, err, ErrInvalidComponentConfig)
+
+	err = NewWheelBonus("wheel").InitEx(&WheelBonusConfig{
+		Wheels: []*Wheel{{Segments: []*WheelSegment{{StrType: "gold", Weight: 1}}}},
+	}, pool)
+	assert.ErrorIs(t, err, ErrInvalidComponentConfig)
+
+	err = NewWheelBonus("wheel").InitEx(&WheelBonusConfig{}, pool)
+	assert.ErrorIs(t, err, ErrInvalidComponentConfig)
+
+	t.Logf("Test_WheelBonusOnPlayGame OK")
+}
@@ -122,6 +122,66 @@ func list2listmapintfloat(val ref.Val) []map[int]float64 {
 	return nil
 }
 
+func list2ValWeightsFitTargets(val ref.Val) []*ValWeightsFitTarget {
+	lst0, isok := val.Value().([]ref.Val)
+	if isok {
+		lst := []*ValWeightsFitTarget{}
+
+		for _, n := range lst0 {
+			cm, isok := n.Value().(map[ref.Val]ref.Val)
+			if !isok {
+				continue
+			}
+
+			target := &ValWeightsFitTarget{}
+
+			for k, v := range cm {
+				key, isok := k.Value().(string)
+				if !isok {
+					continue
+				}
+
+				if key == "type" {
+					if str, isok := v.Value().(string); isok && str == "hitrate" {
+						target.Type = VWFTHitRate
+					}
+
+					continue
+				}
+
+				var fv float64
+				switch cv := v.Value().(type) {
+				case float64:
+					fv = cv
+				case int64:
+					fv = float64(cv)
+				default:
+					continue
+				}
+
+				switch key {
+				case "target":
+					target.Target = fv
+				case "tolerance":
+					target.Tolerance = fv
+				case "importance":
+					target.Importance = fv
+				case "scale":
+					target.Scale = fv
+				case "minVal":
+					target.MinVal = fv
+				}
+			}
+
+			lst = append(lst, target)
+		}
+
+		return lst
+	}
+
+	return nil
+}
+
 func array2SymbolTypeSlice(val ref.Val) []SymbolType {
 	lst0, isok := val.Value().([]ref.Val)
 	if isok {
@@ -585,6 +645,65 @@ func newBasicScriptFuncs(mgrGenMath *GenMathMgr) []cel.EnvOption {
 				),
 			),
 		),
+		cel.Function("fitValWeights",
+			cel.Overload("fitValWeights_string_string_int_int_double_list",
+				[]*cel.Type{cel.StringType, cel.StringType, cel.IntType, cel.IntType, cel.DoubleType, cel.ListType(cel.MapType(cel.StringType, cel.DynType))},
+				cel.DoubleType,
+				cel.FunctionBinding(func(params ...ref.Val) ref.Val {
+					if len(params) != 6 {
+						goutils.Error("fitValWeights",
+							goutils.Err(ErrInvalidFunctionParams))
+
+						return types.Double(0)
+					}
+
+					srcfn := params[0].Value().(string)
+					targetfn := params[1].Value().(string)
+
+					vw, err := sgc7game.LoadValWeights2FromExcel(srcfn, "val", "weight", sgc7game.NewStrVal)
+					if err != nil {
+						goutils.Error("fitValWeights:LoadValWeights2FromExcel",
+							goutils.Err(err))
+
+						return types.Double(0)
+					}
+
+					options := &ValWeightsFitOptions{
+						TotalWeight: int(params[2].Value().(int64)),
+						MinWeight:   int(params[3].Value().(int64)),
+						SmoothRatio: params[4].Value().(float64),
+						Targets:     list2ValWeightsFitTargets(params[5]),
+					}
+
+					ret, err := FitValWeights2(vw, options)
+					if err != nil {
+						goutils.Error("fitValWeights:FitValWeights2",
+							goutils.Err(err))
+
+						return types.Double(0)
+					}
+
+					err = ret.ValWeights.SaveExcel(targetfn, "val", "weight")
+					if err != nil {
+						goutils.Error("fitValWeights:SaveExcel",
+							goutils.Err(err))
+
+						return types.Double(0)
+					}
+
+					if !ret.IsConverged {
+						goutils.Error("fitValWeights",
+							slog.Any("values", ret.Values),
+							goutils.Err(ErrCannotBeConverged))
+
+						return types.Double(0)
+					}
+
+					return types.Double(1)
+				},
+				),
+			),
+		),
 		cel.Function("genReelsMainSymbolsDistance",
 			cel.Overload("genReelsMainSymbolsDistance_string_string_string_list_int",
 				[]*cel.Type{cel.StringType, cel.StringType, cel.StringType, cel.ListType(cel.StringType), cel.IntType},
@@ -34,5 +34,12 @@ func Test_ScriptCore(t *testing.T) {
 	_, err = script.Eval(mgrGenMath)
 	assert.NoError(t, err)
 
+	err = script.Compile(`fitValWeights("../unittestdata/mysteryweights.xlsx", "../unittestdata/fitvalweights2.xlsx", 10000, 1, 2.0, [{"type": "avg", "target": 3.5, "tolerance": 0.01}, {"type": "hitrate", "minVal": 7, "target": 0.1, "tolerance": 0.005}])`)
+	assert.NoError(t, err)
+
+	out2, err := script.Eval(mgrGenMath)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, out2.Value().(float64))
+
 	t.Logf("Test_ScriptCore OK")
 }
@@ -0,0 +1,639 @@
+package mathtoolset
+
+import (
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"slices"
+	"sync"
+
+	"github.com/xuri/excelize/v2"
+	"github.com/zhs007/goutils"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+)
+
+type ClusterPayType int
+
+const (
+	// CPTCluster - cluster pays, it's the same as sgc7game.CalcClusterResult
+	CPTCluster ClusterPayType = 0
+	// CPTAdjacentPay - adjacent pays, it's the same as sgc7game.CalcAdjacentPay
+	CPTAdjacentPay ClusterPayType = 1
+)
+
+// ClusterGrid - 棋盘的符号来源，Reels 和 PosWeights 二选一
+type ClusterGrid struct {
+	Width      int
+	Height     int
+	Reels      *sgc7game.ReelsData       // 按轮子，每个轮子均匀随机停轮
+	PosWeights [][]*sgc7game.ValWeights2 // 按位置的符号权重，[x][y]，val 是符号编码
+}
+
+func (grid *ClusterGrid) isValid() bool {
+	if grid.Width <= 0 || grid.Height <= 0 {
+		return false
+	}
+
+	if grid.Reels != nil {
+		if len(grid.Reels.Reels) != grid.Width {
+			return false
+		}
+
+		for _, reel := range grid.Reels.Reels {
+			if len(reel) <= 0 {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	if len(grid.PosWeights) != grid.Width {
+		return false
+	}
+
+	for _, arr := range grid.PosWeights {
+		if len(arr) != grid.Height {
+			return false
+		}
+
+		for _, vw := range arr {
+			if vw == nil || len(vw.Vals) <= 0 || vw.MaxWeight <= 0 {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// getDimensions - 每个独立随机量的取值个数，reels 是每个轮子，posweights 是每个位置
+func (grid *ClusterGrid) getDimensions() []int {
+	dims := []int{}
+
+	if grid.Reels != nil {
+		for _, reel := range grid.Reels.Reels {
+			dims = append(dims, len(reel))
+		}
+
+		return dims
+	}
+
+	for _, arr := range grid.PosWeights {
+		for _, vw := range arr {
+			dims = append(dims, len(vw.Vals))
+		}
+	}
+
+	return dims
+}
+
+// fill - 用 indexes 填充棋盘，返回这个棋盘的概率权重
+func (grid *ClusterGrid) fill(arr [][]int, indexes []int) float64 {
+	if grid.Reels != nil {
+		for x, reel := range grid.Reels.Reels {
+			for y := 0; y < grid.Height; y++ {
+				arr[x][y] = reel[(indexes[x]+y)%len(reel)]
+			}
+		}
+
+		return 1
+	}
+
+	prob := float64(1)
+
+	for x, lst := range grid.PosWeights {
+		for y, vw := range lst {
+			i := indexes[x*grid.Height+y]
+
+			arr[x][y] = vw.Vals[i].Int()
+			prob *= float64(vw.Weights[i]) / float64(vw.MaxWeight)
+		}
+	}
+
+	return prob
+}
+
+// random - 随机一个棋盘
+func (grid *ClusterGrid) random(arr [][]int, r *rand.Rand) {
+	if grid.Reels != nil {
+		for x, reel := range grid.Reels.Reels {
+			si := r.Intn(len(reel))
+
+			for y := 0; y < grid.Height; y++ {
+				arr[x][y] = reel[(si+y)%len(reel)]
+			}
+		}
+
+		return
+	}
+
+	for x, lst := range grid.PosWeights {
+		for y, vw := range lst {
+			cw := r.Intn(vw.MaxWeight)
+
+			for i, w := range vw.Weights {
+				if cw < w {
+					arr[x][y] = vw.Vals[i].Int()
+
+					break
+				}
+
+				cw -= w
+			}
+		}
+	}
+}
+
+// ClusterPayStats - 每个符号每个大小的中奖个数，和 paytable 无关，所以改 paytable 以后不用重新跑
+type ClusterPayStats struct {
+	Type       ClusterPayType
+	IsExact    bool                    // 是否是穷举的结果
+	Spins      float64                 // 穷举时是概率和（应该为 1），模拟时是局数
+	MapSymbols map[int]map[int]float64 // symbol -> size -> count
+}
+
+func newClusterPayStats(t ClusterPayType) *ClusterPayStats {
+	return &ClusterPayStats{
+		Type:       t,
+		MapSymbols: make(map[int]map[int]float64),
+	}
+}
+
+func (stats *ClusterPayStats) add(symbol int, size int, weight float64) {
+	mapNums, isok := stats.MapSymbols[symbol]
+	if !isok {
+		mapNums = make(map[int]float64)
+		stats.MapSymbols[symbol] = mapNums
+	}
+
+	mapNums[size] += weight
+}
+
+func (stats *ClusterPayStats) merge(src *ClusterPayStats) {
+	stats.Spins += src.Spins
+
+	for s, mapNums := range src.MapSymbols {
+		for n, v := range mapNums {
+			stats.add(s, n, v)
+		}
+	}
+}
+
+// GetSymbols - all symbols, sorted
+func (stats *ClusterPayStats) GetSymbols() []int {
+	symbols := []int{}
+	for s := range stats.MapSymbols {
+		symbols = append(symbols, s)
+	}
+
+	slices.Sort(symbols)
+
+	return symbols
+}
+
+// GetMaxSize - the max cluster size
+func (stats *ClusterPayStats) GetMaxSize() int {
+	maxsize := 0
+
+	for _, mapNums := range stats.MapSymbols {
+		for n := range mapNums {
+			if n > maxsize {
+				maxsize = n
+			}
+		}
+	}
+
+	return maxsize
+}
+
+// GetFrequency - 每局平均出现多少个这个符号这个大小的 cluster
+func (stats *ClusterPayStats) GetFrequency(symbol int, size int) float64 {
+	if stats.Spins <= 0 {
+		return 0
+	}
+
+	return stats.MapSymbols[symbol][size] / stats.Spins
+}
+
+// getClusterPay - 和 game 里一样，超过 paytable 长度的按最后一个算
+func getClusterPay(pt *sgc7game.PayTables, symbol int, size int) int {
+	pays, isok := pt.MapPay[symbol]
+	if !isok || len(pays) <= 0 {
+		return 0
+	}
+
+	if size > len(pays) {
+		size = len(pays)
+	}
+
+	return pays[size-1]
+}
+
+// GetRTP - RTP of this symbol and size, bet is the total bet in coins
+func (stats *ClusterPayStats) GetRTP(pt *sgc7game.PayTables, bet int, symbol int, size int) float64 {
+	return stats.GetFrequency(symbol, size) * float64(getClusterPay(pt, symbol, size)) / float64(bet)
+}
+
+// CalcRTP - total RTP and RTP of every symbol, bet is the total bet in coins
+func (stats *ClusterPayStats) CalcRTP(pt *sgc7game.PayTables, bet int) (float64, map[int]float64) {
+	total := float64(0)
+	mapRTP := make(map[int]float64)
+
+	for s, mapNums := range stats.MapSymbols {
+		for n := range mapNums {
+			rtp := stats.GetRTP(pt, bet, s, n)
+
+			mapRTP[s] += rtp
+			total += rtp
+		}
+	}
+
+	return total, mapRTP
+}
+
+// SaveExcel - save frequency and rtp tables, row is symbol and column is size
+func (stats *ClusterPayStats) SaveExcel(fn string, pt *sgc7game.PayTables, bet int) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	symbols := stats.GetSymbols()
+	maxsize := stats.GetMaxSize()
+	total, mapRTP := stats.CalcRTP(pt, bet)
+
+	saveSheet := func(sheet string, getVal func(symbol int, size int) float64) {
+		f.SetCellStr(sheet, goutils.Pos2Cell(0, 0), "symbol")
+		for n := 1; n <= maxsize; n++ {
+			f.SetCellStr(sheet, goutils.Pos2Cell(n, 0), fmt.Sprintf("X%v", n))
+		}
+
+		f.SetCellStr(sheet, goutils.Pos2Cell(maxsize+1, 0), "rtp")
+
+		for i, s := range symbols {
+			str := pt.GetStringFromInt(s)
+			if str == "" {
+				str = fmt.Sprintf("%v", s)
+			}
+
+			f.SetCellStr(sheet, goutils.Pos2Cell(0, i+1), str)
+
+			for n := 1; n <= maxsize; n++ {
+				f.SetCellValue(sheet, goutils.Pos2Cell(n, i+1), getVal(s, n))
+			}
+
+			f.SetCellValue(sheet, goutils.Pos2Cell(maxsize+1, i+1), mapRTP[s])
+		}
+
+		f.SetCellStr(sheet, goutils.Pos2Cell(0, len(symbols)+1), "total")
+		f.SetCellValue(sheet, goutils.Pos2Cell(maxsize+1, len(symbols)+1), total)
+	}
+
+	f.SetSheetName(f.GetSheetName(0), "frequency")
+	saveSheet("frequency", stats.GetFrequency)
+
+	f.NewSheet("rtp")
+	saveSheet("rtp", func(symbol int, size int) float64 {
+		return stats.GetRTP(pt, bet, symbol, size)
+	})
+
+	return f.SaveAs(fn)
+}
+
+// ClusterPayEstimator - 不经过 lowcode，直接在棋盘上统计 cluster 的大小分布
+//
+//	CPTCluster: 4 方向连通，wild 可以属于多个 cluster，只有 wild 的连通块算 wild 的 cluster
+//	CPTAdjacentPay: 横向和纵向的连续段，wild 可以替代，只有 wild 的连续段算 wild 的
+type ClusterPayEstimator struct {
+	Type    ClusterPayType
+	Grid    *ClusterGrid
+	Wilds   []int // wild 的符号编码
+	Symbols []int // 要统计的符号，为空时统计所有出现的符号
+}
+
+func (cpe *ClusterPayEstimator) isWild(s int) bool {
+	return slices.Contains(cpe.Wilds, s)
+}
+
+func (cpe *ClusterPayEstimator) isValidSymbol(s int) bool {
+	if s < 0 {
+		return false
+	}
+
+	if len(cpe.Symbols) == 0 || cpe.isWild(s) {
+		return true
+	}
+
+	return slices.Contains(cpe.Symbols, s)
+}
+
+// countCluster - 从 (x, y) 开始，统计 symbol 和 wild 的连通块，visited 只记录非 wild
+func (cpe *ClusterPayEstimator) countCluster(arr [][]int, x, y int, symbol int, visited [][]bool, inCluster [][]int, id int, stack []int) ([]int, int) {
+	num := 0
+	stack = append(stack[:0], x, y)
+	inCluster[x][y] = id
+
+	for len(stack) > 0 {
+		cx := stack[len(stack)-2]
+		cy := stack[len(stack)-1]
+		stack = stack[:len(stack)-2]
+
+		num++
+
+		if arr[cx][cy] == symbol {
+			visited[cx][cy] = true
+		}
+
+		for d := 0; d < 4; d++ {
+			nx, ny := cx, cy
+
+			switch d {
+			case 0:
+				nx--
+			case 1:
+				nx++
+			case 2:
+				ny--
+			case 3:
+				ny++
+			}
+
+			if nx < 0 || ny < 0 || nx >= len(arr) || ny >= len(arr[nx]) || inCluster[nx][ny] == id {
+				continue
+			}
+
+			cs := arr[nx][ny]
+			if cs == symbol || (cpe.isWild(cs) && !cpe.isWild(symbol)) {
+				inCluster[nx][ny] = id
+				stack = append(stack, nx, ny)
+			}
+		}
+	}
+
+	return stack, num
+}
+
+func (cpe *ClusterPayEstimator) countClusters(arr [][]int, stats *ClusterPayStats, weight float64, visited [][]bool, inCluster [][]int, stack []int) []int {
+	for x := range visited {
+		for y := range visited[x] {
+			visited[x][y] = false
+			inCluster[x][y] = 0
+		}
+	}
+
+	id := 0
+
+	for x, lst := range arr {
+		for y, s := range lst {
+			if visited[x][y] || !cpe.isValidSymbol(s) {
+				continue
+			}
+
+			id++
+
+			var num int
+			stack, num = cpe.countCluster(arr, x, y, s, visited, inCluster, id, stack)
+
+			stats.add(s, num, weight)
+		}
+	}
+
+	return stack
+}
+
+// countSegments - 统计一行（或一列）里的连续段
+func (cpe *ClusterPayEstimator) countSegments(lst []int, stats *ClusterPayStats, weight float64) {
+	for i, s := range lst {
+		if !cpe.isValidSymbol(s) {
+			continue
+		}
+
+		if cpe.isWild(s) {
+			// 只有 wild 的最长段
+			if i > 0 && cpe.isWild(lst[i-1]) {
+				continue
+			}
+
+			end := i + 1
+			for end < len(lst) && cpe.isWild(lst[end]) {
+				end++
+			}
+
+			stats.add(s, end-i, weight)
+
+			continue
+		}
+
+		// 前面是同一个符号（中间可以有 wild）时，已经统计过了
+		start := i
+		for start > 0 && cpe.isWild(lst[start-1]) {
+			start--
+		}
+
+		isCounted := false
+		for j := start; j < i; j++ {
+			if lst[j] == s {
+				isCounted = true
+
+				break
+			}
+		}
+
+		if isCounted || (start > 0 && lst[start-1] == s) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(lst) && (lst[end] == s || cpe.isWild(lst[end])) {
+			end++
+		}
+
+		stats.add(s, end-start, weight)
+	}
+}
+
+func (cpe *ClusterPayEstimator) countAdjacent(arr [][]int, stats *ClusterPayStats, weight float64, row []int) []int {
+	for y := 0; y < cpe.Grid.Height; y++ {
+		row = row[:0]
+		for x := 0; x < cpe.Grid.Width; x++ {
+			row = append(row, arr[x][y])
+		}
+
+		cpe.countSegments(row, stats, weight)
+	}
+
+	for x := 0; x < cpe.Grid.Width; x++ {
+		cpe.countSegments(arr[x], stats, weight)
+	}
+
+	return row
+}
+
+type clusterPayCounter struct {
+	cpe       *ClusterPayEstimator
+	arr       [][]int
+	visited   [][]bool
+	inCluster [][]int
+	buf       []int // cluster 时是 flood fill 的栈，adjacent 时是一行
+	stats     *ClusterPayStats
+}
+
+func (counter *clusterPayCounter) count(weight float64) {
+	if counter.cpe.Type == CPTAdjacentPay {
+		counter.buf = counter.cpe.countAdjacent(counter.arr, counter.stats, weight, counter.buf)
+	} else {
+		counter.buf = counter.cpe.countClusters(counter.arr, counter.stats, weight, counter.visited, counter.inCluster, counter.buf)
+	}
+
+	counter.stats.Spins += weight
+}
+
+func (cpe *ClusterPayEstimator) newCounter() *clusterPayCounter {
+	counter := &clusterPayCounter{
+		cpe:   cpe,
+		stats: newClusterPayStats(cpe.Type),
+	}
+
+	for x := 0; x < cpe.Grid.Width; x++ {
+		counter.arr = append(counter.arr, make([]int, cpe.Grid.Height))
+		counter.visited = append(counter.visited, make([]bool, cpe.Grid.Height))
+		counter.inCluster = append(counter.inCluster, make([]int, cpe.Grid.Height))
+	}
+
+	return counter
+}
+
+// CountScene - count a scene, it's used to check the estimator with a real scene
+func (cpe *ClusterPayEstimator) CountScene(scene *sgc7game.GameScene) *ClusterPayStats {
+	counter := cpe.newCounter()
+
+	for x, arr := range scene.Arr {
+		copy(counter.arr[x], arr)
+	}
+
+	counter.count(1)
+
+	return counter.stats
+}
+
+// GetCombinations - 穷举时的组合数
+func (cpe *ClusterPayEstimator) GetCombinations() int64 {
+	total := int64(1)
+
+	for _, n := range cpe.Grid.getDimensions() {
+		if total > (1<<62)/int64(n) {
+			return -1
+		}
+
+		total *= int64(n)
+	}
+
+	return total
+}
+
+// Enumerate - 穷举所有的棋盘，组合数超过 maxCombinations 时返回错误
+func (cpe *ClusterPayEstimator) Enumerate(maxCombinations int64) (*ClusterPayStats, error) {
+	if cpe.Grid == nil || !cpe.Grid.isValid() {
+		goutils.Error("ClusterPayEstimator.Enumerate",
+			goutils.Err(ErrInvalidClusterGrid))
+
+		return nil, ErrInvalidClusterGrid
+	}
+
+	combinations := cpe.GetCombinations()
+	if combinations < 0 || combinations > maxCombinations {
+		goutils.Error("ClusterPayEstimator.Enumerate",
+			slog.Int64("combinations", combinations),
+			slog.Int64("maxCombinations", maxCombinations),
+			goutils.Err(ErrTooManyCombinations))
+
+		return nil, ErrTooManyCombinations
+	}
+
+	dims := cpe.Grid.getDimensions()
+	indexes := make([]int, len(dims))
+	counter := cpe.newCounter()
+
+	for {
+		weight := cpe.Grid.fill(counter.arr, indexes)
+		if cpe.Grid.Reels != nil {
+			weight = 1 / float64(combinations)
+		}
+
+		counter.count(weight)
+
+		i := 0
+		for ; i < len(indexes); i++ {
+			indexes[i]++
+			if indexes[i] < dims[i] {
+				break
+			}
+
+			indexes[i] = 0
+		}
+
+		if i == len(indexes) {
+			break
+		}
+	}
+
+	counter.stats.IsExact = true
+
+	return counter.stats, nil
+}
+
+// Simulate - Monte Carlo，workers 个协程，每个协程的随机种子是 seed + i
+func (cpe *ClusterPayEstimator) Simulate(spins int64, workers int, seed int64) (*ClusterPayStats, error) {
+	if cpe.Grid == nil || !cpe.Grid.isValid() {
+		goutils.Error("ClusterPayEstimator.Simulate",
+			goutils.Err(ErrInvalidClusterGrid))
+
+		return nil, ErrInvalidClusterGrid
+	}
+
+	if workers <= 0 {
+		workers = 1
+	}
+
+	stats := newClusterPayStats(cpe.Type)
+	lock := sync.Mutex{}
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < workers; i++ {
+		curspins := spins / int64(workers)
+		if int64(i) < spins%int64(workers) {
+			curspins++
+		}
+
+		wg.Add(1)
+
+		go func(curseed int64, curspins int64) {
+			defer wg.Done()
+
+			r := rand.New(rand.NewSource(curseed))
+			counter := cpe.newCounter()
+
+			for j := int64(0); j < curspins; j++ {
+				cpe.Grid.random(counter.arr, r)
+				counter.count(1)
+			}
+
+			lock.Lock()
+			stats.merge(counter.stats)
+			lock.Unlock()
+		}(seed+int64(i), curspins)
+	}
+
+	wg.Wait()
+
+	return stats, nil
+}
+
+// Estimate - 组合数不超过 maxCombinations 时穷举，否则模拟 spins 局
+func (cpe *ClusterPayEstimator) Estimate(maxCombinations int64, spins int64, workers int, seed int64) (*ClusterPayStats, error) {
+	combinations := cpe.GetCombinations()
+	if combinations >= 0 && combinations <= maxCombinations {
+		return cpe.Enumerate(maxCombinations)
+	}
+
+	return cpe.Simulate(spins, workers, seed)
+}
@@ -0,0 +1,224 @@
+package mathtoolset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+)
+
+func newTestClusterPosWeights(t *testing.T, width, height int, symbols []int, weights []int) [][]*sgc7game.ValWeights2 {
+	vals := []sgc7game.IVal{}
+	for _, s := range symbols {
+		vals = append(vals, sgc7game.NewIntValEx(s))
+	}
+
+	arr := [][]*sgc7game.ValWeights2{}
+	for x := 0; x < width; x++ {
+		lst := []*sgc7game.ValWeights2{}
+
+		for y := 0; y < height; y++ {
+			vw, err := sgc7game.NewValWeights2(vals, weights)
+			assert.NoError(t, err)
+
+			lst = append(lst, vw)
+		}
+
+		arr = append(arr, lst)
+	}
+
+	return arr
+}
+
+// calcGameRTPWithEnumerate - 用 game 里的算法穷举，用来检查 estimator
+func calcGameRTPWithEnumerate(t *testing.T, grid *ClusterGrid, pt *sgc7game.PayTables, cpt ClusterPayType, wilds []int) float64 {
+	dims := grid.getDimensions()
+	indexes := make([]int, len(dims))
+
+	scene, err := sgc7game.NewGameScene(grid.Width, grid.Height)
+	assert.NoError(t, err)
+
+	isWild := func(cursymbol int) bool {
+		for _, w := range wilds {
+			if w == cursymbol {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	rtp := float64(0)
+
+	for {
+		prob := grid.fill(scene.Arr, indexes)
+
+		var results []*sgc7game.Result
+		if cpt == CPTCluster {
+			results, err = sgc7game.CalcClusterResult(scene, pt, 1, func(cursymbol int) bool {
+				return true
+			}, isWild, func(cursymbol int, startsymbol int) bool {
+				return cursymbol == startsymbol || isWild(cursymbol)
+			}, func(cursymbol int) int {
+				return cursymbol
+			})
+		} else {
+			results, err = sgc7game.CalcAdjacentPay(scene, pt, 1, func(cursymbol int) bool {
+				return true
+			}, isWild, func(cursymbol int, startsymbol int) bool {
+				return cursymbol == startsymbol || isWild(cursymbol)
+			}, func(cursymbol int) int {
+				return cursymbol
+			})
+		}
+		assert.NoError(t, err)
+
+		for _, r := range results {
+			rtp += prob * float64(r.CoinWin)
+		}
+
+		i := 0
+		for ; i < len(indexes); i++ {
+			indexes[i]++
+			if indexes[i] < dims[i] {
+				break
+			}
+
+			indexes[i] = 0
+		}
+
+		if i == len(indexes) {
+			break
+		}
+	}
+
+	return rtp
+}
+
+func Test_ClusterPayEstimator(t *testing.T) {
+	pt := &sgc7game.PayTables{
+		MapPay: map[int][]int{
+			0: {0, 0, 0, 0, 0, 0, 0, 0, 0},
+			1: {0, 0, 1, 2, 5, 10, 20, 50, 100},
+			2: {0, 0, 2, 4, 8, 15, 30, 60, 150},
+			3: {0, 0, 3, 6, 10, 20, 40, 80, 200},
+		},
+		MapSymbols: map[string]int{
+			"WL": 0,
+			"A":  1,
+			"B":  2,
+			"C":  3,
+		},
+	}
+
+	grid := &ClusterGrid{
+		Width:      3,
+		Height:     3,
+		PosWeights: newTestClusterPosWeights(t, 3, 3, []int{1, 2, 3}, []int{50, 30, 20}),
+	}
+
+	for _, cpt := range []ClusterPayType{CPTCluster, CPTAdjacentPay} {
+		cpe := &ClusterPayEstimator{
+			Type: cpt,
+			Grid: grid,
+		}
+
+		assert.Equal(t, int64(19683), cpe.GetCombinations())
+
+		stats, err := cpe.Enumerate(100000)
+		assert.NoError(t, err)
+		assert.True(t, stats.IsExact)
+		assert.InDelta(t, 1.0, stats.Spins, 1e-9)
+
+		rtp, mapRTP := stats.CalcRTP(pt, 1)
+		assert.InDelta(t, calcGameRTPWithEnumerate(t, grid, pt, cpt, nil), rtp, 1e-9)
+		assert.Equal(t, 3, len(mapRTP))
+
+		// 模拟的结果要接近穷举的结果
+		stats1, err := cpe.Simulate(200000, 4, 1)
+		assert.NoError(t, err)
+		assert.False(t, stats1.IsExact)
+		assert.Equal(t, float64(200000), stats1.Spins)
+
+		rtp1, _ := stats1.CalcRTP(pt, 1)
+		assert.InDelta(t, rtp, rtp1, rtp*0.03)
+
+		_, err = cpe.Enumerate(1000)
+		assert.Equal(t, ErrTooManyCombinations, err)
+	}
+
+	// 带 wild 的 cluster，wild 不赔付时和 game 一致
+	gridw := &ClusterGrid{
+		Width:      3,
+		Height:     3,
+		PosWeights: newTestClusterPosWeights(t, 3, 3, []int{0, 1, 2}, []int{10, 50, 40}),
+	}
+
+	cpe := &ClusterPayEstimator{
+		Type:  CPTCluster,
+		Grid:  gridw,
+		Wilds: []int{0},
+	}
+
+	stats, err := cpe.Enumerate(100000)
+	assert.NoError(t, err)
+
+	rtp, _ := stats.CalcRTP(pt, 1)
+	assert.InDelta(t, calcGameRTPWithEnumerate(t, gridw, pt, CPTCluster, []int{0}), rtp, 1e-9)
+
+	err = stats.SaveExcel("../unittestdata/clusterpays.xlsx", pt, 1)
+	assert.NoError(t, err)
+
+	t.Logf("Test_ClusterPayEstimator OK")
+}
+
+func Test_ClusterPayEstimatorWithReels(t *testing.T) {
+	pt, err := sgc7game.LoadPayTables5JSON("../unittestdata/paytables.json")
+	assert.NoError(t, err)
+
+	rd, err := sgc7game.LoadReels5JSON("../unittestdata/reels.json")
+	assert.NoError(t, err)
+
+	cpe := &ClusterPayEstimator{
+		Type: CPTCluster,
+		Grid: &ClusterGrid{
+			Width:  5,
+			Height: 3,
+			Reels:  rd,
+		},
+		Wilds: []int{0},
+	}
+
+	scene, err := sgc7game.NewGameScene(5, 3)
+	assert.NoError(t, err)
+
+	scene.Arr = [][]int{{1, 1, 2}, {0, 1, 2}, {3, 3, 3}, {4, 4, 4}, {4, 0, 5}}
+
+	stats0 := cpe.CountScene(scene)
+	assert.Equal(t, float64(1), stats0.MapSymbols[1][4])
+	assert.Equal(t, float64(1), stats0.MapSymbols[2][2])
+	assert.Equal(t, float64(1), stats0.MapSymbols[3][4])
+	assert.Equal(t, float64(1), stats0.MapSymbols[4][5])
+	assert.Equal(t, float64(1), stats0.MapSymbols[5][2])
+	assert.Equal(t, float64(2), stats0.MapSymbols[0][1])
+
+	assert.True(t, cpe.GetCombinations() > 1000000)
+
+	stats, err := cpe.Estimate(1000000, 10000, 2, 1)
+	assert.NoError(t, err)
+	assert.False(t, stats.IsExact)
+	assert.Equal(t, float64(10000), stats.Spins)
+
+	rtp, _ := stats.CalcRTP(pt, 20)
+	assert.True(t, rtp > 0)
+
+	cpe.Type = CPTAdjacentPay
+	stats1 := cpe.CountScene(scene)
+	assert.Equal(t, float64(1), stats1.MapSymbols[3][3])
+	assert.Equal(t, float64(1), stats1.MapSymbols[3][2])
+	assert.Equal(t, float64(2), stats1.MapSymbols[3][1])
+	assert.Equal(t, float64(1), stats1.MapSymbols[4][3])
+	assert.Equal(t, float64(3), stats1.MapSymbols[4][2])
+
+	t.Logf("Test_ClusterPayEstimatorWithReels OK")
+}
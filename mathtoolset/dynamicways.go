@@ -0,0 +1,189 @@
+package mathtoolset
+
+import (
+	"log/slog"
+	"slices"
+
+	"github.com/zhs007/goutils"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+)
+
+// DynamicWaysReels - 每个轴的高度按权重随机的 ways 游戏，和 lowcode 里的 dynamicHeightReels 一致，
+//
+//	每个轴随机一个位置，往下取 height 个符号，
+//	有顶轴时，顶轴随机一个位置，从 TopReelStart 开始横着放 TopReelWidth 个符号，每个被覆盖的轴多 1 个格子
+type DynamicWaysReels struct {
+	Reels        [][]int
+	Heights      []*sgc7game.ValWeights2 // 每个轴的高度权重
+	TopReel      []int                   // 空表示没有顶轴
+	TopReelStart int
+	TopReelWidth int
+}
+
+func (dwr *DynamicWaysReels) isValid() bool {
+	if len(dwr.Reels) == 0 || len(dwr.Heights) != len(dwr.Reels) {
+		return false
+	}
+
+	for x, reel := range dwr.Reels {
+		if len(reel) == 0 || dwr.Heights[x] == nil || dwr.Heights[x].MaxWeight <= 0 {
+			return false
+		}
+
+		for _, h := range dwr.Heights[x].Vals {
+			if h.Int() <= 0 {
+				return false
+			}
+		}
+	}
+
+	if len(dwr.TopReel) > 0 {
+		return dwr.TopReelStart >= 0 && dwr.TopReelWidth > 0 && dwr.TopReelStart+dwr.TopReelWidth <= len(dwr.Reels)
+	}
+
+	return true
+}
+
+// getTopSymbol - 顶轴在位置 t 时，第 x 个轴上面的符号，没有时返回 -1
+func (dwr *DynamicWaysReels) getTopSymbol(t int, x int) int {
+	if len(dwr.TopReel) == 0 || x < dwr.TopReelStart || x >= dwr.TopReelStart+dwr.TopReelWidth {
+		return -1
+	}
+
+	return dwr.TopReel[(t+x-dwr.TopReelStart)%len(dwr.TopReel)]
+}
+
+// DynamicWaysRTP - the result of CalcDynamicWaysRTP
+type DynamicWaysRTP struct {
+	TotalRTP  float64
+	SymbolRTP map[SymbolType]float64
+	AvgWays   float64
+}
+
+// calcReelWithSymbol - 一个轴上，匹配符号数量的期望，和一个都不匹配的概率，不包括顶轴
+func calcReelWithSymbol(reel []int, heights *sgc7game.ValWeights2, isMatch func(s int) bool) (float64, float64) {
+	matchNum := 0
+	for _, s := range reel {
+		if isMatch(s) {
+			matchNum++
+		}
+	}
+
+	avgNum := float64(0)
+	noMatchProb := float64(0)
+
+	for i, v := range heights.Vals {
+		h := v.Int()
+		p := float64(heights.Weights[i]) / float64(heights.MaxWeight)
+
+		// 每个位置都被 h 个窗口覆盖
+		avgNum += p * float64(h*matchNum) / float64(len(reel))
+
+		noMatchNum := 0
+		for y := range reel {
+			isNoMatch := true
+			for j := 0; j < h; j++ {
+				if isMatch(reel[(y+j)%len(reel)]) {
+					isNoMatch = false
+
+					break
+				}
+			}
+
+			if isNoMatch {
+				noMatchNum++
+			}
+		}
+
+		noMatchProb += p * float64(noMatchNum) / float64(len(reel))
+	}
+
+	return avgNum, noMatchProb
+}
+
+// CalcDynamicWaysRTP - 精确计算 dynamicHeightReels 的 ways 的 rtp，符号和 wild 都算匹配，wild 自己只和 wild 匹配，
+//
+//	顶轴的位置确定以后，每个轴是独立的，所以按顶轴的位置分别算再求平均
+func CalcDynamicWaysRTP(paytables *sgc7game.PayTables, dwr *DynamicWaysReels, symbols []SymbolType, wilds []SymbolType, totalBet int) (*DynamicWaysRTP, error) {
+	if !dwr.isValid() || totalBet <= 0 {
+		goutils.Error("CalcDynamicWaysRTP:isValid",
+			slog.Int("totalBet", totalBet),
+			goutils.Err(ErrInvalidDynamicWaysReels))
+
+		return nil, ErrInvalidDynamicWaysReels
+	}
+
+	ret := &DynamicWaysRTP{
+		SymbolRTP: make(map[SymbolType]float64),
+		AvgWays:   1,
+	}
+
+	for x := range dwr.Reels {
+		avgHeight := float64(0)
+		for i, v := range dwr.Heights[x].Vals {
+			avgHeight += float64(v.Int()*dwr.Heights[x].Weights[i]) / float64(dwr.Heights[x].MaxWeight)
+		}
+
+		if dwr.getTopSymbol(0, x) >= 0 {
+			avgHeight++
+		}
+
+		ret.AvgWays *= avgHeight
+	}
+
+	topNum := 1
+	if len(dwr.TopReel) > 0 {
+		topNum = len(dwr.TopReel)
+	}
+
+	for _, s := range symbols {
+		arrPay, isok := paytables.MapPay[int(s)]
+		if !isok {
+			continue
+		}
+
+		isMatch := func(cs int) bool {
+			return cs == int(s) || (!slices.Contains(wilds, s) && cs >= 0 && slices.Contains(wilds, SymbolType(cs)))
+		}
+
+		avgNums := make([]float64, len(dwr.Reels))
+		noMatchProbs := make([]float64, len(dwr.Reels))
+
+		for x, reel := range dwr.Reels {
+			avgNums[x], noMatchProbs[x] = calcReelWithSymbol(reel, dwr.Heights[x], isMatch)
+		}
+
+		wins := float64(0)
+
+		for t := 0; t < topNum; t++ {
+			ways := float64(1)
+
+			for x := range dwr.Reels {
+				curAvgNum := avgNums[x]
+				curNoMatchProb := noMatchProbs[x]
+
+				if isMatch(dwr.getTopSymbol(t, x)) {
+					curAvgNum++
+					curNoMatchProb = 0
+				}
+
+				if x > 0 && x <= len(arrPay) && arrPay[x-1] > 0 {
+					wins += float64(arrPay[x-1]) * ways * curNoMatchProb
+				}
+
+				ways *= curAvgNum
+			}
+
+			if len(arrPay) >= len(dwr.Reels) && arrPay[len(dwr.Reels)-1] > 0 {
+				wins += float64(arrPay[len(dwr.Reels)-1]) * ways
+			}
+		}
+
+		rtp := wins / float64(topNum) / float64(totalBet)
+
+		ret.SymbolRTP[s] = rtp
+		ret.TotalRTP += rtp
+	}
+
+	return ret, nil
+}
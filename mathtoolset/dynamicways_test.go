@@ -0,0 +1,176 @@
+package mathtoolset
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+)
+
+func newTestHeightWeights(t *testing.T, heights []int, weights []int) *sgc7game.ValWeights2 {
+	vals := []sgc7game.IVal{}
+	for _, h := range heights {
+		vals = append(vals, sgc7game.NewIntValEx(h))
+	}
+
+	vw, err := sgc7game.NewValWeights2(vals, weights)
+	assert.NoError(t, err)
+
+	return vw
+}
+
+// calcDynamicWaysRTPWithEnumerate - 用 game 里的 CheckWays5 穷举，用来检查 CalcDynamicWaysRTP
+func calcDynamicWaysRTPWithEnumerate(t *testing.T, pt *sgc7game.PayTables, dwr *DynamicWaysReels, wilds []int, height int) (float64, float64) {
+	width := len(dwr.Reels)
+
+	scene, err := sgc7game.NewGameScene(width, height)
+	assert.NoError(t, err)
+
+	topNum := 1
+	if len(dwr.TopReel) > 0 {
+		topNum = len(dwr.TopReel)
+	}
+
+	// 每个轴是 高度 x 位置
+	dims := make([]int, width)
+	for x := range dims {
+		dims[x] = len(dwr.Heights[x].Vals) * len(dwr.Reels[x])
+	}
+
+	indexes := make([]int, width)
+	totalWins := float64(0)
+	totalWays := float64(0)
+
+	for {
+		prob := float64(1)
+		for x, reel := range dwr.Reels {
+			hi := indexes[x] / len(reel)
+			h := dwr.Heights[x].Vals[hi].Int()
+			cn := indexes[x] % len(reel)
+
+			prob *= float64(dwr.Heights[x].Weights[hi]) / float64(dwr.Heights[x].MaxWeight) / float64(len(reel))
+
+			for y := 0; y < height; y++ {
+				if y < height-h {
+					scene.Arr[x][y] = -1
+				} else {
+					scene.Arr[x][y] = reel[(cn+y-height+h)%len(reel)]
+				}
+			}
+		}
+
+		for ti := 0; ti < topNum; ti++ {
+			ways := 1
+			for x := 0; x < width; x++ {
+				s := dwr.getTopSymbol(ti, x)
+				if s >= 0 {
+					scene.Arr[x][0] = s
+				}
+
+				num := 0
+				for _, cs := range scene.Arr[x] {
+					if cs >= 0 {
+						num++
+					}
+				}
+
+				ways *= num
+			}
+
+			results := sgc7game.CheckWays5(scene, pt, 1, func(cursymbol int) bool {
+				return true
+			}, func(cursymbol int, scene *sgc7game.GameScene, x, y int) bool {
+				return scene.Arr[x][y] >= 0
+			}, func(cursymbol int, x, y int) int {
+				return cursymbol
+			}, func(cursymbol int) bool {
+				return slices.Contains(wilds, cursymbol)
+			}, func(cursymbol int, startsymbol int) bool {
+				return cursymbol == startsymbol || slices.Contains(wilds, cursymbol)
+			}, func(x, y int) int {
+				return 1
+			})
+
+			for _, r := range results {
+				totalWins += prob * float64(r.CoinWin) / float64(topNum)
+			}
+
+			totalWays += prob * float64(ways) / float64(topNum)
+		}
+
+		x := 0
+		for ; x < width; x++ {
+			indexes[x]++
+			if indexes[x] < dims[x] {
+				break
+			}
+
+			indexes[x] = 0
+		}
+
+		if x >= width {
+			break
+		}
+	}
+
+	return totalWins, totalWays
+}
+
+func Test_CalcDynamicWaysRTP(t *testing.T) {
+	pt := &sgc7game.PayTables{
+		MapPay: map[int][]int{
+			0: {0, 0, 50},
+			1: {0, 5, 20},
+			2: {0, 2, 10},
+			3: {0, 0, 5},
+		},
+	}
+
+	heights := newTestHeightWeights(t, []int{1, 2, 3}, []int{1, 2, 1})
+
+	dwr := &DynamicWaysReels{
+		Reels: [][]int{
+			{1, 2, 3, 0, 2},
+			{3, 1, 0, 2, 3, 1},
+			{2, 3, 1, 3, 0},
+		},
+		Heights:      []*sgc7game.ValWeights2{heights, heights, newTestHeightWeights(t, []int{2, 3}, []int{3, 1})},
+		TopReel:      []int{0, 2, 3},
+		TopReelStart: 1,
+		TopReelWidth: 1,
+	}
+
+	symbols := []SymbolType{0, 1, 2, 3}
+	wilds := []SymbolType{0}
+
+	ret, err := CalcDynamicWaysRTP(pt, dwr, symbols, wilds, 10)
+	assert.NoError(t, err)
+
+	wins, ways := calcDynamicWaysRTPWithEnumerate(t, pt, dwr, []int{0}, 4)
+	assert.InDelta(t, wins/10, ret.TotalRTP, 0.0000001)
+	assert.InDelta(t, ways, ret.AvgWays, 0.0000001)
+
+	totalRTP := float64(0)
+	for _, s := range symbols {
+		totalRTP += ret.SymbolRTP[s]
+	}
+
+	assert.InDelta(t, ret.TotalRTP, totalRTP, 0.0000001)
+
+	// 没有顶轴
+	dwr.TopReel = nil
+
+	ret, err = CalcDynamicWaysRTP(pt, dwr, symbols, wilds, 10)
+	assert.NoError(t, err)
+
+	wins, ways = calcDynamicWaysRTPWithEnumerate(t, pt, dwr, []int{0}, 3)
+	assert.InDelta(t, wins/10, ret.TotalRTP, 0.0000001)
+	assert.InDelta(t, ways, ret.AvgWays, 0.0000001)
+
+	dwr.Heights = dwr.Heights[1:]
+	_, err = CalcDynamicWaysRTP(pt, dwr, symbols, wilds, 10)
+	assert.ErrorIs(t, err, ErrInvalidDynamicWaysReels)
+
+	t.Logf("Test_CalcDynamicWaysRTP OK")
+}
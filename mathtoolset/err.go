@@ -62,4 +62,15 @@ var (
 	ErrInvalidReelsStats2File = errors.New("invalid reelsstats2 file")
 	// ErrGenStackReel - genStackReel error
 	ErrGenStackReel = errors.New("genStackReel error")
+
+	// ErrInvalidValWeightsFitOptions - invalid ValWeightsFitOptions
+	ErrInvalidValWeightsFitOptions = errors.New("invalid ValWeightsFitOptions")
+
+	// ErrInvalidClusterGrid - invalid ClusterGrid
+	ErrInvalidClusterGrid = errors.New("invalid ClusterGrid")
+	// ErrTooManyCombinations - too many combinations
+	ErrTooManyCombinations = errors.New("too many combinations")
+
+	// ErrInvalidDynamicWaysReels - invalid DynamicWaysReels
+	ErrInvalidDynamicWaysReels = errors.New("invalid DynamicWaysReels")
 )
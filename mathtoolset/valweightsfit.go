@@ -0,0 +1,389 @@
+package mathtoolset
+
+import (
+	"log/slog"
+	"math"
+
+	"github.com/zhs007/goutils"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+)
+
+// smoothPenalty - 平滑约束的惩罚系数，要远大于目标的误差，这样会优先满足平滑约束
+const smoothPenalty = 1e6
+
+type ValWeightsFitTargetType int
+
+const (
+	// VWFTAvg - Scale * E(val)，一般用来算 RTP 贡献
+	VWFTAvg ValWeightsFitTargetType = 0
+	// VWFTHitRate - P(val >= MinVal)，一般用来算大值的命中率
+	VWFTHitRate ValWeightsFitTargetType = 1
+)
+
+// ValWeightsFitTarget - a target of FitValWeights2
+type ValWeightsFitTarget struct {
+	Type       ValWeightsFitTargetType
+	Target     float64
+	Tolerance  float64 // |value - Target| <= Tolerance 就算达标，必须 > 0
+	Importance float64 // 多个目标时的权重，<= 0 时为 1
+	Scale      float64 // 只用于 VWFTAvg，<= 0 时为 1
+	MinVal     float64 // 只用于 VWFTHitRate
+}
+
+// getCoefficient - 目标是权重的线性函数，value = Σ weight[i] * coefficient[i] / totalWeight
+func (target *ValWeightsFitTarget) getCoefficient(val float64) float64 {
+	if target.Type == VWFTHitRate {
+		if val >= target.MinVal {
+			return 1
+		}
+
+		return 0
+	}
+
+	if target.Scale <= 0 {
+		return val
+	}
+
+	return val * target.Scale
+}
+
+func (target *ValWeightsFitTarget) getImportance() float64 {
+	if target.Importance <= 0 {
+		return 1
+	}
+
+	return target.Importance
+}
+
+// isValid - is it a valid target
+func (target *ValWeightsFitTarget) isValid() bool {
+	if target.Tolerance <= 0 {
+		return false
+	}
+
+	return target.Type == VWFTAvg || target.Type == VWFTHitRate
+}
+
+// ValWeightsFitOptions - options of FitValWeights2
+type ValWeightsFitOptions struct {
+	Targets       []*ValWeightsFitTarget
+	TotalWeight   int                             // 总权重，<= 0 时用 vw.MaxWeight
+	MinWeight     int                             // 每个权重的最小值，为 0 时允许某个值被去掉
+	MaxWeight     int                             // 每个权重的最大值，为 0 时不限制
+	SmoothRatio   float64                         // 相邻 2 个权重的比值（大/小）不能超过这个值，为 0 时不限制
+	MaxIterations int                             // 最大迭代次数，为 0 时为 100000
+	FuncGetVal    func(val sgc7game.IVal) float64 // val 转 float64，为 nil 时用 val.Float64()
+}
+
+func (options *ValWeightsFitOptions) getVal(val sgc7game.IVal) float64 {
+	if options.FuncGetVal != nil {
+		return options.FuncGetVal(val)
+	}
+
+	return val.Float64()
+}
+
+// ValWeightsFitResult - result of FitValWeights2
+type ValWeightsFitResult struct {
+	ValWeights  *sgc7game.ValWeights2
+	Values      []float64 // 每个目标的最终值
+	IsConverged bool      // 所有目标都在容差内，且满足平滑约束
+}
+
+type valWeightsFitter struct {
+	options      *ValWeightsFitOptions
+	weights      []int
+	totalWeight  int
+	coefficients [][]float64 // [target][val]
+	values       []float64   // Σ weight[i] * coefficient[i]，没有除以 totalWeight
+}
+
+func (fitter *valWeightsFitter) calcValues() {
+	fitter.values = make([]float64, len(fitter.options.Targets))
+
+	for ti, arr := range fitter.coefficients {
+		for i, c := range arr {
+			fitter.values[ti] += float64(fitter.weights[i]) * c
+		}
+	}
+}
+
+// calcSmoothLoss - 相邻权重比值超过 SmoothRatio 的部分
+func (fitter *valWeightsFitter) calcSmoothLoss() float64 {
+	if fitter.options.SmoothRatio <= 0 {
+		return 0
+	}
+
+	loss := float64(0)
+
+	for i := 1; i < len(fitter.weights); i++ {
+		w0 := fitter.weights[i-1]
+		w1 := fitter.weights[i]
+		if w0 > w1 {
+			w0, w1 = w1, w0
+		}
+
+		var ratio float64
+		if w0 <= 0 {
+			if w1 <= 0 {
+				continue
+			}
+
+			ratio = float64(w1) + 1
+		} else {
+			ratio = float64(w1) / float64(w0)
+		}
+
+		if ratio > fitter.options.SmoothRatio {
+			loss += ratio - fitter.options.SmoothRatio
+		}
+	}
+
+	return loss
+}
+
+// calcLoss - 把 step 从 src 移到 dest 之后的总误差，step 为 0 时就是当前的误差
+func (fitter *valWeightsFitter) calcLoss(src int, dest int, step int) float64 {
+	loss := float64(0)
+
+	for ti, target := range fitter.options.Targets {
+		v := fitter.values[ti]
+		if step > 0 {
+			v += float64(step) * (fitter.coefficients[ti][dest] - fitter.coefficients[ti][src])
+		}
+
+		off := (v/float64(fitter.totalWeight) - target.Target) / target.Tolerance
+		loss += target.getImportance() * off * off
+	}
+
+	if step > 0 {
+		fitter.weights[src] -= step
+		fitter.weights[dest] += step
+	}
+
+	loss += fitter.calcSmoothLoss() * smoothPenalty
+
+	if step > 0 {
+		fitter.weights[src] += step
+		fitter.weights[dest] -= step
+	}
+
+	return loss
+}
+
+func (fitter *valWeightsFitter) canMove(src int, dest int, step int) bool {
+	if fitter.weights[src]-step < fitter.options.MinWeight {
+		return false
+	}
+
+	if fitter.options.MaxWeight > 0 && fitter.weights[dest]+step > fitter.options.MaxWeight {
+		return false
+	}
+
+	return true
+}
+
+func (fitter *valWeightsFitter) isConverged() bool {
+	if fitter.calcSmoothLoss() > 0 {
+		return false
+	}
+
+	for ti, target := range fitter.options.Targets {
+		if math.Abs(fitter.values[ti]/float64(fitter.totalWeight)-target.Target) > target.Tolerance {
+			return false
+		}
+	}
+
+	return true
+}
+
+// initWeights - 按原来的比例缩放到 totalWeight，并满足 MinWeight 和 MaxWeight
+func (fitter *valWeightsFitter) initWeights(vw *sgc7game.ValWeights2) {
+	n := len(vw.Weights)
+	fitter.weights = make([]int, n)
+
+	cur := 0
+	for i, w := range vw.Weights {
+		if vw.MaxWeight > 0 {
+			fitter.weights[i] = int(int64(w) * int64(fitter.totalWeight) / int64(vw.MaxWeight))
+		} else {
+			fitter.weights[i] = fitter.totalWeight / n
+		}
+
+		if fitter.weights[i] < fitter.options.MinWeight {
+			fitter.weights[i] = fitter.options.MinWeight
+		}
+
+		if fitter.options.MaxWeight > 0 && fitter.weights[i] > fitter.options.MaxWeight {
+			fitter.weights[i] = fitter.options.MaxWeight
+		}
+
+		cur += fitter.weights[i]
+	}
+
+	// 多退少补，一次一个，保证不越界
+	for i := 0; cur != fitter.totalWeight; i = (i + 1) % n {
+		if cur < fitter.totalWeight {
+			if fitter.options.MaxWeight <= 0 || fitter.weights[i] < fitter.options.MaxWeight {
+				fitter.weights[i]++
+				cur++
+			}
+		} else if fitter.weights[i] > fitter.options.MinWeight {
+			fitter.weights[i]--
+			cur--
+		}
+	}
+}
+
+// fit - 每次在所有 (src, dest) 里找误差下降最多的移动，找不到就把步长减半
+func (fitter *valWeightsFitter) fit() {
+	n := len(fitter.weights)
+
+	step := fitter.totalWeight / (n * 4)
+	if step <= 0 {
+		step = 1
+	}
+
+	maxIterations := fitter.options.MaxIterations
+	if maxIterations <= 0 {
+		maxIterations = 100000
+	}
+
+	loss := fitter.calcLoss(0, 0, 0)
+
+	for it := 0; it < maxIterations && step > 0; it++ {
+		if fitter.isConverged() {
+			// 已经达标了，继续往目标中心靠，但步长为 1 时就不再细调
+			if step == 1 {
+				break
+			}
+		}
+
+		bestLoss := loss
+		bestSrc := -1
+		bestDest := -1
+
+		for src := 0; src < n; src++ {
+			for dest := 0; dest < n; dest++ {
+				if src == dest || !fitter.canMove(src, dest, step) {
+					continue
+				}
+
+				cl := fitter.calcLoss(src, dest, step)
+				if cl < bestLoss {
+					bestLoss = cl
+					bestSrc = src
+					bestDest = dest
+				}
+			}
+		}
+
+		if bestSrc < 0 {
+			step /= 2
+
+			continue
+		}
+
+		fitter.weights[bestSrc] -= step
+		fitter.weights[bestDest] += step
+
+		for ti := range fitter.options.Targets {
+			fitter.values[ti] += float64(step) * (fitter.coefficients[ti][bestDest] - fitter.coefficients[ti][bestSrc])
+		}
+
+		loss = bestLoss
+	}
+}
+
+// CalcValWeightsFitTarget - calculate the value of target with vw
+func CalcValWeightsFitTarget(vw *sgc7game.ValWeights2, target *ValWeightsFitTarget, funcGetVal func(val sgc7game.IVal) float64) float64 {
+	if vw.MaxWeight <= 0 {
+		return 0
+	}
+
+	options := &ValWeightsFitOptions{FuncGetVal: funcGetVal}
+	ret := float64(0)
+
+	for i, v := range vw.Vals {
+		ret += float64(vw.Weights[i]) * target.getCoefficient(options.getVal(v))
+	}
+
+	return ret / float64(vw.MaxWeight)
+}
+
+// FitValWeights2 - fit the integer weights of vw to hit all targets at the same time,
+//
+//	the sum of weights is options.TotalWeight, and vw is not changed
+func FitValWeights2(vw *sgc7game.ValWeights2, options *ValWeightsFitOptions) (*ValWeightsFitResult, error) {
+	n := len(vw.Vals)
+	if n <= 0 || len(options.Targets) <= 0 {
+		goutils.Error("FitValWeights2",
+			slog.Int("vals", n),
+			slog.Int("targets", len(options.Targets)),
+			goutils.Err(ErrInvalidValWeightsFitOptions))
+
+		return nil, ErrInvalidValWeightsFitOptions
+	}
+
+	fitter := &valWeightsFitter{
+		options:     options,
+		totalWeight: options.TotalWeight,
+	}
+
+	if fitter.totalWeight <= 0 {
+		fitter.totalWeight = vw.MaxWeight
+	}
+
+	if options.MinWeight < 0 || fitter.totalWeight <= 0 || n*options.MinWeight > fitter.totalWeight ||
+		(options.MaxWeight > 0 && n*options.MaxWeight < fitter.totalWeight) {
+		goutils.Error("FitValWeights2",
+			slog.Int("vals", n),
+			slog.Int("totalWeight", fitter.totalWeight),
+			slog.Int("minWeight", options.MinWeight),
+			slog.Int("maxWeight", options.MaxWeight),
+			goutils.Err(ErrInvalidValWeightsFitOptions))
+
+		return nil, ErrInvalidValWeightsFitOptions
+	}
+
+	for i, target := range options.Targets {
+		if !target.isValid() {
+			goutils.Error("FitValWeights2",
+				slog.Int("i", i),
+				slog.Any("target", target),
+				goutils.Err(ErrInvalidValWeightsFitOptions))
+
+			return nil, ErrInvalidValWeightsFitOptions
+		}
+
+		arr := make([]float64, n)
+		for vi, v := range vw.Vals {
+			arr[vi] = target.getCoefficient(options.getVal(v))
+		}
+
+		fitter.coefficients = append(fitter.coefficients, arr)
+	}
+
+	fitter.initWeights(vw)
+	fitter.calcValues()
+	fitter.fit()
+
+	nvw, err := sgc7game.NewValWeights2(vw.Vals, fitter.weights)
+	if err != nil {
+		goutils.Error("FitValWeights2:NewValWeights2",
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	ret := &ValWeightsFitResult{
+		ValWeights:  nvw,
+		IsConverged: fitter.isConverged(),
+	}
+
+	for ti := range options.Targets {
+		ret.Values = append(ret.Values, fitter.values[ti]/float64(fitter.totalWeight))
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,94 @@
+package mathtoolset
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+)
+
+func Test_FitValWeights2(t *testing.T) {
+	vw, err := sgc7game.LoadValWeights2FromExcel("../unittestdata/mysteryweights.xlsx", "val", "weight", sgc7game.NewIntVal[int])
+	assert.NoError(t, err)
+
+	options := &ValWeightsFitOptions{
+		TotalWeight: 10000,
+		MinWeight:   1,
+		SmoothRatio: 2,
+		Targets: []*ValWeightsFitTarget{
+			{Type: VWFTAvg, Target: 3.5, Tolerance: 0.01},
+			{Type: VWFTHitRate, MinVal: 7, Target: 0.1, Tolerance: 0.005},
+		},
+	}
+
+	ret, err := FitValWeights2(vw, options)
+	assert.NoError(t, err)
+	assert.True(t, ret.IsConverged)
+	assert.Equal(t, 10000, ret.ValWeights.MaxWeight)
+	assert.Equal(t, vw.GetIntVals(), ret.ValWeights.GetIntVals())
+
+	for i, target := range options.Targets {
+		v := CalcValWeightsFitTarget(ret.ValWeights, target, nil)
+		assert.InDelta(t, ret.Values[i], v, 1e-9)
+		assert.InDelta(t, target.Target, v, target.Tolerance)
+	}
+
+	for i, w := range ret.ValWeights.Weights {
+		assert.True(t, w >= 1)
+
+		if i > 0 {
+			w0 := ret.ValWeights.Weights[i-1]
+			assert.True(t, float64(w) <= float64(w0)*2 && float64(w0) <= float64(w)*2)
+		}
+	}
+
+	err = ret.ValWeights.SaveExcel("../unittestdata/fitvalweights.xlsx", "val", "weight")
+	assert.NoError(t, err)
+
+	vw1, err := sgc7game.LoadValWeights2FromExcel("../unittestdata/fitvalweights.xlsx", "val", "weight", sgc7game.NewIntVal[int])
+	assert.NoError(t, err)
+	assert.Equal(t, ret.ValWeights.Weights, vw1.Weights)
+
+	// RTP 贡献，Scale 是触发概率
+	ret1, err := FitValWeights2(vw, &ValWeightsFitOptions{
+		TotalWeight: 1000,
+		MinWeight:   1,
+		Targets: []*ValWeightsFitTarget{
+			{Type: VWFTAvg, Scale: 0.01, Target: 0.06, Tolerance: 0.0001},
+		},
+	})
+	assert.NoError(t, err)
+	assert.True(t, ret1.IsConverged)
+	assert.Equal(t, 1000, ret1.ValWeights.MaxWeight)
+
+	// 不可能的目标
+	ret2, err := FitValWeights2(vw, &ValWeightsFitOptions{
+		TotalWeight: 1000,
+		MinWeight:   1,
+		Targets: []*ValWeightsFitTarget{
+			{Type: VWFTAvg, Target: 10, Tolerance: 0.01},
+		},
+	})
+	assert.NoError(t, err)
+	assert.False(t, ret2.IsConverged)
+	assert.Equal(t, 1000, ret2.ValWeights.MaxWeight)
+
+	_, err = FitValWeights2(vw, &ValWeightsFitOptions{
+		TotalWeight: 5,
+		MinWeight:   1,
+		Targets: []*ValWeightsFitTarget{
+			{Type: VWFTAvg, Target: 3, Tolerance: 0.01},
+		},
+	})
+	assert.Equal(t, ErrInvalidValWeightsFitOptions, err)
+
+	_, err = FitValWeights2(vw, &ValWeightsFitOptions{
+		TotalWeight: 1000,
+		Targets: []*ValWeightsFitTarget{
+			{Type: VWFTAvg, Target: 3},
+		},
+	})
+	assert.Equal(t, ErrInvalidValWeightsFitOptions, err)
+
+	t.Logf("Test_FitValWeights2 OK")
+}
@@ -71,4 +71,7 @@ var (
 
 	// ErrInvalidFileData - invalid filedata
 	ErrInvalidFileData = errors.New("invalid filedata")
+
+	// ErrUnsatisfiableExRules - the symbols pool is unsatisfiable with exrules
+	ErrUnsatisfiableExRules = errors.New("the symbols pool is unsatisfiable with exrules")
 )
@@ -1,6 +1,8 @@
 package mathtoolset2
 
 import (
+	"fmt"
+	"io"
 	"log/slog"
 	"slices"
 	"strings"
@@ -8,27 +10,276 @@ import (
 	"github.com/zhs007/goutils"
 )
 
+const (
+	// ExRuleSEP - "SEP_n,S1,S2" 同一个symbol之间至少间隔n个其它symbol（堆叠算一个整体）
+	ExRuleSEP = "SEP"
+	// ExRuleEXC - "EXC_n,S1,S2" 这一组symbol之间至少间隔n个其它symbol
+	ExRuleEXC = "EXC"
+	// ExRuleSTK - "STK_n,S1,S2" symbol连续出现最多n个
+	ExRuleSTK = "STK"
+	// ExRuleDIS - "DIS_n,A,B" A和B之间至少间隔n个symbol，首尾相连
+	ExRuleDIS = "DIS"
+	// ExRulePAT - "PAT_h,S1,S2,S2" 高度为h的窗口里，不能同时出现这些symbol（按数量）
+	ExRulePAT = "PAT"
+	// ExRuleWSC - "WSC_h_n,S1,S2" 高度为h的窗口里，这些symbol最多出现n个
+	ExRuleWSC = "WSC"
+)
+
 type ExRule struct {
 	Code    string
 	Params  []int
 	Symbols []string
 }
 
-// IsFeasible - 判断当前pool和rd是否理论上有解（支持SEP和EXC规则）
+// ExRuleViolation - 已有轮带不满足规则的位置
+type ExRuleViolation struct {
+	ReelIndex int
+	Index     int
+	Rule      string
+}
+
+func (v *ExRuleViolation) String() string {
+	return fmt.Sprintf("R%v[%v] %v", v.ReelIndex+1, v.Index, v.Rule)
+}
+
+// String - 还原成 "SEP_3,SC,WL" 这样的格式
+func (rule *ExRule) String() string {
+	arr := []string{rule.Code}
+	for _, v := range rule.Params {
+		arr = append(arr, fmt.Sprintf("%v", v))
+	}
+
+	return strings.Join(append([]string{strings.Join(arr, "_")}, rule.Symbols...), ",")
+}
+
+// isWindowRule - 新的规则都用窗口来判断
+func (rule *ExRule) isWindowRule() bool {
+	return rule.Code == ExRuleSTK || rule.Code == ExRuleDIS || rule.Code == ExRulePAT || rule.Code == ExRuleWSC
+}
+
+// windowSize - 从某个位置开始，规则需要检查的连续symbol数量
+func (rule *ExRule) windowSize() int {
+	switch rule.Code {
+	case ExRuleSEP, ExRuleEXC, ExRuleSTK, ExRuleDIS:
+		return rule.Params[0] + 1
+	case ExRulePAT, ExRuleWSC:
+		return rule.Params[0]
+	}
+
+	return 0
+}
+
+func (rule *ExRule) getWindow(reel []string, start int, isWrap bool) []string {
+	w := rule.windowSize()
+	if w > len(reel) {
+		w = len(reel)
+	}
+
+	win := make([]string, 0, w)
+	for i := start; i < start+w; i++ {
+		if i < len(reel) {
+			win = append(win, reel[i])
+		} else if isWrap {
+			win = append(win, reel[i-len(reel)])
+		} else {
+			break
+		}
+	}
+
+	return win
+}
+
+// isViolatedAt - 判断从start开始的窗口是否违反规则，isWrap为false时窗口可以不完整
+func (rule *ExRule) isViolatedAt(reel []string, start int, isWrap bool) bool {
+	win := rule.getWindow(reel, start, isWrap)
+	if len(win) == 0 || !slices.Contains(rule.Symbols, win[0]) {
+		return false
+	}
+
+	switch rule.Code {
+	case ExRuleSEP, ExRuleEXC:
+		// 只从堆叠的最后一个symbol开始判断
+		if len(win) < 2 || win[1] == win[0] {
+			return false
+		}
+
+		for _, s := range win[1:] {
+			if s == win[0] || (rule.Code == ExRuleEXC && slices.Contains(rule.Symbols, s)) {
+				return true
+			}
+		}
+	case ExRuleSTK:
+		if len(win) < rule.windowSize() {
+			return false
+		}
+
+		for _, s := range win[1:] {
+			if s != win[0] {
+				return false
+			}
+		}
+
+		return true
+	case ExRuleDIS:
+		other := rule.Symbols[1]
+		if win[0] == rule.Symbols[1] {
+			other = rule.Symbols[0]
+		}
+
+		if other == win[0] {
+			return false
+		}
+
+		return slices.Contains(win[1:], other)
+	case ExRulePAT:
+		for _, s := range rule.Symbols {
+			if countSymbol(win, s) < countSymbol(rule.Symbols, s) {
+				return false
+			}
+		}
+
+		return true
+	case ExRuleWSC:
+		num := 0
+		for _, s := range win {
+			if slices.Contains(rule.Symbols, s) {
+				num++
+			}
+		}
+
+		return num > rule.Params[1]
+	}
+
+	return false
+}
+
+// isOKWithWindow - 把sd放到rd后面，检查所有包含新位置的窗口，最后一次放置时要首尾相连检查
+func (rule *ExRule) isOKWithWindow(rd []string, sd *SymbolData, lastNum int) bool {
+	if !rule.isWindowRule() {
+		return true
+	}
+
+	isLast := lastNum <= sd.Num
+
+	// 不是规则里的symbol，不会产生新的问题，但最后一次放置时需要检查首尾
+	if !isLast && !slices.Contains(rule.Symbols, sd.Symbol) {
+		return true
+	}
+
+	nrd := slices.Clone(rd)
+	for range sd.Num {
+		nrd = append(nrd, sd.Symbol)
+	}
+
+	if isLast {
+		return len(rule.CheckReel(nrd)) == 0
+	}
+
+	start := len(rd) - rule.windowSize() + 1
+	if start < 0 {
+		start = 0
+	}
+
+	for i := start; i < len(nrd); i++ {
+		if rule.isViolatedAt(nrd, i, false) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CheckReel - 检查一个已有的轮带（首尾相连），返回所有违反规则的位置
+func (rule *ExRule) CheckReel(reel []string) []int {
+	lst := []int{}
+
+	if rule.windowSize() <= 0 {
+		return lst
+	}
+
+	for i := range reel {
+		if rule.isViolatedAt(reel, i, true) {
+			lst = append(lst, i)
+		}
+	}
+
+	return lst
+}
+
+// IsFeasible - 判断当前pool和rd是否理论上有解（支持SEP、STK、DIS和WSC规则）
+//
+//	pool 会放在 rd 后面，最后整条轮带首尾相连，所以 pool 的最后一个位置后面接的是 rd[0]
 func (rule *ExRule) IsFeasible(pool *SymbolsPool, rd []string) bool {
-	if rule.Code == "SEP" && len(rule.Symbols) > 0 && len(rule.Params) > 0 {
-		sep := rule.Params[0]
-		total := pool.CountAllSymbolNumber()
+	if rule.Code == ExRuleSTK {
 		for _, sym := range rule.Symbols {
 			cnt := 0
 			for _, sd := range pool.Pool {
 				if sd.Symbol == sym {
+					// 堆叠本身就超过了
+					if sd.Num > rule.Params[0] {
+						return false
+					}
+
 					cnt += sd.Num
 				}
 			}
-			// 最多能放置的位置数
-			maxPos := (total-1)/(sep+1) + 1
-			if cnt > maxPos {
+
+			if cnt > 0 && cnt > rule.getMaxStackNum(rd, sym, pool.CountAllSymbolNumber()-cnt) {
+				return false
+			}
+		}
+	} else if rule.Code == ExRuleDIS {
+		total := pool.CountAllSymbolNumber()
+		cnta := countSymbolInPool(pool, rule.Symbols[0])
+		cntb := countSymbolInPool(pool, rule.Symbols[1])
+		// A和B中间至少要放n个其它symbol
+		if cnta > 0 && cntb > 0 && total-cnta-cntb < rule.Params[0] {
+			return false
+		}
+
+		// 首尾相连以后，A到B和B到A两段都至少要n个其它symbol
+		hasA := cnta > 0 || slices.Contains(rd, rule.Symbols[0])
+		hasB := cntb > 0 || slices.Contains(rd, rule.Symbols[1])
+		if hasA && hasB {
+			others := total - cnta - cntb
+			for _, s := range rd {
+				if !slices.Contains(rule.Symbols, s) {
+					others++
+				}
+			}
+
+			if others < rule.Params[0]*2 {
+				return false
+			}
+		}
+
+		if cnta > 0 && cnta > rule.getDisRangeNum(rd, rule.Symbols[0], total) {
+			return false
+		}
+
+		if cntb > 0 && cntb > rule.getDisRangeNum(rd, rule.Symbols[1], total) {
+			return false
+		}
+	} else if rule.Code == ExRuleWSC {
+		cnt := 0
+		for _, sym := range rule.Symbols {
+			cnt += countSymbolInPool(pool, sym)
+		}
+
+		// 剩下的位置是连续的，每个窗口最多放n个
+		total := pool.CountAllSymbolNumber()
+		maxNum := total/rule.Params[0]*rule.Params[1] + min(rule.Params[1], total%rule.Params[0])
+		if cnt > maxNum {
+			return false
+		}
+	} else if rule.Code == ExRuleSEP && len(rule.Symbols) > 0 && len(rule.Params) > 0 {
+		sep := rule.Params[0]
+		total := pool.CountAllSymbolNumber()
+		for _, sym := range rule.Symbols {
+			// 堆叠算一个整体，每两个堆叠之间至少要n个其它symbol
+			stacks := pool.CountSymbolDataNumber(sym)
+			others := total - countSymbolInPool(pool, sym)
+			if stacks > 0 && getSegmentNum(stacks, len(rd) == 0)*sep > others {
 				return false
 			}
 		}
@@ -37,6 +288,81 @@ func (rule *ExRule) IsFeasible(pool *SymbolsPool, rd []string) bool {
 	return true
 }
 
+// getSegmentNum - num 段之间需要的间隔数，首尾相连时最后一段和第一段之间也要间隔
+func getSegmentNum(num int, isWrap bool) int {
+	if isWrap {
+		return num
+	}
+
+	return num - 1
+}
+
+// getMaxStackNum - STK规则下，pool 里最多还能放多少个 sym，others 是 pool 里其它 symbol 的数量
+//
+//	pool 会被 others 分成最多 others + 1 段，第一段接在 rd 的末尾，最后一段接在 rd[0] 前面（首尾相连），
+//	rd 为空或者全是 sym 时，首尾两段和 rd 是同一段
+func (rule *ExRule) getMaxStackNum(rd []string, sym string, others int) int {
+	n := rule.Params[0]
+
+	head := 0
+	for head < len(rd) && rd[head] == sym {
+		head++
+	}
+
+	if head == len(rd) {
+		// 整条轮带只有 sym 时，长度不超过 n 就可以
+		if others == 0 {
+			return max(n-len(rd), 0)
+		}
+
+		return max(n-len(rd), 0) + (others-1)*n
+	}
+
+	tail := 0
+	for rd[len(rd)-1-tail] == sym {
+		tail++
+	}
+
+	if others == 0 {
+		return max(n-head-tail, 0)
+	}
+
+	return max(n-tail, 0) + max(n-head, 0) + (others-1)*n
+}
+
+// getDisRangeNum - DIS规则下，sym 在 pool 里可以放的位置数量，total 是 pool 里 symbol 的数量
+//
+//	离 rd 末尾最近的另一个 symbol 限制了开始的位置，离 rd[0] 最近的另一个 symbol 限制了结束的位置（首尾相连）
+func (rule *ExRule) getDisRangeNum(rd []string, sym string, total int) int {
+	n := rule.Params[0]
+	start := 0
+	end := total - 1
+
+	for i := len(rd) - 1; i >= 0; i-- {
+		if slices.Contains(rule.Symbols, rd[i]) {
+			if rd[i] != sym {
+				// rd[i] 和 pool[start] 之间有 len(rd)-1-i+start 个symbol
+				start = max(n-(len(rd)-1-i), 0)
+			}
+
+			break
+		}
+	}
+
+	for i, s := range rd {
+		if slices.Contains(rule.Symbols, s) {
+			if s != sym {
+				// pool[end] 和 rd[i] 之间有 total-1-end+i 个symbol
+				end = total - 1 - max(n-i, 0)
+			}
+
+			break
+		}
+	}
+
+	return max(end-start+1, 0)
+}
+
 func (rule *ExRule) procWeight(sd []*SymbolData) {
 	if rule.Code == "EXC" {
 		totalWeight := 0
@@ -82,6 +408,10 @@ func (rule *ExRule) isOKForHead(rd []string, sd *SymbolData, lastNum int) bool {
 }
 
 func (rule *ExRule) IsOK(rd []string, sd *SymbolData, lastNum int) bool {
+	if rule.isWindowRule() {
+		return rule.isOKWithWindow(rd, sd, lastNum)
+	}
+
 	// separation
 	if rule.Code == "SEP" {
 		if !slices.Contains(rule.Symbols, sd.Symbol) {
@@ -159,9 +489,37 @@ func ParseExRule(code string) (*ExRule, error) {
 
 	rule.Symbols = arr[1:]
 
+	if !rule.isValid() {
+		goutils.Error("ParseExRule:isValid",
+			slog.String("code", code),
+			goutils.Err(ErrInvalidCode))
+
+		return nil, ErrInvalidCode
+	}
+
 	return rule, nil
 }
 
+// isValid - 检查已知规则的参数
+func (rule *ExRule) isValid() bool {
+	switch rule.Code {
+	case ExRuleSEP, ExRuleEXC, ExRuleDIS:
+		if len(rule.Params) != 1 || rule.Params[0] <= 0 {
+			return false
+		}
+
+		if rule.Code == ExRuleDIS {
+			return len(rule.Symbols) == 2 && rule.Symbols[0] != rule.Symbols[1]
+		}
+	case ExRuleSTK, ExRulePAT:
+		return len(rule.Params) == 1 && rule.Params[0] > 0
+	case ExRuleWSC:
+		return len(rule.Params) == 2 && rule.Params[0] > 0 && rule.Params[1] >= 0
+	}
+
+	return true
+}
+
 // ParseExRules - code is like "OFF_3,SC,WL;OFF_5,H1,H2;"
 func ParseExRules(code string) ([]*ExRule, error) {
 	rules := []*ExRule{}
@@ -209,3 +567,66 @@ func BuildCurSymbols(rd []string, rules []*ExRule, pool *SymbolsPool) []*SymbolD
 
 	return lst
 }
+
+// CheckReelsWithExRules - 检查已有的轮带，返回所有违反规则的位置
+func CheckReelsWithExRules(reels [][]string, rules []*ExRule) []*ExRuleViolation {
+	lst := []*ExRuleViolation{}
+
+	for ri, reel := range reels {
+		for _, rule := range rules {
+			for _, i := range rule.CheckReel(reel) {
+				lst = append(lst, &ExRuleViolation{
+					ReelIndex: ri,
+					Index:     i,
+					Rule:      rule.String(),
+				})
+			}
+		}
+	}
+
+	return lst
+}
+
+// CheckReels - 校验模式，reader是轮带的excel文件
+func CheckReels(reader io.Reader, strExRule string) ([]*ExRuleViolation, error) {
+	reels, err := LoadReels(reader)
+	if err != nil {
+		goutils.Error("CheckReels:LoadReels",
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	rules, err := ParseExRules(strExRule)
+	if err != nil {
+		goutils.Error("CheckReels:ParseExRules",
+			slog.String("strExRule", strExRule),
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	return CheckReelsWithExRules(reels, rules), nil
+}
+
+func countSymbol(arr []string, symbol string) int {
+	num := 0
+	for _, s := range arr {
+		if s == symbol {
+			num++
+		}
+	}
+
+	return num
+}
+
+func countSymbolInPool(pool *SymbolsPool, symbol string) int {
+	num := 0
+	for _, sd := range pool.Pool {
+		if sd.Symbol == symbol {
+			num += sd.Num
+		}
+	}
+
+	return num
+}
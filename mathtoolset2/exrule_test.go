@@ -19,3 +19,194 @@ func Test_ParseExRule(t *testing.T) {
 
 	t.Logf("Test_ParseExRule OK")
 }
+
+func Test_ParseExRuleNewCodes(t *testing.T) {
+	rule, err := ParseExRule("WSC_3_1,SC")
+	assert.NoError(t, err)
+	assert.Equal(t, ExRuleWSC, rule.Code)
+	assert.Equal(t, []int{3, 1}, rule.Params)
+	assert.Equal(t, "WSC_3_1,SC", rule.String())
+
+	_, err = ParseExRule("DIS_2,SC")
+	assert.ErrorIs(t, err, ErrInvalidCode)
+
+	_, err = ParseExRule("WSC_3,SC")
+	assert.ErrorIs(t, err, ErrInvalidCode)
+
+	_, err = ParseExRule("STK_0,WL")
+	assert.ErrorIs(t, err, ErrInvalidCode)
+
+	t.Logf("Test_ParseExRuleNewCodes OK")
+}
+
+func Test_ExRuleCheckReel(t *testing.T) {
+	rules, err := ParseExRules("STK_2,WL;DIS_2,SC,WL;PAT_3,SC,H1;WSC_4_1,SC;SEP_2,H1")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, len(rules))
+
+	// STK_2,WL
+	assert.Equal(t, []int{1}, rules[0].CheckReel([]string{"L1", "WL", "WL", "WL", "L2"}))
+	// 首尾相连
+	assert.Equal(t, []int{4}, rules[0].CheckReel([]string{"WL", "WL", "L1", "L2", "WL"}))
+
+	// DIS_2,SC,WL
+	assert.Equal(t, []int{0}, rules[1].CheckReel([]string{"SC", "L1", "WL", "L2", "L3", "L4"}))
+	assert.Equal(t, []int{5}, rules[1].CheckReel([]string{"WL", "L1", "L2", "L3", "L4", "SC"}))
+	assert.Equal(t, 0, len(rules[1].CheckReel([]string{"SC", "L1", "L2", "WL", "L3", "L4"})))
+
+	// PAT_3,SC,H1
+	assert.Equal(t, []int{1}, rules[2].CheckReel([]string{"L1", "SC", "L2", "H1", "L3", "L4"}))
+
+	// WSC_4_1,SC
+	assert.Equal(t, []int{1}, rules[3].CheckReel([]string{"L1", "SC", "L2", "L3", "SC", "L4", "L5"}))
+	assert.Equal(t, 0, len(rules[3].CheckReel([]string{"L1", "SC", "L2", "L3", "L4", "SC", "L5", "L6"})))
+
+	// SEP_2,H1，堆叠算一个整体
+	assert.Equal(t, 0, len(rules[4].CheckReel([]string{"H1", "H1", "L1", "L2", "H1", "L3", "L4"})))
+	assert.Equal(t, []int{1}, rules[4].CheckReel([]string{"H1", "H1", "L1", "H1", "L2", "L3", "L4"}))
+
+	lst := CheckReelsWithExRules([][]string{{"L1", "WL", "WL", "WL", "L2", "L3"}, {"SC", "L1", "WL", "L2", "L3", "L4"}}, rules)
+	assert.Equal(t, 2, len(lst))
+	assert.Equal(t, "R1[1] STK_2,WL", lst[0].String())
+	assert.Equal(t, "R2[0] DIS_2,SC,WL", lst[1].String())
+
+	t.Logf("Test_ExRuleCheckReel OK")
+}
+
+func Test_ExRuleIsFeasible(t *testing.T) {
+	pool := &SymbolsPool{}
+	pool.PushEx("WL", 1, 6)
+	pool.PushEx("L1", 1, 2)
+
+	rule, err := ParseExRule("STK_3,WL")
+	assert.NoError(t, err)
+	assert.True(t, rule.IsFeasible(pool, nil))
+
+	rule, err = ParseExRule("STK_1,WL")
+	assert.NoError(t, err)
+	assert.False(t, rule.IsFeasible(pool, nil))
+
+	rule, err = ParseExRule("WSC_4_1,WL")
+	assert.NoError(t, err)
+	assert.False(t, rule.IsFeasible(pool, nil))
+
+	pool.Push("H1", 3)
+
+	rule, err = ParseExRule("STK_2,H1")
+	assert.NoError(t, err)
+	assert.False(t, rule.IsFeasible(pool, nil))
+
+	rule, err = ParseExRule("DIS_3,WL,H1")
+	assert.NoError(t, err)
+	assert.False(t, rule.IsFeasible(pool, nil))
+
+	_, err = genReelDeep(pool, []*ExRule{rule}, []string{})
+	assert.ErrorIs(t, err, ErrUnsatisfiableExRules)
+
+	// 首尾相连，3个WL要分成2段，需要2个其它symbol
+	pool = &SymbolsPool{}
+	pool.PushEx("WL", 1, 3)
+	pool.PushEx("L1", 1, 1)
+
+	rule, err = ParseExRule("STK_2,WL")
+	assert.NoError(t, err)
+	assert.False(t, rule.IsFeasible(pool, nil))
+	assert.True(t, rule.IsFeasible(pool, []string{"L1"}))
+
+	// SEP 按堆叠数算，[L1×9 WL×4 L1×3 WL×4] 这样是可以的
+	pool = &SymbolsPool{}
+	pool.PushEx("WL", 4, 2)
+	pool.PushEx("L1", 1, 12)
+
+	rule, err = ParseExRule("SEP_3,WL")
+	assert.NoError(t, err)
+	assert.True(t, rule.IsFeasible(pool, nil))
+	assert.Empty(t, rule.CheckReel([]string{"L1", "L1", "L1", "L1", "L1", "L1", "L1", "L1", "L1",
+		"WL", "WL", "WL", "WL", "L1", "L1", "L1", "WL", "WL", "WL", "WL"}))
+
+	rd, err := genReelDeep(pool, []*ExRule{rule}, []string{})
+	assert.NoError(t, err)
+	assert.Len(t, rd, 20)
+	assert.Empty(t, rule.CheckReel(rd))
+
+	pool = &SymbolsPool{}
+	pool.PushEx("WL", 4, 3)
+	pool.PushEx("L1", 1, 8)
+	assert.False(t, rule.IsFeasible(pool, nil))
+
+	t.Logf("Test_ExRuleIsFeasible OK")
+}
+
+func Test_ExRuleIsFeasibleWrap(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+		pool []*SymbolData
+		rd   []string
+		want bool
+	}{
+		// 轮带长度等于堆叠长度
+		{"stack = reel", "STK_3,WL", []*SymbolData{{Symbol: "WL", Num: 3}}, nil, true},
+		{"symbols = reel", "STK_3,WL", []*SymbolData{{Symbol: "WL", Num: 1}, {Symbol: "WL", Num: 1}, {Symbol: "WL", Num: 1}}, nil, true},
+		{"symbols > reel", "STK_3,WL", []*SymbolData{{Symbol: "WL", Num: 1}, {Symbol: "WL", Num: 1}, {Symbol: "WL", Num: 1}, {Symbol: "WL", Num: 1}}, nil, false},
+		{"rd + stack = reel", "STK_2,WL", []*SymbolData{{Symbol: "WL", Num: 1}}, []string{"WL"}, true},
+		{"rd + stack > reel", "STK_2,WL", []*SymbolData{{Symbol: "WL", Num: 1}, {Symbol: "WL", Num: 1}}, []string{"WL"}, false},
+		{"rd all stacked", "STK_2,WL", []*SymbolData{{Symbol: "WL", Num: 1}, {Symbol: "L1", Num: 1}}, []string{"WL"}, true},
+		// pool 的首尾都接着 rd 里的 WL
+		{"stack joins head and tail", "STK_2,WL", []*SymbolData{{Symbol: "WL", Num: 1}, {Symbol: "WL", Num: 1}}, []string{"WL", "L1", "WL"}, false},
+		{"stack split by others", "STK_2,WL", []*SymbolData{{Symbol: "WL", Num: 1}, {Symbol: "L1", Num: 1}}, []string{"WL", "L1", "WL"}, true},
+		// 首尾相连以后 A 到 B、B 到 A 两段都要间隔
+		{"both arcs", "DIS_2,SC,WL", []*SymbolData{{Symbol: "SC", Num: 1}, {Symbol: "L1", Num: 1}, {Symbol: "L2", Num: 1},
+			{Symbol: "WL", Num: 1}, {Symbol: "L3", Num: 1}, {Symbol: "L4", Num: 1}}, nil, true},
+		{"one arc only", "DIS_2,SC,WL", []*SymbolData{{Symbol: "SC", Num: 1}, {Symbol: "L1", Num: 1}, {Symbol: "L2", Num: 1},
+			{Symbol: "WL", Num: 1}, {Symbol: "L3", Num: 1}}, nil, false},
+		// pool 的最后几个位置后面接的是 rd[0]
+		{"wrap to rd head", "DIS_2,SC,WL", []*SymbolData{{Symbol: "WL", Num: 1}, {Symbol: "L1", Num: 1}, {Symbol: "L2", Num: 1}},
+			[]string{"SC", "L3", "L4"}, true},
+		{"wrap to rd middle", "DIS_2,SC,WL", []*SymbolData{{Symbol: "WL", Num: 1}, {Symbol: "L1", Num: 1}, {Symbol: "L2", Num: 1}},
+			[]string{"L3", "SC", "L4"}, true},
+		{"wrap to rd tail", "DIS_2,SC,WL", []*SymbolData{{Symbol: "WL", Num: 1}, {Symbol: "L1", Num: 1}, {Symbol: "L2", Num: 1}},
+			[]string{"L3", "L4", "SC"}, true},
+		{"wrap too close", "DIS_2,SC,WL", []*SymbolData{{Symbol: "L1", Num: 1}, {Symbol: "WL", Num: 1}},
+			[]string{"SC", "L3", "L4", "L5", "L6"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseExRule(tt.rule)
+			assert.NoError(t, err)
+
+			pool := &SymbolsPool{Pool: tt.pool}
+			assert.Equal(t, tt.want, rule.IsFeasible(pool, tt.rd))
+
+			// 和 CheckReel 的结果要一致
+			rd, err := genReelDeep(pool, []*ExRule{rule}, tt.rd)
+			if tt.want {
+				assert.NoError(t, err)
+				assert.Empty(t, rule.CheckReel(rd))
+			} else {
+				assert.ErrorIs(t, err, ErrUnsatisfiableExRules)
+			}
+		})
+	}
+
+	t.Logf("Test_ExRuleIsFeasibleWrap OK")
+}
+
+func Test_GenReelWithExRules(t *testing.T) {
+	pool := &SymbolsPool{}
+	pool.PushEx("L1", 1, 8)
+	pool.PushEx("L2", 1, 8)
+	pool.PushEx("WL", 1, 4)
+	pool.PushEx("SC", 1, 3)
+
+	rules, err := ParseExRules("STK_1,WL;WSC_5_1,SC;DIS_1,SC,WL")
+	assert.NoError(t, err)
+
+	rd, err := genReelDeep(pool, rules, []string{})
+	assert.NoError(t, err)
+	assert.Equal(t, 23, len(rd))
+	assert.Equal(t, 0, len(CheckReelsWithExRules([][]string{rd}, rules)))
+
+	t.Logf("Test_GenReelWithExRules OK")
+}
@@ -9,18 +9,18 @@ import (
 )
 
 func genReelDeep(pool *SymbolsPool, rules []*ExRule, rd []string) ([]string, error) {
+	// 可行性剪枝，递归前判断所有规则
+	for _, rule := range rules {
+		if !rule.IsFeasible(pool, rd) {
+			return nil, ErrUnsatisfiableExRules
+		}
+	}
+
 	lst := BuildCurSymbols(rd, rules, pool)
 	if len(lst) <= 0 {
-		return nil, ErrUnkonow
+		return nil, ErrUnsatisfiableExRules
 	}
 
-	// // 新增全局可行性剪枝，递归前判断所有规则
-	// for _, rule := range rules {
-	// 	if !rule.IsFeasible(pool, rd) {
-	// 		return nil, ErrUnkonow
-	// 	}
-	// }
-
 retry:
 	nrd := slices.Clone(rd)
 	npool := pool.Clone()
@@ -41,7 +41,7 @@ retry:
 	ret, err := genReelDeep(npool, rules, nrd)
 	if err != nil {
 		if len(lst) <= 0 {
-			return nil, ErrUnkonow
+			return nil, ErrUnsatisfiableExRules
 		}
 
 		goto retry
@@ -61,7 +61,26 @@ func genReel(rs2 *ReelStats2, rules []*ExRule) ([]string, error) {
 		return nil, err
 	}
 
-	return genReelDeep(pool, rules, rd)
+	for _, rule := range rules {
+		if !rule.IsFeasible(pool, rd) {
+			goutils.Error("genReel:IsFeasible",
+				slog.String("rule", rule.String()),
+				goutils.Err(ErrUnsatisfiableExRules))
+
+			return nil, ErrUnsatisfiableExRules
+		}
+	}
+
+	reel, err := genReelDeep(pool, rules, rd)
+	if err != nil {
+		goutils.Error("genReel:genReelDeep",
+			slog.Int("symbols", pool.CountAllSymbolNumber()),
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	return reel, nil
 }
 
 func GenReels(reader io.Reader, strExRule string) ([][]string, error) {
@@ -3,53 +3,52 @@ package mathtoolset2
 import (
 	"context"
 	"log/slog"
-	"net"
 
 	goutils "github.com/zhs007/goutils"
+	"github.com/zhs007/slotsgamecore7/grpcutils"
 	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
 	sgc7ver "github.com/zhs007/slotsgamecore7/ver"
-	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
-	"google.golang.org/grpc"
 )
 
 // Serv - Service
 type Serv struct {
 	sgc7pb.UnimplementedMathToolsetServer
-	lis      net.Listener
-	grpcServ *grpc.Server
+	grpcServ *grpcutils.Server
 }
 
 // NewServ -
 func NewServ(bindaddr string, version string, useOpenTelemetry bool) (*Serv, error) {
+	opts := &grpcutils.ServOptions{}
+
+	if useOpenTelemetry {
+		opts.UseOpenTelemetry = true
+		opts.MaxRecvMsgSize = 1024 * 1024 * 10
+		opts.MaxSendMsgSize = 1024 * 1024 * 10
+	}
+
+	return NewServWithOptions(bindaddr, version, opts)
+}
+
+// NewServWithOptions - new a Serv with ServOptions
+func NewServWithOptions(bindaddr string, version string, opts *grpcutils.ServOptions) (*Serv, error) {
 	// lowcode.SetJsonMode()
 
-	lis, err := net.Listen("tcp", bindaddr)
+	grpcServ, err := grpcutils.NewServer(bindaddr, opts)
 	if err != nil {
-		goutils.Error("NewServ.Listen",
+		goutils.Error("NewServWithOptions:NewServer",
 			goutils.Err(err))
 
 		return nil, err
 	}
 
-	var grpcServ *grpc.Server
-
-	if useOpenTelemetry {
-		grpcServ = grpc.NewServer(
-			grpc.MaxRecvMsgSize(1024*1024*10),
-			grpc.MaxSendMsgSize(1024*1024*10),
-			// otelgrpc v0.63.0 uses stats handlers rather than interceptors
-			grpc.StatsHandler(otelgrpc.NewServerHandler()),
-		)
-	} else {
-		grpcServ = grpc.NewServer()
-	}
-
 	serv := &Serv{
-		lis:      lis,
 		grpcServ: grpcServ,
 	}
 
-	sgc7pb.RegisterMathToolsetServer(grpcServ, serv)
+	sgc7pb.RegisterMathToolsetServer(grpcServ.GRPCServ, serv)
+
+	grpcServ.SetServing("", true)
+	grpcServ.SetServing(sgc7pb.MathToolset_ServiceDesc.ServiceName, true)
 
 	goutils.Info("NewServ OK.",
 		slog.String("addr", bindaddr),
@@ -61,12 +60,12 @@ func NewServ(bindaddr string, version string, useOpenTelemetry bool) (*Serv, err
 
 // Start - start a service
 func (serv *Serv) Start(ctx context.Context) error {
-	return serv.grpcServ.Serve(serv.lis)
+	return serv.grpcServ.Start()
 }
 
-// Stop - stop service
+// Stop - stop service, the in-flight requests can finish in DrainTimeout
 func (serv *Serv) Stop() {
-	serv.lis.Close()
+	serv.grpcServ.Stop()
 }
 
 // initGame - initial game
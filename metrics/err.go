@@ -0,0 +1,8 @@
+package metrics
+
+import "errors"
+
+var (
+	// ErrNegativeCounter - a counter can not be decreased
+	ErrNegativeCounter = errors.New("negative counter value")
+)
@@ -0,0 +1,76 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/zhs007/slotsgamecore7/cheatpolicy"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	"github.com/zhs007/slotsgamecore7/grpcutils"
+	"github.com/zhs007/slotsgamecore7/lowcode"
+	"github.com/zhs007/slotsgamecore7/roundstore"
+	"github.com/zhs007/slotsgamecore7/wallet"
+)
+
+// ErrorReasonOther - the error label of the errors not in the list
+const ErrorReasonOther = "other"
+
+// ErrorReasonCheat - the error label of the cheatpolicy errors
+const ErrorReasonCheat = "cheat"
+
+// UnknownGame - the game label of an invalid gameCode
+const UnknownGame = "unknown"
+
+// errorReason - err -> the error label
+type errorReason struct {
+	err    error
+	reason string
+}
+
+var lockErrorReasons sync.RWMutex
+
+// errorReasons - error 这个 label 只用这里的值，不能直接用 err.Error()，否则 label 的数量没有上限
+var errorReasons = []*errorReason{
+	{sgc7game.ErrInvalidStake, "invalid_stake"},
+	{grpcutils.ErrServerDraining, "draining"},
+	{roundstore.ErrInvalidRoundID, "invalid_round"},
+	{roundstore.ErrRoundNotFound, "invalid_round"},
+	{roundstore.ErrRoundFinished, "invalid_round"},
+	{roundstore.ErrInvalidGameCode, "invalid_round"},
+	{roundstore.ErrInvalidCommand, "invalid_command"},
+	{roundstore.ErrInvalidPlayerID, "invalid_player"},
+	{roundstore.ErrInvalidStake, "invalid_stake"},
+	{wallet.ErrInsufficientBalance, "insufficient_balance"},
+	{lowcode.ErrInvalidCommand, "invalid_command"},
+	{lowcode.ErrInvalidCmd, "invalid_command"},
+	{lowcode.ErrInvalidCmdParam, "invalid_command"},
+	{context.Canceled, "canceled"},
+	{context.DeadlineExceeded, "timeout"},
+}
+
+// AddErrorReason - the error label of err is reason, it's for the errors of the servers, call it before the servers start
+func AddErrorReason(err error, reason string) {
+	lockErrorReasons.Lock()
+	defer lockErrorReasons.Unlock()
+
+	errorReasons = append(errorReasons, &errorReason{err: err, reason: reason})
+}
+
+// GetErrorReason - err -> the error label, it's one of a fixed set of values
+func GetErrorReason(err error) string {
+	if cheatpolicy.IsCheatError(err) {
+		return ErrorReasonCheat
+	}
+
+	lockErrorReasons.RLock()
+	defer lockErrorReasons.RUnlock()
+
+	for _, v := range errorReasons {
+		if errors.Is(err, v.err) {
+			return v.reason
+		}
+	}
+
+	return ErrorReasonOther
+}
@@ -0,0 +1,219 @@
+package metrics
+
+import (
+	"time"
+
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	"github.com/zhs007/slotsgamecore7/lowcode"
+	sgc7pbutils "github.com/zhs007/slotsgamecore7/pbutils"
+	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
+)
+
+// DefaultLatencyBuckets - the buckets of play latency, in seconds
+var DefaultLatencyBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5}
+
+// DefaultRngBuckets - the buckets of rng calls in a play
+var DefaultRngBuckets = []float64{1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500}
+
+// BetCommands - 这些命令会开始新的一局，bet 只在这些命令时统计
+var BetCommands = []string{"", "SPIN"}
+
+// DefaultCurrencies - the default currency labels, use GameMetrics.SetCurrencies to change them
+var DefaultCurrencies = []string{"EUR", "USD", "GBP", "CNY", "JPY", "KRW", "INR", "BRL", "CAD", "AUD"}
+
+// UnknownCurrency - the currency label of the currencies not in the configured set
+const UnknownCurrency = "unknown"
+
+// PlayInfo - the data of a play for metrics, it is used by the servers without protobuf, like gatiserv
+type PlayInfo struct {
+	GameCode string
+	Command  string
+	Currency string
+	CashBet  float64
+	CashWin  float64
+	RngNum   int
+}
+
+// GameMetrics - the metrics shared by all game servers
+//
+//	所有的 metric 都有 server 和 game 两个 label，gamecollection 之外的服务只有一个游戏，game 是配置里的 gameCode（可以为空）
+//	currency 是客户端传过来的，不在配置里的 currency 都是 UnknownCurrency，否则 label 的数量没有上限
+type GameMetrics struct {
+	Server       string
+	Registry     *Registry
+	Plays        *CounterVec   // sgc7_plays_total{server,game,command}
+	PlayDuration *HistogramVec // sgc7_play_duration_seconds{server,game}
+	Errors       *CounterVec   // sgc7_errors_total{server,game,error}，error 是 GetErrorReason
+	RngCalls     *HistogramVec // sgc7_rng_calls{server,game}
+	Rounds       *CounterVec   // sgc7_rounds_total{server,game,currency}
+	Bet          *CounterVec   // sgc7_bet_total{server,game,currency}
+	Win          *CounterVec   // sgc7_win_total{server,game,currency}
+	GameVersions *GaugeVec     // sgc7_game_versions{server,game}
+	ActiveGame   *GaugeVec     // sgc7_game_active_version{server,game,version}
+	PoolCreated  *GaugeVec     // sgc7_gameprop_pool_created{server,game,version,bet}
+	PoolInUse    *GaugeVec     // sgc7_gameprop_pool_in_use{server,game,version,bet}
+	currencies   map[string]bool
+}
+
+// IsBetCommand - is it a command to start a new round
+func IsBetCommand(cmd string) bool {
+	for _, v := range BetCommands {
+		if v == cmd {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetCurrencies - set the currency labels, the other currencies are UnknownCurrency, call it before the servers start
+func (m *GameMetrics) SetCurrencies(currencies []string) {
+	m.currencies = make(map[string]bool, len(currencies))
+
+	for _, v := range currencies {
+		m.currencies[v] = true
+	}
+}
+
+// getCurrency - the currency label
+func (m *GameMetrics) getCurrency(currency string) string {
+	if m.currencies[currency] {
+		return currency
+	}
+
+	return UnknownCurrency
+}
+
+// OnPlay - add a play, nil safe
+func (m *GameMetrics) OnPlay(info *PlayInfo, err error, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+
+	m.PlayDuration.Observe(elapsed.Seconds(), m.Server, info.GameCode)
+
+	if err != nil {
+		m.OnError(info.GameCode, err)
+
+		return
+	}
+
+	cmd := info.Command
+	if cmd == "" {
+		cmd = "SPIN"
+	}
+
+	m.Plays.Inc(m.Server, info.GameCode, cmd)
+	m.RngCalls.Observe(float64(info.RngNum), m.Server, info.GameCode)
+
+	currency := m.getCurrency(info.Currency)
+
+	if IsBetCommand(info.Command) {
+		m.Rounds.Inc(m.Server, info.GameCode, currency)
+		m.Bet.Add(info.CashBet, m.Server, info.GameCode, currency)
+	}
+
+	m.Win.Add(info.CashWin, m.Server, info.GameCode, currency)
+}
+
+// OnPlayPB - add a play with protobuf, nil safe
+func (m *GameMetrics) OnPlayPB(gameCode string, req *sgc7pb.RequestPlay, reply *sgc7pb.ReplyPlay, err error, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+
+	info := &PlayInfo{
+		GameCode: gameCode,
+	}
+
+	if req != nil {
+		info.Command = req.Command
+	}
+
+	if req != nil && req.Stake != nil {
+		stake := sgc7pbutils.BuildStake(req.Stake)
+
+		info.Currency = stake.Currency
+		info.CashBet = float64(stake.CashBet)
+	}
+
+	if reply != nil {
+		info.RngNum = len(reply.RandomNumbers)
+
+		for _, r := range reply.Results {
+			info.CashWin += float64(r.CashWin)
+		}
+	}
+
+	m.OnPlay(info, err, elapsed)
+}
+
+// OnError - add an error, nil safe
+func (m *GameMetrics) OnError(gameCode string, err error) {
+	if m == nil || err == nil {
+		return
+	}
+
+	m.Errors.Inc(m.Server, gameCode, GetErrorReason(err))
+}
+
+// SetGameVersions - set the versions of a game, it should be called in OnCollect
+func (m *GameMetrics) SetGameVersions(gameCode string, versions int, activeVersion string) {
+	m.GameVersions.Set(float64(versions), m.Server, gameCode)
+	m.ActiveGame.Set(1, m.Server, gameCode, activeVersion)
+}
+
+// SetGamePool - set the pool stats of a game, it should be called in OnCollect, only lowcode.Game has pool stats
+func (m *GameMetrics) SetGamePool(gameCode string, version string, game sgc7game.IGame) {
+	lowcodeGame, isok := game.(*lowcode.Game)
+	if !isok || lowcodeGame.Pool == nil {
+		return
+	}
+
+	for _, stats := range lowcodeGame.Pool.GetStats() {
+		bet := formatFloat(float64(stats.Bet))
+
+		m.PoolCreated.Set(float64(stats.Created), m.Server, gameCode, version, bet)
+		m.PoolInUse.Set(float64(stats.InUse), m.Server, gameCode, version, bet)
+	}
+}
+
+// OnCollect - update the gauges before the metrics are written,
+//
+//	gauges 会先清空，所以 retire 掉的版本不会一直留着
+func (m *GameMetrics) OnCollect(onCollect FuncOnCollect) {
+	m.Registry.OnCollect(func() {
+		m.GameVersions.Reset()
+		m.ActiveGame.Reset()
+		m.PoolCreated.Reset()
+		m.PoolInUse.Reset()
+
+		onCollect()
+	})
+}
+
+// NewGameMetrics - new a GameMetrics with a new Registry
+func NewGameMetrics(server string) *GameMetrics {
+	m := &GameMetrics{
+		Server:       server,
+		Registry:     NewRegistry(),
+		Plays:        NewCounterVec("sgc7_plays_total", "Number of successful plays.", "server", "game", "command"),
+		PlayDuration: NewHistogramVec("sgc7_play_duration_seconds", "Latency of plays in seconds.", DefaultLatencyBuckets, "server", "game"),
+		Errors:       NewCounterVec("sgc7_errors_total", "Number of failed requests by error.", "server", "game", "error"),
+		RngCalls:     NewHistogramVec("sgc7_rng_calls", "Number of random numbers used by a play.", DefaultRngBuckets, "server", "game"),
+		Rounds:       NewCounterVec("sgc7_rounds_total", "Number of rounds started.", "server", "game", "currency"),
+		Bet:          NewCounterVec("sgc7_bet_total", "Total cash bet of rounds started.", "server", "game", "currency"),
+		Win:          NewCounterVec("sgc7_win_total", "Total cash win.", "server", "game", "currency"),
+		GameVersions: NewGaugeVec("sgc7_game_versions", "Number of loaded versions of a game.", "server", "game"),
+		ActiveGame:   NewGaugeVec("sgc7_game_active_version", "The active version of a game.", "server", "game", "version"),
+		PoolCreated:  NewGaugeVec("sgc7_gameprop_pool_created", "Number of GameProperty created by the pool.", "server", "game", "version", "bet"),
+		PoolInUse:    NewGaugeVec("sgc7_gameprop_pool_in_use", "Number of GameProperty in use.", "server", "game", "version", "bet"),
+	}
+
+	m.Registry.Register(m.Plays, m.PlayDuration, m.Errors, m.RngCalls, m.Rounds, m.Bet, m.Win,
+		m.GameVersions, m.ActiveGame, m.PoolCreated, m.PoolInUse)
+
+	m.SetCurrencies(DefaultCurrencies)
+
+	return m
+}
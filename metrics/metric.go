@@ -0,0 +1,404 @@
+package metrics
+
+import (
+	"io"
+	"log/slog"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	goutils "github.com/zhs007/goutils"
+)
+
+const (
+	typeCounter   = "counter"
+	typeGauge     = "gauge"
+	typeHistogram = "histogram"
+)
+
+// Metric - a metric family, it is written in the Prometheus text format
+type Metric interface {
+	// GetName - get name
+	GetName() string
+	// Write - write in the Prometheus text format
+	Write(w io.Writer) error
+}
+
+// labelsKey - label values -> map key
+func labelsKey(labelValues []string) string {
+	return strings.Join(labelValues, "\xff")
+}
+
+// escapeLabelValue - escape \, " and \n
+func escapeLabelValue(str string) string {
+	if !strings.ContainsAny(str, "\\\"\n") {
+		return str
+	}
+
+	str = strings.ReplaceAll(str, "\\", "\\\\")
+	str = strings.ReplaceAll(str, "\"", "\\\"")
+
+	return strings.ReplaceAll(str, "\n", "\\n")
+}
+
+// formatFloat - format a value in the Prometheus text format
+func formatFloat(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+
+	if math.IsInf(v, -1) {
+		return "-Inf"
+	}
+
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// buildLabels - {a="1",b="2"}, extName and extValue are appended if extName is not empty
+func buildLabels(labelNames []string, labelValues []string, extName string, extValue string) string {
+	if len(labelNames) == 0 && extName == "" {
+		return ""
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("{")
+
+	for i, name := range labelNames {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+
+		sb.WriteString(name)
+		sb.WriteString("=\"")
+		sb.WriteString(escapeLabelValue(labelValues[i]))
+		sb.WriteString("\"")
+	}
+
+	if extName != "" {
+		if len(labelNames) > 0 {
+			sb.WriteString(",")
+		}
+
+		sb.WriteString(extName)
+		sb.WriteString("=\"")
+		sb.WriteString(extValue)
+		sb.WriteString("\"")
+	}
+
+	sb.WriteString("}")
+
+	return sb.String()
+}
+
+// writeHeader - # HELP and # TYPE
+func writeHeader(w io.Writer, name string, help string, typ string) error {
+	_, err := io.WriteString(w, "# HELP "+name+" "+strings.ReplaceAll(help, "\n", " ")+"\n# TYPE "+name+" "+typ+"\n")
+
+	return err
+}
+
+type valueSample struct {
+	labelValues []string
+	val         float64
+}
+
+// valueVec - CounterVec 和 GaugeVec 共用的实现
+type valueVec struct {
+	name       string
+	help       string
+	typ        string
+	labelNames []string
+	lock       sync.Mutex
+	mapSamples map[string]*valueSample
+}
+
+func (vec *valueVec) getSample(labelValues []string) *valueSample {
+	if len(labelValues) != len(vec.labelNames) {
+		panic("metrics: invalid label values for " + vec.name)
+	}
+
+	key := labelsKey(labelValues)
+
+	sample, isok := vec.mapSamples[key]
+	if !isok {
+		sample = &valueSample{
+			labelValues: slices.Clone(labelValues),
+		}
+
+		vec.mapSamples[key] = sample
+	}
+
+	return sample
+}
+
+func (vec *valueVec) add(v float64, labelValues []string) {
+	vec.lock.Lock()
+	vec.getSample(labelValues).val += v
+	vec.lock.Unlock()
+}
+
+func (vec *valueVec) set(v float64, labelValues []string) {
+	vec.lock.Lock()
+	vec.getSample(labelValues).val = v
+	vec.lock.Unlock()
+}
+
+func (vec *valueVec) get(labelValues []string) float64 {
+	vec.lock.Lock()
+	defer vec.lock.Unlock()
+
+	sample, isok := vec.mapSamples[labelsKey(labelValues)]
+	if !isok {
+		return 0
+	}
+
+	return sample.val
+}
+
+func (vec *valueVec) reset() {
+	vec.lock.Lock()
+	vec.mapSamples = make(map[string]*valueSample)
+	vec.lock.Unlock()
+}
+
+// GetName - get name
+func (vec *valueVec) GetName() string {
+	return vec.name
+}
+
+// Write - write in the Prometheus text format
+func (vec *valueVec) Write(w io.Writer) error {
+	vec.lock.Lock()
+	defer vec.lock.Unlock()
+
+	err := writeHeader(w, vec.name, vec.help, vec.typ)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(vec.mapSamples))
+	for k := range vec.mapSamples {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		sample := vec.mapSamples[k]
+
+		_, err = io.WriteString(w, vec.name+buildLabels(vec.labelNames, sample.labelValues, "", "")+" "+formatFloat(sample.val)+"\n")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CounterVec - counters with labels
+type CounterVec struct {
+	valueVec
+}
+
+// Inc - add 1
+func (vec *CounterVec) Inc(labelValues ...string) {
+	vec.add(1, labelValues)
+}
+
+// Add - v must not be negative, the negative values are logged and dropped
+func (vec *CounterVec) Add(v float64, labelValues ...string) {
+	if v < 0 {
+		goutils.Warn("CounterVec.Add",
+			slog.String("name", vec.name),
+			slog.Any("labels", labelValues),
+			slog.Float64("value", v),
+			goutils.Err(ErrNegativeCounter))
+
+		return
+	}
+
+	vec.add(v, labelValues)
+}
+
+// Get - get value
+func (vec *CounterVec) Get(labelValues ...string) float64 {
+	return vec.get(labelValues)
+}
+
+// NewCounterVec - new a CounterVec
+func NewCounterVec(name string, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{
+		valueVec: valueVec{
+			name:       name,
+			help:       help,
+			typ:        typeCounter,
+			labelNames: labelNames,
+			mapSamples: make(map[string]*valueSample),
+		},
+	}
+}
+
+// GaugeVec - gauges with labels
+type GaugeVec struct {
+	valueVec
+}
+
+// Set - set value
+func (vec *GaugeVec) Set(v float64, labelValues ...string) {
+	vec.set(v, labelValues)
+}
+
+// Add - add value, v can be negative
+func (vec *GaugeVec) Add(v float64, labelValues ...string) {
+	vec.add(v, labelValues)
+}
+
+// Get - get value
+func (vec *GaugeVec) Get(labelValues ...string) float64 {
+	return vec.get(labelValues)
+}
+
+// Reset - remove all values
+func (vec *GaugeVec) Reset() {
+	vec.reset()
+}
+
+// NewGaugeVec - new a GaugeVec
+func NewGaugeVec(name string, help string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{
+		valueVec: valueVec{
+			name:       name,
+			help:       help,
+			typ:        typeGauge,
+			labelNames: labelNames,
+			mapSamples: make(map[string]*valueSample),
+		},
+	}
+}
+
+type histogramSample struct {
+	labelValues []string
+	buckets     []uint64 // 不是累加的，输出时再累加
+	sum         float64
+	count       uint64
+}
+
+// HistogramVec - histograms with labels
+type HistogramVec struct {
+	name       string
+	help       string
+	labelNames []string
+	buckets    []float64 // 升序，不含 +Inf
+	lock       sync.Mutex
+	mapSamples map[string]*histogramSample
+}
+
+// Observe - add a value
+func (vec *HistogramVec) Observe(v float64, labelValues ...string) {
+	if len(labelValues) != len(vec.labelNames) {
+		panic("metrics: invalid label values for " + vec.name)
+	}
+
+	i, _ := slices.BinarySearch(vec.buckets, v)
+
+	key := labelsKey(labelValues)
+
+	vec.lock.Lock()
+	defer vec.lock.Unlock()
+
+	sample, isok := vec.mapSamples[key]
+	if !isok {
+		sample = &histogramSample{
+			labelValues: slices.Clone(labelValues),
+			buckets:     make([]uint64, len(vec.buckets)+1),
+		}
+
+		vec.mapSamples[key] = sample
+	}
+
+	sample.buckets[i]++
+	sample.sum += v
+	sample.count++
+}
+
+// GetCount - get the number of values
+func (vec *HistogramVec) GetCount(labelValues ...string) uint64 {
+	vec.lock.Lock()
+	defer vec.lock.Unlock()
+
+	sample, isok := vec.mapSamples[labelsKey(labelValues)]
+	if !isok {
+		return 0
+	}
+
+	return sample.count
+}
+
+// GetName - get name
+func (vec *HistogramVec) GetName() string {
+	return vec.name
+}
+
+// Write - write in the Prometheus text format
+func (vec *HistogramVec) Write(w io.Writer) error {
+	vec.lock.Lock()
+	defer vec.lock.Unlock()
+
+	err := writeHeader(w, vec.name, vec.help, typeHistogram)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(vec.mapSamples))
+	for k := range vec.mapSamples {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+
+	for _, k := range keys {
+		sample := vec.mapSamples[k]
+
+		var sb strings.Builder
+		cumulative := uint64(0)
+
+		for i, n := range sample.buckets {
+			cumulative += n
+
+			le := "+Inf"
+			if i < len(vec.buckets) {
+				le = formatFloat(vec.buckets[i])
+			}
+
+			sb.WriteString(vec.name + "_bucket" + buildLabels(vec.labelNames, sample.labelValues, "le", le) + " " + strconv.FormatUint(cumulative, 10) + "\n")
+		}
+
+		labels := buildLabels(vec.labelNames, sample.labelValues, "", "")
+		sb.WriteString(vec.name + "_sum" + labels + " " + formatFloat(sample.sum) + "\n")
+		sb.WriteString(vec.name + "_count" + labels + " " + strconv.FormatUint(sample.count, 10) + "\n")
+
+		_, err = io.WriteString(w, sb.String())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NewHistogramVec - new a HistogramVec, buckets is the upper bounds, +Inf is added automatically
+func NewHistogramVec(name string, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	nbuckets := slices.Clone(buckets)
+	slices.Sort(nbuckets)
+
+	return &HistogramVec{
+		name:       name,
+		help:       help,
+		labelNames: labelNames,
+		buckets:    nbuckets,
+		mapSamples: make(map[string]*histogramSample),
+	}
+}
@@ -0,0 +1,186 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/zhs007/slotsgamecore7/cheatpolicy"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
+)
+
+func Test_Registry(t *testing.T) {
+	reg := NewRegistry()
+
+	counter := NewCounterVec("test_total", "Test counter.", "a")
+	gauge := NewGaugeVec("test_gauge", "Test gauge.")
+	histogram := NewHistogramVec("test_seconds", "Test histogram.", []float64{1, 0.1}, "a")
+
+	reg.Register(counter, gauge, histogram)
+
+	collected := 0
+	reg.OnCollect(func() {
+		collected++
+		gauge.Set(float64(collected))
+	})
+
+	counter.Inc("x\"y")
+	counter.Add(2, "x\"y")
+	counter.Add(-1, "x\"y")
+	counter.Inc("b")
+	assert.Equal(t, 3.0, counter.Get("x\"y"))
+
+	histogram.Observe(0.05, "1")
+	histogram.Observe(0.5, "1")
+	histogram.Observe(5, "1")
+	assert.Equal(t, uint64(3), histogram.GetCount("1"))
+	assert.Equal(t, uint64(0), histogram.GetCount("2"))
+
+	assert.Equal(t, `# HELP test_total Test counter.
+# TYPE test_total counter
+test_total{a="b"} 1
+test_total{a="x\"y"} 3
+# HELP test_gauge Test gauge.
+# TYPE test_gauge gauge
+test_gauge 1
+# HELP test_seconds Test histogram.
+# TYPE test_seconds histogram
+test_seconds_bucket{a="1",le="0.1"} 1
+test_seconds_bucket{a="1",le="1"} 2
+test_seconds_bucket{a="1",le="+Inf"} 3
+test_seconds_sum{a="1"} 5.55
+test_seconds_count{a="1"} 3
+`, reg.String())
+
+	assert.Panics(t, func() {
+		counter.Inc()
+	})
+
+	t.Logf("Test_Registry OK")
+}
+
+func Test_GameMetrics(t *testing.T) {
+	var nilMetrics *GameMetrics
+
+	nilMetrics.OnPlayPB("game1", &sgc7pb.RequestPlay{}, nil, nil, time.Second)
+	nilMetrics.OnError("game1", errors.New("test"))
+
+	m := NewGameMetrics("test")
+
+	req := &sgc7pb.RequestPlay{
+		Stake: &sgc7pb.Stake{
+			CoinBet:  1,
+			CashBet:  20,
+			Currency: "EUR",
+		},
+	}
+
+	reply := &sgc7pb.ReplyPlay{
+		RandomNumbers: []*sgc7pb.RngInfo{{}, {}, {}},
+		Results:       []*sgc7pb.GameResult{{CashWin: 10}, {CashWin: 5}},
+	}
+
+	m.OnPlayPB("game1", req, reply, nil, time.Millisecond)
+
+	req.Command = "RESPIN"
+	m.OnPlayPB("game1", req, reply, nil, time.Millisecond)
+	m.OnPlayPB("game1", req, nil, errors.New("test error"), time.Millisecond)
+
+	assert.Equal(t, 1.0, m.Plays.Get("test", "game1", "SPIN"))
+	assert.Equal(t, 1.0, m.Plays.Get("test", "game1", "RESPIN"))
+	assert.Equal(t, 1.0, m.Errors.Get("test", "game1", ErrorReasonOther))
+	assert.Equal(t, 1.0, m.Rounds.Get("test", "game1", "EUR"))
+	assert.Equal(t, 20.0, m.Bet.Get("test", "game1", "EUR"))
+	assert.Equal(t, 30.0, m.Win.Get("test", "game1", "EUR"))
+	assert.Equal(t, uint64(3), m.PlayDuration.GetCount("test", "game1"))
+	assert.Equal(t, uint64(2), m.RngCalls.GetCount("test", "game1"))
+
+	// the currencies from the client are not the labels
+	req.Command = ""
+	req.Stake.Currency = "abc123"
+	m.OnPlayPB("game1", req, reply, nil, time.Millisecond)
+
+	req.Stake.Currency = "xyz456"
+	m.OnPlayPB("game1", req, reply, nil, time.Millisecond)
+
+	assert.Equal(t, 2.0, m.Rounds.Get("test", "game1", UnknownCurrency))
+	assert.Equal(t, 40.0, m.Bet.Get("test", "game1", UnknownCurrency))
+	assert.Equal(t, 0.0, m.Rounds.Get("test", "game1", "abc123"))
+
+	// EUR is not in the set now
+	m.SetCurrencies([]string{"xyz456"})
+	m.OnPlayPB("game1", req, reply, nil, time.Millisecond)
+
+	req.Stake.Currency = "EUR"
+	m.OnPlayPB("game1", req, reply, nil, time.Millisecond)
+
+	assert.Equal(t, 1.0, m.Rounds.Get("test", "game1", "xyz456"))
+	assert.Equal(t, 3.0, m.Rounds.Get("test", "game1", UnknownCurrency))
+	assert.Equal(t, 1.0, m.Rounds.Get("test", "game1", "EUR"))
+
+	versions := 2
+	m.OnCollect(func() {
+		m.SetGameVersions("game1", versions, "v1")
+	})
+
+	str := m.Registry.String()
+	assert.Contains(t, str, `sgc7_game_versions{server="test",game="game1"} 2`)
+	assert.Contains(t, str, `sgc7_game_active_version{server="test",game="game1",version="v1"} 1`)
+	assert.Contains(t, str, `sgc7_win_total{server="test",game="game1",currency="EUR"} 30`)
+
+	// gauges 每次都会重新收集
+	versions = 1
+	str = m.Registry.String()
+	assert.Contains(t, str, `sgc7_game_versions{server="test",game="game1"} 1`)
+
+	t.Logf("Test_GameMetrics OK")
+}
+
+func Test_Serv(t *testing.T) {
+	m := NewGameMetrics("test")
+	m.OnError("game1", errors.New("test error"))
+
+	serv := NewServ("127.0.0.1:0", m.Registry)
+
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Init(&fasthttp.Request{}, &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 1234}, nil)
+	ctx.Request.SetRequestURI(MetricsURL)
+
+	serv.HandleFastHTTP(ctx)
+
+	assert.Equal(t, fasthttp.StatusOK, ctx.Response.StatusCode())
+	assert.Equal(t, ContentType, string(ctx.Response.Header.ContentType()))
+	assert.True(t, strings.Contains(string(ctx.Response.Body()), `sgc7_errors_total{server="test",game="game1",error="other"} 1`))
+
+	ctx.Request.Header.SetMethod(fasthttp.MethodPost)
+	serv.HandleFastHTTP(ctx)
+	assert.Equal(t, fasthttp.StatusMethodNotAllowed, ctx.Response.StatusCode())
+
+	t.Logf("Test_Serv OK")
+}
+
+func Test_GetErrorReason(t *testing.T) {
+	assert.Equal(t, ErrorReasonCheat, GetErrorReason(cheatpolicy.ErrCallerNotAllowed))
+	assert.Equal(t, "invalid_stake", GetErrorReason(sgc7game.ErrInvalidStake))
+	assert.Equal(t, "timeout", GetErrorReason(fmt.Errorf("play: %w", context.DeadlineExceeded)))
+	assert.Equal(t, ErrorReasonOther, GetErrorReason(errors.New("invalid gameCode abc")))
+
+	errTest := errors.New("test")
+	AddErrorReason(errTest, "test")
+	assert.Equal(t, "test", GetErrorReason(errTest))
+
+	// 负数不会让 counter 变小
+	m := NewGameMetrics("test")
+	m.Win.Add(10, "test", "game1", "EUR")
+	m.Win.Add(-5, "test", "game1", "EUR")
+	assert.Equal(t, 10.0, m.Win.Get("test", "game1", "EUR"))
+
+	t.Logf("Test_GetErrorReason OK")
+}
@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"io"
+	"strings"
+	"sync"
+)
+
+// FuncOnCollect - called before the metrics are written, it is used to update the gauges
+type FuncOnCollect func()
+
+// Registry - a set of metrics
+type Registry struct {
+	lock       sync.Mutex
+	metrics    []Metric
+	lstCollect []FuncOnCollect
+}
+
+// Register - register a metric, the metrics are written in this order
+func (reg *Registry) Register(metrics ...Metric) {
+	reg.lock.Lock()
+	reg.metrics = append(reg.metrics, metrics...)
+	reg.lock.Unlock()
+}
+
+// OnCollect - register a FuncOnCollect
+func (reg *Registry) OnCollect(onCollect FuncOnCollect) {
+	reg.lock.Lock()
+	reg.lstCollect = append(reg.lstCollect, onCollect)
+	reg.lock.Unlock()
+}
+
+// Write - write all metrics in the Prometheus text format
+func (reg *Registry) Write(w io.Writer) error {
+	reg.lock.Lock()
+	metrics := reg.metrics
+	lstCollect := reg.lstCollect
+	reg.lock.Unlock()
+
+	for _, onCollect := range lstCollect {
+		onCollect()
+	}
+
+	for _, m := range metrics {
+		err := m.Write(w)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// String - all metrics in the Prometheus text format
+func (reg *Registry) String() string {
+	var sb strings.Builder
+
+	reg.Write(&sb)
+
+	return sb.String()
+}
+
+// NewRegistry - new a Registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
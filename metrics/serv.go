@@ -0,0 +1,55 @@
+package metrics
+
+import (
+	"bytes"
+
+	"github.com/valyala/fasthttp"
+	goutils "github.com/zhs007/goutils"
+	sgc7http "github.com/zhs007/slotsgamecore7/http"
+)
+
+// MetricsURL - the url of metrics
+const MetricsURL = "/metrics"
+
+// ContentType - the Prometheus text format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Serv - a http server for metrics
+type Serv struct {
+	*sgc7http.Serv
+	Registry *Registry
+}
+
+// NewServ - new a Serv, bindAddr should be a local address, like 127.0.0.1:9100
+func NewServ(bindAddr string, reg *Registry) *Serv {
+	s := &Serv{
+		Serv:     sgc7http.NewServ(bindAddr, false),
+		Registry: reg,
+	}
+
+	s.RegHandle(MetricsURL,
+		func(ctx *fasthttp.RequestCtx, serv *sgc7http.Serv) {
+			if !ctx.Request.Header.IsGet() {
+				s.SetHTTPStatus(ctx, fasthttp.StatusMethodNotAllowed)
+
+				return
+			}
+
+			buf := &bytes.Buffer{}
+			err := s.Registry.Write(buf)
+			if err != nil {
+				goutils.Warn("metrics.Serv.metrics:Write",
+					goutils.Err(err))
+
+				s.SetHTTPStatus(ctx, fasthttp.StatusInternalServerError)
+
+				return
+			}
+
+			ctx.SetContentType(ContentType)
+			ctx.SetStatusCode(fasthttp.StatusOK)
+			ctx.SetBody(buf.Bytes())
+		})
+
+	return s
+}
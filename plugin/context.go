@@ -0,0 +1,54 @@
+package sgc7plugin
+
+import "context"
+
+// contextPlugin - IPlugin with a context,
+//
+//	老的代码里都是 Random(context.Background(), r)，这时用绑定的 ctx，这样远程的 RNG 也能被取消、带上 trace
+type contextPlugin struct {
+	IPlugin
+	ctx context.Context
+}
+
+// Random - return [0, r)
+func (plugin *contextPlugin) Random(ctx context.Context, r int) (int, error) {
+	if isEmptyContext(ctx) {
+		ctx = plugin.ctx
+	}
+
+	return plugin.IPlugin.Random(ctx, r)
+}
+
+func isEmptyContext(ctx context.Context) bool {
+	return ctx == nil || ctx == context.Background() || ctx == context.TODO()
+}
+
+// WithContext - bind ctx to plugin, free the original plugin, not the returned one
+func WithContext(plugin IPlugin, ctx context.Context) IPlugin {
+	if isEmptyContext(ctx) {
+		return plugin
+	}
+
+	cp, isok := plugin.(*contextPlugin)
+	if isok {
+		return &contextPlugin{
+			IPlugin: cp.IPlugin,
+			ctx:     ctx,
+		}
+	}
+
+	return &contextPlugin{
+		IPlugin: plugin,
+		ctx:     ctx,
+	}
+}
+
+// GetContext - get the context bound by WithContext, it is context.Background() if there is no context
+func GetContext(plugin IPlugin) context.Context {
+	cp, isok := plugin.(*contextPlugin)
+	if isok {
+		return cp.ctx
+	}
+
+	return context.Background()
+}
@@ -0,0 +1,57 @@
+package sgc7plugin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type ctxKey struct{}
+
+// ctxPlugin - remember the context of the last Random
+type ctxPlugin struct {
+	*MockPlugin
+	lastCtx context.Context
+}
+
+func (plugin *ctxPlugin) Random(ctx context.Context, r int) (int, error) {
+	plugin.lastCtx = ctx
+
+	return plugin.MockPlugin.Random(ctx, r)
+}
+
+func Test_WithContext(t *testing.T) {
+	base := &ctxPlugin{MockPlugin: NewMockPlugin()}
+
+	assert.Equal(t, base, WithContext(base, context.Background()))
+	assert.Equal(t, context.Background(), GetContext(base))
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, 1)
+	plugin := WithContext(base, ctx)
+	assert.Equal(t, ctx, GetContext(plugin))
+
+	// context.Background() 用绑定的 ctx
+	_, err := plugin.Random(context.Background(), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, base.lastCtx.Value(ctxKey{}))
+
+	ctx2 := context.WithValue(context.Background(), ctxKey{}, 2)
+	_, err = plugin.Random(ctx2, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, base.lastCtx.Value(ctxKey{}))
+
+	// 不会套两层
+	plugin2 := WithContext(plugin, ctx2)
+	assert.Equal(t, ctx2, GetContext(plugin2))
+	assert.Equal(t, IPlugin(base), plugin2.(*contextPlugin).IPlugin)
+
+	_, err = plugin2.Random(context.Background(), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, base.lastCtx.Value(ctxKey{}))
+
+	// 其它接口用原来的 plugin
+	assert.Equal(t, 3, len(plugin.GetUsedRngs()))
+
+	t.Logf("Test_WithContext OK")
+}
@@ -0,0 +1,142 @@
+package reelsconv
+
+import (
+	"log/slog"
+
+	"github.com/zhs007/goutils"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+)
+
+// hasNames - reels5json and reels3json have no reel set name
+func hasNames(format Format) bool {
+	return format != FormatReels5JSON && format != FormatReels3JSON
+}
+
+// Load - load reel sets, pt is required for the int code formats (reels5json, reels3json and excel),
+// and is used to check symbols for the other formats if it is not nil
+func Load(fn string, format Format, pt *sgc7game.PayTables) (*ReelSets, error) {
+	switch format {
+	case FormatReels5JSON, FormatReels3JSON:
+		return loadReelsJSON(fn, format, pt)
+	case FormatExcel, FormatExcelSymbols:
+		return loadExcel(fn, format, pt)
+	case FormatRelaxXML:
+		return loadRelaxXML(fn, pt)
+	case FormatLowcodeJSON:
+		return loadLowcodeJSON(fn, pt)
+	}
+
+	goutils.Error("Load",
+		slog.String("fn", fn),
+		slog.String("format", string(format)),
+		goutils.Err(ErrInvalidFormat))
+
+	return nil, ErrInvalidFormat
+}
+
+// Save - save reel sets, pt is required for the int code formats (reels5json, reels3json and excel)
+func Save(fn string, format Format, rss *ReelSets, pt *sgc7game.PayTables) error {
+	if rss == nil || len(rss.Sets) <= 0 {
+		goutils.Error("Save",
+			slog.String("fn", fn),
+			goutils.Err(ErrNoReelSets))
+
+		return ErrNoReelSets
+	}
+
+	for _, rs := range rss.Sets {
+		err := checkWeights(rs)
+		if err != nil {
+			goutils.Error("Save:checkWeights",
+				slog.String("fn", fn),
+				goutils.Err(err))
+
+			return err
+		}
+	}
+
+	switch format {
+	case FormatReels5JSON, FormatReels3JSON:
+		return saveReelsJSON(fn, format, rss, pt)
+	case FormatExcel, FormatExcelSymbols:
+		return saveExcel(fn, format, rss, pt)
+	case FormatRelaxXML:
+		return saveRelaxXML(fn, rss)
+	case FormatLowcodeJSON:
+		return saveLowcodeJSON(fn, rss)
+	}
+
+	goutils.Error("Save",
+		slog.String("fn", fn),
+		slog.String("format", string(format)),
+		goutils.Err(ErrInvalidFormat))
+
+	return ErrInvalidFormat
+}
+
+// Verify - reload fn and compare with rss
+func Verify(fn string, format Format, rss *ReelSets, pt *sgc7game.PayTables) error {
+	dest, err := Load(fn, format, pt)
+	if err != nil {
+		goutils.Error("Verify:Load",
+			slog.String("fn", fn),
+			slog.String("format", string(format)),
+			goutils.Err(err))
+
+		return err
+	}
+
+	// 没有名字的格式用原来的名字
+	if !hasNames(format) && len(dest.Sets) == len(rss.Sets) {
+		for i, rs := range dest.Sets {
+			rs.Name = rss.Sets[i].Name
+		}
+	}
+
+	err = Compare(rss, dest)
+	if err != nil {
+		goutils.Error("Verify:Compare",
+			slog.String("fn", fn),
+			slog.String("format", string(format)),
+			goutils.Err(err))
+
+		return err
+	}
+
+	return nil
+}
+
+// Convert - load srcfn, save as destfn, then reload destfn and check round-trip equality
+func Convert(srcfn string, srcFormat Format, destfn string, destFormat Format, pt *sgc7game.PayTables) (*ReelSets, error) {
+	rss, err := Load(srcfn, srcFormat, pt)
+	if err != nil {
+		goutils.Error("Convert:Load",
+			slog.String("fn", srcfn),
+			slog.String("format", string(srcFormat)),
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	err = Save(destfn, destFormat, rss, pt)
+	if err != nil {
+		goutils.Error("Convert:Save",
+			slog.String("fn", destfn),
+			slog.String("format", string(destFormat)),
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	err = Verify(destfn, destFormat, rss, pt)
+	if err != nil {
+		goutils.Error("Convert:Verify",
+			slog.String("fn", destfn),
+			slog.String("format", string(destFormat)),
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	return rss, nil
+}
@@ -0,0 +1,30 @@
+package reelsconv
+
+import "errors"
+
+var (
+	// ErrInvalidFormat - invalid format
+	ErrInvalidFormat = errors.New("invalid format")
+	// ErrInvalidPaytables - invalid paytables
+	ErrInvalidPaytables = errors.New("invalid paytables")
+	// ErrInvalidSymbol - invalid symbol
+	ErrInvalidSymbol = errors.New("invalid symbol")
+	// ErrInvalidWeight - invalid weight
+	ErrInvalidWeight = errors.New("invalid weight")
+	// ErrInvalidReels - invalid reels
+	ErrInvalidReels = errors.New("invalid reels")
+	// ErrInvalidWidth - invalid width
+	ErrInvalidWidth = errors.New("invalid width")
+	// ErrInvalidExcelFile - invalid excel file
+	ErrInvalidExcelFile = errors.New("invalid excel file")
+	// ErrNoReelSets - no reel sets
+	ErrNoReelSets = errors.New("no reel sets")
+	// ErrDuplicateName - duplicate reel set name
+	ErrDuplicateName = errors.New("duplicate reel set name")
+	// ErrMultiSetsNotSupported - this format can only hold one reel set
+	ErrMultiSetsNotSupported = errors.New("this format can only hold one reel set")
+	// ErrWeightsNotSupported - this format can not hold stop weights
+	ErrWeightsNotSupported = errors.New("this format can not hold stop weights")
+	// ErrRoundTripMismatch - round-trip mismatch
+	ErrRoundTripMismatch = errors.New("round-trip mismatch")
+)
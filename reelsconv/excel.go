@@ -0,0 +1,387 @@
+package reelsconv
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+	"github.com/zhs007/goutils"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+)
+
+// excelColumn - a reel or weight column in the header
+type excelColumn struct {
+	reel     int
+	isWeight bool
+}
+
+// parseExcelHeader - line, R1..Rn, W1..Wn, the other columns are ignored
+func parseExcelHeader(row []string) (map[int]*excelColumn, int, error) {
+	mapcol := make(map[int]*excelColumn)
+	maxri := 0
+	nums := 0
+
+	for x, cell := range row {
+		header := strings.ToLower(strings.TrimSpace(cell))
+		if len(header) < 2 || (header[0] != 'r' && header[0] != 'w') {
+			continue
+		}
+
+		iv, err := goutils.String2Int64(header[1:])
+		if err != nil || iv <= 0 {
+			continue
+		}
+
+		col := &excelColumn{
+			reel:     int(iv) - 1,
+			isWeight: header[0] == 'w',
+		}
+
+		mapcol[x] = col
+
+		if !col.isWeight {
+			nums++
+
+			if int(iv) > maxri {
+				maxri = int(iv)
+			}
+		}
+	}
+
+	if maxri <= 0 || maxri != nums {
+		goutils.Error("parseExcelHeader",
+			slog.Any("header", row),
+			slog.Int("maxri", maxri),
+			slog.Int("nums", nums),
+			goutils.Err(ErrInvalidExcelFile))
+
+		return nil, 0, ErrInvalidExcelFile
+	}
+
+	for _, col := range mapcol {
+		if col.isWeight && col.reel >= maxri {
+			goutils.Error("parseExcelHeader",
+				slog.Any("header", row),
+				slog.Int("weight", col.reel+1),
+				goutils.Err(ErrInvalidExcelFile))
+
+			return nil, 0, ErrInvalidExcelFile
+		}
+	}
+
+	return mapcol, maxri, nil
+}
+
+// loadExcelSheet - load a reel set from a sheet
+func loadExcelSheet(f *excelize.File, sheet string, format Format, pt *sgc7game.PayTables) (*ReelSet, error) {
+	rows, err := f.GetRows(sheet)
+	if err != nil {
+		goutils.Error("loadExcelSheet:GetRows",
+			slog.String("sheet", sheet),
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	if len(rows) <= 0 {
+		goutils.Error("loadExcelSheet",
+			slog.String("sheet", sheet),
+			goutils.Err(ErrInvalidExcelFile))
+
+		return nil, ErrInvalidExcelFile
+	}
+
+	mapcol, width, err := parseExcelHeader(rows[0])
+	if err != nil {
+		goutils.Error("loadExcelSheet:parseExcelHeader",
+			slog.String("sheet", sheet),
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	mapCode := make(map[int]string)
+	if pt != nil {
+		for k, v := range pt.MapSymbols {
+			mapCode[v] = k
+		}
+	}
+
+	rs := &ReelSet{
+		Name: sheet,
+	}
+
+	isend := make([]bool, width)
+	weights := make([][]int, width)
+	for x := 0; x < width; x++ {
+		rs.Reels = append(rs.Reels, &Reel{})
+	}
+
+	for y := 1; y < len(rows); y++ {
+		for x, cell := range rows[y] {
+			col, isok := mapcol[x]
+			if !isok || col.isWeight {
+				continue
+			}
+
+			cell = strings.TrimSpace(cell)
+			if cell == "" {
+				isend[col.reel] = true
+
+				continue
+			}
+
+			str := cell
+
+			if format == FormatExcel {
+				v, err := goutils.String2Int64(cell)
+				if err != nil {
+					goutils.Error("loadExcelSheet:String2Int64",
+						slog.String("sheet", sheet),
+						slog.Int("x", x),
+						slog.Int("y", y),
+						slog.String("val", cell),
+						goutils.Err(err))
+
+					return nil, err
+				}
+
+				if v < 0 {
+					isend[col.reel] = true
+
+					continue
+				}
+
+				str, isok = mapCode[int(v)]
+				if !isok {
+					goutils.Error("loadExcelSheet",
+						slog.String("sheet", sheet),
+						slog.Int("x", x),
+						slog.Int("y", y),
+						slog.Int64("symbol", v),
+						goutils.Err(ErrInvalidSymbol))
+
+					return nil, ErrInvalidSymbol
+				}
+			}
+
+			if isend[col.reel] {
+				goutils.Error("loadExcelSheet",
+					slog.String("info", "check already finished."),
+					slog.String("sheet", sheet),
+					slog.Int("x", x),
+					slog.Int("y", y),
+					goutils.Err(ErrInvalidExcelFile))
+
+				return nil, ErrInvalidExcelFile
+			}
+
+			reel := rs.Reels[col.reel]
+			reel.Symbols = append(reel.Symbols, str)
+		}
+
+		for x, cell := range rows[y] {
+			col, isok := mapcol[x]
+			if !isok || !col.isWeight {
+				continue
+			}
+
+			cell = strings.TrimSpace(cell)
+			if cell == "" {
+				continue
+			}
+
+			v, err := goutils.String2Int64(cell)
+			if err != nil {
+				goutils.Error("loadExcelSheet:String2Int64",
+					slog.String("sheet", sheet),
+					slog.Int("x", x),
+					slog.Int("y", y),
+					slog.String("weight", cell),
+					goutils.Err(err))
+
+				return nil, err
+			}
+
+			weights[col.reel] = append(weights[col.reel], int(v))
+		}
+	}
+
+	for x, reel := range rs.Reels {
+		if len(weights[x]) > 0 {
+			reel.Weights = weights[x]
+		}
+	}
+
+	err = checkWeights(rs)
+	if err != nil {
+		goutils.Error("loadExcelSheet:checkWeights",
+			slog.String("sheet", sheet),
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	if format != FormatExcel {
+		err = checkSymbols(rs, pt)
+		if err != nil {
+			goutils.Error("loadExcelSheet:checkSymbols",
+				slog.String("sheet", sheet),
+				goutils.Err(err))
+
+			return nil, err
+		}
+	}
+
+	normalizeWeights(rs)
+
+	return rs, nil
+}
+
+// loadExcel - load excel or excelsymbols, every sheet is a reel set and the sheet name is the reel set name
+func loadExcel(fn string, format Format, pt *sgc7game.PayTables) (*ReelSets, error) {
+	if format == FormatExcel && pt == nil {
+		goutils.Error("loadExcel",
+			slog.String("fn", fn),
+			goutils.Err(ErrInvalidPaytables))
+
+		return nil, ErrInvalidPaytables
+	}
+
+	f, err := excelize.OpenFile(fn)
+	if err != nil {
+		goutils.Error("loadExcel:OpenFile",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return nil, err
+	}
+	defer f.Close()
+
+	rss := &ReelSets{}
+
+	for _, sheet := range f.GetSheetList() {
+		rs, err := loadExcelSheet(f, sheet, format, pt)
+		if err != nil {
+			goutils.Error("loadExcel:loadExcelSheet",
+				slog.String("fn", fn),
+				slog.String("sheet", sheet),
+				goutils.Err(err))
+
+			return nil, err
+		}
+
+		rss.Sets = append(rss.Sets, rs)
+	}
+
+	if len(rss.Sets) <= 0 {
+		goutils.Error("loadExcel",
+			slog.String("fn", fn),
+			goutils.Err(ErrNoReelSets))
+
+		return nil, ErrNoReelSets
+	}
+
+	return rss, nil
+}
+
+// saveExcelSheet - line, R1..Rn, and W1..Wn if the reel set is weighted, W1..Wn is a reelsconv extension and LoadReelsFromExcel ignores it
+func saveExcelSheet(f *excelize.File, sheet string, rs *ReelSet, format Format, pt *sgc7game.PayTables) error {
+	var rd *sgc7game.ReelsData
+
+	if format == FormatExcel {
+		crd, err := rs.ToReelsData(pt)
+		if err != nil {
+			goutils.Error("saveExcelSheet:ToReelsData",
+				slog.String("sheet", sheet),
+				goutils.Err(err))
+
+			return err
+		}
+
+		rd = crd
+	}
+
+	isWeighted := rs.IsWeighted()
+	width := len(rs.Reels)
+
+	f.SetCellStr(sheet, goutils.Pos2Cell(0, 0), "line")
+	for i := range rs.Reels {
+		f.SetCellStr(sheet, goutils.Pos2Cell(i+1, 0), fmt.Sprintf("R%v", i+1))
+
+		if isWeighted {
+			f.SetCellStr(sheet, goutils.Pos2Cell(width+i+1, 0), fmt.Sprintf("W%v", i+1))
+		}
+	}
+
+	maxj := 0
+
+	for i, reel := range rs.Reels {
+		if maxj < len(reel.Symbols) {
+			maxj = len(reel.Symbols)
+		}
+
+		for j, s := range reel.Symbols {
+			if rd != nil {
+				f.SetCellValue(sheet, goutils.Pos2Cell(i+1, j+1), rd.Reels[i][j])
+			} else {
+				f.SetCellStr(sheet, goutils.Pos2Cell(i+1, j+1), s)
+			}
+
+			if isWeighted {
+				f.SetCellValue(sheet, goutils.Pos2Cell(width+i+1, j+1), reel.GetWeight(j))
+			}
+		}
+	}
+
+	for i := 0; i < maxj; i++ {
+		f.SetCellValue(sheet, goutils.Pos2Cell(0, i+1), i)
+	}
+
+	return nil
+}
+
+// saveExcel - save excel or excelsymbols, one sheet per reel set
+func saveExcel(fn string, format Format, rss *ReelSets, pt *sgc7game.PayTables) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	for i, rs := range rss.Sets {
+		sheet := rs.Name
+
+		if i == 0 {
+			err := f.SetSheetName(f.GetSheetName(0), sheet)
+			if err != nil {
+				goutils.Error("saveExcel:SetSheetName",
+					slog.String("fn", fn),
+					slog.String("sheet", sheet),
+					goutils.Err(err))
+
+				return err
+			}
+		} else {
+			_, err := f.NewSheet(sheet)
+			if err != nil {
+				goutils.Error("saveExcel:NewSheet",
+					slog.String("fn", fn),
+					slog.String("sheet", sheet),
+					goutils.Err(err))
+
+				return err
+			}
+		}
+
+		err := saveExcelSheet(f, sheet, rs, format, pt)
+		if err != nil {
+			goutils.Error("saveExcel:saveExcelSheet",
+				slog.String("fn", fn),
+				slog.String("sheet", sheet),
+				goutils.Err(err))
+
+			return err
+		}
+	}
+
+	return f.SaveAs(fn)
+}
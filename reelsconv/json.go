@@ -0,0 +1,174 @@
+package reelsconv
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/bytedance/sonic"
+	"github.com/zhs007/goutils"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+)
+
+type reels5Row struct {
+	R1   int `json:"R1"`
+	R2   int `json:"R2"`
+	R3   int `json:"R3"`
+	R4   int `json:"R4"`
+	R5   int `json:"R5"`
+	Line int `json:"line"`
+}
+
+type reels3Row struct {
+	R1   int `json:"R1"`
+	R2   int `json:"R2"`
+	R3   int `json:"R3"`
+	Line int `json:"line"`
+}
+
+func getWidth(format Format) int {
+	if format == FormatReels3JSON {
+		return 3
+	}
+
+	return 5
+}
+
+// loadReelsJSON - load reels5json or reels3json, the name of reel set is the filename
+func loadReelsJSON(fn string, format Format, pt *sgc7game.PayTables) (*ReelSets, error) {
+	var rd *sgc7game.ReelsData
+	var err error
+
+	if format == FormatReels3JSON {
+		rd, err = sgc7game.LoadReels3JSON(fn)
+	} else {
+		rd, err = sgc7game.LoadReels5JSON(fn)
+	}
+
+	if err != nil {
+		goutils.Error("loadReelsJSON:LoadReelsJSON",
+			slog.String("fn", fn),
+			slog.String("format", string(format)),
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	if rd == nil {
+		goutils.Error("loadReelsJSON",
+			slog.String("fn", fn),
+			slog.String("format", string(format)),
+			goutils.Err(ErrInvalidReels))
+
+		return nil, ErrInvalidReels
+	}
+
+	rs, err := NewReelSetFromReelsData(nameFromFile(fn), rd, pt)
+	if err != nil {
+		goutils.Error("loadReelsJSON:NewReelSetFromReelsData",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	return &ReelSets{Sets: []*ReelSet{rs}}, nil
+}
+
+// saveReelsJSON - save reels5json or reels3json, they can only hold one unweighted reel set
+func saveReelsJSON(fn string, format Format, rss *ReelSets, pt *sgc7game.PayTables) error {
+	if len(rss.Sets) != 1 {
+		goutils.Error("saveReelsJSON",
+			slog.String("fn", fn),
+			slog.Int("sets", len(rss.Sets)),
+			goutils.Err(ErrMultiSetsNotSupported))
+
+		return ErrMultiSetsNotSupported
+	}
+
+	rs := rss.Sets[0]
+	if rs.IsWeighted() {
+		goutils.Error("saveReelsJSON",
+			slog.String("fn", fn),
+			slog.String("name", rs.Name),
+			goutils.Err(ErrWeightsNotSupported))
+
+		return ErrWeightsNotSupported
+	}
+
+	w := getWidth(format)
+	if len(rs.Reels) != w {
+		goutils.Error("saveReelsJSON",
+			slog.String("fn", fn),
+			slog.String("format", string(format)),
+			slog.Int("width", len(rs.Reels)),
+			goutils.Err(ErrInvalidWidth))
+
+		return ErrInvalidWidth
+	}
+
+	rd, err := rs.ToReelsData(pt)
+	if err != nil {
+		goutils.Error("saveReelsJSON:ToReelsData",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return err
+	}
+
+	maxlen := 0
+	for _, arr := range rd.Reels {
+		if len(arr) > maxlen {
+			maxlen = len(arr)
+		}
+	}
+
+	// 不足的位置填 -1，读取时会被忽略
+	getCode := func(x, y int) int {
+		if y < len(rd.Reels[x]) {
+			return rd.Reels[x][y]
+		}
+
+		return -1
+	}
+
+	var data any
+
+	if w == 3 {
+		rows := make([]*reels3Row, 0, maxlen)
+		for y := 0; y < maxlen; y++ {
+			rows = append(rows, &reels3Row{
+				R1:   getCode(0, y),
+				R2:   getCode(1, y),
+				R3:   getCode(2, y),
+				Line: y,
+			})
+		}
+
+		data = rows
+	} else {
+		rows := make([]*reels5Row, 0, maxlen)
+		for y := 0; y < maxlen; y++ {
+			rows = append(rows, &reels5Row{
+				R1:   getCode(0, y),
+				R2:   getCode(1, y),
+				R3:   getCode(2, y),
+				R4:   getCode(3, y),
+				R5:   getCode(4, y),
+				Line: y,
+			})
+		}
+
+		data = rows
+	}
+
+	buf, err := sonic.Marshal(data)
+	if err != nil {
+		goutils.Error("saveReelsJSON:Marshal",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return err
+	}
+
+	return os.WriteFile(fn, buf, 0644)
+}
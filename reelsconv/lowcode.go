@@ -0,0 +1,258 @@
+package reelsconv
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/bytedance/sonic/ast"
+	"github.com/zhs007/goutils"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+)
+
+const lowcodeReelsType = "Reels"
+
+// lowcodeReels - an entry in lowcode otherList
+//
+//	fileWeights is a reelsconv extension, lowcode ignores it, it only keeps the stop weights
+type lowcodeReels struct {
+	FileName    string     `json:"fileName"`
+	Type        string     `json:"type"`
+	FileJSON    [][]string `json:"fileJson"`
+	FileWeights [][]int    `json:"fileWeights,omitempty"`
+}
+
+// parseLowcodeExcelJSON - excelJson is [{"R1":"A", "R2":"B", ...}, ...]
+func parseLowcodeExcelJSON(rows []map[string]string) []*Reel {
+	width := 0
+	for _, row := range rows {
+		for k := range row {
+			if len(k) > 1 && (k[0] == 'R' || k[0] == 'r') {
+				iv, err := goutils.String2Int64(k[1:])
+				if err == nil && int(iv) > width {
+					width = int(iv)
+				}
+			}
+		}
+	}
+
+	reels := make([]*Reel, 0, width)
+	for x := 1; x <= width; x++ {
+		reel := &Reel{}
+
+		for _, row := range rows {
+			str := strings.TrimSpace(row[fmt.Sprintf("R%v", x)])
+			if str == "" {
+				break
+			}
+
+			reel.Symbols = append(reel.Symbols, str)
+		}
+
+		reels = append(reels, reel)
+	}
+
+	return reels
+}
+
+// parseLowcodeReels - parse an entry in lowcode otherList
+func parseLowcodeReels(n *ast.Node) (*ReelSet, error) {
+	buf, err := n.MarshalJSON()
+	if err != nil {
+		goutils.Error("parseLowcodeReels:MarshalJSON",
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	entry := &lowcodeReels{}
+	err = sonic.Unmarshal(buf, entry)
+	if err != nil {
+		goutils.Error("parseLowcodeReels:Unmarshal",
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	rs := &ReelSet{
+		Name: entry.FileName,
+	}
+
+	if entry.FileJSON != nil {
+		for x, arr := range entry.FileJSON {
+			reel := &Reel{
+				Symbols: arr,
+			}
+
+			if x < len(entry.FileWeights) {
+				reel.Weights = entry.FileWeights[x]
+			}
+
+			rs.Reels = append(rs.Reels, reel)
+		}
+
+		return rs, nil
+	}
+
+	excelJSON := n.Get("excelJson")
+	if excelJSON == nil || !excelJSON.Exists() {
+		goutils.Error("parseLowcodeReels",
+			slog.String("fileName", entry.FileName),
+			goutils.Err(ErrInvalidReels))
+
+		return nil, ErrInvalidReels
+	}
+
+	buf, err = excelJSON.MarshalJSON()
+	if err != nil {
+		goutils.Error("parseLowcodeReels:excelJson:MarshalJSON",
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	rows := []map[string]string{}
+	err = sonic.Unmarshal(buf, &rows)
+	if err != nil {
+		goutils.Error("parseLowcodeReels:excelJson:Unmarshal",
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	rs.Reels = parseLowcodeExcelJSON(rows)
+
+	return rs, nil
+}
+
+// loadLowcodeJSON - load a lowcode game json (repository.otherList) or an otherList array, only type "Reels" is used
+func loadLowcodeJSON(fn string, pt *sgc7game.PayTables) (*ReelSets, error) {
+	data, err := os.ReadFile(fn)
+	if err != nil {
+		goutils.Error("loadLowcodeJSON:ReadFile",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	root, err := sonic.Get(data)
+	if err != nil {
+		goutils.Error("loadLowcodeJSON:Get",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	lstOther := &root
+	if root.TypeSafe() == ast.V_OBJECT {
+		lstOther = root.GetByPath("repository", "otherList")
+	}
+
+	lst, err := lstOther.ArrayUseNode()
+	if err != nil {
+		goutils.Error("loadLowcodeJSON:ArrayUseNode",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	rss := &ReelSets{}
+
+	for i := range lst {
+		t, err := lst[i].Get("type").String()
+		if err != nil || t != lowcodeReelsType {
+			continue
+		}
+
+		rs, err := parseLowcodeReels(&lst[i])
+		if err != nil {
+			goutils.Error("loadLowcodeJSON:parseLowcodeReels",
+				slog.String("fn", fn),
+				slog.Int("i", i),
+				goutils.Err(err))
+
+			return nil, err
+		}
+
+		err = checkWeights(rs)
+		if err != nil {
+			goutils.Error("loadLowcodeJSON:checkWeights",
+				slog.String("fn", fn),
+				goutils.Err(err))
+
+			return nil, err
+		}
+
+		err = checkSymbols(rs, pt)
+		if err != nil {
+			goutils.Error("loadLowcodeJSON:checkSymbols",
+				slog.String("fn", fn),
+				goutils.Err(err))
+
+			return nil, err
+		}
+
+		normalizeWeights(rs)
+
+		err = rss.Add(rs)
+		if err != nil {
+			goutils.Error("loadLowcodeJSON:Add",
+				slog.String("fn", fn),
+				goutils.Err(err))
+
+			return nil, err
+		}
+	}
+
+	if len(rss.Sets) <= 0 {
+		goutils.Error("loadLowcodeJSON",
+			slog.String("fn", fn),
+			goutils.Err(ErrNoReelSets))
+
+		return nil, ErrNoReelSets
+	}
+
+	return rss, nil
+}
+
+// saveLowcodeJSON - save as an otherList array, every entry can be pasted into lowcode otherList
+func saveLowcodeJSON(fn string, rss *ReelSets) error {
+	lst := make([]*lowcodeReels, 0, len(rss.Sets))
+
+	for _, rs := range rss.Sets {
+		entry := &lowcodeReels{
+			FileName: rs.Name,
+			Type:     lowcodeReelsType,
+			FileJSON: rs.ToStrings(),
+		}
+
+		if rs.IsWeighted() {
+			for _, reel := range rs.Reels {
+				weights := make([]int, 0, len(reel.Symbols))
+				for i := range reel.Symbols {
+					weights = append(weights, reel.GetWeight(i))
+				}
+
+				entry.FileWeights = append(entry.FileWeights, weights)
+			}
+		}
+
+		lst = append(lst, entry)
+	}
+
+	buf, err := sonic.MarshalIndent(lst, "", "  ")
+	if err != nil {
+		goutils.Error("saveLowcodeJSON:MarshalIndent",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return err
+	}
+
+	return os.WriteFile(fn, buf, 0644)
+}
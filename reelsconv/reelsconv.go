@@ -0,0 +1,403 @@
+package reelsconv
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+
+	"github.com/zhs007/goutils"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+)
+
+// Format - reels file format
+//
+//	None of these formats defines stop weights, so weighted reels are saved with extensions,
+//	and the original loaders ignore them:
+//	  excel / excelsymbols - W1..Wn columns after R1..Rn
+//	  relaxxml - <weight> after the <reel> list of a <table>, relaxutils.Table.Weight
+//	  lowcodejson - fileWeights next to fileJson
+//	reels5json and reels3json can not hold weights, Save returns ErrWeightsNotSupported.
+//	Unweighted reels are saved without any extension.
+type Format string
+
+const (
+	// FormatReels5JSON - LoadReels5JSON, [{R1..R5, line}], int code
+	FormatReels5JSON Format = "reels5json"
+	// FormatReels3JSON - LoadReels3JSON, [{R1..R3, line}], int code
+	FormatReels3JSON Format = "reels3json"
+	// FormatExcel - LoadReelsFromExcel, line, R1..Rn, int code, one sheet per reel set, W1..Wn is an extension
+	FormatExcel Format = "excel"
+	// FormatExcelSymbols - LoadReelsFromExcel2 and mathtoolset2.LoadReels, line, R1..Rn, symbol string, one sheet per reel set, W1..Wn is an extension
+	FormatExcelSymbols Format = "excelsymbols"
+	// FormatRelaxXML - relaxutils.BuildReels, <table><reel><item>, <weight> is an extension
+	FormatRelaxXML Format = "relaxxml"
+	// FormatLowcodeJSON - lowcode otherList, {fileName, type:"Reels", fileJson}, fileWeights is an extension
+	FormatLowcodeJSON Format = "lowcodejson"
+)
+
+// AllFormats - all formats
+var AllFormats = []Format{
+	FormatReels5JSON,
+	FormatReels3JSON,
+	FormatExcel,
+	FormatExcelSymbols,
+	FormatRelaxXML,
+	FormatLowcodeJSON,
+}
+
+// ParseFormat - string to Format
+func ParseFormat(str string) (Format, error) {
+	str = strings.ToLower(strings.TrimSpace(str))
+
+	for _, v := range AllFormats {
+		if string(v) == str {
+			return v, nil
+		}
+	}
+
+	goutils.Error("ParseFormat",
+		slog.String("format", str),
+		goutils.Err(ErrInvalidFormat))
+
+	return "", ErrInvalidFormat
+}
+
+// Reel - one reel, Weights is nil when every stop has the same weight
+type Reel struct {
+	Symbols []string `json:"symbols"`
+	Weights []int    `json:"weights,omitempty"`
+}
+
+// IsWeighted - is it a weighted reel
+func (reel *Reel) IsWeighted() bool {
+	for _, v := range reel.Weights {
+		if v != 1 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetWeight - get weight of stop
+func (reel *Reel) GetWeight(i int) int {
+	if len(reel.Weights) == 0 {
+		return 1
+	}
+
+	return reel.Weights[i]
+}
+
+// ReelSet - a named reel set
+type ReelSet struct {
+	Name  string  `json:"name"`
+	Reels []*Reel `json:"reels"`
+}
+
+// IsWeighted - is it a weighted reel set
+func (rs *ReelSet) IsWeighted() bool {
+	for _, reel := range rs.Reels {
+		if reel.IsWeighted() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ToReelsData - convert to ReelsData
+func (rs *ReelSet) ToReelsData(pt *sgc7game.PayTables) (*sgc7game.ReelsData, error) {
+	if pt == nil {
+		goutils.Error("ReelSet.ToReelsData",
+			goutils.Err(ErrInvalidPaytables))
+
+		return nil, ErrInvalidPaytables
+	}
+
+	rd := sgc7game.NewReelsData(len(rs.Reels))
+
+	for x, reel := range rs.Reels {
+		arr := make([]int, 0, len(reel.Symbols))
+
+		for y, str := range reel.Symbols {
+			s, isok := pt.MapSymbols[str]
+			if !isok {
+				goutils.Error("ReelSet.ToReelsData",
+					slog.String("name", rs.Name),
+					slog.Int("x", x),
+					slog.Int("y", y),
+					slog.String("symbol", str),
+					goutils.Err(ErrInvalidSymbol))
+
+				return nil, ErrInvalidSymbol
+			}
+
+			arr = append(arr, s)
+		}
+
+		rd.SetReel(x, arr)
+	}
+
+	return rd, nil
+}
+
+// ToStrings - convert to [][]string, it's the same as mathtoolset2 reels
+func (rs *ReelSet) ToStrings() [][]string {
+	arr := make([][]string, 0, len(rs.Reels))
+
+	for _, reel := range rs.Reels {
+		arr = append(arr, reel.Symbols)
+	}
+
+	return arr
+}
+
+// ReelSets - reel sets
+type ReelSets struct {
+	Sets []*ReelSet `json:"sets"`
+}
+
+// IsWeighted - is there any weighted reel
+func (rss *ReelSets) IsWeighted() bool {
+	for _, rs := range rss.Sets {
+		if rs.IsWeighted() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Get - get reel set by name
+func (rss *ReelSets) Get(name string) *ReelSet {
+	for _, rs := range rss.Sets {
+		if rs.Name == name {
+			return rs
+		}
+	}
+
+	return nil
+}
+
+// Add - add a reel set
+func (rss *ReelSets) Add(rs *ReelSet) error {
+	if rss.Get(rs.Name) != nil {
+		goutils.Error("ReelSets.Add",
+			slog.String("name", rs.Name),
+			goutils.Err(ErrDuplicateName))
+
+		return ErrDuplicateName
+	}
+
+	rss.Sets = append(rss.Sets, rs)
+
+	return nil
+}
+
+// NewReelSetFromReelsData - new ReelSet with ReelsData
+func NewReelSetFromReelsData(name string, rd *sgc7game.ReelsData, pt *sgc7game.PayTables) (*ReelSet, error) {
+	if pt == nil {
+		goutils.Error("NewReelSetFromReelsData",
+			goutils.Err(ErrInvalidPaytables))
+
+		return nil, ErrInvalidPaytables
+	}
+
+	if rd == nil {
+		goutils.Error("NewReelSetFromReelsData",
+			slog.String("name", name),
+			goutils.Err(ErrInvalidReels))
+
+		return nil, ErrInvalidReels
+	}
+
+	mapCode := make(map[int]string, len(pt.MapSymbols))
+	for k, v := range pt.MapSymbols {
+		mapCode[v] = k
+	}
+
+	rs := &ReelSet{
+		Name: name,
+	}
+
+	for x, arr := range rd.Reels {
+		reel := &Reel{
+			Symbols: make([]string, 0, len(arr)),
+		}
+
+		for y, s := range arr {
+			str, isok := mapCode[s]
+			if !isok {
+				goutils.Error("NewReelSetFromReelsData",
+					slog.String("name", name),
+					slog.Int("x", x),
+					slog.Int("y", y),
+					slog.Int("symbol", s),
+					goutils.Err(ErrInvalidSymbol))
+
+				return nil, ErrInvalidSymbol
+			}
+
+			reel.Symbols = append(reel.Symbols, str)
+		}
+
+		rs.Reels = append(rs.Reels, reel)
+	}
+
+	return rs, nil
+}
+
+// Compare - compare 2 ReelSets, the names, symbols and weights must be the same
+func Compare(src *ReelSets, dest *ReelSets) error {
+	if len(src.Sets) != len(dest.Sets) {
+		goutils.Error("Compare",
+			slog.Int("src", len(src.Sets)),
+			slog.Int("dest", len(dest.Sets)),
+			goutils.Err(ErrRoundTripMismatch))
+
+		return ErrRoundTripMismatch
+	}
+
+	for i, srs := range src.Sets {
+		drs := dest.Sets[i]
+
+		err := compareReelSet(srs, drs)
+		if err != nil {
+			goutils.Error("Compare:compareReelSet",
+				slog.Int("i", i),
+				goutils.Err(err))
+
+			return err
+		}
+	}
+
+	return nil
+}
+
+func compareReelSet(src *ReelSet, dest *ReelSet) error {
+	if src.Name != dest.Name || len(src.Reels) != len(dest.Reels) {
+		goutils.Error("compareReelSet",
+			slog.String("src", src.Name),
+			slog.String("dest", dest.Name),
+			slog.Int("srcWidth", len(src.Reels)),
+			slog.Int("destWidth", len(dest.Reels)),
+			goutils.Err(ErrRoundTripMismatch))
+
+		return ErrRoundTripMismatch
+	}
+
+	for x, sreel := range src.Reels {
+		dreel := dest.Reels[x]
+
+		if len(sreel.Symbols) != len(dreel.Symbols) {
+			goutils.Error("compareReelSet",
+				slog.String("name", src.Name),
+				slog.Int("x", x),
+				slog.Int("src", len(sreel.Symbols)),
+				slog.Int("dest", len(dreel.Symbols)),
+				goutils.Err(ErrRoundTripMismatch))
+
+			return ErrRoundTripMismatch
+		}
+
+		for y, s := range sreel.Symbols {
+			if s != dreel.Symbols[y] || sreel.GetWeight(y) != dreel.GetWeight(y) {
+				goutils.Error("compareReelSet",
+					slog.String("name", src.Name),
+					slog.Int("x", x),
+					slog.Int("y", y),
+					slog.String("src", s),
+					slog.String("dest", dreel.Symbols[y]),
+					slog.Int("srcWeight", sreel.GetWeight(y)),
+					slog.Int("destWeight", dreel.GetWeight(y)),
+					goutils.Err(ErrRoundTripMismatch))
+
+				return ErrRoundTripMismatch
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkSymbols - if pt is not nil, all symbols must be in paytables
+func checkSymbols(rs *ReelSet, pt *sgc7game.PayTables) error {
+	if pt == nil {
+		return nil
+	}
+
+	for x, reel := range rs.Reels {
+		for y, str := range reel.Symbols {
+			_, isok := pt.MapSymbols[str]
+			if !isok {
+				goutils.Error("checkSymbols",
+					slog.String("name", rs.Name),
+					slog.Int("x", x),
+					slog.Int("y", y),
+					slog.String("symbol", str),
+					goutils.Err(ErrInvalidSymbol))
+
+				return ErrInvalidSymbol
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkWeights - the length of Weights must be the same as Symbols, and every weight must be > 0
+func checkWeights(rs *ReelSet) error {
+	for x, reel := range rs.Reels {
+		if len(reel.Weights) == 0 {
+			continue
+		}
+
+		if len(reel.Weights) != len(reel.Symbols) {
+			goutils.Error("checkWeights",
+				slog.String("name", rs.Name),
+				slog.Int("x", x),
+				slog.Int("symbols", len(reel.Symbols)),
+				slog.Int("weights", len(reel.Weights)),
+				goutils.Err(ErrInvalidWeight))
+
+			return ErrInvalidWeight
+		}
+
+		for y, w := range reel.Weights {
+			if w <= 0 {
+				goutils.Error("checkWeights",
+					slog.String("name", rs.Name),
+					slog.Int("x", x),
+					slog.Int("y", y),
+					slog.Int("weight", w),
+					goutils.Err(ErrInvalidWeight))
+
+				return ErrInvalidWeight
+			}
+		}
+	}
+
+	return nil
+}
+
+// normalizeWeights - remove Weights when all of them are 1
+func normalizeWeights(rs *ReelSet) {
+	for _, reel := range rs.Reels {
+		if !reel.IsWeighted() {
+			reel.Weights = nil
+		}
+	}
+}
+
+// nameFromFile - reels5json and reels3json have no name, use filename
+func nameFromFile(fn string) string {
+	name := filepath.Base(fn)
+
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// defaultName - default reel set name
+func defaultName(i int) string {
+	return fmt.Sprintf("reels%v", i)
+}
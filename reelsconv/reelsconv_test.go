@@ -0,0 +1,171 @@
+package reelsconv
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+)
+
+func getTestFn(prefix string, format Format) string {
+	switch format {
+	case FormatExcel, FormatExcelSymbols:
+		return fmt.Sprintf("../unittestdata/%v-%v.xlsx", prefix, format)
+	case FormatRelaxXML:
+		return fmt.Sprintf("../unittestdata/%v-%v.xml", prefix, format)
+	}
+
+	return fmt.Sprintf("../unittestdata/%v-%v.json", prefix, format)
+}
+
+func Test_ParseFormat(t *testing.T) {
+	f, err := ParseFormat(" Excel ")
+	assert.NoError(t, err)
+	assert.Equal(t, FormatExcel, f)
+
+	_, err = ParseFormat("csv")
+	assert.Equal(t, ErrInvalidFormat, err)
+
+	t.Logf("Test_ParseFormat OK")
+}
+
+func Test_Convert(t *testing.T) {
+	pt, err := sgc7game.LoadPayTables5JSON("../unittestdata/paytables.json")
+	assert.NoError(t, err)
+
+	src, err := Load("../unittestdata/reels.json", FormatReels5JSON, pt)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(src.Sets))
+	assert.Equal(t, "reels", src.Sets[0].Name)
+	assert.Equal(t, 5, len(src.Sets[0].Reels))
+
+	rd, err := sgc7game.LoadReels5JSON("../unittestdata/reels.json")
+	assert.NoError(t, err)
+
+	rd1, err := src.Sets[0].ToReelsData(pt)
+	assert.NoError(t, err)
+	assert.Equal(t, rd.Reels, rd1.Reels)
+
+	srcfn := "../unittestdata/reels.json"
+	srcFormat := FormatReels5JSON
+
+	for _, format := range []Format{FormatExcel, FormatExcelSymbols, FormatRelaxXML, FormatLowcodeJSON, FormatReels5JSON} {
+		destfn := getTestFn("reelsconv", format)
+
+		rss, err := Convert(srcfn, srcFormat, destfn, format, pt)
+		assert.NoError(t, err)
+		assert.NoError(t, Compare(src, rss))
+
+		srcfn = destfn
+		srcFormat = format
+	}
+
+	// excel is compatible with LoadReelsFromExcel
+	rd2, err := sgc7game.LoadReelsFromExcel(getTestFn("reelsconv", FormatExcel))
+	assert.NoError(t, err)
+	assert.Equal(t, rd.Reels, rd2.Reels)
+
+	// excelsymbols is compatible with LoadReelsFromExcel2
+	rd3, err := sgc7game.LoadReelsFromExcel2(getTestFn("reelsconv", FormatExcelSymbols), pt)
+	assert.NoError(t, err)
+	assert.Equal(t, rd.Reels, rd3.Reels)
+
+	_, err = Load("../unittestdata/reels.json", FormatReels3JSON, pt)
+	assert.NoError(t, err)
+
+	err = Save(getTestFn("reelsconv", FormatReels3JSON), FormatReels3JSON, src, pt)
+	assert.Equal(t, ErrInvalidWidth, err)
+
+	t.Logf("Test_Convert OK")
+}
+
+func Test_ConvertWeights(t *testing.T) {
+	pt, err := sgc7game.LoadPayTables5JSON("../unittestdata/paytables.json")
+	assert.NoError(t, err)
+
+	src := &ReelSets{}
+
+	err = src.Add(&ReelSet{
+		Name: "bg",
+		Reels: []*Reel{
+			{Symbols: []string{"WL", "A", "B"}, Weights: []int{1, 5, 10}},
+			{Symbols: []string{"A", "B"}},
+			{Symbols: []string{"C", "D", "E", "F"}, Weights: []int{2, 2, 3, 1}},
+		},
+	})
+	assert.NoError(t, err)
+
+	err = src.Add(&ReelSet{
+		Name: "fg",
+		Reels: []*Reel{
+			{Symbols: []string{"A", "B"}},
+			{Symbols: []string{"C", "D", "E"}},
+			{Symbols: []string{"WL"}},
+		},
+	})
+	assert.NoError(t, err)
+
+	err = src.Add(&ReelSet{Name: "fg"})
+	assert.Equal(t, ErrDuplicateName, err)
+
+	for _, format := range []Format{FormatExcel, FormatExcelSymbols, FormatRelaxXML, FormatLowcodeJSON} {
+		fn := getTestFn("reelsconvw", format)
+
+		err = Save(fn, format, src, pt)
+		assert.NoError(t, err)
+
+		err = Verify(fn, format, src, pt)
+		assert.NoError(t, err)
+	}
+
+	// W1..Wn 是扩展，原来的 LoadReelsFromExcel 只读 R1..Rn
+	rd, err := sgc7game.LoadReelsFromExcel(getTestFn("reelsconvw", FormatExcel))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(rd.Reels))
+
+	for x, reel := range src.Sets[0].Reels {
+		assert.Equal(t, len(reel.Symbols), len(rd.Reels[x]))
+	}
+
+	err = Save(getTestFn("reelsconvw", FormatReels5JSON), FormatReels5JSON, src, pt)
+	assert.Equal(t, ErrMultiSetsNotSupported, err)
+
+	err = Save(getTestFn("reelsconvw", FormatReels3JSON), FormatReels3JSON, &ReelSets{Sets: src.Sets[:1]}, pt)
+	assert.Equal(t, ErrWeightsNotSupported, err)
+
+	err = Save(getTestFn("reelsconvw", FormatReels3JSON), FormatReels3JSON, &ReelSets{Sets: src.Sets[1:]}, pt)
+	assert.NoError(t, err)
+
+	err = Verify(getTestFn("reelsconvw", FormatReels3JSON), FormatReels3JSON, &ReelSets{Sets: src.Sets[1:]}, pt)
+	assert.NoError(t, err)
+
+	src.Sets[0].Reels[0].Weights[0] = 2
+	err = Verify(getTestFn("reelsconvw", FormatRelaxXML), FormatRelaxXML, src, pt)
+	assert.Equal(t, ErrRoundTripMismatch, err)
+
+	src.Sets[0].Reels[0].Weights = []int{1}
+	err = Save(getTestFn("reelsconvw", FormatRelaxXML), FormatRelaxXML, src, pt)
+	assert.Equal(t, ErrInvalidWeight, err)
+
+	t.Logf("Test_ConvertWeights OK")
+}
+
+func Test_LoadLowcodeJSON(t *testing.T) {
+	rss, err := Load("../unittestdata/testgame.json", FormatLowcodeJSON, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(rss.Sets))
+	assert.NotNil(t, rss.Get("bg-reel1"))
+	assert.NotNil(t, rss.Get("fg-reel1"))
+
+	err = Save(getTestFn("reelsconvlc", FormatRelaxXML), FormatRelaxXML, rss, nil)
+	assert.NoError(t, err)
+
+	err = Verify(getTestFn("reelsconvlc", FormatRelaxXML), FormatRelaxXML, rss, nil)
+	assert.NoError(t, err)
+
+	_, err = Load("../unittestdata/testgame.json", FormatExcel, nil)
+	assert.Equal(t, ErrInvalidPaytables, err)
+
+	t.Logf("Test_LoadLowcodeJSON OK")
+}
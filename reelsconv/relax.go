@@ -0,0 +1,169 @@
+package reelsconv
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/zhs007/goutils"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	"github.com/zhs007/slotsgamecore7/relaxutils"
+)
+
+type relaxReels struct {
+	XMLName xml.Name `xml:"reels"`
+	Tables  []*relaxutils.Table
+}
+
+// loadRelaxXML - load every <table> in the xml file, so it also works with a full relax config
+//
+//	the comment of table is the reel set name, <weight> is a reelsconv extension for the optional stop weights of the reel
+func loadRelaxXML(fn string, pt *sgc7game.PayTables) (*ReelSets, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		goutils.Error("loadRelaxXML:Open",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return nil, err
+	}
+	defer f.Close()
+
+	rss := &ReelSets{}
+	decoder := xml.NewDecoder(f)
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			goutils.Error("loadRelaxXML:Token",
+				slog.String("fn", fn),
+				goutils.Err(err))
+
+			return nil, err
+		}
+
+		se, isok := token.(xml.StartElement)
+		if !isok || se.Name.Local != "table" {
+			continue
+		}
+
+		table := &relaxutils.Table{}
+		err = decoder.DecodeElement(table, &se)
+		if err != nil {
+			goutils.Error("loadRelaxXML:DecodeElement",
+				slog.String("fn", fn),
+				goutils.Err(err))
+
+			return nil, err
+		}
+
+		rs := &ReelSet{
+			Name: strings.TrimSpace(table.TableComment),
+		}
+
+		if rs.Name == "" {
+			rs.Name = defaultName(len(rss.Sets))
+		}
+
+		for _, reel := range table.Reel {
+			rs.Reels = append(rs.Reels, &Reel{
+				Symbols: reel.Vals,
+			})
+		}
+
+		if len(table.Weight) > 0 {
+			if len(table.Weight) != len(table.Reel) {
+				goutils.Error("loadRelaxXML",
+					slog.String("fn", fn),
+					slog.String("name", rs.Name),
+					slog.Int("reels", len(table.Reel)),
+					slog.Int("weights", len(table.Weight)),
+					goutils.Err(ErrInvalidWeight))
+
+				return nil, ErrInvalidWeight
+			}
+
+			for x, weights := range table.Weight {
+				rs.Reels[x].Weights = weights.Vals
+			}
+		}
+
+		err = checkWeights(rs)
+		if err != nil {
+			goutils.Error("loadRelaxXML:checkWeights",
+				slog.String("fn", fn),
+				goutils.Err(err))
+
+			return nil, err
+		}
+
+		err = checkSymbols(rs, pt)
+		if err != nil {
+			goutils.Error("loadRelaxXML:checkSymbols",
+				slog.String("fn", fn),
+				goutils.Err(err))
+
+			return nil, err
+		}
+
+		normalizeWeights(rs)
+
+		err = rss.Add(rs)
+		if err != nil {
+			goutils.Error("loadRelaxXML:Add",
+				slog.String("fn", fn),
+				goutils.Err(err))
+
+			return nil, err
+		}
+	}
+
+	if len(rss.Sets) <= 0 {
+		goutils.Error("loadRelaxXML",
+			slog.String("fn", fn),
+			goutils.Err(ErrNoReelSets))
+
+		return nil, ErrNoReelSets
+	}
+
+	return rss, nil
+}
+
+// saveRelaxXML - save as <reels><table>, it's the same as relaxutils.BuildReels
+func saveRelaxXML(fn string, rss *ReelSets) error {
+	cfg := &relaxReels{}
+
+	for _, rs := range rss.Sets {
+		table := &relaxutils.Table{
+			TableComment: fmt.Sprintf(" %v ", rs.Name),
+		}
+
+		isWeighted := rs.IsWeighted()
+
+		for _, reel := range rs.Reels {
+			table.Reel = append(table.Reel, &relaxutils.StringList{
+				Vals: reel.Symbols,
+			})
+
+			if isWeighted {
+				weights := &relaxutils.IntList{}
+				for i := range reel.Symbols {
+					weights.Vals = append(weights.Vals, reel.GetWeight(i))
+				}
+
+				table.Weight = append(table.Weight, weights)
+			}
+		}
+
+		cfg.Tables = append(cfg.Tables, table)
+	}
+
+	return relaxutils.SaveConfig(fn, cfg, nil)
+}
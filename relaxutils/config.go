@@ -51,6 +51,7 @@ type Table struct {
 	XMLName      xml.Name      `xml:"table"`
 	TableComment string        `xml:",comment"`
 	Reel         []*StringList `xml:"reel"`
+	Weight       []*IntList    `xml:"weight,omitempty"` // 不是 relax 格式里的，reelsconv 用来保存停轮权重
 }
 
 type Reels struct {
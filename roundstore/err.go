@@ -0,0 +1,24 @@
+package roundstore
+
+import "errors"
+
+var (
+	// ErrRoundNotFound - round not found
+	ErrRoundNotFound = errors.New("round not found")
+	// ErrInvalidRoundID - invalid roundID
+	ErrInvalidRoundID = errors.New("invalid roundID")
+	// ErrRoundFinished - the round is finished
+	ErrRoundFinished = errors.New("the round is finished")
+	// ErrInvalidGameCode - the round is not in this game
+	ErrInvalidGameCode = errors.New("invalid gameCode")
+	// ErrNoRoundStore - no RoundStore
+	ErrNoRoundStore = errors.New("no RoundStore")
+	// ErrInvalidPlayerID - the wallet needs playerID
+	ErrInvalidPlayerID = errors.New("invalid playerID")
+	// ErrInvalidStake - the wallet needs stake
+	ErrInvalidStake = errors.New("invalid stake")
+	// ErrInvalidCommand - the command and params are not in nextCommands of the round
+	ErrInvalidCommand = errors.New("invalid command")
+	// ErrInvalidRoundPlayer - the round is not of this player
+	ErrInvalidRoundPlayer = errors.New("the round is not of this player")
+)
@@ -0,0 +1,219 @@
+package roundstore
+
+import (
+	"log/slog"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/zhs007/goutils"
+	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const roundFileExt = ".json"
+
+// FileRoundStore - RoundStore in a directory, a json file for each round
+type FileRoundStore struct {
+	dir string
+}
+
+func (store *FileRoundStore) getFilename(roundID string) string {
+	return path.Join(store.dir, roundID+roundFileExt)
+}
+
+func (store *FileRoundStore) loadRound(fn string) (*sgc7pb.RoundData, error) {
+	buf, err := os.ReadFile(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	round := &sgc7pb.RoundData{}
+	err = protojson.Unmarshal(buf, round)
+	if err != nil {
+		goutils.Error("FileRoundStore.loadRound:Unmarshal",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	return round, nil
+}
+
+// GetRound - get a round, return ErrRoundNotFound if the round is not found
+func (store *FileRoundStore) GetRound(roundID string) (*sgc7pb.RoundData, error) {
+	if !IsValidRoundID(roundID) {
+		goutils.Error("FileRoundStore.GetRound",
+			slog.String("roundID", roundID),
+			goutils.Err(ErrInvalidRoundID))
+
+		return nil, ErrInvalidRoundID
+	}
+
+	round, err := store.loadRound(store.getFilename(roundID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrRoundNotFound
+		}
+
+		goutils.Error("FileRoundStore.GetRound:loadRound",
+			slog.String("roundID", roundID),
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	return round, nil
+}
+
+// SaveRound - add or update a round, write a temporary file and rename it, so a crash never leaves a broken file
+func (store *FileRoundStore) SaveRound(round *sgc7pb.RoundData) error {
+	if !IsValidRoundID(round.RoundID) {
+		goutils.Error("FileRoundStore.SaveRound",
+			slog.String("roundID", round.RoundID),
+			goutils.Err(ErrInvalidRoundID))
+
+		return ErrInvalidRoundID
+	}
+
+	buf, err := protojson.Marshal(round)
+	if err != nil {
+		goutils.Error("FileRoundStore.SaveRound:Marshal",
+			slog.String("roundID", round.RoundID),
+			goutils.Err(err))
+
+		return err
+	}
+
+	f, err := os.CreateTemp(store.dir, round.RoundID+".*.tmp")
+	if err != nil {
+		goutils.Error("FileRoundStore.SaveRound:CreateTemp",
+			slog.String("roundID", round.RoundID),
+			goutils.Err(err))
+
+		return err
+	}
+
+	tmpfn := f.Name()
+
+	_, err = f.Write(buf)
+	if err == nil {
+		err = f.Sync()
+	}
+
+	f.Close()
+
+	if err == nil {
+		err = os.Rename(tmpfn, store.getFilename(round.RoundID))
+	}
+
+	if err != nil {
+		goutils.Error("FileRoundStore.SaveRound:Write",
+			slog.String("roundID", round.RoundID),
+			goutils.Err(err))
+
+		os.Remove(tmpfn)
+
+		return err
+	}
+
+	return nil
+}
+
+// DeleteRound - delete a round
+func (store *FileRoundStore) DeleteRound(roundID string) error {
+	if !IsValidRoundID(roundID) {
+		goutils.Error("FileRoundStore.DeleteRound",
+			slog.String("roundID", roundID),
+			goutils.Err(ErrInvalidRoundID))
+
+		return ErrInvalidRoundID
+	}
+
+	err := os.Remove(store.getFilename(roundID))
+	if err != nil && !os.IsNotExist(err) {
+		goutils.Error("FileRoundStore.DeleteRound:Remove",
+			slog.String("roundID", roundID),
+			goutils.Err(err))
+
+		return err
+	}
+
+	return nil
+}
+
+// ClearFinishedRounds - delete the finished rounds updated before ts, the rounds with pending jackpots or wallet transactions are kept
+func (store *FileRoundStore) ClearFinishedRounds(ts int64) (int, error) {
+	lst, err := os.ReadDir(store.dir)
+	if err != nil {
+		goutils.Error("FileRoundStore.ClearFinishedRounds:ReadDir",
+			slog.String("dir", store.dir),
+			goutils.Err(err))
+
+		return 0, err
+	}
+
+	num := 0
+	for _, v := range lst {
+		if v.IsDir() || !strings.HasSuffix(v.Name(), roundFileExt) {
+			continue
+		}
+
+		fn := path.Join(store.dir, v.Name())
+
+		round, err := store.loadRound(fn)
+		if err != nil {
+			// 可能正好被删掉了
+			continue
+		}
+
+		if round.Finished && !hasPending(round) && round.UpdateTime < ts {
+			err = os.Remove(fn)
+			if err == nil {
+				num++
+			}
+		}
+	}
+
+	return num, nil
+}
+
+// ListRounds - list all roundIDs
+func (store *FileRoundStore) ListRounds() ([]string, error) {
+	lst, err := os.ReadDir(store.dir)
+	if err != nil {
+		goutils.Error("FileRoundStore.ListRounds:ReadDir",
+			slog.String("dir", store.dir),
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	roundIDs := []string{}
+	for _, v := range lst {
+		if v.IsDir() || !strings.HasSuffix(v.Name(), roundFileExt) {
+			continue
+		}
+
+		roundIDs = append(roundIDs, strings.TrimSuffix(v.Name(), roundFileExt))
+	}
+
+	return roundIDs, nil
+}
+
+// NewFileRoundStore - new a FileRoundStore, dir will be created if it does not exist
+func NewFileRoundStore(dir string) (*FileRoundStore, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		goutils.Error("NewFileRoundStore:MkdirAll",
+			slog.String("dir", dir),
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	return &FileRoundStore{
+		dir: dir,
+	}, nil
+}
@@ -0,0 +1,95 @@
+package roundstore
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/zhs007/goutils"
+	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// MemRoundStore - RoundStore in memory, the rounds are lost when the process exits
+type MemRoundStore struct {
+	lock      sync.RWMutex
+	mapRounds map[string]*sgc7pb.RoundData
+}
+
+// GetRound - get a round, return ErrRoundNotFound if the round is not found
+func (store *MemRoundStore) GetRound(roundID string) (*sgc7pb.RoundData, error) {
+	store.lock.RLock()
+	defer store.lock.RUnlock()
+
+	round, isok := store.mapRounds[roundID]
+	if !isok {
+		return nil, ErrRoundNotFound
+	}
+
+	// 返回副本，调用者改了也不会影响保存的数据
+	return proto.Clone(round).(*sgc7pb.RoundData), nil
+}
+
+// SaveRound - add or update a round
+func (store *MemRoundStore) SaveRound(round *sgc7pb.RoundData) error {
+	if !IsValidRoundID(round.RoundID) {
+		goutils.Error("MemRoundStore.SaveRound",
+			slog.String("roundID", round.RoundID),
+			goutils.Err(ErrInvalidRoundID))
+
+		return ErrInvalidRoundID
+	}
+
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	store.mapRounds[round.RoundID] = proto.Clone(round).(*sgc7pb.RoundData)
+
+	return nil
+}
+
+// DeleteRound - delete a round
+func (store *MemRoundStore) DeleteRound(roundID string) error {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	delete(store.mapRounds, roundID)
+
+	return nil
+}
+
+// ClearFinishedRounds - delete the finished rounds updated before ts, the rounds with pending jackpots or wallet transactions are kept
+func (store *MemRoundStore) ClearFinishedRounds(ts int64) (int, error) {
+	store.lock.Lock()
+	defer store.lock.Unlock()
+
+	num := 0
+	for k, v := range store.mapRounds {
+		if v.Finished && !hasPending(v) && v.UpdateTime < ts {
+			delete(store.mapRounds, k)
+
+			num++
+		}
+	}
+
+	return num, nil
+}
+
+// ListRounds - list all roundIDs
+func (store *MemRoundStore) ListRounds() ([]string, error) {
+	store.lock.RLock()
+	defer store.lock.RUnlock()
+
+	lst := make([]string, 0, len(store.mapRounds))
+	for k := range store.mapRounds {
+		lst = append(lst, k)
+	}
+
+	return lst, nil
+}
+
+// NewMemRoundStore - new a MemRoundStore
+func NewMemRoundStore() *MemRoundStore {
+	return &MemRoundStore{
+		mapRounds: make(map[string]*sgc7pb.RoundData),
+	}
+}
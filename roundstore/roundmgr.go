@@ -0,0 +1,746 @@
+package roundstore
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/zhs007/goutils"
+	"github.com/zhs007/slotsgamecore7/jackpot"
+	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
+	"github.com/zhs007/slotsgamecore7/wallet"
+	"google.golang.org/protobuf/proto"
+)
+
+// roundLockNum - 按 roundID 的 hash 分桶加锁，同一个 round 的请求是串行的
+const roundLockNum = 64
+
+// DefaultFinishedRoundTTL - 结束的 round 保留多久，这段时间里重复的请求和 ResumeRound 还能拿到结果
+const DefaultFinishedRoundTTL = 24 * time.Hour
+
+// DefaultCleanupInterval - 多久清理一次结束的 round
+const DefaultCleanupInterval = 10 * time.Minute
+
+const (
+	// walletStateNone - no pending transaction
+	walletStateNone int32 = 0
+	// walletStateDebiting - debit 可能已经完成，但第一步的结果还没保存，恢复时 rollback
+	walletStateDebiting int32 = 1
+	// walletStateCrediting - 结果已经保存，credit 还没完成，恢复时重新 credit
+	walletStateCrediting int32 = 2
+)
+
+// hasPending - the round has a pending wallet transaction or jackpot, it can't be cleared
+func hasPending(round *sgc7pb.RoundData) bool {
+	return round.WalletState != walletStateNone || round.IsPendingJackpot
+}
+
+// FuncPlay - play a step of round, the server's original play function
+type FuncPlay func(req *sgc7pb.RequestPlay) (*sgc7pb.ReplyPlay, error)
+
+// RoundMgr - play with RoundStore and Wallet
+//
+//	一个 round 从第一次请求开始，到 Finished 为止，中间的每一步都会保存下来，
+//	继续一个 round 时，PlayerState 和 Stake 都用保存的，不依赖客户端传回来的数据。
+//	同一个 requestID 的重复请求会直接返回第一次的结果。
+//	round 属于第一次请求的 playerID，其他玩家继续这个 round 或者 ResumeRound 都会返回 ErrInvalidRoundPlayer。
+//	有 Wallet 时，第一步之前 debit stake.cashBet，每一步之后 credit 这一步的 cashWin，
+//	wallet 的状态会先写到 RoundData 里，进程在 debit 和 credit 之间崩溃时，可以用 RecoverRounds 恢复。
+//	有 Jackpot 时，第一步从 stake.cashBet 里抽取奖池，gameCode 就是 poolID，每一步中的 jackpot 和这一步的 cashWin 一起 credit，
+//	这一步的结果先保存，再处理 jackpot，失败时下次请求或者 RecoverRounds 会用同样的 txID 和 hitID 再处理一次。
+//	结束的 round 保留 FinishedRoundTTL，用 StartCleanup 定时清理。
+type RoundMgr struct {
+	Store            RoundStore
+	Wallet           wallet.Wallet
+	Jackpot          jackpot.JackpotPool
+	FinishedRoundTTL time.Duration
+	locks            [roundLockNum]sync.Mutex
+}
+
+func (mgr *RoundMgr) getLock(roundID string) *sync.Mutex {
+	h := fnv.New32a()
+	h.Write([]byte(roundID))
+
+	return &mgr.locks[h.Sum32()%roundLockNum]
+}
+
+// getRoundID - 没有 roundID 时，如果有 requestID 就用它生成，这样新 round 的第一个请求重复时也能找到，
+//
+//	playerID 也要放进去，不同玩家用同一个 requestID 时不会是同一个 round
+func (mgr *RoundMgr) getRoundID(req *sgc7pb.RequestPlay) (string, error) {
+	if req.RoundID != "" {
+		if !IsValidRoundID(req.RoundID) {
+			return "", ErrInvalidRoundID
+		}
+
+		return req.RoundID, nil
+	}
+
+	if req.RequestID != "" {
+		// playerID 里可能有 roundID 不允许的字符，所以用它的 hash
+		h := fnv.New64a()
+		h.Write([]byte(req.PlayerID))
+
+		roundID := fmt.Sprintf("r-%016x-%v", h.Sum64(), req.RequestID)
+		if !IsValidRoundID(roundID) {
+			return "", ErrInvalidRoundID
+		}
+
+		return roundID, nil
+	}
+
+	return NewRoundID(), nil
+}
+
+func (mgr *RoundMgr) getDebitTx(round *sgc7pb.RoundData) *wallet.Transaction {
+	return &wallet.Transaction{
+		TxID:     round.WalletTxID + "-d",
+		PlayerID: round.PlayerID,
+		RoundID:  round.RoundID,
+		Currency: round.Stake.Currency,
+		Amount:   int64(round.Stake.CashBet),
+	}
+}
+
+func (mgr *RoundMgr) getCreditTx(round *sgc7pb.RoundData) *wallet.Transaction {
+	return &wallet.Transaction{
+		TxID:     fmt.Sprintf("%v-c%v", round.WalletTxID, round.CreditNum),
+		PlayerID: round.PlayerID,
+		RoundID:  round.RoundID,
+		Currency: round.Stake.Currency,
+		Amount:   round.PendingCredit,
+	}
+}
+
+// rollbackRound - rollback the debit and delete the round
+func (mgr *RoundMgr) rollbackRound(round *sgc7pb.RoundData) error {
+	_, err := mgr.Wallet.Rollback(mgr.getDebitTx(round))
+	if err != nil {
+		goutils.Error("RoundMgr.rollbackRound:Rollback",
+			slog.String("roundID", round.RoundID),
+			goutils.Err(err))
+
+		return err
+	}
+
+	err = mgr.Store.DeleteRound(round.RoundID)
+	if err != nil {
+		goutils.Error("RoundMgr.rollbackRound:DeleteRound",
+			slog.String("roundID", round.RoundID),
+			goutils.Err(err))
+
+		return err
+	}
+
+	return nil
+}
+
+// creditRound - credit the pending win, the round is saved
+func (mgr *RoundMgr) creditRound(round *sgc7pb.RoundData) error {
+	balance, err := mgr.Wallet.Credit(mgr.getCreditTx(round))
+	if err != nil {
+		goutils.Error("RoundMgr.creditRound:Credit",
+			slog.String("roundID", round.RoundID),
+			goutils.Err(err))
+
+		return err
+	}
+
+	round.Balance = balance
+	round.CreditNum++
+	round.PendingCredit = 0
+	round.WalletState = walletStateNone
+	round.UpdateTime = time.Now().Unix()
+
+	// credit 已经成功了，保存失败时恢复会再 credit 一次，txID 一样所以不会重复
+	err = mgr.Store.SaveRound(round)
+	if err != nil {
+		goutils.Error("RoundMgr.creditRound:SaveRound",
+			slog.String("roundID", round.RoundID),
+			goutils.Err(err))
+	}
+
+	return nil
+}
+
+// procJackpot - process the jackpot of the last step, the results of the step are saved,
+//
+//	the contribution is taken only in the first step, the jackpot win is added to the pending credit
+func (mgr *RoundMgr) procJackpot(round *sgc7pb.RoundData) error {
+	stake := int64(0)
+	if round.Stake != nil {
+		stake = int64(round.Stake.CashBet)
+	}
+
+	offset := int(round.JackpotOffset)
+
+	jpwin, err := jackpot.ProcPBResults(mgr.Jackpot, round.GameCode, round.PlayerID, round.RoundID, stake, offset == 0, offset, round.Results[offset:])
+	if err != nil {
+		goutils.Error("RoundMgr.procJackpot:ProcPBResults",
+			slog.String("roundID", round.RoundID),
+			goutils.Err(err))
+
+		return err
+	}
+
+	// 保存过的 round 里，reply 和 round.Results 不是同一份
+	reply, isok := round.Requests[round.JackpotRequestID]
+	if isok {
+		reply.Results = round.Results[offset:]
+	}
+
+	round.IsPendingJackpot = false
+	round.JackpotOffset = 0
+	round.JackpotRequestID = ""
+	round.UpdateTime = time.Now().Unix()
+
+	if mgr.Wallet != nil && jpwin > 0 {
+		round.WalletState = walletStateCrediting
+		round.PendingCredit += jpwin
+	}
+
+	// jackpot 已经处理了，保存失败时恢复会再处理一次，txID 和 hitID 一样所以不会重复
+	err = mgr.Store.SaveRound(round)
+	if err != nil {
+		goutils.Error("RoundMgr.procJackpot:SaveRound",
+			slog.String("roundID", round.RoundID),
+			goutils.Err(err))
+	}
+
+	return nil
+}
+
+// recoverRound - finish the pending jackpot and wallet transaction, return nil if the round is rolled back
+func (mgr *RoundMgr) recoverRound(round *sgc7pb.RoundData) (*sgc7pb.RoundData, error) {
+	if round.IsPendingJackpot && mgr.Jackpot != nil {
+		goutils.Info("RoundMgr.recoverRound:jackpot",
+			slog.String("roundID", round.RoundID))
+
+		err := mgr.procJackpot(round)
+		if err != nil {
+			goutils.Error("RoundMgr.recoverRound:procJackpot",
+				slog.String("roundID", round.RoundID),
+				goutils.Err(err))
+
+			return nil, err
+		}
+	}
+
+	if mgr.Wallet == nil {
+		return round, nil
+	}
+
+	switch round.WalletState {
+	case walletStateDebiting:
+		goutils.Info("RoundMgr.recoverRound:rollback",
+			slog.String("roundID", round.RoundID))
+
+		err := mgr.rollbackRound(round)
+		if err != nil {
+			goutils.Error("RoundMgr.recoverRound:rollbackRound",
+				slog.String("roundID", round.RoundID),
+				goutils.Err(err))
+
+			return nil, err
+		}
+
+		return nil, nil
+	case walletStateCrediting:
+		goutils.Info("RoundMgr.recoverRound:credit",
+			slog.String("roundID", round.RoundID),
+			slog.Int64("pendingCredit", round.PendingCredit))
+
+		err := mgr.creditRound(round)
+		if err != nil {
+			goutils.Error("RoundMgr.recoverRound:creditRound",
+				slog.String("roundID", round.RoundID),
+				goutils.Err(err))
+
+			return nil, err
+		}
+	}
+
+	return round, nil
+}
+
+// newRound - new a round, debit the bet if there is a wallet
+func (mgr *RoundMgr) newRound(gameCode string, roundID string, req *sgc7pb.RequestPlay) (*sgc7pb.RoundData, error) {
+	round := &sgc7pb.RoundData{
+		RoundID:          roundID,
+		GameCode:         gameCode,
+		Stake:            req.Stake,
+		StartPlayerState: req.PlayerState,
+		PlayerID:         req.PlayerID,
+	}
+
+	if mgr.Wallet == nil {
+		return round, nil
+	}
+
+	if req.PlayerID == "" {
+		goutils.Error("RoundMgr.newRound",
+			slog.String("roundID", roundID),
+			goutils.Err(ErrInvalidPlayerID))
+
+		return nil, ErrInvalidPlayerID
+	}
+
+	if req.Stake == nil || req.Stake.CashBet < 0 {
+		goutils.Error("RoundMgr.newRound",
+			slog.String("roundID", roundID),
+			goutils.Err(ErrInvalidStake))
+
+		return nil, ErrInvalidStake
+	}
+
+	// 每次尝试都用新的 txID，被 rollback 的 debit 不会影响重试
+	round.WalletTxID = goutils.AppendString(roundID, "-", newRandomID(4))
+	round.WalletState = walletStateDebiting
+	round.UpdateTime = time.Now().Unix()
+
+	err := mgr.Store.SaveRound(round)
+	if err != nil {
+		goutils.Error("RoundMgr.newRound:SaveRound",
+			slog.String("roundID", roundID),
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	balance, err := mgr.Wallet.Debit(mgr.getDebitTx(round))
+	if err != nil {
+		goutils.Error("RoundMgr.newRound:Debit",
+			slog.String("roundID", roundID),
+			goutils.Err(err))
+
+		// debit 的结果不确定时也要 rollback
+		mgr.rollbackRound(round)
+
+		return nil, err
+	}
+
+	round.Balance = balance
+
+	return round, nil
+}
+
+// isValidNextCommand - the command and params must be one of nextCommands, the params of some games carry the state,
+//
+//	an empty command is any of nextCommands
+func isValidNextCommand(round *sgc7pb.RoundData, req *sgc7pb.RequestPlay) bool {
+	if len(round.NextCommands) == 0 {
+		return true
+	}
+
+	for i, cmd := range round.NextCommands {
+		if req.Command != "" && req.Command != cmd {
+			continue
+		}
+
+		param := ""
+		if i < len(round.NextCommandParams) {
+			param = round.NextCommandParams[i]
+		}
+
+		if req.ClientParams == param {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Play - play a step of round with onPlay, and save it
+func (mgr *RoundMgr) Play(gameCode string, req *sgc7pb.RequestPlay, onPlay FuncPlay) (*sgc7pb.ReplyPlay, error) {
+	roundID, err := mgr.getRoundID(req)
+	if err != nil {
+		goutils.Error("RoundMgr.Play:getRoundID",
+			slog.String("roundID", req.RoundID),
+			slog.String("requestID", req.RequestID),
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	lock := mgr.getLock(roundID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	round, err := mgr.Store.GetRound(roundID)
+	if err != nil && err != ErrRoundNotFound {
+		goutils.Error("RoundMgr.Play:GetRound",
+			slog.String("roundID", roundID),
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	if round != nil {
+		round, err = mgr.recoverRound(round)
+		if err != nil {
+			goutils.Error("RoundMgr.Play:recoverRound",
+				slog.String("roundID", roundID),
+				goutils.Err(err))
+
+			return nil, err
+		}
+	}
+
+	nreq := proto.Clone(req).(*sgc7pb.RequestPlay)
+	isNewRound := round == nil
+
+	if !isNewRound {
+		if round.GameCode != gameCode {
+			goutils.Error("RoundMgr.Play",
+				slog.String("roundID", roundID),
+				slog.String("gameCode", gameCode),
+				slog.String("roundGameCode", round.GameCode),
+				goutils.Err(ErrInvalidGameCode))
+
+			return nil, ErrInvalidGameCode
+		}
+
+		// 重复的请求和继续 round 都只能是这个 round 的玩家
+		if round.PlayerID != req.PlayerID {
+			goutils.Error("RoundMgr.Play",
+				slog.String("roundID", roundID),
+				slog.String("playerID", req.PlayerID),
+				slog.String("roundPlayerID", round.PlayerID),
+				goutils.Err(ErrInvalidRoundPlayer))
+
+			return nil, ErrInvalidRoundPlayer
+		}
+
+		if req.RequestID != "" {
+			reply, isok := round.Requests[req.RequestID]
+			if isok {
+				goutils.Info("RoundMgr.Play:duplicate request",
+					slog.String("roundID", roundID),
+					slog.String("requestID", req.RequestID))
+
+				if mgr.Wallet != nil {
+					reply.Balance = round.Balance
+				}
+
+				return reply, nil
+			}
+		}
+
+		if round.Finished {
+			goutils.Error("RoundMgr.Play",
+				slog.String("roundID", roundID),
+				goutils.Err(ErrRoundFinished))
+
+			return nil, ErrRoundFinished
+		}
+
+		if !isValidNextCommand(round, req) {
+			goutils.Error("RoundMgr.Play",
+				slog.String("roundID", roundID),
+				slog.String("command", req.Command),
+				goutils.Err(ErrInvalidCommand))
+
+			return nil, ErrInvalidCommand
+		}
+
+		nreq.PlayerState = round.PlayerState
+		nreq.Stake = round.Stake
+	} else {
+		round, err = mgr.newRound(gameCode, roundID, req)
+		if err != nil {
+			goutils.Error("RoundMgr.Play:newRound",
+				slog.String("roundID", roundID),
+				goutils.Err(err))
+
+			return nil, err
+		}
+	}
+
+	reply, err := onPlay(nreq)
+	if err != nil {
+		goutils.Error("RoundMgr.Play:onPlay",
+			slog.String("roundID", roundID),
+			goutils.Err(err))
+
+		if isNewRound && mgr.Wallet != nil {
+			mgr.rollbackRound(round)
+		}
+
+		return nil, err
+	}
+
+	reply.RoundID = roundID
+
+	win := int64(0)
+	for _, v := range reply.Results {
+		win += v.CashWin
+	}
+
+	if mgr.Jackpot != nil {
+		// must hit 的 jackpot 放在这一步的最后一个结果里，没有结果时加一个空的
+		if len(reply.Results) == 0 {
+			reply.Results = append(reply.Results, &sgc7pb.GameResult{})
+		}
+
+		round.IsPendingJackpot = true
+		round.JackpotOffset = int32(len(round.Results))
+		round.JackpotRequestID = req.RequestID
+	}
+
+	round.Results = append(round.Results, reply.Results...)
+	round.RandomNumbers = append(round.RandomNumbers, reply.RandomNumbers...)
+	round.PlayerState = reply.PlayerState
+	round.Finished = reply.Finished
+	round.NextCommands = reply.NextCommands
+	round.NextCommandParams = reply.NextCommandParams
+	round.UpdateTime = time.Now().Unix()
+
+	if mgr.Wallet != nil {
+		if win > 0 {
+			round.WalletState = walletStateCrediting
+			round.PendingCredit = win
+		} else {
+			round.WalletState = walletStateNone
+		}
+	}
+
+	if req.RequestID != "" {
+		if round.Requests == nil {
+			round.Requests = make(map[string]*sgc7pb.ReplyPlay)
+		}
+
+		round.Requests[req.RequestID] = reply
+	}
+
+	// 保存失败时不返回结果，否则客户端会以为这一步已经可以恢复了
+	err = mgr.Store.SaveRound(round)
+	if err != nil {
+		goutils.Error("RoundMgr.Play:SaveRound",
+			slog.String("roundID", roundID),
+			goutils.Err(err))
+
+		if isNewRound && mgr.Wallet != nil {
+			mgr.rollbackRound(round)
+		}
+
+		return nil, err
+	}
+
+	if round.IsPendingJackpot {
+		// 失败时结果已经保存了，下次请求或者 RecoverRounds 时会再处理
+		err = mgr.procJackpot(round)
+		if err != nil {
+			goutils.Error("RoundMgr.Play:procJackpot",
+				slog.String("roundID", roundID),
+				goutils.Err(err))
+
+			return nil, err
+		}
+	}
+
+	if mgr.Wallet != nil {
+		if round.WalletState == walletStateCrediting {
+			// credit 失败时结果已经保存了，下次请求或者 RecoverRounds 时会再 credit
+			err = mgr.creditRound(round)
+			if err != nil {
+				goutils.Error("RoundMgr.Play:creditRound",
+					slog.String("roundID", roundID),
+					goutils.Err(err))
+
+				return nil, err
+			}
+		} else if !isNewRound {
+			balance, err := mgr.Wallet.GetBalance(round.PlayerID, round.Stake.Currency)
+			if err != nil {
+				goutils.Error("RoundMgr.Play:GetBalance",
+					slog.String("roundID", roundID),
+					goutils.Err(err))
+
+				return nil, err
+			}
+
+			round.Balance = balance
+		}
+
+		reply.Balance = round.Balance
+	}
+
+	return reply, nil
+}
+
+// ResumeRound - get all results so far of a round, the client can continue it with nextCommands and roundID,
+//
+//	playerID must be the player of the round
+func (mgr *RoundMgr) ResumeRound(gameCode string, playerID string, roundID string) (*sgc7pb.ReplyPlay, error) {
+	if !IsValidRoundID(roundID) {
+		goutils.Error("RoundMgr.ResumeRound",
+			slog.String("roundID", roundID),
+			goutils.Err(ErrInvalidRoundID))
+
+		return nil, ErrInvalidRoundID
+	}
+
+	lock := mgr.getLock(roundID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	round, err := mgr.Store.GetRound(roundID)
+	if err != nil {
+		goutils.Error("RoundMgr.ResumeRound:GetRound",
+			slog.String("roundID", roundID),
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	if round.GameCode != gameCode {
+		goutils.Error("RoundMgr.ResumeRound",
+			slog.String("roundID", roundID),
+			slog.String("gameCode", gameCode),
+			slog.String("roundGameCode", round.GameCode),
+			goutils.Err(ErrInvalidGameCode))
+
+		return nil, ErrInvalidGameCode
+	}
+
+	if round.PlayerID != playerID {
+		goutils.Error("RoundMgr.ResumeRound",
+			slog.String("roundID", roundID),
+			slog.String("playerID", playerID),
+			slog.String("roundPlayerID", round.PlayerID),
+			goutils.Err(ErrInvalidRoundPlayer))
+
+		return nil, ErrInvalidRoundPlayer
+	}
+
+	round, err = mgr.recoverRound(round)
+	if err != nil {
+		goutils.Error("RoundMgr.ResumeRound:recoverRound",
+			slog.String("roundID", roundID),
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	if round == nil {
+		return nil, ErrRoundNotFound
+	}
+
+	return &sgc7pb.ReplyPlay{
+		RandomNumbers:     round.RandomNumbers,
+		PlayerState:       round.PlayerState,
+		Finished:          round.Finished,
+		Results:           round.Results,
+		NextCommands:      round.NextCommands,
+		NextCommandParams: round.NextCommandParams,
+		RoundID:           round.RoundID,
+		Balance:           round.Balance,
+	}, nil
+}
+
+// RecoverRounds - finish all the pending jackpots and wallet transactions, call it when the server starts,
+//
+//	return the number of recovered rounds
+func (mgr *RoundMgr) RecoverRounds() (int, error) {
+	if mgr.Wallet == nil && mgr.Jackpot == nil {
+		return 0, nil
+	}
+
+	lst, err := mgr.Store.ListRounds()
+	if err != nil {
+		goutils.Error("RoundMgr.RecoverRounds:ListRounds",
+			goutils.Err(err))
+
+		return 0, err
+	}
+
+	num := 0
+	for _, roundID := range lst {
+		isRecovered, err := mgr.recoverRoundWithID(roundID)
+		if err != nil {
+			goutils.Error("RoundMgr.RecoverRounds:recoverRoundWithID",
+				slog.String("roundID", roundID),
+				goutils.Err(err))
+
+			return num, err
+		}
+
+		if isRecovered {
+			num++
+		}
+	}
+
+	return num, nil
+}
+
+func (mgr *RoundMgr) recoverRoundWithID(roundID string) (bool, error) {
+	lock := mgr.getLock(roundID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	round, err := mgr.Store.GetRound(roundID)
+	if err != nil {
+		if err == ErrRoundNotFound {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	if !hasPending(round) {
+		return false, nil
+	}
+
+	_, err = mgr.recoverRound(round)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ClearFinishedRounds - delete the rounds finished before FinishedRoundTTL, the rounds with pending jackpots or wallet transactions are kept
+func (mgr *RoundMgr) ClearFinishedRounds() (int, error) {
+	num, err := mgr.Store.ClearFinishedRounds(time.Now().Add(-mgr.FinishedRoundTTL).Unix())
+	if err != nil {
+		goutils.Error("RoundMgr.ClearFinishedRounds:ClearFinishedRounds",
+			goutils.Err(err))
+
+		return 0, err
+	}
+
+	return num, nil
+}
+
+// StartCleanup - call ClearFinishedRounds every interval until ctx is done
+func (mgr *RoundMgr) StartCleanup(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				num, err := mgr.ClearFinishedRounds()
+				if err == nil && num > 0 {
+					goutils.Info("RoundMgr.StartCleanup",
+						slog.Int("rounds", num))
+				}
+			}
+		}
+	}()
+}
+
+// NewRoundMgr - new a RoundMgr, w can be nil
+func NewRoundMgr(store RoundStore, w wallet.Wallet) *RoundMgr {
+	return &RoundMgr{
+		Store:            store,
+		Wallet:           w,
+		FinishedRoundTTL: DefaultFinishedRoundTTL,
+	}
+}
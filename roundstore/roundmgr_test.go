@@ -0,0 +1,535 @@
+package roundstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	"github.com/zhs007/slotsgamecore7/jackpot"
+	"github.com/zhs007/slotsgamecore7/lowcode"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
+	"github.com/zhs007/slotsgamecore7/wallet"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+var errTestCrash = errors.New("crash")
+
+// crashWallet - the next credit fails, like a crash between debit and credit
+type crashWallet struct {
+	*wallet.MemWallet
+	isCrashCredit bool
+}
+
+func (w *crashWallet) Credit(tx *wallet.Transaction) (int64, error) {
+	if w.isCrashCredit {
+		w.isCrashCredit = false
+
+		return 0, errTestCrash
+	}
+
+	return w.MemWallet.Credit(tx)
+}
+
+// failStore - SaveRound fails when isFail returns true
+type failStore struct {
+	*MemRoundStore
+	isFail func(round *sgc7pb.RoundData) bool
+}
+
+func (store *failStore) SaveRound(round *sgc7pb.RoundData) error {
+	if store.isFail != nil && store.isFail(round) {
+		return errTestCrash
+	}
+
+	return store.MemRoundStore.SaveRound(round)
+}
+
+// failJackpotPool - the next hit fails
+type failJackpotPool struct {
+	jackpot.JackpotPool
+	isFailHit bool
+}
+
+func (jp *failJackpotPool) Hit(h *jackpot.Hit) (int64, error) {
+	if jp.isFailHit {
+		jp.isFailHit = false
+
+		return 0, errTestCrash
+	}
+
+	return jp.JackpotPool.Hit(h)
+}
+
+func Test_RoundMgrWallet(t *testing.T) {
+	store := NewMemRoundStore()
+	w := &crashWallet{MemWallet: wallet.NewMemWallet(1000)}
+	mgr := NewRoundMgr(store, w)
+	game := &testGame{}
+	stake := &sgc7pb.Stake{CoinBet: 1, CashBet: 10, Currency: "EUR"}
+
+	_, err := mgr.Play("game1", &sgc7pb.RequestPlay{Stake: stake, RequestID: "req0"}, game.onPlay)
+	assert.Equal(t, ErrInvalidPlayerID, err)
+
+	_, err = mgr.Play("game1", &sgc7pb.RequestPlay{Stake: &sgc7pb.Stake{CoinBet: 1, CashBet: 2000, Currency: "EUR"},
+		PlayerID: "p1", RequestID: "req0"}, game.onPlay)
+	assert.Equal(t, wallet.ErrInsufficientBalance, err)
+
+	roundID0, err := mgr.getRoundID(&sgc7pb.RequestPlay{PlayerID: "p1", RequestID: "req0"})
+	assert.NoError(t, err)
+
+	_, err = store.GetRound(roundID0)
+	assert.Equal(t, ErrRoundNotFound, err)
+
+	// the game error rolls back the bet
+	_, err = mgr.Play("game1", &sgc7pb.RequestPlay{Stake: stake, PlayerID: "p1", RequestID: "req0", Command: "ERROR"}, game.onPlay)
+	assert.Equal(t, ErrInvalidGameCode, err)
+
+	balance, err := w.GetBalance("p1", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), balance)
+
+	_, err = store.GetRound(roundID0)
+	assert.Equal(t, ErrRoundNotFound, err)
+
+	// the retry with the same requestID is a new attempt
+	reply, err := mgr.Play("game1", &sgc7pb.RequestPlay{Stake: stake, PlayerID: "p1", RequestID: "req0"}, game.onPlay)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), reply.Balance)
+	assert.Equal(t, roundID0, reply.RoundID)
+
+	// step 2 wins 20
+	reply, err = mgr.Play("game1", &sgc7pb.RequestPlay{RoundID: reply.RoundID, PlayerID: "p1", RequestID: "req1"}, game.onPlay)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1020), reply.Balance)
+
+	// step 3 wins 30, and crashes before credit
+	w.isCrashCredit = true
+
+	_, err = mgr.Play("game1", &sgc7pb.RequestPlay{RoundID: reply.RoundID, PlayerID: "p1", RequestID: "req2"}, game.onPlay)
+	assert.Equal(t, errTestCrash, err)
+
+	balance, err = w.GetBalance("p1", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1020), balance)
+
+	// restart
+	mgr1 := NewRoundMgr(store, w)
+
+	num, err := mgr1.RecoverRounds()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, num)
+
+	balance, err = w.GetBalance("p1", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1050), balance)
+
+	num, err = mgr1.RecoverRounds()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, num)
+
+	// the client retries
+	reply, err = mgr1.Play("game1", &sgc7pb.RequestPlay{RoundID: reply.RoundID, PlayerID: "p1", RequestID: "req2"}, game.onPlay)
+	assert.NoError(t, err)
+	assert.True(t, reply.Finished)
+	assert.Equal(t, int64(1050), reply.Balance)
+	assert.Equal(t, 4, game.plays) // ERROR + 3 steps
+
+	// crashes after debit, before the result is saved
+	round := &sgc7pb.RoundData{
+		RoundID:     "r-req3",
+		GameCode:    "game1",
+		Stake:       stake,
+		PlayerID:    "p1",
+		WalletState: walletStateDebiting,
+		WalletTxID:  "r-req3-0000",
+	}
+
+	err = store.SaveRound(round)
+	assert.NoError(t, err)
+
+	balance, err = w.Debit(mgr1.getDebitTx(round))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1040), balance)
+
+	_, err = mgr1.ResumeRound("game1", "p1", "r-req3")
+	assert.Equal(t, ErrRoundNotFound, err)
+
+	balance, err = w.GetBalance("p1", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1050), balance)
+
+	t.Logf("Test_RoundMgrWallet OK")
+}
+
+// gambleGame - the spin wins 20 and goes into the gamble, every request uses a new gambleData like grpcserv
+type gambleGame struct {
+	gamble *lowcode.Gamble
+	plugin *sgc7plugin.MockPlugin
+}
+
+func (game *gambleGame) onPlay(req *sgc7pb.RequestPlay) (*sgc7pb.ReplyPlay, error) {
+	stake := &sgc7game.Stake{CoinBet: int64(req.Stake.CoinBet), CashBet: int64(req.Stake.CashBet), Currency: req.Stake.Currency}
+	gameProp := &lowcode.GameProperty{Pool: &lowcode.GamePropertyPool{}}
+	gp := lowcode.NewGameParam(stake, nil)
+
+	pr := sgc7game.NewPlayResult("bg", 0, 0, "bg")
+	cmd := req.Command
+
+	if cmd == "" || cmd == "SPIN" {
+		cmd = "SPIN"
+		pr.Results = append(pr.Results, &sgc7game.Result{Type: sgc7game.RTLine, CoinWin: 20, CashWin: 20 * int(stake.CoinBet)})
+	}
+
+	// gamble 的状态在私有的 PlayerState 里，和 grpcserv.BasicService2 一样用 json 传
+	ps := lowcode.NewPlayerState()
+	if req.PlayerState != nil && req.PlayerState.Private != nil {
+		pri := &sgc7pb.BasicPlayerPrivateState2{}
+
+		err := req.PlayerState.Private.UnmarshalTo(pri)
+		if err != nil {
+			return nil, err
+		}
+
+		err = ps.SetPrivateJson(pri.Json)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := game.gamble.InitPlayerState(nil, nil, nil, ps, int(stake.CashBet/stake.CoinBet), int(stake.CoinBet))
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = game.gamble.OnPlayGame(gameProp, pr, gp, game.plugin, cmd, req.ClientParams, ps, stake, nil, game.gamble.NewComponentData())
+	if err != nil {
+		return nil, err
+	}
+
+	ps.OnOutput()
+
+	pri, err := anypb.New(&sgc7pb.BasicPlayerPrivateState2{Json: ps.GetPrivateJson()})
+	if err != nil {
+		return nil, err
+	}
+
+	// 和 onStepEnd 一样，不付 IsNoPayNow 的
+	ret := &sgc7pb.GameResult{}
+	for _, v := range pr.Results {
+		if !v.IsNoPayNow {
+			ret.CoinWin += int64(v.CoinWin)
+			ret.CashWin += int64(v.CashWin)
+		}
+	}
+
+	return &sgc7pb.ReplyPlay{
+		PlayerState:       &sgc7pb.PlayerState{Private: pri},
+		Finished:          !pr.IsWait,
+		Results:           []*sgc7pb.GameResult{ret},
+		NextCommands:      pr.NextCmds,
+		NextCommandParams: pr.NextCmdParams,
+	}, nil
+}
+
+func Test_RoundMgrGamble(t *testing.T) {
+	gamble := lowcode.NewGamble("gamble").(*lowcode.Gamble)
+	err := gamble.InitEx(&lowcode.GambleConfig{MaxAttempts: 3}, &lowcode.GamePropertyPool{Config: &lowcode.Config{}})
+	assert.NoError(t, err)
+
+	game := &gambleGame{gamble: gamble, plugin: sgc7plugin.NewMockPlugin()}
+	store := NewMemRoundStore()
+	w := wallet.NewMemWallet(1000)
+	mgr := NewRoundMgr(store, w)
+	stake := &sgc7pb.Stake{CoinBet: 1, CashBet: 10, Currency: "EUR"}
+
+	// the win of the spin is not paid before the gamble is over
+	reply, err := mgr.Play("game1", &sgc7pb.RequestPlay{Stake: stake, PlayerID: "p1", RequestID: "req0"}, game.onPlay)
+	assert.NoError(t, err)
+	assert.False(t, reply.Finished)
+	assert.Equal(t, int64(990), reply.Balance)
+	assert.Equal(t, []string{lowcode.DefaultCollectCmd, lowcode.DefaultGambleCmd, lowcode.DefaultGambleCmd}, reply.NextCommands)
+
+	// the params must be one of nextCommandParams
+	_, err = mgr.Play("game1", &sgc7pb.RequestPlay{RoundID: reply.RoundID, PlayerID: "p1", RequestID: "req1", Command: lowcode.DefaultCollectCmd,
+		ClientParams: `{"baseWin":2000,"curWin":2000}`}, game.onPlay)
+	assert.Equal(t, ErrInvalidCommand, err)
+
+	// lose
+	game.plugin.Cache = []int{15000, 0}
+
+	reply, err = mgr.Play("game1", &sgc7pb.RequestPlay{RoundID: reply.RoundID, PlayerID: "p1", RequestID: "req2", Command: lowcode.DefaultGambleCmd,
+		ClientParams: reply.NextCommandParams[1]}, game.onPlay)
+	assert.NoError(t, err)
+	assert.True(t, reply.Finished)
+	assert.Equal(t, int64(0), reply.Results[0].CashWin)
+	assert.Equal(t, int64(990), reply.Balance)
+
+	// collect
+	reply, err = mgr.Play("game1", &sgc7pb.RequestPlay{Stake: stake, PlayerID: "p1", RequestID: "req3"}, game.onPlay)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(980), reply.Balance)
+
+	reply, err = mgr.Play("game1", &sgc7pb.RequestPlay{RoundID: reply.RoundID, PlayerID: "p1", RequestID: "req4", Command: lowcode.DefaultCollectCmd,
+		ClientParams: reply.NextCommandParams[0]}, game.onPlay)
+	assert.NoError(t, err)
+	assert.True(t, reply.Finished)
+	assert.Equal(t, int64(1000), reply.Balance)
+
+	t.Logf("Test_RoundMgrGamble OK")
+}
+
+func Test_RoundMgrJackpot(t *testing.T) {
+	jp, err := jackpot.NewMemJackpotPool(&jackpot.Config{
+		Tiers: []*jackpot.TierConfig{{Name: "grand", Seed: 1000, Contribution: 100}},
+	})
+	assert.NoError(t, err)
+
+	store := NewMemRoundStore()
+	w := wallet.NewMemWallet(10000)
+	mgr := NewRoundMgr(store, w)
+	mgr.Jackpot = jp
+	stake := &sgc7pb.Stake{CoinBet: 1, CashBet: 100, Currency: "EUR"}
+
+	// the second step hits grand
+	onPlay := func(req *sgc7pb.RequestPlay) (*sgc7pb.ReplyPlay, error) {
+		if req.PlayerState == nil {
+			return &sgc7pb.ReplyPlay{
+				PlayerState:  &sgc7pb.PlayerState{Version: "1"},
+				Results:      []*sgc7pb.GameResult{{ClientData: &sgc7pb.PlayResult{}}},
+				NextCommands: []string{"SPIN"},
+			}, nil
+		}
+
+		return &sgc7pb.ReplyPlay{
+			PlayerState: &sgc7pb.PlayerState{Version: "2"},
+			Finished:    true,
+			Results:     []*sgc7pb.GameResult{{CashWin: 5, ClientData: &sgc7pb.PlayResult{JackpotType: 1}}},
+		}, nil
+	}
+
+	reply, err := mgr.Play("game1", &sgc7pb.RequestPlay{Stake: stake, PlayerID: "p1", RequestID: "req0"}, onPlay)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9900), reply.Balance)
+
+	pool, err := jp.GetPool("game1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), pool.TotalStake)
+	assert.Equal(t, int64(1001), pool.GetTier("grand").Value)
+
+	reply, err = mgr.Play("game1", &sgc7pb.RequestPlay{RoundID: reply.RoundID, PlayerID: "p1", RequestID: "req1"}, onPlay)
+	assert.NoError(t, err)
+	assert.True(t, reply.Finished)
+	assert.Equal(t, int64(1001), reply.Results[0].ClientData.JackpotCashWin)
+	assert.Equal(t, int64(9900+5+1001), reply.Balance)
+
+	// the contribution is only in the first step
+	pool, err = jp.GetPool("game1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), pool.TotalStake)
+	assert.Equal(t, int64(1000), pool.GetTier("grand").Value)
+
+	t.Logf("Test_RoundMgrJackpot OK")
+}
+
+func Test_RoundMgrJackpotRecover(t *testing.T) {
+	mjp, err := jackpot.NewMemJackpotPool(&jackpot.Config{
+		Tiers: []*jackpot.TierConfig{
+			{Name: "mini", Seed: 10, Contribution: 1000, MustHitBy: 20},
+			{Name: "grand", Seed: 1000, Contribution: 100},
+		},
+	})
+	assert.NoError(t, err)
+
+	jp := &failJackpotPool{JackpotPool: mjp}
+	store := &failStore{MemRoundStore: NewMemRoundStore()}
+	w := wallet.NewMemWallet(10000)
+	mgr := NewRoundMgr(store, w)
+	mgr.Jackpot = jp
+	stake := &sgc7pb.Stake{CoinBet: 1, CashBet: 100, Currency: "EUR"}
+
+	// the first step has no results and mini reaches MustHitBy, the second step hits grand
+	onPlay := func(req *sgc7pb.RequestPlay) (*sgc7pb.ReplyPlay, error) {
+		if req.PlayerState == nil {
+			return &sgc7pb.ReplyPlay{
+				PlayerState:  &sgc7pb.PlayerState{Version: "1"},
+				NextCommands: []string{"SPIN"},
+			}, nil
+		}
+
+		return &sgc7pb.ReplyPlay{
+			PlayerState: &sgc7pb.PlayerState{Version: "2"},
+			Finished:    true,
+			Results:     []*sgc7pb.GameResult{{CashWin: 5, ClientData: &sgc7pb.PlayResult{JackpotType: 2}}},
+		}, nil
+	}
+
+	// the results are not saved, the jackpot is not touched
+	store.isFail = func(round *sgc7pb.RoundData) bool {
+		return round.IsPendingJackpot
+	}
+
+	_, err = mgr.Play("game1", &sgc7pb.RequestPlay{Stake: stake, PlayerID: "p1", RequestID: "req0"}, onPlay)
+	assert.Equal(t, errTestCrash, err)
+
+	store.isFail = nil
+
+	pool, err := jp.GetPool("game1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), pool.TotalStake)
+	assert.Equal(t, int64(10), pool.GetTier("mini").Value)
+
+	balance, err := w.GetBalance("p1", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10000), balance)
+
+	// mini is paid in the result added to the step
+	reply, err := mgr.Play("game1", &sgc7pb.RequestPlay{Stake: stake, PlayerID: "p1", RequestID: "req0"}, onPlay)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(reply.Results))
+	assert.Equal(t, int32(1), reply.Results[0].ClientData.JackpotType)
+	assert.Equal(t, int64(20), reply.Results[0].ClientData.JackpotCashWin)
+	assert.Equal(t, int64(10000-100+20), reply.Balance)
+
+	roundID := reply.RoundID
+
+	// the hit fails after the results are saved
+	jp.isFailHit = true
+
+	_, err = mgr.Play("game1", &sgc7pb.RequestPlay{RoundID: roundID, PlayerID: "p1", RequestID: "req1"}, onPlay)
+	assert.Equal(t, errTestCrash, err)
+
+	round, err := store.GetRound(roundID)
+	assert.NoError(t, err)
+	assert.True(t, round.IsPendingJackpot)
+
+	balance, err = w.GetBalance("p1", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9920), balance)
+
+	// restart
+	mgr1 := NewRoundMgr(store, w)
+	mgr1.Jackpot = jp
+
+	num, err := mgr1.RecoverRounds()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, num)
+
+	balance, err = w.GetBalance("p1", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(9920+5+1001), balance)
+
+	// the client retries
+	reply, err = mgr1.Play("game1", &sgc7pb.RequestPlay{RoundID: roundID, PlayerID: "p1", RequestID: "req1"}, onPlay)
+	assert.NoError(t, err)
+	assert.True(t, reply.Finished)
+	assert.Equal(t, int64(1001), reply.Results[0].ClientData.JackpotCashWin)
+	assert.Equal(t, int64(9920+5+1001), reply.Balance)
+
+	pool, err = jp.GetPool("game1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), pool.TotalStake)
+	assert.Equal(t, int64(1000), pool.GetTier("grand").Value)
+
+	t.Logf("Test_RoundMgrJackpotRecover OK")
+}
+
+func Test_RoundMgrPlayers(t *testing.T) {
+	store := NewMemRoundStore()
+	w := wallet.NewMemWallet(1000)
+	mgr := NewRoundMgr(store, w)
+	game := &testGame{}
+	stake := &sgc7pb.Stake{CoinBet: 1, CashBet: 10, Currency: "EUR"}
+
+	// the same requestID of 2 players are 2 rounds
+	reply1, err := mgr.Play("game1", &sgc7pb.RequestPlay{Stake: stake, PlayerID: "p1", RequestID: "req0"}, game.onPlay)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), reply1.Balance)
+
+	reply2, err := mgr.Play("game1", &sgc7pb.RequestPlay{Stake: stake, PlayerID: "p2", RequestID: "req0"}, game.onPlay)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), reply2.Balance)
+	assert.NotEqual(t, reply1.RoundID, reply2.RoundID)
+	assert.Equal(t, 2, game.plays)
+
+	// p2 can not get the reply of p1, or continue and resume the round of p1
+	_, err = mgr.Play("game1", &sgc7pb.RequestPlay{RoundID: reply1.RoundID, PlayerID: "p2", RequestID: "req0"}, game.onPlay)
+	assert.Equal(t, ErrInvalidRoundPlayer, err)
+
+	_, err = mgr.Play("game1", &sgc7pb.RequestPlay{RoundID: reply1.RoundID, PlayerID: "p2", RequestID: "req1"}, game.onPlay)
+	assert.Equal(t, ErrInvalidRoundPlayer, err)
+
+	_, err = mgr.Play("game1", &sgc7pb.RequestPlay{RoundID: reply1.RoundID, RequestID: "req1"}, game.onPlay)
+	assert.Equal(t, ErrInvalidRoundPlayer, err)
+
+	_, err = mgr.ResumeRound("game1", "p2", reply1.RoundID)
+	assert.Equal(t, ErrInvalidRoundPlayer, err)
+
+	_, err = mgr.ResumeRound("game1", "", reply1.RoundID)
+	assert.Equal(t, ErrInvalidRoundPlayer, err)
+
+	assert.Equal(t, 2, game.plays)
+
+	balance, err := w.GetBalance("p2", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), balance)
+
+	// p1 still can
+	reply, err := mgr.Play("game1", &sgc7pb.RequestPlay{RoundID: reply1.RoundID, PlayerID: "p1", RequestID: "req0"}, game.onPlay)
+	assert.NoError(t, err)
+	assert.Equal(t, reply1.Results, reply.Results)
+
+	reply, err = mgr.Play("game1", &sgc7pb.RequestPlay{RoundID: reply1.RoundID, PlayerID: "p1", RequestID: "req1"}, game.onPlay)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1020), reply.Balance)
+
+	resume, err := mgr.ResumeRound("game1", "p1", reply1.RoundID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(resume.Results))
+
+	t.Logf("Test_RoundMgrPlayers OK")
+}
+
+func Test_RoundMgrClearFinishedRounds(t *testing.T) {
+	store := NewMemRoundStore()
+	mgr := NewRoundMgr(store, nil)
+	now := time.Now().Unix()
+
+	for _, v := range []*sgc7pb.RoundData{
+		{RoundID: "r0", Finished: true, UpdateTime: 100},
+		{RoundID: "r1", Finished: true, UpdateTime: 100, WalletState: walletStateCrediting},
+		{RoundID: "r2", UpdateTime: 100},
+		{RoundID: "r3", Finished: true, UpdateTime: now},
+	} {
+		err := store.SaveRound(v)
+		assert.NoError(t, err)
+	}
+
+	// the pending credit is kept for RecoverRounds
+	num, err := mgr.ClearFinishedRounds()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, num)
+
+	_, err = store.GetRound("r0")
+	assert.Equal(t, ErrRoundNotFound, err)
+
+	_, err = store.GetRound("r1")
+	assert.NoError(t, err)
+
+	mgr.FinishedRoundTTL = -time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mgr.StartCleanup(ctx, time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		_, err := store.GetRound("r3")
+		return err == ErrRoundNotFound
+	}, time.Second, time.Millisecond)
+
+	t.Logf("Test_RoundMgrClearFinishedRounds OK")
+}
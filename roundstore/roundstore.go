@@ -0,0 +1,55 @@
+package roundstore
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
+)
+
+// maxRoundIDLen - roundID 会被用来做文件名，所以要限制长度和字符
+const maxRoundIDLen = 128
+
+// RoundStore - persist the rounds, so an unfinished round can be resumed after the client disconnects
+//
+//	the implementations must be safe for concurrent use
+type RoundStore interface {
+	// GetRound - get a round, return ErrRoundNotFound if the round is not found
+	GetRound(roundID string) (*sgc7pb.RoundData, error)
+	// SaveRound - add or update a round
+	SaveRound(round *sgc7pb.RoundData) error
+	// DeleteRound - delete a round, it's not an error if the round is not found
+	DeleteRound(roundID string) error
+	// ClearFinishedRounds - delete the finished rounds updated before ts, return the number of deleted rounds,
+	//	the rounds with pending jackpots or wallet transactions are kept
+	ClearFinishedRounds(ts int64) (int, error)
+	// ListRounds - list all roundIDs
+	ListRounds() ([]string, error)
+}
+
+// IsValidRoundID - only [0-9a-zA-Z_-]
+func IsValidRoundID(roundID string) bool {
+	if roundID == "" || len(roundID) > maxRoundIDLen {
+		return false
+	}
+
+	for _, c := range roundID {
+		if !((c >= '0' && c <= '9') || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_' || c == '-') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// NewRoundID - new a random roundID
+func NewRoundID() string {
+	return newRandomID(16)
+}
+
+func newRandomID(n int) string {
+	buf := make([]byte, n)
+	rand.Read(buf)
+
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,192 @@
+package roundstore
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
+)
+
+// testGame - a round has 3 steps, playerState.version is the number of steps
+type testGame struct {
+	lock  sync.Mutex
+	plays int
+}
+
+func (game *testGame) onPlay(req *sgc7pb.RequestPlay) (*sgc7pb.ReplyPlay, error) {
+	game.lock.Lock()
+	game.plays++
+	game.lock.Unlock()
+
+	if req.Command == "ERROR" {
+		return nil, ErrInvalidGameCode
+	}
+
+	step := 0
+	if req.PlayerState != nil {
+		fmt.Sscanf(req.PlayerState.Version, "%d", &step)
+	}
+
+	step++
+
+	return &sgc7pb.ReplyPlay{
+		RandomNumbers: []*sgc7pb.RngInfo{{Value: int32(step)}},
+		PlayerState:   &sgc7pb.PlayerState{Version: fmt.Sprintf("%d", step)},
+		Finished:      step >= 3,
+		Results:       []*sgc7pb.GameResult{{CoinWin: int64(step), CashWin: int64(req.Stake.CashBet) * int64(step)}},
+		NextCommands:  []string{"SPIN"},
+	}, nil
+}
+
+func testRoundStore(t *testing.T, store RoundStore) {
+	_, err := store.GetRound("abc")
+	assert.Equal(t, ErrRoundNotFound, err)
+
+	err = store.SaveRound(&sgc7pb.RoundData{RoundID: "../abc"})
+	assert.Equal(t, ErrInvalidRoundID, err)
+
+	err = store.SaveRound(&sgc7pb.RoundData{RoundID: "abc", UpdateTime: 100})
+	assert.NoError(t, err)
+
+	err = store.SaveRound(&sgc7pb.RoundData{RoundID: "abd", UpdateTime: 100, Finished: true})
+	assert.NoError(t, err)
+
+	err = store.SaveRound(&sgc7pb.RoundData{RoundID: "abe", UpdateTime: 200, Finished: true})
+	assert.NoError(t, err)
+
+	round, err := store.GetRound("abc")
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", round.RoundID)
+	assert.Equal(t, int64(100), round.UpdateTime)
+
+	num, err := store.ClearFinishedRounds(150)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, num)
+
+	_, err = store.GetRound("abd")
+	assert.Equal(t, ErrRoundNotFound, err)
+
+	err = store.DeleteRound("abe")
+	assert.NoError(t, err)
+
+	err = store.DeleteRound("abe")
+	assert.NoError(t, err)
+
+	_, err = store.GetRound("abe")
+	assert.Equal(t, ErrRoundNotFound, err)
+
+	mgr := NewRoundMgr(store, nil)
+	game := &testGame{}
+	stake := &sgc7pb.Stake{CoinBet: 1, CashBet: 10, Currency: "EUR"}
+
+	// step 1, a new round
+	reply1, err := mgr.Play("game1", &sgc7pb.RequestPlay{Stake: stake, RequestID: "req1"}, game.onPlay)
+	assert.NoError(t, err)
+	roundID1, err := mgr.getRoundID(&sgc7pb.RequestPlay{RequestID: "req1"})
+	assert.NoError(t, err)
+	assert.Equal(t, roundID1, reply1.RoundID)
+	assert.False(t, reply1.Finished)
+
+	// the duplicate request returns the same reply
+	reply, err := mgr.Play("game1", &sgc7pb.RequestPlay{Stake: stake, RequestID: "req1"}, game.onPlay)
+	assert.NoError(t, err)
+	assert.Equal(t, reply1.Results[0].CoinWin, reply.Results[0].CoinWin)
+	assert.Equal(t, 1, game.plays)
+
+	_, err = mgr.Play("game2", &sgc7pb.RequestPlay{Stake: stake, RoundID: reply1.RoundID}, game.onPlay)
+	assert.Equal(t, ErrInvalidGameCode, err)
+
+	// step 2, continue with the stored playerState and stake, not the client's
+	reply2, err := mgr.Play("game1", &sgc7pb.RequestPlay{
+		Stake:       &sgc7pb.Stake{CoinBet: 1, CashBet: 1000},
+		PlayerState: &sgc7pb.PlayerState{Version: "100"},
+		RoundID:     reply1.RoundID,
+		RequestID:   "req2",
+	}, game.onPlay)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), reply2.Results[0].CoinWin)
+	assert.Equal(t, int64(20), reply2.Results[0].CashWin)
+
+	// the client disconnects, and resumes the round
+	resume, err := mgr.ResumeRound("game1", "", reply1.RoundID)
+	assert.NoError(t, err)
+	assert.False(t, resume.Finished)
+	assert.Equal(t, 2, len(resume.Results))
+	assert.Equal(t, 2, len(resume.RandomNumbers))
+	assert.Equal(t, "2", resume.PlayerState.Version)
+	assert.Equal(t, []string{"SPIN"}, resume.NextCommands)
+
+	_, err = mgr.ResumeRound("game2", "", reply1.RoundID)
+	assert.Equal(t, ErrInvalidGameCode, err)
+
+	_, err = mgr.ResumeRound("game1", "", "abcd")
+	assert.Equal(t, ErrRoundNotFound, err)
+
+	// step 3, the concurrent duplicate requests only play once
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			reply, err := mgr.Play("game1", &sgc7pb.RequestPlay{RoundID: reply1.RoundID, RequestID: "req3"}, game.onPlay)
+			assert.NoError(t, err)
+			assert.True(t, reply.Finished)
+		}()
+	}
+
+	wg.Wait()
+	assert.Equal(t, 3, game.plays)
+
+	_, err = mgr.Play("game1", &sgc7pb.RequestPlay{RoundID: reply1.RoundID, RequestID: "req4"}, game.onPlay)
+	assert.Equal(t, ErrRoundFinished, err)
+
+	resume, err = mgr.ResumeRound("game1", "", reply1.RoundID)
+	assert.NoError(t, err)
+	assert.True(t, resume.Finished)
+	assert.Equal(t, 3, len(resume.Results))
+
+	// no roundID and requestID
+	reply, err = mgr.Play("game1", &sgc7pb.RequestPlay{Stake: stake}, game.onPlay)
+	assert.NoError(t, err)
+	assert.True(t, IsValidRoundID(reply.RoundID))
+
+	_, err = mgr.Play("game1", &sgc7pb.RequestPlay{Stake: stake, RoundID: "a/b"}, game.onPlay)
+	assert.Equal(t, ErrInvalidRoundID, err)
+}
+
+func Test_MemRoundStore(t *testing.T) {
+	testRoundStore(t, NewMemRoundStore())
+
+	t.Logf("Test_MemRoundStore OK")
+}
+
+func Test_FileRoundStore(t *testing.T) {
+	dir := "../unittestdata/roundstore"
+	os.RemoveAll(dir)
+	defer os.RemoveAll(dir)
+
+	store, err := NewFileRoundStore(dir)
+	assert.NoError(t, err)
+
+	testRoundStore(t, store)
+
+	// the rounds are still there after restart
+	store1, err := NewFileRoundStore(dir)
+	assert.NoError(t, err)
+
+	mgr := NewRoundMgr(store1, nil)
+	roundID1, err := mgr.getRoundID(&sgc7pb.RequestPlay{RequestID: "req1"})
+	assert.NoError(t, err)
+
+	resume, err := mgr.ResumeRound("game1", "", roundID1)
+	assert.NoError(t, err)
+	assert.True(t, resume.Finished)
+	assert.Equal(t, 3, len(resume.Results))
+
+	t.Logf("Test_FileRoundStore OK")
+}
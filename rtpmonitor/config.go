@@ -0,0 +1,70 @@
+package rtpmonitor
+
+import (
+	"os"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	DefaultWindowRounds = 100000
+	DefaultMinRounds    = 10000
+	DefaultWarningZ     = 3
+	DefaultCriticalZ    = 5
+)
+
+// GameConfig - the theory of a game, if Stats is not empty, RTP and SD are loaded from the stats2 report
+type GameConfig struct {
+	RTP   float64 `yaml:"rtp"`
+	SD    float64 `yaml:"sd"`
+	Stats string  `yaml:"stats"`
+}
+
+// Config - configuration
+type Config struct {
+	WindowRounds int                    `yaml:"windowrounds"` // 滚动窗口里的局数
+	MinRounds    int                    `yaml:"minrounds"`    // 窗口里的局数少于这个时不报警
+	WarningZ     float64                `yaml:"warningz"`
+	CriticalZ    float64                `yaml:"criticalz"`
+	Webhook      string                 `yaml:"webhook"`
+	Games        map[string]*GameConfig `yaml:"games"`
+}
+
+// fixDefault - use the default values if they are not set
+func (cfg *Config) fixDefault() {
+	if cfg.WindowRounds <= 0 {
+		cfg.WindowRounds = DefaultWindowRounds
+	}
+
+	if cfg.MinRounds <= 0 {
+		cfg.MinRounds = DefaultMinRounds
+	}
+
+	if cfg.MinRounds > cfg.WindowRounds {
+		cfg.MinRounds = cfg.WindowRounds
+	}
+
+	if cfg.WarningZ <= 0 {
+		cfg.WarningZ = DefaultWarningZ
+	}
+
+	if cfg.CriticalZ < cfg.WarningZ {
+		cfg.CriticalZ = cfg.WarningZ
+	}
+}
+
+// LoadConfig - load configuration
+func LoadConfig(fn string) (*Config, error) {
+	data, err := os.ReadFile(fn)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{}
+	err = yaml.Unmarshal(data, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
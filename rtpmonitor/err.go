@@ -0,0 +1,12 @@
+package rtpmonitor
+
+import "errors"
+
+var (
+	// ErrInvalidTheory - invalid theoretical RTP or SD
+	ErrInvalidTheory = errors.New("invalid theoretical RTP or SD")
+	// ErrInvalidStats - the stats2 report has no bets
+	ErrInvalidStats = errors.New("invalid stats2 report")
+	// ErrNonStatusOK - the webhook does not return 200
+	ErrNonStatusOK = errors.New("non statusOK")
+)
@@ -0,0 +1,342 @@
+package rtpmonitor
+
+import (
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/zhs007/goutils"
+	"github.com/zhs007/slotsgamecore7/metrics"
+	sgc7pbutils "github.com/zhs007/slotsgamecore7/pbutils"
+	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
+)
+
+const (
+	LevelOK       = "ok"
+	LevelWarning  = "warning"
+	LevelCritical = "critical"
+)
+
+// Alert - an alert is sent when the level of a window is changed
+type Alert struct {
+	GameCode  string  `json:"gameCode"`
+	BetMethod int     `json:"betMethod"`
+	Level     string  `json:"level"`
+	PrevLevel string  `json:"prevLevel"`
+	Rounds    int     `json:"rounds"`
+	RTP       float64 `json:"rtp"` // 窗口里实际的 RTP
+	TheoryRTP float64 `json:"theoryRTP"`
+	TheorySD  float64 `json:"theorySD"`
+	ZScore    float64 `json:"z"`
+	Time      int64   `json:"time"`
+}
+
+type windowKey struct {
+	gameCode  string
+	betMethod int
+}
+
+// window - 最近 n 局的 win/bet，是一个环形缓冲
+type window struct {
+	returns    []float64
+	players    []string       // 每一局是哪个玩家的
+	pending    map[string]int // 每个玩家最后一局的位置，后续的 step 加到这一局上
+	pos        int            // 下一个要写的位置
+	num        int
+	sumReturns float64
+	level      string
+}
+
+func newWindow(rounds int) *window {
+	return &window{
+		returns: make([]float64, rounds),
+		players: make([]string, rounds),
+		pending: make(map[string]int),
+		level:   LevelOK,
+	}
+}
+
+func (w *window) push(playerID string, ret float64) {
+	if w.num == len(w.returns) {
+		w.sumReturns -= w.returns[w.pos]
+
+		// 被覆盖掉的这一局不能再加后续的赢分了
+		old := w.players[w.pos]
+		if last, isok := w.pending[old]; isok && last == w.pos {
+			delete(w.pending, old)
+		}
+	} else {
+		w.num++
+	}
+
+	w.returns[w.pos] = ret
+	w.players[w.pos] = playerID
+	w.pending[playerID] = w.pos
+	w.sumReturns += ret
+	w.pos = (w.pos + 1) % len(w.returns)
+
+	// 转完一圈重新算一次，避免浮点误差一直累积
+	if w.pos == 0 {
+		w.sumReturns = 0
+		for _, v := range w.returns {
+			w.sumReturns += v
+		}
+	}
+}
+
+// addToLast - the wins of the later steps in a round, like respin,
+//
+//	they are added to the last round of this player, and ignored if this round is out of the window
+func (w *window) addToLast(playerID string, ret float64) {
+	last, isok := w.pending[playerID]
+	if !isok {
+		return
+	}
+
+	w.returns[last] += ret
+	w.sumReturns += ret
+}
+
+func (w *window) rtp() float64 {
+	if w.num == 0 {
+		return 0
+	}
+
+	return w.sumReturns / float64(w.num)
+}
+
+func (w *window) zScore(theory *Theory) float64 {
+	if w.num == 0 {
+		return 0
+	}
+
+	return (w.rtp() - theory.RTP) / (theory.SD / math.Sqrt(float64(w.num)))
+}
+
+// Monitor - live RTP drift monitor
+//
+//	每个 gameCode + betMethod 有一个滚动窗口，窗口里的局数够了以后，用 z = (rtp - theoryRTP) / (theorySD / sqrt(n)) 判断是否偏离理论值，
+//	level 变化时（包括恢复到 ok）发一个 Alert 给所有的 Sink。没有 Theory 的游戏不统计。
+type Monitor struct {
+	cfg        *Config
+	lock       sync.Mutex
+	mapTheory  map[windowKey]*Theory // betMethod 为 0 时是这个游戏所有的 betMethod
+	mapWindows map[windowKey]*window
+	sinks      []Sink
+}
+
+// AddSink - add a sink
+func (m *Monitor) AddSink(sink Sink) {
+	m.lock.Lock()
+	m.sinks = append(m.sinks, sink)
+	m.lock.Unlock()
+}
+
+// SetTheory - set the theory of a game, betMethod is 0 for all bet methods,
+//
+//	the windows of this game are cleared
+func (m *Monitor) SetTheory(gameCode string, betMethod int, theory *Theory) error {
+	if !theory.IsValid() {
+		goutils.Error("Monitor.SetTheory",
+			slog.String("gameCode", gameCode),
+			slog.Int("betMethod", betMethod),
+			goutils.Err(ErrInvalidTheory))
+
+		return ErrInvalidTheory
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.mapTheory[windowKey{gameCode: gameCode, betMethod: betMethod}] = theory
+
+	for k := range m.mapWindows {
+		if k.gameCode == gameCode && (betMethod == 0 || k.betMethod == betMethod) {
+			delete(m.mapWindows, k)
+		}
+	}
+
+	return nil
+}
+
+func (m *Monitor) getTheory(key windowKey) *Theory {
+	theory, isok := m.mapTheory[key]
+	if isok {
+		return theory
+	}
+
+	return m.mapTheory[windowKey{gameCode: key.gameCode}]
+}
+
+// OnPlay - add a play, bet is the bet of the round, isNewRound is false for the later steps in a round,
+//
+//	the later steps are added to the last round of playerID, nil safe
+func (m *Monitor) OnPlay(gameCode string, betMethod int, playerID string, bet float64, win float64, isNewRound bool) {
+	if m == nil || bet <= 0 {
+		return
+	}
+
+	key := windowKey{gameCode: gameCode, betMethod: betMethod}
+
+	m.lock.Lock()
+
+	theory := m.getTheory(key)
+	if theory == nil {
+		m.lock.Unlock()
+
+		return
+	}
+
+	w, isok := m.mapWindows[key]
+	if !isok {
+		if !isNewRound {
+			m.lock.Unlock()
+
+			return
+		}
+
+		w = newWindow(m.cfg.WindowRounds)
+
+		m.mapWindows[key] = w
+	}
+
+	if isNewRound {
+		w.push(playerID, win/bet)
+	} else {
+		w.addToLast(playerID, win/bet)
+	}
+
+	var alert *Alert
+
+	if w.num >= m.cfg.MinRounds {
+		z := w.zScore(theory)
+
+		level := LevelOK
+		if math.Abs(z) >= m.cfg.CriticalZ {
+			level = LevelCritical
+		} else if math.Abs(z) >= m.cfg.WarningZ {
+			level = LevelWarning
+		}
+
+		if level != w.level {
+			alert = m.newAlert(key, w, theory)
+			alert.PrevLevel = w.level
+			alert.Level = level
+
+			w.level = level
+		}
+	}
+
+	sinks := m.sinks
+
+	m.lock.Unlock()
+
+	if alert != nil {
+		for _, sink := range sinks {
+			err := sink.OnAlert(alert)
+			if err != nil {
+				goutils.Error("Monitor.OnPlay:OnAlert",
+					slog.String("gameCode", gameCode),
+					slog.Int("betMethod", betMethod),
+					goutils.Err(err))
+			}
+		}
+	}
+}
+
+// OnPlayPB - add a play with protobuf, nil safe
+func (m *Monitor) OnPlayPB(gameCode string, req *sgc7pb.RequestPlay, reply *sgc7pb.ReplyPlay) {
+	if m == nil || req == nil || req.Stake == nil || reply == nil {
+		return
+	}
+
+	stake := sgc7pbutils.BuildStake(req.Stake)
+	if stake.CoinBet <= 0 {
+		return
+	}
+
+	win := int64(0)
+	for _, r := range reply.Results {
+		win += r.CashWin
+	}
+
+	m.OnPlay(gameCode, int(stake.CashBet/stake.CoinBet), req.PlayerID, float64(stake.CashBet), float64(win), metrics.IsBetCommand(req.Command))
+}
+
+func (m *Monitor) newAlert(key windowKey, w *window, theory *Theory) *Alert {
+	return &Alert{
+		GameCode:  key.gameCode,
+		BetMethod: key.betMethod,
+		Level:     w.level,
+		Rounds:    w.num,
+		RTP:       w.rtp(),
+		TheoryRTP: theory.RTP,
+		TheorySD:  theory.SD,
+		ZScore:    w.zScore(theory),
+		Time:      time.Now().Unix(),
+	}
+}
+
+// GetStatus - get the current status of a window, return nil if there is no window
+func (m *Monitor) GetStatus(gameCode string, betMethod int) *Alert {
+	key := windowKey{gameCode: gameCode, betMethod: betMethod}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	w, isok := m.mapWindows[key]
+	if !isok {
+		return nil
+	}
+
+	return m.newAlert(key, w, m.getTheory(key))
+}
+
+// NewMonitor - new a Monitor, the theories in cfg.Games are loaded, and a WebhookSink is added if cfg.Webhook is set
+func NewMonitor(cfg *Config, sinks ...Sink) (*Monitor, error) {
+	cfg.fixDefault()
+
+	m := &Monitor{
+		cfg:        cfg,
+		mapTheory:  make(map[windowKey]*Theory),
+		mapWindows: make(map[windowKey]*window),
+		sinks:      sinks,
+	}
+
+	for gameCode, gc := range cfg.Games {
+		theory := &Theory{
+			RTP: gc.RTP,
+			SD:  gc.SD,
+		}
+
+		if gc.Stats != "" {
+			t, err := LoadTheoryFromStats(gc.Stats)
+			if err != nil {
+				goutils.Error("NewMonitor:LoadTheoryFromStats",
+					slog.String("gameCode", gameCode),
+					slog.String("stats", gc.Stats),
+					goutils.Err(err))
+
+				return nil, err
+			}
+
+			theory = t
+		}
+
+		err := m.SetTheory(gameCode, 0, theory)
+		if err != nil {
+			goutils.Error("NewMonitor:SetTheory",
+				slog.String("gameCode", gameCode),
+				goutils.Err(err))
+
+			return nil, err
+		}
+	}
+
+	if cfg.Webhook != "" {
+		m.sinks = append(m.sinks, &WebhookSink{URL: cfg.Webhook})
+	}
+
+	return m, nil
+}
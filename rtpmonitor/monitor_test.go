@@ -0,0 +1,237 @@
+package rtpmonitor
+
+import (
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/bytedance/sonic"
+	"github.com/stretchr/testify/assert"
+	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
+	"github.com/zhs007/slotsgamecore7/stats2"
+)
+
+func Test_Window(t *testing.T) {
+	w := newWindow(3)
+
+	w.addToLast("p1", 1)
+	assert.Equal(t, 0, w.num)
+
+	w.push("p1", 1)
+	w.push("p1", 0)
+	w.addToLast("p1", 2)
+	assert.Equal(t, 2, w.num)
+	assert.Equal(t, 1.5, w.rtp())
+
+	w.push("p1", 3)
+	w.push("p1", 0)
+	assert.Equal(t, 3, w.num)
+	assert.Equal(t, []float64{0, 2, 3}, w.returns)
+	assert.Equal(t, 5.0/3, w.rtp())
+
+	theory := &Theory{RTP: 1, SD: 1}
+	assert.InDelta(t, (5.0/3-1)*math.Sqrt(3), w.zScore(theory), 1e-9)
+
+	t.Logf("Test_Window OK")
+}
+
+func Test_WindowPlayers(t *testing.T) {
+	w := newWindow(3)
+
+	// p1 的 respin 在 p2 开始新的一局以后才到，要加到 p1 的那一局上
+	w.push("p1", 0)
+	w.push("p2", 1)
+	w.addToLast("p1", 2)
+	assert.Equal(t, []float64{2, 1, 0}, w.returns)
+
+	w.addToLast("p3", 5)
+	assert.Equal(t, []float64{2, 1, 0}, w.returns)
+	assert.Equal(t, 1.5, w.rtp())
+
+	// p1 开始新的一局以后，后续的赢分加到新的一局上
+	w.push("p1", 0)
+	w.addToLast("p1", 1)
+	w.addToLast("p2", 1)
+	assert.Equal(t, []float64{2, 2, 1}, w.returns)
+
+	// p2 的那一局被挤出窗口以后，后续的赢分就不要了
+	w.push("p3", 0)
+	w.push("p3", 0)
+	assert.Equal(t, []float64{0, 0, 1}, w.returns)
+
+	w.addToLast("p2", 10)
+	assert.Equal(t, []float64{0, 0, 1}, w.returns)
+	assert.InDelta(t, 1.0/3, w.rtp(), 1e-9)
+
+	_, isok := w.pending["p2"]
+	assert.False(t, isok)
+
+	w.addToLast("p1", 1)
+	assert.Equal(t, []float64{0, 0, 2}, w.returns)
+
+	t.Logf("Test_WindowPlayers OK")
+}
+
+func Test_Monitor(t *testing.T) {
+	var nilMonitor *Monitor
+	nilMonitor.OnPlay("game1", 1, "p1", 10, 10, true)
+
+	sink := NewMemSink()
+
+	m, err := NewMonitor(&Config{
+		WindowRounds: 100,
+		MinRounds:    10,
+		WarningZ:     2,
+		CriticalZ:    4,
+	}, sink)
+	assert.NoError(t, err)
+
+	assert.Equal(t, ErrInvalidTheory, m.SetTheory("game1", 0, &Theory{RTP: 0.96}))
+
+	err = m.SetTheory("game1", 0, &Theory{RTP: 1, SD: 1})
+	assert.NoError(t, err)
+
+	// 没有 theory 的游戏不统计
+	m.OnPlay("game2", 10, "p1", 10, 0, true)
+	assert.Nil(t, m.GetStatus("game2", 10))
+
+	// 少于 MinRounds 时不报警
+	for i := 0; i < 9; i++ {
+		m.OnPlay("game1", 10, "p1", 10, 0, true)
+	}
+
+	assert.Equal(t, 0, len(sink.GetAlerts()))
+	assert.Equal(t, 9, m.GetStatus("game1", 10).Rounds)
+
+	// 10 局都是 0，z = -1 / (1 / sqrt(10)) = -3.16
+	m.OnPlay("game1", 10, "p1", 10, 0, true)
+
+	alerts := sink.GetAlerts()
+	assert.Equal(t, 1, len(alerts))
+	assert.Equal(t, LevelWarning, alerts[0].Level)
+	assert.Equal(t, LevelOK, alerts[0].PrevLevel)
+	assert.Equal(t, "game1", alerts[0].GameCode)
+	assert.Equal(t, 10, alerts[0].BetMethod)
+	assert.InDelta(t, -math.Sqrt(10), alerts[0].ZScore, 1e-9)
+
+	// level 没变时不会再发
+	m.OnPlay("game1", 10, "p1", 10, 0, true)
+	assert.Equal(t, 1, len(sink.GetAlerts()))
+
+	for i := 0; i < 10; i++ {
+		m.OnPlay("game1", 10, "p1", 10, 0, true)
+	}
+
+	alerts = sink.GetAlerts()
+	assert.Equal(t, 2, len(alerts))
+	assert.Equal(t, LevelCritical, alerts[1].Level)
+	assert.Equal(t, LevelWarning, alerts[1].PrevLevel)
+
+	// respin 的赢分加到最后一局上，恢复以后会发一个 ok
+	m.OnPlay("game1", 10, "p1", 10, 210, false)
+
+	alerts = sink.GetAlerts()
+	assert.Equal(t, 3, len(alerts))
+	assert.Equal(t, LevelOK, alerts[2].Level)
+	assert.Equal(t, 21, alerts[2].Rounds)
+	assert.Equal(t, 1.0, alerts[2].RTP)
+
+	// 另一个 betMethod 有单独的窗口
+	assert.Nil(t, m.GetStatus("game1", 20))
+
+	m.OnPlayPB("game1", &sgc7pb.RequestPlay{
+		Stake: &sgc7pb.Stake{CoinBet: 1, CashBet: 20, Currency: "EUR"},
+	}, &sgc7pb.ReplyPlay{
+		Results: []*sgc7pb.GameResult{{CashWin: 10}, {CashWin: 20}},
+	})
+
+	status := m.GetStatus("game1", 20)
+	assert.Equal(t, 1, status.Rounds)
+	assert.Equal(t, 1.5, status.RTP)
+
+	// 新的 theory 会清掉窗口
+	err = m.SetTheory("game1", 20, &Theory{RTP: 0.9, SD: 2})
+	assert.NoError(t, err)
+	assert.Nil(t, m.GetStatus("game1", 20))
+	assert.NotNil(t, m.GetStatus("game1", 10))
+
+	t.Logf("Test_Monitor OK")
+}
+
+func Test_Theory(t *testing.T) {
+	_, err := NewTheoryFromStats(stats2.NewStats(nil))
+	assert.Equal(t, ErrInvalidStats, err)
+
+	s2 := stats2.NewStats(nil)
+	s2.TotalBet = 400
+	s2.BetTimes = 4
+	s2.TotalWins = 300
+	s2.Wins.AddWin(0)
+	s2.Wins.AddWin(0)
+	s2.Wins.AddWin(100)
+	s2.Wins.AddWin(200)
+
+	theory, err := NewTheoryFromStats(s2)
+	assert.NoError(t, err)
+	assert.Equal(t, 0.75, theory.RTP)
+	assert.Equal(t, s2.Wins.CalcSD(100), theory.SD)
+
+	dir := t.TempDir()
+	statsfn := path.Join(dir, "stats.json")
+	err = os.WriteFile(statsfn, []byte(s2.ToJson()), 0644)
+	assert.NoError(t, err)
+
+	cfgfn := path.Join(dir, "rtpmonitor.yaml")
+	err = os.WriteFile(cfgfn, []byte("windowrounds: 1000\ngames:\n  game1:\n    stats: "+statsfn+"\n  game2:\n    rtp: 0.96\n    sd: 5\n"), 0644)
+	assert.NoError(t, err)
+
+	cfg, err := LoadConfig(cfgfn)
+	assert.NoError(t, err)
+
+	m, err := NewMonitor(cfg)
+	assert.NoError(t, err)
+	assert.Equal(t, 1000, cfg.WindowRounds)
+	// MinRounds 的默认值比窗口大，会被限制成窗口的大小
+	assert.Equal(t, 1000, cfg.MinRounds)
+	assert.Equal(t, 0.75, m.getTheory(windowKey{gameCode: "game1", betMethod: 10}).RTP)
+	assert.Equal(t, 5.0, m.getTheory(windowKey{gameCode: "game2"}).SD)
+
+	t.Logf("Test_Theory OK")
+}
+
+func Test_WebhookSink(t *testing.T) {
+	var received *Alert
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = &Alert{}
+
+		err := sonic.ConfigDefault.NewDecoder(r.Body).Decode(received)
+		assert.NoError(t, err)
+	}))
+	defer ts.Close()
+
+	m, err := NewMonitor(&Config{
+		WindowRounds: 10,
+		MinRounds:    1,
+		Webhook:      ts.URL,
+		Games: map[string]*GameConfig{
+			"game1": {RTP: 1, SD: 0.1},
+		},
+	})
+	assert.NoError(t, err)
+
+	m.OnPlay("game1", 1, "p1", 10, 0, true)
+
+	assert.NotNil(t, received)
+	assert.Equal(t, LevelCritical, received.Level)
+	assert.Equal(t, "game1", received.GameCode)
+
+	sink := &WebhookSink{URL: ts.URL + "/404"}
+	ts.Config.Handler = http.NotFoundHandler()
+	assert.Equal(t, ErrNonStatusOK, sink.OnAlert(received))
+
+	t.Logf("Test_WebhookSink OK")
+}
@@ -0,0 +1,97 @@
+package rtpmonitor
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/zhs007/goutils"
+	sgc7http "github.com/zhs007/slotsgamecore7/http"
+)
+
+// Sink - the alerts are sent to sinks
+type Sink interface {
+	// OnAlert - it is called without any lock of Monitor, but it should not block for long
+	OnAlert(alert *Alert) error
+}
+
+// LogSink - write the alerts in log
+type LogSink struct{}
+
+// OnAlert - Sink
+func (sink *LogSink) OnAlert(alert *Alert) error {
+	attrs := []slog.Attr{
+		slog.String("gameCode", alert.GameCode),
+		slog.Int("betMethod", alert.BetMethod),
+		slog.String("level", alert.Level),
+		slog.String("prevLevel", alert.PrevLevel),
+		slog.Int("rounds", alert.Rounds),
+		slog.Float64("rtp", alert.RTP),
+		slog.Float64("theoryRTP", alert.TheoryRTP),
+		slog.Float64("theorySD", alert.TheorySD),
+		slog.Float64("z", alert.ZScore),
+	}
+
+	if alert.Level == LevelOK {
+		goutils.Info("rtpmonitor.LogSink.OnAlert", attrs...)
+	} else {
+		goutils.Warn("rtpmonitor.LogSink.OnAlert", attrs...)
+	}
+
+	return nil
+}
+
+// MemSink - keep the alerts in memory, it is used for test or as a stand-in for webhook
+type MemSink struct {
+	lock   sync.Mutex
+	alerts []*Alert
+}
+
+// OnAlert - Sink
+func (sink *MemSink) OnAlert(alert *Alert) error {
+	sink.lock.Lock()
+	sink.alerts = append(sink.alerts, alert)
+	sink.lock.Unlock()
+
+	return nil
+}
+
+// GetAlerts - get all alerts
+func (sink *MemSink) GetAlerts() []*Alert {
+	sink.lock.Lock()
+	defer sink.lock.Unlock()
+
+	return append([]*Alert{}, sink.alerts...)
+}
+
+// NewMemSink - new a MemSink
+func NewMemSink() *MemSink {
+	return &MemSink{}
+}
+
+// WebhookSink - post the alerts as json
+type WebhookSink struct {
+	URL string
+}
+
+// OnAlert - Sink
+func (sink *WebhookSink) OnAlert(alert *Alert) error {
+	sc, _, err := sgc7http.HTTPPost(sink.URL, nil, alert)
+	if err != nil {
+		goutils.Error("rtpmonitor.WebhookSink.OnAlert:HTTPPost",
+			slog.String("url", sink.URL),
+			goutils.Err(err))
+
+		return err
+	}
+
+	if sc != 200 {
+		goutils.Error("rtpmonitor.WebhookSink.OnAlert:HTTPPost",
+			slog.String("url", sink.URL),
+			slog.Int("statusCode", sc),
+			goutils.Err(ErrNonStatusOK))
+
+		return ErrNonStatusOK
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package rtpmonitor
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/zhs007/goutils"
+	"github.com/zhs007/slotsgamecore7/stats2"
+)
+
+// Theory - theoretical RTP and SD, SD is the standard deviation of win/bet in a round
+type Theory struct {
+	RTP float64 `yaml:"rtp" json:"rtp"`
+	SD  float64 `yaml:"sd" json:"sd"`
+}
+
+// IsValid - SD must be positive
+func (theory *Theory) IsValid() bool {
+	return theory != nil && theory.RTP > 0 && theory.SD > 0
+}
+
+// NewTheoryFromStats - get the theoretical RTP and SD from a stats2 report
+func NewTheoryFromStats(s2 *stats2.Stats) (*Theory, error) {
+	if s2 == nil || s2.TotalBet <= 0 || s2.BetTimes <= 0 || s2.Wins == nil {
+		goutils.Error("NewTheoryFromStats",
+			goutils.Err(ErrInvalidStats))
+
+		return nil, ErrInvalidStats
+	}
+
+	theory := &Theory{
+		RTP: float64(s2.TotalWins) / float64(s2.TotalBet),
+		SD:  s2.Wins.CalcSD(int(s2.TotalBet / s2.BetTimes)),
+	}
+
+	if !theory.IsValid() {
+		goutils.Error("NewTheoryFromStats",
+			slog.Float64("rtp", theory.RTP),
+			slog.Float64("sd", theory.SD),
+			goutils.Err(ErrInvalidTheory))
+
+		return nil, ErrInvalidTheory
+	}
+
+	return theory, nil
+}
+
+// LoadTheoryFromStats - load a stats2 report (json) and get the theoretical RTP and SD
+func LoadTheoryFromStats(fn string) (*Theory, error) {
+	data, err := os.ReadFile(fn)
+	if err != nil {
+		goutils.Error("LoadTheoryFromStats:ReadFile",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	s2, err := stats2.LoadStats(string(data))
+	if err != nil {
+		goutils.Error("LoadTheoryFromStats:LoadStats",
+			slog.String("fn", fn),
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	return NewTheoryFromStats(s2)
+}
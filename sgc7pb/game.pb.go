@@ -607,9 +607,11 @@ func (*RequestConfig) Descriptor() ([]byte, []int) {
 
 // PlayerState
 type PlayerState struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Public        *anypb.Any             `protobuf:"bytes,1,opt,name=public,proto3" json:"public,omitempty"`
-	Private       *anypb.Any             `protobuf:"bytes,2,opt,name=private,proto3" json:"private,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Public  *anypb.Any             `protobuf:"bytes,1,opt,name=public,proto3" json:"public,omitempty"`
+	Private *anypb.Any             `protobuf:"bytes,2,opt,name=private,proto3" json:"private,omitempty"`
+	// version - the game config version of the unfinished round, empty means the active version
+	Version       string `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -658,6 +660,13 @@ func (x *PlayerState) GetPrivate() *anypb.Any {
 	return nil
 }
 
+func (x *PlayerState) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
 // RequestInitialize
 type RequestInitialize struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -781,8 +790,14 @@ type RequestPlay struct {
 	Command           string                 `protobuf:"bytes,5,opt,name=command,proto3" json:"command,omitempty"`
 	JackpotStakeValue int64                  `protobuf:"varint,6,opt,name=jackpotStakeValue,proto3" json:"jackpotStakeValue,omitempty"`
 	FreespinsActive   bool                   `protobuf:"varint,7,opt,name=freespinsActive,proto3" json:"freespinsActive,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	// roundID - the round to continue, empty means a new round
+	RoundID string `protobuf:"bytes,8,opt,name=roundID,proto3" json:"roundID,omitempty"`
+	// requestID - the duplicate requests with the same requestID return the same reply
+	RequestID string `protobuf:"bytes,9,opt,name=requestID,proto3" json:"requestID,omitempty"`
+	// playerID - the player in wallet
+	PlayerID      string `protobuf:"bytes,10,opt,name=playerID,proto3" json:"playerID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RequestPlay) Reset() {
@@ -864,6 +879,27 @@ func (x *RequestPlay) GetFreespinsActive() bool {
 	return false
 }
 
+func (x *RequestPlay) GetRoundID() string {
+	if x != nil {
+		return x.RoundID
+	}
+	return ""
+}
+
+func (x *RequestPlay) GetRequestID() string {
+	if x != nil {
+		return x.RequestID
+	}
+	return ""
+}
+
+func (x *RequestPlay) GetPlayerID() string {
+	if x != nil {
+		return x.PlayerID
+	}
+	return ""
+}
+
 // RngInfo - rng infomation
 type RngInfo struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -1341,8 +1377,12 @@ type ReplyPlay struct {
 	// Deprecated: Marked as deprecated in game.proto.
 	Stake             *Stake   `protobuf:"bytes,6,opt,name=stake,proto3" json:"stake,omitempty"`
 	NextCommandParams []string `protobuf:"bytes,7,rep,name=nextCommandParams,proto3" json:"nextCommandParams,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	// roundID - only when the server has a RoundStore
+	RoundID string `protobuf:"bytes,8,opt,name=roundID,proto3" json:"roundID,omitempty"`
+	// balance - only when the server has a Wallet
+	Balance       int64 `protobuf:"varint,9,opt,name=balance,proto3" json:"balance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ReplyPlay) Reset() {
@@ -1425,6 +1465,279 @@ func (x *ReplyPlay) GetNextCommandParams() []string {
 	return nil
 }
 
+func (x *ReplyPlay) GetRoundID() string {
+	if x != nil {
+		return x.RoundID
+	}
+	return ""
+}
+
+func (x *ReplyPlay) GetBalance() int64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+// RequestResumeRound - resume an unfinished round
+type RequestResumeRound struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	RoundID string                 `protobuf:"bytes,1,opt,name=roundID,proto3" json:"roundID,omitempty"`
+	// playerID - must be the player of the round
+	PlayerID      string `protobuf:"bytes,2,opt,name=playerID,proto3" json:"playerID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestResumeRound) Reset() {
+	*x = RequestResumeRound{}
+	mi := &file_game_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestResumeRound) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestResumeRound) ProtoMessage() {}
+
+func (x *RequestResumeRound) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestResumeRound.ProtoReflect.Descriptor instead.
+func (*RequestResumeRound) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RequestResumeRound) GetRoundID() string {
+	if x != nil {
+		return x.RoundID
+	}
+	return ""
+}
+
+func (x *RequestResumeRound) GetPlayerID() string {
+	if x != nil {
+		return x.PlayerID
+	}
+	return ""
+}
+
+// RoundData - the data of a round saved in RoundStore
+type RoundData struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	RoundID           string                 `protobuf:"bytes,1,opt,name=roundID,proto3" json:"roundID,omitempty"`
+	GameCode          string                 `protobuf:"bytes,2,opt,name=gameCode,proto3" json:"gameCode,omitempty"`
+	Stake             *Stake                 `protobuf:"bytes,3,opt,name=stake,proto3" json:"stake,omitempty"`
+	StartPlayerState  *PlayerState           `protobuf:"bytes,4,opt,name=startPlayerState,proto3" json:"startPlayerState,omitempty"` // the player state before this round
+	PlayerState       *PlayerState           `protobuf:"bytes,5,opt,name=playerState,proto3" json:"playerState,omitempty"`           // the latest player state
+	Results           []*GameResult          `protobuf:"bytes,6,rep,name=results,proto3" json:"results,omitempty"`                   // all results so far
+	RandomNumbers     []*RngInfo             `protobuf:"bytes,7,rep,name=randomNumbers,proto3" json:"randomNumbers,omitempty"`       // all used rngs so far
+	Finished          bool                   `protobuf:"varint,8,opt,name=finished,proto3" json:"finished,omitempty"`
+	NextCommands      []string               `protobuf:"bytes,9,rep,name=nextCommands,proto3" json:"nextCommands,omitempty"`
+	NextCommandParams []string               `protobuf:"bytes,10,rep,name=nextCommandParams,proto3" json:"nextCommandParams,omitempty"`
+	Requests          map[string]*ReplyPlay  `protobuf:"bytes,11,rep,name=requests,proto3" json:"requests,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // requestID -> reply
+	UpdateTime        int64                  `protobuf:"varint,12,opt,name=updateTime,proto3" json:"updateTime,omitempty"`
+	PlayerID          string                 `protobuf:"bytes,13,opt,name=playerID,proto3" json:"playerID,omitempty"`
+	WalletState       int32                  `protobuf:"varint,14,opt,name=walletState,proto3" json:"walletState,omitempty"`           // the pending wallet transaction
+	PendingCredit     int64                  `protobuf:"varint,15,opt,name=pendingCredit,proto3" json:"pendingCredit,omitempty"`       // the win to credit
+	CreditNum         int32                  `protobuf:"varint,16,opt,name=creditNum,proto3" json:"creditNum,omitempty"`               // the number of credit transactions
+	Balance           int64                  `protobuf:"varint,17,opt,name=balance,proto3" json:"balance,omitempty"`                   // the latest balance
+	WalletTxID        string                 `protobuf:"bytes,18,opt,name=walletTxID,proto3" json:"walletTxID,omitempty"`              // the prefix of wallet txID, a new one for each attempt of round
+	IsPendingJackpot  bool                   `protobuf:"varint,19,opt,name=isPendingJackpot,proto3" json:"isPendingJackpot,omitempty"` // the results of the last step are saved, the jackpot is not processed
+	JackpotOffset     int32                  `protobuf:"varint,20,opt,name=jackpotOffset,proto3" json:"jackpotOffset,omitempty"`       // the index of the first result of the last step
+	JackpotRequestID  string                 `protobuf:"bytes,21,opt,name=jackpotRequestID,proto3" json:"jackpotRequestID,omitempty"`  // the requestID of the last step, its reply is updated with the jackpot
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *RoundData) Reset() {
+	*x = RoundData{}
+	mi := &file_game_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoundData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoundData) ProtoMessage() {}
+
+func (x *RoundData) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoundData.ProtoReflect.Descriptor instead.
+func (*RoundData) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RoundData) GetRoundID() string {
+	if x != nil {
+		return x.RoundID
+	}
+	return ""
+}
+
+func (x *RoundData) GetGameCode() string {
+	if x != nil {
+		return x.GameCode
+	}
+	return ""
+}
+
+func (x *RoundData) GetStake() *Stake {
+	if x != nil {
+		return x.Stake
+	}
+	return nil
+}
+
+func (x *RoundData) GetStartPlayerState() *PlayerState {
+	if x != nil {
+		return x.StartPlayerState
+	}
+	return nil
+}
+
+func (x *RoundData) GetPlayerState() *PlayerState {
+	if x != nil {
+		return x.PlayerState
+	}
+	return nil
+}
+
+func (x *RoundData) GetResults() []*GameResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *RoundData) GetRandomNumbers() []*RngInfo {
+	if x != nil {
+		return x.RandomNumbers
+	}
+	return nil
+}
+
+func (x *RoundData) GetFinished() bool {
+	if x != nil {
+		return x.Finished
+	}
+	return false
+}
+
+func (x *RoundData) GetNextCommands() []string {
+	if x != nil {
+		return x.NextCommands
+	}
+	return nil
+}
+
+func (x *RoundData) GetNextCommandParams() []string {
+	if x != nil {
+		return x.NextCommandParams
+	}
+	return nil
+}
+
+func (x *RoundData) GetRequests() map[string]*ReplyPlay {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+func (x *RoundData) GetUpdateTime() int64 {
+	if x != nil {
+		return x.UpdateTime
+	}
+	return 0
+}
+
+func (x *RoundData) GetPlayerID() string {
+	if x != nil {
+		return x.PlayerID
+	}
+	return ""
+}
+
+func (x *RoundData) GetWalletState() int32 {
+	if x != nil {
+		return x.WalletState
+	}
+	return 0
+}
+
+func (x *RoundData) GetPendingCredit() int64 {
+	if x != nil {
+		return x.PendingCredit
+	}
+	return 0
+}
+
+func (x *RoundData) GetCreditNum() int32 {
+	if x != nil {
+		return x.CreditNum
+	}
+	return 0
+}
+
+func (x *RoundData) GetBalance() int64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *RoundData) GetWalletTxID() string {
+	if x != nil {
+		return x.WalletTxID
+	}
+	return ""
+}
+
+func (x *RoundData) GetIsPendingJackpot() bool {
+	if x != nil {
+		return x.IsPendingJackpot
+	}
+	return false
+}
+
+func (x *RoundData) GetJackpotOffset() int32 {
+	if x != nil {
+		return x.JackpotOffset
+	}
+	return 0
+}
+
+func (x *RoundData) GetJackpotRequestID() string {
+	if x != nil {
+		return x.JackpotRequestID
+	}
+	return ""
+}
+
 var File_game_proto protoreflect.FileDescriptor
 
 const file_game_proto_rawDesc = "" +
@@ -1474,17 +1787,18 @@ const file_game_proto_rawDesc = "" +
 	"\x0ePayTablesEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\x05R\x03key\x12!\n" +
 	"\x05value\x18\x02 \x01(\v2\v.sgc7pb.RowR\x05value:\x028\x01\"\x0f\n" +
-	"\rRequestConfig\"k\n" +
+	"\rRequestConfig\"\x85\x01\n" +
 	"\vPlayerState\x12,\n" +
 	"\x06public\x18\x01 \x01(\v2\x14.google.protobuf.AnyR\x06public\x12.\n" +
-	"\aprivate\x18\x02 \x01(\v2\x14.google.protobuf.AnyR\aprivate\"\x13\n" +
+	"\aprivate\x18\x02 \x01(\v2\x14.google.protobuf.AnyR\aprivate\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\"\x13\n" +
 	"\x11RequestInitialize\"\x93\x01\n" +
 	"\x05Stake\x12\x18\n" +
 	"\acoinBet\x18\x01 \x01(\x05R\acoinBet\x12\x18\n" +
 	"\acashBet\x18\x02 \x01(\x05R\acashBet\x12\x1a\n" +
 	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x12\x1c\n" +
 	"\tcoinBet64\x18\x04 \x01(\x03R\tcoinBet64\x12\x1c\n" +
-	"\tcashBet64\x18\x05 \x01(\x03R\tcashBet64\"\x95\x02\n" +
+	"\tcashBet64\x18\x05 \x01(\x03R\tcashBet64\"\xe9\x02\n" +
 	"\vRequestPlay\x125\n" +
 	"\vplayerState\x18\x01 \x01(\v2\x13.sgc7pb.PlayerStateR\vplayerState\x12\x14\n" +
 	"\x05cheat\x18\x02 \x01(\tR\x05cheat\x12#\n" +
@@ -1492,7 +1806,11 @@ const file_game_proto_rawDesc = "" +
 	"\fclientParams\x18\x04 \x01(\tR\fclientParams\x12\x18\n" +
 	"\acommand\x18\x05 \x01(\tR\acommand\x12,\n" +
 	"\x11jackpotStakeValue\x18\x06 \x01(\x03R\x11jackpotStakeValue\x12(\n" +
-	"\x0ffreespinsActive\x18\a \x01(\bR\x0ffreespinsActive\"I\n" +
+	"\x0ffreespinsActive\x18\a \x01(\bR\x0ffreespinsActive\x12\x18\n" +
+	"\aroundID\x18\b \x01(\tR\aroundID\x12\x1c\n" +
+	"\trequestID\x18\t \x01(\tR\trequestID\x12\x1a\n" +
+	"\bplayerID\x18\n" +
+	" \x01(\tR\bplayerID\"I\n" +
 	"\aRngInfo\x12\x12\n" +
 	"\x04bits\x18\x01 \x01(\x05R\x04bits\x12\x14\n" +
 	"\x05range\x18\x02 \x01(\x05R\x05range\x12\x14\n" +
@@ -1546,7 +1864,7 @@ const file_game_proto_rawDesc = "" +
 	"\acashWin\x18\x02 \x01(\x03R\acashWin\x122\n" +
 	"\n" +
 	"clientData\x18\x03 \x01(\v2\x12.sgc7pb.PlayResultR\n" +
-	"clientData\"\xbe\x02\n" +
+	"clientData\"\xf2\x02\n" +
 	"\tReplyPlay\x125\n" +
 	"\rrandomNumbers\x18\x01 \x03(\v2\x0f.sgc7pb.RngInfoR\rrandomNumbers\x125\n" +
 	"\vplayerState\x18\x02 \x01(\v2\x13.sgc7pb.PlayerStateR\vplayerState\x12\x1a\n" +
@@ -1554,19 +1872,55 @@ const file_game_proto_rawDesc = "" +
 	"\aresults\x18\x04 \x03(\v2\x12.sgc7pb.GameResultR\aresults\x12\"\n" +
 	"\fnextCommands\x18\x05 \x03(\tR\fnextCommands\x12'\n" +
 	"\x05stake\x18\x06 \x01(\v2\r.sgc7pb.StakeB\x02\x18\x01R\x05stake\x12,\n" +
-	"\x11nextCommandParams\x18\a \x03(\tR\x11nextCommandParams2\xee\x01\n" +
+	"\x11nextCommandParams\x18\a \x03(\tR\x11nextCommandParams\x12\x18\n" +
+	"\aroundID\x18\b \x01(\tR\aroundID\x12\x18\n" +
+	"\abalance\x18\t \x01(\x03R\abalance\"J\n" +
+	"\x12RequestResumeRound\x12\x18\n" +
+	"\aroundID\x18\x01 \x01(\tR\aroundID\x12\x1a\n" +
+	"\bplayerID\x18\x02 \x01(\tR\bplayerID\"\x98\a\n" +
+	"\tRoundData\x12\x18\n" +
+	"\aroundID\x18\x01 \x01(\tR\aroundID\x12\x1a\n" +
+	"\bgameCode\x18\x02 \x01(\tR\bgameCode\x12#\n" +
+	"\x05stake\x18\x03 \x01(\v2\r.sgc7pb.StakeR\x05stake\x12?\n" +
+	"\x10startPlayerState\x18\x04 \x01(\v2\x13.sgc7pb.PlayerStateR\x10startPlayerState\x125\n" +
+	"\vplayerState\x18\x05 \x01(\v2\x13.sgc7pb.PlayerStateR\vplayerState\x12,\n" +
+	"\aresults\x18\x06 \x03(\v2\x12.sgc7pb.GameResultR\aresults\x125\n" +
+	"\rrandomNumbers\x18\a \x03(\v2\x0f.sgc7pb.RngInfoR\rrandomNumbers\x12\x1a\n" +
+	"\bfinished\x18\b \x01(\bR\bfinished\x12\"\n" +
+	"\fnextCommands\x18\t \x03(\tR\fnextCommands\x12,\n" +
+	"\x11nextCommandParams\x18\n" +
+	" \x03(\tR\x11nextCommandParams\x12;\n" +
+	"\brequests\x18\v \x03(\v2\x1f.sgc7pb.RoundData.RequestsEntryR\brequests\x12\x1e\n" +
+	"\n" +
+	"updateTime\x18\f \x01(\x03R\n" +
+	"updateTime\x12\x1a\n" +
+	"\bplayerID\x18\r \x01(\tR\bplayerID\x12 \n" +
+	"\vwalletState\x18\x0e \x01(\x05R\vwalletState\x12$\n" +
+	"\rpendingCredit\x18\x0f \x01(\x03R\rpendingCredit\x12\x1c\n" +
+	"\tcreditNum\x18\x10 \x01(\x05R\tcreditNum\x12\x18\n" +
+	"\abalance\x18\x11 \x01(\x03R\abalance\x12\x1e\n" +
+	"\n" +
+	"walletTxID\x18\x12 \x01(\tR\n" +
+	"walletTxID\x12*\n" +
+	"\x10isPendingJackpot\x18\x13 \x01(\bR\x10isPendingJackpot\x12$\n" +
+	"\rjackpotOffset\x18\x14 \x01(\x05R\rjackpotOffset\x12*\n" +
+	"\x10jackpotRequestID\x18\x15 \x01(\tR\x10jackpotRequestID\x1aN\n" +
+	"\rRequestsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12'\n" +
+	"\x05value\x18\x02 \x01(\v2\x11.sgc7pb.ReplyPlayR\x05value:\x028\x012\xee\x01\n" +
 	"\vDTGameLogic\x128\n" +
 	"\tgetConfig\x12\x15.sgc7pb.RequestConfig\x1a\x12.sgc7pb.GameConfig\"\x00\x12>\n" +
 	"\n" +
 	"initialize\x12\x19.sgc7pb.RequestInitialize\x1a\x13.sgc7pb.PlayerState\"\x00\x122\n" +
 	"\x04play\x12\x13.sgc7pb.RequestPlay\x1a\x11.sgc7pb.ReplyPlay\"\x000\x01\x121\n" +
-	"\x05play2\x12\x13.sgc7pb.RequestPlay\x1a\x11.sgc7pb.ReplyPlay\"\x002\xec\x01\n" +
+	"\x05play2\x12\x13.sgc7pb.RequestPlay\x1a\x11.sgc7pb.ReplyPlay\"\x002\xac\x02\n" +
 	"\tGameLogic\x128\n" +
 	"\tgetConfig\x12\x15.sgc7pb.RequestConfig\x1a\x12.sgc7pb.GameConfig\"\x00\x12>\n" +
 	"\n" +
 	"initialize\x12\x19.sgc7pb.RequestInitialize\x1a\x13.sgc7pb.PlayerState\"\x00\x122\n" +
 	"\x04play\x12\x13.sgc7pb.RequestPlay\x1a\x11.sgc7pb.ReplyPlay\"\x000\x01\x121\n" +
-	"\x05play2\x12\x13.sgc7pb.RequestPlay\x1a\x11.sgc7pb.ReplyPlay\"\x00B)Z'github.com/zhs007/slotsgamecore7/sgc7pbb\x06proto3"
+	"\x05play2\x12\x13.sgc7pb.RequestPlay\x1a\x11.sgc7pb.ReplyPlay\"\x00\x12>\n" +
+	"\vresumeRound\x12\x1a.sgc7pb.RequestResumeRound\x1a\x11.sgc7pb.ReplyPlay\"\x00B)Z'github.com/zhs007/slotsgamecore7/sgc7pbb\x06proto3"
 
 var (
 	file_game_proto_rawDescOnce sync.Once
@@ -1580,7 +1934,7 @@ func file_game_proto_rawDescGZIP() []byte {
 	return file_game_proto_rawDescData
 }
 
-var file_game_proto_msgTypes = make([]protoimpl.MessageInfo, 24)
+var file_game_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
 var file_game_proto_goTypes = []any{
 	(*Column)(nil),                   // 0: sgc7pb.Column
 	(*Row)(nil),                      // 1: sgc7pb.Row
@@ -1603,60 +1957,72 @@ var file_game_proto_goTypes = []any{
 	(*PlayResult)(nil),               // 18: sgc7pb.PlayResult
 	(*GameResult)(nil),               // 19: sgc7pb.GameResult
 	(*ReplyPlay)(nil),                // 20: sgc7pb.ReplyPlay
-	nil,                              // 21: sgc7pb.GameConfig.ReelsEntry
-	nil,                              // 22: sgc7pb.GameConfig.PayTablesEntry
-	nil,                              // 23: sgc7pb.PlayResult.SpGridEntry
-	(*anypb.Any)(nil),                // 24: google.protobuf.Any
+	(*RequestResumeRound)(nil),       // 21: sgc7pb.RequestResumeRound
+	(*RoundData)(nil),                // 22: sgc7pb.RoundData
+	nil,                              // 23: sgc7pb.GameConfig.ReelsEntry
+	nil,                              // 24: sgc7pb.GameConfig.PayTablesEntry
+	nil,                              // 25: sgc7pb.PlayResult.SpGridEntry
+	nil,                              // 26: sgc7pb.RoundData.RequestsEntry
+	(*anypb.Any)(nil),                // 27: google.protobuf.Any
 }
 var file_game_proto_depIdxs = []int32{
 	1,  // 0: sgc7pb.LinesData.lines:type_name -> sgc7pb.Row
 	0,  // 1: sgc7pb.ReelsData.reels:type_name -> sgc7pb.Column
 	0,  // 2: sgc7pb.GameScene.values:type_name -> sgc7pb.Column
 	2,  // 3: sgc7pb.GameConfig.lines:type_name -> sgc7pb.LinesData
-	21, // 4: sgc7pb.GameConfig.reels:type_name -> sgc7pb.GameConfig.ReelsEntry
-	22, // 5: sgc7pb.GameConfig.payTables:type_name -> sgc7pb.GameConfig.PayTablesEntry
+	23, // 4: sgc7pb.GameConfig.reels:type_name -> sgc7pb.GameConfig.ReelsEntry
+	24, // 5: sgc7pb.GameConfig.payTables:type_name -> sgc7pb.GameConfig.PayTablesEntry
 	4,  // 6: sgc7pb.GameConfig.defaultScene:type_name -> sgc7pb.GameScene
 	4,  // 7: sgc7pb.GameConfig.defaultScene2:type_name -> sgc7pb.GameScene
-	24, // 8: sgc7pb.PlayerState.public:type_name -> google.protobuf.Any
-	24, // 9: sgc7pb.PlayerState.private:type_name -> google.protobuf.Any
+	27, // 8: sgc7pb.PlayerState.public:type_name -> google.protobuf.Any
+	27, // 9: sgc7pb.PlayerState.private:type_name -> google.protobuf.Any
 	11, // 10: sgc7pb.RequestPlay.playerState:type_name -> sgc7pb.PlayerState
 	13, // 11: sgc7pb.RequestPlay.stake:type_name -> sgc7pb.Stake
 	4,  // 12: sgc7pb.SPGridList.scenes:type_name -> sgc7pb.GameScene
-	24, // 13: sgc7pb.PlayResult.curGameModParam:type_name -> google.protobuf.Any
+	27, // 13: sgc7pb.PlayResult.curGameModParam:type_name -> google.protobuf.Any
 	4,  // 14: sgc7pb.PlayResult.scenes:type_name -> sgc7pb.GameScene
 	4,  // 15: sgc7pb.PlayResult.otherScenes:type_name -> sgc7pb.GameScene
 	16, // 16: sgc7pb.PlayResult.results:type_name -> sgc7pb.GameScenePlayResult
 	4,  // 17: sgc7pb.PlayResult.prizeScenes:type_name -> sgc7pb.GameScene
-	23, // 18: sgc7pb.PlayResult.spGrid:type_name -> sgc7pb.PlayResult.SpGridEntry
+	25, // 18: sgc7pb.PlayResult.spGrid:type_name -> sgc7pb.PlayResult.SpGridEntry
 	18, // 19: sgc7pb.GameResult.clientData:type_name -> sgc7pb.PlayResult
 	15, // 20: sgc7pb.ReplyPlay.randomNumbers:type_name -> sgc7pb.RngInfo
 	11, // 21: sgc7pb.ReplyPlay.playerState:type_name -> sgc7pb.PlayerState
 	19, // 22: sgc7pb.ReplyPlay.results:type_name -> sgc7pb.GameResult
 	13, // 23: sgc7pb.ReplyPlay.stake:type_name -> sgc7pb.Stake
-	3,  // 24: sgc7pb.GameConfig.ReelsEntry.value:type_name -> sgc7pb.ReelsData
-	1,  // 25: sgc7pb.GameConfig.PayTablesEntry.value:type_name -> sgc7pb.Row
-	17, // 26: sgc7pb.PlayResult.SpGridEntry.value:type_name -> sgc7pb.SPGridList
-	10, // 27: sgc7pb.DTGameLogic.getConfig:input_type -> sgc7pb.RequestConfig
-	12, // 28: sgc7pb.DTGameLogic.initialize:input_type -> sgc7pb.RequestInitialize
-	14, // 29: sgc7pb.DTGameLogic.play:input_type -> sgc7pb.RequestPlay
-	14, // 30: sgc7pb.DTGameLogic.play2:input_type -> sgc7pb.RequestPlay
-	10, // 31: sgc7pb.GameLogic.getConfig:input_type -> sgc7pb.RequestConfig
-	12, // 32: sgc7pb.GameLogic.initialize:input_type -> sgc7pb.RequestInitialize
-	14, // 33: sgc7pb.GameLogic.play:input_type -> sgc7pb.RequestPlay
-	14, // 34: sgc7pb.GameLogic.play2:input_type -> sgc7pb.RequestPlay
-	9,  // 35: sgc7pb.DTGameLogic.getConfig:output_type -> sgc7pb.GameConfig
-	11, // 36: sgc7pb.DTGameLogic.initialize:output_type -> sgc7pb.PlayerState
-	20, // 37: sgc7pb.DTGameLogic.play:output_type -> sgc7pb.ReplyPlay
-	20, // 38: sgc7pb.DTGameLogic.play2:output_type -> sgc7pb.ReplyPlay
-	9,  // 39: sgc7pb.GameLogic.getConfig:output_type -> sgc7pb.GameConfig
-	11, // 40: sgc7pb.GameLogic.initialize:output_type -> sgc7pb.PlayerState
-	20, // 41: sgc7pb.GameLogic.play:output_type -> sgc7pb.ReplyPlay
-	20, // 42: sgc7pb.GameLogic.play2:output_type -> sgc7pb.ReplyPlay
-	35, // [35:43] is the sub-list for method output_type
-	27, // [27:35] is the sub-list for method input_type
-	27, // [27:27] is the sub-list for extension type_name
-	27, // [27:27] is the sub-list for extension extendee
-	0,  // [0:27] is the sub-list for field type_name
+	13, // 24: sgc7pb.RoundData.stake:type_name -> sgc7pb.Stake
+	11, // 25: sgc7pb.RoundData.startPlayerState:type_name -> sgc7pb.PlayerState
+	11, // 26: sgc7pb.RoundData.playerState:type_name -> sgc7pb.PlayerState
+	19, // 27: sgc7pb.RoundData.results:type_name -> sgc7pb.GameResult
+	15, // 28: sgc7pb.RoundData.randomNumbers:type_name -> sgc7pb.RngInfo
+	26, // 29: sgc7pb.RoundData.requests:type_name -> sgc7pb.RoundData.RequestsEntry
+	3,  // 30: sgc7pb.GameConfig.ReelsEntry.value:type_name -> sgc7pb.ReelsData
+	1,  // 31: sgc7pb.GameConfig.PayTablesEntry.value:type_name -> sgc7pb.Row
+	17, // 32: sgc7pb.PlayResult.SpGridEntry.value:type_name -> sgc7pb.SPGridList
+	20, // 33: sgc7pb.RoundData.RequestsEntry.value:type_name -> sgc7pb.ReplyPlay
+	10, // 34: sgc7pb.DTGameLogic.getConfig:input_type -> sgc7pb.RequestConfig
+	12, // 35: sgc7pb.DTGameLogic.initialize:input_type -> sgc7pb.RequestInitialize
+	14, // 36: sgc7pb.DTGameLogic.play:input_type -> sgc7pb.RequestPlay
+	14, // 37: sgc7pb.DTGameLogic.play2:input_type -> sgc7pb.RequestPlay
+	10, // 38: sgc7pb.GameLogic.getConfig:input_type -> sgc7pb.RequestConfig
+	12, // 39: sgc7pb.GameLogic.initialize:input_type -> sgc7pb.RequestInitialize
+	14, // 40: sgc7pb.GameLogic.play:input_type -> sgc7pb.RequestPlay
+	14, // 41: sgc7pb.GameLogic.play2:input_type -> sgc7pb.RequestPlay
+	21, // 42: sgc7pb.GameLogic.resumeRound:input_type -> sgc7pb.RequestResumeRound
+	9,  // 43: sgc7pb.DTGameLogic.getConfig:output_type -> sgc7pb.GameConfig
+	11, // 44: sgc7pb.DTGameLogic.initialize:output_type -> sgc7pb.PlayerState
+	20, // 45: sgc7pb.DTGameLogic.play:output_type -> sgc7pb.ReplyPlay
+	20, // 46: sgc7pb.DTGameLogic.play2:output_type -> sgc7pb.ReplyPlay
+	9,  // 47: sgc7pb.GameLogic.getConfig:output_type -> sgc7pb.GameConfig
+	11, // 48: sgc7pb.GameLogic.initialize:output_type -> sgc7pb.PlayerState
+	20, // 49: sgc7pb.GameLogic.play:output_type -> sgc7pb.ReplyPlay
+	20, // 50: sgc7pb.GameLogic.play2:output_type -> sgc7pb.ReplyPlay
+	20, // 51: sgc7pb.GameLogic.resumeRound:output_type -> sgc7pb.ReplyPlay
+	43, // [43:52] is the sub-list for method output_type
+	34, // [34:43] is the sub-list for method input_type
+	34, // [34:34] is the sub-list for extension type_name
+	34, // [34:34] is the sub-list for extension extendee
+	0,  // [0:34] is the sub-list for field type_name
 }
 
 func init() { file_game_proto_init() }
@@ -1670,7 +2036,7 @@ func file_game_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_game_proto_rawDesc), len(file_game_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   24,
+			NumMessages:   27,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
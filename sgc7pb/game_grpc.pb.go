@@ -251,10 +251,11 @@ var DTGameLogic_ServiceDesc = grpc.ServiceDesc{
 }
 
 const (
-	GameLogic_GetConfig_FullMethodName  = "/sgc7pb.GameLogic/getConfig"
-	GameLogic_Initialize_FullMethodName = "/sgc7pb.GameLogic/initialize"
-	GameLogic_Play_FullMethodName       = "/sgc7pb.GameLogic/play"
-	GameLogic_Play2_FullMethodName      = "/sgc7pb.GameLogic/play2"
+	GameLogic_GetConfig_FullMethodName   = "/sgc7pb.GameLogic/getConfig"
+	GameLogic_Initialize_FullMethodName  = "/sgc7pb.GameLogic/initialize"
+	GameLogic_Play_FullMethodName        = "/sgc7pb.GameLogic/play"
+	GameLogic_Play2_FullMethodName       = "/sgc7pb.GameLogic/play2"
+	GameLogic_ResumeRound_FullMethodName = "/sgc7pb.GameLogic/resumeRound"
 )
 
 // GameLogicClient is the client API for GameLogic service.
@@ -271,6 +272,8 @@ type GameLogicClient interface {
 	Play(ctx context.Context, in *RequestPlay, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ReplyPlay], error)
 	// play2 - play game v2
 	Play2(ctx context.Context, in *RequestPlay, opts ...grpc.CallOption) (*ReplyPlay, error)
+	// resumeRound - get all results so far of an unfinished round
+	ResumeRound(ctx context.Context, in *RequestResumeRound, opts ...grpc.CallOption) (*ReplyPlay, error)
 }
 
 type gameLogicClient struct {
@@ -330,6 +333,16 @@ func (c *gameLogicClient) Play2(ctx context.Context, in *RequestPlay, opts ...gr
 	return out, nil
 }
 
+func (c *gameLogicClient) ResumeRound(ctx context.Context, in *RequestResumeRound, opts ...grpc.CallOption) (*ReplyPlay, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplyPlay)
+	err := c.cc.Invoke(ctx, GameLogic_ResumeRound_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // GameLogicServer is the server API for GameLogic service.
 // All implementations must embed UnimplementedGameLogicServer
 // for forward compatibility.
@@ -344,6 +357,8 @@ type GameLogicServer interface {
 	Play(*RequestPlay, grpc.ServerStreamingServer[ReplyPlay]) error
 	// play2 - play game v2
 	Play2(context.Context, *RequestPlay) (*ReplyPlay, error)
+	// resumeRound - get all results so far of an unfinished round
+	ResumeRound(context.Context, *RequestResumeRound) (*ReplyPlay, error)
 	mustEmbedUnimplementedGameLogicServer()
 }
 
@@ -366,6 +381,9 @@ func (UnimplementedGameLogicServer) Play(*RequestPlay, grpc.ServerStreamingServe
 func (UnimplementedGameLogicServer) Play2(context.Context, *RequestPlay) (*ReplyPlay, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Play2 not implemented")
 }
+func (UnimplementedGameLogicServer) ResumeRound(context.Context, *RequestResumeRound) (*ReplyPlay, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeRound not implemented")
+}
 func (UnimplementedGameLogicServer) mustEmbedUnimplementedGameLogicServer() {}
 func (UnimplementedGameLogicServer) testEmbeddedByValue()                   {}
 
@@ -452,6 +470,24 @@ func _GameLogic_Play2_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GameLogic_ResumeRound_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestResumeRound)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GameLogicServer).ResumeRound(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GameLogic_ResumeRound_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GameLogicServer).ResumeRound(ctx, req.(*RequestResumeRound))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // GameLogic_ServiceDesc is the grpc.ServiceDesc for GameLogic service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -471,6 +507,10 @@ var GameLogic_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "play2",
 			Handler:    _GameLogic_Play2_Handler,
 		},
+		{
+			MethodName: "resumeRound",
+			Handler:    _GameLogic_ResumeRound_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
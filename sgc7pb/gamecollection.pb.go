@@ -23,9 +23,11 @@ const (
 
 // RequestInitGame - initial game
 type RequestInitGame struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	GameCode      string                 `protobuf:"bytes,1,opt,name=gameCode,proto3" json:"gameCode,omitempty"`
-	Config        string                 `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	GameCode string                 `protobuf:"bytes,1,opt,name=gameCode,proto3" json:"gameCode,omitempty"`
+	Config   string                 `protobuf:"bytes,2,opt,name=config,proto3" json:"config,omitempty"`
+	// notActivate - only add the version, do not activate it
+	NotActivate   bool `protobuf:"varint,3,opt,name=notActivate,proto3" json:"notActivate,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -74,11 +76,20 @@ func (x *RequestInitGame) GetConfig() string {
 	return ""
 }
 
+func (x *RequestInitGame) GetNotActivate() bool {
+	if x != nil {
+		return x.NotActivate
+	}
+	return false
+}
+
 // ReplyInitGame - reply initial game
 type ReplyInitGame struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	IsOK          bool                   `protobuf:"varint,1,opt,name=isOK,proto3" json:"isOK,omitempty"`
-	Err           string                 `protobuf:"bytes,2,opt,name=err,proto3" json:"err,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	IsOK  bool                   `protobuf:"varint,1,opt,name=isOK,proto3" json:"isOK,omitempty"`
+	Err   string                 `protobuf:"bytes,2,opt,name=err,proto3" json:"err,omitempty"`
+	// version - the version of this config
+	Version       string `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -127,6 +138,13 @@ func (x *ReplyInitGame) GetErr() string {
 	return ""
 }
 
+func (x *ReplyInitGame) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
 // RequestGameConfig - get game config
 type RequestGameConfig struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -349,9 +367,11 @@ func (x *ReplyInitializeGamePlayer) GetPlayerState() *PlayerState {
 
 // RequestPlayGame - play input parameters for the game
 type RequestPlayGame struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	GameCode      string                 `protobuf:"bytes,1,opt,name=gameCode,proto3" json:"gameCode,omitempty"`
-	Play          *RequestPlay           `protobuf:"bytes,2,opt,name=play,proto3" json:"play,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	GameCode string                 `protobuf:"bytes,1,opt,name=gameCode,proto3" json:"gameCode,omitempty"`
+	Play     *RequestPlay           `protobuf:"bytes,2,opt,name=play,proto3" json:"play,omitempty"`
+	// version - the game config version, empty means the version in playerState or the active version
+	Version       string `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -400,6 +420,13 @@ func (x *RequestPlayGame) GetPlay() *RequestPlay {
 	return nil
 }
 
+func (x *RequestPlayGame) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
 // RequestPlayGame - play input parameters for the game
 type ReplyPlayGame struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -461,18 +488,461 @@ func (x *ReplyPlayGame) GetPlay() *ReplyPlay {
 	return nil
 }
 
+// RequestResumeGameRound - resume an unfinished round
+type RequestResumeGameRound struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	GameCode string                 `protobuf:"bytes,1,opt,name=gameCode,proto3" json:"gameCode,omitempty"`
+	RoundID  string                 `protobuf:"bytes,2,opt,name=roundID,proto3" json:"roundID,omitempty"`
+	// playerID - must be the player of the round
+	PlayerID      string `protobuf:"bytes,3,opt,name=playerID,proto3" json:"playerID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestResumeGameRound) Reset() {
+	*x = RequestResumeGameRound{}
+	mi := &file_gamecollection_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestResumeGameRound) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestResumeGameRound) ProtoMessage() {}
+
+func (x *RequestResumeGameRound) ProtoReflect() protoreflect.Message {
+	mi := &file_gamecollection_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestResumeGameRound.ProtoReflect.Descriptor instead.
+func (*RequestResumeGameRound) Descriptor() ([]byte, []int) {
+	return file_gamecollection_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RequestResumeGameRound) GetGameCode() string {
+	if x != nil {
+		return x.GameCode
+	}
+	return ""
+}
+
+func (x *RequestResumeGameRound) GetRoundID() string {
+	if x != nil {
+		return x.RoundID
+	}
+	return ""
+}
+
+func (x *RequestResumeGameRound) GetPlayerID() string {
+	if x != nil {
+		return x.PlayerID
+	}
+	return ""
+}
+
+// GameVersion - a version of game config
+type GameVersion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	IsActive      bool                   `protobuf:"varint,2,opt,name=isActive,proto3" json:"isActive,omitempty"`
+	CreateTime    int64                  `protobuf:"varint,3,opt,name=createTime,proto3" json:"createTime,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GameVersion) Reset() {
+	*x = GameVersion{}
+	mi := &file_gamecollection_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameVersion) ProtoMessage() {}
+
+func (x *GameVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_gamecollection_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameVersion.ProtoReflect.Descriptor instead.
+func (*GameVersion) Descriptor() ([]byte, []int) {
+	return file_gamecollection_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GameVersion) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *GameVersion) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+func (x *GameVersion) GetCreateTime() int64 {
+	if x != nil {
+		return x.CreateTime
+	}
+	return 0
+}
+
+// RequestListGameVersions - list all versions of a game
+type RequestListGameVersions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GameCode      string                 `protobuf:"bytes,1,opt,name=gameCode,proto3" json:"gameCode,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestListGameVersions) Reset() {
+	*x = RequestListGameVersions{}
+	mi := &file_gamecollection_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestListGameVersions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestListGameVersions) ProtoMessage() {}
+
+func (x *RequestListGameVersions) ProtoReflect() protoreflect.Message {
+	mi := &file_gamecollection_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestListGameVersions.ProtoReflect.Descriptor instead.
+func (*RequestListGameVersions) Descriptor() ([]byte, []int) {
+	return file_gamecollection_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RequestListGameVersions) GetGameCode() string {
+	if x != nil {
+		return x.GameCode
+	}
+	return ""
+}
+
+// ReplyListGameVersions - reply list all versions of a game
+type ReplyListGameVersions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IsOK          bool                   `protobuf:"varint,1,opt,name=isOK,proto3" json:"isOK,omitempty"`
+	Err           string                 `protobuf:"bytes,2,opt,name=err,proto3" json:"err,omitempty"`
+	Versions      []*GameVersion         `protobuf:"bytes,3,rep,name=versions,proto3" json:"versions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplyListGameVersions) Reset() {
+	*x = ReplyListGameVersions{}
+	mi := &file_gamecollection_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplyListGameVersions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplyListGameVersions) ProtoMessage() {}
+
+func (x *ReplyListGameVersions) ProtoReflect() protoreflect.Message {
+	mi := &file_gamecollection_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplyListGameVersions.ProtoReflect.Descriptor instead.
+func (*ReplyListGameVersions) Descriptor() ([]byte, []int) {
+	return file_gamecollection_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ReplyListGameVersions) GetIsOK() bool {
+	if x != nil {
+		return x.IsOK
+	}
+	return false
+}
+
+func (x *ReplyListGameVersions) GetErr() string {
+	if x != nil {
+		return x.Err
+	}
+	return ""
+}
+
+func (x *ReplyListGameVersions) GetVersions() []*GameVersion {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+// RequestActivateGameVersion - activate a version of game
+type RequestActivateGameVersion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GameCode      string                 `protobuf:"bytes,1,opt,name=gameCode,proto3" json:"gameCode,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestActivateGameVersion) Reset() {
+	*x = RequestActivateGameVersion{}
+	mi := &file_gamecollection_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestActivateGameVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestActivateGameVersion) ProtoMessage() {}
+
+func (x *RequestActivateGameVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_gamecollection_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestActivateGameVersion.ProtoReflect.Descriptor instead.
+func (*RequestActivateGameVersion) Descriptor() ([]byte, []int) {
+	return file_gamecollection_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RequestActivateGameVersion) GetGameCode() string {
+	if x != nil {
+		return x.GameCode
+	}
+	return ""
+}
+
+func (x *RequestActivateGameVersion) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+// ReplyActivateGameVersion - reply activate a version of game
+type ReplyActivateGameVersion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IsOK          bool                   `protobuf:"varint,1,opt,name=isOK,proto3" json:"isOK,omitempty"`
+	Err           string                 `protobuf:"bytes,2,opt,name=err,proto3" json:"err,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplyActivateGameVersion) Reset() {
+	*x = ReplyActivateGameVersion{}
+	mi := &file_gamecollection_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplyActivateGameVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplyActivateGameVersion) ProtoMessage() {}
+
+func (x *ReplyActivateGameVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_gamecollection_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplyActivateGameVersion.ProtoReflect.Descriptor instead.
+func (*ReplyActivateGameVersion) Descriptor() ([]byte, []int) {
+	return file_gamecollection_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ReplyActivateGameVersion) GetIsOK() bool {
+	if x != nil {
+		return x.IsOK
+	}
+	return false
+}
+
+func (x *ReplyActivateGameVersion) GetErr() string {
+	if x != nil {
+		return x.Err
+	}
+	return ""
+}
+
+// RequestRetireGameVersion - retire a version of game
+type RequestRetireGameVersion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GameCode      string                 `protobuf:"bytes,1,opt,name=gameCode,proto3" json:"gameCode,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestRetireGameVersion) Reset() {
+	*x = RequestRetireGameVersion{}
+	mi := &file_gamecollection_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestRetireGameVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestRetireGameVersion) ProtoMessage() {}
+
+func (x *RequestRetireGameVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_gamecollection_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestRetireGameVersion.ProtoReflect.Descriptor instead.
+func (*RequestRetireGameVersion) Descriptor() ([]byte, []int) {
+	return file_gamecollection_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *RequestRetireGameVersion) GetGameCode() string {
+	if x != nil {
+		return x.GameCode
+	}
+	return ""
+}
+
+func (x *RequestRetireGameVersion) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+// ReplyRetireGameVersion - reply retire a version of game
+type ReplyRetireGameVersion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	IsOK          bool                   `protobuf:"varint,1,opt,name=isOK,proto3" json:"isOK,omitempty"`
+	Err           string                 `protobuf:"bytes,2,opt,name=err,proto3" json:"err,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplyRetireGameVersion) Reset() {
+	*x = ReplyRetireGameVersion{}
+	mi := &file_gamecollection_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplyRetireGameVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplyRetireGameVersion) ProtoMessage() {}
+
+func (x *ReplyRetireGameVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_gamecollection_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplyRetireGameVersion.ProtoReflect.Descriptor instead.
+func (*ReplyRetireGameVersion) Descriptor() ([]byte, []int) {
+	return file_gamecollection_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ReplyRetireGameVersion) GetIsOK() bool {
+	if x != nil {
+		return x.IsOK
+	}
+	return false
+}
+
+func (x *ReplyRetireGameVersion) GetErr() string {
+	if x != nil {
+		return x.Err
+	}
+	return ""
+}
+
 var File_gamecollection_proto protoreflect.FileDescriptor
 
 const file_gamecollection_proto_rawDesc = "" +
 	"\n" +
 	"\x14gamecollection.proto\x12\x06sgc7pb\x1a\n" +
-	"game.proto\"E\n" +
+	"game.proto\"g\n" +
 	"\x0fRequestInitGame\x12\x1a\n" +
 	"\bgameCode\x18\x01 \x01(\tR\bgameCode\x12\x16\n" +
-	"\x06config\x18\x02 \x01(\tR\x06config\"5\n" +
+	"\x06config\x18\x02 \x01(\tR\x06config\x12 \n" +
+	"\vnotActivate\x18\x03 \x01(\bR\vnotActivate\"O\n" +
 	"\rReplyInitGame\x12\x12\n" +
 	"\x04isOK\x18\x01 \x01(\bR\x04isOK\x12\x10\n" +
-	"\x03err\x18\x02 \x01(\tR\x03err\"X\n" +
+	"\x03err\x18\x02 \x01(\tR\x03err\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\"X\n" +
 	"\x11RequestGameConfig\x12\x1a\n" +
 	"\bgameCode\x18\x01 \x01(\tR\bgameCode\x12'\n" +
 	"\x03req\x18\x02 \x01(\v2\x15.sgc7pb.RequestConfigR\x03req\"k\n" +
@@ -487,20 +957,53 @@ const file_gamecollection_proto_rawDesc = "" +
 	"\x19ReplyInitializeGamePlayer\x12\x12\n" +
 	"\x04isOK\x18\x01 \x01(\bR\x04isOK\x12\x10\n" +
 	"\x03err\x18\x02 \x01(\tR\x03err\x125\n" +
-	"\vplayerState\x18\x03 \x01(\v2\x13.sgc7pb.PlayerStateR\vplayerState\"V\n" +
+	"\vplayerState\x18\x03 \x01(\v2\x13.sgc7pb.PlayerStateR\vplayerState\"p\n" +
 	"\x0fRequestPlayGame\x12\x1a\n" +
 	"\bgameCode\x18\x01 \x01(\tR\bgameCode\x12'\n" +
-	"\x04play\x18\x02 \x01(\v2\x13.sgc7pb.RequestPlayR\x04play\"\\\n" +
+	"\x04play\x18\x02 \x01(\v2\x13.sgc7pb.RequestPlayR\x04play\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\"\\\n" +
 	"\rReplyPlayGame\x12\x12\n" +
 	"\x04isOK\x18\x01 \x01(\bR\x04isOK\x12\x10\n" +
 	"\x03err\x18\x02 \x01(\tR\x03err\x12%\n" +
-	"\x04play\x18\x03 \x01(\v2\x11.sgc7pb.ReplyPlayR\x04play2\xfb\x02\n" +
+	"\x04play\x18\x03 \x01(\v2\x11.sgc7pb.ReplyPlayR\x04play\"j\n" +
+	"\x16RequestResumeGameRound\x12\x1a\n" +
+	"\bgameCode\x18\x01 \x01(\tR\bgameCode\x12\x18\n" +
+	"\aroundID\x18\x02 \x01(\tR\aroundID\x12\x1a\n" +
+	"\bplayerID\x18\x03 \x01(\tR\bplayerID\"c\n" +
+	"\vGameVersion\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x12\x1a\n" +
+	"\bisActive\x18\x02 \x01(\bR\bisActive\x12\x1e\n" +
+	"\n" +
+	"createTime\x18\x03 \x01(\x03R\n" +
+	"createTime\"5\n" +
+	"\x17RequestListGameVersions\x12\x1a\n" +
+	"\bgameCode\x18\x01 \x01(\tR\bgameCode\"n\n" +
+	"\x15ReplyListGameVersions\x12\x12\n" +
+	"\x04isOK\x18\x01 \x01(\bR\x04isOK\x12\x10\n" +
+	"\x03err\x18\x02 \x01(\tR\x03err\x12/\n" +
+	"\bversions\x18\x03 \x03(\v2\x13.sgc7pb.GameVersionR\bversions\"R\n" +
+	"\x1aRequestActivateGameVersion\x12\x1a\n" +
+	"\bgameCode\x18\x01 \x01(\tR\bgameCode\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\"@\n" +
+	"\x18ReplyActivateGameVersion\x12\x12\n" +
+	"\x04isOK\x18\x01 \x01(\bR\x04isOK\x12\x10\n" +
+	"\x03err\x18\x02 \x01(\tR\x03err\"P\n" +
+	"\x18RequestRetireGameVersion\x12\x1a\n" +
+	"\bgameCode\x18\x01 \x01(\tR\bgameCode\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\">\n" +
+	"\x16ReplyRetireGameVersion\x12\x12\n" +
+	"\x04isOK\x18\x01 \x01(\bR\x04isOK\x12\x10\n" +
+	"\x03err\x18\x02 \x01(\tR\x03err2\xd5\x05\n" +
 	"\x13GameLogicCollection\x12<\n" +
 	"\binitGame\x12\x17.sgc7pb.RequestInitGame\x1a\x15.sgc7pb.ReplyInitGame\"\x00\x12E\n" +
 	"\rgetGameConfig\x12\x19.sgc7pb.RequestGameConfig\x1a\x17.sgc7pb.ReplyGameConfig\"\x00\x12`\n" +
 	"\x14initializeGamePlayer\x12#.sgc7pb.RequestInitializeGamePlayer\x1a!.sgc7pb.ReplyInitializeGamePlayer\"\x00\x12>\n" +
 	"\bplayGame\x12\x17.sgc7pb.RequestPlayGame\x1a\x15.sgc7pb.ReplyPlayGame\"\x000\x01\x12=\n" +
-	"\tplayGame2\x12\x17.sgc7pb.RequestPlayGame\x1a\x15.sgc7pb.ReplyPlayGame\"\x00B)Z'github.com/zhs007/slotsgamecore7/sgc7pbb\x06proto3"
+	"\tplayGame2\x12\x17.sgc7pb.RequestPlayGame\x1a\x15.sgc7pb.ReplyPlayGame\"\x00\x12T\n" +
+	"\x10listGameVersions\x12\x1f.sgc7pb.RequestListGameVersions\x1a\x1d.sgc7pb.ReplyListGameVersions\"\x00\x12]\n" +
+	"\x13activateGameVersion\x12\".sgc7pb.RequestActivateGameVersion\x1a .sgc7pb.ReplyActivateGameVersion\"\x00\x12W\n" +
+	"\x11retireGameVersion\x12 .sgc7pb.RequestRetireGameVersion\x1a\x1e.sgc7pb.ReplyRetireGameVersion\"\x00\x12J\n" +
+	"\x0fresumeGameRound\x12\x1e.sgc7pb.RequestResumeGameRound\x1a\x15.sgc7pb.ReplyPlayGame\"\x00B)Z'github.com/zhs007/slotsgamecore7/sgc7pbb\x06proto3"
 
 var (
 	file_gamecollection_proto_rawDescOnce sync.Once
@@ -514,7 +1017,7 @@ func file_gamecollection_proto_rawDescGZIP() []byte {
 	return file_gamecollection_proto_rawDescData
 }
 
-var file_gamecollection_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_gamecollection_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_gamecollection_proto_goTypes = []any{
 	(*RequestInitGame)(nil),             // 0: sgc7pb.RequestInitGame
 	(*ReplyInitGame)(nil),               // 1: sgc7pb.ReplyInitGame
@@ -524,33 +1027,50 @@ var file_gamecollection_proto_goTypes = []any{
 	(*ReplyInitializeGamePlayer)(nil),   // 5: sgc7pb.ReplyInitializeGamePlayer
 	(*RequestPlayGame)(nil),             // 6: sgc7pb.RequestPlayGame
 	(*ReplyPlayGame)(nil),               // 7: sgc7pb.ReplyPlayGame
-	(*RequestConfig)(nil),               // 8: sgc7pb.RequestConfig
-	(*GameConfig)(nil),                  // 9: sgc7pb.GameConfig
-	(*PlayerState)(nil),                 // 10: sgc7pb.PlayerState
-	(*RequestPlay)(nil),                 // 11: sgc7pb.RequestPlay
-	(*ReplyPlay)(nil),                   // 12: sgc7pb.ReplyPlay
+	(*RequestResumeGameRound)(nil),      // 8: sgc7pb.RequestResumeGameRound
+	(*GameVersion)(nil),                 // 9: sgc7pb.GameVersion
+	(*RequestListGameVersions)(nil),     // 10: sgc7pb.RequestListGameVersions
+	(*ReplyListGameVersions)(nil),       // 11: sgc7pb.ReplyListGameVersions
+	(*RequestActivateGameVersion)(nil),  // 12: sgc7pb.RequestActivateGameVersion
+	(*ReplyActivateGameVersion)(nil),    // 13: sgc7pb.ReplyActivateGameVersion
+	(*RequestRetireGameVersion)(nil),    // 14: sgc7pb.RequestRetireGameVersion
+	(*ReplyRetireGameVersion)(nil),      // 15: sgc7pb.ReplyRetireGameVersion
+	(*RequestConfig)(nil),               // 16: sgc7pb.RequestConfig
+	(*GameConfig)(nil),                  // 17: sgc7pb.GameConfig
+	(*PlayerState)(nil),                 // 18: sgc7pb.PlayerState
+	(*RequestPlay)(nil),                 // 19: sgc7pb.RequestPlay
+	(*ReplyPlay)(nil),                   // 20: sgc7pb.ReplyPlay
 }
 var file_gamecollection_proto_depIdxs = []int32{
-	8,  // 0: sgc7pb.RequestGameConfig.req:type_name -> sgc7pb.RequestConfig
-	9,  // 1: sgc7pb.ReplyGameConfig.gameConfig:type_name -> sgc7pb.GameConfig
-	10, // 2: sgc7pb.ReplyInitializeGamePlayer.playerState:type_name -> sgc7pb.PlayerState
-	11, // 3: sgc7pb.RequestPlayGame.play:type_name -> sgc7pb.RequestPlay
-	12, // 4: sgc7pb.ReplyPlayGame.play:type_name -> sgc7pb.ReplyPlay
-	0,  // 5: sgc7pb.GameLogicCollection.initGame:input_type -> sgc7pb.RequestInitGame
-	2,  // 6: sgc7pb.GameLogicCollection.getGameConfig:input_type -> sgc7pb.RequestGameConfig
-	4,  // 7: sgc7pb.GameLogicCollection.initializeGamePlayer:input_type -> sgc7pb.RequestInitializeGamePlayer
-	6,  // 8: sgc7pb.GameLogicCollection.playGame:input_type -> sgc7pb.RequestPlayGame
-	6,  // 9: sgc7pb.GameLogicCollection.playGame2:input_type -> sgc7pb.RequestPlayGame
-	1,  // 10: sgc7pb.GameLogicCollection.initGame:output_type -> sgc7pb.ReplyInitGame
-	3,  // 11: sgc7pb.GameLogicCollection.getGameConfig:output_type -> sgc7pb.ReplyGameConfig
-	5,  // 12: sgc7pb.GameLogicCollection.initializeGamePlayer:output_type -> sgc7pb.ReplyInitializeGamePlayer
-	7,  // 13: sgc7pb.GameLogicCollection.playGame:output_type -> sgc7pb.ReplyPlayGame
-	7,  // 14: sgc7pb.GameLogicCollection.playGame2:output_type -> sgc7pb.ReplyPlayGame
-	10, // [10:15] is the sub-list for method output_type
-	5,  // [5:10] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	16, // 0: sgc7pb.RequestGameConfig.req:type_name -> sgc7pb.RequestConfig
+	17, // 1: sgc7pb.ReplyGameConfig.gameConfig:type_name -> sgc7pb.GameConfig
+	18, // 2: sgc7pb.ReplyInitializeGamePlayer.playerState:type_name -> sgc7pb.PlayerState
+	19, // 3: sgc7pb.RequestPlayGame.play:type_name -> sgc7pb.RequestPlay
+	20, // 4: sgc7pb.ReplyPlayGame.play:type_name -> sgc7pb.ReplyPlay
+	9,  // 5: sgc7pb.ReplyListGameVersions.versions:type_name -> sgc7pb.GameVersion
+	0,  // 6: sgc7pb.GameLogicCollection.initGame:input_type -> sgc7pb.RequestInitGame
+	2,  // 7: sgc7pb.GameLogicCollection.getGameConfig:input_type -> sgc7pb.RequestGameConfig
+	4,  // 8: sgc7pb.GameLogicCollection.initializeGamePlayer:input_type -> sgc7pb.RequestInitializeGamePlayer
+	6,  // 9: sgc7pb.GameLogicCollection.playGame:input_type -> sgc7pb.RequestPlayGame
+	6,  // 10: sgc7pb.GameLogicCollection.playGame2:input_type -> sgc7pb.RequestPlayGame
+	10, // 11: sgc7pb.GameLogicCollection.listGameVersions:input_type -> sgc7pb.RequestListGameVersions
+	12, // 12: sgc7pb.GameLogicCollection.activateGameVersion:input_type -> sgc7pb.RequestActivateGameVersion
+	14, // 13: sgc7pb.GameLogicCollection.retireGameVersion:input_type -> sgc7pb.RequestRetireGameVersion
+	8,  // 14: sgc7pb.GameLogicCollection.resumeGameRound:input_type -> sgc7pb.RequestResumeGameRound
+	1,  // 15: sgc7pb.GameLogicCollection.initGame:output_type -> sgc7pb.ReplyInitGame
+	3,  // 16: sgc7pb.GameLogicCollection.getGameConfig:output_type -> sgc7pb.ReplyGameConfig
+	5,  // 17: sgc7pb.GameLogicCollection.initializeGamePlayer:output_type -> sgc7pb.ReplyInitializeGamePlayer
+	7,  // 18: sgc7pb.GameLogicCollection.playGame:output_type -> sgc7pb.ReplyPlayGame
+	7,  // 19: sgc7pb.GameLogicCollection.playGame2:output_type -> sgc7pb.ReplyPlayGame
+	11, // 20: sgc7pb.GameLogicCollection.listGameVersions:output_type -> sgc7pb.ReplyListGameVersions
+	13, // 21: sgc7pb.GameLogicCollection.activateGameVersion:output_type -> sgc7pb.ReplyActivateGameVersion
+	15, // 22: sgc7pb.GameLogicCollection.retireGameVersion:output_type -> sgc7pb.ReplyRetireGameVersion
+	7,  // 23: sgc7pb.GameLogicCollection.resumeGameRound:output_type -> sgc7pb.ReplyPlayGame
+	15, // [15:24] is the sub-list for method output_type
+	6,  // [6:15] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_gamecollection_proto_init() }
@@ -565,7 +1085,7 @@ func file_gamecollection_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_gamecollection_proto_rawDesc), len(file_gamecollection_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
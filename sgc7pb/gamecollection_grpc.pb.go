@@ -24,6 +24,10 @@ const (
 	GameLogicCollection_InitializeGamePlayer_FullMethodName = "/sgc7pb.GameLogicCollection/initializeGamePlayer"
 	GameLogicCollection_PlayGame_FullMethodName             = "/sgc7pb.GameLogicCollection/playGame"
 	GameLogicCollection_PlayGame2_FullMethodName            = "/sgc7pb.GameLogicCollection/playGame2"
+	GameLogicCollection_ListGameVersions_FullMethodName     = "/sgc7pb.GameLogicCollection/listGameVersions"
+	GameLogicCollection_ActivateGameVersion_FullMethodName  = "/sgc7pb.GameLogicCollection/activateGameVersion"
+	GameLogicCollection_RetireGameVersion_FullMethodName    = "/sgc7pb.GameLogicCollection/retireGameVersion"
+	GameLogicCollection_ResumeGameRound_FullMethodName      = "/sgc7pb.GameLogicCollection/resumeGameRound"
 )
 
 // GameLogicCollectionClient is the client API for GameLogicCollection service.
@@ -42,6 +46,14 @@ type GameLogicCollectionClient interface {
 	PlayGame(ctx context.Context, in *RequestPlayGame, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ReplyPlayGame], error)
 	// playGame2 - play game v2
 	PlayGame2(ctx context.Context, in *RequestPlayGame, opts ...grpc.CallOption) (*ReplyPlayGame, error)
+	// listGameVersions - list all versions of a game
+	ListGameVersions(ctx context.Context, in *RequestListGameVersions, opts ...grpc.CallOption) (*ReplyListGameVersions, error)
+	// activateGameVersion - activate a version of game
+	ActivateGameVersion(ctx context.Context, in *RequestActivateGameVersion, opts ...grpc.CallOption) (*ReplyActivateGameVersion, error)
+	// retireGameVersion - retire a version of game
+	RetireGameVersion(ctx context.Context, in *RequestRetireGameVersion, opts ...grpc.CallOption) (*ReplyRetireGameVersion, error)
+	// resumeGameRound - get all results so far of an unfinished round
+	ResumeGameRound(ctx context.Context, in *RequestResumeGameRound, opts ...grpc.CallOption) (*ReplyPlayGame, error)
 }
 
 type gameLogicCollectionClient struct {
@@ -111,6 +123,46 @@ func (c *gameLogicCollectionClient) PlayGame2(ctx context.Context, in *RequestPl
 	return out, nil
 }
 
+func (c *gameLogicCollectionClient) ListGameVersions(ctx context.Context, in *RequestListGameVersions, opts ...grpc.CallOption) (*ReplyListGameVersions, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplyListGameVersions)
+	err := c.cc.Invoke(ctx, GameLogicCollection_ListGameVersions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gameLogicCollectionClient) ActivateGameVersion(ctx context.Context, in *RequestActivateGameVersion, opts ...grpc.CallOption) (*ReplyActivateGameVersion, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplyActivateGameVersion)
+	err := c.cc.Invoke(ctx, GameLogicCollection_ActivateGameVersion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gameLogicCollectionClient) RetireGameVersion(ctx context.Context, in *RequestRetireGameVersion, opts ...grpc.CallOption) (*ReplyRetireGameVersion, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplyRetireGameVersion)
+	err := c.cc.Invoke(ctx, GameLogicCollection_RetireGameVersion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gameLogicCollectionClient) ResumeGameRound(ctx context.Context, in *RequestResumeGameRound, opts ...grpc.CallOption) (*ReplyPlayGame, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplyPlayGame)
+	err := c.cc.Invoke(ctx, GameLogicCollection_ResumeGameRound_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // GameLogicCollectionServer is the server API for GameLogicCollection service.
 // All implementations must embed UnimplementedGameLogicCollectionServer
 // for forward compatibility.
@@ -127,6 +179,14 @@ type GameLogicCollectionServer interface {
 	PlayGame(*RequestPlayGame, grpc.ServerStreamingServer[ReplyPlayGame]) error
 	// playGame2 - play game v2
 	PlayGame2(context.Context, *RequestPlayGame) (*ReplyPlayGame, error)
+	// listGameVersions - list all versions of a game
+	ListGameVersions(context.Context, *RequestListGameVersions) (*ReplyListGameVersions, error)
+	// activateGameVersion - activate a version of game
+	ActivateGameVersion(context.Context, *RequestActivateGameVersion) (*ReplyActivateGameVersion, error)
+	// retireGameVersion - retire a version of game
+	RetireGameVersion(context.Context, *RequestRetireGameVersion) (*ReplyRetireGameVersion, error)
+	// resumeGameRound - get all results so far of an unfinished round
+	ResumeGameRound(context.Context, *RequestResumeGameRound) (*ReplyPlayGame, error)
 	mustEmbedUnimplementedGameLogicCollectionServer()
 }
 
@@ -152,6 +212,18 @@ func (UnimplementedGameLogicCollectionServer) PlayGame(*RequestPlayGame, grpc.Se
 func (UnimplementedGameLogicCollectionServer) PlayGame2(context.Context, *RequestPlayGame) (*ReplyPlayGame, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PlayGame2 not implemented")
 }
+func (UnimplementedGameLogicCollectionServer) ListGameVersions(context.Context, *RequestListGameVersions) (*ReplyListGameVersions, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListGameVersions not implemented")
+}
+func (UnimplementedGameLogicCollectionServer) ActivateGameVersion(context.Context, *RequestActivateGameVersion) (*ReplyActivateGameVersion, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ActivateGameVersion not implemented")
+}
+func (UnimplementedGameLogicCollectionServer) RetireGameVersion(context.Context, *RequestRetireGameVersion) (*ReplyRetireGameVersion, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RetireGameVersion not implemented")
+}
+func (UnimplementedGameLogicCollectionServer) ResumeGameRound(context.Context, *RequestResumeGameRound) (*ReplyPlayGame, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeGameRound not implemented")
+}
 func (UnimplementedGameLogicCollectionServer) mustEmbedUnimplementedGameLogicCollectionServer() {}
 func (UnimplementedGameLogicCollectionServer) testEmbeddedByValue()                             {}
 
@@ -256,6 +328,78 @@ func _GameLogicCollection_PlayGame2_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GameLogicCollection_ListGameVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestListGameVersions)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GameLogicCollectionServer).ListGameVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GameLogicCollection_ListGameVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GameLogicCollectionServer).ListGameVersions(ctx, req.(*RequestListGameVersions))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GameLogicCollection_ActivateGameVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestActivateGameVersion)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GameLogicCollectionServer).ActivateGameVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GameLogicCollection_ActivateGameVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GameLogicCollectionServer).ActivateGameVersion(ctx, req.(*RequestActivateGameVersion))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GameLogicCollection_RetireGameVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestRetireGameVersion)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GameLogicCollectionServer).RetireGameVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GameLogicCollection_RetireGameVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GameLogicCollectionServer).RetireGameVersion(ctx, req.(*RequestRetireGameVersion))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GameLogicCollection_ResumeGameRound_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestResumeGameRound)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GameLogicCollectionServer).ResumeGameRound(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: GameLogicCollection_ResumeGameRound_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GameLogicCollectionServer).ResumeGameRound(ctx, req.(*RequestResumeGameRound))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // GameLogicCollection_ServiceDesc is the grpc.ServiceDesc for GameLogicCollection service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -279,6 +423,22 @@ var GameLogicCollection_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "playGame2",
 			Handler:    _GameLogicCollection_PlayGame2_Handler,
 		},
+		{
+			MethodName: "listGameVersions",
+			Handler:    _GameLogicCollection_ListGameVersions_Handler,
+		},
+		{
+			MethodName: "activateGameVersion",
+			Handler:    _GameLogicCollection_ActivateGameVersion_Handler,
+		},
+		{
+			MethodName: "retireGameVersion",
+			Handler:    _GameLogicCollection_RetireGameVersion_Handler,
+		},
+		{
+			MethodName: "resumeGameRound",
+			Handler:    _GameLogicCollection_ResumeGameRound_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
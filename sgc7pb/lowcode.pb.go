@@ -1715,6 +1715,7 @@ type WaysTriggerData struct {
 	RespinNum          int32                  `protobuf:"varint,5,opt,name=respinNum,proto3" json:"respinNum,omitempty"`
 	Wins               int32                  `protobuf:"varint,6,opt,name=wins,proto3" json:"wins,omitempty"`
 	WinMulti           int32                  `protobuf:"varint,7,opt,name=winMulti,proto3" json:"winMulti,omitempty"`
+	Ways               int32                  `protobuf:"varint,8,opt,name=ways,proto3" json:"ways,omitempty"`
 	unknownFields      protoimpl.UnknownFields
 	sizeCache          protoimpl.SizeCache
 }
@@ -1798,6 +1799,13 @@ func (x *WaysTriggerData) GetWinMulti() int32 {
 	return 0
 }
 
+func (x *WaysTriggerData) GetWays() int32 {
+	if x != nil {
+		return x.Ways
+	}
+	return 0
+}
+
 // ClusterTriggerData
 type ClusterTriggerData struct {
 	state              protoimpl.MessageState `protogen:"open.v1"`
@@ -4154,6 +4162,585 @@ func (x *SymbolValsSPData) GetPos() []int32 {
 	return nil
 }
 
+// DynamicHeightReelsData
+type DynamicHeightReelsData struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	BasicComponentData *ComponentData         `protobuf:"bytes,1,opt,name=basicComponentData,proto3" json:"basicComponentData,omitempty"`
+	Heights            []int32                `protobuf:"varint,2,rep,packed,name=heights,proto3" json:"heights,omitempty"`
+	TopReel            []int32                `protobuf:"varint,3,rep,packed,name=topReel,proto3" json:"topReel,omitempty"`
+	TopReelStart       int32                  `protobuf:"varint,4,opt,name=topReelStart,proto3" json:"topReelStart,omitempty"`
+	TopReelIndex       int32                  `protobuf:"varint,5,opt,name=topReelIndex,proto3" json:"topReelIndex,omitempty"`
+	Ways               int32                  `protobuf:"varint,6,opt,name=ways,proto3" json:"ways,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *DynamicHeightReelsData) Reset() {
+	*x = DynamicHeightReelsData{}
+	mi := &file_lowcode_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DynamicHeightReelsData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DynamicHeightReelsData) ProtoMessage() {}
+
+func (x *DynamicHeightReelsData) ProtoReflect() protoreflect.Message {
+	mi := &file_lowcode_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DynamicHeightReelsData.ProtoReflect.Descriptor instead.
+func (*DynamicHeightReelsData) Descriptor() ([]byte, []int) {
+	return file_lowcode_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *DynamicHeightReelsData) GetBasicComponentData() *ComponentData {
+	if x != nil {
+		return x.BasicComponentData
+	}
+	return nil
+}
+
+func (x *DynamicHeightReelsData) GetHeights() []int32 {
+	if x != nil {
+		return x.Heights
+	}
+	return nil
+}
+
+func (x *DynamicHeightReelsData) GetTopReel() []int32 {
+	if x != nil {
+		return x.TopReel
+	}
+	return nil
+}
+
+func (x *DynamicHeightReelsData) GetTopReelStart() int32 {
+	if x != nil {
+		return x.TopReelStart
+	}
+	return 0
+}
+
+func (x *DynamicHeightReelsData) GetTopReelIndex() int32 {
+	if x != nil {
+		return x.TopReelIndex
+	}
+	return 0
+}
+
+func (x *DynamicHeightReelsData) GetWays() int32 {
+	if x != nil {
+		return x.Ways
+	}
+	return 0
+}
+
+// StickySymbol
+type StickySymbol struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	X             int32                  `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y             int32                  `protobuf:"varint,2,opt,name=y,proto3" json:"y,omitempty"`
+	Symbol        int32                  `protobuf:"varint,3,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Multi         int32                  `protobuf:"varint,4,opt,name=multi,proto3" json:"multi,omitempty"`
+	Life          int32                  `protobuf:"varint,5,opt,name=life,proto3" json:"life,omitempty"`
+	MoveType      int32                  `protobuf:"varint,6,opt,name=moveType,proto3" json:"moveType,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StickySymbol) Reset() {
+	*x = StickySymbol{}
+	mi := &file_lowcode_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StickySymbol) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StickySymbol) ProtoMessage() {}
+
+func (x *StickySymbol) ProtoReflect() protoreflect.Message {
+	mi := &file_lowcode_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StickySymbol.ProtoReflect.Descriptor instead.
+func (*StickySymbol) Descriptor() ([]byte, []int) {
+	return file_lowcode_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *StickySymbol) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *StickySymbol) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+func (x *StickySymbol) GetSymbol() int32 {
+	if x != nil {
+		return x.Symbol
+	}
+	return 0
+}
+
+func (x *StickySymbol) GetMulti() int32 {
+	if x != nil {
+		return x.Multi
+	}
+	return 0
+}
+
+func (x *StickySymbol) GetLife() int32 {
+	if x != nil {
+		return x.Life
+	}
+	return 0
+}
+
+func (x *StickySymbol) GetMoveType() int32 {
+	if x != nil {
+		return x.MoveType
+	}
+	return 0
+}
+
+// StickySymbolsData
+type StickySymbolsData struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	BasicComponentData *ComponentData         `protobuf:"bytes,1,opt,name=basicComponentData,proto3" json:"basicComponentData,omitempty"`
+	Stickies           []*StickySymbol        `protobuf:"bytes,2,rep,name=stickies,proto3" json:"stickies,omitempty"`
+	NewNum             int32                  `protobuf:"varint,3,opt,name=newNum,proto3" json:"newNum,omitempty"`
+	RemovedNum         int32                  `protobuf:"varint,4,opt,name=removedNum,proto3" json:"removedNum,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *StickySymbolsData) Reset() {
+	*x = StickySymbolsData{}
+	mi := &file_lowcode_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StickySymbolsData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StickySymbolsData) ProtoMessage() {}
+
+func (x *StickySymbolsData) ProtoReflect() protoreflect.Message {
+	mi := &file_lowcode_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StickySymbolsData.ProtoReflect.Descriptor instead.
+func (*StickySymbolsData) Descriptor() ([]byte, []int) {
+	return file_lowcode_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *StickySymbolsData) GetBasicComponentData() *ComponentData {
+	if x != nil {
+		return x.BasicComponentData
+	}
+	return nil
+}
+
+func (x *StickySymbolsData) GetStickies() []*StickySymbol {
+	if x != nil {
+		return x.Stickies
+	}
+	return nil
+}
+
+func (x *StickySymbolsData) GetNewNum() int32 {
+	if x != nil {
+		return x.NewNum
+	}
+	return 0
+}
+
+func (x *StickySymbolsData) GetRemovedNum() int32 {
+	if x != nil {
+		return x.RemovedNum
+	}
+	return 0
+}
+
+// TrailBoardData
+type TrailBoardData struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	BasicComponentData *ComponentData         `protobuf:"bytes,1,opt,name=basicComponentData,proto3" json:"basicComponentData,omitempty"`
+	Position           int32                  `protobuf:"varint,2,opt,name=position,proto3" json:"position,omitempty"`
+	Steps              int32                  `protobuf:"varint,3,opt,name=steps,proto3" json:"steps,omitempty"`
+	Path               []int32                `protobuf:"varint,4,rep,packed,name=path,proto3" json:"path,omitempty"`
+	Multi              int32                  `protobuf:"varint,5,opt,name=multi,proto3" json:"multi,omitempty"`
+	PendingWins        int32                  `protobuf:"varint,6,opt,name=pendingWins,proto3" json:"pendingWins,omitempty"`
+	Wins               int32                  `protobuf:"varint,7,opt,name=wins,proto3" json:"wins,omitempty"`
+	IsEnded            bool                   `protobuf:"varint,8,opt,name=isEnded,proto3" json:"isEnded,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *TrailBoardData) Reset() {
+	*x = TrailBoardData{}
+	mi := &file_lowcode_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrailBoardData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrailBoardData) ProtoMessage() {}
+
+func (x *TrailBoardData) ProtoReflect() protoreflect.Message {
+	mi := &file_lowcode_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrailBoardData.ProtoReflect.Descriptor instead.
+func (*TrailBoardData) Descriptor() ([]byte, []int) {
+	return file_lowcode_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *TrailBoardData) GetBasicComponentData() *ComponentData {
+	if x != nil {
+		return x.BasicComponentData
+	}
+	return nil
+}
+
+func (x *TrailBoardData) GetPosition() int32 {
+	if x != nil {
+		return x.Position
+	}
+	return 0
+}
+
+func (x *TrailBoardData) GetSteps() int32 {
+	if x != nil {
+		return x.Steps
+	}
+	return 0
+}
+
+func (x *TrailBoardData) GetPath() []int32 {
+	if x != nil {
+		return x.Path
+	}
+	return nil
+}
+
+func (x *TrailBoardData) GetMulti() int32 {
+	if x != nil {
+		return x.Multi
+	}
+	return 0
+}
+
+func (x *TrailBoardData) GetPendingWins() int32 {
+	if x != nil {
+		return x.PendingWins
+	}
+	return 0
+}
+
+func (x *TrailBoardData) GetWins() int32 {
+	if x != nil {
+		return x.Wins
+	}
+	return 0
+}
+
+func (x *TrailBoardData) GetIsEnded() bool {
+	if x != nil {
+		return x.IsEnded
+	}
+	return false
+}
+
+// WheelBonusData
+type WheelBonusData struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	BasicComponentData *ComponentData         `protobuf:"bytes,1,opt,name=basicComponentData,proto3" json:"basicComponentData,omitempty"`
+	Wheels             []int32                `protobuf:"varint,2,rep,packed,name=wheels,proto3" json:"wheels,omitempty"`
+	Segments           []int32                `protobuf:"varint,3,rep,packed,name=segments,proto3" json:"segments,omitempty"`
+	SegmentType        string                 `protobuf:"bytes,4,opt,name=segmentType,proto3" json:"segmentType,omitempty"`
+	Value              int32                  `protobuf:"varint,5,opt,name=value,proto3" json:"value,omitempty"`
+	Wins               int32                  `protobuf:"varint,6,opt,name=wins,proto3" json:"wins,omitempty"`
+	Multi              int32                  `protobuf:"varint,7,opt,name=multi,proto3" json:"multi,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *WheelBonusData) Reset() {
+	*x = WheelBonusData{}
+	mi := &file_lowcode_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WheelBonusData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WheelBonusData) ProtoMessage() {}
+
+func (x *WheelBonusData) ProtoReflect() protoreflect.Message {
+	mi := &file_lowcode_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WheelBonusData.ProtoReflect.Descriptor instead.
+func (*WheelBonusData) Descriptor() ([]byte, []int) {
+	return file_lowcode_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *WheelBonusData) GetBasicComponentData() *ComponentData {
+	if x != nil {
+		return x.BasicComponentData
+	}
+	return nil
+}
+
+func (x *WheelBonusData) GetWheels() []int32 {
+	if x != nil {
+		return x.Wheels
+	}
+	return nil
+}
+
+func (x *WheelBonusData) GetSegments() []int32 {
+	if x != nil {
+		return x.Segments
+	}
+	return nil
+}
+
+func (x *WheelBonusData) GetSegmentType() string {
+	if x != nil {
+		return x.SegmentType
+	}
+	return ""
+}
+
+func (x *WheelBonusData) GetValue() int32 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *WheelBonusData) GetWins() int32 {
+	if x != nil {
+		return x.Wins
+	}
+	return 0
+}
+
+func (x *WheelBonusData) GetMulti() int32 {
+	if x != nil {
+		return x.Multi
+	}
+	return 0
+}
+
+// SymbolUpgradeData
+type SymbolUpgradeData struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	BasicComponentData *ComponentData         `protobuf:"bytes,1,opt,name=basicComponentData,proto3" json:"basicComponentData,omitempty"`
+	Level              int32                  `protobuf:"varint,2,opt,name=level,proto3" json:"level,omitempty"`
+	UpgradedNum        int32                  `protobuf:"varint,3,opt,name=upgradedNum,proto3" json:"upgradedNum,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *SymbolUpgradeData) Reset() {
+	*x = SymbolUpgradeData{}
+	mi := &file_lowcode_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SymbolUpgradeData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SymbolUpgradeData) ProtoMessage() {}
+
+func (x *SymbolUpgradeData) ProtoReflect() protoreflect.Message {
+	mi := &file_lowcode_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SymbolUpgradeData.ProtoReflect.Descriptor instead.
+func (*SymbolUpgradeData) Descriptor() ([]byte, []int) {
+	return file_lowcode_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *SymbolUpgradeData) GetBasicComponentData() *ComponentData {
+	if x != nil {
+		return x.BasicComponentData
+	}
+	return nil
+}
+
+func (x *SymbolUpgradeData) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *SymbolUpgradeData) GetUpgradedNum() int32 {
+	if x != nil {
+		return x.UpgradedNum
+	}
+	return 0
+}
+
+// PositionMultiplierGridData
+type PositionMultiplierGridData struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	BasicComponentData *ComponentData         `protobuf:"bytes,1,opt,name=basicComponentData,proto3" json:"basicComponentData,omitempty"`
+	Width              int32                  `protobuf:"varint,2,opt,name=width,proto3" json:"width,omitempty"`
+	Height             int32                  `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+	Multis             []int32                `protobuf:"varint,4,rep,packed,name=multis,proto3" json:"multis,omitempty"`
+	WinMultis          []int32                `protobuf:"varint,5,rep,packed,name=winMultis,proto3" json:"winMultis,omitempty"`
+	Wins               int32                  `protobuf:"varint,6,opt,name=wins,proto3" json:"wins,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *PositionMultiplierGridData) Reset() {
+	*x = PositionMultiplierGridData{}
+	mi := &file_lowcode_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PositionMultiplierGridData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PositionMultiplierGridData) ProtoMessage() {}
+
+func (x *PositionMultiplierGridData) ProtoReflect() protoreflect.Message {
+	mi := &file_lowcode_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PositionMultiplierGridData.ProtoReflect.Descriptor instead.
+func (*PositionMultiplierGridData) Descriptor() ([]byte, []int) {
+	return file_lowcode_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *PositionMultiplierGridData) GetBasicComponentData() *ComponentData {
+	if x != nil {
+		return x.BasicComponentData
+	}
+	return nil
+}
+
+func (x *PositionMultiplierGridData) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *PositionMultiplierGridData) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *PositionMultiplierGridData) GetMultis() []int32 {
+	if x != nil {
+		return x.Multis
+	}
+	return nil
+}
+
+func (x *PositionMultiplierGridData) GetWinMultis() []int32 {
+	if x != nil {
+		return x.WinMultis
+	}
+	return nil
+}
+
+func (x *PositionMultiplierGridData) GetWins() int32 {
+	if x != nil {
+		return x.Wins
+	}
+	return 0
+}
+
 // GameParam
 type GameParam struct {
 	state                  protoimpl.MessageState `protogen:"open.v1"`
@@ -4171,7 +4758,7 @@ type GameParam struct {
 
 func (x *GameParam) Reset() {
 	*x = GameParam{}
-	mi := &file_lowcode_proto_msgTypes[64]
+	mi := &file_lowcode_proto_msgTypes[71]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -4183,7 +4770,7 @@ func (x *GameParam) String() string {
 func (*GameParam) ProtoMessage() {}
 
 func (x *GameParam) ProtoReflect() protoreflect.Message {
-	mi := &file_lowcode_proto_msgTypes[64]
+	mi := &file_lowcode_proto_msgTypes[71]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -4196,7 +4783,7 @@ func (x *GameParam) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GameParam.ProtoReflect.Descriptor instead.
 func (*GameParam) Descriptor() ([]byte, []int) {
-	return file_lowcode_proto_rawDescGZIP(), []int{64}
+	return file_lowcode_proto_rawDescGZIP(), []int{71}
 }
 
 func (x *GameParam) GetFirstComponent() string {
@@ -4394,7 +4981,7 @@ const file_lowcode_proto_rawDesc = "" +
 	"\awildNum\x18\x04 \x01(\x05R\awildNum\x12\x1c\n" +
 	"\trespinNum\x18\x05 \x01(\x05R\trespinNum\x12\x12\n" +
 	"\x04wins\x18\x06 \x01(\x05R\x04wins\x12\x1a\n" +
-	"\bwinMulti\x18\a \x01(\x05R\bwinMulti\"\x84\x02\n" +
+	"\bwinMulti\x18\a \x01(\x05R\bwinMulti\"\x98\x02\n" +
 	"\x0fWaysTriggerData\x12E\n" +
 	"\x12basicComponentData\x18\x01 \x01(\v2\x15.sgc7pb.ComponentDataR\x12basicComponentData\x12$\n" +
 	"\rnextComponent\x18\x02 \x01(\tR\rnextComponent\x12\x1c\n" +
@@ -4402,7 +4989,8 @@ const file_lowcode_proto_rawDesc = "" +
 	"\awildNum\x18\x04 \x01(\x05R\awildNum\x12\x1c\n" +
 	"\trespinNum\x18\x05 \x01(\x05R\trespinNum\x12\x12\n" +
 	"\x04wins\x18\x06 \x01(\x05R\x04wins\x12\x1a\n" +
-	"\bwinMulti\x18\a \x01(\x05R\bwinMulti\"\x87\x02\n" +
+	"\bwinMulti\x18\a \x01(\x05R\bwinMulti\x12\x12\n" +
+	"\x04ways\x18\b \x01(\x05R\x04ways\"\x87\x02\n" +
 	"\x12ClusterTriggerData\x12E\n" +
 	"\x12basicComponentData\x18\x01 \x01(\v2\x15.sgc7pb.ComponentDataR\x12basicComponentData\x12$\n" +
 	"\rnextComponent\x18\x02 \x01(\tR\rnextComponent\x12\x1c\n" +
@@ -4569,7 +5157,56 @@ const file_lowcode_proto_rawDesc = "" +
 	"\x10collectSymbolNum\x18\x05 \x01(\x05R\x10collectSymbolNum\x122\n" +
 	"\x14collectCoinSymbolNum\x18\x06 \x01(\x05R\x14collectCoinSymbolNum\x12 \n" +
 	"\vcollectCoin\x18\a \x01(\x05R\vcollectCoin\x12\x10\n" +
-	"\x03pos\x18\b \x03(\x05R\x03pos\"\x93\x05\n" +
+	"\x03pos\x18\b \x03(\x05R\x03pos\"\xef\x01\n" +
+	"\x16DynamicHeightReelsData\x12E\n" +
+	"\x12basicComponentData\x18\x01 \x01(\v2\x15.sgc7pb.ComponentDataR\x12basicComponentData\x12\x18\n" +
+	"\aheights\x18\x02 \x03(\x05R\aheights\x12\x18\n" +
+	"\atopReel\x18\x03 \x03(\x05R\atopReel\x12\"\n" +
+	"\ftopReelStart\x18\x04 \x01(\x05R\ftopReelStart\x12\"\n" +
+	"\ftopReelIndex\x18\x05 \x01(\x05R\ftopReelIndex\x12\x12\n" +
+	"\x04ways\x18\x06 \x01(\x05R\x04ways\"\x88\x01\n" +
+	"\fStickySymbol\x12\f\n" +
+	"\x01x\x18\x01 \x01(\x05R\x01x\x12\f\n" +
+	"\x01y\x18\x02 \x01(\x05R\x01y\x12\x16\n" +
+	"\x06symbol\x18\x03 \x01(\x05R\x06symbol\x12\x14\n" +
+	"\x05multi\x18\x04 \x01(\x05R\x05multi\x12\x12\n" +
+	"\x04life\x18\x05 \x01(\x05R\x04life\x12\x1a\n" +
+	"\bmoveType\x18\x06 \x01(\x05R\bmoveType\"\xc4\x01\n" +
+	"\x11StickySymbolsData\x12E\n" +
+	"\x12basicComponentData\x18\x01 \x01(\v2\x15.sgc7pb.ComponentDataR\x12basicComponentData\x120\n" +
+	"\bstickies\x18\x02 \x03(\v2\x14.sgc7pb.StickySymbolR\bstickies\x12\x16\n" +
+	"\x06newNum\x18\x03 \x01(\x05R\x06newNum\x12\x1e\n" +
+	"\n" +
+	"removedNum\x18\x04 \x01(\x05R\n" +
+	"removedNum\"\x83\x02\n" +
+	"\x0eTrailBoardData\x12E\n" +
+	"\x12basicComponentData\x18\x01 \x01(\v2\x15.sgc7pb.ComponentDataR\x12basicComponentData\x12\x1a\n" +
+	"\bposition\x18\x02 \x01(\x05R\bposition\x12\x14\n" +
+	"\x05steps\x18\x03 \x01(\x05R\x05steps\x12\x12\n" +
+	"\x04path\x18\x04 \x03(\x05R\x04path\x12\x14\n" +
+	"\x05multi\x18\x05 \x01(\x05R\x05multi\x12 \n" +
+	"\vpendingWins\x18\x06 \x01(\x05R\vpendingWins\x12\x12\n" +
+	"\x04wins\x18\a \x01(\x05R\x04wins\x12\x18\n" +
+	"\aisEnded\x18\b \x01(\bR\aisEnded\"\xed\x01\n" +
+	"\x0eWheelBonusData\x12E\n" +
+	"\x12basicComponentData\x18\x01 \x01(\v2\x15.sgc7pb.ComponentDataR\x12basicComponentData\x12\x16\n" +
+	"\x06wheels\x18\x02 \x03(\x05R\x06wheels\x12\x1a\n" +
+	"\bsegments\x18\x03 \x03(\x05R\bsegments\x12 \n" +
+	"\vsegmentType\x18\x04 \x01(\tR\vsegmentType\x12\x14\n" +
+	"\x05value\x18\x05 \x01(\x05R\x05value\x12\x12\n" +
+	"\x04wins\x18\x06 \x01(\x05R\x04wins\x12\x14\n" +
+	"\x05multi\x18\a \x01(\x05R\x05multi\"\x92\x01\n" +
+	"\x11SymbolUpgradeData\x12E\n" +
+	"\x12basicComponentData\x18\x01 \x01(\v2\x15.sgc7pb.ComponentDataR\x12basicComponentData\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\x05R\x05level\x12 \n" +
+	"\vupgradedNum\x18\x03 \x01(\x05R\vupgradedNum\"\xdb\x01\n" +
+	"\x1aPositionMultiplierGridData\x12E\n" +
+	"\x12basicComponentData\x18\x01 \x01(\v2\x15.sgc7pb.ComponentDataR\x12basicComponentData\x12\x14\n" +
+	"\x05width\x18\x02 \x01(\x05R\x05width\x12\x16\n" +
+	"\x06height\x18\x03 \x01(\x05R\x06height\x12\x16\n" +
+	"\x06multis\x18\x04 \x03(\x05R\x06multis\x12\x1c\n" +
+	"\twinMultis\x18\x05 \x03(\x05R\twinMultis\x12\x12\n" +
+	"\x04wins\x18\x06 \x01(\x05R\x04wins\"\x93\x05\n" +
 	"\tGameParam\x12&\n" +
 	"\x0efirstComponent\x18\x01 \x01(\tR\x0efirstComponent\x126\n" +
 	"\x16nextStepFirstComponent\x18\x02 \x01(\tR\x16nextStepFirstComponent\x12J\n" +
@@ -4603,7 +5240,7 @@ func file_lowcode_proto_rawDescGZIP() []byte {
 	return file_lowcode_proto_rawDescData
 }
 
-var file_lowcode_proto_msgTypes = make([]protoimpl.MessageInfo, 69)
+var file_lowcode_proto_msgTypes = make([]protoimpl.MessageInfo, 76)
 var file_lowcode_proto_goTypes = []any{
 	(*UsedSPGridData)(nil),              // 0: sgc7pb.UsedSPGridData
 	(*ComponentData)(nil),               // 1: sgc7pb.ComponentData
@@ -4669,15 +5306,22 @@ var file_lowcode_proto_goTypes = []any{
 	(*CascadingRegulatorData)(nil),      // 61: sgc7pb.CascadingRegulatorData
 	(*WinResultLimiterData)(nil),        // 62: sgc7pb.WinResultLimiterData
 	(*SymbolValsSPData)(nil),            // 63: sgc7pb.SymbolValsSPData
-	(*GameParam)(nil),                   // 64: sgc7pb.GameParam
-	nil,                                 // 65: sgc7pb.ComponentData.MapUsedSPGridEntry
-	nil,                                 // 66: sgc7pb.GameParam.MapComponentsEntry
-	nil,                                 // 67: sgc7pb.GameParam.MapValsEntry
-	nil,                                 // 68: sgc7pb.GameParam.MapStrValsEntry
-	(*anypb.Any)(nil),                   // 69: google.protobuf.Any
+	(*DynamicHeightReelsData)(nil),      // 64: sgc7pb.DynamicHeightReelsData
+	(*StickySymbol)(nil),                // 65: sgc7pb.StickySymbol
+	(*StickySymbolsData)(nil),           // 66: sgc7pb.StickySymbolsData
+	(*TrailBoardData)(nil),              // 67: sgc7pb.TrailBoardData
+	(*WheelBonusData)(nil),              // 68: sgc7pb.WheelBonusData
+	(*SymbolUpgradeData)(nil),           // 69: sgc7pb.SymbolUpgradeData
+	(*PositionMultiplierGridData)(nil),  // 70: sgc7pb.PositionMultiplierGridData
+	(*GameParam)(nil),                   // 71: sgc7pb.GameParam
+	nil,                                 // 72: sgc7pb.ComponentData.MapUsedSPGridEntry
+	nil,                                 // 73: sgc7pb.GameParam.MapComponentsEntry
+	nil,                                 // 74: sgc7pb.GameParam.MapValsEntry
+	nil,                                 // 75: sgc7pb.GameParam.MapStrValsEntry
+	(*anypb.Any)(nil),                   // 76: google.protobuf.Any
 }
 var file_lowcode_proto_depIdxs = []int32{
-	65, // 0: sgc7pb.ComponentData.mapUsedSPGrid:type_name -> sgc7pb.ComponentData.MapUsedSPGridEntry
+	72, // 0: sgc7pb.ComponentData.mapUsedSPGrid:type_name -> sgc7pb.ComponentData.MapUsedSPGridEntry
 	1,  // 1: sgc7pb.BasicComponentData.basicComponentData:type_name -> sgc7pb.ComponentData
 	1,  // 2: sgc7pb.BookOfData.basicComponentData:type_name -> sgc7pb.ComponentData
 	1,  // 3: sgc7pb.BookOf2Data.basicComponentData:type_name -> sgc7pb.ComponentData
@@ -4739,16 +5383,23 @@ var file_lowcode_proto_depIdxs = []int32{
 	1,  // 59: sgc7pb.CascadingRegulatorData.basicComponentData:type_name -> sgc7pb.ComponentData
 	1,  // 60: sgc7pb.WinResultLimiterData.basicComponentData:type_name -> sgc7pb.ComponentData
 	1,  // 61: sgc7pb.SymbolValsSPData.basicComponentData:type_name -> sgc7pb.ComponentData
-	66, // 62: sgc7pb.GameParam.mapComponents:type_name -> sgc7pb.GameParam.MapComponentsEntry
-	67, // 63: sgc7pb.GameParam.mapVals:type_name -> sgc7pb.GameParam.MapValsEntry
-	68, // 64: sgc7pb.GameParam.mapStrVals:type_name -> sgc7pb.GameParam.MapStrValsEntry
-	0,  // 65: sgc7pb.ComponentData.MapUsedSPGridEntry.value:type_name -> sgc7pb.UsedSPGridData
-	69, // 66: sgc7pb.GameParam.MapComponentsEntry.value:type_name -> google.protobuf.Any
-	67, // [67:67] is the sub-list for method output_type
-	67, // [67:67] is the sub-list for method input_type
-	67, // [67:67] is the sub-list for extension type_name
-	67, // [67:67] is the sub-list for extension extendee
-	0,  // [0:67] is the sub-list for field type_name
+	1,  // 62: sgc7pb.DynamicHeightReelsData.basicComponentData:type_name -> sgc7pb.ComponentData
+	1,  // 63: sgc7pb.StickySymbolsData.basicComponentData:type_name -> sgc7pb.ComponentData
+	65, // 64: sgc7pb.StickySymbolsData.stickies:type_name -> sgc7pb.StickySymbol
+	1,  // 65: sgc7pb.TrailBoardData.basicComponentData:type_name -> sgc7pb.ComponentData
+	1,  // 66: sgc7pb.WheelBonusData.basicComponentData:type_name -> sgc7pb.ComponentData
+	1,  // 67: sgc7pb.SymbolUpgradeData.basicComponentData:type_name -> sgc7pb.ComponentData
+	1,  // 68: sgc7pb.PositionMultiplierGridData.basicComponentData:type_name -> sgc7pb.ComponentData
+	73, // 69: sgc7pb.GameParam.mapComponents:type_name -> sgc7pb.GameParam.MapComponentsEntry
+	74, // 70: sgc7pb.GameParam.mapVals:type_name -> sgc7pb.GameParam.MapValsEntry
+	75, // 71: sgc7pb.GameParam.mapStrVals:type_name -> sgc7pb.GameParam.MapStrValsEntry
+	0,  // 72: sgc7pb.ComponentData.MapUsedSPGridEntry.value:type_name -> sgc7pb.UsedSPGridData
+	76, // 73: sgc7pb.GameParam.MapComponentsEntry.value:type_name -> google.protobuf.Any
+	74, // [74:74] is the sub-list for method output_type
+	74, // [74:74] is the sub-list for method input_type
+	74, // [74:74] is the sub-list for extension type_name
+	74, // [74:74] is the sub-list for extension extendee
+	0,  // [0:74] is the sub-list for field type_name
 }
 
 func init() { file_lowcode_proto_init() }
@@ -4762,7 +5413,7 @@ func file_lowcode_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_lowcode_proto_rawDesc), len(file_lowcode_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   69,
+			NumMessages:   76,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -3,6 +3,7 @@ package simserv
 import (
 	"os"
 
+	"github.com/zhs007/slotsgamecore7/cheatpolicy"
 	"gopkg.in/yaml.v2"
 )
 
@@ -12,6 +13,8 @@ type Config struct {
 	BindAddr    string `yaml:"bindaddr"`
 	IsDebugMode bool   `yaml:"isdebugmode"`
 	LogLevel    string `yaml:"loglevel"`
+	// CheatPolicy - 为空时不接受任何 cheat
+	CheatPolicy *cheatpolicy.Policy `yaml:"cheatpolicy"`
 }
 
 // LoadConfig - load configuration
@@ -1,26 +1,34 @@
 package simserv
 
 import (
+	"context"
 	"log/slog"
+	"time"
 
-	"github.com/bytedance/sonic"
 	"github.com/valyala/fasthttp"
 	goutils "github.com/zhs007/goutils"
 	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	"github.com/zhs007/slotsgamecore7/grpcserv"
 	sgc7http "github.com/zhs007/slotsgamecore7/http"
-	sgc7pbutils "github.com/zhs007/slotsgamecore7/pbutils"
+	"github.com/zhs007/slotsgamecore7/jackpot"
+	"github.com/zhs007/slotsgamecore7/metrics"
 	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+	"github.com/zhs007/slotsgamecore7/roundstore"
 	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
+	"github.com/zhs007/slotsgamecore7/wallet"
 )
 
 // BasicURL - basic url
 const BasicURL = "/game"
 
-// Serv -
+// Serv - the play logic is grpcserv.PlayGame, new servers should use gateway with grpcserv
 type Serv struct {
 	*sgc7http.Serv
-	Service IService
-	Cfg     *Config
+	Service     IService
+	Cfg         *Config
+	roundMgr    *roundstore.RoundMgr
+	metrics     *metrics.GameMetrics
+	stopCleanup context.CancelFunc
 }
 
 // NewServ - new a serv
@@ -29,6 +37,9 @@ func NewServ(service IService, cfg *Config) *Serv {
 		sgc7http.NewServ(cfg.BindAddr, cfg.IsDebugMode),
 		service,
 		cfg,
+		nil,
+		nil,
+		nil,
 	}
 
 	s.RegHandle(goutils.AppendString(BasicURL, "/config"),
@@ -92,12 +103,26 @@ func NewServ(service IService, cfg *Config) *Serv {
 				return
 			}
 
-			ret, err := s.onPlay(params)
+			err = s.Cfg.CheatPolicy.CheckHTTP(ctx, "simserv", s.Cfg.GameCode, params.Cheat)
+			if err != nil {
+				goutils.Warn("gatiserv.Serv.play:CheckHTTP",
+					goutils.Err(err))
+
+				s.metrics.OnError(s.Cfg.GameCode, err)
+
+				s.SetHTTPStatus(ctx, fasthttp.StatusForbidden)
+
+				return
+			}
+
+			ret, err := s.play(ctx, params)
 			if err != nil {
 				goutils.Warn("gatiserv.Serv.play:Play",
 					goutils.Err(err))
 
-				if err == sgc7game.ErrInvalidStake {
+				if err == sgc7game.ErrInvalidStake || err == roundstore.ErrInvalidRoundID || err == roundstore.ErrRoundFinished ||
+					err == roundstore.ErrInvalidPlayerID || err == roundstore.ErrInvalidCommand || err == roundstore.ErrInvalidRoundPlayer ||
+					err == wallet.ErrInsufficientBalance {
 					s.SetHTTPStatus(ctx, fasthttp.StatusBadRequest)
 
 					return
@@ -115,123 +140,149 @@ func NewServ(service IService, cfg *Config) *Serv {
 			s.SetPBResponse(ctx, ret)
 		})
 
-	return s
-}
+	s.RegHandle(goutils.AppendString(BasicURL, "/resume"),
+		func(ctx *fasthttp.RequestCtx, serv *sgc7http.Serv) {
+			if !ctx.Request.Header.IsPost() {
+				s.SetHTTPStatus(ctx, fasthttp.StatusBadRequest)
 
-// ProcCheat - process cheat
-func (serv *Serv) ProcCheat(plugin sgc7plugin.IPlugin, cheat string) error {
-	if cheat != "" {
-		str := goutils.AppendString("[", cheat, "]")
+				return
+			}
 
-		rngs := []int{}
-		err := sonic.Unmarshal([]byte(str), &rngs)
-		if err != nil {
-			return err
-		}
+			if s.roundMgr == nil {
+				s.SetHTTPStatus(ctx, fasthttp.StatusNotFound)
 
-		plugin.SetCache(rngs)
-	}
+				return
+			}
 
-	return nil
-}
+			params := &sgc7pb.RequestResumeRound{}
+			err := s.ParseBody(ctx, params)
+			if err != nil {
+				goutils.Warn("gatiserv.Serv.resume:ParseBody",
+					goutils.Err(err))
 
-// Play - play game
-func (serv *Serv) onPlay(req *sgc7pb.RequestPlay) (*sgc7pb.ReplyPlay, error) {
-	ips := serv.Service.GetGame().NewPlayerState()
-	if req.PlayerState != nil {
-		err := serv.Service.BuildPlayerStateFromPB(ips, req.PlayerState)
-		if err != nil {
-			goutils.Error("BasicService.onPlay:BuildPlayerStateFromPB",
-				goutils.Err(err))
-
-			return nil, err
-		}
-	}
+				s.SetHTTPStatus(ctx, fasthttp.StatusBadRequest)
+
+				return
+			}
+
+			ret, err := s.roundMgr.ResumeRound("", params.PlayerID, params.RoundID)
+			if err != nil {
+				goutils.Warn("gatiserv.Serv.resume:ResumeRound",
+					goutils.Err(err))
+
+				if err == roundstore.ErrRoundNotFound || err == roundstore.ErrInvalidRoundID || err == roundstore.ErrInvalidRoundPlayer {
+					s.SetHTTPStatus(ctx, fasthttp.StatusNotFound)
+
+					return
+				}
+
+				s.SetHTTPStatus(ctx, fasthttp.StatusInternalServerError)
 
-	plugin := serv.Service.GetGame().NewPlugin()
-	defer serv.Service.GetGame().FreePlugin(plugin)
+				return
+			}
 
-	serv.ProcCheat(plugin, req.Cheat)
+			ctx.Response.Header.Set("Access-Control-Allow-Origin", "*")
+			ctx.Response.Header.Set("Access-Control-Allow-Credentials", "true")
+			ctx.Response.Header.Set("Access-Control-Allow-Headers", "Origin, X-Requested-With, Content-Type, Accept, Connection, User-Agent, Cookie")
 
-	stake := sgc7pbutils.BuildStake(req.Stake)
-	err := serv.Service.GetGame().CheckStake(stake)
+			s.SetPBResponse(ctx, ret)
+		})
+
+	return s
+}
+
+// SetRoundStore - save the rounds in store, so the unfinished rounds can be resumed,
+//
+//	w can be nil, if it is not nil, the bets and wins are in wallet, and the pending transactions are recovered here
+func (serv *Serv) SetRoundStore(store roundstore.RoundStore, w wallet.Wallet) error {
+	roundMgr := roundstore.NewRoundMgr(store, w)
+
+	num, err := roundMgr.RecoverRounds()
 	if err != nil {
-		goutils.Error("BasicService.onPlay:CheckStake",
-			slog.Any("stake", stake),
+		goutils.Error("Serv.SetRoundStore:RecoverRounds",
 			goutils.Err(err))
 
-		return nil, err
+		return err
 	}
 
-	results := []*sgc7game.PlayResult{}
-	gameData := serv.Service.GetGame().NewGameData(stake)
-	if gameData == nil {
-		goutils.Error("BasicService.onPlay:NewGameData",
-			goutils.Err(sgc7game.ErrInvalidStake))
+	if num > 0 {
+		goutils.Info("Serv.SetRoundStore:RecoverRounds",
+			slog.Int("rounds", num))
+	}
 
-		return nil, err
+	if serv.stopCleanup != nil {
+		serv.stopCleanup()
 	}
 
-	defer serv.Service.GetGame().DeleteGameData(gameData)
+	ctx, cancel := context.WithCancel(context.Background())
+	roundMgr.StartCleanup(ctx, roundstore.DefaultCleanupInterval)
 
-	cmd := req.Command
+	serv.roundMgr = roundMgr
+	serv.stopCleanup = cancel
 
-	for {
-		if cmd == "" {
-			cmd = "SPIN"
-		}
+	return nil
+}
 
-		pr, err := serv.Service.GetGame().Play(plugin, cmd, req.ClientParams, ips, stake, results, gameData)
-		if err != nil {
-			goutils.Error("BasicService.onPlay:Play",
-				slog.Int("results", len(results)),
-				goutils.Err(err))
+// Stop - stop the cleanup of RoundStore and the http server
+func (serv *Serv) Stop() error {
+	if serv.stopCleanup != nil {
+		serv.stopCleanup()
+	}
 
-			return nil, err
-		}
+	return serv.Serv.Stop()
+}
 
-		if pr == nil {
-			break
-		}
+// SetJackpotPool - the jackpots are paid from jp in the rounds, SetRoundStore must be called first
+func (serv *Serv) SetJackpotPool(jp jackpot.JackpotPool) error {
+	if serv.roundMgr == nil {
+		goutils.Error("Serv.SetJackpotPool",
+			goutils.Err(roundstore.ErrNoRoundStore))
 
-		results = append(results, pr)
-		if pr.IsFinish {
-			break
-		}
+		return roundstore.ErrNoRoundStore
+	}
 
-		if pr.IsWait {
-			break
-		}
+	serv.roundMgr.Jackpot = jp
 
-		if len(pr.NextCmds) > 0 {
-			cmd = pr.NextCmds[0]
-		} else {
-			cmd = ""
-		}
-	}
+	return nil
+}
 
-	pr := &sgc7pb.ReplyPlay{
-		RandomNumbers: sgc7pbutils.BuildPBRngs(plugin.GetUsedRngs()),
+// play - play with RoundStore if it is set
+func (serv *Serv) play(ctx context.Context, req *sgc7pb.RequestPlay) (*sgc7pb.ReplyPlay, error) {
+	if serv.roundMgr == nil {
+		return serv.onPlayWithMetrics(ctx, req)
 	}
 
-	ps, err := serv.Service.BuildPBPlayerState(ips)
-	if err != nil {
-		goutils.Error("BasicService.onPlay:BuildPlayerState",
-			goutils.Err(err))
+	return serv.roundMgr.Play("", req, func(req *sgc7pb.RequestPlay) (*sgc7pb.ReplyPlay, error) {
+		return serv.onPlayWithMetrics(ctx, req)
+	})
+}
 
-		return nil, err
-	}
+// SetMetrics - record the plays in m, use metrics.NewServ to serve m.Registry
+func (serv *Serv) SetMetrics(m *metrics.GameMetrics) {
+	serv.metrics = m
 
-	pr.PlayerState = ps
+	m.OnCollect(func() {
+		m.SetGamePool(serv.Cfg.GameCode, "", serv.Service.GetGame())
+	})
+}
 
-	if len(results) > 0 {
-		AddPlayResult(serv.Service, pr, results)
+// onPlayWithMetrics - onPlay, and record it in metrics
+func (serv *Serv) onPlayWithMetrics(ctx context.Context, req *sgc7pb.RequestPlay) (*sgc7pb.ReplyPlay, error) {
+	st := time.Now()
 
-		lastr := results[len(results)-1]
+	res, err := serv.onPlay(ctx, req)
 
-		pr.Finished = lastr.IsFinish
-		pr.NextCommands = lastr.NextCmds
-	}
+	serv.metrics.OnPlayPB(serv.Cfg.GameCode, req, res, err, time.Since(st))
 
-	return pr, nil
+	return res, err
+}
+
+// ProcCheat - process cheat
+func (serv *Serv) ProcCheat(plugin sgc7plugin.IPlugin, cheat string) error {
+	return grpcserv.ProcCheat(plugin, cheat)
+}
+
+// Play - play game
+func (serv *Serv) onPlay(ctx context.Context, req *sgc7pb.RequestPlay) (*sgc7pb.ReplyPlay, error) {
+	return grpcserv.PlayGameWithContext(ctx, serv.Service, serv.Service.GetGame(), req)
 }
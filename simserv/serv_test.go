@@ -0,0 +1,105 @@
+package simserv
+
+import (
+	"net"
+	"testing"
+
+	"github.com/bytedance/sonic"
+	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
+	"github.com/zhs007/slotsgamecore7/cheatpolicy"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	"github.com/zhs007/slotsgamecore7/lowcode"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// testService - simserv.IService for lowcode game
+type testService struct {
+	*BasicService
+}
+
+// BuildPBGameModParam - any -> *anypb.Any
+func (sv *testService) BuildPBGameModParam(gp any) (*anypb.Any, error) {
+	mygp, isok := gp.(*lowcode.GameParams)
+	if !isok {
+		return nil, sgc7game.ErrInvalidParam
+	}
+
+	return anypb.New(&mygp.GameParam)
+}
+
+// BuildPBGameModParamFromAny - *anypb.Any -> any
+func (sv *testService) BuildPBGameModParamFromAny(msg *anypb.Any) (any, error) {
+	mygp := &sgc7pb.GameParam{}
+
+	err := msg.UnmarshalTo(mygp)
+	if err != nil {
+		return nil, err
+	}
+
+	return mygp, nil
+}
+
+func playWithCheat(t *testing.T, serv *Serv, ip string, cheat string) int {
+	req := &sgc7pb.RequestPlay{
+		Stake: &sgc7pb.Stake{
+			CoinBet:  1,
+			CashBet:  20,
+			Currency: "EUR",
+		},
+		Command: "SPIN",
+		Cheat:   cheat,
+	}
+
+	body, err := sonic.Marshal(req)
+	assert.NoError(t, err)
+
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Init(&fasthttp.Request{}, &net.TCPAddr{IP: net.ParseIP(ip), Port: 1234}, nil)
+	ctx.Request.Header.SetMethod(fasthttp.MethodPost)
+	ctx.Request.SetRequestURI("/game/play")
+	ctx.Request.SetBody(body)
+
+	serv.HandleFastHTTP(ctx)
+
+	return ctx.Response.StatusCode()
+}
+
+func Test_ServCheatPolicy(t *testing.T) {
+	game, err := lowcode.NewGame2("../unittestdata/testgame.json", func() sgc7plugin.IPlugin {
+		return sgc7plugin.NewFastPlugin()
+	}, lowcode.NewBasicRNG, lowcode.NewEmptyFeatureLevel)
+	assert.NoError(t, err)
+
+	bs, err := NewBasicService(game)
+	assert.NoError(t, err)
+
+	service := &testService{bs}
+
+	cfg := &Config{
+		GameCode: "game1",
+		BindAddr: "127.0.0.1:0",
+	}
+
+	serv := NewServ(service, cfg)
+
+	// 默认不接受 cheat
+	assert.Equal(t, fasthttp.StatusForbidden, playWithCheat(t, serv, "127.0.0.1", "1,2,3"))
+	assert.Equal(t, fasthttp.StatusOK, playWithCheat(t, serv, "127.0.0.1", ""))
+
+	cfg.CheatPolicy = &cheatpolicy.Policy{
+		IsEnabled:    true,
+		GameCodes:    []string{"game1"},
+		AllowCallers: []string{"127.0.0.1"},
+	}
+
+	assert.Equal(t, fasthttp.StatusOK, playWithCheat(t, serv, "127.0.0.1", "1,2,3"))
+	assert.Equal(t, fasthttp.StatusForbidden, playWithCheat(t, serv, "10.0.0.1", "1,2,3"))
+
+	cfg.CheatPolicy.GameCodes = []string{"game2"}
+	assert.Equal(t, fasthttp.StatusForbidden, playWithCheat(t, serv, "127.0.0.1", "1,2,3"))
+
+	t.Logf("Test_ServCheatPolicy OK")
+}
@@ -3,11 +3,12 @@ package stats2
 import "github.com/zhs007/goutils"
 
 type Cache struct {
-	MapStats  map[string]*Feature
-	Bet       int
-	TotalWin  int64
-	RespinArr []string
-	rngs      []int
+	MapStats   map[string]*Feature
+	Bet        int
+	TotalWin   int64
+	JackpotWin int64 // 奖池的奖励，不在 TotalWin 里
+	RespinArr  []string
+	rngs       []int
 }
 
 func (s2 *Cache) check() {
@@ -55,6 +56,11 @@ func (s2 *Cache) ProcStatsOnEnding(win int64, rngs []int) {
 	s2.rngs = rngs
 }
 
+// ProcStatsJackpotOnEnding - the wins paid by the jackpot pools
+func (s2 *Cache) ProcStatsJackpotOnEnding(win int64) {
+	s2.JackpotWin = win
+}
+
 func (s2 *Cache) ProcStatsWins(name string, win int64) {
 	f2, isok := s2.MapStats[name]
 	if isok {
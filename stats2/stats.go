@@ -11,21 +11,36 @@ import (
 	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
 )
 
+// JackpotContributionPrecision - the jackpot contribution is in 1/JackpotContributionPrecision of the bet
+const JackpotContributionPrecision = 10000
+
 type Stats struct {
-	MapStats       map[string]*Feature `json:"mapStats"`
-	chanBet        chan int            `json:"-"`
-	chanCache      chan *Cache         `json:"-"`
-	chanRNGs       chan *rngData       `json:"-"`
-	TotalBet       int64               `json:"totalBet"`
-	TotalWins      int64               `json:"totalWins"`
-	BetTimes       int64               `json:"betTimes"`
-	MaxWins        int64               `json:"maxWins"`
-	MaxWinTimes    int64               `json:"maxWinTimes"`
-	MaxWinRNGs     []int               `json:"maxWinRNGs"`
-	BetEndingTimes int64               `json:"-"`
-	Components     []string            `json:"components"`
-	Wins           *StatsWins          `json:"wins"`
-	MapRNGs        map[string][]int    `json:"rngs"`
+	MapStats            map[string]*Feature `json:"mapStats"`
+	chanBet             chan int            `json:"-"`
+	chanCache           chan *Cache         `json:"-"`
+	chanRNGs            chan *rngData       `json:"-"`
+	TotalBet            int64               `json:"totalBet"`
+	TotalWins           int64               `json:"totalWins"`
+	BetTimes            int64               `json:"betTimes"`
+	MaxWins             int64               `json:"maxWins"`
+	MaxWinTimes         int64               `json:"maxWinTimes"`
+	MaxWinRNGs          []int               `json:"maxWinRNGs"`
+	BetEndingTimes      int64               `json:"-"`
+	Components          []string            `json:"components"`
+	Wins                *StatsWins          `json:"wins"`
+	MapRNGs             map[string][]int    `json:"rngs"`
+	JackpotWins         int64               `json:"jackpotWins"`         // 奖池的奖励，不在 TotalWins 里
+	JackpotContribution int                 `json:"jackpotContribution"` // 奖池从 bet 里抽的比例，单位是 1/JackpotContributionPrecision
+}
+
+// SetJackpotContribution - the rate of the bet which goes into the jackpot pools, in 1/JackpotContributionPrecision
+func (s2 *Stats) SetJackpotContribution(contribution int) {
+	s2.JackpotContribution = contribution
+}
+
+// GetJackpotContributionRTP - the rtp of the contribution to the jackpot pools
+func (s2 *Stats) GetJackpotContributionRTP() float64 {
+	return float64(s2.JackpotContribution) / JackpotContributionPrecision
 }
 
 func (s2 *Stats) PushRNGs(name string, rngs []int) {
@@ -66,6 +81,7 @@ func (s2 *Stats) onCache(cache *Cache) {
 	}
 
 	s2.TotalWins += cache.TotalWin
+	s2.JackpotWins += cache.JackpotWin
 
 	s2.Wins.AddWin(cache.TotalWin)
 }
@@ -95,6 +111,10 @@ func (s2 *Stats) saveBasicSheet(f *excelize.File) {
 	f.SetCellValue(sheet, goutils.Pos2Cell(0, 4), "max wins")
 	f.SetCellValue(sheet, goutils.Pos2Cell(0, 5), "times of the max wins")
 	f.SetCellValue(sheet, goutils.Pos2Cell(0, 6), "rngs for max win")
+	f.SetCellValue(sheet, goutils.Pos2Cell(0, 7), "jackpot wins")
+	f.SetCellValue(sheet, goutils.Pos2Cell(0, 8), "jackpot rtp")
+	f.SetCellValue(sheet, goutils.Pos2Cell(0, 9), "jackpot contribution rtp")
+	f.SetCellValue(sheet, goutils.Pos2Cell(0, 10), "rtp with jackpot contribution")
 
 	f.SetCellValue(sheet, goutils.Pos2Cell(1, 0), s2.BetEndingTimes)
 	f.SetCellValue(sheet, goutils.Pos2Cell(1, 1), s2.TotalBet)
@@ -109,6 +129,19 @@ func (s2 *Stats) saveBasicSheet(f *excelize.File) {
 	f.SetCellValue(sheet, goutils.Pos2Cell(1, 4), s2.MaxWins)
 	f.SetCellValue(sheet, goutils.Pos2Cell(1, 5), s2.MaxWinTimes)
 	f.SetCellValue(sheet, goutils.Pos2Cell(1, 6), sgc7plugin.GenRngsString(s2.MaxWinRNGs))
+
+	// rtp 是 base rtp，奖池的奖励按配置的平均值算，真实的奖池 rtp 是抽成的比例
+	f.SetCellValue(sheet, goutils.Pos2Cell(1, 7), s2.JackpotWins)
+
+	if s2.TotalBet > 0 {
+		f.SetCellValue(sheet, goutils.Pos2Cell(1, 8), float64(s2.JackpotWins)/float64(s2.TotalBet))
+		f.SetCellValue(sheet, goutils.Pos2Cell(1, 10), float64(s2.TotalWins)/float64(s2.TotalBet)+s2.GetJackpotContributionRTP())
+	} else {
+		f.SetCellValue(sheet, goutils.Pos2Cell(1, 8), 0)
+		f.SetCellValue(sheet, goutils.Pos2Cell(1, 10), 0)
+	}
+
+	f.SetCellValue(sheet, goutils.Pos2Cell(1, 9), s2.GetJackpotContributionRTP())
 }
 
 func (s2 *Stats) saveWins(f *excelize.File) {
@@ -234,7 +267,12 @@ func (s2 *Stats) Merge(src *Stats) {
 
 	s2.TotalBet += src.TotalBet
 	s2.TotalWins += src.TotalWins
+	s2.JackpotWins += src.JackpotWins
 	s2.BetTimes += src.BetTimes
+
+	if s2.JackpotContribution == 0 {
+		s2.JackpotContribution = src.JackpotContribution
+	}
 	if src.MaxWins > s2.MaxWins {
 		s2.MaxWins = src.MaxWins
 		s2.MaxWinTimes = src.MaxWinTimes
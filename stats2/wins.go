@@ -268,6 +268,11 @@ func (wins *StatsWins) saveSheet(f *excelize.File, sheet string, sx, sy int, s2
 	}
 }
 
+// CalcSD - standard deviation of the wins in multiples of bet
+func (wins *StatsWins) CalcSD(bet int) float64 {
+	return wins.calcSD(bet)
+}
+
 func (wins *StatsWins) calcSD(bet int) float64 {
 	lstRets := []float64{}
 	lstWeights := []float64{}
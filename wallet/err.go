@@ -0,0 +1,16 @@
+package wallet
+
+import "errors"
+
+var (
+	// ErrInsufficientBalance - insufficient balance
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	// ErrInvalidAmount - invalid amount
+	ErrInvalidAmount = errors.New("invalid amount")
+	// ErrInvalidTransaction - invalid transaction
+	ErrInvalidTransaction = errors.New("invalid transaction")
+	// ErrTransactionConflict - the same txID with the different data
+	ErrTransactionConflict = errors.New("transaction conflict")
+	// ErrTransactionRolledBack - the transaction has been rolled back
+	ErrTransactionRolledBack = errors.New("transaction rolled back")
+)
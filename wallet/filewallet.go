@@ -0,0 +1,101 @@
+package wallet
+
+import (
+	"log/slog"
+	"os"
+	"path"
+
+	"github.com/bytedance/sonic"
+	"github.com/zhs007/goutils"
+)
+
+// FileWallet - MemWallet saved in a json file after every transaction, for development
+type FileWallet struct {
+	*MemWallet
+	fn string
+}
+
+// save - write a temporary file and rename it
+func (w *FileWallet) save(data *memWalletData) error {
+	buf, err := sonic.Marshal(data)
+	if err != nil {
+		goutils.Error("FileWallet.save:Marshal",
+			goutils.Err(err))
+
+		return err
+	}
+
+	f, err := os.CreateTemp(path.Dir(w.fn), path.Base(w.fn)+".*.tmp")
+	if err != nil {
+		goutils.Error("FileWallet.save:CreateTemp",
+			slog.String("fn", w.fn),
+			goutils.Err(err))
+
+		return err
+	}
+
+	tmpfn := f.Name()
+
+	_, err = f.Write(buf)
+	if err == nil {
+		err = f.Sync()
+	}
+
+	f.Close()
+
+	if err == nil {
+		err = os.Rename(tmpfn, w.fn)
+	}
+
+	if err != nil {
+		goutils.Error("FileWallet.save:Write",
+			slog.String("fn", w.fn),
+			goutils.Err(err))
+
+		os.Remove(tmpfn)
+
+		return err
+	}
+
+	return nil
+}
+
+// NewFileWallet - new a FileWallet, load fn if it exists
+func NewFileWallet(fn string, defaultBalance int64) (*FileWallet, error) {
+	w := &FileWallet{
+		MemWallet: NewMemWallet(defaultBalance),
+		fn:        fn,
+	}
+
+	buf, err := os.ReadFile(fn)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			goutils.Error("NewFileWallet:ReadFile",
+				slog.String("fn", fn),
+				goutils.Err(err))
+
+			return nil, err
+		}
+	} else {
+		err = sonic.Unmarshal(buf, w.data)
+		if err != nil {
+			goutils.Error("NewFileWallet:Unmarshal",
+				slog.String("fn", fn),
+				goutils.Err(err))
+
+			return nil, err
+		}
+
+		if w.data.Balances == nil {
+			w.data.Balances = make(map[string]int64)
+		}
+
+		if w.data.Txs == nil {
+			w.data.Txs = make(map[string]*txRecord)
+		}
+	}
+
+	w.onChanged = w.save
+
+	return w, nil
+}
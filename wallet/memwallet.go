@@ -0,0 +1,271 @@
+package wallet
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/zhs007/goutils"
+)
+
+const (
+	txTypeDebit    = "debit"
+	txTypeCredit   = "credit"
+	txTypeRollback = "rollback"
+)
+
+// txRecord - a transaction in MemWallet
+type txRecord struct {
+	Type         string       `json:"type"`
+	Tx           *Transaction `json:"tx"`
+	IsRolledBack bool         `json:"isRolledBack"`
+}
+
+// memWalletData - the data of MemWallet, FileWallet saves it
+type memWalletData struct {
+	Balances map[string]int64     `json:"balances"` // playerID:currency -> balance
+	Txs      map[string]*txRecord `json:"txs"`
+}
+
+// MemWallet - Wallet in memory, for development
+type MemWallet struct {
+	lock           sync.Mutex
+	data           *memWalletData
+	defaultBalance int64
+	onChanged      func(data *memWalletData) error // 在锁里调用
+}
+
+func getBalanceKey(playerID string, currency string) string {
+	return goutils.AppendString(playerID, ":", currency)
+}
+
+func (w *MemWallet) getBalance(playerID string, currency string) int64 {
+	balance, isok := w.data.Balances[getBalanceKey(playerID, currency)]
+	if !isok {
+		return w.defaultBalance
+	}
+
+	return balance
+}
+
+func (w *MemWallet) setBalance(playerID string, currency string, balance int64) {
+	w.data.Balances[getBalanceKey(playerID, currency)] = balance
+}
+
+// commit - 改完以后调用，失败时恢复 balance 和 transaction
+func (w *MemWallet) commit(tx *Transaction, oldBalance int64, oldRecord *txRecord) error {
+	if w.onChanged == nil {
+		return nil
+	}
+
+	err := w.onChanged(w.data)
+	if err != nil {
+		w.setBalance(tx.PlayerID, tx.Currency, oldBalance)
+
+		if oldRecord != nil {
+			w.data.Txs[tx.TxID] = oldRecord
+		} else {
+			delete(w.data.Txs, tx.TxID)
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// GetBalance - get the balance of the player
+func (w *MemWallet) GetBalance(playerID string, currency string) (int64, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return w.getBalance(playerID, currency), nil
+}
+
+// Debit - debit the bet
+func (w *MemWallet) Debit(tx *Transaction) (int64, error) {
+	if !tx.isValid() {
+		goutils.Error("MemWallet.Debit",
+			slog.Any("tx", tx),
+			goutils.Err(ErrInvalidTransaction))
+
+		return 0, ErrInvalidTransaction
+	}
+
+	if tx.Amount < 0 {
+		goutils.Error("MemWallet.Debit",
+			slog.Any("tx", tx),
+			goutils.Err(ErrInvalidAmount))
+
+		return 0, ErrInvalidAmount
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	balance := w.getBalance(tx.PlayerID, tx.Currency)
+
+	record, isok := w.data.Txs[tx.TxID]
+	if isok {
+		if record.IsRolledBack {
+			goutils.Error("MemWallet.Debit",
+				slog.Any("tx", tx),
+				goutils.Err(ErrTransactionRolledBack))
+
+			return balance, ErrTransactionRolledBack
+		}
+
+		if record.Type != txTypeDebit || !record.Tx.isSame(tx) {
+			goutils.Error("MemWallet.Debit",
+				slog.Any("tx", tx),
+				goutils.Err(ErrTransactionConflict))
+
+			return balance, ErrTransactionConflict
+		}
+
+		return balance, nil
+	}
+
+	if balance < tx.Amount {
+		goutils.Error("MemWallet.Debit",
+			slog.Any("tx", tx),
+			slog.Int64("balance", balance),
+			goutils.Err(ErrInsufficientBalance))
+
+		return balance, ErrInsufficientBalance
+	}
+
+	w.setBalance(tx.PlayerID, tx.Currency, balance-tx.Amount)
+	w.data.Txs[tx.TxID] = &txRecord{Type: txTypeDebit, Tx: tx}
+
+	err := w.commit(tx, balance, nil)
+	if err != nil {
+		goutils.Error("MemWallet.Debit:commit",
+			slog.Any("tx", tx),
+			goutils.Err(err))
+
+		return balance, err
+	}
+
+	return balance - tx.Amount, nil
+}
+
+// Credit - credit the win
+func (w *MemWallet) Credit(tx *Transaction) (int64, error) {
+	if !tx.isValid() {
+		goutils.Error("MemWallet.Credit",
+			slog.Any("tx", tx),
+			goutils.Err(ErrInvalidTransaction))
+
+		return 0, ErrInvalidTransaction
+	}
+
+	if tx.Amount < 0 {
+		goutils.Error("MemWallet.Credit",
+			slog.Any("tx", tx),
+			goutils.Err(ErrInvalidAmount))
+
+		return 0, ErrInvalidAmount
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	balance := w.getBalance(tx.PlayerID, tx.Currency)
+
+	record, isok := w.data.Txs[tx.TxID]
+	if isok {
+		if record.Type != txTypeCredit || !record.Tx.isSame(tx) {
+			goutils.Error("MemWallet.Credit",
+				slog.Any("tx", tx),
+				goutils.Err(ErrTransactionConflict))
+
+			return balance, ErrTransactionConflict
+		}
+
+		return balance, nil
+	}
+
+	w.setBalance(tx.PlayerID, tx.Currency, balance+tx.Amount)
+	w.data.Txs[tx.TxID] = &txRecord{Type: txTypeCredit, Tx: tx}
+
+	err := w.commit(tx, balance, nil)
+	if err != nil {
+		goutils.Error("MemWallet.Credit:commit",
+			slog.Any("tx", tx),
+			goutils.Err(err))
+
+		return balance, err
+	}
+
+	return balance + tx.Amount, nil
+}
+
+// Rollback - rollback a debit
+func (w *MemWallet) Rollback(tx *Transaction) (int64, error) {
+	if !tx.isValid() {
+		goutils.Error("MemWallet.Rollback",
+			slog.Any("tx", tx),
+			goutils.Err(ErrInvalidTransaction))
+
+		return 0, ErrInvalidTransaction
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	balance := w.getBalance(tx.PlayerID, tx.Currency)
+
+	record, isok := w.data.Txs[tx.TxID]
+	if !isok {
+		// 没有找到也要记下来，这样之后到达的 debit 会被拒绝
+		w.data.Txs[tx.TxID] = &txRecord{Type: txTypeRollback, Tx: tx, IsRolledBack: true}
+
+		err := w.commit(tx, balance, nil)
+		if err != nil {
+			goutils.Error("MemWallet.Rollback:commit",
+				slog.Any("tx", tx),
+				goutils.Err(err))
+
+			return balance, err
+		}
+
+		return balance, nil
+	}
+
+	if record.IsRolledBack {
+		return balance, nil
+	}
+
+	if record.Type != txTypeDebit {
+		goutils.Error("MemWallet.Rollback",
+			slog.Any("tx", tx),
+			goutils.Err(ErrTransactionConflict))
+
+		return balance, ErrTransactionConflict
+	}
+
+	w.setBalance(record.Tx.PlayerID, record.Tx.Currency, balance+record.Tx.Amount)
+	w.data.Txs[tx.TxID] = &txRecord{Type: txTypeDebit, Tx: record.Tx, IsRolledBack: true}
+
+	err := w.commit(record.Tx, balance, record)
+	if err != nil {
+		goutils.Error("MemWallet.Rollback:commit",
+			slog.Any("tx", tx),
+			goutils.Err(err))
+
+		return balance, err
+	}
+
+	return balance + record.Tx.Amount, nil
+}
+
+// NewMemWallet - new a MemWallet, the new players have defaultBalance
+func NewMemWallet(defaultBalance int64) *MemWallet {
+	return &MemWallet{
+		data: &memWalletData{
+			Balances: make(map[string]int64),
+			Txs:      make(map[string]*txRecord),
+		},
+		defaultBalance: defaultBalance,
+	}
+}
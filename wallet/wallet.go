@@ -0,0 +1,35 @@
+package wallet
+
+// Transaction - a wallet transaction
+type Transaction struct {
+	TxID     string `json:"txID"` // idempotency key，同一个 TxID 只会生效一次
+	PlayerID string `json:"playerID"`
+	RoundID  string `json:"roundID"`
+	Currency string `json:"currency"`
+	Amount   int64  `json:"amount"`
+}
+
+// isValid - is it a valid transaction
+func (tx *Transaction) isValid() bool {
+	return tx.TxID != "" && tx.PlayerID != ""
+}
+
+// isSame - same transaction
+func (tx *Transaction) isSame(tx1 *Transaction) bool {
+	return tx.PlayerID == tx1.PlayerID && tx.RoundID == tx1.RoundID && tx.Currency == tx1.Currency && tx.Amount == tx1.Amount
+}
+
+// Wallet - the wallet of the players, all the transactions are idempotent with TxID,
+//
+//	the implementations must be safe for concurrent use
+type Wallet interface {
+	// GetBalance - get the balance of the player
+	GetBalance(playerID string, currency string) (int64, error)
+	// Debit - debit the bet, return the balance
+	Debit(tx *Transaction) (int64, error)
+	// Credit - credit the win, return the balance
+	Credit(tx *Transaction) (int64, error)
+	// Rollback - rollback a debit, return the balance,
+	//	it's not an error if the debit is not found, and the debit with the same txID will be rejected later
+	Rollback(tx *Transaction) (int64, error)
+}
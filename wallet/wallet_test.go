@@ -0,0 +1,111 @@
+package wallet
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testWallet(t *testing.T, w Wallet) {
+	balance, err := w.GetBalance("p1", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), balance)
+
+	_, err = w.Debit(&Transaction{TxID: "", PlayerID: "p1", Currency: "EUR", Amount: 10})
+	assert.Equal(t, ErrInvalidTransaction, err)
+
+	_, err = w.Debit(&Transaction{TxID: "tx0", PlayerID: "p1", Currency: "EUR", Amount: -10})
+	assert.Equal(t, ErrInvalidAmount, err)
+
+	_, err = w.Debit(&Transaction{TxID: "tx0", PlayerID: "p1", Currency: "EUR", Amount: 1000})
+	assert.Equal(t, ErrInsufficientBalance, err)
+
+	balance, err = w.Debit(&Transaction{TxID: "tx1", PlayerID: "p1", RoundID: "r1", Currency: "EUR", Amount: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(90), balance)
+
+	// idempotent
+	balance, err = w.Debit(&Transaction{TxID: "tx1", PlayerID: "p1", RoundID: "r1", Currency: "EUR", Amount: 10})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(90), balance)
+
+	_, err = w.Debit(&Transaction{TxID: "tx1", PlayerID: "p1", RoundID: "r1", Currency: "EUR", Amount: 20})
+	assert.Equal(t, ErrTransactionConflict, err)
+
+	balance, err = w.Credit(&Transaction{TxID: "tx2", PlayerID: "p1", RoundID: "r1", Currency: "EUR", Amount: 25})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(115), balance)
+
+	balance, err = w.Credit(&Transaction{TxID: "tx2", PlayerID: "p1", RoundID: "r1", Currency: "EUR", Amount: 25})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(115), balance)
+
+	_, err = w.Credit(&Transaction{TxID: "tx1", PlayerID: "p1", RoundID: "r1", Currency: "EUR", Amount: 10})
+	assert.Equal(t, ErrTransactionConflict, err)
+
+	_, err = w.Rollback(&Transaction{TxID: "tx2", PlayerID: "p1", Currency: "EUR"})
+	assert.Equal(t, ErrTransactionConflict, err)
+
+	// the other currency
+	balance, err = w.Debit(&Transaction{TxID: "tx3", PlayerID: "p1", RoundID: "r2", Currency: "USD", Amount: 30})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(70), balance)
+
+	balance, err = w.Rollback(&Transaction{TxID: "tx3", PlayerID: "p1", Currency: "USD"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), balance)
+
+	balance, err = w.Rollback(&Transaction{TxID: "tx3", PlayerID: "p1", Currency: "USD"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), balance)
+
+	_, err = w.Debit(&Transaction{TxID: "tx3", PlayerID: "p1", RoundID: "r2", Currency: "USD", Amount: 30})
+	assert.Equal(t, ErrTransactionRolledBack, err)
+
+	// rollback before debit
+	balance, err = w.Rollback(&Transaction{TxID: "tx4", PlayerID: "p2", Currency: "EUR"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100), balance)
+
+	_, err = w.Debit(&Transaction{TxID: "tx4", PlayerID: "p2", RoundID: "r3", Currency: "EUR", Amount: 10})
+	assert.Equal(t, ErrTransactionRolledBack, err)
+
+	balance, err = w.GetBalance("p1", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(115), balance)
+}
+
+func Test_MemWallet(t *testing.T) {
+	testWallet(t, NewMemWallet(100))
+
+	t.Logf("Test_MemWallet OK")
+}
+
+func Test_FileWallet(t *testing.T) {
+	fn := "../unittestdata/wallet.json"
+	os.Remove(fn)
+	defer os.Remove(fn)
+
+	w, err := NewFileWallet(fn, 100)
+	assert.NoError(t, err)
+
+	testWallet(t, w)
+
+	// reload
+	w1, err := NewFileWallet(fn, 100)
+	assert.NoError(t, err)
+
+	balance, err := w1.GetBalance("p1", "EUR")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(115), balance)
+
+	balance, err = w1.Credit(&Transaction{TxID: "tx2", PlayerID: "p1", RoundID: "r1", Currency: "EUR", Amount: 25})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(115), balance)
+
+	_, err = w1.Debit(&Transaction{TxID: "tx3", PlayerID: "p1", RoundID: "r2", Currency: "USD", Amount: 30})
+	assert.Equal(t, ErrTransactionRolledBack, err)
+
+	t.Logf("Test_FileWallet OK")
+}
@@ -0,0 +1,123 @@
+package wsserv
+
+import (
+	"errors"
+
+	"github.com/bytedance/sonic"
+	goutils "github.com/zhs007/goutils"
+	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
+	"golang.org/x/net/websocket"
+)
+
+// Client - websocket client
+type Client struct {
+	conn *websocket.Conn
+	// OnMessage - 每收到一个消息都会调用，可以一步一步地处理结果
+	OnMessage func(msg *Message)
+}
+
+// NewClient - new a client, url is like ws://127.0.0.1:8080/ws
+func NewClient(url string, origin string) (*Client, error) {
+	conn, err := websocket.Dial(url, "", origin)
+	if err != nil {
+		goutils.Error("wsserv.NewClient:Dial",
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	return &Client{
+		conn: conn,
+	}, nil
+}
+
+// Close - close the connection
+func (client *Client) Close() error {
+	return client.conn.Close()
+}
+
+// Play - start a round
+func (client *Client) Play(stake *sgc7pb.Stake, cmd string, params string, ps *sgc7pb.PlayerState, cheat string) ([]*Message, error) {
+	return client.Send(&Message{
+		Type:         MsgPlay,
+		Stake:        stake,
+		Command:      cmd,
+		ClientParams: params,
+		PlayerState:  ps,
+		Cheat:        cheat,
+	})
+}
+
+// Next - send the next command when the round is waiting
+func (client *Client) Next(cmd string, params string) ([]*Message, error) {
+	return client.Send(&Message{
+		Type:         MsgPlay,
+		Command:      cmd,
+		ClientParams: params,
+	})
+}
+
+// Resume - reconnect to an unfinished round
+func (client *Client) Resume(roundID string) ([]*Message, error) {
+	return client.Send(&Message{
+		Type:    MsgResume,
+		RoundID: roundID,
+	})
+}
+
+// Send - send a message, and receive the messages until wait, end or error,
+//
+//	the last message is wait or end if there is no error.
+func (client *Client) Send(msg *Message) ([]*Message, error) {
+	buf, err := sonic.Marshal(msg)
+	if err != nil {
+		goutils.Error("Client.Send:Marshal",
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	err = websocket.Message.Send(client.conn, string(buf))
+	if err != nil {
+		goutils.Error("Client.Send:Send",
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	lst := []*Message{}
+
+	for {
+		var str string
+		err = websocket.Message.Receive(client.conn, &str)
+		if err != nil {
+			goutils.Error("Client.Send:Receive",
+				goutils.Err(err))
+
+			return lst, err
+		}
+
+		cur := &Message{}
+		err = sonic.Unmarshal([]byte(str), cur)
+		if err != nil {
+			goutils.Error("Client.Send:Unmarshal",
+				goutils.Err(err))
+
+			return lst, err
+		}
+
+		if client.OnMessage != nil {
+			client.OnMessage(cur)
+		}
+
+		if cur.Type == MsgError {
+			return lst, errors.New(cur.Err)
+		}
+
+		lst = append(lst, cur)
+
+		if cur.Type == MsgWait || cur.Type == MsgEnd {
+			return lst, nil
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package wsserv
+
+import "errors"
+
+var (
+	// ErrInvalidMessage - invalid message
+	ErrInvalidMessage = errors.New("invalid message")
+	// ErrInvalidCommand - the command is not in the NextCommands of the last result
+	ErrInvalidCommand = errors.New("invalid command")
+	// ErrNoRound - no unfinished round
+	ErrNoRound = errors.New("no unfinished round")
+	// ErrRoundInUse - the round is used by another connection
+	ErrRoundInUse = errors.New("the round is used by another connection")
+	// ErrTooManySteps - too many steps in a round
+	ErrTooManySteps = errors.New("too many steps in a round")
+	// ErrServerStopped - the server is stopped
+	ErrServerStopped = errors.New("the server is stopped")
+)
@@ -0,0 +1,39 @@
+package wsserv
+
+import (
+	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
+)
+
+const (
+	// MsgPlay - client -> server, start a round, or send the next command when the round is waiting
+	MsgPlay = "play"
+	// MsgResume - client -> server, reconnect to an unfinished round by roundID
+	MsgResume = "resume"
+	// MsgResult - server -> client, a PlayResult, it is sent as soon as BasicGame.Play returns it
+	MsgResult = "result"
+	// MsgWait - server -> client, the round is waiting for the next command
+	MsgWait = "wait"
+	// MsgEnd - server -> client, the round is finished
+	MsgEnd = "end"
+	// MsgError - server -> client, an error
+	MsgError = "error"
+)
+
+// Message - 所有的消息都是这个结构，用 type 区分
+type Message struct {
+	Type              string              `json:"type"`
+	RoundID           string              `json:"roundID,omitempty"`
+	Stake             *sgc7pb.Stake       `json:"stake,omitempty"`
+	Command           string              `json:"command,omitempty"`
+	ClientParams      string              `json:"clientParams,omitempty"`
+	Cheat             string              `json:"cheat,omitempty"`
+	PlayerState       *sgc7pb.PlayerState `json:"playerState,omitempty"`
+	Index             int                 `json:"index,omitempty"`
+	Result            *sgc7pb.GameResult  `json:"result,omitempty"`
+	IsFinish          bool                `json:"isFinish,omitempty"`
+	IsWait            bool                `json:"isWait,omitempty"`
+	NextCommands      []string            `json:"nextCommands,omitempty"`
+	NextCommandParams []string            `json:"nextCommandParams,omitempty"`
+	RandomNumbers     []*sgc7pb.RngInfo   `json:"randomNumbers,omitempty"`
+	Err               string              `json:"err,omitempty"`
+}
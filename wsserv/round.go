@@ -0,0 +1,69 @@
+package wsserv
+
+import (
+	"sync"
+	"time"
+
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+)
+
+// round - 一个没结束的局，断线以后还在，可以用 roundID 重连
+type round struct {
+	lock       sync.Mutex
+	roundID    string
+	plugin     sgc7plugin.IPlugin
+	ips        sgc7game.IPlayerState
+	stake      *sgc7game.Stake
+	gameData   sgc7game.IGameData
+	results    []*sgc7game.PlayResult
+	owner      *session
+	lastActive time.Time
+	isFreed    bool
+}
+
+// getLastResult - the last PlayResult, nil if no result
+func (r *round) getLastResult() *sgc7game.PlayResult {
+	if len(r.results) == 0 {
+		return nil
+	}
+
+	return r.results[len(r.results)-1]
+}
+
+// isWaiting - the last result is waiting for the next command
+func (r *round) isWaiting() bool {
+	pr := r.getLastResult()
+
+	return pr != nil && !pr.IsFinish
+}
+
+// checkCommand - if the last result has NextCmds, cmd must be one of them
+func (r *round) checkCommand(cmd string) error {
+	pr := r.getLastResult()
+	if pr == nil || len(pr.NextCmds) == 0 {
+		return nil
+	}
+
+	for _, v := range pr.NextCmds {
+		if v == cmd {
+			return nil
+		}
+	}
+
+	return ErrInvalidCommand
+}
+
+// free - results 里的数据属于 gameData，free 以后不能再用
+func (r *round) free(game sgc7game.IGame) {
+	if r.isFreed {
+		return
+	}
+
+	r.isFreed = true
+
+	game.DeleteGameData(r.gameData)
+	game.FreePlugin(r.plugin)
+
+	r.results = nil
+}
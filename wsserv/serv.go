@@ -0,0 +1,609 @@
+package wsserv
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bytedance/sonic"
+	goutils "github.com/zhs007/goutils"
+	"github.com/zhs007/slotsgamecore7/cheatpolicy"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	"github.com/zhs007/slotsgamecore7/grpcserv"
+	sgc7pbutils "github.com/zhs007/slotsgamecore7/pbutils"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+	"github.com/zhs007/slotsgamecore7/roundstore"
+	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
+	"golang.org/x/net/websocket"
+)
+
+// URL - websocket url
+const URL = "/ws"
+
+// DefaultRoundTimeout - 断线以后没结束的局保留多久
+const DefaultRoundTimeout = 10 * time.Minute
+
+// MaxStepNum - max steps in a round
+const MaxStepNum = 1000
+
+// session - 一个连接，同时只有一个没结束的局
+type session struct {
+	ctx    context.Context
+	conn   *websocket.Conn
+	caller string
+	round  *round
+}
+
+// send - 只在连接自己的 goroutine 里调用
+func (sess *session) send(msg *Message) error {
+	buf, err := sonic.Marshal(msg)
+	if err != nil {
+		goutils.Error("session.send:Marshal",
+			goutils.Err(err))
+
+		return err
+	}
+
+	return websocket.Message.Send(sess.conn, string(buf))
+}
+
+// Serv - websocket play channel, one session per connection, every PlayResult is sent as soon as it is produced,
+//
+//	when a result IsWait, the round waits for the next command from the client, the unfinished round can be resumed by roundID after reconnect.
+type Serv struct {
+	RoundTimeout time.Duration
+	service      grpcserv.IService
+	game         sgc7game.IGame
+	cheatPolicy  *cheatpolicy.Policy
+	lis          net.Listener
+	httpServ     *http.Server
+	lock         sync.Mutex
+	mapRounds    map[string]*round
+	mapSessions  map[*session]struct{}
+	isStopped    bool
+}
+
+// NewServ - new a serv
+func NewServ(service grpcserv.IService, game sgc7game.IGame, bindaddr string) (*Serv, error) {
+	lis, err := net.Listen("tcp", bindaddr)
+	if err != nil {
+		goutils.Error("wsserv.NewServ:Listen",
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	serv := &Serv{
+		RoundTimeout: DefaultRoundTimeout,
+		service:      service,
+		game:         game,
+		lis:          lis,
+		mapRounds:    make(map[string]*round),
+		mapSessions:  make(map[*session]struct{}),
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(URL, serv.Handler())
+
+	serv.httpServ = &http.Server{
+		Handler: mux,
+	}
+
+	return serv, nil
+}
+
+// SetCheatPolicy - set the cheat policy, nil rejects all cheats
+func (serv *Serv) SetCheatPolicy(policy *cheatpolicy.Policy) {
+	serv.cheatPolicy = policy
+}
+
+// GetAddr - get the listen address, it is useful when the port is 0
+func (serv *Serv) GetAddr() string {
+	return serv.lis.Addr().String()
+}
+
+// Handler - websocket handler, it can be mounted on another http server
+//
+//	这里不检查 Origin，和 gateway 的 Access-Control-Allow-Origin 一样
+func (serv *Serv) Handler() http.Handler {
+	return websocket.Server{
+		Handler: serv.onConn,
+	}
+}
+
+// Start - serve, it returns after Stop
+func (serv *Serv) Start() error {
+	err := serv.httpServ.Serve(serv.lis)
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+
+	return err
+}
+
+// Stop - close all connections, and free all unfinished rounds
+func (serv *Serv) Stop() {
+	serv.lock.Lock()
+	if serv.isStopped {
+		serv.lock.Unlock()
+
+		return
+	}
+
+	serv.isStopped = true
+
+	rounds := serv.mapRounds
+	serv.mapRounds = make(map[string]*round)
+
+	sessions := serv.mapSessions
+	serv.mapSessions = make(map[*session]struct{})
+	serv.lock.Unlock()
+
+	serv.httpServ.Close()
+
+	for sess := range sessions {
+		sess.conn.Close()
+	}
+
+	for _, r := range rounds {
+		r.lock.Lock()
+		r.free(serv.game)
+		r.lock.Unlock()
+	}
+}
+
+// getCaller - remote ip, or x-caller-id if the cheat policy trusts it
+func (serv *Serv) getCaller(req *http.Request) string {
+	if serv.cheatPolicy != nil && serv.cheatPolicy.IsTrustCallerID {
+		caller := req.Header.Get(cheatpolicy.CallerIDKey)
+		if caller != "" {
+			return caller
+		}
+	}
+
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+
+	return host
+}
+
+func (serv *Serv) addSession(sess *session) bool {
+	serv.lock.Lock()
+	defer serv.lock.Unlock()
+
+	if serv.isStopped {
+		return false
+	}
+
+	serv.mapSessions[sess] = struct{}{}
+
+	return true
+}
+
+// removeSession - 断线以后，没结束的局还在，等 RoundTimeout
+func (serv *Serv) removeSession(sess *session) {
+	serv.lock.Lock()
+	delete(serv.mapSessions, sess)
+	serv.lock.Unlock()
+
+	r := sess.round
+	if r != nil {
+		r.lock.Lock()
+		if r.owner == sess {
+			r.owner = nil
+			r.lastActive = time.Now()
+		}
+		r.lock.Unlock()
+	}
+}
+
+func (serv *Serv) addRound(r *round) bool {
+	serv.lock.Lock()
+	defer serv.lock.Unlock()
+
+	if serv.isStopped {
+		return false
+	}
+
+	serv.mapRounds[r.roundID] = r
+
+	return true
+}
+
+func (serv *Serv) removeRound(r *round) {
+	serv.lock.Lock()
+	defer serv.lock.Unlock()
+
+	if serv.mapRounds[r.roundID] == r {
+		delete(serv.mapRounds, r.roundID)
+	}
+}
+
+func (serv *Serv) getRound(roundID string) *round {
+	serv.lock.Lock()
+	defer serv.lock.Unlock()
+
+	return serv.mapRounds[roundID]
+}
+
+// clearTimeoutRounds - free the rounds without connection for RoundTimeout
+func (serv *Serv) clearTimeoutRounds() {
+	serv.lock.Lock()
+
+	lst := []*round{}
+	for _, r := range serv.mapRounds {
+		lst = append(lst, r)
+	}
+
+	serv.lock.Unlock()
+
+	for _, r := range lst {
+		r.lock.Lock()
+		if r.owner == nil && time.Since(r.lastActive) > serv.RoundTimeout {
+			goutils.Info("Serv.clearTimeoutRounds",
+				slog.String("roundID", r.roundID),
+				slog.Int("results", len(r.results)))
+
+			serv.removeRound(r)
+			r.free(serv.game)
+		}
+		r.lock.Unlock()
+	}
+}
+
+func (serv *Serv) onConn(conn *websocket.Conn) {
+	defer conn.Close()
+
+	sess := &session{
+		ctx:    conn.Request().Context(),
+		conn:   conn,
+		caller: serv.getCaller(conn.Request()),
+	}
+
+	if !serv.addSession(sess) {
+		sess.send(&Message{Type: MsgError, Err: ErrServerStopped.Error()})
+
+		return
+	}
+
+	defer serv.removeSession(sess)
+
+	for {
+		var str string
+		err := websocket.Message.Receive(conn, &str)
+		if err != nil {
+			if err != io.EOF {
+				goutils.Debug("Serv.onConn:Receive",
+					goutils.Err(err))
+			}
+
+			return
+		}
+
+		msg := &Message{}
+		err = sonic.Unmarshal([]byte(str), msg)
+		if err != nil {
+			goutils.Warn("Serv.onConn:Unmarshal",
+				goutils.Err(err))
+
+			err = ErrInvalidMessage
+		} else {
+			switch msg.Type {
+			case MsgPlay:
+				err = serv.onPlay(sess, msg)
+			case MsgResume:
+				err = serv.onResume(sess, msg)
+			default:
+				err = ErrInvalidMessage
+			}
+		}
+
+		if err != nil {
+			err = sess.send(&Message{Type: MsgError, RoundID: msg.RoundID, Err: err.Error()})
+			if err != nil {
+				return
+			}
+		}
+	}
+}
+
+// newRound - 开始一局，和 grpcserv.PlayGame 一样，先 OnBet
+func (serv *Serv) newRound(sess *session, msg *Message) (*round, error) {
+	if msg.Stake == nil {
+		return nil, ErrInvalidMessage
+	}
+
+	var ips sgc7game.IPlayerState
+	if msg.PlayerState != nil {
+		ips = serv.game.NewPlayerState()
+
+		err := serv.service.BuildPlayerStateFromPB(ips, msg.PlayerState)
+		if err != nil {
+			goutils.Error("Serv.newRound:BuildPlayerStateFromPB",
+				goutils.Err(err))
+
+			return nil, err
+		}
+	} else {
+		ips = serv.game.Initialize()
+	}
+
+	stake := sgc7pbutils.BuildStake(msg.Stake)
+	err := serv.game.CheckStake(stake)
+	if err != nil {
+		goutils.Error("Serv.newRound:CheckStake",
+			slog.Any("stake", stake),
+			goutils.Err(err))
+
+		return nil, err
+	}
+
+	plugin := serv.game.NewPlugin()
+
+	err = grpcserv.ProcCheat(plugin, msg.Cheat)
+	if err != nil {
+		serv.game.FreePlugin(plugin)
+
+		return nil, err
+	}
+
+	gameData := serv.game.NewGameData(stake)
+	if gameData == nil {
+		serv.game.FreePlugin(plugin)
+
+		goutils.Error("Serv.newRound:NewGameData",
+			goutils.Err(sgc7game.ErrInvalidStake))
+
+		return nil, sgc7game.ErrInvalidStake
+	}
+
+	r := &round{
+		roundID:    roundstore.NewRoundID(),
+		plugin:     plugin,
+		ips:        ips,
+		stake:      stake,
+		gameData:   gameData,
+		owner:      sess,
+		lastActive: time.Now(),
+	}
+
+	serv.game.OnBet(sgc7plugin.WithContext(plugin, sess.ctx), msg.Command, msg.ClientParams, ips, stake, r.results, gameData)
+
+	return r, nil
+}
+
+func (serv *Serv) onPlay(sess *session, msg *Message) error {
+	err := serv.cheatPolicy.Check("wsserv", "", sess.caller, msg.Cheat)
+	if err != nil {
+		return err
+	}
+
+	r := sess.round
+	if r == nil {
+		serv.clearTimeoutRounds()
+
+		r, err = serv.newRound(sess, msg)
+		if err != nil {
+			return err
+		}
+
+		r.lock.Lock()
+		defer r.lock.Unlock()
+
+		sess.round = r
+
+		return serv.procRound(sess, r, msg.Command, msg.ClientParams)
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.owner != sess {
+		sess.round = nil
+
+		return ErrRoundInUse
+	}
+
+	cmd := msg.Command
+	if cmd == "" {
+		cmd = "SPIN"
+	}
+
+	err = r.checkCommand(cmd)
+	if err != nil {
+		return err
+	}
+
+	err = grpcserv.ProcCheat(r.plugin, msg.Cheat)
+	if err != nil {
+		return err
+	}
+
+	return serv.procRound(sess, r, cmd, msg.ClientParams)
+}
+
+// procRound - play until the round is finished or waiting, r.lock must be locked
+func (serv *Serv) procRound(sess *session, r *round, cmd string, params string) error {
+	plugin := sgc7plugin.WithContext(r.plugin, sess.ctx)
+
+	for {
+		if cmd == "" {
+			cmd = "SPIN"
+		}
+
+		pr, err := sgc7game.PlayWithContext(sess.ctx, serv.game, plugin, cmd, params, r.ips, r.stake, r.results, r.gameData)
+		if err != nil {
+			goutils.Error("Serv.procRound:Play",
+				slog.String("roundID", r.roundID),
+				slog.Int("results", len(r.results)),
+				goutils.Err(err))
+
+			// 第一步就失败的局直接丢掉，否则还停在上一步，客户端可以换个命令
+			if len(r.results) == 0 {
+				serv.endRound(sess, r)
+			}
+
+			return err
+		}
+
+		if pr == nil {
+			break
+		}
+
+		r.results = append(r.results, pr)
+
+		// 断线了也继续，局还在，可以重连
+		sess.send(serv.buildResultMsg(r, len(r.results)-1))
+
+		if pr.IsFinish || pr.IsWait {
+			break
+		}
+
+		if len(pr.NextCmds) > 0 {
+			cmd = pr.NextCmds[0]
+
+			if len(pr.NextCmdParams) > 0 {
+				params = pr.NextCmdParams[0]
+			} else {
+				params = ""
+			}
+		} else {
+			cmd = ""
+			params = ""
+		}
+
+		if len(r.results) >= MaxStepNum {
+			goutils.Error("Serv.procRound",
+				slog.String("roundID", r.roundID),
+				slog.Int("steps", len(r.results)),
+				goutils.Err(ErrTooManySteps))
+
+			serv.endRound(sess, r)
+
+			return ErrTooManySteps
+		}
+	}
+
+	if r.isWaiting() {
+		r.lastActive = time.Now()
+		if !serv.addRound(r) {
+			serv.endRound(sess, r)
+
+			return ErrServerStopped
+		}
+
+		sess.send(serv.buildWaitMsg(r))
+
+		return nil
+	}
+
+	msg := &Message{
+		Type:          MsgEnd,
+		RoundID:       r.roundID,
+		IsFinish:      true,
+		RandomNumbers: sgc7pbutils.BuildPBRngs(r.plugin.GetUsedRngs()),
+	}
+
+	ps, err := serv.service.BuildPBPlayerState(r.ips)
+	if err != nil {
+		goutils.Error("Serv.procRound:BuildPBPlayerState",
+			goutils.Err(err))
+
+		serv.endRound(sess, r)
+
+		return err
+	}
+
+	msg.PlayerState = ps
+
+	serv.endRound(sess, r)
+
+	sess.send(msg)
+
+	return nil
+}
+
+// endRound - r.lock must be locked
+func (serv *Serv) endRound(sess *session, r *round) {
+	serv.removeRound(r)
+	r.free(serv.game)
+
+	if sess.round == r {
+		sess.round = nil
+	}
+}
+
+func (serv *Serv) onResume(sess *session, msg *Message) error {
+	if sess.round != nil && sess.round.roundID != msg.RoundID {
+		return ErrRoundInUse
+	}
+
+	serv.clearTimeoutRounds()
+
+	r := serv.getRound(msg.RoundID)
+	if r == nil {
+		return ErrNoRound
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.isFreed {
+		return ErrNoRound
+	}
+
+	// 旧的连接可能还没发现断线，新的连接接管这一局
+	r.owner = sess
+	sess.round = r
+
+	for i := range r.results {
+		err := sess.send(serv.buildResultMsg(r, i))
+		if err != nil {
+			return err
+		}
+	}
+
+	return sess.send(serv.buildWaitMsg(r))
+}
+
+func (serv *Serv) buildResultMsg(r *round, index int) *Message {
+	pr := r.results[index]
+
+	msg := &Message{
+		Type:              MsgResult,
+		RoundID:           r.roundID,
+		Index:             index,
+		IsFinish:          pr.IsFinish,
+		IsWait:            pr.IsWait,
+		NextCommands:      pr.NextCmds,
+		NextCommandParams: pr.NextCmdParams,
+	}
+
+	reply := &sgc7pb.ReplyPlay{}
+	grpcserv.AddPlayResult(serv.service, reply, []*sgc7game.PlayResult{pr})
+
+	if len(reply.Results) > 0 {
+		msg.Result = reply.Results[0]
+	}
+
+	return msg
+}
+
+func (serv *Serv) buildWaitMsg(r *round) *Message {
+	pr := r.getLastResult()
+
+	return &Message{
+		Type:              MsgWait,
+		RoundID:           r.roundID,
+		IsWait:            true,
+		NextCommands:      pr.NextCmds,
+		NextCommandParams: pr.NextCmdParams,
+	}
+}
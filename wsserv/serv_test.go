@@ -0,0 +1,353 @@
+package wsserv
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zhs007/slotsgamecore7/cheatpolicy"
+	sgc7game "github.com/zhs007/slotsgamecore7/game"
+	"github.com/zhs007/slotsgamecore7/grpcserv"
+	"github.com/zhs007/slotsgamecore7/lowcode"
+	sgc7plugin "github.com/zhs007/slotsgamecore7/plugin"
+	sgc7pb "github.com/zhs007/slotsgamecore7/sgc7pb"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// testGameData - sgc7game.IGameData
+type testGameData struct {
+}
+
+// GetBetMul - get bet mul
+func (gd *testGameData) GetBetMul() int {
+	return 1
+}
+
+// pickGame - SPIN 以后等玩家选 0、1、2，赢 (n + 1) * 10
+type pickGame struct {
+	*sgc7game.BasicGame
+}
+
+// Play - play
+func (game *pickGame) Play(plugin sgc7plugin.IPlugin, cmd string, param string, ps sgc7game.IPlayerState, stake *sgc7game.Stake, prs []*sgc7game.PlayResult, gameData any) (*sgc7game.PlayResult, error) {
+	pr := sgc7game.NewPlayResult("bg", len(prs), 0, "bg")
+	pr.NextGameMod = "bg"
+
+	if cmd == "SPIN" && len(prs) == 0 {
+		_, err := plugin.Random(context.Background(), 3)
+		if err != nil {
+			return nil, err
+		}
+
+		pr.NextCmds = []string{"PICK", "PICK", "PICK"}
+		pr.NextCmdParams = []string{"0", "1", "2"}
+		pr.IsWait = true
+
+		return pr, nil
+	}
+
+	if cmd == "PICK" && len(prs) == 1 {
+		n, err := strconv.Atoi(param)
+		if err != nil || n < 0 || n > 2 {
+			return nil, sgc7game.ErrInvalidParam
+		}
+
+		pr.CoinWin = (n + 1) * 10
+		pr.IsFinish = true
+
+		return pr, nil
+	}
+
+	return nil, sgc7game.ErrInvalidCommand
+}
+
+// CheckStake - check stake
+func (game *pickGame) CheckStake(stake *sgc7game.Stake) error {
+	return nil
+}
+
+// NewGameData - new GameData
+func (game *pickGame) NewGameData(stake *sgc7game.Stake) sgc7game.IGameData {
+	return &testGameData{}
+}
+
+// testService - grpcserv.IService for pickGame
+type testService struct {
+	*grpcserv.BasicService
+}
+
+// BuildPBGameModParam - any -> *anypb.Any
+func (sv *testService) BuildPBGameModParam(gp any) (*anypb.Any, error) {
+	return nil, nil
+}
+
+// BuildPBGameModParamFromAny - *anypb.Any -> any
+func (sv *testService) BuildPBGameModParamFromAny(msg *anypb.Any) (any, error) {
+	return nil, nil
+}
+
+func newTestServ(t *testing.T) *Serv {
+	game := &pickGame{
+		BasicGame: sgc7game.NewBasicGame(func() sgc7plugin.IPlugin {
+			return sgc7plugin.NewFastPlugin()
+		}),
+	}
+
+	serv, err := NewServ(&testService{grpcserv.NewBasicService()}, game, "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	go serv.Start()
+
+	return serv
+}
+
+func newTestClient(t *testing.T, serv *Serv) *Client {
+	client, err := NewClient("ws://"+serv.GetAddr()+URL, "http://127.0.0.1/")
+	assert.NoError(t, err)
+
+	return client
+}
+
+func newTestStake() *sgc7pb.Stake {
+	return &sgc7pb.Stake{
+		CoinBet:  1,
+		CashBet:  20,
+		Currency: "EUR",
+	}
+}
+
+// waitDetached - 等服务器发现断线
+func waitDetached(t *testing.T, serv *Serv, roundID string) {
+	r := serv.getRound(roundID)
+	assert.NotNil(t, r)
+
+	for i := 0; i < 100; i++ {
+		r.lock.Lock()
+		owner := r.owner
+		r.lock.Unlock()
+
+		if owner == nil {
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.Fail(t, "the round is not detached")
+}
+
+func Test_ServPlay(t *testing.T) {
+	serv := newTestServ(t)
+	defer serv.Stop()
+
+	client := newTestClient(t, serv)
+	defer client.Close()
+
+	steps := 0
+	client.OnMessage = func(msg *Message) {
+		steps++
+	}
+
+	msgs, err := client.Play(newTestStake(), "SPIN", "", nil, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(msgs))
+	assert.Equal(t, 2, steps)
+	assert.Equal(t, MsgResult, msgs[0].Type)
+	assert.True(t, msgs[0].IsWait)
+	assert.NotNil(t, msgs[0].Result)
+	assert.Equal(t, MsgWait, msgs[1].Type)
+	assert.Equal(t, []string{"PICK", "PICK", "PICK"}, msgs[1].NextCommands)
+	assert.Equal(t, []string{"0", "1", "2"}, msgs[1].NextCommandParams)
+
+	roundID := msgs[1].RoundID
+	assert.NotEmpty(t, roundID)
+
+	// 不在 NextCommands 里
+	_, err = client.Next("SPIN", "")
+	assert.EqualError(t, err, ErrInvalidCommand.Error())
+
+	// 游戏返回错误，还停在上一步
+	_, err = client.Next("PICK", "5")
+	assert.EqualError(t, err, sgc7game.ErrInvalidParam.Error())
+
+	msgs, err = client.Next("PICK", "1")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(msgs))
+	assert.Equal(t, MsgResult, msgs[0].Type)
+	assert.Equal(t, 1, msgs[0].Index)
+	assert.True(t, msgs[0].IsFinish)
+	assert.Equal(t, int64(20), msgs[0].Result.CoinWin)
+	assert.Equal(t, MsgEnd, msgs[1].Type)
+	assert.Equal(t, roundID, msgs[1].RoundID)
+	assert.NotNil(t, msgs[1].PlayerState)
+	assert.Equal(t, 1, len(msgs[1].RandomNumbers))
+
+	assert.Nil(t, serv.getRound(roundID))
+
+	// 新的一局
+	msgs, err = client.Play(newTestStake(), "", "", msgs[1].PlayerState, "")
+	assert.NoError(t, err)
+	assert.Equal(t, MsgWait, msgs[len(msgs)-1].Type)
+	assert.NotEqual(t, roundID, msgs[len(msgs)-1].RoundID)
+
+	// 没有 stake
+	client2 := newTestClient(t, serv)
+	defer client2.Close()
+
+	_, err = client2.Play(nil, "SPIN", "", nil, "")
+	assert.EqualError(t, err, ErrInvalidMessage.Error())
+
+	_, err = client2.Send(&Message{Type: "unknown"})
+	assert.EqualError(t, err, ErrInvalidMessage.Error())
+
+	t.Logf("Test_ServPlay OK")
+}
+
+func Test_ServResume(t *testing.T) {
+	serv := newTestServ(t)
+	defer serv.Stop()
+
+	client := newTestClient(t, serv)
+
+	msgs, err := client.Play(newTestStake(), "SPIN", "", nil, "")
+	assert.NoError(t, err)
+
+	roundID := msgs[len(msgs)-1].RoundID
+
+	client.Close()
+	waitDetached(t, serv, roundID)
+
+	client2 := newTestClient(t, serv)
+	defer client2.Close()
+
+	_, err = client2.Resume("unknown")
+	assert.EqualError(t, err, ErrNoRound.Error())
+
+	msgs, err = client2.Resume(roundID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(msgs))
+	assert.Equal(t, MsgResult, msgs[0].Type)
+	assert.Equal(t, MsgWait, msgs[1].Type)
+	assert.Equal(t, roundID, msgs[1].RoundID)
+
+	// 接管以后旧的连接不能再用这一局
+	client3 := newTestClient(t, serv)
+	defer client3.Close()
+
+	msgs, err = client3.Resume(roundID)
+	assert.NoError(t, err)
+	assert.Equal(t, MsgWait, msgs[len(msgs)-1].Type)
+
+	_, err = client2.Next("PICK", "2")
+	assert.EqualError(t, err, ErrRoundInUse.Error())
+
+	msgs, err = client3.Next("PICK", "2")
+	assert.NoError(t, err)
+	assert.Equal(t, MsgEnd, msgs[len(msgs)-1].Type)
+	assert.Equal(t, int64(30), msgs[0].Result.CoinWin)
+
+	_, err = client3.Resume(roundID)
+	assert.EqualError(t, err, ErrNoRound.Error())
+
+	t.Logf("Test_ServResume OK")
+}
+
+func Test_ServRoundTimeout(t *testing.T) {
+	serv := newTestServ(t)
+	defer serv.Stop()
+
+	serv.RoundTimeout = 0
+
+	client := newTestClient(t, serv)
+
+	msgs, err := client.Play(newTestStake(), "SPIN", "", nil, "")
+	assert.NoError(t, err)
+
+	roundID := msgs[len(msgs)-1].RoundID
+
+	client.Close()
+	waitDetached(t, serv, roundID)
+
+	client2 := newTestClient(t, serv)
+	defer client2.Close()
+
+	_, err = client2.Resume(roundID)
+	assert.EqualError(t, err, ErrNoRound.Error())
+
+	t.Logf("Test_ServRoundTimeout OK")
+}
+
+func Test_ServCheatPolicy(t *testing.T) {
+	serv := newTestServ(t)
+	defer serv.Stop()
+
+	client := newTestClient(t, serv)
+	defer client.Close()
+
+	_, err := client.Play(newTestStake(), "SPIN", "", nil, "1")
+	assert.EqualError(t, err, cheatpolicy.ErrCheatDisabled.Error())
+
+	serv.SetCheatPolicy(&cheatpolicy.Policy{
+		IsEnabled:    true,
+		AllowCallers: []string{"127.0.0.1"},
+	})
+
+	msgs, err := client.Play(newTestStake(), "SPIN", "", nil, "1")
+	assert.NoError(t, err)
+	assert.Equal(t, MsgWait, msgs[len(msgs)-1].Type)
+
+	t.Logf("Test_ServCheatPolicy OK")
+}
+
+func Test_ServLowcode(t *testing.T) {
+	game, err := lowcode.NewGame2("../unittestdata/testgame.json", func() sgc7plugin.IPlugin {
+		return sgc7plugin.NewFastPlugin()
+	}, lowcode.NewBasicRNG, lowcode.NewEmptyFeatureLevel)
+	assert.NoError(t, err)
+
+	serv, err := NewServ(&lowcodeService{grpcserv.NewBasicService2()}, game, "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer serv.Stop()
+
+	go serv.Start()
+
+	client := newTestClient(t, serv)
+	defer client.Close()
+
+	msgs, err := client.Play(newTestStake(), "SPIN", "", nil, "")
+	assert.NoError(t, err)
+	assert.True(t, len(msgs) >= 2)
+	assert.Equal(t, MsgEnd, msgs[len(msgs)-1].Type)
+	assert.NotEmpty(t, msgs[len(msgs)-1].RandomNumbers)
+
+	t.Logf("Test_ServLowcode OK")
+}
+
+// lowcodeService - grpcserv.IService for lowcode game
+type lowcodeService struct {
+	*grpcserv.BasicService2
+}
+
+// BuildPBGameModParam - any -> *anypb.Any
+func (sv *lowcodeService) BuildPBGameModParam(gp any) (*anypb.Any, error) {
+	mygp, isok := gp.(*lowcode.GameParams)
+	if !isok {
+		return nil, sgc7game.ErrInvalidParam
+	}
+
+	return anypb.New(&mygp.GameParam)
+}
+
+// BuildPBGameModParamFromAny - *anypb.Any -> any
+func (sv *lowcodeService) BuildPBGameModParamFromAny(msg *anypb.Any) (any, error) {
+	mygp := &sgc7pb.GameParam{}
+
+	err := msg.UnmarshalTo(mygp)
+	if err != nil {
+		return nil, err
+	}
+
+	return mygp, nil
+}